@@ -1,18 +1,31 @@
 package sdk
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
 	accounttypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	grpc "github.com/cosmos/gogoproto/grpc"
 	grpcoptions "google.golang.org/grpc"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
 )
 
+// maxConcurrentPubKeyFetches is the maximum number of public keys fetched
+// concurrently by a single call to GetPubKeys.
+const maxConcurrentPubKeyFetches = 16
+
 var queryCodec *codec.ProtoCodec
 
 // init initializes the codec for the account module
@@ -50,6 +63,199 @@ func (ac *AccountClient) GetPubKeyFromAddress(
 	return fetchedAccount.GetPubKey(), nil
 }
 
+// GetPubKeys returns the public keys of the accounts with the given addresses,
+// keyed by address, querying the account module concurrently.
+// An error is returned if any of the public keys can not be fetched.
+func (ac *AccountClient) GetPubKeys(
+	ctx context.Context,
+	addresses []string,
+) (map[string]cryptotypes.PubKey, error) {
+	return getPubKeys(ctx, ac, addresses)
+}
+
+// ErrPubKeyAddressMismatch is returned when the public key fetched for an
+// address does not derive to that address, e.g. if the full node is buggy or
+// malicious.
+var ErrPubKeyAddressMismatch = sdkerrors.ErrPubKeyAddressMismatch
+
+// CachedAccountClientOption is a functional option used to configure a CachedAccountClient.
+type CachedAccountClientOption func(*CachedAccountClient)
+
+// WithPubKeyMismatchObserver sets a function called with the address and the
+// error of every fetched public key which does not derive to the requested
+// address, e.g. to log it, or to alert on a misbehaving full node.
+func WithPubKeyMismatchObserver(observer func(address string, err error)) CachedAccountClientOption {
+	return func(cac *CachedAccountClient) {
+		cac.mismatchObserver = observer
+	}
+}
+
+// WithPubKeyMetricsRecorder sets the MetricsRecorder of the lookups in the
+// cache of public keys.
+func WithPubKeyMetricsRecorder(recorder MetricsRecorder) CachedAccountClientOption {
+	return func(cac *CachedAccountClient) {
+		cac.metricsRecorder = recorder
+	}
+}
+
+// CachedAccountClient wraps a PublicKeyFetcher, typically an AccountClient,
+// caching the fetched public keys.
+//
+// Public keys are cached indefinitely, since an account's public key never
+// changes once set, and concurrent fetches of the same address are coalesced
+// into a single query.
+// Before being cached, a fetched public key is verified to derive to the
+// requested address: mismatches fail with an error wrapping
+// ErrPubKeyAddressMismatch and are not cached, so a buggy or malicious full node
+// can not poison the rings built using the cached public keys.
+// It can be used anywhere a PublicKeyFetcher is expected, e.g. by an ApplicationRing.
+type CachedAccountClient struct {
+	inner            PublicKeyFetcher
+	cache            *cache.Cache[string, cryptotypes.PubKey]
+	mismatchObserver func(address string, err error)
+	metricsRecorder  MetricsRecorder
+}
+
+// NewCachedAccountClient returns a CachedAccountClient fetching the public keys
+// missing from its cache using the given PublicKeyFetcher, configured using the
+// given options.
+func NewCachedAccountClient(inner PublicKeyFetcher, opts ...CachedAccountClientOption) *CachedAccountClient {
+	cac := &CachedAccountClient{
+		inner: inner,
+		cache: cache.New[string, cryptotypes.PubKey](cache.Config{}),
+	}
+	for _, opt := range opts {
+		opt(cac)
+	}
+
+	return cac
+}
+
+// GetPubKeyFromAddress returns the public key of the account with the given
+// address, fetching it using the wrapped PublicKeyFetcher if it is not cached.
+func (cac *CachedAccountClient) GetPubKeyFromAddress(
+	ctx context.Context,
+	address string,
+) (cryptotypes.PubKey, error) {
+	if cac.metricsRecorder != nil {
+		_, isCached := cac.cache.Get(address)
+		cac.metricsRecorder.ObserveCacheLookup(MetricsCachePubKey, isCached)
+	}
+
+	pubKey, _, err := cac.cache.GetOrFetch(ctx, address, func(ctx context.Context) (cryptotypes.PubKey, error) {
+		pubKey, err := cac.inner.GetPubKeyFromAddress(ctx, address)
+		if err != nil || pubKey == nil {
+			return pubKey, err
+		}
+
+		if err := verifyPubKeyAddress(address, pubKey); err != nil {
+			if cac.mismatchObserver != nil {
+				cac.mismatchObserver(address, err)
+			}
+			return nil, err
+		}
+		return pubKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// An account has no public key until it signs its first transaction: do
+	// not cache the missing public key, so that it is fetched again next time.
+	if pubKey == nil {
+		cac.cache.Delete(address)
+	}
+
+	return pubKey, nil
+}
+
+// GetPubKeys returns the public keys of the accounts with the given addresses,
+// keyed by address, fetching the ones which are not cached concurrently.
+// It can be used to warm the cache, e.g. with the public keys of the rings of
+// the applications served by a gateway on startup, so the first relays do not
+// wait for the public keys to be fetched.
+// An error is returned if any of the public keys can not be fetched.
+func (cac *CachedAccountClient) GetPubKeys(
+	ctx context.Context,
+	addresses []string,
+) (map[string]cryptotypes.PubKey, error) {
+	return getPubKeys(ctx, cac, addresses)
+}
+
+// getPubKeys fetches the public keys of the given addresses concurrently using
+// the given PublicKeyFetcher, fetching each distinct address only once.
+// The fetches are canceled following the first failure.
+func getPubKeys(
+	ctx context.Context,
+	fetcher PublicKeyFetcher,
+	addresses []string,
+) (map[string]cryptotypes.PubKey, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	uniqueAddresses := slices.Compact(slices.Sorted(slices.Values(addresses)))
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		semaphore = make(chan struct{}, maxConcurrentPubKeyFetches)
+		pubKeys   = make([]cryptotypes.PubKey, len(uniqueAddresses))
+		errs      []error
+	)
+
+	for i, address := range uniqueAddresses {
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			pubKey, err := fetcher.GetPubKeyFromAddress(ctx, address)
+			if err == nil {
+				pubKeys[i] = pubKey
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			// Fetches canceled following an earlier failure are not reported.
+			if len(errs) == 0 || !errors.Is(err, context.Canceled) {
+				errs = append(errs, fmt.Errorf("error getting public key of address %s: %w", address, err))
+			}
+			cancel()
+		}(i, address)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	pubKeysByAddress := make(map[string]cryptotypes.PubKey, len(uniqueAddresses))
+	for i, address := range uniqueAddresses {
+		pubKeysByAddress[address] = pubKeys[i]
+	}
+
+	return pubKeysByAddress, nil
+}
+
+// verifyPubKeyAddress returns an error wrapping ErrPubKeyAddressMismatch if the
+// given public key does not derive to the given bech32 address, regardless of
+// the address prefix.
+func verifyPubKeyAddress(address string, pubKey cryptotypes.PubKey) error {
+	prefix, addressBz, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return fmt.Errorf("%w: error decoding address %s: %w", ErrPubKeyAddressMismatch, address, err)
+	}
+
+	if !bytes.Equal(addressBz, pubKey.Address()) {
+		derivedAddress, _ := AddressToBech32(prefix, pubKey.Address())
+		return fmt.Errorf("%w: public key fetched for address %s derives to address %s", ErrPubKeyAddressMismatch, address, derivedAddress)
+	}
+	return nil
+}
+
 // NewPoktNodeAccountFetcher returns the default implementation of the PoktNodeAccountFetcher interfce.
 // It connects to a POKT full node, through the account module's query client, to get account data.
 func NewPoktNodeAccountFetcher(grpcConn grpc.ClientConn) PoktNodeAccountFetcher {
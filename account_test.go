@@ -0,0 +1,124 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedAccountClient_GetPubKeyFromAddress(t *testing.T) {
+	pubKey := secp256k1.GenPrivKey().PubKey()
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, pubKey)
+	require.NoError(t, err)
+
+	fetcher := &countingPubKeyFetcher{
+		pubKeys: map[string]cryptotypes.PubKey{appAddress: pubKey},
+	}
+	cachedClient := NewCachedAccountClient(fetcher)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetchedPubKey, err := cachedClient.GetPubKeyFromAddress(ctx, appAddress)
+			require.NoError(t, err)
+			require.Equal(t, pubKey, fetchedPubKey)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int64(1), fetcher.calls.Load())
+
+	// Missing public keys are not cached.
+	for i := 0; i < 2; i++ {
+		pubKey, err := cachedClient.GetPubKeyFromAddress(ctx, "pokt1new")
+		require.NoError(t, err)
+		require.Nil(t, pubKey)
+	}
+	require.Equal(t, int64(3), fetcher.calls.Load())
+}
+
+func TestCachedAccountClient_PubKeyAddressMismatch(t *testing.T) {
+	pubKey := secp256k1.GenPrivKey().PubKey()
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, pubKey)
+	require.NoError(t, err)
+	otherAddress, err := PubKeyToAddress(PoktAddressPrefix, secp256k1.GenPrivKey().PubKey())
+	require.NoError(t, err)
+
+	// The full node serves the public key of another account for otherAddress,
+	// and a public key for an undecodable address.
+	fetcher := &countingPubKeyFetcher{
+		pubKeys: map[string]cryptotypes.PubKey{appAddress: pubKey, otherAddress: pubKey, "pokt1invalid": pubKey},
+	}
+	var mismatches []string
+	cachedClient := NewCachedAccountClient(fetcher, WithPubKeyMismatchObserver(func(address string, err error) {
+		require.ErrorIs(t, err, ErrPubKeyAddressMismatch)
+		mismatches = append(mismatches, address)
+	}))
+
+	ctx := context.Background()
+	fetchedPubKey, err := cachedClient.GetPubKeyFromAddress(ctx, appAddress)
+	require.NoError(t, err)
+	require.Equal(t, pubKey, fetchedPubKey)
+
+	// The mismatched public keys are rejected, and not cached.
+	for i := 0; i < 2; i++ {
+		_, err = cachedClient.GetPubKeyFromAddress(ctx, otherAddress)
+		require.ErrorIs(t, err, ErrPubKeyAddressMismatch)
+		require.ErrorContains(t, err, appAddress)
+	}
+	_, err = cachedClient.GetPubKeyFromAddress(ctx, "pokt1invalid")
+	require.ErrorIs(t, err, ErrPubKeyAddressMismatch)
+
+	require.Equal(t, []string{otherAddress, otherAddress, "pokt1invalid"}, mismatches)
+	require.Equal(t, int64(4), fetcher.calls.Load())
+}
+
+func TestCachedAccountClient_GetPubKeys(t *testing.T) {
+	pubKeys := make(map[string]cryptotypes.PubKey)
+	var addresses []string
+	for i := 0; i < 3; i++ {
+		pubKey := secp256k1.GenPrivKey().PubKey()
+		address, err := PubKeyToAddress(PoktAddressPrefix, pubKey)
+		require.NoError(t, err)
+		pubKeys[address] = pubKey
+		addresses = append(addresses, address, address)
+	}
+
+	fetcher := &countingPubKeyFetcher{pubKeys: pubKeys}
+	cachedClient := NewCachedAccountClient(fetcher)
+
+	// Each distinct address is fetched once, and cached.
+	ctx := context.Background()
+	fetchedPubKeys, err := cachedClient.GetPubKeys(ctx, addresses)
+	require.NoError(t, err)
+	require.Equal(t, pubKeys, fetchedPubKeys)
+	require.Equal(t, int64(3), fetcher.calls.Load())
+
+	_, err = cachedClient.GetPubKeys(ctx, addresses)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), fetcher.calls.Load())
+
+	// A single failure fails the whole batch.
+	pubKeys["pokt1invalid"] = secp256k1.GenPrivKey().PubKey()
+	_, err = cachedClient.GetPubKeys(ctx, append(addresses, "pokt1invalid"))
+	require.ErrorIs(t, err, ErrPubKeyAddressMismatch)
+	require.ErrorContains(t, err, "pokt1invalid")
+}
+
+// countingPubKeyFetcher is a PublicKeyFetcher which counts the number of fetches.
+type countingPubKeyFetcher struct {
+	pubKeys map[string]cryptotypes.PubKey
+	calls   atomic.Int64
+}
+
+func (f *countingPubKeyFetcher) GetPubKeyFromAddress(_ context.Context, address string) (cryptotypes.PubKey, error) {
+	f.calls.Add(1)
+	return f.pubKeys[address], nil
+}
@@ -0,0 +1,73 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/pokt-network/poktroll/app"
+
+	"github.com/pokt-network/shannon-sdk/crypto"
+)
+
+// PoktAddressPrefix is the bech32 prefix of POKT account addresses.
+const PoktAddressPrefix = app.AccountAddressPrefix
+
+// The functions below operate on bech32 addresses with an explicit prefix, and
+// do not depend on the global cosmos SDK config.
+// They allow consumers embedding other cosmos-based SDKs in the same binary,
+// e.g. for other chains, to handle the addresses of each chain side-by-side
+// without bech32 prefix collisions.
+
+// AddressFromBech32 decodes the given bech32 address, returning an error if its
+// prefix does not match the given prefix.
+func AddressFromBech32(prefix, address string) ([]byte, error) {
+	if len(address) == 0 {
+		return nil, errors.New("AddressFromBech32: empty address")
+	}
+
+	addressPrefix, addressBz, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return nil, fmt.Errorf("AddressFromBech32: error decoding address %s: %w", address, err)
+	}
+
+	if addressPrefix != prefix {
+		return nil, fmt.Errorf(
+			"AddressFromBech32: invalid prefix for address %s: expected %s, got %s",
+			address,
+			prefix,
+			addressPrefix,
+		)
+	}
+
+	return addressBz, nil
+}
+
+// AddressToBech32 encodes the given address bytes as a bech32 address with the given prefix.
+func AddressToBech32(prefix string, addressBz []byte) (string, error) {
+	address, err := bech32.ConvertAndEncode(prefix, addressBz)
+	if err != nil {
+		return "", fmt.Errorf("AddressToBech32: error encoding address with prefix %s: %w", prefix, err)
+	}
+
+	return address, nil
+}
+
+// ValidateAddress returns an error if the given address is not a valid bech32
+// address with the given prefix.
+func ValidateAddress(prefix, address string) error {
+	_, err := AddressFromBech32(prefix, address)
+	return err
+}
+
+// PubKeyToAddress returns the bech32 address, with the given prefix, of the
+// account owning the given public key. It is equivalent to crypto.Address.
+func PubKeyToAddress(prefix string, pubKey cryptotypes.PubKey) (string, error) {
+	address, err := crypto.Address(prefix, pubKey)
+	if err != nil {
+		return "", fmt.Errorf("PubKeyToAddress: %w", err)
+	}
+
+	return address, nil
+}
@@ -0,0 +1,41 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/crypto"
+)
+
+func TestAddress_Bech32Prefixes(t *testing.T) {
+	pubKey := secp256k1.GenPrivKey().PubKey()
+
+	poktAddress, err := PubKeyToAddress(PoktAddressPrefix, pubKey)
+	require.NoError(t, err)
+	require.NoError(t, ValidateAddress(PoktAddressPrefix, poktAddress))
+
+	// PubKeyToAddress derives the address using the crypto package.
+	cryptoAddress, err := crypto.Address(PoktAddressPrefix, pubKey)
+	require.NoError(t, err)
+	require.Equal(t, cryptoAddress, poktAddress)
+	_, err = PubKeyToAddress(PoktAddressPrefix, nil)
+	require.ErrorContains(t, err, "public key not set")
+
+	// The same account can be handled using another chain's prefix, regardless
+	// of the sealed global cosmos SDK config.
+	cosmosAddress, err := PubKeyToAddress("cosmos", pubKey)
+	require.NoError(t, err)
+	require.NoError(t, ValidateAddress("cosmos", cosmosAddress))
+	require.Error(t, ValidateAddress(PoktAddressPrefix, cosmosAddress))
+
+	poktAddressBz, err := AddressFromBech32(PoktAddressPrefix, poktAddress)
+	require.NoError(t, err)
+	cosmosAddressBz, err := AddressFromBech32("cosmos", cosmosAddress)
+	require.NoError(t, err)
+	require.Equal(t, poktAddressBz, cosmosAddressBz)
+
+	require.Error(t, ValidateAddress(PoktAddressPrefix, ""))
+	require.Error(t, ValidateAddress(PoktAddressPrefix, "not-an-address"))
+}
@@ -2,7 +2,6 @@ package sdk
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"slices"
 
@@ -11,6 +10,8 @@ import (
 	"github.com/pokt-network/poktroll/pkg/crypto/rings"
 	"github.com/pokt-network/poktroll/x/application/types"
 	"github.com/pokt-network/ring-go"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
 )
 
 // ApplicationClient is the interface to interact with the on-chain application-module.
@@ -66,6 +67,94 @@ func (ac *ApplicationClient) GetApplication(
 	return res.Application, nil
 }
 
+// ErrApplicationUnbonding is returned when an application is unbonding, i.e. its
+// stake will be removed at the end of its unstake session, and it should no
+// longer be used to send relays.
+var ErrApplicationUnbonding = sdkerrors.ErrApplicationUnbonding
+
+// GetActiveApplication returns the details of the application with the given
+// address, or an error wrapping ErrApplicationUnbonding if the application is unbonding.
+func (ac *ApplicationClient) GetActiveApplication(
+	ctx context.Context,
+	appAddress string,
+) (types.Application, error) {
+	return getActiveApplication(ctx, ac, appAddress)
+}
+
+// getActiveApplication returns the application with the given address, fetched
+// using the given ApplicationQuerier, or an error wrapping ErrApplicationUnbonding
+// if the application is unbonding.
+func getActiveApplication(
+	ctx context.Context,
+	appClient ApplicationQuerier,
+	appAddress string,
+) (types.Application, error) {
+	app, err := appClient.GetApplication(ctx, appAddress)
+	if err != nil {
+		return types.Application{}, err
+	}
+
+	if IsApplicationUnbonding(app) {
+		return app, fmt.Errorf(
+			"GetActiveApplication: %w: application %s unstakes at session end height %d",
+			ErrApplicationUnbonding,
+			appAddress,
+			app.UnstakeSessionEndHeight,
+		)
+	}
+
+	return app, nil
+}
+
+// IsApplicationUnbonding returns true if the given application has requested
+// to unstake, i.e. its unstake session end height is set.
+func IsApplicationUnbonding(app types.Application) bool {
+	return app.UnstakeSessionEndHeight != 0
+}
+
+// ApplicationDelegations is an application along with the gateways it delegates
+// to at a given session end height.
+type ApplicationDelegations struct {
+	types.Application
+	SessionEndHeight uint64
+	// DelegatedGateways holds, in lexical order, the addresses of the gateways the
+	// application delegates to at SessionEndHeight: its current delegatees, along
+	// with the gateways whose undelegation is still pending at that height.
+	DelegatedGateways []string
+}
+
+// CanSign returns true if the given gateway can sign relays on behalf of the
+// application at the session end height, i.e. if it is part of the application's ring.
+func (d ApplicationDelegations) CanSign(gatewayAddress string) bool {
+	_, found := slices.BinarySearch(d.DelegatedGateways, gatewayAddress)
+	return found
+}
+
+// GetApplicationDelegations returns the application with the given address, along
+// with the gateways it delegates to at the given session end height, as used to
+// build its ring.
+func (ac *ApplicationClient) GetApplicationDelegations(
+	ctx context.Context,
+	appAddress string,
+	sessionEndHeight uint64,
+) (ApplicationDelegations, error) {
+	app, err := ac.GetApplication(ctx, appAddress)
+	if err != nil {
+		return ApplicationDelegations{}, fmt.Errorf("GetApplicationDelegations: error getting application %s: %w", appAddress, err)
+	}
+
+	// The ring addresses are copied, as they may share the application's
+	// delegatee addresses slice.
+	delegatedGateways := slices.Clone(rings.GetRingAddressesAtSessionEndHeight(&app, sessionEndHeight))
+	slices.Sort(delegatedGateways)
+
+	return ApplicationDelegations{
+		Application:       app,
+		SessionEndHeight:  sessionEndHeight,
+		DelegatedGateways: slices.Compact(delegatedGateways),
+	}, nil
+}
+
 // TODO_TECHDEBT: Use a more efficient logic based on a filtering query of onchain applications,
 // once the following enhancement on poktroll is implemented:
 // https://github.com/pokt-network/poktroll/issues/767
@@ -86,8 +175,19 @@ func (ac *ApplicationClient) GetApplicationsDelegatingToGateway(
 		return nil, fmt.Errorf("GetApplicationsDelegatingToGateway: error getting all applications: %w", err)
 	}
 
+	return filterApplicationsDelegatingToGateway(allApplications, gatewayAddress, sessionEndHeight), nil
+}
+
+// filterApplicationsDelegatingToGateway returns the addresses of the given
+// applications which are delegating to the given gateway address at the given
+// session end height.
+func filterApplicationsDelegatingToGateway(
+	applications []types.Application,
+	gatewayAddress string,
+	sessionEndHeight uint64,
+) []string {
 	gatewayDelegatingApplications := make([]string, 0)
-	for _, application := range allApplications {
+	for _, application := range applications {
 		// Get the gateways that are delegated to the application
 		// at the query height and check if the given gateway address is in the list.
 		gatewaysDelegatedTo := rings.GetRingAddressesAtSessionEndHeight(&application, sessionEndHeight)
@@ -97,7 +197,7 @@ func (ac *ApplicationClient) GetApplicationsDelegatingToGateway(
 		}
 	}
 
-	return gatewayDelegatingApplications, nil
+	return gatewayDelegatingApplications
 }
 
 type ApplicationRing struct {
@@ -108,14 +208,65 @@ type ApplicationRing struct {
 // GetRing returns the ring for the application until the current session end height.
 // The ring is created using the application's public key and the public keys of
 // the gateways that are currently delegated from the application.
+//
+// The public keys are fetched in parallel. All the pending fetches are canceled
+// as soon as one of them fails, or the given context is done, and the returned
+// error aggregates the errors of the failed fetches.
 func (a ApplicationRing) GetRing(
 	ctx context.Context,
 	sessionEndHeight uint64,
 ) (addressRing *ring.Ring, err error) {
+	ringPubKeys, err := a.getRingPubKeys(ctx, sessionEndHeight)
+	if err != nil {
+		return nil, fmt.Errorf("GetRing: %w", err)
+	}
+
+	return rings.GetRingFromPubKeys(ringPubKeys)
+}
+
+// GetRingPubKeys returns the public keys of the members of the application's
+// ring until the current session end height, in the order of GetRingAddresses,
+// e.g. to be sent to a remote RingSigner.
+// The public keys are fetched as done by GetRing.
+func (a ApplicationRing) GetRingPubKeys(
+	ctx context.Context,
+	sessionEndHeight uint64,
+) ([]cryptotypes.PubKey, error) {
+	ringPubKeys, err := a.getRingPubKeys(ctx, sessionEndHeight)
+	if err != nil {
+		return nil, fmt.Errorf("GetRingPubKeys: %w", err)
+	}
+	return ringPubKeys, nil
+}
+
+// getRingPubKeys fetches the public keys of the members of the application's ring.
+func (a ApplicationRing) getRingPubKeys(
+	ctx context.Context,
+	sessionEndHeight uint64,
+) ([]cryptotypes.PubKey, error) {
 	if a.PublicKeyFetcher == nil {
-		return nil, errors.New("GetRing: Public Key Fetcher not set")
+		return nil, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Public Key Fetcher not set")
+	}
+
+	ringAddresses := a.GetRingAddresses(sessionEndHeight)
+
+	pubKeys, err := getPubKeys(ctx, a.PublicKeyFetcher, ringAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	ringPubKeys := make([]cryptotypes.PubKey, 0, len(ringAddresses))
+	for _, address := range ringAddresses {
+		ringPubKeys = append(ringPubKeys, pubKeys[address])
 	}
 
+	return ringPubKeys, nil
+}
+
+// GetRingAddresses returns the addresses of the members of the application's
+// ring at the given session end height: the application itself and the gateways
+// it delegates to at that height.
+func (a ApplicationRing) GetRingAddresses(sessionEndHeight uint64) []string {
 	// Get the gateway addresses that are delegated from the application at the query height.
 	currentGatewayAddresses := rings.GetRingAddressesAtSessionEndHeight(&a.Application, sessionEndHeight)
 
@@ -129,16 +280,7 @@ func (a ApplicationRing) GetRing(
 		ringAddresses = append(ringAddresses, currentGatewayAddresses...)
 	}
 
-	ringPubKeys := make([]cryptotypes.PubKey, 0, len(ringAddresses))
-	for _, address := range ringAddresses {
-		pubKey, err := a.PublicKeyFetcher.GetPubKeyFromAddress(ctx, address)
-		if err != nil {
-			return nil, err
-		}
-		ringPubKeys = append(ringPubKeys, pubKey)
-	}
-
-	return rings.GetRingFromPubKeys(ringPubKeys)
+	return ringAddresses
 }
 
 // PublicKeyFetcher specifies an interface that allows getting the public
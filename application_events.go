@@ -0,0 +1,241 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+const (
+	// applicationTxQuery is the CometBFT event query matching the transactions
+	// including a message of the application module.
+	applicationTxQuery = "tm.event='Tx' AND message.module='application'"
+
+	// The type URLs of the application module messages changing the state of an
+	// application.
+	msgDelegateToGatewayTypeURL     = "/poktroll.application.MsgDelegateToGateway"
+	msgUndelegateFromGatewayTypeURL = "/poktroll.application.MsgUndelegateFromGateway"
+	msgStakeApplicationTypeURL      = "/poktroll.application.MsgStakeApplication"
+	msgUnstakeApplicationTypeURL    = "/poktroll.application.MsgUnstakeApplication"
+)
+
+// TxEventSubscriber subscribes to the committed transactions of a POKT full node.
+//
+// Most users can rely on the default implementation provided by the
+// NewCometBFTTxEventSubscriber function.
+type TxEventSubscriber interface {
+	// SubscribeTxEvents returns a channel receiving the events, in order, of
+	// every committed transaction matching the given CometBFT query, which is
+	// closed once the given context is done or the subscription is lost.
+	SubscribeTxEvents(ctx context.Context, query string) (<-chan []abci.Event, error)
+}
+
+// NewCometBFTTxEventSubscriber returns the default implementation of the
+// TxEventSubscriber interface.
+// It subscribes, through the CometBFT websocket endpoint of the POKT full node
+// at the given RPC URL, to the Tx events.
+func NewCometBFTTxEventSubscriber(queryNodeRpcUrl string) TxEventSubscriber {
+	return &cometBFTTxEventSubscriber{queryNodeRpcUrl: queryNodeRpcUrl}
+}
+
+// cometBFTTxEventSubscriber is a TxEventSubscriber using the CometBFT websocket
+// endpoint of a full node.
+type cometBFTTxEventSubscriber struct {
+	queryNodeRpcUrl string
+}
+
+// SubscribeTxEvents connects to the websocket endpoint of the full node and
+// subscribes to its Tx events matching the given query.
+// The connection is closed once the given context is done.
+func (s *cometBFTTxEventSubscriber) SubscribeTxEvents(ctx context.Context, query string) (<-chan []abci.Event, error) {
+	txEvents, err := subscribeCometBFTEvents(ctx, s.queryNodeRpcUrl, query, func(event ctypes.ResultEvent) ([]abci.Event, bool) {
+		tx, ok := event.Data.(cmttypes.EventDataTx)
+		if !ok {
+			return nil, false
+		}
+		return tx.Result.Events, true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscribeTxEvents: %w", err)
+	}
+	return txEvents, nil
+}
+
+// ApplicationEventWatcher watches the onchain application changes, i.e. the
+// delegations and undelegations to gateways, stakes and unstakes, and publishes
+// them on an EventBus as DelegationChangedEvents and ApplicationStakeChangedEvents.
+//
+// Subscribing the SessionCache and the DelegatingApplicationsCache to the
+// EventBus makes them refresh the changed applications mid-session, instead of
+// using stale delegations, e.g. of an application which undelegated from the
+// gateway, until the session rolls over.
+type ApplicationEventWatcher struct {
+	subscriber      TxEventSubscriber
+	eventBus        *EventBus
+	reconnectPolicy retry.Policy
+	onError         func(error)
+}
+
+// ApplicationEventWatcherOption is a functional option used to configure an
+// ApplicationEventWatcher.
+type ApplicationEventWatcherOption func(*ApplicationEventWatcher)
+
+// WithApplicationEventsReconnectPolicy sets the policy specifying the delays
+// between the consecutive reconnections of the subscription.
+// It defaults to an exponential backoff, from 1 to 30 seconds, with jitter.
+func WithApplicationEventsReconnectPolicy(policy retry.Policy) ApplicationEventWatcherOption {
+	return func(w *ApplicationEventWatcher) {
+		w.reconnectPolicy = policy
+	}
+}
+
+// WithApplicationEventsErrorObserver sets a function called with the error
+// every time the subscription fails or is lost, e.g. to log a warning.
+// Application changes committed while the subscription is down are missed,
+// and only picked up once the affected sessions roll over.
+func WithApplicationEventsErrorObserver(observer func(error)) ApplicationEventWatcherOption {
+	return func(w *ApplicationEventWatcher) {
+		w.onError = observer
+	}
+}
+
+// NewApplicationEventWatcher returns an ApplicationEventWatcher receiving the
+// transactions through the given TxEventSubscriber, and publishing the
+// application changes on the given EventBus, configured using the given options.
+func NewApplicationEventWatcher(
+	subscriber TxEventSubscriber,
+	eventBus *EventBus,
+	opts ...ApplicationEventWatcherOption,
+) *ApplicationEventWatcher {
+	w := &ApplicationEventWatcher{
+		subscriber:      subscriber,
+		eventBus:        eventBus,
+		reconnectPolicy: defaultSubscriptionReconnectPolicy,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Run subscribes to the transactions of the application module and publishes
+// the application changes, reconnecting whenever the subscription fails or is
+// lost, until the given context is done.
+func (w *ApplicationEventWatcher) Run(ctx context.Context) error {
+	if w.subscriber == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Run: TxEventSubscriber not set")
+	}
+	if w.eventBus == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Run: EventBus not set")
+	}
+
+	return runSubscription(ctx, w.reconnectPolicy, w.onError, w.subscribe)
+}
+
+// subscribe publishes the application changes of the committed transactions,
+// until the given context is done or the subscription is lost.
+// It returns whether any transaction was received, along with the reason the
+// subscription ended.
+func (w *ApplicationEventWatcher) subscribe(ctx context.Context) (received bool, err error) {
+	subscriptionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	txs, err := w.subscriber.SubscribeTxEvents(subscriptionCtx, applicationTxQuery)
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return received, ctx.Err()
+		case txEvents, ok := <-txs:
+			if !ok {
+				return received, errors.New("application events subscription closed")
+			}
+			received = true
+
+			for _, event := range applicationEventsFromTx(txEvents) {
+				w.eventBus.Publish(event)
+			}
+		}
+	}
+}
+
+// applicationEventsFromTx returns the application changes of the transaction
+// with the given events.
+//
+// Each message of a transaction emits a "message" event, whose "action" and
+// "sender" attributes are the message's type URL and signer, i.e. the address
+// of the application for the application module messages, followed by the
+// events emitted while handling it. The gateway of a (un)delegation is read
+// from the "gateway_address" attribute of the latter, if any.
+func applicationEventsFromTx(txEvents []abci.Event) []Event {
+	var (
+		events []Event
+		// current is the index in events of the change of the message whose
+		// events are being read, or -1 if it is not an application change.
+		current = -1
+	)
+	for _, txEvent := range txEvents {
+		if action, ok := eventAttribute(txEvent, "action"); ok && txEvent.Type == "message" {
+			current = -1
+			sender, _ := eventAttribute(txEvent, "sender")
+
+			var event Event
+			switch action {
+			case msgDelegateToGatewayTypeURL:
+				event = DelegationChangedEvent{AppAddress: sender, Delegated: true}
+			case msgUndelegateFromGatewayTypeURL:
+				event = DelegationChangedEvent{AppAddress: sender}
+			case msgStakeApplicationTypeURL:
+				event = ApplicationStakeChangedEvent{AppAddress: sender, Staked: true}
+			case msgUnstakeApplicationTypeURL:
+				event = ApplicationStakeChangedEvent{AppAddress: sender}
+			}
+			if event != nil && sender != "" {
+				current = len(events)
+				events = append(events, event)
+			}
+			continue
+		}
+
+		if current < 0 {
+			continue
+		}
+		delegationChanged, ok := events[current].(DelegationChangedEvent)
+		if !ok || delegationChanged.GatewayAddress != "" {
+			continue
+		}
+		if gatewayAddress, ok := eventAttribute(txEvent, "gateway_address"); ok {
+			delegationChanged.GatewayAddress = gatewayAddress
+			events[current] = delegationChanged
+		}
+	}
+
+	return events
+}
+
+// eventAttribute returns the value of the given attribute of the given event.
+// The JSON-quoted values of the typed events are unquoted.
+func eventAttribute(event abci.Event, key string) (string, bool) {
+	for _, attribute := range event.Attributes {
+		if attribute.Key != key {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(attribute.Value); err == nil {
+			return unquoted, true
+		}
+		return attribute.Value, true
+	}
+	return "", false
+}
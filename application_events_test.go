@@ -0,0 +1,101 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestApplicationEventsFromTx(t *testing.T) {
+	txEvents := []abci.Event{
+		messageEvent(msgDelegateToGatewayTypeURL, "pokt1app1"),
+		{Type: "poktroll.application.EventRedelegation", Attributes: []abci.EventAttribute{
+			{Key: "app_address", Value: `"pokt1app1"`},
+			{Key: "gateway_address", Value: `"pokt1gw"`},
+		}},
+		messageEvent("/cosmos.bank.v1beta1.MsgSend", "pokt1app2"),
+		messageEvent(msgStakeApplicationTypeURL, "pokt1app2"),
+		// The events emitted while handling a message may include other
+		// "message" events, e.g. the transfer of the stake.
+		{Type: "message", Attributes: []abci.EventAttribute{{Key: "sender", Value: "pokt1app2"}}},
+		{Type: "transfer", Attributes: []abci.EventAttribute{{Key: "recipient", Value: "pokt1module"}}},
+		messageEvent(msgUndelegateFromGatewayTypeURL, "pokt1app3"),
+		messageEvent(msgUnstakeApplicationTypeURL, "pokt1app4"),
+	}
+
+	require.Equal(t, []Event{
+		DelegationChangedEvent{AppAddress: "pokt1app1", GatewayAddress: "pokt1gw", Delegated: true},
+		ApplicationStakeChangedEvent{AppAddress: "pokt1app2", Staked: true},
+		DelegationChangedEvent{AppAddress: "pokt1app3"},
+		ApplicationStakeChangedEvent{AppAddress: "pokt1app4"},
+	}, applicationEventsFromTx(txEvents))
+
+	require.Empty(t, applicationEventsFromTx([]abci.Event{messageEvent("/cosmos.bank.v1beta1.MsgSend", "pokt1app1")}))
+}
+
+func TestApplicationEventWatcher_Run(t *testing.T) {
+	subscriber := &fakeTxEventSubscriber{subscriptions: make(chan chan []abci.Event), queries: make(chan string, 1)}
+	bus := NewEventBus()
+	published := make(chan Event, 1)
+	bus.Subscribe(func(event Event) { published <- event })
+
+	subscriptionErrs := make(chan error, 1)
+	watcher := NewApplicationEventWatcher(
+		subscriber,
+		bus,
+		WithApplicationEventsReconnectPolicy(retry.Constant{}),
+		WithApplicationEventsErrorObserver(func(err error) { subscriptionErrs <- err }),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- watcher.Run(ctx) }()
+
+	txs := <-subscriber.subscriptions
+	require.Equal(t, applicationTxQuery, <-subscriber.queries)
+	txs <- []abci.Event{messageEvent(msgUndelegateFromGatewayTypeURL, "pokt1app1")}
+	require.Equal(t, DelegationChangedEvent{AppAddress: "pokt1app1"}, <-published)
+
+	// A lost subscription is reconnected.
+	close(txs)
+	require.Error(t, <-subscriptionErrs)
+	txs = <-subscriber.subscriptions
+	<-subscriber.queries
+	txs <- []abci.Event{messageEvent(msgUnstakeApplicationTypeURL, "pokt1app2")}
+	require.Equal(t, ApplicationStakeChangedEvent{AppAddress: "pokt1app2"}, <-published)
+
+	cancel()
+	require.ErrorIs(t, <-runErr, context.Canceled)
+
+	require.ErrorIs(t, NewApplicationEventWatcher(subscriber, nil).Run(context.Background()), sdkerrors.ErrNotConfigured)
+}
+
+// messageEvent returns the "message" event emitted for a message of the given
+// type URL signed by the given address.
+func messageEvent(action, sender string) abci.Event {
+	return abci.Event{Type: "message", Attributes: []abci.EventAttribute{
+		{Key: "action", Value: action},
+		{Key: "sender", Value: sender},
+		{Key: "module", Value: "application"},
+	}}
+}
+
+// fakeTxEventSubscriber is a TxEventSubscriber sending the channel and the query
+// of every new subscription to the subscriptions and queries channels, for the
+// test to push transactions.
+type fakeTxEventSubscriber struct {
+	subscriptions chan chan []abci.Event
+	queries       chan string
+}
+
+func (f *fakeTxEventSubscriber) SubscribeTxEvents(_ context.Context, query string) (<-chan []abci.Event, error) {
+	txs := make(chan []abci.Event)
+	f.subscriptions <- txs
+	f.queries <- query
+	return txs, nil
+}
@@ -0,0 +1,149 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestApplicationRing_GetRing(t *testing.T) {
+	fetcher := &countingPubKeyFetcher{
+		pubKeys: map[string]cryptotypes.PubKey{"pokt1app": secp256k1.GenPrivKey().PubKey()},
+	}
+	appRing := ApplicationRing{
+		Application:      apptypes.Application{Address: "pokt1app"},
+		PublicKeyFetcher: fetcher,
+	}
+
+	// The application address appears twice in the ring of an application
+	// without delegations, but its public key is only fetched once.
+	ring, err := appRing.GetRing(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, ring.Size())
+	require.Equal(t, int64(1), fetcher.calls.Load())
+}
+
+func TestApplicationRing_GetRing_FetchErrors(t *testing.T) {
+	errNotFound := errors.New("account not found")
+	appRing := ApplicationRing{
+		Application: apptypes.Application{
+			Address:                   "pokt1app",
+			DelegateeGatewayAddresses: []string{"pokt1gw1", "pokt1gw2"},
+		},
+		PublicKeyFetcher: &blockingPubKeyFetcher{failingAddress: "pokt1gw1", err: errNotFound},
+	}
+
+	// The failed fetch cancels the pending ones, which are not reported.
+	_, err := appRing.GetRing(context.Background(), 10)
+	require.ErrorIs(t, err, errNotFound)
+	require.NotErrorIs(t, err, context.Canceled)
+	require.ErrorContains(t, err, "pokt1gw1")
+}
+
+// blockingPubKeyFetcher is a PublicKeyFetcher which fails fetching the given
+// address, and blocks fetching any other address until the context is done.
+type blockingPubKeyFetcher struct {
+	failingAddress string
+	err            error
+}
+
+func (f *blockingPubKeyFetcher) GetPubKeyFromAddress(ctx context.Context, address string) (cryptotypes.PubKey, error) {
+	if address == f.failingAddress {
+		return nil, f.err
+	}
+
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestApplicationClient_GetActiveApplication(t *testing.T) {
+	ac := ApplicationClient{
+		QueryClient: &fakeAppQueryClient{apps: map[string]apptypes.Application{
+			"pokt1active":    {Address: "pokt1active"},
+			"pokt1unbonding": {Address: "pokt1unbonding", UnstakeSessionEndHeight: 100},
+		}},
+	}
+
+	app, err := ac.GetActiveApplication(context.Background(), "pokt1active")
+	require.NoError(t, err)
+	require.False(t, IsApplicationUnbonding(app))
+
+	app, err = ac.GetActiveApplication(context.Background(), "pokt1unbonding")
+	require.ErrorIs(t, err, ErrApplicationUnbonding)
+	require.True(t, IsApplicationUnbonding(app))
+}
+
+func TestApplicationClient_GetApplicationDelegations(t *testing.T) {
+	ac := ApplicationClient{
+		QueryClient: &fakeAppQueryClient{apps: map[string]apptypes.Application{
+			"pokt1app": {
+				Address:                   "pokt1app",
+				DelegateeGatewayAddresses: []string{"pokt1gw2", "pokt1gw1"},
+				PendingUndelegations: map[uint64]apptypes.UndelegatingGatewayList{
+					// gw3 undelegated during the session ending at 20.
+					20: {GatewayAddresses: []string{"pokt1gw3"}},
+				},
+			},
+		}},
+	}
+
+	// gw3 is still part of the ring of the sessions preceding its undelegation.
+	delegations, err := ac.GetApplicationDelegations(context.Background(), "pokt1app", 10)
+	require.NoError(t, err)
+	require.Equal(t, "pokt1app", delegations.Address)
+	require.Equal(t, []string{"pokt1gw1", "pokt1gw2", "pokt1gw3"}, delegations.DelegatedGateways)
+	require.True(t, delegations.CanSign("pokt1gw3"))
+
+	delegations, err = ac.GetApplicationDelegations(context.Background(), "pokt1app", 30)
+	require.NoError(t, err)
+	require.Equal(t, []string{"pokt1gw1", "pokt1gw2"}, delegations.DelegatedGateways)
+	require.False(t, delegations.CanSign("pokt1gw3"))
+
+	_, err = ac.GetApplicationDelegations(context.Background(), "pokt1unknown", 10)
+	require.Error(t, err)
+}
+
+// fakeAppQueryClient is an application module QueryClient serving the given applications.
+// Calling any method other than Application and AllApplications panics.
+type fakeAppQueryClient struct {
+	apptypes.QueryClient
+	apps map[string]apptypes.Application
+
+	applicationCalls    atomic.Int64
+	allApplicationCalls atomic.Int64
+}
+
+func (c *fakeAppQueryClient) Application(
+	_ context.Context,
+	req *apptypes.QueryGetApplicationRequest,
+	_ ...grpcoptions.CallOption,
+) (*apptypes.QueryGetApplicationResponse, error) {
+	c.applicationCalls.Add(1)
+	app, ok := c.apps[req.Address]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "application not found")
+	}
+	return &apptypes.QueryGetApplicationResponse{Application: app}, nil
+}
+
+func (c *fakeAppQueryClient) AllApplications(
+	_ context.Context,
+	_ *apptypes.QueryAllApplicationsRequest,
+	_ ...grpcoptions.CallOption,
+) (*apptypes.QueryAllApplicationsResponse, error) {
+	c.allApplicationCalls.Add(1)
+	return &apptypes.QueryAllApplicationsResponse{
+		Applications: slices.Collect(maps.Values(c.apps)),
+	}, nil
+}
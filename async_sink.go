@@ -0,0 +1,112 @@
+package sdk
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncSink delivers values, e.g. events, audit records or metrics, to a
+// handler running in its own goroutine, so a slow handler, e.g. one writing to
+// disk or to Kafka, never adds latency to the relay hot path.
+//
+// Values are buffered in a bounded queue. When the queue is full, the oldest
+// queued value is dropped to make room for the new one, and counted as dropped.
+// An AsyncSink is safe for concurrent use.
+type AsyncSink[T any] struct {
+	handle  func(T)
+	queue   chan T
+	dropped atomic.Uint64
+	done    chan struct{}
+
+	// closeMu protects closed, and prevents sending to the queue once it is closed.
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+// NewAsyncSink returns an AsyncSink which calls the given handler with every
+// value sent to it, buffering at most queueSize values.
+// A queueSize lower than 1 is treated as 1.
+// Close must be called to release the handler's goroutine.
+func NewAsyncSink[T any](handle func(T), queueSize int) *AsyncSink[T] {
+	s := &AsyncSink[T]{
+		handle: handle,
+		queue:  make(chan T, max(queueSize, 1)),
+		done:   make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// Send queues the given value for the handler, without blocking.
+// If the queue is full, the oldest queued value is dropped.
+// Values sent after the sink is closed are dropped.
+func (s *AsyncSink[T]) Send(value T) {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+
+	if s.closed {
+		s.dropped.Add(1)
+		return
+	}
+
+	for {
+		select {
+		case s.queue <- value:
+			return
+		default:
+		}
+
+		// The queue is full: drop the oldest value, unless the handler
+		// dequeued it concurrently, and try again.
+		select {
+		case <-s.queue:
+			s.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of values dropped because the queue was full, or
+// because they were sent after the sink was closed.
+func (s *AsyncSink[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Len returns the number of values waiting in the queue.
+func (s *AsyncSink[T]) Len() int {
+	return len(s.queue)
+}
+
+// Close stops accepting values, and waits for the handler to process the
+// values remaining in the queue.
+func (s *AsyncSink[T]) Close() {
+	s.closeMu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.closeMu.Unlock()
+
+	<-s.done
+}
+
+// run calls the handler with the queued values until the queue is closed.
+func (s *AsyncSink[T]) run() {
+	defer close(s.done)
+
+	for value := range s.queue {
+		s.handle(value)
+	}
+}
+
+// NewAsyncEventHandler returns an EventHandler which forwards the events to the
+// given handler through an AsyncSink, so a slow handler can be subscribed to an
+// EventBus without blocking the publishers.
+// The returned AsyncSink reports the dropped events, and must be closed once
+// the handler is unsubscribed.
+func NewAsyncEventHandler(handler EventHandler, queueSize int) (EventHandler, *AsyncSink[Event]) {
+	sink := NewAsyncSink(func(event Event) { handler(event) }, queueSize)
+	return sink.Send, sink
+}
@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncSink_DropOldest(t *testing.T) {
+	release := make(chan struct{})
+	var received []int
+	sink := NewAsyncSink(func(value int) {
+		<-release
+		received = append(received, value)
+	}, 2)
+
+	// Wait for the handler to dequeue the first value, so the queue holds
+	// exactly the values sent afterwards.
+	sink.Send(0)
+	require.Eventually(t, func() bool { return sink.Len() == 0 }, time.Second, time.Millisecond)
+
+	// Sending does not block on the slow handler: the oldest values are dropped.
+	for value := 1; value <= 5; value++ {
+		sink.Send(value)
+	}
+	require.Equal(t, uint64(3), sink.Dropped())
+
+	close(release)
+	sink.Close()
+	require.Equal(t, []int{0, 4, 5}, received)
+
+	// Values sent after the sink is closed are dropped.
+	sink.Send(6)
+	require.Equal(t, uint64(4), sink.Dropped())
+}
+
+func TestNewAsyncEventHandler(t *testing.T) {
+	bus := NewEventBus()
+
+	var received []Event
+	handler, sink := NewAsyncEventHandler(func(event Event) {
+		received = append(received, event)
+	}, 10)
+	unsubscribe := bus.Subscribe(handler, EventCacheEvicted)
+
+	bus.Publish(CacheEvictedEvent{Key: SessionKey{AppAddress: "app1", ServiceId: "svc1"}})
+	unsubscribe()
+	sink.Close()
+
+	require.Equal(t, []Event{CacheEvictedEvent{Key: SessionKey{AppAddress: "app1", ServiceId: "svc1"}}}, received)
+	require.Zero(t, sink.Dropped())
+}
@@ -8,10 +8,9 @@ import (
 
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	cosmos "github.com/cosmos/cosmos-sdk/client"
-)
 
-// TODO_IDEA: The BlockClient could leverage websockets to get notified about new blocks
-// and cache the latest block height to avoid querying the blockchain for it every time.
+	"github.com/pokt-network/shannon-sdk/retry"
+)
 
 // BlockClient is a concrete type used to interact with the on-chain block module.
 // For example, it can be used to get the latest block height.
@@ -19,19 +18,84 @@ import (
 // For obtaining the latest height, BlockClient uses a POKT full node's status
 // which contains the latest block height. This is done to avoid fetching the
 // entire latest block just to extract the block height.
+// If configured using WithBlockSubscription, BlockClient returns the height
+// pushed by the full node while the subscription is live, and only polls the
+// status otherwise.
 type BlockClient struct {
 	// PoktNodeStatusFetcher specifies the functionality required by the
 	// BlockClient to interact with a POKT full node.
 	PoktNodeStatusFetcher
+
+	// RetryConfig, if set, specifies how failed requests to the POKT full node
+	// should be retried.
+	RetryConfig *retry.Config
+
+	// subscription, if set, tracks the latest block height pushed by the full node.
+	subscription *BlockSubscription
+}
+
+// BlockClientOption is a functional option used to configure a BlockClient.
+type BlockClientOption func(*BlockClient)
+
+// WithRetryConfig sets the retry configuration used by the BlockClient for
+// requests to the POKT full node.
+func WithRetryConfig(retryConfig retry.Config) BlockClientOption {
+	return func(bc *BlockClient) {
+		bc.RetryConfig = &retryConfig
+	}
+}
+
+// WithStatusFetcher sets the PoktNodeStatusFetcher used by the BlockClient,
+// overriding the default implementation.
+func WithStatusFetcher(statusFetcher PoktNodeStatusFetcher) BlockClientOption {
+	return func(bc *BlockClient) {
+		bc.PoktNodeStatusFetcher = statusFetcher
+	}
+}
+
+// WithBlockSubscription sets the BlockSubscription used by the BlockClient to
+// get the latest block height without polling the full node's status.
+// The subscription must be run, using BlockSubscription.Run, by the caller.
+func WithBlockSubscription(subscription *BlockSubscription) BlockClientOption {
+	return func(bc *BlockClient) {
+		bc.subscription = subscription
+	}
+}
+
+// NewBlockClient returns a BlockClient which uses the default PoktNodeStatusFetcher
+// to connect to the POKT full node at the given RPC URL, configured using the given options.
+func NewBlockClient(queryNodeRpcUrl string, opts ...BlockClientOption) (*BlockClient, error) {
+	bc := &BlockClient{}
+	for _, opt := range opts {
+		opt(bc)
+	}
+
+	if bc.PoktNodeStatusFetcher == nil {
+		statusFetcher, err := NewPoktNodeStatusFetcher(queryNodeRpcUrl)
+		if err != nil {
+			return nil, fmt.Errorf("NewBlockClient: %w", err)
+		}
+		bc.PoktNodeStatusFetcher = statusFetcher
+	}
+
+	return bc, nil
 }
 
 // LatestBlockHeight returns the height of the latest committed block in the blockchain.
+// The height pushed through the BlockSubscription, if any, is returned while the
+// subscription is live, falling back to polling the full node's status otherwise.
 func (bc *BlockClient) LatestBlockHeight(ctx context.Context) (height int64, err error) {
+	if bc.subscription != nil {
+		if height, live := bc.subscription.LatestBlockHeight(); live {
+			return height, nil
+		}
+	}
+
 	if bc.PoktNodeStatusFetcher == nil {
 		return 0, errors.New("LatestBlockHeight: nil PoktNodeStatusFetcher")
 	}
 
-	nodeStatus, err := bc.PoktNodeStatusFetcher.Status(ctx)
+	nodeStatus, err := bc.status(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -39,6 +103,26 @@ func (bc *BlockClient) LatestBlockHeight(ctx context.Context) (height int64, err
 	return nodeStatus.SyncInfo.LatestBlockHeight, nil
 }
 
+// status returns the status of the POKT full node, retrying the failed requests
+// according to the RetryConfig, if set.
+func (bc *BlockClient) status(ctx context.Context) (nodeStatus *ctypes.ResultStatus, err error) {
+	fetchStatus := func(ctx context.Context) error {
+		nodeStatus, err = bc.PoktNodeStatusFetcher.Status(ctx)
+		return err
+	}
+
+	if bc.RetryConfig != nil {
+		err = retry.Do(ctx, *bc.RetryConfig, fetchStatus)
+	} else {
+		err = fetchStatus(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nodeStatus, nil
+}
+
 // NewPoktNodeStatusFetcher returns the default implementation of the PoktNodeStatusFetcher interface.
 // It connects, through a cometbft RPC HTTP client, to a POKT full node to get its status.
 func NewPoktNodeStatusFetcher(queryNodeRpcUrl string) (PoktNodeStatusFetcher, error) {
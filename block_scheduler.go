@@ -0,0 +1,177 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// defaultSchedulerPollInterval is the interval at which the BlockScheduler polls
+// the latest block height if no poll interval is specified.
+const defaultSchedulerPollInterval = time.Second
+
+// BlockScheduler runs callbacks at block boundaries, i.e. once the latest block
+// height reaches a given height or every N blocks.
+// It lets gateways coordinate work to block boundaries, e.g. session rollovers,
+// without each writing its own polling loop.
+// If the BlockClient is configured using WithBlockSubscription, the callbacks
+// run as soon as a new block is pushed by the full node, instead of on the
+// next poll.
+//
+// Callbacks are run sequentially by Run, in the order of the heights they are
+// scheduled at, and must not block.
+// A BlockScheduler is safe for concurrent use.
+type BlockScheduler struct {
+	blockClient  *BlockClient
+	pollInterval time.Duration
+
+	mu         sync.Mutex
+	lastHeight int64
+	atHeight   []heightTask
+	everyN     []periodicTask
+}
+
+// heightTask is a callback scheduled to run once at a given height.
+type heightTask struct {
+	height int64
+	fn     func()
+}
+
+// periodicTask is a callback scheduled to run every n blocks.
+type periodicTask struct {
+	n  int64
+	fn func()
+}
+
+// NewBlockScheduler returns a BlockScheduler which polls the latest block height
+// using the given BlockClient, at the given interval.
+// The latest block height is polled every second if pollInterval is zero.
+func NewBlockScheduler(blockClient *BlockClient, pollInterval time.Duration) *BlockScheduler {
+	if pollInterval <= 0 {
+		pollInterval = defaultSchedulerPollInterval
+	}
+
+	return &BlockScheduler{
+		blockClient:  blockClient,
+		pollInterval: pollInterval,
+	}
+}
+
+// AtHeight schedules fn to run once, as soon as the latest block height is at
+// least the given height.
+// If the height has already been reached, fn runs on the next poll.
+func (s *BlockScheduler) AtHeight(height int64, fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.atHeight = append(s.atHeight, heightTask{height: height, fn: fn})
+}
+
+// EveryNBlocks schedules fn to run every time the latest block height reaches
+// a multiple of n.
+// If several multiples of n are crossed between two polls, fn runs only once.
+func (s *BlockScheduler) EveryNBlocks(n int64, fn func()) error {
+	if n <= 0 {
+		return errors.New("EveryNBlocks: n must be positive")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.everyN = append(s.everyN, periodicTask{n: n, fn: fn})
+	return nil
+}
+
+// Run polls the latest block height and runs the scheduled callbacks, until
+// the given context is done.
+// Errors fetching the latest block height are ignored: the height is fetched
+// again on the next poll.
+func (s *BlockScheduler) Run(ctx context.Context) error {
+	if s.blockClient == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Run: BlockClient not set")
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	// The new blocks pushed through the BlockClient's subscription, if any, wake
+	// up the loop. Polling goes on as the fallback of a lost subscription.
+	newBlock := make(chan struct{}, 1)
+	if s.blockClient.subscription != nil {
+		unsubscribe := s.blockClient.subscription.OnNewBlock(func(int64) {
+			select {
+			case newBlock <- struct{}{}:
+			default:
+			}
+		})
+		defer unsubscribe()
+	}
+
+	for {
+		if height, err := s.blockClient.LatestBlockHeight(ctx); err == nil {
+			s.onHeight(height)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-newBlock:
+		}
+	}
+}
+
+// onHeight runs the callbacks which are due at the given latest block height.
+func (s *BlockScheduler) onHeight(height int64) {
+	for _, fn := range s.dueTasks(height) {
+		fn()
+	}
+}
+
+// dueTasks returns the callbacks due at the given latest block height, removing
+// the one-off ones from the schedule.
+func (s *BlockScheduler) dueTasks(height int64) []func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if height <= s.lastHeight {
+		return nil
+	}
+	previousHeight := s.lastHeight
+	s.lastHeight = height
+
+	// Run the one-off callbacks in the order of their scheduled heights.
+	sort.SliceStable(s.atHeight, func(i, j int) bool {
+		return s.atHeight[i].height < s.atHeight[j].height
+	})
+
+	var dueFns []func()
+	remaining := s.atHeight[:0]
+	for _, task := range s.atHeight {
+		if task.height <= height {
+			dueFns = append(dueFns, task.fn)
+			continue
+		}
+		remaining = append(remaining, task)
+	}
+	s.atHeight = remaining
+
+	for _, task := range s.everyN {
+		// On the first observed height, only run the callback if the height is a
+		// multiple of n. Afterwards, run it if a multiple of n was crossed since
+		// the previously observed height.
+		crossed := height/task.n > previousHeight/task.n
+		if previousHeight == 0 {
+			crossed = height%task.n == 0
+		}
+		if crossed {
+			dueFns = append(dueFns, task.fn)
+		}
+	}
+
+	return dueFns
+}
@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockScheduler(t *testing.T) {
+	scheduler := NewBlockScheduler(&BlockClient{}, 0)
+
+	var calls []string
+	scheduler.AtHeight(12, func() { calls = append(calls, "at12") })
+	scheduler.AtHeight(11, func() { calls = append(calls, "at11") })
+	require.NoError(t, scheduler.EveryNBlocks(5, func() { calls = append(calls, "every5") }))
+	require.Error(t, scheduler.EveryNBlocks(0, func() {}))
+
+	// The first observed height is not a multiple of 5.
+	scheduler.onHeight(9)
+	require.Empty(t, calls)
+
+	scheduler.onHeight(10)
+	require.Equal(t, []string{"every5"}, calls)
+
+	// Already observed heights are ignored.
+	calls = nil
+	scheduler.onHeight(10)
+	require.Empty(t, calls)
+
+	// Heights skipped between polls still trigger the due callbacks, once.
+	scheduler.onHeight(21)
+	require.Equal(t, []string{"at11", "at12", "every5"}, calls)
+
+	// One-off callbacks run only once.
+	calls = nil
+	scheduler.onHeight(22)
+	require.Empty(t, calls)
+
+	// Callbacks scheduled at a past height run on the next observed height.
+	scheduler.AtHeight(5, func() { calls = append(calls, "at5") })
+	scheduler.onHeight(23)
+	require.Equal(t, []string{"at5"}, calls)
+}
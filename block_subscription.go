@@ -0,0 +1,351 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+const (
+	// newBlockQuery is the CometBFT event query matching the new blocks.
+	newBlockQuery = "tm.event='NewBlock'"
+	// cometBFTSubscriberName identifies the SDK's subscriptions to the full node.
+	cometBFTSubscriberName = "shannon-sdk"
+
+	// defaultBlockSubscriptionStaleAfter is the duration without a new block
+	// after which the subscription is considered lost, if none is specified.
+	defaultBlockSubscriptionStaleAfter = 30 * time.Second
+)
+
+// defaultSubscriptionReconnectPolicy is the policy specifying the delays
+// between the reconnections of the subscriptions, if none is specified.
+var defaultSubscriptionReconnectPolicy = retry.Exponential{
+	Initial: time.Second,
+	Max:     30 * time.Second,
+	Jitter:  true,
+}
+
+// NewBlockSubscriber subscribes to the new blocks committed by a POKT full node.
+//
+// Most users can rely on the default implementation provided by the
+// NewCometBFTNewBlockSubscriber function.
+type NewBlockSubscriber interface {
+	// SubscribeNewBlocks returns a channel receiving the height of every new
+	// block, which is closed once the given context is done or the subscription
+	// is lost.
+	SubscribeNewBlocks(ctx context.Context) (<-chan int64, error)
+}
+
+// NewCometBFTNewBlockSubscriber returns the default implementation of the
+// NewBlockSubscriber interface.
+// It subscribes, through the CometBFT websocket endpoint of the POKT full node
+// at the given RPC URL, to the NewBlock events.
+func NewCometBFTNewBlockSubscriber(queryNodeRpcUrl string) NewBlockSubscriber {
+	return &cometBFTNewBlockSubscriber{queryNodeRpcUrl: queryNodeRpcUrl}
+}
+
+// cometBFTNewBlockSubscriber is a NewBlockSubscriber using the CometBFT websocket
+// endpoint of a full node.
+type cometBFTNewBlockSubscriber struct {
+	queryNodeRpcUrl string
+}
+
+// SubscribeNewBlocks connects to the websocket endpoint of the full node and
+// subscribes to its NewBlock events.
+// The connection is closed once the given context is done.
+func (s *cometBFTNewBlockSubscriber) SubscribeNewBlocks(ctx context.Context) (<-chan int64, error) {
+	heights, err := subscribeCometBFTEvents(ctx, s.queryNodeRpcUrl, newBlockQuery, func(event ctypes.ResultEvent) (int64, bool) {
+		newBlock, ok := event.Data.(cmttypes.EventDataNewBlock)
+		if !ok || newBlock.Block == nil {
+			return 0, false
+		}
+		return newBlock.Block.Height, true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscribeNewBlocks: %w", err)
+	}
+	return heights, nil
+}
+
+// subscribeCometBFTEvents connects to the websocket endpoint of the full node at
+// the given RPC URL, and subscribes to the events matching the given query.
+// The returned channel receives the values extracted from the events by the
+// given function, skipping the events it rejects, and is closed once the given
+// context is done or the subscription is lost.
+func subscribeCometBFTEvents[T any](
+	ctx context.Context,
+	queryNodeRpcUrl string,
+	query string,
+	extract func(ctypes.ResultEvent) (T, bool),
+) (<-chan T, error) {
+	client, err := rpchttp.New(queryNodeRpcUrl, "/websocket")
+	if err != nil {
+		return nil, fmt.Errorf("error constructing the websocket client: %w", err)
+	}
+	if err := client.Start(); err != nil {
+		return nil, fmt.Errorf("error connecting to the websocket endpoint: %w", err)
+	}
+
+	events, err := client.Subscribe(ctx, cometBFTSubscriberName, query)
+	if err != nil {
+		_ = client.Stop()
+		return nil, fmt.Errorf("error subscribing to %q: %w", query, err)
+	}
+
+	values := make(chan T)
+	go func() {
+		defer close(values)
+		defer func() { _ = client.Stop() }()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+
+				value, ok := extract(event)
+				if !ok {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case values <- value:
+				}
+			}
+		}
+	}()
+
+	return values, nil
+}
+
+// BlockSubscription keeps track of the latest block height pushed by a full
+// node through a NewBlockSubscriber, reconnecting whenever the subscription is
+// lost, e.g. if the websocket connection drops or no block was received for a
+// while.
+//
+// A BlockClient configured using WithBlockSubscription returns the tracked
+// height while the subscription is live, and falls back to polling the full
+// node's status otherwise.
+// A BlockSubscription is safe for concurrent use.
+type BlockSubscription struct {
+	subscriber      NewBlockSubscriber
+	staleAfter      time.Duration
+	reconnectPolicy retry.Policy
+	onError         func(error)
+	// now returns the current time. It is overridden in tests.
+	now func() time.Time
+
+	mu         sync.Mutex
+	live       bool
+	height     int64
+	receivedAt time.Time
+	nextId     uint64
+	handlers   map[uint64]func(height int64)
+}
+
+// BlockSubscriptionOption is a functional option used to configure a BlockSubscription.
+type BlockSubscriptionOption func(*BlockSubscription)
+
+// WithSubscriptionStaleAfter sets the duration without a new block after which the
+// subscription is considered lost, and is reconnected.
+// It defaults to 30 seconds, which should be well above the chain's block time.
+func WithSubscriptionStaleAfter(staleAfter time.Duration) BlockSubscriptionOption {
+	return func(s *BlockSubscription) {
+		s.staleAfter = staleAfter
+	}
+}
+
+// WithSubscriptionReconnectPolicy sets the policy specifying the delays between the
+// consecutive reconnections of the subscription.
+// It defaults to an exponential backoff, from 1 to 30 seconds, with jitter.
+func WithSubscriptionReconnectPolicy(policy retry.Policy) BlockSubscriptionOption {
+	return func(s *BlockSubscription) {
+		s.reconnectPolicy = policy
+	}
+}
+
+// WithSubscriptionErrorObserver sets a function called with the error every
+// time the subscription fails or is lost, e.g. to log a warning.
+func WithSubscriptionErrorObserver(observer func(error)) BlockSubscriptionOption {
+	return func(s *BlockSubscription) {
+		s.onError = observer
+	}
+}
+
+// NewBlockSubscription returns a BlockSubscription using the given NewBlockSubscriber,
+// configured using the given options.
+// The subscription is established by Run.
+func NewBlockSubscription(subscriber NewBlockSubscriber, opts ...BlockSubscriptionOption) *BlockSubscription {
+	s := &BlockSubscription{
+		subscriber:      subscriber,
+		staleAfter:      defaultBlockSubscriptionStaleAfter,
+		reconnectPolicy: defaultSubscriptionReconnectPolicy,
+		now:             time.Now,
+		handlers:        make(map[uint64]func(height int64)),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Run subscribes to the new blocks and tracks their heights, reconnecting
+// whenever the subscription fails or is lost, until the given context is done.
+func (s *BlockSubscription) Run(ctx context.Context) error {
+	if s.subscriber == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Run: NewBlockSubscriber not set")
+	}
+
+	return runSubscription(ctx, s.reconnectPolicy, s.onError, func(ctx context.Context) (bool, error) {
+		defer s.setLive(false)
+		return s.subscribe(ctx)
+	})
+}
+
+// runSubscription calls subscribe until the given context is done, waiting for
+// the delays of the given reconnect policy between the consecutive calls, and
+// reporting the error of each call to onError, if set.
+// The subscribe function returns, along with the reason the subscription ended,
+// whether it received any event, which resets the reconnect policy's backoff.
+func runSubscription(
+	ctx context.Context,
+	reconnectPolicy retry.Policy,
+	onError func(error),
+	subscribe func(ctx context.Context) (received bool, err error),
+) error {
+	for attempt := 1; ; attempt++ {
+		received, err := subscribe(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if received {
+			attempt = 1
+		}
+		if onError != nil {
+			onError(err)
+		}
+
+		var delay time.Duration
+		if reconnectPolicy != nil {
+			delay = reconnectPolicy.Delay(attempt)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// LatestBlockHeight returns the height of the latest block received, if the
+// subscription is live.
+func (s *BlockSubscription) LatestBlockHeight() (height int64, live bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.live || s.now().Sub(s.receivedAt) > s.staleAfter {
+		return 0, false
+	}
+	return s.height, true
+}
+
+// OnNewBlock registers the given handler, called with the height of every new
+// block received. The handler is called synchronously, in the goroutine of
+// Run, and must not block.
+// The returned function removes the handler.
+func (s *BlockSubscription) OnNewBlock(handler func(height int64)) (unsubscribe func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextId
+	s.nextId++
+	s.handlers[id] = handler
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.handlers, id)
+	}
+}
+
+// subscribe subscribes to the new blocks and tracks their heights, until the
+// given context is done or the subscription is lost.
+// It returns whether any block was received, along with the reason the
+// subscription ended.
+func (s *BlockSubscription) subscribe(ctx context.Context) (received bool, err error) {
+	subscriptionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	heights, err := s.subscriber.SubscribeNewBlocks(subscriptionCtx)
+	if err != nil {
+		return false, err
+	}
+
+	staleTimer := time.NewTimer(s.staleAfter)
+	defer staleTimer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return received, ctx.Err()
+		case <-staleTimer.C:
+			return received, fmt.Errorf("no new block received for %s", s.staleAfter)
+		case height, ok := <-heights:
+			if !ok {
+				return received, errors.New("new block subscription closed")
+			}
+			received = true
+			s.onNewBlock(height)
+
+			if !staleTimer.Stop() {
+				<-staleTimer.C
+			}
+			staleTimer.Reset(s.staleAfter)
+		}
+	}
+}
+
+// onNewBlock records the height of a new block, and calls the handlers.
+func (s *BlockSubscription) onNewBlock(height int64) {
+	s.mu.Lock()
+	s.live = true
+	s.receivedAt = s.now()
+	// A lower height, e.g. after a reconnection to a lagging full node, is
+	// recorded as is: the full node's view is the one the sessions depend on.
+	s.height = height
+	handlers := make([]func(int64), 0, len(s.handlers))
+	for _, handler := range s.handlers {
+		handlers = append(handlers, handler)
+	}
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(height)
+	}
+}
+
+// setLive sets whether the subscription is live.
+func (s *BlockSubscription) setLive(live bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.live = live
+}
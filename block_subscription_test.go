@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestBlockSubscription_Run(t *testing.T) {
+	subscriber := &fakeNewBlockSubscriber{subscriptions: make(chan chan int64)}
+	subscriptionErrs := make(chan error, 1)
+	subscription := NewBlockSubscription(
+		subscriber,
+		WithSubscriptionReconnectPolicy(retry.Constant{}),
+		WithSubscriptionErrorObserver(func(err error) { subscriptionErrs <- err }),
+	)
+	newBlocks := make(chan int64, 1)
+	subscription.OnNewBlock(func(height int64) { newBlocks <- height })
+
+	statusFetcher := &sequenceStatusFetcher{statuses: []ctypes.SyncInfo{{LatestBlockHeight: 5}}}
+	bc, err := NewBlockClient("", WithStatusFetcher(statusFetcher), WithBlockSubscription(subscription))
+	require.NoError(t, err)
+
+	// The status is polled until the subscription is live.
+	height, err := bc.LatestBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(5), height)
+	require.Equal(t, 1, statusFetcher.calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- subscription.Run(ctx) }()
+
+	heights := <-subscriber.subscriptions
+	heights <- 10
+	require.Equal(t, int64(10), <-newBlocks)
+
+	// The pushed height is returned without polling the status.
+	height, err = bc.LatestBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(10), height)
+	require.Equal(t, 1, statusFetcher.calls)
+
+	// A lost subscription falls back to polling, and is reconnected.
+	close(heights)
+	require.Error(t, <-subscriptionErrs)
+	height, err = bc.LatestBlockHeight(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(5), height)
+	require.Equal(t, 2, statusFetcher.calls)
+
+	heights = <-subscriber.subscriptions
+	heights <- 11
+	require.Equal(t, int64(11), <-newBlocks)
+
+	cancel()
+	require.ErrorIs(t, <-runErr, context.Canceled)
+
+	require.ErrorIs(t, NewBlockSubscription(nil).Run(context.Background()), sdkerrors.ErrNotConfigured)
+}
+
+func TestBlockSubscription_Stale(t *testing.T) {
+	subscriber := &fakeNewBlockSubscriber{subscriptions: make(chan chan int64)}
+	subscriptionErrs := make(chan error, 1)
+	subscription := NewBlockSubscription(
+		subscriber,
+		WithSubscriptionStaleAfter(10*time.Millisecond),
+		WithSubscriptionReconnectPolicy(retry.Constant{Interval: time.Hour}),
+		WithSubscriptionErrorObserver(func(err error) { subscriptionErrs <- err }),
+	)
+
+	now := time.Now()
+	subscription.now = func() time.Time { return now }
+	subscription.onNewBlock(7)
+	height, live := subscription.LatestBlockHeight()
+	require.True(t, live)
+	require.Equal(t, int64(7), height)
+
+	// The height is not returned once no block was received for too long.
+	now = now.Add(time.Second)
+	_, live = subscription.LatestBlockHeight()
+	require.False(t, live)
+
+	// A subscription not receiving any block is considered lost.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = subscription.Run(ctx) }()
+
+	<-subscriber.subscriptions
+	require.ErrorContains(t, <-subscriptionErrs, "no new block received")
+}
+
+// fakeNewBlockSubscriber is a NewBlockSubscriber sending the channel of every
+// new subscription to the subscriptions channel, for the test to push heights.
+type fakeNewBlockSubscriber struct {
+	subscriptions chan chan int64
+}
+
+func (f *fakeNewBlockSubscriber) SubscribeNewBlocks(context.Context) (<-chan int64, error) {
+	heights := make(chan int64)
+	f.subscriptions <- heights
+	return heights, nil
+}
@@ -0,0 +1,331 @@
+// Package cache implements an in-memory cache used by the SDK to avoid querying
+// POKT full nodes for the same data multiple times.
+//
+// Besides the usual get/set operations, the cache supports fetching missing or
+// expired entries through a caller-provided fetch function, coalescing concurrent
+// fetches of the same key, and optionally serving a stale entry when a fetch
+// fails because the full node did not respond in time.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultFetchTimeout is the default FetchTimeout of a Cache.
+const defaultFetchTimeout = 30 * time.Second
+
+// Config specifies the behavior of a Cache.
+type Config struct {
+	// TTL is the duration for which a cached entry is considered fresh.
+	// Entries never expire if TTL is zero.
+	TTL time.Duration `yaml:"ttl"`
+
+	// StaleGracePeriod is the duration, after an entry expires, during which the
+	// entry may still be served if fetching a fresh value fails with an error
+	// accepted by ServeStaleOnError.
+	// Stale entries are never served if StaleGracePeriod is zero.
+	StaleGracePeriod time.Duration `yaml:"stale_grace_period"`
+
+	// ServeStaleOnError reports whether a stale entry may be served in place of
+	// the given fetch error.
+	// It defaults to IsDeadlineError if not set.
+	ServeStaleOnError func(error) bool `yaml:"-"`
+
+	// FetchTimeout bounds the calls to the fetch functions, which are detached
+	// from the contexts of the callers so a caller giving up does not fail the
+	// other callers sharing the fetch.
+	// It defaults to 30 seconds if not set.
+	FetchTimeout time.Duration `yaml:"fetch_timeout"`
+}
+
+// Result describes how a value returned by the cache was obtained.
+type Result struct {
+	// StaleServed is true if the returned value is a stale entry, served because
+	// fetching a fresh value failed.
+	StaleServed bool
+	// FetchErr is the error which caused a stale entry to be served.
+	FetchErr error
+}
+
+// HeightScoped is implemented by the cached values which are only valid up to a
+// block height, e.g. sessions, so they can be dropped by InvalidateAtHeight.
+type HeightScoped interface {
+	// ValidUntilHeight returns the last block height at which the value is valid.
+	ValidUntilHeight() int64
+}
+
+// FetchFn fetches the value of a key which is missing from the cache or expired.
+type FetchFn[V any] func(ctx context.Context) (V, error)
+
+// Cache is an in-memory key/value cache, safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	config Config
+	// now returns the current time. It is overridden in tests.
+	now func() time.Time
+
+	mu       sync.RWMutex
+	engine   Engine[K, V]
+	inflight map[K]*call[V]
+	// validUntilHeights holds the last valid height of the HeightScoped values.
+	validUntilHeights map[K]int64
+}
+
+// call is an in-flight fetch of a key, shared by all the concurrent callers
+// requesting the same key.
+type call[V any] struct {
+	done   chan struct{}
+	value  V
+	result Result
+	err    error
+}
+
+// New returns an empty Cache with the given configuration, storing its entries
+// in an in-memory map.
+func New[K comparable, V any](config Config) *Cache[K, V] {
+	return NewWithEngine[K, V](config, NewMapEngine[K, V]())
+}
+
+// NewWithEngine returns a Cache with the given configuration, storing its
+// entries in the given engine.
+func NewWithEngine[K comparable, V any](config Config, engine Engine[K, V]) *Cache[K, V] {
+	if config.ServeStaleOnError == nil {
+		config.ServeStaleOnError = IsDeadlineError
+	}
+	if config.FetchTimeout <= 0 {
+		config.FetchTimeout = defaultFetchTimeout
+	}
+
+	c := &Cache[K, V]{
+		config:            config,
+		now:               time.Now,
+		engine:            engine,
+		inflight:          make(map[K]*call[V]),
+		validUntilHeights: make(map[K]int64),
+	}
+
+	// The engine only evicts entries from Set, which is called with mu held.
+	if evictingEngine, ok := engine.(EvictingEngine[K, V]); ok {
+		evictingEngine.SetEvictionCallback(func(key K) {
+			delete(c.validUntilHeights, key)
+		})
+	}
+
+	return c
+}
+
+// Get returns the fresh cached value of the given key, if any.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.engine.Get(key)
+	if !ok || c.isExpired(e) {
+		var zero V
+		return zero, false
+	}
+
+	return e.Value, true
+}
+
+// Set stores the given value for the given key.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.engine.Set(key, Entry[V]{Value: value, FetchedAt: c.now()})
+	if heightScoped, ok := any(value).(HeightScoped); ok {
+		c.validUntilHeights[key] = heightScoped.ValidUntilHeight()
+	} else {
+		delete(c.validUntilHeights, key)
+	}
+}
+
+// Delete removes the given key from the cache.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.engine.Delete(key)
+	delete(c.validUntilHeights, key)
+}
+
+// InvalidateAtHeight removes, at once, all the entries holding a HeightScoped
+// value whose validity ends at or before the given height, and returns their keys.
+// It can be called on every new block, or to recover from a chain reorg.
+// Values fetched concurrently are stored once their fetch completes.
+func (c *Cache[K, V]) InvalidateAtHeight(height int64) []K {
+	return c.InvalidateFunc(func(_ K, validUntilHeight int64) bool {
+		return validUntilHeight <= height
+	})
+}
+
+// InvalidateFunc removes, at once, all the entries holding a HeightScoped value
+// for which match returns true, and returns their keys.
+// It can be used to invalidate the entries derived from a changed onchain
+// state, e.g. the sessions of an application which undelegated from a gateway.
+// Values fetched concurrently are stored once their fetch completes.
+func (c *Cache[K, V]) InvalidateFunc(match func(key K, validUntilHeight int64) bool) []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var invalidatedKeys []K
+	for key, validUntilHeight := range c.validUntilHeights {
+		if !match(key, validUntilHeight) {
+			continue
+		}
+
+		// The engine may have evicted the entry already.
+		if _, ok := c.engine.Get(key); ok {
+			c.engine.Delete(key)
+			invalidatedKeys = append(invalidatedKeys, key)
+		}
+		delete(c.validUntilHeights, key)
+	}
+
+	return invalidatedKeys
+}
+
+// Len returns the number of entries in the cache, including expired ones.
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.engine.Len()
+}
+
+// GetOrFetch returns the fresh cached value of the given key, or fetches it
+// using fetchFn if the key is missing or expired.
+// See Fetch for details on how values are fetched.
+func (c *Cache[K, V]) GetOrFetch(ctx context.Context, key K, fetchFn FetchFn[V]) (V, Result, error) {
+	if value, ok := c.Get(key); ok {
+		return value, Result{}, nil
+	}
+
+	return c.Fetch(ctx, key, fetchFn)
+}
+
+// Fetch fetches the value of the given key using fetchFn, regardless of any
+// cached value, and stores it in the cache.
+//
+// Concurrent fetches of the same key are coalesced into a single call to fetchFn.
+// The call is detached from the context of the caller which started it, keeping
+// its values but bounded by the FetchTimeout config instead, so the fetch
+// completes, and the value is cached, even if that caller gives up first.
+// If fetchFn fails with an error accepted by the ServeStaleOnError config, and
+// the cached entry expired less than StaleGracePeriod ago, the stale entry is
+// returned instead of the error and the returned Result is marked accordingly.
+// The same applies to a caller whose context is done before the fetch completes.
+func (c *Cache[K, V]) Fetch(ctx context.Context, key K, fetchFn FetchFn[V]) (V, Result, error) {
+	c.mu.Lock()
+	inflightCall, ok := c.inflight[key]
+	if !ok {
+		inflightCall = &call[V]{done: make(chan struct{})}
+		c.inflight[key] = inflightCall
+		go c.runCall(context.WithoutCancel(ctx), key, inflightCall, fetchFn)
+	}
+	c.mu.Unlock()
+
+	return c.wait(ctx, key, inflightCall)
+}
+
+// runCall runs the given in-flight call, and removes it from the in-flight
+// calls once it completes, even if fetchFn panics.
+func (c *Cache[K, V]) runCall(ctx context.Context, key K, inflightCall *call[V], fetchFn FetchFn[V]) {
+	defer close(inflightCall.done)
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			inflightCall.err = fmt.Errorf("cache: fetch of key %v panicked: %v", key, r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.FetchTimeout)
+	defer cancel()
+
+	inflightCall.value, inflightCall.result, inflightCall.err = c.fetch(ctx, key, fetchFn)
+}
+
+// fetch calls fetchFn and updates the cache with the fetched value, falling
+// back to a stale entry if allowed by the cache config.
+func (c *Cache[K, V]) fetch(ctx context.Context, key K, fetchFn FetchFn[V]) (V, Result, error) {
+	value, err := fetchFn(ctx)
+	if err == nil {
+		c.Set(key, value)
+		return value, Result{}, nil
+	}
+
+	if staleValue, ok := c.getStale(key, err); ok {
+		return staleValue, Result{StaleServed: true, FetchErr: err}, nil
+	}
+
+	var zero V
+	return zero, Result{}, err
+}
+
+// wait waits for the given in-flight call of the given key to complete, or for
+// the context to be done, falling back to a stale entry if allowed by the cache
+// config in the latter case.
+func (c *Cache[K, V]) wait(ctx context.Context, key K, inflightCall *call[V]) (V, Result, error) {
+	select {
+	case <-inflightCall.done:
+		return inflightCall.value, inflightCall.result, inflightCall.err
+	case <-ctx.Done():
+		err := ctx.Err()
+		if staleValue, ok := c.getStale(key, err); ok {
+			return staleValue, Result{StaleServed: true, FetchErr: err}, nil
+		}
+
+		var zero V
+		return zero, Result{}, err
+	}
+}
+
+// getStale returns the cached entry of the given key if it may be served in
+// place of the given fetch error.
+func (c *Cache[K, V]) getStale(key K, fetchErr error) (V, bool) {
+	var zero V
+	if c.config.StaleGracePeriod == 0 || !c.config.ServeStaleOnError(fetchErr) {
+		return zero, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.engine.Get(key)
+	if !ok {
+		return zero, false
+	}
+
+	if c.config.TTL > 0 && c.now().After(e.FetchedAt.Add(c.config.TTL+c.config.StaleGracePeriod)) {
+		return zero, false
+	}
+
+	return e.Value, true
+}
+
+// isExpired returns true if the given entry is older than the cache TTL.
+func (c *Cache[K, V]) isExpired(e Entry[V]) bool {
+	return c.config.TTL > 0 && c.now().After(e.FetchedAt.Add(c.config.TTL))
+}
+
+// IsDeadlineError reports whether the given error is caused by an exceeded
+// deadline, either of the context or of a gRPC call to a full node.
+func IsDeadlineError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.DeadlineExceeded
+}
@@ -0,0 +1,376 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_GetOrFetch(t *testing.T) {
+	c := New[string, int](Config{TTL: time.Minute})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	fetchCount := 0
+	fetchFn := func(context.Context) (int, error) {
+		fetchCount++
+		return fetchCount, nil
+	}
+
+	value, result, err := c.GetOrFetch(context.Background(), "key", fetchFn)
+	require.NoError(t, err)
+	require.False(t, result.StaleServed)
+	require.Equal(t, 1, value)
+
+	// The cached value is returned while it is fresh.
+	value, _, err = c.GetOrFetch(context.Background(), "key", fetchFn)
+	require.NoError(t, err)
+	require.Equal(t, 1, value)
+
+	// The value is fetched again once expired.
+	now = now.Add(2 * time.Minute)
+	value, _, err = c.GetOrFetch(context.Background(), "key", fetchFn)
+	require.NoError(t, err)
+	require.Equal(t, 2, value)
+}
+
+func TestCache_StaleFallback(t *testing.T) {
+	tests := []struct {
+		desc              string
+		gracePeriod       time.Duration
+		fetchErr          error
+		elapsed           time.Duration
+		expectStaleServed bool
+	}{
+		{
+			desc:              "stale entry served on deadline error within grace period",
+			gracePeriod:       time.Minute,
+			fetchErr:          context.DeadlineExceeded,
+			elapsed:           90 * time.Second,
+			expectStaleServed: true,
+		},
+		{
+			desc:        "stale entry not served after grace period",
+			gracePeriod: time.Minute,
+			fetchErr:    context.DeadlineExceeded,
+			elapsed:     3 * time.Minute,
+		},
+		{
+			desc:        "stale entry not served on non-deadline error",
+			gracePeriod: time.Minute,
+			fetchErr:    errors.New("not found"),
+			elapsed:     90 * time.Second,
+		},
+		{
+			desc:     "stale entry not served without grace period",
+			fetchErr: context.DeadlineExceeded,
+			elapsed:  90 * time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			c := New[string, int](Config{TTL: time.Minute, StaleGracePeriod: test.gracePeriod})
+			now := time.Now()
+			c.now = func() time.Time { return now }
+			c.Set("key", 42)
+
+			now = now.Add(test.elapsed)
+			value, result, err := c.GetOrFetch(context.Background(), "key", func(context.Context) (int, error) {
+				return 0, test.fetchErr
+			})
+
+			if !test.expectStaleServed {
+				require.ErrorIs(t, err, test.fetchErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, result.StaleServed)
+			require.ErrorIs(t, result.FetchErr, test.fetchErr)
+			require.Equal(t, 42, value)
+		})
+	}
+}
+
+func TestCache_FetchCoalescing(t *testing.T) {
+	c := New[string, int](Config{})
+
+	var fetchCount atomic.Int32
+	release := make(chan struct{})
+	fetchFn := func(context.Context) (int, error) {
+		fetchCount.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const numCallers = 10
+	var wg sync.WaitGroup
+	values := make([]int, numCallers)
+	for i := 0; i < numCallers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], _, _ = c.GetOrFetch(context.Background(), "key", fetchFn)
+		}(i)
+	}
+
+	// Give all the callers a chance to join the in-flight fetch before releasing it.
+	require.Eventually(t, func() bool { return fetchCount.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), fetchCount.Load())
+	for _, value := range values {
+		require.Equal(t, 42, value)
+	}
+}
+
+func TestCache_CustomEngine(t *testing.T) {
+	engine := &singleEntryEngine[string, int]{}
+	c := NewWithEngine[string, int](Config{}, engine)
+
+	c.Set("key1", 1)
+	c.Set("key2", 2)
+
+	// The engine evicted the first key when the second one was set.
+	_, ok := c.Get("key1")
+	require.False(t, ok)
+
+	value, ok := c.Get("key2")
+	require.True(t, ok)
+	require.Equal(t, 2, value)
+	require.Equal(t, 1, c.Len())
+}
+
+func TestCache_InvalidateAtHeight(t *testing.T) {
+	c := New[string, heightScopedValue](Config{})
+	c.Set("ends-at-10", heightScopedValue{validUntilHeight: 10})
+	c.Set("ends-at-20", heightScopedValue{validUntilHeight: 20})
+	c.Set("evicted", heightScopedValue{validUntilHeight: 5})
+	c.Delete("evicted")
+
+	require.Empty(t, c.InvalidateAtHeight(9))
+	require.Equal(t, []string{"ends-at-10"}, c.InvalidateAtHeight(10))
+
+	_, ok := c.Get("ends-at-10")
+	require.False(t, ok)
+	_, ok = c.Get("ends-at-20")
+	require.True(t, ok)
+
+	// Values which are not height-scoped are never invalidated.
+	other := New[string, int](Config{})
+	other.Set("key", 1)
+	require.Empty(t, other.InvalidateAtHeight(100))
+	require.Equal(t, 1, other.Len())
+}
+
+func TestCache_InvalidateFunc(t *testing.T) {
+	c := New[string, heightScopedValue](Config{})
+	c.Set("app1/anvil", heightScopedValue{validUntilHeight: 10})
+	c.Set("app1/eth", heightScopedValue{validUntilHeight: 20})
+	c.Set("app2/anvil", heightScopedValue{validUntilHeight: 10})
+
+	invalidatedKeys := c.InvalidateFunc(func(key string, _ int64) bool {
+		return strings.HasPrefix(key, "app1/")
+	})
+	slices.Sort(invalidatedKeys)
+	require.Equal(t, []string{"app1/anvil", "app1/eth"}, invalidatedKeys)
+	require.Equal(t, 1, c.Len())
+
+	_, ok := c.Get("app2/anvil")
+	require.True(t, ok)
+}
+
+// heightScopedValue is a HeightScoped cached value.
+type heightScopedValue struct {
+	validUntilHeight int64
+}
+
+func (v heightScopedValue) ValidUntilHeight() int64 {
+	return v.validUntilHeight
+}
+
+// singleEntryEngine is an Engine which only keeps the last set entry.
+type singleEntryEngine[K comparable, V any] struct {
+	key   K
+	entry *Entry[V]
+}
+
+func (e *singleEntryEngine[K, V]) Get(key K) (Entry[V], bool) {
+	if e.entry == nil || e.key != key {
+		return Entry[V]{}, false
+	}
+	return *e.entry, true
+}
+
+func (e *singleEntryEngine[K, V]) Set(key K, entry Entry[V]) {
+	e.key, e.entry = key, &entry
+}
+
+func (e *singleEntryEngine[K, V]) Delete(key K) {
+	if e.key == key {
+		e.entry = nil
+	}
+}
+
+func (e *singleEntryEngine[K, V]) Len() int {
+	if e.entry == nil {
+		return 0
+	}
+	return 1
+}
+
+func TestSegmentedLRUEngine_ProtectsHotEntries(t *testing.T) {
+	engine := NewSegmentedLRUEngine[string, int](4, 0.5)
+	c := NewWithEngine[string, int](Config{}, engine)
+
+	// Read the hot keys again, promoting them to the protected segment.
+	for _, key := range []string{"hot1", "hot2"} {
+		c.Set(key, 1)
+		_, ok := c.Get(key)
+		require.True(t, ok)
+	}
+
+	// A scan of one-off keys only evicts other one-off keys.
+	for i := 0; i < 100; i++ {
+		c.Set(fmt.Sprintf("scan%d", i), i)
+	}
+	require.Equal(t, 4, c.Len())
+
+	for _, key := range []string{"hot1", "hot2", "scan98", "scan99"} {
+		_, ok := c.Get(key)
+		require.True(t, ok, key)
+	}
+	_, ok := c.Get("scan97")
+	require.False(t, ok)
+}
+
+func TestSegmentedLRUEngine_EvictionCallback(t *testing.T) {
+	engine := NewSegmentedLRUEngine[string, heightScopedValue](2, 0.5)
+	c := NewWithEngine[string, heightScopedValue](Config{}, engine)
+
+	// The heights of the evicted entries are dropped along with them.
+	for i := range 100 {
+		c.Set(fmt.Sprintf("key%d", i), heightScopedValue{validUntilHeight: int64(i)})
+	}
+	require.Equal(t, 2, c.Len())
+	require.Len(t, c.validUntilHeights, 2)
+
+	require.ElementsMatch(t, []string{"key98", "key99"}, c.InvalidateAtHeight(100))
+	require.Empty(t, c.validUntilHeights)
+}
+
+func TestSegmentedLRUEngine_DemotesProtectedEntries(t *testing.T) {
+	engine := NewSegmentedLRUEngine[string, int](3, 0.5)
+
+	// Only one entry fits in the protected segment.
+	for _, key := range []string{"key1", "key2"} {
+		engine.Set(key, Entry[int]{Value: 1})
+		_, ok := engine.Get(key)
+		require.True(t, ok)
+	}
+
+	// key1 was demoted to the probation segment when key2 was promoted, so it
+	// is evicted first.
+	engine.Set("key3", Entry[int]{Value: 3})
+	engine.Set("key4", Entry[int]{Value: 4})
+	require.Equal(t, 3, engine.Len())
+
+	_, ok := engine.Get("key1")
+	require.False(t, ok)
+	_, ok = engine.Get("key2")
+	require.True(t, ok)
+
+	engine.Delete("key2")
+	require.Equal(t, 2, engine.Len())
+}
+
+func TestCache_FetchDetachedFromCaller(t *testing.T) {
+	c := New[string, int](Config{})
+
+	var (
+		fetchCount  atomic.Int32
+		startedOnce sync.Once
+	)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetchFn := func(ctx context.Context) (int, error) {
+		fetchCount.Add(1)
+		startedOnce.Do(func() { close(started) })
+		select {
+		case <-release:
+			return 42, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	// The caller starting the fetch gives up before it completes.
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, _, err := c.Fetch(ctx, "key", fetchFn)
+		firstErr <- err
+	}()
+	<-started
+
+	secondValue := make(chan int)
+	go func() {
+		value, _, _ := c.Fetch(context.Background(), "key", fetchFn)
+		secondValue <- value
+	}()
+
+	cancel()
+	require.ErrorIs(t, <-firstErr, context.Canceled)
+
+	// Give the second caller a chance to join the in-flight fetch before releasing it.
+	time.Sleep(10 * time.Millisecond)
+	c.mu.RLock()
+	require.Len(t, c.inflight, 1)
+	c.mu.RUnlock()
+
+	// The other callers still get the fetched value, which is cached.
+	close(release)
+	require.Equal(t, 42, <-secondValue)
+	require.Equal(t, int32(1), fetchCount.Load())
+	value, ok := c.Get("key")
+	require.True(t, ok)
+	require.Equal(t, 42, value)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	require.Empty(t, c.inflight)
+}
+
+func TestCache_FetchTimeoutAndPanic(t *testing.T) {
+	c := New[string, int](Config{FetchTimeout: 10 * time.Millisecond})
+
+	// The fetch is bounded by the FetchTimeout config.
+	_, _, err := c.Fetch(context.Background(), "key", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// A panicking fetch fails its callers, and does not leak its in-flight call.
+	_, _, err = c.Fetch(context.Background(), "key", func(context.Context) (int, error) {
+		panic("boom")
+	})
+	require.ErrorContains(t, err, "panicked: boom")
+
+	value, _, err := c.Fetch(context.Background(), "key", func(context.Context) (int, error) {
+		return 42, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 42, value)
+}
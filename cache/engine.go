@@ -0,0 +1,67 @@
+package cache
+
+import "time"
+
+// Entry is a cached value along with the time at which it was fetched.
+type Entry[V any] struct {
+	Value     V
+	FetchedAt time.Time
+}
+
+// Engine stores the entries of a Cache.
+//
+// The Cache handles expiry, stale fallback and fetch coalescing on top of the
+// engine, so an engine only needs to store entries. This allows swapping the
+// default in-memory map for another storage, e.g. a size-bounded one, without
+// changing the Cache's public API.
+//
+// The Cache never calls Set or Delete concurrently with any other method, but
+// may call Get and Len concurrently.
+// An engine may evict entries at any time, e.g. to bound its size.
+type Engine[K comparable, V any] interface {
+	Get(key K) (Entry[V], bool)
+	Set(key K, entry Entry[V])
+	Delete(key K)
+	Len() int
+}
+
+// EvictingEngine is implemented by the Engines evicting entries on their own,
+// e.g. to bound their size, so the Cache can drop the metadata it keeps about
+// the evicted keys.
+type EvictingEngine[K comparable, V any] interface {
+	Engine[K, V]
+	// SetEvictionCallback sets the function called with the key of every entry
+	// evicted by the engine. It is only called from Set, before Set returns.
+	SetEvictionCallback(onEvict func(key K))
+}
+
+// MapEngine is the default Engine, storing entries in an unbounded in-memory map.
+type MapEngine[K comparable, V any] struct {
+	entries map[K]Entry[V]
+}
+
+// NewMapEngine returns an empty MapEngine.
+func NewMapEngine[K comparable, V any]() *MapEngine[K, V] {
+	return &MapEngine[K, V]{entries: make(map[K]Entry[V])}
+}
+
+// Get returns the entry of the given key, if any.
+func (e *MapEngine[K, V]) Get(key K) (Entry[V], bool) {
+	entry, ok := e.entries[key]
+	return entry, ok
+}
+
+// Set stores the given entry for the given key.
+func (e *MapEngine[K, V]) Set(key K, entry Entry[V]) {
+	e.entries[key] = entry
+}
+
+// Delete removes the entry of the given key.
+func (e *MapEngine[K, V]) Delete(key K) {
+	delete(e.entries, key)
+}
+
+// Len returns the number of stored entries.
+func (e *MapEngine[K, V]) Len() int {
+	return len(e.entries)
+}
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// SegmentedLRUEngine is a size-bounded Engine which protects frequently used
+// entries from being evicted by bursts of one-off keys.
+//
+// Entries are split into two LRU segments: new entries are admitted to the
+// probation segment, and promoted to the protected segment when read again.
+// Entries are evicted from the probation segment first, so a scan of many
+// one-off keys only evicts other one-off keys, and not the hot entries of the
+// protected segment. When the protected segment is full, its least recently
+// used entry is demoted back to the probation segment.
+//
+// A SegmentedLRUEngine is safe for concurrent use.
+type SegmentedLRUEngine[K comparable, V any] struct {
+	mu                sync.Mutex
+	capacity          int
+	protectedCapacity int
+	probation         *list.List
+	protected         *list.List
+	elements          map[K]*list.Element
+	onEvict           func(key K)
+}
+
+// slruItem is an entry stored in one of the segments of a SegmentedLRUEngine.
+type slruItem[K comparable, V any] struct {
+	key       K
+	entry     Entry[V]
+	protected bool
+}
+
+// NewSegmentedLRUEngine returns an empty SegmentedLRUEngine holding at most
+// capacity entries, of which at most protectedRatio can be protected,
+// e.g. 0.8 for 80% of the capacity.
+// The capacity is at least 1, and the probation segment always has room for
+// at least one entry.
+func NewSegmentedLRUEngine[K comparable, V any](capacity int, protectedRatio float64) *SegmentedLRUEngine[K, V] {
+	capacity = max(capacity, 1)
+	protectedCapacity := min(max(int(float64(capacity)*protectedRatio), 0), capacity-1)
+
+	return &SegmentedLRUEngine[K, V]{
+		capacity:          capacity,
+		protectedCapacity: protectedCapacity,
+		probation:         list.New(),
+		protected:         list.New(),
+		elements:          make(map[K]*list.Element),
+	}
+}
+
+// Get returns the entry of the given key, if any, promoting it to the protected segment.
+func (e *SegmentedLRUEngine[K, V]) Get(key K) (Entry[V], bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	element, ok := e.elements[key]
+	if !ok {
+		return Entry[V]{}, false
+	}
+
+	item := element.Value.(*slruItem[K, V])
+	e.touch(element)
+	return item.entry, true
+}
+
+// Set stores the given entry for the given key, evicting the least recently
+// used entry if the engine is full.
+// New keys are admitted to the probation segment; existing keys keep their segment.
+func (e *SegmentedLRUEngine[K, V]) Set(key K, entry Entry[V]) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if element, ok := e.elements[key]; ok {
+		item := element.Value.(*slruItem[K, V])
+		item.entry = entry
+		e.segment(item).MoveToFront(element)
+		return
+	}
+
+	if len(e.elements) >= e.capacity {
+		e.evict()
+	}
+
+	e.elements[key] = e.probation.PushFront(&slruItem[K, V]{key: key, entry: entry})
+}
+
+// Delete removes the entry of the given key.
+func (e *SegmentedLRUEngine[K, V]) Delete(key K) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	element, ok := e.elements[key]
+	if !ok {
+		return
+	}
+
+	e.segment(element.Value.(*slruItem[K, V])).Remove(element)
+	delete(e.elements, key)
+}
+
+// SetEvictionCallback sets the function called with the key of every entry
+// evicted to make room for a new one.
+func (e *SegmentedLRUEngine[K, V]) SetEvictionCallback(onEvict func(key K)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.onEvict = onEvict
+}
+
+// Len returns the number of stored entries.
+func (e *SegmentedLRUEngine[K, V]) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return len(e.elements)
+}
+
+// touch marks the entry of the given element as recently used, promoting it to
+// the protected segment if it is in the probation segment.
+func (e *SegmentedLRUEngine[K, V]) touch(element *list.Element) {
+	item := element.Value.(*slruItem[K, V])
+	if item.protected {
+		e.protected.MoveToFront(element)
+		return
+	}
+
+	if e.protectedCapacity == 0 {
+		e.probation.MoveToFront(element)
+		return
+	}
+
+	e.probation.Remove(element)
+	item.protected = true
+	e.elements[item.key] = e.protected.PushFront(item)
+
+	// Demote the least recently used protected entry if the segment overflows.
+	if e.protected.Len() > e.protectedCapacity {
+		demoted := e.protected.Remove(e.protected.Back()).(*slruItem[K, V])
+		demoted.protected = false
+		e.elements[demoted.key] = e.probation.PushFront(demoted)
+	}
+}
+
+// evict removes the least recently used entry of the probation segment, or of
+// the protected segment if the probation segment is empty.
+func (e *SegmentedLRUEngine[K, V]) evict() {
+	victim := e.probation.Back()
+	if victim == nil {
+		victim = e.protected.Back()
+	}
+	if victim == nil {
+		return
+	}
+
+	item := victim.Value.(*slruItem[K, V])
+	e.segment(item).Remove(victim)
+	delete(e.elements, item.key)
+	if e.onEvict != nil {
+		e.onEvict(item.key)
+	}
+}
+
+// segment returns the segment holding the given item.
+func (e *SegmentedLRUEngine[K, V]) segment(item *slruItem[K, V]) *list.List {
+	if item.protected {
+		return e.protected
+	}
+	return e.probation
+}
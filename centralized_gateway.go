@@ -0,0 +1,200 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// AppKeyStore holds the private keys of the applications owned by a gateway
+// running in centralized mode, i.e. signing the relay requests with the keys
+// of its own applications rather than relying on the applications' delegation
+// to the gateway.
+//
+// It implements RelaySignerGetter, so it can be set as the RelaySigners of a
+// GatewayClient. It is safe for concurrent use.
+type AppKeyStore struct {
+	mu      sync.RWMutex
+	signers map[string]*Signer
+}
+
+// NewAppKeyStore returns an AppKeyStore holding the given hex-encoded
+// application private keys.
+func NewAppKeyStore(privateKeyHexes ...string) (*AppKeyStore, error) {
+	store := &AppKeyStore{signers: make(map[string]*Signer, len(privateKeyHexes))}
+	for _, privateKeyHex := range privateKeyHexes {
+		if _, err := store.Add(privateKeyHex); err != nil {
+			return nil, fmt.Errorf("NewAppKeyStore: %w", err)
+		}
+	}
+
+	return store, nil
+}
+
+// Add adds the given hex-encoded application private key to the store, and
+// returns the address of its application.
+func (s *AppKeyStore) Add(privateKeyHex string) (string, error) {
+	appAddress, err := AddressFromPrivateKeyHex(privateKeyHex)
+	if err != nil {
+		return "", fmt.Errorf("Add: error getting the application address: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.signers[appAddress]; ok {
+		return "", fmt.Errorf("Add: duplicate key of application %s", appAddress)
+	}
+	s.signers[appAddress] = &Signer{PrivateKeyHex: privateKeyHex}
+
+	return appAddress, nil
+}
+
+// Addresses returns the addresses, in lexical order, of the applications whose
+// keys are held by the store.
+func (s *AppKeyStore) Addresses() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(s.signers))
+}
+
+// GetRelaySigner returns the Signer of the given application's relay requests,
+// using the application's own key.
+func (s *AppKeyStore) GetRelaySigner(_ context.Context, appAddress string) (RelayRequestSigner, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	signer, ok := s.signers[appAddress]
+	if !ok {
+		return nil, fmt.Errorf("GetRelaySigner: no key for application %s", appAddress)
+	}
+	return signer, nil
+}
+
+// CentralizedGatewayClient relays on behalf of the applications owned by the
+// gateway, signing each relay request with the key of its application.
+//
+// The application of each relay is selected among the owned applications
+// staked for the relay's service, as found by the latest call to
+// LoadApplications.
+// It is safe for concurrent use if the GatewayClient's components are.
+type CentralizedGatewayClient struct {
+	gatewayClient GatewayClient
+	appKeys       *AppKeyStore
+	appClient     ApplicationQuerier
+
+	mu sync.RWMutex
+	// appsByService holds the addresses of the owned applications, by the id
+	// of the services they are staked for.
+	appsByService map[string][]string
+}
+
+// NewCentralizedGatewayClient returns a CentralizedGatewayClient relaying
+// through a copy of the given GatewayClient, using the keys of the given
+// AppKeyStore, and validating the owned applications using the given
+// ApplicationQuerier, e.g. an ApplicationClient.
+// The GatewayClient's Signer is not used.
+func NewCentralizedGatewayClient(
+	gatewayClient GatewayClient,
+	appKeys *AppKeyStore,
+	appClient ApplicationQuerier,
+) (*CentralizedGatewayClient, error) {
+	if appKeys == nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "NewCentralizedGatewayClient: AppKeyStore not set")
+	}
+	if appClient == nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "NewCentralizedGatewayClient: ApplicationQuerier not set")
+	}
+
+	gatewayClient.RelaySigners = appKeys
+	return &CentralizedGatewayClient{
+		gatewayClient: gatewayClient,
+		appKeys:       appKeys,
+		appClient:     appClient,
+		appsByService: make(map[string][]string),
+	}, nil
+}
+
+// LoadApplications fetches the owned applications, and records the services
+// each of them is staked for.
+// The applications which can not be fetched, e.g. which are not staked, or
+// which are unbonding, are not used to relay, and are reported in the returned
+// error.
+func (c *CentralizedGatewayClient) LoadApplications(ctx context.Context) error {
+	appsByService := make(map[string][]string)
+	var errs []error
+	for _, appAddress := range c.appKeys.Addresses() {
+		app, err := getActiveApplication(ctx, c.appClient, appAddress)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("application %s: %w", appAddress, err))
+			continue
+		}
+
+		for _, serviceConfig := range app.ServiceConfigs {
+			appsByService[serviceConfig.ServiceId] = append(appsByService[serviceConfig.ServiceId], appAddress)
+		}
+	}
+
+	c.mu.Lock()
+	c.appsByService = appsByService
+	c.mu.Unlock()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("LoadApplications: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ApplicationsForService returns the addresses, in lexical order, of the owned
+// applications staked for the given service.
+func (c *CentralizedGatewayClient) ApplicationsForService(serviceId string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.appsByService[serviceId])
+}
+
+// Relay relays the given serialized POKTHTTPRequest to a supplier of the given
+// service, on behalf of one of the owned applications staked for the service,
+// selected at random.
+func (c *CentralizedGatewayClient) Relay(
+	ctx context.Context,
+	serviceId string,
+	requestBz []byte,
+) (*types.POKTHTTPResponse, error) {
+	appAddresses := c.ApplicationsForService(serviceId)
+	if len(appAddresses) == 0 {
+		return nil, fmt.Errorf("Relay: no owned application staked for service %s", serviceId)
+	}
+
+	appAddress := appAddresses[rand.IntN(len(appAddresses))]
+	return c.gatewayClient.Relay(ctx, appAddress, serviceId, requestBz)
+}
+
+// RelayForApplication relays the given serialized POKTHTTPRequest to a
+// supplier of the given service, on behalf of the given owned application,
+// which must be staked for the service.
+func (c *CentralizedGatewayClient) RelayForApplication(
+	ctx context.Context,
+	appAddress string,
+	serviceId string,
+	requestBz []byte,
+) (*types.POKTHTTPResponse, error) {
+	if !slices.Contains(c.ApplicationsForService(serviceId), appAddress) {
+		return nil, fmt.Errorf(
+			"RelayForApplication: application %s is not an owned application staked for service %s",
+			appAddress,
+			serviceId,
+		)
+	}
+
+	return c.gatewayClient.Relay(ctx, appAddress, serviceId, requestBz)
+}
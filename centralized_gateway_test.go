@@ -0,0 +1,79 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestAppKeyStore(t *testing.T) {
+	keyHex1, address1 := newTestKey(t)
+	keyHex2, address2 := newTestKey(t)
+
+	store, err := NewAppKeyStore(keyHex1, keyHex2)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{address1, address2}, store.Addresses())
+
+	signer, err := store.GetRelaySigner(context.Background(), address2)
+	require.NoError(t, err)
+	require.Equal(t, &Signer{PrivateKeyHex: keyHex2}, signer)
+
+	_, err = store.GetRelaySigner(context.Background(), "pokt1unknown")
+	require.Error(t, err)
+
+	_, err = store.Add(keyHex1)
+	require.ErrorContains(t, err, "duplicate")
+
+	_, err = NewAppKeyStore("not hex")
+	require.Error(t, err)
+}
+
+func TestCentralizedGatewayClient(t *testing.T) {
+	anvilKeyHex, anvilApp := newTestKey(t)
+	unbondingKeyHex, unbondingApp := newTestKey(t)
+	unstakedKeyHex, unstakedApp := newTestKey(t)
+
+	appKeys, err := NewAppKeyStore(anvilKeyHex, unbondingKeyHex, unstakedKeyHex)
+	require.NoError(t, err)
+
+	appClient := &ApplicationClient{
+		QueryClient: &fakeAppQueryClient{apps: map[string]apptypes.Application{
+			anvilApp: {
+				Address:        anvilApp,
+				ServiceConfigs: []*sharedtypes.ApplicationServiceConfig{{ServiceId: "anvil"}},
+			},
+			unbondingApp: {
+				Address:                 unbondingApp,
+				ServiceConfigs:          []*sharedtypes.ApplicationServiceConfig{{ServiceId: "anvil"}},
+				UnstakeSessionEndHeight: 10,
+			},
+		}},
+	}
+
+	client, err := NewCentralizedGatewayClient(GatewayClient{}, appKeys, appClient)
+	require.NoError(t, err)
+
+	// The unstaked and unbonding applications are reported, and not used.
+	err = client.LoadApplications(context.Background())
+	require.ErrorContains(t, err, unstakedApp)
+	require.ErrorIs(t, err, ErrApplicationUnbonding)
+	require.Equal(t, []string{anvilApp}, client.ApplicationsForService("anvil"))
+
+	_, err = client.Relay(context.Background(), "ethereum", []byte("request"))
+	require.ErrorContains(t, err, "no owned application staked for service ethereum")
+
+	_, err = client.RelayForApplication(context.Background(), unbondingApp, "anvil", []byte("request"))
+	require.ErrorContains(t, err, "is not an owned application staked for service anvil")
+
+	// The relays of the staked applications go through the GatewayClient.
+	_, err = client.Relay(context.Background(), "anvil", []byte("request"))
+	require.ErrorIs(t, err, sdkerrors.ErrNotConfigured)
+
+	_, err = NewCentralizedGatewayClient(GatewayClient{}, nil, appClient)
+	require.ErrorIs(t, err, sdkerrors.ErrNotConfigured)
+}
@@ -0,0 +1,208 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/p2p"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// defaultReorgPollInterval is the interval at which the ReorgDetector polls the
+// full node's status if no poll interval is specified.
+const defaultReorgPollInterval = time.Second
+
+// ReorgReason tells how a chain reorg was detected.
+type ReorgReason string
+
+const (
+	// ReorgHeightRegression is reported when the latest block height decreases.
+	ReorgHeightRegression ReorgReason = "height_regression"
+	// ReorgBlockHashMismatch is reported when the hash of the latest block
+	// changes while its height does not.
+	ReorgBlockHashMismatch ReorgReason = "block_hash_mismatch"
+	// ReorgAppHashMismatch is reported when the app hash of the latest block
+	// changes while its height does not.
+	ReorgAppHashMismatch ReorgReason = "app_hash_mismatch"
+	// ReorgChainReset is reported when the hash of the earliest block changes
+	// while its height does not, i.e. the chain was restarted from a new genesis.
+	ReorgChainReset ReorgReason = "chain_reset"
+)
+
+// ReorgRecoveryFunc recovers a component from the given chain reorg, e.g. by
+// dropping the data derived from the abandoned chain.
+// SessionCache.RecoverFromReorg is a ReorgRecoveryFunc.
+type ReorgRecoveryFunc func(ctx context.Context, reorg ChainReorgEvent) error
+
+// ReorgDetectorOption is a functional option used to configure a ReorgDetector.
+type ReorgDetectorOption func(*ReorgDetector)
+
+// WithReorgPollInterval sets the interval at which Run polls the full node's
+// status. It defaults to one second.
+func WithReorgPollInterval(pollInterval time.Duration) ReorgDetectorOption {
+	return func(d *ReorgDetector) {
+		d.pollInterval = pollInterval
+	}
+}
+
+// WithReorgRecovery adds the functions called, in order, when a reorg is detected.
+func WithReorgRecovery(recoveryFns ...ReorgRecoveryFunc) ReorgDetectorOption {
+	return func(d *ReorgDetector) {
+		d.recoveryFns = append(d.recoveryFns, recoveryFns...)
+	}
+}
+
+// WithReorgEventBus sets the EventBus on which a ChainReorgEvent is published
+// for every detected reorg, e.g. to log it.
+func WithReorgEventBus(eventBus *EventBus) ReorgDetectorOption {
+	return func(d *ReorgDetector) {
+		d.eventBus = eventBus
+	}
+}
+
+// ReorgDetector detects chain reorgs and resets, which happen regularly on
+// LocalNet and testnets, by comparing the successive statuses of the full node,
+// and triggers the recovery of the components holding height-derived data, e.g.
+// the SessionCache.
+//
+// A reorg is detected when the latest block height decreases, when the hash or
+// the app hash of the latest block changes without the height changing, or when
+// the earliest block changes without its height changing.
+// Each status is compared with the previous status of the same full node, as
+// identified by its node ID, so a BlockClient load balancing its requests over
+// several full nodes does not report the lag of one node over another as a reorg.
+// It is safe for concurrent use.
+type ReorgDetector struct {
+	blockClient  *BlockClient
+	pollInterval time.Duration
+	recoveryFns  []ReorgRecoveryFunc
+	eventBus     *EventBus
+
+	// mu protects last, the latest observed sync info of each full node, by node ID.
+	mu   sync.Mutex
+	last map[p2p.ID]ctypes.SyncInfo
+	// recoveryMu serializes the recoveries from the detected reorgs.
+	recoveryMu sync.Mutex
+}
+
+// NewReorgDetector returns a ReorgDetector polling the full node's status using
+// the given BlockClient, configured using the given options.
+func NewReorgDetector(blockClient *BlockClient, opts ...ReorgDetectorOption) *ReorgDetector {
+	d := &ReorgDetector{
+		blockClient:  blockClient,
+		pollInterval: defaultReorgPollInterval,
+		last:         make(map[p2p.ID]ctypes.SyncInfo),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Check fetches the full node's status and compares it with the previously
+// observed status of the same node. If a reorg is detected, it publishes a
+// ChainReorgEvent, runs the recovery functions, and returns the event along
+// with true.
+// The recovery functions are run without blocking the concurrent checks, one
+// reorg at a time.
+// The returned error aggregates the errors of the recovery functions, if any.
+func (d *ReorgDetector) Check(ctx context.Context) (ChainReorgEvent, bool, error) {
+	if d.blockClient == nil || d.blockClient.PoktNodeStatusFetcher == nil {
+		return ChainReorgEvent{}, false, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Check: BlockClient not set")
+	}
+
+	nodeStatus, err := d.blockClient.status(ctx)
+	if err != nil {
+		return ChainReorgEvent{}, false, fmt.Errorf("Check: error fetching the full node status: %w", err)
+	}
+
+	syncInfo := nodeStatus.SyncInfo
+	nodeID := nodeStatus.NodeInfo.ID()
+	d.mu.Lock()
+	previous, ok := d.last[nodeID]
+	d.last[nodeID] = syncInfo
+	d.mu.Unlock()
+	if !ok {
+		return ChainReorgEvent{}, false, nil
+	}
+
+	reason, detected := detectReorg(previous, syncInfo)
+	if !detected {
+		return ChainReorgEvent{}, false, nil
+	}
+
+	reorg := ChainReorgEvent{
+		PreviousHeight: previous.LatestBlockHeight,
+		Height:         syncInfo.LatestBlockHeight,
+		Reason:         reason,
+	}
+	d.eventBus.Publish(reorg)
+
+	d.recoveryMu.Lock()
+	defer d.recoveryMu.Unlock()
+
+	var errs []error
+	for _, recoveryFn := range d.recoveryFns {
+		if err := recoveryFn(ctx, reorg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return reorg, true, fmt.Errorf("Check: error recovering from the chain reorg: %w", errors.Join(errs...))
+	}
+
+	return reorg, true, nil
+}
+
+// Run checks the full node's status at the configured poll interval, until the
+// given context is done.
+// Errors fetching the status or recovering from a reorg are ignored: the status
+// is checked again on the next poll.
+func (d *ReorgDetector) Run(ctx context.Context) error {
+	if d.blockClient == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Run: BlockClient not set")
+	}
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		_, _, _ = d.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// detectReorg compares the given successive sync infos of a full node, and
+// returns the reason of the reorg they reveal, if any.
+func detectReorg(previous, current ctypes.SyncInfo) (ReorgReason, bool) {
+	switch {
+	case current.LatestBlockHeight < previous.LatestBlockHeight:
+		return ReorgHeightRegression, true
+	case current.LatestBlockHeight == previous.LatestBlockHeight &&
+		!bytes.Equal(current.LatestBlockHash, previous.LatestBlockHash):
+		return ReorgBlockHashMismatch, true
+	case current.LatestBlockHeight == previous.LatestBlockHeight &&
+		!bytes.Equal(current.LatestAppHash, previous.LatestAppHash):
+		return ReorgAppHashMismatch, true
+	// The earliest block of a pruning node moves forward: only a change of the
+	// earliest block at the same height reveals a new chain.
+	case current.EarliestBlockHeight == previous.EarliestBlockHeight &&
+		len(previous.EarliestBlockHash) > 0 &&
+		!bytes.Equal(current.EarliestBlockHash, previous.EarliestBlockHash):
+		return ReorgChainReset, true
+	default:
+		return "", false
+	}
+}
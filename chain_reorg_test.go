@@ -0,0 +1,206 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cometbft/cometbft/p2p"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestDetectReorg(t *testing.T) {
+	base := ctypes.SyncInfo{
+		LatestBlockHash:     []byte("block10"),
+		LatestAppHash:       []byte("app10"),
+		LatestBlockHeight:   10,
+		EarliestBlockHash:   []byte("genesis"),
+		EarliestBlockHeight: 1,
+	}
+
+	tests := []struct {
+		name           string
+		update         func(*ctypes.SyncInfo)
+		expectedReason ReorgReason
+		expectedReorg  bool
+	}{
+		{
+			name:   "same status",
+			update: func(*ctypes.SyncInfo) {},
+		},
+		{
+			name: "new block",
+			update: func(s *ctypes.SyncInfo) {
+				s.LatestBlockHeight, s.LatestBlockHash, s.LatestAppHash = 11, []byte("block11"), []byte("app11")
+			},
+		},
+		{
+			name: "pruned earliest block",
+			update: func(s *ctypes.SyncInfo) {
+				s.EarliestBlockHeight, s.EarliestBlockHash = 5, []byte("block5")
+			},
+		},
+		{
+			name:           "height regression",
+			update:         func(s *ctypes.SyncInfo) { s.LatestBlockHeight = 8 },
+			expectedReason: ReorgHeightRegression,
+			expectedReorg:  true,
+		},
+		{
+			name:           "block hash mismatch",
+			update:         func(s *ctypes.SyncInfo) { s.LatestBlockHash = []byte("other") },
+			expectedReason: ReorgBlockHashMismatch,
+			expectedReorg:  true,
+		},
+		{
+			name:           "app hash mismatch",
+			update:         func(s *ctypes.SyncInfo) { s.LatestAppHash = []byte("other") },
+			expectedReason: ReorgAppHashMismatch,
+			expectedReorg:  true,
+		},
+		{
+			name: "chain reset",
+			update: func(s *ctypes.SyncInfo) {
+				s.LatestBlockHeight, s.EarliestBlockHash = 12, []byte("new genesis")
+			},
+			expectedReason: ReorgChainReset,
+			expectedReorg:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			current := base
+			test.update(&current)
+
+			reason, reorg := detectReorg(base, current)
+			require.Equal(t, test.expectedReorg, reorg)
+			require.Equal(t, test.expectedReason, reason)
+		})
+	}
+}
+
+func TestReorgDetector_Check(t *testing.T) {
+	statusFetcher := &sequenceStatusFetcher{statuses: []ctypes.SyncInfo{
+		{LatestBlockHeight: 10, LatestBlockHash: []byte("block10")},
+		{LatestBlockHeight: 11, LatestBlockHash: []byte("block11")},
+		{LatestBlockHeight: 3, LatestBlockHash: []byte("block3")},
+	}}
+
+	bus := NewEventBus()
+	var published []Event
+	bus.Subscribe(func(event Event) { published = append(published, event) }, EventChainReorg)
+
+	var recovered []ChainReorgEvent
+	recoveryErr := errors.New("recovery error")
+	detector := NewReorgDetector(
+		&BlockClient{PoktNodeStatusFetcher: statusFetcher},
+		WithReorgEventBus(bus),
+		WithReorgRecovery(
+			func(_ context.Context, reorg ChainReorgEvent) error {
+				recovered = append(recovered, reorg)
+				return nil
+			},
+			func(context.Context, ChainReorgEvent) error { return recoveryErr },
+		),
+	)
+
+	ctx := context.Background()
+	// The first status is only recorded.
+	_, reorg, err := detector.Check(ctx)
+	require.NoError(t, err)
+	require.False(t, reorg)
+
+	_, reorg, err = detector.Check(ctx)
+	require.NoError(t, err)
+	require.False(t, reorg)
+	require.Empty(t, recovered)
+
+	// All the recovery functions are run, even if one of them fails.
+	event, reorg, err := detector.Check(ctx)
+	require.ErrorIs(t, err, recoveryErr)
+	require.True(t, reorg)
+	expectedEvent := ChainReorgEvent{PreviousHeight: 11, Height: 3, Reason: ReorgHeightRegression}
+	require.Equal(t, expectedEvent, event)
+	require.Equal(t, []ChainReorgEvent{expectedEvent}, recovered)
+	require.Equal(t, []Event{expectedEvent}, published)
+
+	_, _, err = NewReorgDetector(nil).Check(ctx)
+	require.ErrorIs(t, err, sdkerrors.ErrNotConfigured)
+}
+
+func TestReorgDetector_Check_MultipleNodes(t *testing.T) {
+	// The statuses are returned by two full nodes behind a load balancer, one
+	// of them lagging behind the other.
+	statusFetcher := &sequenceStatusFetcher{
+		statuses: []ctypes.SyncInfo{
+			{LatestBlockHeight: 10, LatestBlockHash: []byte("block10")},
+			{LatestBlockHeight: 8, LatestBlockHash: []byte("block8")},
+			{LatestBlockHeight: 11, LatestBlockHash: []byte("block11")},
+			{LatestBlockHeight: 9, LatestBlockHash: []byte("block9")},
+			{LatestBlockHeight: 3, LatestBlockHash: []byte("block3")},
+		},
+		nodeIDs: []p2p.ID{"node1", "node2", "node1", "node2", "node2"},
+	}
+	detector := NewReorgDetector(&BlockClient{PoktNodeStatusFetcher: statusFetcher})
+
+	ctx := context.Background()
+	for range 4 {
+		_, reorg, err := detector.Check(ctx)
+		require.NoError(t, err)
+		require.False(t, reorg)
+	}
+
+	// A height regression of the same node is a reorg.
+	event, reorg, err := detector.Check(ctx)
+	require.NoError(t, err)
+	require.True(t, reorg)
+	require.Equal(t, ChainReorgEvent{PreviousHeight: 9, Height: 3, Reason: ReorgHeightRegression}, event)
+}
+
+func TestSessionCache_RecoverFromReorg(t *testing.T) {
+	bus := NewEventBus()
+	var evicted []Event
+	bus.Subscribe(func(event Event) { evicted = append(evicted, event) }, EventCacheEvicted)
+
+	sc := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: &heightSessionFetcher{numBlocksPerSession: 4}},
+		WithEventBus(bus),
+	)
+
+	ctx := context.Background()
+	_, err := sc.GetSession(ctx, "app1", "svc1", 10)
+	require.NoError(t, err)
+
+	// The session cached before the reorg is fetched again at the reorg height.
+	require.NoError(t, sc.RecoverFromReorg(ctx, ChainReorgEvent{PreviousHeight: 10, Height: 2}))
+	require.Equal(t, []Event{CacheEvictedEvent{Key: SessionKey{AppAddress: "app1", ServiceId: "svc1"}}}, evicted)
+
+	sessionInfo, err := sc.GetSession(ctx, "app1", "svc1", 2)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceCache, sessionInfo.Source)
+	require.Equal(t, int64(0), sessionInfo.GetHeader().GetSessionStartBlockHeight())
+}
+
+// sequenceStatusFetcher is a PoktNodeStatusFetcher returning the given sync
+// infos in order, then repeating the last one.
+// If set, nodeIDs holds the ID of the node returning each sync info.
+type sequenceStatusFetcher struct {
+	statuses []ctypes.SyncInfo
+	nodeIDs  []p2p.ID
+	calls    int
+}
+
+func (f *sequenceStatusFetcher) Status(context.Context) (*ctypes.ResultStatus, error) {
+	i := min(f.calls, len(f.statuses)-1)
+	f.calls++
+
+	status := &ctypes.ResultStatus{SyncInfo: f.statuses[i]}
+	if len(f.nodeIDs) > 0 {
+		status.NodeInfo.DefaultNodeID = f.nodeIDs[i]
+	}
+	return status, nil
+}
@@ -0,0 +1,42 @@
+// Command drybench measures the maximum sustainable relay sign+validate
+// throughput on the current machine, and prints the ops/sec and the P99
+// latencies, so operators can size their gateway instances before launch.
+//
+// The signing key is read from the DRYBENCH_PRIVATE_KEY_HEX environment
+// variable, e.g. to bench the gateway's key, and generated if not set.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/pokt-network/shannon-sdk/drybench"
+)
+
+func main() {
+	var config drybench.Config
+	flag.IntVar(&config.RingSize, "ring-size", 2, "number of members of the application's ring, i.e. the application and its gateways")
+	flag.IntVar(&config.PayloadSize, "payload-size", 1024, "size, in bytes, of the relay request and response payloads")
+	flag.IntVar(&config.Concurrency, "concurrency", 0, "number of goroutines processing relays, defaults to GOMAXPROCS")
+	flag.DurationVar(&config.Duration, "duration", 0, "duration of the measurement, defaults to 10s")
+	flag.Parse()
+	config.PrivateKeyHex = os.Getenv("DRYBENCH_PRIVATE_KEY_HEX")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := drybench.Run(ctx, config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println(report)
+	if err := report.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
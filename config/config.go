@@ -0,0 +1,253 @@
+// Package config decodes the YAML configuration of the SDK's components, e.g.
+// a cache.Config or a TransportConfig.
+//
+// By default, as with yaml.Unmarshal, unknown fields are silently ignored.
+// The strict mode, enabled using WithStrictFields, rejects them instead, and
+// suggests the closest known field of each unknown one, so typo'd fields, e.g.
+// "stale_grace_perod", fail on startup instead of silently keeping their default.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// ErrUnknownField is matched by the UnknownFieldErrors of the strict mode.
+var ErrUnknownField = sdkerrors.ErrUnknownConfigField
+
+// unmarshalerType is the type of the yaml.Unmarshaler interface.
+var unmarshalerType = reflect.TypeFor[yaml.Unmarshaler]()
+
+// UnknownFieldError is returned in strict mode for every unknown field of a
+// YAML configuration.
+type UnknownFieldError struct {
+	// Path is the dot-separated path of the unknown field, e.g. "default.timout".
+	Path string
+	// Line is the line of the unknown field in the YAML document.
+	Line int
+	// Suggestion is the closest known field, if any is close enough.
+	Suggestion string
+}
+
+// Error returns the error message, including the suggestion, if any.
+func (e *UnknownFieldError) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf("line %d: unknown field %q", e.Line, e.Path)
+	}
+	return fmt.Sprintf("line %d: unknown field %q, did you mean %q?", e.Line, e.Path, e.Suggestion)
+}
+
+// Unwrap returns ErrUnknownField.
+func (e *UnknownFieldError) Unwrap() error {
+	return ErrUnknownField
+}
+
+// Option is a functional option used to configure the decoding of a YAML configuration.
+type Option func(*decodeConfig)
+
+// decodeConfig holds the settings applied by Options.
+type decodeConfig struct {
+	strict bool
+}
+
+// WithStrictFields rejects the unknown fields, i.e. the fields which do not
+// match any field of the decoded struct, with an UnknownFieldError each.
+func WithStrictFields() Option {
+	return func(c *decodeConfig) {
+		c.strict = true
+	}
+}
+
+// Unmarshal decodes the given YAML document into the value pointed to by out,
+// configured using the given options.
+// In strict mode, the returned error joins the UnknownFieldErrors of all the
+// unknown fields, and out is left unchanged.
+func Unmarshal(data []byte, out any, opts ...Option) error {
+	var config decodeConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.strict {
+		var document yaml.Node
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("Unmarshal: %w", err)
+		}
+		if unknownFieldErrs := unknownFields(&document, reflect.TypeOf(out), ""); len(unknownFieldErrs) > 0 {
+			return fmt.Errorf("Unmarshal: %w", errors.Join(unknownFieldErrs...))
+		}
+	}
+
+	if err := yaml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("Unmarshal: %w", err)
+	}
+	return nil
+}
+
+// unknownFields returns the UnknownFieldErrors of the fields of the given YAML
+// node not matching a field of the given type, recursively.
+func unknownFields(node *yaml.Node, t reflect.Type, path string) []error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// The types decoding themselves, e.g. from a scalar, define their own fields.
+	if t == nil || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		var errs []error
+		for _, child := range node.Content {
+			errs = append(errs, unknownFields(child, t, path)...)
+		}
+		return errs
+	case yaml.AliasNode:
+		return unknownFields(node.Alias, t, path)
+	case yaml.SequenceNode:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return nil
+		}
+		var errs []error
+		for i, child := range node.Content {
+			errs = append(errs, unknownFields(child, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case yaml.MappingNode:
+		switch t.Kind() {
+		case reflect.Map:
+			var errs []error
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				errs = append(errs, unknownFields(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))...)
+			}
+			return errs
+		case reflect.Struct:
+			return unknownStructFields(node, t, path)
+		}
+	}
+	return nil
+}
+
+// unknownStructFields returns the UnknownFieldErrors of the fields of the given
+// YAML mapping node not matching a field of the given struct type, recursively.
+func unknownStructFields(node *yaml.Node, t reflect.Type, path string) []error {
+	fields := structFields(t)
+
+	var errs []error
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		// The merge keys, i.e. "<<", are resolved by the YAML decoder.
+		if keyNode.Tag == "!!merge" {
+			errs = append(errs, unknownFields(valueNode, t, path)...)
+			continue
+		}
+
+		fieldType, ok := fields[keyNode.Value]
+		if !ok {
+			errs = append(errs, &UnknownFieldError{
+				Path:       joinPath(path, keyNode.Value),
+				Line:       keyNode.Line,
+				Suggestion: suggestField(keyNode.Value, fields),
+			})
+			continue
+		}
+		errs = append(errs, unknownFields(valueNode, fieldType, joinPath(path, keyNode.Value))...)
+	}
+	return errs
+}
+
+// structFields returns the types of the fields of the given struct type, keyed
+// by their YAML key, following the rules of the YAML decoder: the key is set by
+// the field's yaml tag, and defaults to the lower-cased field name.
+// The fields of the inlined structs are included.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if strings.Contains(flags, "inline") && field.Type.Kind() == reflect.Struct {
+			for inlinedName, inlinedType := range structFields(field.Type) {
+				fields[inlinedName] = inlinedType
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// suggestField returns the known field closest to the given unknown one, or
+// an empty string if none is close enough to be a likely typo.
+func suggestField(unknown string, fields map[string]reflect.Type) string {
+	normalizedUnknown := normalizeField(unknown)
+
+	var (
+		suggestion   string
+		bestDistance int
+	)
+	for field := range fields {
+		distance := levenshtein(normalizedUnknown, normalizeField(field))
+		if suggestion == "" || distance < bestDistance || (distance == bestDistance && field < suggestion) {
+			suggestion, bestDistance = field, distance
+		}
+	}
+
+	// Allow a typo per 3 characters, and at least 2.
+	if suggestion == "" || bestDistance > max(2, len(normalizedUnknown)/3) {
+		return ""
+	}
+	return suggestion
+}
+
+// normalizeField lower-cases the given field, and removes its separators, so
+// e.g. "sessionTTL" and "session-ttl" are suggested "session_ttl".
+func normalizeField(field string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(field))
+}
+
+// levenshtein returns the edit distance between the given strings.
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitution := previous[j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// joinPath returns the path of the given field of the given parent path.
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,96 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+	"github.com/pokt-network/shannon-sdk/config"
+	"github.com/pokt-network/shannon-sdk/retry"
+)
+
+type gatewayConfig struct {
+	SessionTTL time.Duration           `yaml:"session_ttl"`
+	Cache      cache.Config            `yaml:"cache"`
+	Retries    map[string]retry.Config `yaml:"retries"`
+	Services   []serviceConfig         `yaml:"services"`
+}
+
+type serviceConfig struct {
+	ServiceId   string `yaml:"service_id"`
+	MaxInFlight int
+}
+
+const validConfig = `
+session_ttl: 30s
+cache:
+  ttl: 1m
+  stale_grace_period: 10s
+retries:
+  fullnode:
+    max_attempts: 3
+services:
+  - service_id: anvil
+    maxinflight: 100
+`
+
+func TestUnmarshal(t *testing.T) {
+	expected := gatewayConfig{
+		SessionTTL: 30 * time.Second,
+		Cache:      cache.Config{TTL: time.Minute, StaleGracePeriod: 10 * time.Second},
+		Retries:    map[string]retry.Config{"fullnode": {MaxAttempts: 3}},
+		Services:   []serviceConfig{{ServiceId: "anvil", MaxInFlight: 100}},
+	}
+
+	for _, opts := range [][]config.Option{nil, {config.WithStrictFields()}} {
+		var decoded gatewayConfig
+		require.NoError(t, config.Unmarshal([]byte(validConfig), &decoded, opts...))
+		require.Equal(t, expected, decoded)
+	}
+}
+
+func TestUnmarshal_StrictFields(t *testing.T) {
+	const typoConfig = `
+sesion_ttl: 30s
+cache:
+  ttl: 1m
+  stale_grace_perod: 10s
+retries:
+  fullnode:
+    maxAttempts: 3
+services:
+  - service_id: anvil
+    max_in_flight: 100
+unrelated: true
+`
+
+	// Unknown fields are ignored by default.
+	var lenient gatewayConfig
+	require.NoError(t, config.Unmarshal([]byte(typoConfig), &lenient))
+	require.Equal(t, time.Minute, lenient.Cache.TTL)
+	require.Zero(t, lenient.SessionTTL)
+
+	strict := gatewayConfig{SessionTTL: time.Second}
+	err := config.Unmarshal([]byte(typoConfig), &strict, config.WithStrictFields())
+	require.ErrorIs(t, err, config.ErrUnknownField)
+	// The decoded value is left unchanged.
+	require.Equal(t, gatewayConfig{SessionTTL: time.Second}, strict)
+
+	var unknownFieldErrs []config.UnknownFieldError
+	for _, joinedErr := range errors.Unwrap(err).(interface{ Unwrap() []error }).Unwrap() {
+		var unknownFieldErr *config.UnknownFieldError
+		require.ErrorAs(t, joinedErr, &unknownFieldErr)
+		unknownFieldErrs = append(unknownFieldErrs, *unknownFieldErr)
+	}
+	require.Equal(t, []config.UnknownFieldError{
+		{Path: "sesion_ttl", Line: 2, Suggestion: "session_ttl"},
+		{Path: "cache.stale_grace_perod", Line: 5, Suggestion: "stale_grace_period"},
+		{Path: "retries.fullnode.maxAttempts", Line: 8, Suggestion: "max_attempts"},
+		{Path: "services[0].max_in_flight", Line: 11, Suggestion: "maxinflight"},
+		{Path: "unrelated", Line: 12},
+	}, unknownFieldErrs)
+	require.ErrorContains(t, err, `line 2: unknown field "sesion_ttl", did you mean "session_ttl"?`)
+}
@@ -0,0 +1,26 @@
+module github.com/pokt-network/shannon-sdk/config
+
+go 1.23.0
+
+require (
+	github.com/pokt-network/shannon-sdk/cache v0.0.0-00010101000000-000000000000
+	github.com/pokt-network/shannon-sdk/retry v0.0.0-00010101000000-000000000000
+	github.com/pokt-network/shannon-sdk/sdkerrors v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.9.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240528184218-531527333157 // indirect
+	google.golang.org/grpc v1.65.0 // indirect
+	google.golang.org/protobuf v1.34.1 // indirect
+)
+
+replace (
+	github.com/pokt-network/shannon-sdk/cache => ../cache
+	github.com/pokt-network/shannon-sdk/retry => ../retry
+	github.com/pokt-network/shannon-sdk/sdkerrors => ../sdkerrors
+)
@@ -0,0 +1,365 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// EndpointMetadataReadiness is the metadata key set on the endpoints warmed up
+	// by a ConnectionWarmer. Its value is EndpointReadinessWarm or
+	// EndpointReadinessFailed.
+	EndpointMetadataReadiness = "readiness"
+	// EndpointReadinessWarm is the readiness of the endpoints to which a
+	// connection was established by the last warm-up.
+	EndpointReadinessWarm = "warm"
+	// EndpointReadinessFailed is the readiness of the endpoints which could not be
+	// reached by the last warm-up.
+	EndpointReadinessFailed = "failed"
+
+	// defaultWarmupTimeout is the maximum duration of the warm-up of an endpoint
+	// if no timeout is specified.
+	defaultWarmupTimeout = 5 * time.Second
+	// defaultMaxConcurrentWarmups is the maximum number of endpoints warmed up
+	// concurrently if no limit is specified.
+	defaultMaxConcurrentWarmups = 16
+)
+
+// EndpointWarmup is the result of the last warm-up of an endpoint.
+type EndpointWarmup struct {
+	URL string
+	// Warm is true if a connection to the endpoint was established, regardless
+	// of the HTTP status returned by the supplier.
+	Warm bool
+	// Latency is the duration of the warm-up, including the TCP and TLS handshakes
+	// if no connection to the endpoint was open yet.
+	Latency time.Duration
+	// At is the time of the warm-up.
+	At time.Time
+	// Err is the error which prevented reaching the endpoint, if any.
+	Err error
+}
+
+// ConnectionWarmerOption is a functional option used to configure a ConnectionWarmer.
+type ConnectionWarmerOption func(*connectionWarmerConfig)
+
+// connectionWarmerConfig holds the settings applied by ConnectionWarmerOptions.
+type connectionWarmerConfig struct {
+	timeout          time.Duration
+	maxConcurrent    int
+	keepWarmInterval time.Duration
+}
+
+// WithWarmupTimeout sets the maximum duration of the warm-up of an endpoint.
+// It defaults to 5 seconds.
+func WithWarmupTimeout(timeout time.Duration) ConnectionWarmerOption {
+	return func(c *connectionWarmerConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithMaxConcurrentWarmups bounds the number of endpoints warmed up concurrently.
+// It defaults to 16.
+func WithMaxConcurrentWarmups(maxConcurrent int) ConnectionWarmerOption {
+	return func(c *connectionWarmerConfig) {
+		c.maxConcurrent = maxConcurrent
+	}
+}
+
+// WithKeepWarmInterval sets the interval at which Run warms up the endpoints of
+// the tracked sessions again, so their connections are not closed as idle.
+// It should be lower than the IdleConnTimeout of the HTTP client's transport.
+// Zero, the default, disables the periodic warm-ups.
+func WithKeepWarmInterval(interval time.Duration) ConnectionWarmerOption {
+	return func(c *connectionWarmerConfig) {
+		c.keepWarmInterval = interval
+	}
+}
+
+// ConnectionWarmer proactively establishes the connections to the endpoints of
+// the sessions, so the first relay to each supplier does not pay the TCP and TLS
+// handshakes latency.
+//
+// It must use the same HTTP client as the RelaySender, so the relays reuse the
+// warmed up connections, e.g. as built by NewHTTPClientFromConfig. Enabling the
+// TLSSessionCacheSize of the transport additionally makes the reconnections
+// resume their TLS session.
+//
+// An endpoint is warmed up by sending it a HEAD request, whose response is
+// discarded. The results are exposed as endpoint metadata by DecorateEndpoint,
+// until the sessions of the endpoint are evicted.
+// It is safe for concurrent use.
+type ConnectionWarmer struct {
+	httpClient *http.Client
+	config     connectionWarmerConfig
+	sem        chan struct{}
+
+	// mu protects sessions, which holds the endpoint URLs of the tracked sessions,
+	// warmups, which holds the last warm-up result of each endpoint URL, and
+	// pending, which holds the refreshed sessions waiting to be warmed up by Run.
+	mu       sync.Mutex
+	sessions map[SessionKey][]string
+	warmups  map[string]EndpointWarmup
+	pending  map[SessionKey]SessionInfo
+	// refreshed is signaled when a session is added to pending.
+	refreshed chan struct{}
+}
+
+// NewConnectionWarmer returns a ConnectionWarmer establishing the connections
+// using the given HTTP client, or http.DefaultClient if nil, configured using
+// the given options.
+func NewConnectionWarmer(httpClient *http.Client, opts ...ConnectionWarmerOption) *ConnectionWarmer {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	config := connectionWarmerConfig{
+		timeout:       defaultWarmupTimeout,
+		maxConcurrent: defaultMaxConcurrentWarmups,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &ConnectionWarmer{
+		httpClient: httpClient,
+		config:     config,
+		sem:        make(chan struct{}, max(config.maxConcurrent, 1)),
+		sessions:   make(map[SessionKey][]string),
+		warmups:    make(map[string]EndpointWarmup),
+		pending:    make(map[SessionKey]SessionInfo),
+		refreshed:  make(chan struct{}, 1),
+	}
+}
+
+// WarmSession tracks the given session, replacing the previous session of the
+// same application and service, and warms up the endpoints of its suppliers.
+// It returns once all the endpoints are warmed up.
+func (w *ConnectionWarmer) WarmSession(ctx context.Context, session SessionInfo) error {
+	filter := SessionFilter{Session: session.Session}
+	supplierEndpoints, err := filter.AllEndpoints()
+	if err != nil {
+		return fmt.Errorf("WarmSession: %w", err)
+	}
+
+	var urls []string
+	for _, endpoints := range supplierEndpoints {
+		for _, e := range endpoints {
+			urls = append(urls, e.Endpoint().Url)
+		}
+	}
+
+	w.mu.Lock()
+	w.trackLocked(warmerSessionKey(session), urls)
+	w.mu.Unlock()
+
+	w.warmTrackedURLs(ctx, urls)
+	return nil
+}
+
+// WarmURLs warms up the endpoints at the given URLs, concurrently.
+// It returns once all the endpoints are warmed up.
+func (w *ConnectionWarmer) WarmURLs(ctx context.Context, urls []string) {
+	var wg sync.WaitGroup
+	for _, url := range urls {
+		select {
+		case w.sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-w.sem }()
+
+			warmup := w.warm(ctx, url)
+			w.mu.Lock()
+			w.warmups[url] = warmup
+			w.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+}
+
+// HandleEvent queues the sessions of the SessionRefreshedEvents to be warmed up
+// by Run, and stops tracking the sessions of the CacheEvictedEvents, dropping
+// the warm-up results of their endpoints.
+// It can be subscribed to the EventBus of a SessionCache. It does not block.
+func (w *ConnectionWarmer) HandleEvent(event Event) {
+	switch event := event.(type) {
+	case SessionRefreshedEvent:
+		w.mu.Lock()
+		w.pending[warmerSessionKey(event.Session)] = event.Session
+		w.mu.Unlock()
+
+		select {
+		case w.refreshed <- struct{}{}:
+		default:
+		}
+	case CacheEvictedEvent:
+		w.mu.Lock()
+		delete(w.pending, event.Key)
+		w.trackLocked(event.Key, nil)
+		w.mu.Unlock()
+	}
+}
+
+// Run warms up the endpoints of the sessions queued by HandleEvent, and those of
+// the tracked sessions at the interval set by WithKeepWarmInterval, until the
+// given context is done. The warm-ups are bound to the given context, and to
+// the concurrency set by WithMaxConcurrentWarmups.
+func (w *ConnectionWarmer) Run(ctx context.Context) error {
+	var keepWarm <-chan time.Time
+	if w.config.keepWarmInterval > 0 {
+		ticker := time.NewTicker(w.config.keepWarmInterval)
+		defer ticker.Stop()
+		keepWarm = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.refreshed:
+			w.mu.Lock()
+			pending := w.pending
+			w.pending = make(map[SessionKey]SessionInfo)
+			w.mu.Unlock()
+
+			for _, session := range pending {
+				// The sessions whose endpoints can not be listed are skipped.
+				_ = w.WarmSession(ctx, session)
+			}
+		case <-keepWarm:
+			w.warmTrackedURLs(ctx, w.trackedURLs())
+		}
+	}
+}
+
+// Warmup returns the result of the last warm-up of the endpoint at the given
+// URL, or false if it was never warmed up.
+func (w *ConnectionWarmer) Warmup(url string) (EndpointWarmup, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	warmup, ok := w.warmups[url]
+	return warmup, ok
+}
+
+// DecorateEndpoint is an EndpointDecorator setting the EndpointMetadataReadiness
+// metadata on the warmed up endpoints, e.g. to prefer the warm endpoints.
+// It can be set in the EndpointDecorators of a SessionFilter.
+func (w *ConnectionWarmer) DecorateEndpoint(e Endpoint) Endpoint {
+	warmup, ok := w.Warmup(e.Endpoint().Url)
+	switch {
+	case !ok:
+		return e
+	case warmup.Warm:
+		return WithEndpointMetadata(EndpointMetadataReadiness, EndpointReadinessWarm)(e)
+	default:
+		return WithEndpointMetadata(EndpointMetadataReadiness, EndpointReadinessFailed)(e)
+	}
+}
+
+// trackLocked sets the endpoint URLs of the tracked session with the given key,
+// or stops tracking it if urls is nil, and drops the warm-up results of the
+// endpoints no longer tracked by any session.
+// It must be called with mu held.
+func (w *ConnectionWarmer) trackLocked(key SessionKey, urls []string) {
+	previousURLs := w.sessions[key]
+	if urls == nil {
+		delete(w.sessions, key)
+	} else {
+		w.sessions[key] = urls
+	}
+
+	w.dropUntrackedLocked(previousURLs)
+}
+
+// warmTrackedURLs warms up the endpoints of the tracked sessions at the given
+// URLs, and drops the results of those whose sessions were evicted meanwhile.
+func (w *ConnectionWarmer) warmTrackedURLs(ctx context.Context, urls []string) {
+	w.WarmURLs(ctx, urls)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dropUntrackedLocked(urls)
+}
+
+// dropUntrackedLocked drops the warm-up results of the given endpoint URLs
+// which are not tracked by any session.
+// It must be called with mu held.
+func (w *ConnectionWarmer) dropUntrackedLocked(urls []string) {
+	if len(urls) == 0 {
+		return
+	}
+
+	tracked := make(map[string]struct{})
+	for _, sessionURLs := range w.sessions {
+		for _, url := range sessionURLs {
+			tracked[url] = struct{}{}
+		}
+	}
+	for _, url := range urls {
+		if _, ok := tracked[url]; !ok {
+			delete(w.warmups, url)
+		}
+	}
+}
+
+// warmerSessionKey returns the key of the given session in a ConnectionWarmer.
+func warmerSessionKey(session SessionInfo) SessionKey {
+	header := session.GetHeader()
+	return SessionKey{AppAddress: header.GetApplicationAddress(), ServiceId: header.GetServiceId()}
+}
+
+// trackedURLs returns the distinct endpoint URLs of the tracked sessions.
+func (w *ConnectionWarmer) trackedURLs() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seen := make(map[string]struct{})
+	var urls []string
+	for _, sessionURLs := range w.sessions {
+		for _, url := range sessionURLs {
+			if _, ok := seen[url]; !ok {
+				seen[url] = struct{}{}
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
+
+// warm sends a HEAD request to the given URL, leaving the connection open in
+// the HTTP client's pool of idle connections.
+func (w *ConnectionWarmer) warm(ctx context.Context, url string) EndpointWarmup {
+	ctx, cancel := context.WithTimeout(ctx, w.config.timeout)
+	defer cancel()
+
+	warmup := EndpointWarmup{URL: url, At: time.Now()}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		warmup.Err = fmt.Errorf("error building warm-up request: %w", err)
+		return warmup
+	}
+
+	httpResponse, err := w.httpClient.Do(httpRequest)
+	warmup.Latency = time.Since(warmup.At)
+	if err != nil {
+		warmup.Err = err
+		return warmup
+	}
+	// The body is drained so the connection can be reused.
+	_, _ = io.Copy(io.Discard, httpResponse.Body)
+	httpResponse.Body.Close()
+
+	warmup.Warm = true
+	return warmup
+}
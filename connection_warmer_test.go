@@ -0,0 +1,125 @@
+package sdk
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionWarmer(t *testing.T) {
+	var newConns atomic.Int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConns.Add(1)
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	unreachableServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachableURL := unreachableServer.URL
+	unreachableServer.Close()
+
+	session := &sessiontypes.Session{
+		Header: &sessiontypes.SessionHeader{ApplicationAddress: "pokt1app", ServiceId: "svc1"},
+		Suppliers: []*sharedtypes.Supplier{
+			{OperatorAddress: "pokt1supplier1", Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: server.URL}},
+			}}},
+			{OperatorAddress: "pokt1supplier2", Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: unreachableURL}},
+			}}},
+		},
+	}
+
+	httpClient := server.Client()
+	warmer := NewConnectionWarmer(httpClient)
+	require.NoError(t, warmer.WarmSession(context.Background(), SessionInfo{Session: session}))
+	require.Equal(t, int64(1), newConns.Load())
+
+	warmup, ok := warmer.Warmup(server.URL)
+	require.True(t, ok)
+	require.True(t, warmup.Warm)
+	require.NoError(t, warmup.Err)
+	warmup, ok = warmer.Warmup(unreachableURL)
+	require.True(t, ok)
+	require.False(t, warmup.Warm)
+	require.Error(t, warmup.Err)
+
+	// The warm-up results are exposed as endpoint metadata.
+	filter := SessionFilter{Session: session, EndpointDecorators: []EndpointDecorator{warmer.DecorateEndpoint}}
+	endpoints, err := filter.FilteredEndpoints()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2)
+	for _, e := range endpoints {
+		readiness, ok := GetEndpointMetadata(e, EndpointMetadataReadiness)
+		require.True(t, ok)
+		if e.Endpoint().Url == server.URL {
+			require.Equal(t, EndpointReadinessWarm, readiness)
+		} else {
+			require.Equal(t, EndpointReadinessFailed, readiness)
+		}
+	}
+
+	// The relays reuse the warmed up connection.
+	sender := NewHTTPRelaySender(httpClient)
+	endpoint := NewEndpoint(*session.Header, sharedtypes.SupplierEndpoint{Url: server.URL}, SupplierInfo{})
+	_, err = sender(context.Background(), endpoint, &servicetypes.RelayRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), newConns.Load())
+
+	// The sessions evicted from the cache are no longer tracked.
+	require.ElementsMatch(t, []string{server.URL, unreachableURL}, warmer.trackedURLs())
+	warmer.HandleEvent(CacheEvictedEvent{Key: SessionKey{AppAddress: "pokt1app", ServiceId: "svc1"}})
+	require.Empty(t, warmer.trackedURLs())
+
+	// The warm-up results of their endpoints are dropped.
+	_, ok = warmer.Warmup(server.URL)
+	require.False(t, ok)
+}
+
+func TestConnectionWarmer_Run(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	session := SessionInfo{Session: &sessiontypes.Session{
+		Header: &sessiontypes.SessionHeader{ApplicationAddress: "pokt1app", ServiceId: "svc1"},
+		Suppliers: []*sharedtypes.Supplier{{
+			OperatorAddress: "pokt1supplier1",
+			Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: server.URL}},
+			}},
+		}},
+	}}
+
+	// The refreshed sessions are only warmed up by Run, within its context.
+	warmer := NewConnectionWarmer(server.Client())
+	warmer.HandleEvent(SessionRefreshedEvent{AppAddress: "pokt1app", ServiceId: "svc1", Session: session})
+	_, ok := warmer.Warmup(server.URL)
+	require.False(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- warmer.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		warmup, ok := warmer.Warmup(server.URL)
+		return ok && warmup.Warm
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-runErr, context.Canceled)
+}
@@ -0,0 +1,175 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"cosmossdk.io/math"
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pokt-network/poktroll/app/volatile"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	tokenomicstypes "github.com/pokt-network/poktroll/x/tokenomics/types"
+)
+
+// ComputeUnitsToUpokt converts the given number of compute units to uPOKT using
+// the given compute units to tokens multiplier, as defined by the tokenomics module.
+func ComputeUnitsToUpokt(computeUnits, computeUnitsToTokensMultiplier uint64) cosmostypes.Coin {
+	amount := math.NewIntFromUint64(computeUnits).
+		Mul(math.NewIntFromUint64(computeUnitsToTokensMultiplier))
+
+	return cosmostypes.NewCoin(volatile.DenomuPOKT, amount)
+}
+
+// UpoktToComputeUnits converts the given uPOKT amount to the number of compute
+// units it can pay for, using the given compute units to tokens multiplier.
+// Any remainder which does not cover a full compute unit is discarded.
+func UpoktToComputeUnits(upokt cosmostypes.Coin, computeUnitsToTokensMultiplier uint64) (uint64, error) {
+	if upokt.Denom != volatile.DenomuPOKT {
+		return 0, fmt.Errorf("UpoktToComputeUnits: unexpected denom %q, expected %q", upokt.Denom, volatile.DenomuPOKT)
+	}
+
+	if computeUnitsToTokensMultiplier == 0 {
+		return 0, errors.New("UpoktToComputeUnits: compute units to tokens multiplier must be greater than zero")
+	}
+
+	computeUnits := upokt.Amount.Quo(math.NewIntFromUint64(computeUnitsToTokensMultiplier))
+	if !computeUnits.IsUint64() {
+		return 0, fmt.Errorf("UpoktToComputeUnits: compute units %s overflow uint64", computeUnits)
+	}
+
+	return computeUnits.Uint64(), nil
+}
+
+// CostEstimator provides cost projections of relays and sessions, in uPOKT,
+// which can be used by gateways for capacity planning.
+//
+// It relies on the query clients of the service, tokenomics, shared and
+// application modules to fetch the onchain data required for the estimates.
+type CostEstimator struct {
+	ServiceQueryClient     servicetypes.QueryClient
+	TokenomicsQueryClient  tokenomicstypes.QueryClient
+	SharedQueryClient      sharedtypes.QueryClient
+	ApplicationQueryClient apptypes.QueryClient
+}
+
+// SessionCostEstimate captures the projected cost of a single session of an
+// application for a given service.
+type SessionCostEstimate struct {
+	ServiceId    string
+	NumRelays    uint64
+	ComputeUnits uint64
+	Cost         cosmostypes.Coin
+	// AppStake is the current stake of the application, which is used to pay for the session.
+	AppStake cosmostypes.Coin
+	// ExceedsAppStake indicates whether the projected cost of the session exceeds the application's stake.
+	ExceedsAppStake bool
+}
+
+// EstimateRelayCost returns the cost, in uPOKT, of a single relay for the given service.
+func (ce *CostEstimator) EstimateRelayCost(
+	ctx context.Context,
+	serviceId string,
+) (cosmostypes.Coin, error) {
+	service, multiplier, err := ce.getServiceAndMultiplier(ctx, serviceId)
+	if err != nil {
+		return cosmostypes.Coin{}, fmt.Errorf("EstimateRelayCost: %w", err)
+	}
+
+	return ComputeUnitsToUpokt(service.ComputeUnitsPerRelay, multiplier), nil
+}
+
+// EstimateSessionCost returns the projected cost, in uPOKT, of a single session of
+// the given application for the given service, assuming the application sends
+// relaysPerBlock relays on every block of the session.
+func (ce *CostEstimator) EstimateSessionCost(
+	ctx context.Context,
+	appAddress string,
+	serviceId string,
+	relaysPerBlock uint64,
+) (SessionCostEstimate, error) {
+	if ce.SharedQueryClient == nil || ce.ApplicationQueryClient == nil {
+		return SessionCostEstimate{}, errors.New("EstimateSessionCost: shared and application query clients must be set")
+	}
+
+	service, multiplier, err := ce.getServiceAndMultiplier(ctx, serviceId)
+	if err != nil {
+		return SessionCostEstimate{}, fmt.Errorf("EstimateSessionCost: %w", err)
+	}
+
+	sharedParamsRes, err := ce.SharedQueryClient.Params(ctx, &sharedtypes.QueryParamsRequest{})
+	if err != nil {
+		return SessionCostEstimate{}, fmt.Errorf("EstimateSessionCost: error getting shared module params: %w", err)
+	}
+
+	appRes, err := ce.ApplicationQueryClient.Application(ctx, &apptypes.QueryGetApplicationRequest{Address: appAddress})
+	if err != nil {
+		return SessionCostEstimate{}, fmt.Errorf("EstimateSessionCost: error getting application %s: %w", appAddress, err)
+	}
+
+	numRelays, ok := mulUint64(relaysPerBlock, sharedParamsRes.Params.NumBlocksPerSession)
+	if !ok {
+		return SessionCostEstimate{}, fmt.Errorf(
+			"EstimateSessionCost: number of relays of %d relays per block over %d blocks overflows uint64",
+			relaysPerBlock,
+			sharedParamsRes.Params.NumBlocksPerSession,
+		)
+	}
+	computeUnits, ok := mulUint64(numRelays, service.ComputeUnitsPerRelay)
+	if !ok {
+		return SessionCostEstimate{}, fmt.Errorf(
+			"EstimateSessionCost: compute units of %d relays of %d compute units overflow uint64",
+			numRelays,
+			service.ComputeUnitsPerRelay,
+		)
+	}
+	cost := ComputeUnitsToUpokt(computeUnits, multiplier)
+
+	estimate := SessionCostEstimate{
+		ServiceId:    serviceId,
+		NumRelays:    numRelays,
+		ComputeUnits: computeUnits,
+		Cost:         cost,
+	}
+
+	if appStake := appRes.Application.Stake; appStake != nil {
+		estimate.AppStake = *appStake
+		estimate.ExceedsAppStake = appStake.Denom != cost.Denom || appStake.IsLT(cost)
+	} else {
+		estimate.ExceedsAppStake = true
+	}
+
+	return estimate, nil
+}
+
+// mulUint64 returns the product of a and b, and false if it overflows uint64.
+func mulUint64(a, b uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	return lo, hi == 0
+}
+
+// getServiceAndMultiplier returns the onchain service with the given id, along
+// with the current compute units to tokens multiplier of the tokenomics module.
+func (ce *CostEstimator) getServiceAndMultiplier(
+	ctx context.Context,
+	serviceId string,
+) (sharedtypes.Service, uint64, error) {
+	if ce.ServiceQueryClient == nil || ce.TokenomicsQueryClient == nil {
+		return sharedtypes.Service{}, 0, errors.New("service and tokenomics query clients must be set")
+	}
+
+	serviceRes, err := ce.ServiceQueryClient.Service(ctx, &servicetypes.QueryGetServiceRequest{Id: serviceId})
+	if err != nil {
+		return sharedtypes.Service{}, 0, fmt.Errorf("error getting service %s: %w", serviceId, err)
+	}
+
+	tokenomicsParamsRes, err := ce.TokenomicsQueryClient.Params(ctx, &tokenomicstypes.QueryParamsRequest{})
+	if err != nil {
+		return sharedtypes.Service{}, 0, fmt.Errorf("error getting tokenomics module params: %w", err)
+	}
+
+	return serviceRes.Service, tokenomicsParamsRes.Params.ComputeUnitsToTokensMultiplier, nil
+}
@@ -0,0 +1,170 @@
+package sdk
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	tokenomicstypes "github.com/pokt-network/poktroll/x/tokenomics/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+)
+
+func TestCost_ComputeUnitsToUpokt(t *testing.T) {
+	coin := ComputeUnitsToUpokt(10, 42)
+	require.Equal(t, cosmostypes.NewInt64Coin("upokt", 420), coin)
+}
+
+func TestCost_UpoktToComputeUnits(t *testing.T) {
+	tests := []struct {
+		desc                 string
+		upokt                cosmostypes.Coin
+		multiplier           uint64
+		expectedComputeUnits uint64
+		expectErr            bool
+	}{
+		{
+			desc:                 "exact conversion",
+			upokt:                cosmostypes.NewInt64Coin("upokt", 420),
+			multiplier:           42,
+			expectedComputeUnits: 10,
+		},
+		{
+			desc:                 "remainder is discarded",
+			upokt:                cosmostypes.NewInt64Coin("upokt", 430),
+			multiplier:           42,
+			expectedComputeUnits: 10,
+		},
+		{
+			desc:       "zero multiplier",
+			upokt:      cosmostypes.NewInt64Coin("upokt", 420),
+			multiplier: 0,
+			expectErr:  true,
+		},
+		{
+			desc:       "unexpected denom",
+			upokt:      cosmostypes.NewInt64Coin("stake", 420),
+			multiplier: 42,
+			expectErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			computeUnits, err := UpoktToComputeUnits(test.upokt, test.multiplier)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedComputeUnits, computeUnits)
+		})
+	}
+}
+
+func TestCostEstimator_EstimateSessionCost(t *testing.T) {
+	appStake := cosmostypes.NewInt64Coin("upokt", 1000)
+	newEstimator := func(numBlocksPerSession, computeUnitsPerRelay uint64) *CostEstimator {
+		return &CostEstimator{
+			ServiceQueryClient: &fakeServiceQueryClient{
+				service: sharedtypes.Service{Id: "svc1", ComputeUnitsPerRelay: computeUnitsPerRelay},
+			},
+			TokenomicsQueryClient: &fakeTokenomicsQueryClient{
+				params: tokenomicstypes.Params{ComputeUnitsToTokensMultiplier: 42},
+			},
+			SharedQueryClient: &fakeSharedQueryClient{
+				params: sharedtypes.Params{NumBlocksPerSession: numBlocksPerSession},
+			},
+			ApplicationQueryClient: &fakeAppQueryClient{apps: map[string]apptypes.Application{
+				"pokt1app": {Address: "pokt1app", Stake: &appStake},
+			}},
+		}
+	}
+
+	tests := []struct {
+		desc                 string
+		relaysPerBlock       uint64
+		numBlocksPerSession  uint64
+		computeUnitsPerRelay uint64
+		expectedComputeUnits uint64
+		expectErr            bool
+	}{
+		{
+			desc:                 "session cost",
+			relaysPerBlock:       10,
+			numBlocksPerSession:  4,
+			computeUnitsPerRelay: 2,
+			expectedComputeUnits: 80,
+		},
+		{
+			desc:                 "largest number of compute units",
+			relaysPerBlock:       math.MaxUint64,
+			numBlocksPerSession:  1,
+			computeUnitsPerRelay: 1,
+			expectedComputeUnits: math.MaxUint64,
+		},
+		{
+			desc:                 "number of relays overflow",
+			relaysPerBlock:       math.MaxUint64/4 + 1,
+			numBlocksPerSession:  4,
+			computeUnitsPerRelay: 1,
+			expectErr:            true,
+		},
+		{
+			desc:                 "compute units overflow",
+			relaysPerBlock:       math.MaxUint64 / 4,
+			numBlocksPerSession:  4,
+			computeUnitsPerRelay: 2,
+			expectErr:            true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			estimator := newEstimator(test.numBlocksPerSession, test.computeUnitsPerRelay)
+			estimate, err := estimator.EstimateSessionCost(context.Background(), "pokt1app", "svc1", test.relaysPerBlock)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedComputeUnits, estimate.ComputeUnits)
+			require.Equal(t, ComputeUnitsToUpokt(test.expectedComputeUnits, 42), estimate.Cost)
+			require.Equal(t, estimate.Cost.Amount.GT(appStake.Amount), estimate.ExceedsAppStake)
+		})
+	}
+}
+
+// fakeServiceQueryClient is a service module QueryClient serving a single service.
+type fakeServiceQueryClient struct {
+	servicetypes.QueryClient
+	service sharedtypes.Service
+}
+
+func (c *fakeServiceQueryClient) Service(
+	_ context.Context,
+	_ *servicetypes.QueryGetServiceRequest,
+	_ ...grpcoptions.CallOption,
+) (*servicetypes.QueryGetServiceResponse, error) {
+	return &servicetypes.QueryGetServiceResponse{Service: c.service}, nil
+}
+
+// fakeTokenomicsQueryClient is a tokenomics module QueryClient returning fixed params.
+type fakeTokenomicsQueryClient struct {
+	tokenomicstypes.QueryClient
+	params tokenomicstypes.Params
+}
+
+func (c *fakeTokenomicsQueryClient) Params(
+	_ context.Context,
+	_ *tokenomicstypes.QueryParamsRequest,
+	_ ...grpcoptions.CallOption,
+) (*tokenomicstypes.QueryParamsResponse, error) {
+	return &tokenomicstypes.QueryParamsResponse{Params: c.params}, nil
+}
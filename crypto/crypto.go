@@ -0,0 +1,126 @@
+// Package crypto provides the key management utilities needed to provision
+// application and gateway keys, without relying on the poktrolld CLI.
+//
+// It supports generating secp256k1 keys, importing and exporting them as hex
+// or ASCII-armored and passphrase-encrypted strings, deriving them from BIP39
+// mnemonics using the BIP44 cosmos derivation path, and deriving their addresses.
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	sdkcrypto "github.com/cosmos/cosmos-sdk/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/go-bip39"
+)
+
+const (
+	// CoinType is the BIP44 coin type used to derive POKT keys, i.e. the cosmos coin type.
+	CoinType = 118
+
+	// mnemonicEntropyBits is the entropy of generated mnemonics, resulting in 24 words.
+	mnemonicEntropyBits = 256
+)
+
+// GeneratePrivateKey returns a new, randomly generated, secp256k1 private key.
+func GeneratePrivateKey() *secp256k1.PrivKey {
+	return secp256k1.GenPrivKey()
+}
+
+// PrivateKeyToHex returns the hex encoding of the given private key.
+func PrivateKeyToHex(privKey *secp256k1.PrivKey) string {
+	return hex.EncodeToString(privKey.Bytes())
+}
+
+// PrivateKeyFromHex decodes the given hex-encoded secp256k1 private key.
+func PrivateKeyFromHex(privateKeyHex string) (*secp256k1.PrivKey, error) {
+	privKeyBz, err := hex.DecodeString(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("PrivateKeyFromHex: error decoding private key hex: %w", err)
+	}
+
+	if len(privKeyBz) != secp256k1.PrivKeySize {
+		return nil, fmt.Errorf(
+			"PrivateKeyFromHex: invalid private key length: expected %d bytes, got %d",
+			secp256k1.PrivKeySize,
+			len(privKeyBz),
+		)
+	}
+
+	return &secp256k1.PrivKey{Key: privKeyBz}, nil
+}
+
+// ArmorPrivateKey encrypts the given private key using the given passphrase,
+// and returns it as an ASCII-armored string.
+// The format is the one used by the cosmos SDK keyring, e.g. `poktrolld keys export`.
+func ArmorPrivateKey(privKey *secp256k1.PrivKey, passphrase string) string {
+	return sdkcrypto.EncryptArmorPrivKey(privKey, passphrase, string(hd.Secp256k1Type))
+}
+
+// UnarmorPrivateKey decrypts the given ASCII-armored private key, using the
+// given passphrase.
+func UnarmorPrivateKey(armoredPrivKey string, passphrase string) (*secp256k1.PrivKey, error) {
+	privKey, _, err := sdkcrypto.UnarmorDecryptPrivKey(armoredPrivKey, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("UnarmorPrivateKey: error decrypting private key: %w", err)
+	}
+
+	secp256k1PrivKey, ok := privKey.(*secp256k1.PrivKey)
+	if !ok {
+		return nil, fmt.Errorf("UnarmorPrivateKey: unsupported private key type %T", privKey)
+	}
+
+	return secp256k1PrivKey, nil
+}
+
+// NewMnemonic returns a new, randomly generated, 24 words BIP39 mnemonic.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", fmt.Errorf("NewMnemonic: error generating entropy: %w", err)
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// PrivateKeyFromMnemonic derives the private key of the given account and
+// address index from the given BIP39 mnemonic and optional BIP39 passphrase,
+// using the BIP44 cosmos derivation path, i.e. m/44'/118'/account'/0/index.
+func PrivateKeyFromMnemonic(
+	mnemonic string,
+	bip39Passphrase string,
+	account uint32,
+	index uint32,
+) (*secp256k1.PrivKey, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("PrivateKeyFromMnemonic: invalid mnemonic")
+	}
+
+	hdPath := hd.CreateHDPath(CoinType, account, index).String()
+	privKeyBz, err := hd.Secp256k1.Derive()(mnemonic, bip39Passphrase, hdPath)
+	if err != nil {
+		return nil, fmt.Errorf("PrivateKeyFromMnemonic: error deriving private key: %w", err)
+	}
+
+	return &secp256k1.PrivKey{Key: privKeyBz}, nil
+}
+
+// Address returns the bech32 address, with the given prefix, of the account
+// owning the given public key.
+func Address(prefix string, pubKey cryptotypes.PubKey) (string, error) {
+	if pubKey == nil {
+		return "", errors.New("Address: public key not set")
+	}
+
+	address, err := bech32.ConvertAndEncode(prefix, pubKey.Address())
+	if err != nil {
+		return "", fmt.Errorf("Address: error encoding address with prefix %s: %w", prefix, err)
+	}
+
+	return address, nil
+}
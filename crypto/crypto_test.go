@@ -0,0 +1,64 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateKey_HexRoundTrip(t *testing.T) {
+	privKey := GeneratePrivateKey()
+
+	decodedPrivKey, err := PrivateKeyFromHex(PrivateKeyToHex(privKey))
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(decodedPrivKey))
+
+	_, err = PrivateKeyFromHex("not hex")
+	require.Error(t, err)
+
+	_, err = PrivateKeyFromHex("abcd")
+	require.Error(t, err)
+}
+
+func TestPrivateKey_ArmorRoundTrip(t *testing.T) {
+	privKey := GeneratePrivateKey()
+	armored := ArmorPrivateKey(privKey, "passphrase")
+
+	decodedPrivKey, err := UnarmorPrivateKey(armored, "passphrase")
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(decodedPrivKey))
+
+	_, err = UnarmorPrivateKey(armored, "wrong passphrase")
+	require.Error(t, err)
+}
+
+func TestPrivateKeyFromMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	require.NoError(t, err)
+
+	privKey, err := PrivateKeyFromMnemonic(mnemonic, "", 0, 0)
+	require.NoError(t, err)
+
+	// Derivation is deterministic.
+	samePrivKey, err := PrivateKeyFromMnemonic(mnemonic, "", 0, 0)
+	require.NoError(t, err)
+	require.True(t, privKey.Equals(samePrivKey))
+
+	otherPrivKey, err := PrivateKeyFromMnemonic(mnemonic, "", 0, 1)
+	require.NoError(t, err)
+	require.False(t, privKey.Equals(otherPrivKey))
+
+	_, err = PrivateKeyFromMnemonic("not a valid mnemonic", "", 0, 0)
+	require.Error(t, err)
+}
+
+func TestAddress(t *testing.T) {
+	privKey := GeneratePrivateKey()
+
+	address, err := Address("pokt", privKey.PubKey())
+	require.NoError(t, err)
+	require.Regexp(t, "^pokt1", address)
+
+	_, err = Address("pokt", nil)
+	require.Error(t, err)
+}
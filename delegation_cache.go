@@ -0,0 +1,190 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/pokt-network/poktroll/x/application/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+// DelegatingApplicationsCache caches the onchain applications, so the
+// applications delegating to a gateway can be computed without scanning all
+// the applications of the network on every call.
+//
+// The applications are fetched at once on the first call, and again once the
+// configured cache TTL expires. In between, the applications whose delegations
+// changed are refreshed individually, e.g. as notified by DelegationChangedEvents.
+// It is safe for concurrent use.
+type DelegatingApplicationsCache struct {
+	appClient ApplicationQuerier
+	apps      *cache.Cache[struct{}, map[string]types.Application]
+
+	// mu protects dirty, which holds the addresses of the applications to
+	// refresh, refreshed, which holds the applications refreshed since the
+	// last full scan, or nil for the applications which no longer exist, and
+	// scans, which counts the full scans, so the refreshes fetched across a full
+	// scan are discarded.
+	mu        sync.Mutex
+	dirty     map[string]struct{}
+	refreshed map[string]*types.Application
+	scans     uint64
+}
+
+// NewDelegatingApplicationsCache returns a DelegatingApplicationsCache which
+// fetches the applications using the given ApplicationQuerier, e.g. an
+// ApplicationClient.
+// The cache config's TTL bounds the time between two full scans of the
+// applications; a zero TTL only scans them once.
+func NewDelegatingApplicationsCache(appClient ApplicationQuerier, config cache.Config) *DelegatingApplicationsCache {
+	return &DelegatingApplicationsCache{
+		appClient: appClient,
+		apps:      cache.New[struct{}, map[string]types.Application](config),
+		dirty:     make(map[string]struct{}),
+		refreshed: make(map[string]*types.Application),
+	}
+}
+
+// GetApplicationsDelegatingToGateway returns the addresses, in lexical order, of
+// the applications delegating to the given gateway address at the given session
+// end height.
+// See ApplicationClient.GetApplicationsDelegatingToGateway.
+func (c *DelegatingApplicationsCache) GetApplicationsDelegatingToGateway(
+	ctx context.Context,
+	gatewayAddress string,
+	sessionEndHeight uint64,
+) ([]string, error) {
+	apps, _, err := c.apps.GetOrFetch(ctx, struct{}{}, c.fetchAllApplications)
+	if err != nil {
+		return nil, fmt.Errorf("GetApplicationsDelegatingToGateway: error getting all applications: %w", err)
+	}
+
+	applications, err := c.applyRefreshes(ctx, apps)
+	if err != nil {
+		return nil, fmt.Errorf("GetApplicationsDelegatingToGateway: %w", err)
+	}
+
+	appAddresses := filterApplicationsDelegatingToGateway(applications, gatewayAddress, sessionEndHeight)
+	slices.Sort(appAddresses)
+	return appAddresses, nil
+}
+
+// Invalidate marks the application with the given address to be refreshed on
+// the next call, e.g. after it staked, unstaked, or changed its delegations.
+func (c *DelegatingApplicationsCache) Invalidate(appAddress string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dirty[appAddress] = struct{}{}
+}
+
+// HandleEvent invalidates the application of the DelegationChangedEvents and
+// ApplicationStakeChangedEvents.
+// It can be subscribed to an EventBus, which notifies the application changes,
+// e.g. as published by an ApplicationEventWatcher.
+func (c *DelegatingApplicationsCache) HandleEvent(event Event) {
+	switch event := event.(type) {
+	case DelegationChangedEvent:
+		c.Invalidate(event.AppAddress)
+	case ApplicationStakeChangedEvent:
+		c.Invalidate(event.AppAddress)
+	}
+}
+
+// fetchAllApplications fetches all the onchain applications, keyed by address.
+func (c *DelegatingApplicationsCache) fetchAllApplications(ctx context.Context) (map[string]types.Application, error) {
+	// A full scan supersedes the refreshes requested so far.
+	c.mu.Lock()
+	clear(c.dirty)
+	clear(c.refreshed)
+	c.scans++
+	c.mu.Unlock()
+
+	allApplications, err := c.appClient.GetAllApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := make(map[string]types.Application, len(allApplications))
+	for _, app := range allApplications {
+		apps[app.Address] = app
+	}
+	return apps, nil
+}
+
+// applyRefreshes fetches the applications marked to be refreshed, and returns
+// the given applications updated with all the applications refreshed since the
+// last full scan. The given map is not modified.
+// The applications are fetched without holding the lock, so the concurrent
+// calls and invalidations are not blocked by the full node.
+func (c *DelegatingApplicationsCache) applyRefreshes(
+	ctx context.Context,
+	apps map[string]types.Application,
+) ([]types.Application, error) {
+	c.mu.Lock()
+	dirty := make([]string, 0, len(c.dirty))
+	for appAddress := range c.dirty {
+		dirty = append(dirty, appAddress)
+	}
+	clear(c.dirty)
+	scans := c.scans
+	c.mu.Unlock()
+
+	fetched := make(map[string]*types.Application, len(dirty))
+	var fetchErr error
+	for _, appAddress := range dirty {
+		app, err := c.appClient.GetApplication(ctx, appAddress)
+		switch {
+		case status.Code(err) == codes.NotFound:
+			// The application unstaked.
+			fetched[appAddress] = nil
+		case err != nil:
+			fetchErr = fmt.Errorf("error refreshing application %s: %w", appAddress, err)
+		default:
+			fetched[appAddress] = &app
+		}
+		if fetchErr != nil {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// A full scan completed meanwhile supersedes the fetched applications.
+	if c.scans == scans {
+		for appAddress, app := range fetched {
+			c.refreshed[appAddress] = app
+		}
+		if fetchErr != nil {
+			// The applications which were not fetched are refreshed on the next call.
+			for _, appAddress := range dirty {
+				if _, ok := fetched[appAddress]; !ok {
+					c.dirty[appAddress] = struct{}{}
+				}
+			}
+		}
+	}
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	applications := make([]types.Application, 0, len(apps)+len(c.refreshed))
+	for appAddress, app := range apps {
+		if _, ok := c.refreshed[appAddress]; !ok {
+			applications = append(applications, app)
+		}
+	}
+	for _, app := range c.refreshed {
+		if app != nil {
+			applications = append(applications, *app)
+		}
+	}
+
+	return applications, nil
+}
@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+func TestDelegatingApplicationsCache(t *testing.T) {
+	queryClient := &fakeAppQueryClient{apps: map[string]apptypes.Application{
+		"pokt1app1": {Address: "pokt1app1", DelegateeGatewayAddresses: []string{"pokt1gw"}},
+		"pokt1app2": {Address: "pokt1app2", DelegateeGatewayAddresses: []string{"pokt1gw"}},
+		"pokt1app3": {Address: "pokt1app3"},
+	}}
+	c := NewDelegatingApplicationsCache(&ApplicationClient{QueryClient: queryClient}, cache.Config{})
+
+	bus := NewEventBus()
+	bus.Subscribe(c.HandleEvent, EventDelegationChanged)
+
+	ctx := context.Background()
+	appAddresses, err := c.GetApplicationsDelegatingToGateway(ctx, "pokt1gw", 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"pokt1app1", "pokt1app2"}, appAddresses)
+
+	// The applications are only scanned once.
+	_, err = c.GetApplicationsDelegatingToGateway(ctx, "pokt1gw", 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), queryClient.allApplicationCalls.Load())
+
+	// The applications whose delegations changed are refreshed individually.
+	queryClient.apps["pokt1app3"] = apptypes.Application{Address: "pokt1app3", DelegateeGatewayAddresses: []string{"pokt1gw"}}
+	delete(queryClient.apps, "pokt1app1")
+	bus.Publish(DelegationChangedEvent{AppAddress: "pokt1app3", GatewayAddress: "pokt1gw", Delegated: true})
+	bus.Publish(DelegationChangedEvent{AppAddress: "pokt1app1", GatewayAddress: "pokt1gw"})
+
+	appAddresses, err = c.GetApplicationsDelegatingToGateway(ctx, "pokt1gw", 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"pokt1app2", "pokt1app3"}, appAddresses)
+	require.Equal(t, int64(1), queryClient.allApplicationCalls.Load())
+	require.Equal(t, int64(2), queryClient.applicationCalls.Load())
+
+	// The refreshed applications are kept until the next full scan.
+	appAddresses, err = c.GetApplicationsDelegatingToGateway(ctx, "pokt1gw", 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"pokt1app2", "pokt1app3"}, appAddresses)
+	require.Equal(t, int64(2), queryClient.applicationCalls.Load())
+}
+
+func TestDelegatingApplicationsCache_RefreshOutsideLock(t *testing.T) {
+	queryClient := &blockingAppQueryClient{
+		fakeAppQueryClient: &fakeAppQueryClient{apps: map[string]apptypes.Application{
+			"pokt1app1": {Address: "pokt1app1", DelegateeGatewayAddresses: []string{"pokt1gw"}},
+			"pokt1app2": {Address: "pokt1app2"},
+		}},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	c := NewDelegatingApplicationsCache(&ApplicationClient{QueryClient: queryClient}, cache.Config{})
+
+	ctx := context.Background()
+	_, err := c.GetApplicationsDelegatingToGateway(ctx, "pokt1gw", 10)
+	require.NoError(t, err)
+
+	c.Invalidate("pokt1app1")
+	result := make(chan error)
+	go func() {
+		_, err := c.GetApplicationsDelegatingToGateway(ctx, "pokt1gw", 10)
+		result <- err
+	}()
+
+	// The applications are invalidated while a refresh is in flight.
+	<-queryClient.started
+	queryClient.apps["pokt1app2"] = apptypes.Application{Address: "pokt1app2", DelegateeGatewayAddresses: []string{"pokt1gw"}}
+	c.Invalidate("pokt1app2")
+	close(queryClient.release)
+	require.NoError(t, <-result)
+
+	// The applications invalidated during the refresh are refreshed on the next call.
+	appAddresses, err := c.GetApplicationsDelegatingToGateway(ctx, "pokt1gw", 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"pokt1app1", "pokt1app2"}, appAddresses)
+	require.Equal(t, int64(2), queryClient.applicationCalls.Load())
+}
+
+// blockingAppQueryClient is a fakeAppQueryClient whose Application calls
+// signal started, and block until release is closed.
+type blockingAppQueryClient struct {
+	*fakeAppQueryClient
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (c *blockingAppQueryClient) Application(
+	ctx context.Context,
+	req *apptypes.QueryGetApplicationRequest,
+	opts ...grpcoptions.CallOption,
+) (*apptypes.QueryGetApplicationResponse, error) {
+	c.once.Do(func() { close(c.started) })
+	<-c.release
+	return c.fakeAppQueryClient.Application(ctx, req, opts...)
+}
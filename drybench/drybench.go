@@ -0,0 +1,378 @@
+// Package drybench measures the maximum sustainable throughput of the relay
+// pipeline's CPU-bound steps on the current machine: signing the relay requests
+// using an application's ring, and validating the suppliers' relay responses.
+//
+// No network is involved: the public keys are served from memory, and the relay
+// responses are signed upfront. The measured throughput is thus an upper bound
+// of a gateway instance's capacity, which operators can use to size their
+// instances before launch, e.g. for the ring size of their applications.
+//
+// It is importable, so gateways can dry-bench their own configuration, and is
+// also available as the cmd/drybench command.
+package drybench
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+
+	sdk "github.com/pokt-network/shannon-sdk"
+	"github.com/pokt-network/shannon-sdk/crypto"
+)
+
+const (
+	// defaultRingSize is the ring size used if none is specified: the
+	// application and a single gateway.
+	defaultRingSize = 2
+	// defaultPayloadSize is the size, in bytes, of the payloads if none is specified.
+	defaultPayloadSize = 1024
+	// defaultDuration is the duration of the measurement if none is specified.
+	defaultDuration = 10 * time.Second
+)
+
+// Config specifies a dry-bench.
+type Config struct {
+	// PrivateKeyHex is the hex-encoded private key signing the relays, e.g. the
+	// gateway's key. A key is generated if not set.
+	PrivateKeyHex string
+	// RingSize is the number of members of the application's ring, i.e. the
+	// application and the gateways it delegates to, including the signer.
+	// It must be at least 2, and defaults to 2.
+	RingSize int
+	// PayloadSize is the size, in bytes, of the relay request and response
+	// payloads. It defaults to 1KiB.
+	PayloadSize int
+	// Concurrency is the number of goroutines processing relays. It defaults to GOMAXPROCS.
+	Concurrency int
+	// Duration is the duration of the measurement. It defaults to 10 seconds.
+	Duration time.Duration
+}
+
+// LatencyStats summarizes the latencies of a step of the relay pipeline.
+type LatencyStats struct {
+	P50 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// Report summarizes the results of a dry-bench.
+type Report struct {
+	// Relays is the number of relays signed and validated.
+	Relays uint64
+	// Failures is the number of relays which failed to be signed or validated.
+	Failures uint64
+	// FirstErr is the first observed failure, if any.
+	FirstErr error
+	// Elapsed is the duration of the measurement.
+	Elapsed time.Duration
+	// OpsPerSec is the number of relays signed and validated per second.
+	OpsPerSec float64
+
+	// Sign is the latency of building and signing a relay request.
+	Sign LatencyStats
+	// Validate is the latency of validating a relay response.
+	Validate LatencyStats
+	// Total is the latency of the whole sign+validate pipeline.
+	Total LatencyStats
+}
+
+// String formats the report for display, e.g. by the cmd/drybench command.
+func (r Report) String() string {
+	return fmt.Sprintf(
+		"%d relays in %s: %.0f ops/sec, %d failures\n"+
+			"sign:     p50 %s, p99 %s, max %s\n"+
+			"validate: p50 %s, p99 %s, max %s\n"+
+			"total:    p50 %s, p99 %s, max %s",
+		r.Relays, r.Elapsed.Round(time.Millisecond), r.OpsPerSec, r.Failures,
+		r.Sign.P50, r.Sign.P99, r.Sign.Max,
+		r.Validate.P50, r.Validate.P99, r.Validate.Max,
+		r.Total.P50, r.Total.P99, r.Total.Max,
+	)
+}
+
+// Err returns an error if any relay failed to be signed or validated.
+func (r Report) Err() error {
+	if r.Failures == 0 {
+		return nil
+	}
+	return fmt.Errorf("dry-bench failed: %d of %d relays failed, first error: %w", r.Failures, r.Relays+r.Failures, r.FirstErr)
+}
+
+// Run runs the dry-bench specified by the given config, for the configured
+// duration or until the given context is done.
+func Run(ctx context.Context, config Config) (Report, error) {
+	pipeline, err := newPipeline(config)
+	if err != nil {
+		return Report{}, fmt.Errorf("Run: %w", err)
+	}
+
+	duration := config.Duration
+	if duration <= 0 {
+		duration = defaultDuration
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var (
+		wg           sync.WaitGroup
+		relayResults results
+	)
+	start := time.Now()
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var latencies workerLatencies
+			for ctx.Err() == nil {
+				signLatency, validateLatency, err := pipeline.relay(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						relayResults.recordFailure(err)
+					}
+					continue
+				}
+				latencies.sign = append(latencies.sign, signLatency)
+				latencies.validate = append(latencies.validate, validateLatency)
+				latencies.total = append(latencies.total, signLatency+validateLatency)
+			}
+			relayResults.merge(latencies)
+		}()
+	}
+	wg.Wait()
+
+	return relayResults.report(time.Since(start)), nil
+}
+
+// pipeline signs relay requests and validates relay responses, as a gateway does.
+type pipeline struct {
+	signer    *sdk.Signer
+	appRing   sdk.ApplicationRing
+	validator sdk.RelayResponseValidator
+	endpoint  sdk.Endpoint
+	payload   []byte
+
+	// relayResponseBz is the supplier's signed relay response.
+	relayResponseBz []byte
+}
+
+// newPipeline generates the keys of the application, gateways and supplier,
+// and the supplier's signed relay response.
+func newPipeline(config Config) (*pipeline, error) {
+	ringSize := config.RingSize
+	if ringSize == 0 {
+		ringSize = defaultRingSize
+	}
+	if ringSize < 2 {
+		return nil, fmt.Errorf("ring size %d is lower than 2", ringSize)
+	}
+	payloadSize := config.PayloadSize
+	if payloadSize <= 0 {
+		payloadSize = defaultPayloadSize
+	}
+
+	privateKeyHex := config.PrivateKeyHex
+	if privateKeyHex == "" {
+		privateKeyHex = crypto.PrivateKeyToHex(crypto.GeneratePrivateKey())
+	}
+	signerKey, err := crypto.PrivateKeyFromHex(privateKeyHex)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKeys := make(pubKeyFetcher)
+	signerAddress, err := pubKeys.add(signerKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	appAddress, err := pubKeys.add(secp256k1.GenPrivKeyFromSecret([]byte("drybench-app")).PubKey())
+	if err != nil {
+		return nil, err
+	}
+
+	app := apptypes.Application{Address: appAddress, DelegateeGatewayAddresses: []string{signerAddress}}
+	for i := range ringSize - 2 {
+		gatewayKey := secp256k1.GenPrivKeyFromSecret([]byte(fmt.Sprintf("drybench-gateway-%d", i)))
+		gatewayAddress, err := pubKeys.add(gatewayKey.PubKey())
+		if err != nil {
+			return nil, err
+		}
+		app.DelegateeGatewayAddresses = append(app.DelegateeGatewayAddresses, gatewayAddress)
+	}
+
+	supplierKey := secp256k1.GenPrivKeyFromSecret([]byte("drybench-supplier"))
+	supplierAddress, err := pubKeys.add(supplierKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+
+	header := sessiontypes.SessionHeader{
+		ApplicationAddress:      appAddress,
+		ServiceId:               "drybench",
+		SessionId:               "drybench-session",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   10,
+	}
+	payload := make([]byte, payloadSize)
+
+	relayResponse := &servicetypes.RelayResponse{
+		Meta:    servicetypes.RelayResponseMetadata{SessionHeader: &header},
+		Payload: payload,
+	}
+	signableBz, err := relayResponse.GetSignableBytesHash()
+	if err != nil {
+		return nil, fmt.Errorf("error getting signable bytes hash of the relay response: %w", err)
+	}
+	relayResponse.Meta.SupplierOperatorSignature, err = supplierKey.Sign(signableBz[:])
+	if err != nil {
+		return nil, fmt.Errorf("error signing the relay response: %w", err)
+	}
+	relayResponseBz, err := relayResponse.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling the relay response: %w", err)
+	}
+
+	return &pipeline{
+		signer:    &sdk.Signer{PrivateKeyHex: privateKeyHex},
+		appRing:   sdk.ApplicationRing{Application: app, PublicKeyFetcher: pubKeys},
+		validator: sdk.RelayResponseValidator{PublicKeyFetcher: pubKeys},
+		endpoint: sdk.NewEndpoint(
+			header,
+			sharedtypes.SupplierEndpoint{Url: "https://supplier.drybench.example", RpcType: sharedtypes.RPCType_JSON_RPC},
+			sdk.SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: supplierAddress}},
+		),
+		payload:         payload,
+		relayResponseBz: relayResponseBz,
+	}, nil
+}
+
+// relay signs a relay request and validates the supplier's relay response,
+// returning the latency of each step.
+func (p *pipeline) relay(ctx context.Context) (signLatency, validateLatency time.Duration, err error) {
+	start := time.Now()
+	relayRequest, err := sdk.BuildRelayRequest(p.endpoint, p.payload)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := p.signer.Sign(ctx, relayRequest, p.appRing); err != nil {
+		return 0, 0, err
+	}
+	signLatency = time.Since(start)
+
+	start = time.Now()
+	if _, err := p.validator.Validate(ctx, p.endpoint.Supplier(), p.relayResponseBz); err != nil {
+		return 0, 0, err
+	}
+	return signLatency, time.Since(start), nil
+}
+
+// pubKeyFetcher is a PublicKeyFetcher serving the public keys from memory.
+type pubKeyFetcher map[string]cryptotypes.PubKey
+
+// add adds the given public key, and returns its address.
+func (f pubKeyFetcher) add(pubKey cryptotypes.PubKey) (string, error) {
+	address, err := sdk.PubKeyToAddress(sdk.PoktAddressPrefix, pubKey)
+	if err != nil {
+		return "", err
+	}
+	f[address] = pubKey
+	return address, nil
+}
+
+// GetPubKeyFromAddress returns the public key of the given address.
+func (f pubKeyFetcher) GetPubKeyFromAddress(_ context.Context, address string) (cryptotypes.PubKey, error) {
+	pubKey, ok := f[address]
+	if !ok {
+		return nil, fmt.Errorf("public key of address %s not found", address)
+	}
+	return pubKey, nil
+}
+
+// workerLatencies holds the latencies of the relays processed by a goroutine.
+type workerLatencies struct {
+	sign     []time.Duration
+	validate []time.Duration
+	total    []time.Duration
+}
+
+// results accumulates the outcomes of the relays processed concurrently.
+type results struct {
+	mu        sync.Mutex
+	latencies workerLatencies
+	failures  uint64
+	firstErr  error
+}
+
+// recordFailure records a relay which failed to be signed or validated.
+func (r *results) recordFailure(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures++
+	if r.firstErr == nil {
+		r.firstErr = err
+	}
+}
+
+// merge merges the latencies of a goroutine's relays.
+func (r *results) merge(latencies workerLatencies) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.latencies.sign = append(r.latencies.sign, latencies.sign...)
+	r.latencies.validate = append(r.latencies.validate, latencies.validate...)
+	r.latencies.total = append(r.latencies.total, latencies.total...)
+}
+
+// report returns the report of the relays processed during the given duration.
+func (r *results) report(elapsed time.Duration) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	relays := uint64(len(r.latencies.total))
+	report := Report{
+		Relays:   relays,
+		Failures: r.failures,
+		FirstErr: r.firstErr,
+		Elapsed:  elapsed,
+		Sign:     latencyStats(r.latencies.sign),
+		Validate: latencyStats(r.latencies.validate),
+		Total:    latencyStats(r.latencies.total),
+	}
+	if elapsed > 0 {
+		report.OpsPerSec = float64(relays) / elapsed.Seconds()
+	}
+	return report
+}
+
+// latencyStats returns the stats of the given latencies, which it sorts.
+func latencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+
+	slices.Sort(latencies)
+	percentile := func(p float64) time.Duration {
+		return latencies[int(p*float64(len(latencies)-1))]
+	}
+	return LatencyStats{
+		P50: percentile(0.50),
+		P99: percentile(0.99),
+		Max: latencies[len(latencies)-1],
+	}
+}
@@ -0,0 +1,41 @@
+package drybench_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/crypto"
+	"github.com/pokt-network/shannon-sdk/drybench"
+)
+
+func TestRun(t *testing.T) {
+	report, err := drybench.Run(context.Background(), drybench.Config{
+		PrivateKeyHex: crypto.PrivateKeyToHex(crypto.GeneratePrivateKey()),
+		RingSize:      4,
+		PayloadSize:   256,
+		Concurrency:   2,
+		Duration:      200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, report.Err())
+	require.NotZero(t, report.Relays)
+	require.Positive(t, report.OpsPerSec)
+
+	for _, stats := range []drybench.LatencyStats{report.Sign, report.Validate, report.Total} {
+		require.Positive(t, stats.P50)
+		require.LessOrEqual(t, stats.P50, stats.P99)
+		require.LessOrEqual(t, stats.P99, stats.Max)
+	}
+	require.GreaterOrEqual(t, report.Total.Max, report.Sign.Max)
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	_, err := drybench.Run(context.Background(), drybench.Config{RingSize: 1})
+	require.ErrorContains(t, err, "ring size")
+
+	_, err = drybench.Run(context.Background(), drybench.Config{PrivateKeyHex: "not hex"})
+	require.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// sharedParamsTTL is the duration for which the shared module params are cached
+// by the EffectiveSessionResolver.
+const sharedParamsTTL = time.Minute
+
+// EffectiveSession is the session a relay should be signed against, as returned
+// by the EffectiveSessionResolver.
+type EffectiveSession struct {
+	SessionInfo
+
+	// IsPreviousSession is true if the session is the one preceding the session
+	// of the requested height, which is still within its grace period.
+	IsPreviousSession bool
+	// GracePeriodEndHeight is the last height at which relays can be sent for
+	// the session.
+	GracePeriodEndHeight int64
+}
+
+// EffectiveSessionResolver determines which session a relay should be signed
+// against, accounting for the grace period of the sessions.
+//
+// Once a session ends, its suppliers keep serving its relays until the end of
+// its grace period, as defined by the shared module params. Right after a session
+// rollover, the suppliers' view of the chain may lag behind the gateway's, so
+// relays signed against the new session may be rejected while the previous
+// session is still accepted.
+//
+// The current and previous sessions are cached in separate SessionCaches, so
+// resolving the previous session does not evict the current one.
+type EffectiveSessionResolver struct {
+	sessionCache         *SessionCache
+	previousSessionCache *SessionCache
+	sharedParamsQuerier  SharedParamsQuerier
+	sharedParamsCache    *cache.Cache[struct{}, sharedtypes.Params]
+}
+
+// NewEffectiveSessionResolver returns an EffectiveSessionResolver using the given
+// SessionCache for the current sessions, and the given shared module query client
+// to get the sessions' grace period.
+// The previous sessions are cached using the same SessionQuerier and cache
+// config as the given SessionCache.
+func NewEffectiveSessionResolver(
+	sessionCache *SessionCache,
+	sharedQueryClient sharedtypes.QueryClient,
+) *EffectiveSessionResolver {
+	var sharedParamsQuerier SharedParamsQuerier
+	if sharedQueryClient != nil {
+		sharedParamsQuerier = &SharedClient{QueryClient: sharedQueryClient}
+	}
+	return NewEffectiveSessionResolverFromQuerier(sessionCache, sharedParamsQuerier)
+}
+
+// NewEffectiveSessionResolverFromQuerier returns an EffectiveSessionResolver
+// using the given SessionCache for the current sessions, and the given
+// SharedParamsQuerier, e.g. a SharedClient, to get the sessions' grace period.
+func NewEffectiveSessionResolverFromQuerier(
+	sessionCache *SessionCache,
+	sharedParamsQuerier SharedParamsQuerier,
+) *EffectiveSessionResolver {
+	return &EffectiveSessionResolver{
+		sessionCache: sessionCache,
+		previousSessionCache: NewSessionCacheWithOptions(
+			sessionCache.sessionClient,
+			WithCacheConfig(sessionCache.config.cacheConfig),
+		),
+		sharedParamsQuerier: sharedParamsQuerier,
+		sharedParamsCache:   cache.New[struct{}, sharedtypes.Params](cache.Config{TTL: sharedParamsTTL}),
+	}
+}
+
+// ResolveEffectiveSession returns the session a relay sent at the given height,
+// for the given service and application, should be signed against.
+//
+// It returns the previous session if the given height is within its grace
+// period, i.e. at most GracePeriodEndOffsetBlocks blocks after its end, and the
+// session of the given height otherwise. The session of the given height is
+// also returned if the previous session can not be fetched.
+func (r *EffectiveSessionResolver) ResolveEffectiveSession(
+	ctx context.Context,
+	serviceId string,
+	appAddress string,
+	height int64,
+) (EffectiveSession, error) {
+	if r.sharedParamsQuerier == nil {
+		return EffectiveSession{}, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "ResolveEffectiveSession: shared query client not set")
+	}
+
+	sharedParams, _, err := r.sharedParamsCache.GetOrFetch(ctx, struct{}{}, r.sharedParamsQuerier.GetParams)
+	if err != nil {
+		return EffectiveSession{}, fmt.Errorf("ResolveEffectiveSession: error getting shared module params: %w", err)
+	}
+	gracePeriodBlocks := int64(sharedParams.GracePeriodEndOffsetBlocks)
+
+	currentSession, err := r.sessionCache.GetSession(ctx, appAddress, serviceId, height)
+	if err != nil {
+		return EffectiveSession{}, fmt.Errorf("ResolveEffectiveSession: %w", err)
+	}
+	if currentSession.Session == nil || currentSession.Header == nil {
+		return EffectiveSession{}, fmt.Errorf("ResolveEffectiveSession: got an empty session for app %s and service %s", appAddress, serviceId)
+	}
+
+	effectiveSession := EffectiveSession{
+		SessionInfo:          currentSession,
+		GracePeriodEndHeight: currentSession.Header.SessionEndBlockHeight + gracePeriodBlocks,
+	}
+
+	// The previous session's grace period ends gracePeriodBlocks blocks after
+	// its end height, i.e. the block preceding the current session's start.
+	previousEndHeight := currentSession.Header.SessionStartBlockHeight - 1
+	if previousEndHeight <= 0 || height > previousEndHeight+gracePeriodBlocks {
+		return effectiveSession, nil
+	}
+
+	previousSession, err := r.previousSessionCache.GetSession(ctx, appAddress, serviceId, previousEndHeight)
+	if err != nil || previousSession.Session == nil || previousSession.Header == nil {
+		// The current session is valid at the given height: fall back to it.
+		return effectiveSession, nil
+	}
+
+	return EffectiveSession{
+		SessionInfo:          previousSession,
+		IsPreviousSession:    true,
+		GracePeriodEndHeight: previousSession.Header.SessionEndBlockHeight + gracePeriodBlocks,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+func TestEffectiveSessionResolver_ResolveEffectiveSession(t *testing.T) {
+	sessionCache := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: &heightSessionFetcher{numBlocksPerSession: 10}},
+		WithCacheConfig(cache.Config{TTL: time.Minute}),
+	)
+	resolver := NewEffectiveSessionResolver(sessionCache, &fakeSharedQueryClient{
+		params: sharedtypes.Params{NumBlocksPerSession: 10, GracePeriodEndOffsetBlocks: 2},
+	})
+
+	tests := []struct {
+		desc                         string
+		height                       int64
+		expectedSessionStartHeight   int64
+		expectPreviousSession        bool
+		expectedGracePeriodEndHeight int64
+	}{
+		{
+			desc:                         "first session has no previous session",
+			height:                       5,
+			expectedSessionStartHeight:   0,
+			expectedGracePeriodEndHeight: 11,
+		},
+		{
+			desc:                         "first block of a session is within the previous session's grace period",
+			height:                       10,
+			expectedSessionStartHeight:   0,
+			expectPreviousSession:        true,
+			expectedGracePeriodEndHeight: 11,
+		},
+		{
+			desc:                         "last block of the previous session's grace period",
+			height:                       11,
+			expectedSessionStartHeight:   0,
+			expectPreviousSession:        true,
+			expectedGracePeriodEndHeight: 11,
+		},
+		{
+			desc:                         "previous session's grace period has ended",
+			height:                       12,
+			expectedSessionStartHeight:   10,
+			expectedGracePeriodEndHeight: 21,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			effectiveSession, err := resolver.ResolveEffectiveSession(context.Background(), "svc1", "app1", test.height)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedSessionStartHeight, effectiveSession.Header.SessionStartBlockHeight)
+			require.Equal(t, test.expectPreviousSession, effectiveSession.IsPreviousSession)
+			require.Equal(t, test.expectedGracePeriodEndHeight, effectiveSession.GracePeriodEndHeight)
+		})
+	}
+
+	// Resolving the previous session does not evict the current one.
+	sessionInfo, err := sessionCache.GetSession(context.Background(), "app1", "svc1", 12)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceCache, sessionInfo.Source)
+}
+
+// fakeSharedQueryClient is a shared module QueryClient returning fixed params.
+type fakeSharedQueryClient struct {
+	sharedtypes.QueryClient
+	params sharedtypes.Params
+}
+
+func (c *fakeSharedQueryClient) Params(
+	_ context.Context,
+	_ *sharedtypes.QueryParamsRequest,
+	_ ...grpcoptions.CallOption,
+) (*sharedtypes.QueryParamsResponse, error) {
+	return &sharedtypes.QueryParamsResponse{Params: c.params}, nil
+}
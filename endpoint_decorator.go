@@ -0,0 +1,207 @@
+package sdk
+
+import (
+	"net/http"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+)
+
+const (
+	// EndpointMetadataRegion is the metadata key of an endpoint's region, e.g. "us-east".
+	EndpointMetadataRegion = "region"
+	// EndpointMetadataScore is the metadata key of an endpoint's score, as
+	// computed by the gateway's endpoint selection logic.
+	EndpointMetadataScore = "score"
+)
+
+// EndpointDecorator wraps an Endpoint, overriding or extending its fields.
+type EndpointDecorator func(Endpoint) Endpoint
+
+// MetadataEndpoint is implemented by the endpoints which carry metadata,
+// e.g. the endpoints decorated using WithEndpointMetadata.
+type MetadataEndpoint interface {
+	Endpoint
+	Metadata(key string) (value string, ok bool)
+}
+
+// AuthEndpoint is implemented by the endpoints which carry authentication
+// headers, e.g. the endpoints decorated using WithEndpointAuthHeader.
+// The relay transport adds the headers to the relays sent to the endpoint.
+type AuthEndpoint interface {
+	Endpoint
+	AuthHeaders() http.Header
+}
+
+// NewEndpoint returns an Endpoint for the given supplier endpoint, served
+// within the session with the given header by the given supplier.
+// It allows building endpoints outside of a SessionFilter, e.g. in tests or
+// for custom endpoint implementations wrapping the default one.
+func NewEndpoint(
+	header sessiontypes.SessionHeader,
+	supplierEndpoint sharedtypes.SupplierEndpoint,
+	supplierInfo SupplierInfo,
+) Endpoint {
+	return endpoint{
+		header:           header,
+		supplierEndpoint: supplierEndpoint,
+		typed:            NewTypedSupplierEndpoint(supplierEndpoint),
+		supplier:         SupplierAddress(supplierInfo.OperatorAddress),
+		supplierInfo:     supplierInfo,
+	}
+}
+
+// DecorateEndpoint returns the given endpoint wrapped by the given decorators,
+// applied in order, i.e. the last decorator is the outermost one.
+func DecorateEndpoint(e Endpoint, decorators ...EndpointDecorator) Endpoint {
+	for _, decorator := range decorators {
+		e = decorator(e)
+	}
+	return e
+}
+
+// WithEndpointURL returns an EndpointDecorator which overrides the URL of the
+// endpoint, e.g. to route relays through a proxy or a private network address.
+// The URL is parsed and validated once, when the decorator is applied.
+func WithEndpointURL(url string) EndpointDecorator {
+	return func(e Endpoint) Endpoint {
+		supplierEndpoint := e.Endpoint()
+		supplierEndpoint.Url = url
+		return urlEndpoint{
+			wrappedEndpoint: wrappedEndpoint{inner: e},
+			url:             url,
+			typed:           NewTypedSupplierEndpoint(supplierEndpoint),
+		}
+	}
+}
+
+// WithEndpointMetadata returns an EndpointDecorator which sets the given
+// metadata key to the given value, e.g. EndpointMetadataRegion.
+func WithEndpointMetadata(key, value string) EndpointDecorator {
+	return func(e Endpoint) Endpoint {
+		return metadataEndpoint{wrappedEndpoint: wrappedEndpoint{inner: e}, key: key, value: value}
+	}
+}
+
+// WithEndpointAuthHeader returns an EndpointDecorator which adds the given
+// header to the relays sent to the endpoint.
+func WithEndpointAuthHeader(key, value string) EndpointDecorator {
+	return func(e Endpoint) Endpoint {
+		return authEndpoint{wrappedEndpoint: wrappedEndpoint{inner: e}, key: key, value: value}
+	}
+}
+
+// GetEndpointMetadata returns the value of the given metadata key of the given
+// endpoint, and a boolean indicating whether the key is set.
+func GetEndpointMetadata(e Endpoint, key string) (string, bool) {
+	withMetadata, ok := e.(MetadataEndpoint)
+	if !ok {
+		return "", false
+	}
+	return withMetadata.Metadata(key)
+}
+
+// GetEndpointAuthHeaders returns the authentication headers of the given
+// endpoint, or an empty header if it carries none.
+func GetEndpointAuthHeaders(e Endpoint) http.Header {
+	withAuth, ok := e.(AuthEndpoint)
+	if !ok {
+		return http.Header{}
+	}
+	return withAuth.AuthHeaders()
+}
+
+// wrappedEndpoint forwards all the methods of an Endpoint, including the
+// optional Metadata and AuthHeaders methods, to the wrapped endpoint.
+// It is embedded by the decorators, which only override the relevant methods.
+type wrappedEndpoint struct {
+	inner Endpoint
+}
+
+// Header returns the session header of the wrapped endpoint.
+func (e wrappedEndpoint) Header() sessiontypes.SessionHeader {
+	return e.inner.Header()
+}
+
+// Supplier returns the supplier address of the wrapped endpoint.
+func (e wrappedEndpoint) Supplier() SupplierAddress {
+	return e.inner.Supplier()
+}
+
+// Endpoint returns the supplier endpoint of the wrapped endpoint.
+func (e wrappedEndpoint) Endpoint() sharedtypes.SupplierEndpoint {
+	return e.inner.Endpoint()
+}
+
+// SupplierInfo returns the supplier metadata of the wrapped endpoint.
+func (e wrappedEndpoint) SupplierInfo() SupplierInfo {
+	return e.inner.SupplierInfo()
+}
+
+// Typed returns the parsed supplier endpoint of the wrapped endpoint.
+func (e wrappedEndpoint) Typed() TypedSupplierEndpoint {
+	return GetTypedEndpoint(e.inner)
+}
+
+// Metadata returns the metadata of the wrapped endpoint.
+func (e wrappedEndpoint) Metadata(key string) (string, bool) {
+	return GetEndpointMetadata(e.inner, key)
+}
+
+// AuthHeaders returns the authentication headers of the wrapped endpoint.
+func (e wrappedEndpoint) AuthHeaders() http.Header {
+	return GetEndpointAuthHeaders(e.inner)
+}
+
+// urlEndpoint is an Endpoint decorator overriding the URL of the wrapped endpoint.
+type urlEndpoint struct {
+	wrappedEndpoint
+	url   string
+	typed TypedSupplierEndpoint
+}
+
+// Endpoint returns the supplier endpoint of the wrapped endpoint, with its URL overridden.
+func (e urlEndpoint) Endpoint() sharedtypes.SupplierEndpoint {
+	supplierEndpoint := e.inner.Endpoint()
+	supplierEndpoint.Url = e.url
+	return supplierEndpoint
+}
+
+// Typed returns the parsed supplier endpoint of the wrapped endpoint, with its URL overridden.
+func (e urlEndpoint) Typed() TypedSupplierEndpoint {
+	return e.typed
+}
+
+// metadataEndpoint is an Endpoint decorator setting a single metadata key.
+type metadataEndpoint struct {
+	wrappedEndpoint
+	key   string
+	value string
+}
+
+// Metadata returns the value of the given key, falling back to the metadata
+// of the wrapped endpoint for any other key.
+func (e metadataEndpoint) Metadata(key string) (string, bool) {
+	if key == e.key {
+		return e.value, true
+	}
+	return GetEndpointMetadata(e.inner, key)
+}
+
+// authEndpoint is an Endpoint decorator adding a single authentication header.
+type authEndpoint struct {
+	wrappedEndpoint
+	key   string
+	value string
+}
+
+// AuthHeaders returns the authentication headers of the wrapped endpoint,
+// with the decorator's header added.
+func (e authEndpoint) AuthHeaders() http.Header {
+	headers := GetEndpointAuthHeaders(e.inner).Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Add(e.key, e.value)
+	return headers
+}
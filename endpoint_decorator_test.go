@@ -0,0 +1,81 @@
+package sdk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecorateEndpoint(t *testing.T) {
+	baseEndpoint := NewEndpoint(
+		sessiontypes.SessionHeader{ServiceId: "svc1"},
+		sharedtypes.SupplierEndpoint{Url: "https://supplier.example", RpcType: sharedtypes.RPCType_JSON_RPC},
+		SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: "pokt1supplier"}},
+	)
+
+	decorated := DecorateEndpoint(
+		baseEndpoint,
+		WithEndpointMetadata(EndpointMetadataRegion, "eu-west"),
+		WithEndpointAuthHeader("Authorization", "Bearer token"),
+		WithEndpointURL("http://proxy.internal"),
+		WithEndpointMetadata(EndpointMetadataScore, "0.9"),
+	)
+
+	require.Equal(t, "http://proxy.internal", decorated.Endpoint().Url)
+	require.Equal(t, sharedtypes.RPCType_JSON_RPC, decorated.Endpoint().RpcType)
+	require.Equal(t, SupplierAddress("pokt1supplier"), decorated.Supplier())
+	require.Equal(t, "svc1", decorated.Header().ServiceId)
+
+	region, ok := GetEndpointMetadata(decorated, EndpointMetadataRegion)
+	require.True(t, ok)
+	require.Equal(t, "eu-west", region)
+
+	score, ok := GetEndpointMetadata(decorated, EndpointMetadataScore)
+	require.True(t, ok)
+	require.Equal(t, "0.9", score)
+
+	_, ok = GetEndpointMetadata(decorated, "unknown")
+	require.False(t, ok)
+
+	require.Equal(t, "Bearer token", GetEndpointAuthHeaders(decorated).Get("Authorization"))
+	require.Empty(t, GetEndpointAuthHeaders(baseEndpoint))
+
+	require.False(t, ByRegionHint("eu")(decorated))
+	require.True(t, ByRegionHint("eu")(baseEndpoint))
+}
+
+func TestNewHTTPRelaySender_UsesDecoratedEndpoint(t *testing.T) {
+	var receivedAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	decorated := DecorateEndpoint(
+		NewEndpoint(
+			sessiontypes.SessionHeader{},
+			sharedtypes.SupplierEndpoint{Url: "http://unreachable.invalid"},
+			SupplierInfo{},
+		),
+		WithEndpointURL(server.URL),
+		WithEndpointAuthHeader("Authorization", "Bearer token"),
+	)
+
+	relayRequest := &servicetypes.RelayRequest{Payload: []byte("payload")}
+	responseBz, err := NewHTTPRelaySender(nil)(context.Background(), decorated, relayRequest)
+	require.NoError(t, err)
+
+	expectedBz, err := relayRequest.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, expectedBz, responseBz)
+	require.Equal(t, "Bearer token", receivedAuth)
+}
@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"context"
+	"slices"
+)
+
+// EndpointExclusions lists the suppliers and the endpoints excluded for a
+// single request, e.g. because the end client already failed against them
+// upstream, so the gateway and the SDK cooperate when retrying.
+type EndpointExclusions struct {
+	// Suppliers are the addresses of the suppliers whose endpoints are excluded.
+	Suppliers []SupplierAddress
+	// EndpointURLs are the URLs of the excluded endpoints, as returned by the
+	// Endpoint method of the endpoints, i.e. after decoration.
+	EndpointURLs []string
+}
+
+// IsEmpty returns true if no supplier or endpoint is excluded.
+func (x EndpointExclusions) IsEmpty() bool {
+	return len(x.Suppliers) == 0 && len(x.EndpointURLs) == 0
+}
+
+// Filter returns an EndpointFilter which filters out the excluded endpoints.
+func (x EndpointExclusions) Filter() EndpointFilter {
+	return func(e Endpoint) bool {
+		return slices.Contains(x.Suppliers, e.Supplier()) || slices.Contains(x.EndpointURLs, e.Endpoint().Url)
+	}
+}
+
+// endpointExclusionsKey is the context key of the endpoint exclusions of a request.
+type endpointExclusionsKey struct{}
+
+// ContextWithEndpointExclusions returns a copy of the given context carrying the
+// given exclusions, in addition to the ones it already carries.
+// They are honored by SessionFilter.SelectEndpoint, and so by the GatewayClient
+// when relaying using the context.
+func ContextWithEndpointExclusions(ctx context.Context, exclusions EndpointExclusions) context.Context {
+	existing := EndpointExclusionsFromContext(ctx)
+	return context.WithValue(ctx, endpointExclusionsKey{}, EndpointExclusions{
+		Suppliers:    slices.Concat(existing.Suppliers, exclusions.Suppliers),
+		EndpointURLs: slices.Concat(existing.EndpointURLs, exclusions.EndpointURLs),
+	})
+}
+
+// EndpointExclusionsFromContext returns the endpoint exclusions carried by the
+// given context, which are empty if it carries none.
+func EndpointExclusionsFromContext(ctx context.Context) EndpointExclusions {
+	exclusions, _ := ctx.Value(endpointExclusionsKey{}).(EndpointExclusions)
+	return exclusions
+}
+
+// FilteredEndpointsOption is a functional option used to customize the endpoints
+// returned by SessionFilter.FilteredEndpoints for a single request.
+type FilteredEndpointsOption func(*filteredEndpointsOptions)
+
+// filteredEndpointsOptions holds the options of a call to FilteredEndpoints.
+type filteredEndpointsOptions struct {
+	exclusions EndpointExclusions
+}
+
+// WithEndpointExclusions excludes the given suppliers and endpoints from the
+// endpoints returned by FilteredEndpoints.
+func WithEndpointExclusions(exclusions EndpointExclusions) FilteredEndpointsOption {
+	return func(o *filteredEndpointsOptions) {
+		o.exclusions.Suppliers = append(o.exclusions.Suppliers, exclusions.Suppliers...)
+		o.exclusions.EndpointURLs = append(o.exclusions.EndpointURLs, exclusions.EndpointURLs...)
+	}
+}
@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionFilter_EndpointExclusions(t *testing.T) {
+	session := &sessiontypes.Session{
+		SessionId: "session1",
+		Header:    &sessiontypes.SessionHeader{ServiceId: "svc1", SessionId: "session1"},
+	}
+	for _, supplier := range []string{"pokt1supplier1", "pokt1supplier2", "pokt1supplier3"} {
+		session.Suppliers = append(session.Suppliers, &sharedtypes.Supplier{
+			OperatorAddress: supplier,
+			Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://" + supplier + ".example"}},
+			}},
+		})
+	}
+	filter := SessionFilter{Session: session}
+
+	endpoints, err := filter.FilteredEndpoints(WithEndpointExclusions(EndpointExclusions{
+		Suppliers:    []SupplierAddress{"pokt1supplier1"},
+		EndpointURLs: []string{"https://pokt1supplier2.example"},
+	}))
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	require.Equal(t, SupplierAddress("pokt1supplier3"), endpoints[0].Supplier())
+
+	// The exclusions carried by the context are accumulated, and honored by
+	// SelectEndpoint.
+	ctx := ContextWithEndpointExclusions(context.Background(), EndpointExclusions{Suppliers: []SupplierAddress{"pokt1supplier1"}})
+	ctx = ContextWithEndpointExclusions(ctx, EndpointExclusions{Suppliers: []SupplierAddress{"pokt1supplier3"}})
+	for range 10 {
+		selected, err := filter.SelectEndpoint(ctx)
+		require.NoError(t, err)
+		require.Equal(t, SupplierAddress("pokt1supplier2"), selected.Supplier())
+	}
+
+	ctx = ContextWithEndpointExclusions(ctx, EndpointExclusions{EndpointURLs: []string{"https://pokt1supplier2.example"}})
+	_, err = filter.SelectEndpoint(ctx)
+	require.Error(t, err)
+
+	// The exclusions only apply to the request.
+	endpoints, err = filter.FilteredEndpoints()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 3)
+}
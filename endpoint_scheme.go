@@ -0,0 +1,46 @@
+package sdk
+
+// plaintextSchemeUpgrades maps the plaintext endpoint URL schemes to their TLS counterparts.
+var plaintextSchemeUpgrades = map[string]string{
+	"http": "https",
+	"ws":   "wss",
+}
+
+// EndpointSchemePolicy controls the use of supplier endpoints with a plaintext
+// URL scheme, i.e. "http" or "ws", during endpoint selection.
+// The zero value permits plaintext endpoints as-is.
+type EndpointSchemePolicy struct {
+	// UpgradePlaintext, if set, upgrades the URLs of the plaintext endpoints to
+	// their TLS counterpart, i.e. "https" or "wss".
+	UpgradePlaintext bool
+	// RejectPlaintext, if set, excludes the plaintext endpoints.
+	// If UpgradePlaintext is also set, the endpoints are upgraded instead.
+	RejectPlaintext bool
+}
+
+// Apply returns the given endpoint with the policy applied, and false if the
+// endpoint is excluded by the policy.
+// Endpoints with a URL that can not be parsed are excluded if RejectPlaintext is set.
+func (p EndpointSchemePolicy) Apply(e Endpoint) (Endpoint, bool) {
+	if !p.UpgradePlaintext && !p.RejectPlaintext {
+		return e, true
+	}
+
+	typed := GetTypedEndpoint(e)
+	if typed.URL == nil {
+		return e, !p.RejectPlaintext
+	}
+
+	upgradedScheme, isPlaintext := plaintextSchemeUpgrades[typed.Scheme]
+	if !isPlaintext {
+		return e, true
+	}
+
+	if p.UpgradePlaintext {
+		endpointURL := *typed.URL
+		endpointURL.Scheme = upgradedScheme
+		return DecorateEndpoint(e, WithEndpointURL(endpointURL.String())), true
+	}
+
+	return e, false
+}
@@ -0,0 +1,98 @@
+package sdk
+
+import (
+	"testing"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointSchemePolicy_Apply(t *testing.T) {
+	tests := []struct {
+		desc            string
+		policy          EndpointSchemePolicy
+		url             string
+		expectPermitted bool
+		expectedURL     string
+	}{
+		{
+			desc:            "zero policy permits plaintext endpoints",
+			url:             "http://supplier.example",
+			expectPermitted: true,
+			expectedURL:     "http://supplier.example",
+		},
+		{
+			desc:            "plaintext endpoint rejected",
+			policy:          EndpointSchemePolicy{RejectPlaintext: true},
+			url:             "http://supplier.example",
+			expectPermitted: false,
+		},
+		{
+			desc:            "TLS endpoint permitted when rejecting plaintext",
+			policy:          EndpointSchemePolicy{RejectPlaintext: true},
+			url:             "https://supplier.example",
+			expectPermitted: true,
+			expectedURL:     "https://supplier.example",
+		},
+		{
+			desc:            "plaintext HTTP endpoint upgraded",
+			policy:          EndpointSchemePolicy{UpgradePlaintext: true, RejectPlaintext: true},
+			url:             "http://supplier.example:8545/path",
+			expectPermitted: true,
+			expectedURL:     "https://supplier.example:8545/path",
+		},
+		{
+			desc:            "plaintext websocket endpoint upgraded",
+			policy:          EndpointSchemePolicy{UpgradePlaintext: true},
+			url:             "ws://supplier.example",
+			expectPermitted: true,
+			expectedURL:     "wss://supplier.example",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			e := NewEndpoint(
+				sessiontypes.SessionHeader{},
+				sharedtypes.SupplierEndpoint{Url: test.url},
+				SupplierInfo{},
+			)
+
+			applied, permitted := test.policy.Apply(e)
+			require.Equal(t, test.expectPermitted, permitted)
+			if permitted {
+				require.Equal(t, test.expectedURL, applied.Endpoint().Url)
+			}
+		})
+	}
+}
+
+func TestSessionFilter_SchemePolicy(t *testing.T) {
+	session := &sessiontypes.Session{
+		Header: &sessiontypes.SessionHeader{ServiceId: "svc1"},
+		Suppliers: []*sharedtypes.Supplier{
+			{
+				OperatorAddress: "pokt1supplier",
+				Services: []*sharedtypes.SupplierServiceConfig{
+					{
+						ServiceId: "svc1",
+						Endpoints: []*sharedtypes.SupplierEndpoint{
+							{Url: "http://plaintext.example"},
+							{Url: "https://tls.example"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	sessionFilter := SessionFilter{
+		Session:      session,
+		SchemePolicy: &EndpointSchemePolicy{RejectPlaintext: true},
+	}
+	endpoints, err := sessionFilter.FilteredEndpoints()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	require.Equal(t, "https://tls.example", endpoints[0].Endpoint().Url)
+}
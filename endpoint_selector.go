@@ -0,0 +1,245 @@
+package sdk
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultEndpointStatsDecay is the weight of the latest observation in the
+// EWMAs of an EndpointStats if no decay is specified.
+const defaultEndpointStatsDecay = 0.2
+
+// EndpointSelector selects the endpoint to relay to among the filtered
+// endpoints of a session. Select returns nil if no endpoint is given.
+//
+// The selectors built by NewRandomSelector, NewRoundRobinSelector,
+// NewLatencyWeightedSelector and NewLeastErrorsSelector cover the common
+// strategies, and can be set as the Selector of a SessionFilter.
+type EndpointSelector interface {
+	Select(ctx context.Context, endpoints []Endpoint) Endpoint
+}
+
+// EndpointSelectorFunc is an adapter allowing the use of a function as an
+// EndpointSelector.
+type EndpointSelectorFunc func(ctx context.Context, endpoints []Endpoint) Endpoint
+
+// Select returns f(ctx, endpoints).
+func (f EndpointSelectorFunc) Select(ctx context.Context, endpoints []Endpoint) Endpoint {
+	return f(ctx, endpoints)
+}
+
+// RelayOutcomeObserver is notified of the outcome of the relays sent to the
+// endpoints, e.g. to track their latencies and error rates.
+type RelayOutcomeObserver interface {
+	// ObserveRelayOutcome records the outcome of a relay sent to the given
+	// endpoint, which took the given latency and failed with the given error,
+	// if not nil.
+	ObserveRelayOutcome(endpoint Endpoint, latency time.Duration, err error)
+}
+
+// NewRandomSelector returns an EndpointSelector selecting an endpoint uniformly
+// at random. It is the default selector of a SessionFilter.
+func NewRandomSelector() EndpointSelector {
+	return EndpointSelectorFunc(func(_ context.Context, endpoints []Endpoint) Endpoint {
+		if len(endpoints) == 0 {
+			return nil
+		}
+		return endpoints[rand.IntN(len(endpoints))]
+	})
+}
+
+// NewRoundRobinSelector returns an EndpointSelector cycling through the given
+// endpoints, ordered by supplier and URL, so consecutive selections among the
+// same endpoints spread the relays evenly.
+// It is safe for concurrent use.
+func NewRoundRobinSelector() EndpointSelector {
+	var next atomic.Uint64
+	return EndpointSelectorFunc(func(_ context.Context, endpoints []Endpoint) Endpoint {
+		if len(endpoints) == 0 {
+			return nil
+		}
+
+		// The endpoints are sorted, since the order of the filtered endpoints of a
+		// session is not stable.
+		sorted := slices.SortedFunc(slices.Values(endpoints), func(a, b Endpoint) int {
+			return strings.Compare(latencyKey(a), latencyKey(b))
+		})
+		return sorted[(next.Add(1)-1)%uint64(len(sorted))]
+	})
+}
+
+// NewLatencyWeightedSelector returns an EndpointSelector selecting an endpoint
+// at random, weighted by the inverse of its latency EWMA tracked by the given
+// EndpointStats, so faster endpoints receive proportionally more relays while
+// slower ones are still sampled.
+//
+// The endpoints without a latency yet are weighted as the fastest endpoint, so
+// they are explored.
+func NewLatencyWeightedSelector(stats *EndpointStats) EndpointSelector {
+	return EndpointSelectorFunc(func(_ context.Context, endpoints []Endpoint) Endpoint {
+		if len(endpoints) == 0 {
+			return nil
+		}
+
+		latencies := make([]time.Duration, len(endpoints))
+		var fastest time.Duration
+		for i, e := range endpoints {
+			stat, ok := stats.Stats(e)
+			if !ok || stat.LatencyEWMA <= 0 {
+				continue
+			}
+			latencies[i] = stat.LatencyEWMA
+			if fastest == 0 || stat.LatencyEWMA < fastest {
+				fastest = stat.LatencyEWMA
+			}
+		}
+		if fastest == 0 {
+			return endpoints[rand.IntN(len(endpoints))]
+		}
+
+		weights := make([]float64, len(endpoints))
+		var totalWeight float64
+		for i, latency := range latencies {
+			if latency == 0 {
+				latency = fastest
+			}
+			weights[i] = 1 / latency.Seconds()
+			totalWeight += weights[i]
+		}
+
+		target := rand.Float64() * totalWeight
+		for i, weight := range weights {
+			if target < weight {
+				return endpoints[i]
+			}
+			target -= weight
+		}
+		return endpoints[len(endpoints)-1]
+	})
+}
+
+// NewLeastErrorsSelector returns an EndpointSelector selecting, at random, one
+// of the endpoints with the lowest error rate EWMA tracked by the given
+// EndpointStats. The endpoints without observations have an error rate of zero.
+func NewLeastErrorsSelector(stats *EndpointStats) EndpointSelector {
+	return EndpointSelectorFunc(func(_ context.Context, endpoints []Endpoint) Endpoint {
+		var (
+			candidates []Endpoint
+			lowestRate float64
+		)
+		for _, e := range endpoints {
+			stat, _ := stats.Stats(e)
+			switch {
+			case len(candidates) == 0 || stat.ErrorRateEWMA < lowestRate:
+				candidates = append(candidates[:0], e)
+				lowestRate = stat.ErrorRateEWMA
+			case stat.ErrorRateEWMA == lowestRate:
+				candidates = append(candidates, e)
+			}
+		}
+
+		if len(candidates) == 0 {
+			return nil
+		}
+		return candidates[rand.IntN(len(candidates))]
+	})
+}
+
+// EndpointStat holds the statistics of the relays sent to an endpoint.
+type EndpointStat struct {
+	// LatencyEWMA is the exponentially weighted moving average of the latencies
+	// of the successful relays, or zero if none succeeded.
+	LatencyEWMA time.Duration
+	// ErrorRateEWMA is the exponentially weighted moving average of the relays'
+	// failures, between 0 (no recent failure) and 1 (only recent failures).
+	ErrorRateEWMA float64
+	// Observations is the number of relays observed.
+	Observations int64
+}
+
+// EndpointStatsOption is a functional option used to configure an EndpointStats.
+type EndpointStatsOption func(*EndpointStats)
+
+// WithEndpointStatsDecay sets the weight, between 0 and 1, of the latest
+// observation in the moving averages: the higher, the faster they react to
+// changes. It defaults to 0.2.
+func WithEndpointStatsDecay(decay float64) EndpointStatsOption {
+	return func(s *EndpointStats) {
+		if decay > 0 && decay <= 1 {
+			s.decay = decay
+		}
+	}
+}
+
+// EndpointStats tracks the exponentially weighted moving averages of the
+// latencies and error rates of the relays sent to each endpoint, e.g. for the
+// selectors built by NewLatencyWeightedSelector and NewLeastErrorsSelector.
+//
+// It implements RelayOutcomeObserver, and can be set as the
+// RelayOutcomeObserver of a GatewayClient.
+// It is safe for concurrent use.
+type EndpointStats struct {
+	decay float64
+
+	mu    sync.Mutex
+	stats map[string]EndpointStat
+}
+
+// NewEndpointStats returns an EndpointStats configured using the given options.
+func NewEndpointStats(opts ...EndpointStatsOption) *EndpointStats {
+	s := &EndpointStats{
+		decay: defaultEndpointStatsDecay,
+		stats: make(map[string]EndpointStat),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ObserveRelayOutcome records the outcome of a relay sent to the given endpoint.
+// The latency of the failed relays is not recorded, since they may have failed
+// before reaching the endpoint.
+func (s *EndpointStats) ObserveRelayOutcome(endpoint Endpoint, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := latencyKey(endpoint)
+	stat := s.stats[key]
+
+	failure := 0.0
+	if err != nil {
+		failure = 1
+	}
+	if stat.Observations == 0 {
+		stat.ErrorRateEWMA = failure
+	} else {
+		stat.ErrorRateEWMA += s.decay * (failure - stat.ErrorRateEWMA)
+	}
+
+	if err == nil {
+		if stat.LatencyEWMA == 0 {
+			stat.LatencyEWMA = latency
+		} else {
+			stat.LatencyEWMA += time.Duration(s.decay * float64(latency-stat.LatencyEWMA))
+		}
+	}
+
+	stat.Observations++
+	s.stats[key] = stat
+}
+
+// Stats returns the statistics of the given endpoint, or false if no relay to
+// the endpoint was observed.
+func (s *EndpointStats) Stats(endpoint Endpoint) (EndpointStat, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat, ok := s.stats[latencyKey(endpoint)]
+	return stat, ok
+}
@@ -0,0 +1,135 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointSelectors(t *testing.T) {
+	newEndpoint := func(supplier string) Endpoint {
+		return endpoint{
+			supplierEndpoint: sharedtypes.SupplierEndpoint{Url: "https://" + supplier + ".example"},
+			supplier:         SupplierAddress(supplier),
+		}
+	}
+	fast, slow, failing := newEndpoint("fast"), newEndpoint("slow"), newEndpoint("failing")
+	endpoints := []Endpoint{fast, slow, failing}
+	ctx := context.Background()
+
+	stats := NewEndpointStats(WithEndpointStatsDecay(0.5))
+	for range 5 {
+		stats.ObserveRelayOutcome(fast, 10*time.Millisecond, nil)
+		stats.ObserveRelayOutcome(slow, time.Second, nil)
+		stats.ObserveRelayOutcome(failing, time.Millisecond, errors.New("relay failed"))
+	}
+
+	t.Run("random", func(t *testing.T) {
+		require.Contains(t, endpoints, NewRandomSelector().Select(ctx, endpoints))
+		require.Nil(t, NewRandomSelector().Select(ctx, nil))
+	})
+
+	t.Run("round-robin", func(t *testing.T) {
+		selector := NewRoundRobinSelector()
+		selections := make(map[SupplierAddress]int)
+		var previous Endpoint
+		for range 30 {
+			// The order of the given endpoints does not matter.
+			shuffled := append([]Endpoint(nil), endpoints...)
+			rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+			selected := selector.Select(ctx, shuffled)
+			require.NotEqual(t, previous, selected)
+			selections[selected.Supplier()]++
+			previous = selected
+		}
+		require.Equal(t, map[SupplierAddress]int{"fast": 10, "slow": 10, "failing": 10}, selections)
+	})
+
+	t.Run("latency-weighted", func(t *testing.T) {
+		selector := NewLatencyWeightedSelector(stats)
+		selections := make(map[SupplierAddress]int)
+		for range 1000 {
+			selections[selector.Select(ctx, []Endpoint{fast, slow}).Supplier()]++
+		}
+		require.Greater(t, selections["fast"], 900)
+		require.Positive(t, selections["slow"])
+
+		// The endpoints without latency are explored as if they were the fastest.
+		unknown := newEndpoint("unknown")
+		selections = make(map[SupplierAddress]int)
+		for range 1000 {
+			selections[selector.Select(ctx, []Endpoint{fast, unknown}).Supplier()]++
+		}
+		require.Greater(t, selections["unknown"], 300)
+		require.Greater(t, selections["fast"], 300)
+	})
+
+	t.Run("least-errors", func(t *testing.T) {
+		selector := NewLeastErrorsSelector(stats)
+		for range 100 {
+			require.NotEqual(t, failing, selector.Select(ctx, endpoints))
+		}
+		require.Equal(t, failing, selector.Select(ctx, []Endpoint{failing}))
+		require.Nil(t, selector.Select(ctx, nil))
+	})
+}
+
+func TestEndpointStats(t *testing.T) {
+	e := endpoint{supplierEndpoint: sharedtypes.SupplierEndpoint{Url: "https://supplier.example"}, supplier: "pokt1supplier"}
+	stats := NewEndpointStats(WithEndpointStatsDecay(0.5))
+
+	_, ok := stats.Stats(e)
+	require.False(t, ok)
+
+	stats.ObserveRelayOutcome(e, 100*time.Millisecond, nil)
+	stats.ObserveRelayOutcome(e, 200*time.Millisecond, nil)
+	// The latency of the failed relays is not recorded.
+	stats.ObserveRelayOutcome(e, time.Millisecond, errors.New("relay failed"))
+
+	stat, ok := stats.Stats(e)
+	require.True(t, ok)
+	require.Equal(t, EndpointStat{LatencyEWMA: 150 * time.Millisecond, ErrorRateEWMA: 0.5, Observations: 3}, stat)
+}
+
+func TestSessionFilter_SelectEndpoint(t *testing.T) {
+	session := &sessiontypes.Session{
+		SessionId: "session1",
+		Header:    &sessiontypes.SessionHeader{ServiceId: "svc1", SessionId: "session1"},
+	}
+	for _, supplier := range []string{"pokt1supplier1", "pokt1supplier2"} {
+		session.Suppliers = append(session.Suppliers, &sharedtypes.Supplier{
+			OperatorAddress: supplier,
+			Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://" + supplier + ".example"}},
+			}},
+		})
+	}
+	ctx := context.Background()
+
+	// An endpoint is selected at random by default.
+	filter := SessionFilter{Session: session}
+	selected, err := filter.SelectEndpoint(ctx)
+	require.NoError(t, err)
+	require.Contains(t, []SupplierAddress{"pokt1supplier1", "pokt1supplier2"}, selected.Supplier())
+
+	// The selector only selects among the filtered endpoints.
+	filter.EndpointFilters = []EndpointFilter{BySupplierAllowlist("pokt1supplier2")}
+	filter.Selector = NewRoundRobinSelector()
+	for range 3 {
+		selected, err = filter.SelectEndpoint(ctx)
+		require.NoError(t, err)
+		require.Equal(t, SupplierAddress("pokt1supplier2"), selected.Supplier())
+	}
+
+	filter.EndpointFilters = []EndpointFilter{BySupplierAllowlist("pokt1unknown")}
+	_, err = filter.SelectEndpoint(ctx)
+	require.Error(t, err)
+}
@@ -0,0 +1,309 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+const (
+	// defaultEndpointStoreWindowSize is the number of latest relay outcomes of
+	// each endpoint kept by an EndpointStore if no window size is specified.
+	defaultEndpointStoreWindowSize = 100
+	// defaultEndpointStoreMinObservations is the minimum number of relay
+	// outcomes of an endpoint before it can be sanctioned, if none is specified.
+	defaultEndpointStoreMinObservations = 10
+	// defaultEndpointMaxErrorRate is the error rate above which an endpoint is
+	// sanctioned if no threshold is specified.
+	defaultEndpointMaxErrorRate = 0.5
+	// defaultEndpointSanctionCooldown is the duration of the sanctions if none
+	// is specified.
+	defaultEndpointSanctionCooldown = 5 * time.Minute
+)
+
+// RelayOutcome classifies the outcome of a relay sent to an endpoint.
+type RelayOutcome int
+
+const (
+	// RelayOutcomeSuccess is the outcome of the relays which succeeded.
+	RelayOutcomeSuccess RelayOutcome = iota
+	// RelayOutcomeTimeout is the outcome of the relays which timed out.
+	RelayOutcomeTimeout
+	// RelayOutcomeInvalidSignature is the outcome of the relays whose response
+	// signature could not be verified.
+	RelayOutcomeInvalidSignature
+	// RelayOutcomeMalformedResponse is the outcome of the relays whose response
+	// could not be decoded, failed basic validation, or was too large.
+	RelayOutcomeMalformedResponse
+	// RelayOutcomeError is the outcome of the relays which failed for any other
+	// reason, e.g. a refused connection.
+	RelayOutcomeError
+)
+
+// String returns the name of the outcome, e.g. "timeout".
+func (o RelayOutcome) String() string {
+	switch o {
+	case RelayOutcomeSuccess:
+		return "success"
+	case RelayOutcomeTimeout:
+		return "timeout"
+	case RelayOutcomeInvalidSignature:
+		return "invalid_signature"
+	case RelayOutcomeMalformedResponse:
+		return "malformed_response"
+	case RelayOutcomeError:
+		return "error"
+	default:
+		return fmt.Sprintf("RelayOutcome(%d)", int(o))
+	}
+}
+
+// ClassifyRelayOutcome returns the outcome of a relay which failed with the
+// given error, or RelayOutcomeSuccess if it is nil.
+func ClassifyRelayOutcome(err error) RelayOutcome {
+	switch {
+	case err == nil:
+		return RelayOutcomeSuccess
+	case errors.Is(err, sdkerrors.ErrRelayTimeout), isTimeoutError(err):
+		return RelayOutcomeTimeout
+	case errors.Is(err, sdkerrors.ErrInvalidSupplierSignature):
+		return RelayOutcomeInvalidSignature
+	case errors.Is(err, sdkerrors.ErrInvalidRelayResponse), errors.Is(err, sdkerrors.ErrRelayResponseTooLarge):
+		return RelayOutcomeMalformedResponse
+	default:
+		return RelayOutcomeError
+	}
+}
+
+// EndpointSanction describes the sanction of an endpoint by an EndpointStore.
+type EndpointSanction struct {
+	Endpoint Endpoint
+	// Reason describes the threshold exceeded by the endpoint.
+	Reason string
+	// Until is the time at which the sanction ends.
+	Until time.Time
+}
+
+// EndpointStoreOption is a functional option used to configure an EndpointStore.
+type EndpointStoreOption func(*EndpointStore)
+
+// WithEndpointStoreWindowSize sets the number of latest relay outcomes of each
+// endpoint from which its error rates are computed. It defaults to 100.
+func WithEndpointStoreWindowSize(windowSize int) EndpointStoreOption {
+	return func(s *EndpointStore) {
+		s.windowSize = windowSize
+	}
+}
+
+// WithEndpointMinObservations sets the minimum number of relay outcomes of an
+// endpoint, within the window, before it can be sanctioned. It defaults to 10.
+func WithEndpointMinObservations(minObservations int) EndpointStoreOption {
+	return func(s *EndpointStore) {
+		s.minObservations = minObservations
+	}
+}
+
+// WithEndpointMaxErrorRate sets the rate of failed relays, of any outcome, above
+// which an endpoint is sanctioned. It defaults to 0.5.
+func WithEndpointMaxErrorRate(maxErrorRate float64) EndpointStoreOption {
+	return func(s *EndpointStore) {
+		s.maxErrorRate = maxErrorRate
+	}
+}
+
+// WithEndpointMaxOutcomeRate sets the rate of relays with the given outcome
+// above which an endpoint is sanctioned, e.g. a low rate of invalid signatures,
+// which are a stronger signal of a misbehaving supplier than timeouts.
+func WithEndpointMaxOutcomeRate(outcome RelayOutcome, maxRate float64) EndpointStoreOption {
+	return func(s *EndpointStore) {
+		s.maxOutcomeRates[outcome] = maxRate
+	}
+}
+
+// WithEndpointSanctionCooldown sets the duration for which the sanctioned
+// endpoints are excluded. It defaults to 5 minutes.
+func WithEndpointSanctionCooldown(cooldown time.Duration) EndpointStoreOption {
+	return func(s *EndpointStore) {
+		s.cooldown = cooldown
+	}
+}
+
+// WithEndpointSanctionObserver sets a function called with every new sanction,
+// e.g. to log it or to count the sanctions per supplier.
+// It is called synchronously, and must not block.
+func WithEndpointSanctionObserver(observer func(EndpointSanction)) EndpointStoreOption {
+	return func(s *EndpointStore) {
+		s.sanctionObserver = observer
+	}
+}
+
+// EndpointStore records the outcome of the relays sent to each endpoint, and
+// sanctions the endpoints whose rolling error rates exceed the configured
+// thresholds: the sanctioned endpoints are excluded by its SanctionFilter for
+// the cooldown period, after which their outcomes are recorded anew.
+//
+// It implements RelayOutcomeObserver, and can be set as the
+// RelayOutcomeObserver of a GatewayClient, with its SanctionFilter in the
+// EndpointFilters of the GatewayClient's SessionFilter.
+// It is safe for concurrent use.
+type EndpointStore struct {
+	windowSize       int
+	minObservations  int
+	maxErrorRate     float64
+	maxOutcomeRates  map[RelayOutcome]float64
+	cooldown         time.Duration
+	sanctionObserver func(EndpointSanction)
+	now              func() time.Time
+
+	mu        sync.Mutex
+	endpoints map[string]*endpointRecord
+}
+
+// endpointRecord holds the latest relay outcomes of an endpoint, in a ring
+// buffer, along with its sanction, if any.
+type endpointRecord struct {
+	outcomes        []RelayOutcome
+	next            int
+	counts          map[RelayOutcome]int
+	sanctionedUntil time.Time
+}
+
+// NewEndpointStore returns an EndpointStore configured using the given options.
+func NewEndpointStore(opts ...EndpointStoreOption) *EndpointStore {
+	s := &EndpointStore{
+		windowSize:      defaultEndpointStoreWindowSize,
+		minObservations: defaultEndpointStoreMinObservations,
+		maxErrorRate:    defaultEndpointMaxErrorRate,
+		maxOutcomeRates: make(map[RelayOutcome]float64),
+		cooldown:        defaultEndpointSanctionCooldown,
+		now:             time.Now,
+		endpoints:       make(map[string]*endpointRecord),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.windowSize <= 0 {
+		s.windowSize = defaultEndpointStoreWindowSize
+	}
+	s.minObservations = min(max(s.minObservations, 1), s.windowSize)
+
+	return s
+}
+
+// ObserveRelayOutcome records the outcome of a relay sent to the given endpoint,
+// classified using ClassifyRelayOutcome.
+func (s *EndpointStore) ObserveRelayOutcome(endpoint Endpoint, _ time.Duration, err error) {
+	s.Record(endpoint, ClassifyRelayOutcome(err))
+}
+
+// Record records the given outcome of a relay sent to the given endpoint, and
+// sanctions the endpoint if it exceeds a threshold.
+// The outcomes of the sanctioned endpoints are not recorded.
+func (s *EndpointStore) Record(endpoint Endpoint, outcome RelayOutcome) {
+	sanction, sanctioned := s.record(endpoint, outcome)
+	if sanctioned && s.sanctionObserver != nil {
+		s.sanctionObserver(sanction)
+	}
+}
+
+// record records the given outcome, and returns the new sanction of the
+// endpoint, if any.
+func (s *EndpointStore) record(endpoint Endpoint, outcome RelayOutcome) (EndpointSanction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key := latencyKey(endpoint)
+	record, ok := s.endpoints[key]
+	if !ok {
+		record = &endpointRecord{}
+		s.endpoints[key] = record
+	}
+	if now.Before(record.sanctionedUntil) {
+		return EndpointSanction{}, false
+	}
+	if record.counts == nil {
+		record.outcomes = make([]RelayOutcome, 0, s.windowSize)
+		record.counts = make(map[RelayOutcome]int)
+	}
+
+	if len(record.outcomes) < s.windowSize {
+		record.outcomes = append(record.outcomes, outcome)
+	} else {
+		record.counts[record.outcomes[record.next]]--
+		record.outcomes[record.next] = outcome
+		record.next = (record.next + 1) % s.windowSize
+	}
+	record.counts[outcome]++
+
+	reason := s.exceededThreshold(record)
+	if reason == "" {
+		return EndpointSanction{}, false
+	}
+
+	// The outcomes are recorded anew once the sanction ends.
+	record.sanctionedUntil = now.Add(s.cooldown)
+	record.outcomes, record.next, record.counts = nil, 0, nil
+	return EndpointSanction{Endpoint: endpoint, Reason: reason, Until: record.sanctionedUntil}, true
+}
+
+// exceededThreshold returns a description of the threshold exceeded by the
+// given record, or an empty string if none is.
+func (s *EndpointStore) exceededThreshold(record *endpointRecord) string {
+	observations := len(record.outcomes)
+	if observations < s.minObservations {
+		return ""
+	}
+
+	errorRate := float64(observations-record.counts[RelayOutcomeSuccess]) / float64(observations)
+	if errorRate > s.maxErrorRate {
+		return fmt.Sprintf("error rate of %.2f exceeds %.2f", errorRate, s.maxErrorRate)
+	}
+	for outcome, maxRate := range s.maxOutcomeRates {
+		if rate := float64(record.counts[outcome]) / float64(observations); rate > maxRate {
+			return fmt.Sprintf("%s rate of %.2f exceeds %.2f", outcome, rate, maxRate)
+		}
+	}
+	return ""
+}
+
+// ErrorRate returns the rate of failed relays, of any outcome, among the latest
+// recorded outcomes of the given endpoint, or false if none is recorded.
+func (s *EndpointStore) ErrorRate(endpoint Endpoint) (float64, bool) {
+	successRate, ok := s.OutcomeRate(endpoint, RelayOutcomeSuccess)
+	if !ok {
+		return 0, false
+	}
+	return 1 - successRate, true
+}
+
+// OutcomeRate returns the rate of the given outcome among the latest recorded
+// outcomes of the given endpoint, or false if none is recorded.
+func (s *EndpointStore) OutcomeRate(endpoint Endpoint, outcome RelayOutcome) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.endpoints[latencyKey(endpoint)]
+	if !ok || len(record.outcomes) == 0 {
+		return 0, false
+	}
+	return float64(record.counts[outcome]) / float64(len(record.outcomes)), true
+}
+
+// IsSanctioned returns true if the given endpoint is sanctioned.
+func (s *EndpointStore) IsSanctioned(endpoint Endpoint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.endpoints[latencyKey(endpoint)]
+	return ok && s.now().Before(record.sanctionedUntil)
+}
+
+// SanctionFilter returns an EndpointFilter which filters out the sanctioned
+// endpoints, e.g. to be set in the EndpointFilters of a SessionFilter.
+func (s *EndpointStore) SanctionFilter() EndpointFilter {
+	return s.IsSanctioned
+}
@@ -0,0 +1,92 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestClassifyRelayOutcome(t *testing.T) {
+	tests := []struct {
+		err  error
+		want RelayOutcome
+	}{
+		{nil, RelayOutcomeSuccess},
+		{fmt.Errorf("relay: %w", sdkerrors.ErrRelayTimeout), RelayOutcomeTimeout},
+		{context.DeadlineExceeded, RelayOutcomeTimeout},
+		{sdkerrors.Wrap(sdkerrors.ErrInvalidSupplierSignature, "relay"), RelayOutcomeInvalidSignature},
+		{sdkerrors.Wrap(sdkerrors.ErrInvalidRelayResponse, "relay"), RelayOutcomeMalformedResponse},
+		{sdkerrors.Wrap(sdkerrors.ErrRelayResponseTooLarge, "relay"), RelayOutcomeMalformedResponse},
+		{errors.New("connection refused"), RelayOutcomeError},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, ClassifyRelayOutcome(test.err), "error: %v", test.err)
+	}
+	require.Equal(t, "invalid_signature", RelayOutcomeInvalidSignature.String())
+}
+
+func TestEndpointStore_Sanctions(t *testing.T) {
+	e := endpoint{supplierEndpoint: sharedtypes.SupplierEndpoint{Url: "https://supplier.example"}, supplier: "pokt1supplier"}
+	now := time.Unix(0, 0)
+	var sanctions []EndpointSanction
+	store := NewEndpointStore(
+		WithEndpointStoreWindowSize(10),
+		WithEndpointMinObservations(4),
+		WithEndpointMaxErrorRate(0.5),
+		WithEndpointMaxOutcomeRate(RelayOutcomeInvalidSignature, 0.2),
+		WithEndpointSanctionCooldown(time.Minute),
+		WithEndpointSanctionObserver(func(s EndpointSanction) { sanctions = append(sanctions, s) }),
+	)
+	store.now = func() time.Time { return now }
+	filter := store.SanctionFilter()
+
+	_, ok := store.ErrorRate(e)
+	require.False(t, ok)
+
+	// The endpoint is not sanctioned before the minimum number of observations.
+	for range 3 {
+		store.ObserveRelayOutcome(e, time.Millisecond, sdkerrors.ErrRelayTimeout)
+	}
+	require.False(t, filter(e))
+	rate, ok := store.OutcomeRate(e, RelayOutcomeTimeout)
+	require.True(t, ok)
+	require.Equal(t, 1.0, rate)
+
+	// The endpoint exceeding the error rate is sanctioned for the cooldown.
+	store.Record(e, RelayOutcomeTimeout)
+	require.True(t, filter(e))
+	require.Len(t, sanctions, 1)
+	require.Equal(t, now.Add(time.Minute), sanctions[0].Until)
+
+	// The outcomes of the sanctioned endpoint are not recorded.
+	store.Record(e, RelayOutcomeSuccess)
+	_, ok = store.ErrorRate(e)
+	require.False(t, ok)
+
+	// The outcomes are recorded anew once the sanction ends.
+	now = now.Add(time.Minute)
+	require.False(t, store.IsSanctioned(e))
+	for range 10 {
+		store.Record(e, RelayOutcomeSuccess)
+	}
+	for range 2 {
+		store.Record(e, RelayOutcomeInvalidSignature)
+	}
+	rate, ok = store.ErrorRate(e)
+	require.True(t, ok)
+	require.InDelta(t, 0.2, rate, 1e-9)
+	require.False(t, store.IsSanctioned(e))
+
+	// The per-outcome thresholds are enforced over the rolling window.
+	store.Record(e, RelayOutcomeInvalidSignature)
+	require.True(t, store.IsSanctioned(e))
+	require.Len(t, sanctions, 2)
+	require.Contains(t, sanctions[1].Reason, "invalid_signature")
+}
@@ -0,0 +1,106 @@
+package sdk
+
+import (
+	"fmt"
+	"net/url"
+
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// ErrInvalidEndpoint is the error of the supplier endpoints which can not be
+// relayed to, e.g. with a malformed URL or an unsupported URL scheme.
+var ErrInvalidEndpoint = sdkerrors.ErrInvalidEndpoint
+
+// supportedEndpointSchemes are the URL schemes of the supplier endpoints which
+// can be relayed to.
+var supportedEndpointSchemes = map[string]struct{}{
+	"http":  {},
+	"https": {},
+	"ws":    {},
+	"wss":   {},
+}
+
+// TypedSupplierEndpoint is a supplier endpoint with its URL parsed and validated
+// once, when the session is loaded, instead of on every relay.
+type TypedSupplierEndpoint struct {
+	// Raw is the supplier endpoint, as staked onchain.
+	Raw sharedtypes.SupplierEndpoint
+	// URL is the parsed URL of the endpoint, or nil if it can not be parsed.
+	// It is shared by all the copies of the TypedSupplierEndpoint, and must be
+	// copied before being modified.
+	URL *url.URL
+	// Scheme is the lower-cased URL scheme of the endpoint, e.g. "https".
+	Scheme string
+	// RPCType is the RPC type declared by the supplier for the endpoint.
+	RPCType sharedtypes.RPCType
+	// Err is the validation error of the endpoint, wrapping ErrInvalidEndpoint,
+	// or nil if the endpoint is valid.
+	Err error
+}
+
+// NewTypedSupplierEndpoint parses and validates the given supplier endpoint.
+// An endpoint is valid if its URL is absolute, with a host and one of the
+// "http", "https", "ws" or "wss" schemes.
+func NewTypedSupplierEndpoint(supplierEndpoint sharedtypes.SupplierEndpoint) TypedSupplierEndpoint {
+	typed := TypedSupplierEndpoint{
+		Raw:     supplierEndpoint,
+		RPCType: supplierEndpoint.RpcType,
+	}
+
+	endpointURL, err := url.Parse(supplierEndpoint.Url)
+	if err != nil {
+		typed.Err = fmt.Errorf("%w: %w", ErrInvalidEndpoint, err)
+		return typed
+	}
+	// url.Parse lower-cases the scheme.
+	typed.URL = endpointURL
+	typed.Scheme = endpointURL.Scheme
+
+	switch _, supported := supportedEndpointSchemes[typed.Scheme]; {
+	case typed.Scheme == "":
+		typed.Err = fmt.Errorf("%w: missing URL scheme: %q", ErrInvalidEndpoint, supplierEndpoint.Url)
+	case !supported:
+		typed.Err = fmt.Errorf("%w: unsupported URL scheme %q", ErrInvalidEndpoint, typed.Scheme)
+	case endpointURL.Host == "":
+		typed.Err = fmt.Errorf("%w: missing URL host: %q", ErrInvalidEndpoint, supplierEndpoint.Url)
+	}
+
+	return typed
+}
+
+// Valid returns true if the endpoint passed validation.
+func (t TypedSupplierEndpoint) Valid() bool {
+	return t.Err == nil
+}
+
+// IsPlaintext returns true if the endpoint's URL scheme is "http" or "ws".
+func (t TypedSupplierEndpoint) IsPlaintext() bool {
+	_, isPlaintext := plaintextSchemeUpgrades[t.Scheme]
+	return isPlaintext
+}
+
+// TypedEndpoint is implemented by the endpoints which carry their supplier
+// endpoint parsed and validated, e.g. the endpoints returned by a SessionFilter.
+type TypedEndpoint interface {
+	Endpoint
+	Typed() TypedSupplierEndpoint
+}
+
+// GetTypedEndpoint returns the parsed and validated supplier endpoint of the
+// given endpoint. It is parsed on the fly if the endpoint does not carry it,
+// e.g. for custom Endpoint implementations.
+func GetTypedEndpoint(e Endpoint) TypedSupplierEndpoint {
+	supplierEndpoint := e.Endpoint()
+	if withTyped, ok := e.(TypedEndpoint); ok {
+		typed := withTyped.Typed()
+		// The zero TypedSupplierEndpoint, e.g. of an endpoint built as a struct
+		// literal, was never parsed.
+		if (typed.URL != nil || typed.Err != nil) && typed.Raw.Url == supplierEndpoint.Url {
+			return typed
+		}
+	}
+
+	return NewTypedSupplierEndpoint(supplierEndpoint)
+}
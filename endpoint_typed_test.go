@@ -0,0 +1,71 @@
+package sdk
+
+import (
+	"testing"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTypedSupplierEndpoint(t *testing.T) {
+	tests := []struct {
+		desc           string
+		url            string
+		expectedScheme string
+		expectValid    bool
+		expectParsed   bool
+	}{
+		{desc: "HTTPS endpoint", url: "https://supplier.example:8545/path", expectedScheme: "https", expectValid: true, expectParsed: true},
+		{desc: "upper-case scheme", url: "WSS://supplier.example", expectedScheme: "wss", expectValid: true, expectParsed: true},
+		{desc: "unsupported scheme", url: "ftp://supplier.example", expectedScheme: "ftp", expectParsed: true},
+		{desc: "missing scheme", url: "supplier.example:8545", expectedScheme: "supplier.example", expectParsed: true},
+		{desc: "missing host", url: "https:///path", expectedScheme: "https", expectParsed: true},
+		{desc: "empty URL", url: "", expectParsed: true},
+		{desc: "malformed URL", url: "https://supplier example/%zz"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			supplierEndpoint := sharedtypes.SupplierEndpoint{Url: test.url, RpcType: sharedtypes.RPCType_JSON_RPC}
+			typed := NewTypedSupplierEndpoint(supplierEndpoint)
+
+			require.Equal(t, supplierEndpoint, typed.Raw)
+			require.Equal(t, sharedtypes.RPCType_JSON_RPC, typed.RPCType)
+			require.Equal(t, test.expectedScheme, typed.Scheme)
+			require.Equal(t, test.expectParsed, typed.URL != nil)
+			require.Equal(t, test.expectValid, typed.Valid())
+			if !test.expectValid {
+				require.ErrorIs(t, typed.Err, ErrInvalidEndpoint)
+			}
+		})
+	}
+}
+
+func TestGetTypedEndpoint(t *testing.T) {
+	e := NewEndpoint(
+		sessiontypes.SessionHeader{},
+		sharedtypes.SupplierEndpoint{Url: "http://supplier.example"},
+		SupplierInfo{},
+	)
+	typed := GetTypedEndpoint(e)
+	require.True(t, typed.Valid())
+	require.True(t, typed.IsPlaintext())
+	// The URL is parsed once, when the endpoint is built.
+	require.Same(t, typed.URL, GetTypedEndpoint(e).URL)
+
+	// The decorators overriding the URL carry the overriding URL, parsed.
+	decorated := DecorateEndpoint(e, WithEndpointURL("https://proxy.internal"), WithEndpointMetadata("key", "value"))
+	typed = GetTypedEndpoint(decorated)
+	require.Equal(t, "proxy.internal", typed.URL.Host)
+	require.False(t, typed.IsPlaintext())
+	require.Same(t, typed.URL, GetTypedEndpoint(decorated).URL)
+
+	// The endpoints not carrying their parsed endpoint are parsed on the fly.
+	typed = GetTypedEndpoint(endpoint{supplierEndpoint: sharedtypes.SupplierEndpoint{Url: "wss://supplier.example"}})
+	require.True(t, typed.Valid())
+	require.Equal(t, "wss", typed.Scheme)
+
+	require.True(t, ByValidEndpoint()(DecorateEndpoint(e, WithEndpointURL("ftp://supplier.example"))))
+	require.False(t, ByValidEndpoint()(e))
+}
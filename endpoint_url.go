@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// ErrPathTraversal is returned when a request path attempts to escape the
+// supplier endpoint's base path, e.g. using ".." segments.
+var ErrPathTraversal = sdkerrors.ErrPathTraversal
+
+// ComposeEndpointURL returns the URL of the given supplier endpoint, extended
+// with the path and query of the given serialized client request.
+// It is intended for path-based services, e.g. REST-style chains, whose requests
+// must be sent to a specific path below the supplier's base URL.
+//
+// The request path is appended to the base URL path, and the request query
+// parameters are added to the base URL ones.
+// An error wrapping ErrPathTraversal is returned if the request path contains
+// "." or ".." segments, including percent-encoded ones.
+func ComposeEndpointURL(supplierEndpointURL string, poktRequest *types.POKTHTTPRequest) (string, error) {
+	baseURL, err := url.Parse(supplierEndpointURL)
+	if err != nil {
+		return "", fmt.Errorf("ComposeEndpointURL: error parsing supplier endpoint URL: %w", err)
+	}
+
+	if poktRequest == nil || poktRequest.Url == "" {
+		return baseURL.String(), nil
+	}
+
+	requestURL, err := url.Parse(poktRequest.Url)
+	if err != nil {
+		return "", fmt.Errorf("ComposeEndpointURL: error parsing request URL: %w", err)
+	}
+
+	// url.URL.Path holds the decoded path, so that encoded dot segments are detected too.
+	for _, segment := range strings.Split(requestURL.Path, "/") {
+		if segment == "." || segment == ".." {
+			return "", fmt.Errorf("ComposeEndpointURL: %w: %s", ErrPathTraversal, requestURL.EscapedPath())
+		}
+	}
+
+	if requestPath := strings.TrimPrefix(requestURL.EscapedPath(), "/"); requestPath != "" {
+		joinedURL, err := url.Parse(strings.TrimSuffix(baseURL.EscapedPath(), "/") + "/" + requestPath)
+		if err != nil {
+			return "", fmt.Errorf("ComposeEndpointURL: error joining request path: %w", err)
+		}
+		baseURL.Path = joinedURL.Path
+		baseURL.RawPath = joinedURL.RawPath
+	}
+
+	if requestURL.RawQuery != "" {
+		query := baseURL.Query()
+		for key, values := range requestURL.Query() {
+			for _, value := range values {
+				query.Add(key, value)
+			}
+		}
+		baseURL.RawQuery = query.Encode()
+	}
+
+	return baseURL.String(), nil
+}
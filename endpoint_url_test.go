@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestComposeEndpointURL(t *testing.T) {
+	tests := []struct {
+		desc                string
+		supplierEndpointURL string
+		requestURL          string
+		expectedURL         string
+		expectTraversalErr  bool
+	}{
+		{
+			desc:                "no request URL",
+			supplierEndpointURL: "https://supplier.example/v1",
+			expectedURL:         "https://supplier.example/v1",
+		},
+		{
+			desc:                "request path appended to base path",
+			supplierEndpointURL: "https://supplier.example/v1/",
+			requestURL:          "/cosmos/bank/v1beta1/balances/addr",
+			expectedURL:         "https://supplier.example/v1/cosmos/bank/v1beta1/balances/addr",
+		},
+		{
+			desc:                "full request URL",
+			supplierEndpointURL: "https://supplier.example",
+			requestURL:          "http://localhost:8080/blocks/latest?a=1",
+			expectedURL:         "https://supplier.example/blocks/latest?a=1",
+		},
+		{
+			desc:                "query parameters merged",
+			supplierEndpointURL: "https://supplier.example/api?key=secret",
+			requestURL:          "/status?verbose=true",
+			expectedURL:         "https://supplier.example/api/status?key=secret&verbose=true",
+		},
+		{
+			desc:                "encoded path preserved",
+			supplierEndpointURL: "https://supplier.example",
+			requestURL:          "/files/a%2Fb",
+			expectedURL:         "https://supplier.example/files/a%2Fb",
+		},
+		{
+			desc:                "path traversal",
+			supplierEndpointURL: "https://supplier.example/v1",
+			requestURL:          "/../admin",
+			expectTraversalErr:  true,
+		},
+		{
+			desc:                "encoded path traversal",
+			supplierEndpointURL: "https://supplier.example/v1",
+			requestURL:          "/%2e%2e/admin",
+			expectTraversalErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			composedURL, err := ComposeEndpointURL(
+				test.supplierEndpointURL,
+				&types.POKTHTTPRequest{Url: test.requestURL},
+			)
+			if test.expectTraversalErr {
+				require.ErrorIs(t, err, ErrPathTraversal)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedURL, composedURL)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package sdk
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// EventType identifies the type of an Event.
+type EventType string
+
+const (
+	// EventSessionRefreshed is the type of SessionRefreshedEvent.
+	EventSessionRefreshed EventType = "session_refreshed"
+	// EventCacheEvicted is the type of CacheEvictedEvent.
+	EventCacheEvicted EventType = "cache_evicted"
+	// EventSupplierFailed is the type of SupplierFailedEvent.
+	EventSupplierFailed EventType = "supplier_failed"
+	// EventHealthChanged is the type of HealthChangedEvent.
+	EventHealthChanged EventType = "health_changed"
+	// EventDelegationChanged is the type of DelegationChangedEvent.
+	EventDelegationChanged EventType = "delegation_changed"
+	// EventGRPCConnStateChanged is the type of GRPCConnStateChangedEvent.
+	EventGRPCConnStateChanged EventType = "grpc_conn_state_changed"
+	// EventSupplierStakeChanged is the type of SupplierStakeChangedEvent.
+	EventSupplierStakeChanged EventType = "supplier_stake_changed"
+	// EventSessionClosed is the type of CloseSessionReport.
+	EventSessionClosed EventType = "session_closed"
+	// EventChainReorg is the type of ChainReorgEvent.
+	EventChainReorg EventType = "chain_reorg"
+	// EventApplicationStakeChanged is the type of ApplicationStakeChangedEvent.
+	EventApplicationStakeChanged EventType = "application_stake_changed"
+	// EventNodeRateLimited is the type of NodeRateLimitedEvent.
+	EventNodeRateLimited EventType = "node_rate_limited"
+	// EventNodeRateLimitRecovered is the type of NodeRateLimitRecoveredEvent.
+	EventNodeRateLimitRecovered EventType = "node_rate_limit_recovered"
+)
+
+// Event is a notification published on an EventBus.
+// Subscribers can use a type switch to access the fields of the concrete events.
+type Event interface {
+	EventType() EventType
+}
+
+// SessionRefreshedEvent is published when a session is fetched from the full node.
+type SessionRefreshedEvent struct {
+	AppAddress string
+	ServiceId  string
+	Session    SessionInfo
+}
+
+// EventType returns EventSessionRefreshed.
+func (SessionRefreshedEvent) EventType() EventType { return EventSessionRefreshed }
+
+// CacheEvictedEvent is published when a session is removed from a cache.
+type CacheEvictedEvent struct {
+	Key SessionKey
+}
+
+// EventType returns EventCacheEvicted.
+func (CacheEvictedEvent) EventType() EventType { return EventCacheEvicted }
+
+// SupplierFailedEvent is published when a relay sent to a supplier fails.
+type SupplierFailedEvent struct {
+	ServiceId string
+	Supplier  SupplierAddress
+	Err       error
+}
+
+// EventType returns EventSupplierFailed.
+func (SupplierFailedEvent) EventType() EventType { return EventSupplierFailed }
+
+// HealthChangedEvent is published when a component, e.g. a full node, becomes
+// healthy or unhealthy.
+type HealthChangedEvent struct {
+	// Component identifies the component, e.g. the URL of a full node.
+	Component string
+	Healthy   bool
+	// Err is the error which caused the component to become unhealthy, if any.
+	Err error
+}
+
+// EventType returns EventHealthChanged.
+func (HealthChangedEvent) EventType() EventType { return EventHealthChanged }
+
+// DelegationChangedEvent is published when an application delegates to, or
+// undelegates from, a gateway.
+type DelegationChangedEvent struct {
+	AppAddress     string
+	GatewayAddress string
+	Delegated      bool
+}
+
+// EventType returns EventDelegationChanged.
+func (DelegationChangedEvent) EventType() EventType { return EventDelegationChanged }
+
+// GRPCConnStateChangedEvent is published when the connectivity state of a
+// monitored gRPC connection to a full node changes.
+type GRPCConnStateChangedEvent struct {
+	// Target is the target of the gRPC connection, e.g. the full node's address.
+	Target        string
+	PreviousState connectivity.State
+	State         connectivity.State
+}
+
+// EventType returns EventGRPCConnStateChanged.
+func (GRPCConnStateChangedEvent) EventType() EventType { return EventGRPCConnStateChanged }
+
+// SupplierStakeChangedEvent is published when a supplier stakes, unstakes, or
+// starts unbonding.
+type SupplierStakeChangedEvent struct {
+	Supplier SupplierAddress
+}
+
+// EventType returns EventSupplierStakeChanged.
+func (SupplierStakeChangedEvent) EventType() EventType { return EventSupplierStakeChanged }
+
+// ChainReorgEvent is published when a ReorgDetector detects that the chain was
+// reorganized or reset, e.g. a LocalNet or testnet being restarted.
+type ChainReorgEvent struct {
+	// PreviousHeight is the latest block height observed before the reorg.
+	PreviousHeight int64
+	// Height is the latest block height observed after the reorg.
+	Height int64
+	Reason ReorgReason
+}
+
+// EventType returns EventChainReorg.
+func (ChainReorgEvent) EventType() EventType { return EventChainReorg }
+
+// ApplicationStakeChangedEvent is published when an application stakes, e.g.
+// to update its stake or services, or unstakes.
+type ApplicationStakeChangedEvent struct {
+	AppAddress string
+	Staked     bool
+}
+
+// EventType returns EventApplicationStakeChanged.
+func (ApplicationStakeChangedEvent) EventType() EventType { return EventApplicationStakeChanged }
+
+// NodeRateLimitedEvent is published when the full node rate limits the SDK's
+// requests, and a NodeBackoff starts delaying the following requests, or
+// delays them further.
+type NodeRateLimitedEvent struct {
+	// Attempt is the number of successive rate limit errors.
+	Attempt int
+	// Delay is the duration for which the requests are delayed.
+	Delay time.Duration
+	Err   error
+}
+
+// EventType returns EventNodeRateLimited.
+func (NodeRateLimitedEvent) EventType() EventType { return EventNodeRateLimited }
+
+// NodeRateLimitRecoveredEvent is published when the recovery probe of a
+// NodeBackoff succeeds, and the requests are no longer delayed.
+type NodeRateLimitRecoveredEvent struct {
+	// Duration is the time spent backing off.
+	Duration time.Duration
+}
+
+// EventType returns EventNodeRateLimitRecovered.
+func (NodeRateLimitRecoveredEvent) EventType() EventType { return EventNodeRateLimitRecovered }
+
+// EventHandler is called with the events a subscriber is subscribed to.
+type EventHandler func(Event)
+
+// EventBus dispatches the events published by the SDK components to the
+// subscribed handlers, providing a single notification surface for operators,
+// e.g. to export metrics or trigger alerts.
+//
+// Handlers are called synchronously, in the publisher's goroutine, and must not block.
+// An EventBus is safe for concurrent use. A nil *EventBus discards all the
+// published events, so components can publish unconditionally.
+type EventBus struct {
+	mu            sync.RWMutex
+	nextId        uint64
+	subscriptions map[uint64]subscription
+}
+
+// subscription is the handler of a subscriber along with the event types it
+// is subscribed to.
+type subscription struct {
+	handler    EventHandler
+	eventTypes []EventType
+}
+
+// NewEventBus returns an EventBus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscriptions: make(map[uint64]subscription),
+	}
+}
+
+// Subscribe registers the given handler for the events of the given types, or
+// for all events if no type is given.
+// The returned function removes the subscription.
+func (b *EventBus) Subscribe(handler EventHandler, eventTypes ...EventType) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextId
+	b.nextId++
+	b.subscriptions[id] = subscription{handler: handler, eventTypes: eventTypes}
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscriptions, id)
+	}
+}
+
+// Publish calls the handlers subscribed to the type of the given event.
+func (b *EventBus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]EventHandler, 0, len(b.subscriptions))
+	for _, sub := range b.subscriptions {
+		if len(sub.eventTypes) == 0 || slices.Contains(sub.eventTypes, event.EventType()) {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+func TestEventBus_Subscribe(t *testing.T) {
+	bus := NewEventBus()
+
+	var allEvents, supplierEvents []Event
+	bus.Subscribe(func(e Event) { allEvents = append(allEvents, e) })
+	unsubscribe := bus.Subscribe(
+		func(e Event) { supplierEvents = append(supplierEvents, e) },
+		EventSupplierFailed,
+	)
+
+	supplierFailed := SupplierFailedEvent{ServiceId: "svc1", Supplier: "pokt1supplier", Err: errors.New("timeout")}
+	bus.Publish(supplierFailed)
+	bus.Publish(HealthChangedEvent{Component: "https://node.example", Healthy: false})
+
+	require.Equal(t, []Event{supplierFailed}, supplierEvents)
+	require.Len(t, allEvents, 2)
+
+	// Unsubscribed handlers are no longer called.
+	unsubscribe()
+	bus.Publish(supplierFailed)
+	require.Len(t, supplierEvents, 1)
+	require.Len(t, allEvents, 3)
+
+	// A nil EventBus discards the events.
+	var nilBus *EventBus
+	require.NotPanics(t, func() { nilBus.Publish(supplierFailed) })
+}
+
+func TestSessionCache_EventBus(t *testing.T) {
+	bus := NewEventBus()
+	var refreshed []SessionRefreshedEvent
+	bus.Subscribe(func(e Event) {
+		refreshed = append(refreshed, e.(SessionRefreshedEvent))
+	}, EventSessionRefreshed)
+
+	sc := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: &heightSessionFetcher{numBlocksPerSession: 4}},
+		WithCacheConfig(cache.Config{TTL: time.Minute}),
+		WithEventBus(bus),
+	)
+
+	ctx := context.Background()
+	for _, height := range []int64{1, 2, 5} {
+		_, err := sc.GetSession(ctx, "app1", "svc1", height)
+		require.NoError(t, err)
+	}
+
+	// Only the sessions fetched from the full node are published.
+	require.Len(t, refreshed, 2)
+	require.Equal(t, "app1", refreshed[1].AppAddress)
+	require.Equal(t, "svc1", refreshed[1].ServiceId)
+	require.Equal(t, int64(5), refreshed[1].Session.FetchedAtHeight)
+}
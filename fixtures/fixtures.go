@@ -0,0 +1,308 @@
+// Package fixtures generates large-scale, realistic fixtures of the onchain data
+// a gateway works with: thousands of applications, each staked for several
+// services and delegating to a few gateways, and sessions of many suppliers.
+//
+// The fixtures are deterministic, so the memory and CPU bounds of the session
+// caching and ring construction paths can be asserted against large-gateway
+// workloads, and compared across changes using benchmarks.
+// A Fixture serves the generated data through the SDK's interfaces: it is both
+// a PoktNodeSessionFetcher and a PublicKeyFetcher.
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pokt-network/poktroll/app/volatile"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	grpcoptions "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	sdk "github.com/pokt-network/shannon-sdk"
+)
+
+// stakeAmount is the stake, in uPOKT, of the generated actors.
+const stakeAmount = 1_000_000_000
+
+// Config specifies the size of a generated Fixture.
+type Config struct {
+	// NumApps is the number of applications, each staked for all the services.
+	NumApps int
+	// NumServices is the number of services.
+	NumServices int
+	// NumSuppliers is the number of suppliers, each staked for all the services.
+	NumSuppliers int
+	// SuppliersPerSession is the number of suppliers of each session.
+	SuppliersPerSession int
+	// EndpointsPerSupplier is the number of endpoints of each supplier, per service.
+	EndpointsPerSupplier int
+	// NumGateways is the number of gateways.
+	NumGateways int
+	// GatewaysPerApp is the number of gateways each application delegates to.
+	GatewaysPerApp int
+	// NumBlocksPerSession is the number of blocks of the sessions.
+	NumBlocksPerSession int64
+}
+
+// LargeGatewayConfig returns the Config of a large gateway's workload: 10k
+// applications × 5 services, i.e. 50k active sessions of 15 suppliers each.
+func LargeGatewayConfig() Config {
+	return Config{
+		NumApps:              10_000,
+		NumServices:          5,
+		NumSuppliers:         1_000,
+		SuppliersPerSession:  15,
+		EndpointsPerSupplier: 1,
+		NumGateways:          5,
+		GatewaysPerApp:       2,
+		NumBlocksPerSession:  10,
+	}
+}
+
+// Validate returns an error if the config does not describe a valid fixture.
+func (c Config) Validate() error {
+	if c.NumApps <= 0 || c.NumServices <= 0 || c.NumSuppliers <= 0 || c.SuppliersPerSession <= 0 ||
+		c.EndpointsPerSupplier <= 0 || c.NumGateways <= 0 || c.GatewaysPerApp <= 0 || c.NumBlocksPerSession <= 0 {
+		return errors.New("all the fixture sizes must be greater than zero")
+	}
+	if c.SuppliersPerSession > c.NumSuppliers {
+		return fmt.Errorf("%d suppliers per session exceed the %d suppliers", c.SuppliersPerSession, c.NumSuppliers)
+	}
+	if c.GatewaysPerApp > c.NumGateways {
+		return fmt.Errorf("%d gateways per application exceed the %d gateways", c.GatewaysPerApp, c.NumGateways)
+	}
+	return nil
+}
+
+// Fixture is a generated set of applications, gateways and suppliers.
+// It is safe for concurrent use.
+type Fixture struct {
+	Config     Config
+	ServiceIds []string
+	Apps       []apptypes.Application
+	Gateways   []string
+	Suppliers  []sharedtypes.Supplier
+
+	apps    map[string]*apptypes.Application
+	pubKeys map[string]cryptotypes.PubKey
+}
+
+// Generate returns the Fixture of the given config.
+// The keys of the actors are derived from their index, so the same config
+// always generates the same fixture.
+func Generate(config Config) (*Fixture, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("Generate: %w", err)
+	}
+
+	f := &Fixture{
+		Config:  config,
+		apps:    make(map[string]*apptypes.Application, config.NumApps),
+		pubKeys: make(map[string]cryptotypes.PubKey, config.NumApps+config.NumGateways+config.NumSuppliers),
+	}
+
+	for i := range config.NumServices {
+		f.ServiceIds = append(f.ServiceIds, fmt.Sprintf("svc%d", i))
+	}
+
+	for i := range config.NumGateways {
+		address, err := f.newAccount(fmt.Sprintf("gateway-%d", i))
+		if err != nil {
+			return nil, fmt.Errorf("Generate: %w", err)
+		}
+		f.Gateways = append(f.Gateways, address)
+	}
+
+	f.Suppliers = make([]sharedtypes.Supplier, 0, config.NumSuppliers)
+	for i := range config.NumSuppliers {
+		address, err := f.newAccount(fmt.Sprintf("supplier-%d", i))
+		if err != nil {
+			return nil, fmt.Errorf("Generate: %w", err)
+		}
+		f.Suppliers = append(f.Suppliers, sharedtypes.Supplier{
+			OwnerAddress:    address,
+			OperatorAddress: address,
+			Stake:           newStake(),
+		})
+	}
+
+	f.Apps = make([]apptypes.Application, 0, config.NumApps)
+	for i := range config.NumApps {
+		address, err := f.newAccount(fmt.Sprintf("app-%d", i))
+		if err != nil {
+			return nil, fmt.Errorf("Generate: %w", err)
+		}
+
+		app := apptypes.Application{Address: address, Stake: newStake()}
+		for _, serviceId := range f.ServiceIds {
+			app.ServiceConfigs = append(app.ServiceConfigs, &sharedtypes.ApplicationServiceConfig{ServiceId: serviceId})
+		}
+		for j := range config.GatewaysPerApp {
+			app.DelegateeGatewayAddresses = append(app.DelegateeGatewayAddresses, f.Gateways[(i+j)%config.NumGateways])
+		}
+		f.Apps = append(f.Apps, app)
+	}
+	for i := range f.Apps {
+		f.apps[f.Apps[i].Address] = &f.Apps[i]
+	}
+
+	return f, nil
+}
+
+// SessionKeys returns the keys of all the sessions of the fixture, i.e. every
+// combination of application and service.
+func (f *Fixture) SessionKeys() []sdk.SessionKey {
+	keys := make([]sdk.SessionKey, 0, len(f.Apps)*len(f.ServiceIds))
+	for _, app := range f.Apps {
+		for _, serviceId := range f.ServiceIds {
+			keys = append(keys, sdk.SessionKey{AppAddress: app.Address, ServiceId: serviceId})
+		}
+	}
+	return keys
+}
+
+// GetSession returns the session of the requested application and service
+// covering the requested height. Its suppliers are drawn pseudo-randomly from
+// the fixture's suppliers, based on the session id.
+//
+// Every call returns a newly allocated session, as decoded from a full node's
+// response, so the memory retained by the caches is representative.
+func (f *Fixture) GetSession(
+	_ context.Context,
+	req *sessiontypes.QueryGetSessionRequest,
+	_ ...grpcoptions.CallOption,
+) (*sessiontypes.QueryGetSessionResponse, error) {
+	app, ok := f.apps[req.ApplicationAddress]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "application %s not found", req.ApplicationAddress)
+	}
+	if req.BlockHeight <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid height %d", req.BlockHeight)
+	}
+
+	numBlocksPerSession := f.Config.NumBlocksPerSession
+	sessionNumber := (req.BlockHeight - 1) / numBlocksPerSession
+	startHeight := 1 + sessionNumber*numBlocksPerSession
+	sessionId := fmt.Sprintf("%s-%s-%d", req.ApplicationAddress, req.ServiceId, sessionNumber)
+
+	session := &sessiontypes.Session{
+		Header: &sessiontypes.SessionHeader{
+			ApplicationAddress:      req.ApplicationAddress,
+			ServiceId:               req.ServiceId,
+			SessionId:               sessionId,
+			SessionStartBlockHeight: startHeight,
+			SessionEndBlockHeight:   startHeight + numBlocksPerSession - 1,
+		},
+		SessionId:           sessionId,
+		SessionNumber:       sessionNumber,
+		NumBlocksPerSession: numBlocksPerSession,
+		Application:         cloneApplication(app),
+	}
+	for _, i := range f.sessionSupplierIndexes(sessionId) {
+		session.Suppliers = append(session.Suppliers, f.sessionSupplier(i, req.ServiceId))
+	}
+
+	return &sessiontypes.QueryGetSessionResponse{Session: session}, nil
+}
+
+// GetPubKeyFromAddress returns the public key of the given application, gateway
+// or supplier address.
+func (f *Fixture) GetPubKeyFromAddress(_ context.Context, address string) (cryptotypes.PubKey, error) {
+	pubKey, ok := f.pubKeys[address]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "account %s not found", address)
+	}
+	return pubKey, nil
+}
+
+// ApplicationRing returns the ApplicationRing of the given application, whose
+// public keys are fetched from the fixture.
+func (f *Fixture) ApplicationRing(appAddress string) (sdk.ApplicationRing, error) {
+	app, ok := f.apps[appAddress]
+	if !ok {
+		return sdk.ApplicationRing{}, fmt.Errorf("ApplicationRing: application %s not found", appAddress)
+	}
+	return sdk.ApplicationRing{Application: *app, PublicKeyFetcher: f}, nil
+}
+
+// newAccount generates the key of an account from the given seed, and returns
+// the account's address.
+func (f *Fixture) newAccount(seed string) (string, error) {
+	pubKey := secp256k1.GenPrivKeyFromSecret([]byte(seed)).PubKey()
+	address, err := sdk.PubKeyToAddress(sdk.PoktAddressPrefix, pubKey)
+	if err != nil {
+		return "", fmt.Errorf("error generating the address of %s: %w", seed, err)
+	}
+	f.pubKeys[address] = pubKey
+	return address, nil
+}
+
+// sessionSupplierIndexes returns the indexes of the distinct suppliers of the
+// session with the given id.
+func (f *Fixture) sessionSupplierIndexes(sessionId string) []int {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(sessionId))
+	rng := rand.New(rand.NewPCG(hash.Sum64(), 0))
+
+	indexes := make([]int, 0, f.Config.SuppliersPerSession)
+	selected := make(map[int]struct{}, f.Config.SuppliersPerSession)
+	for len(indexes) < f.Config.SuppliersPerSession {
+		i := rng.IntN(len(f.Suppliers))
+		if _, ok := selected[i]; ok {
+			continue
+		}
+		selected[i] = struct{}{}
+		indexes = append(indexes, i)
+	}
+	return indexes
+}
+
+// sessionSupplier returns a new copy of the supplier with the given index, as
+// listed in a session of the given service, i.e. with the service's endpoints only.
+func (f *Fixture) sessionSupplier(i int, serviceId string) *sharedtypes.Supplier {
+	supplier := f.Suppliers[i]
+
+	serviceConfig := &sharedtypes.SupplierServiceConfig{ServiceId: serviceId}
+	for j := range f.Config.EndpointsPerSupplier {
+		serviceConfig.Endpoints = append(serviceConfig.Endpoints, &sharedtypes.SupplierEndpoint{
+			Url:     fmt.Sprintf("https://supplier-%d-%d.%s.example:8443", i, j, serviceId),
+			RpcType: sharedtypes.RPCType_JSON_RPC,
+		})
+	}
+
+	return &sharedtypes.Supplier{
+		OwnerAddress:    supplier.OwnerAddress,
+		OperatorAddress: supplier.OperatorAddress,
+		Stake:           newStake(),
+		Services:        []*sharedtypes.SupplierServiceConfig{serviceConfig},
+	}
+}
+
+// cloneApplication returns a new copy of the given application.
+func cloneApplication(app *apptypes.Application) *apptypes.Application {
+	clone := &apptypes.Application{
+		Address:                   app.Address,
+		Stake:                     newStake(),
+		DelegateeGatewayAddresses: append([]string(nil), app.DelegateeGatewayAddresses...),
+	}
+	for _, serviceConfig := range app.ServiceConfigs {
+		clone.ServiceConfigs = append(clone.ServiceConfigs, &sharedtypes.ApplicationServiceConfig{ServiceId: serviceConfig.ServiceId})
+	}
+	return clone
+}
+
+// newStake returns a new stake of the generated actors.
+func newStake() *cosmostypes.Coin {
+	stake := cosmostypes.NewCoin(volatile.DenomuPOKT, math.NewInt(stakeAmount))
+	return &stake
+}
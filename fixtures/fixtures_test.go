@@ -0,0 +1,59 @@
+package fixtures_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/pokt-network/shannon-sdk"
+	"github.com/pokt-network/shannon-sdk/fixtures"
+)
+
+func TestGenerate(t *testing.T) {
+	config := fixtures.Config{
+		NumApps:              10,
+		NumServices:          2,
+		NumSuppliers:         20,
+		SuppliersPerSession:  5,
+		EndpointsPerSupplier: 2,
+		NumGateways:          3,
+		GatewaysPerApp:       2,
+		NumBlocksPerSession:  4,
+	}
+	fixture, err := fixtures.Generate(config)
+	require.NoError(t, err)
+	require.Len(t, fixture.Apps, 10)
+	require.Len(t, fixture.SessionKeys(), 20)
+
+	sessionClient := &sdk.SessionClient{PoktNodeSessionFetcher: fixture}
+	app := fixture.Apps[0]
+
+	// The sessions are deterministic, and their suppliers are distinct.
+	session, err := sessionClient.GetSession(context.Background(), app.Address, "svc1", 5)
+	require.NoError(t, err)
+	sameSession, err := sessionClient.GetSession(context.Background(), app.Address, "svc1", 8)
+	require.NoError(t, err)
+	require.Equal(t, session, sameSession)
+	require.Equal(t, int64(5), session.Header.SessionStartBlockHeight)
+	require.Equal(t, int64(8), session.Header.SessionEndBlockHeight)
+
+	suppliers := make(map[string]struct{})
+	for _, supplier := range session.Suppliers {
+		suppliers[supplier.OperatorAddress] = struct{}{}
+		require.Len(t, supplier.Services, 1)
+		require.Equal(t, "svc1", supplier.Services[0].ServiceId)
+		require.Len(t, supplier.Services[0].Endpoints, 2)
+	}
+	require.Len(t, suppliers, 5)
+
+	// The rings are built from the generated keys.
+	appRing, err := fixture.ApplicationRing(app.Address)
+	require.NoError(t, err)
+	require.Len(t, appRing.GetRingAddresses(8), 3)
+	_, err = appRing.GetRing(context.Background(), 8)
+	require.NoError(t, err)
+
+	_, err = fixtures.Generate(fixtures.Config{NumApps: 1})
+	require.Error(t, err)
+}
@@ -0,0 +1,151 @@
+//go:build scale
+
+package fixtures_test
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/pokt-network/shannon-sdk"
+	"github.com/pokt-network/shannon-sdk/cache"
+	"github.com/pokt-network/shannon-sdk/fixtures"
+)
+
+const (
+	// cacheCapacity is the capacity of the session cache validated against the
+	// large gateway workload.
+	cacheCapacity = 100_000
+
+	// maxBytesPerCachedSession bounds the heap retained by each cached session
+	// of LargeGatewayConfig, i.e. 15 suppliers along with the application.
+	maxBytesPerCachedSession = 32 << 10
+	// maxCachedSessionLookup bounds the average duration of a cached session lookup.
+	maxCachedSessionLookup = 20 * time.Microsecond
+	// maxRingConstruction bounds the average duration of the construction of an
+	// application's ring.
+	maxRingConstruction = 5 * time.Millisecond
+)
+
+// largeGateway generates the fixture of LargeGatewayConfig once for all the
+// tests and benchmarks.
+var largeGateway = sync.OnceValues(func() (*fixtures.Fixture, error) {
+	return fixtures.Generate(fixtures.LargeGatewayConfig())
+})
+
+func TestLargeGateway_SessionCache(t *testing.T) {
+	fixture, err := largeGateway()
+	require.NoError(t, err)
+	keys := fixture.SessionKeys()
+
+	var engines []*cache.SegmentedLRUEngine[sdk.SessionKey, sdk.SessionInfo]
+	sessionCache := sdk.NewSessionCacheWithOptions(
+		&sdk.SessionClient{PoktNodeSessionFetcher: fixture},
+		sdk.WithServiceSharding(),
+		sdk.WithCacheEngine(func() cache.Engine[sdk.SessionKey, sdk.SessionInfo] {
+			engine := cache.NewSegmentedLRUEngine[sdk.SessionKey, sdk.SessionInfo](cacheCapacity, 0.8)
+			engines = append(engines, engine)
+			return engine
+		}),
+	)
+
+	heapBefore := heapAlloc()
+	for _, key := range keys {
+		_, err := sessionCache.GetSession(context.Background(), key.AppAddress, key.ServiceId, 1)
+		require.NoError(t, err)
+	}
+	heapAfter := heapAlloc()
+
+	// All the active sessions fit in the cache, without any eviction.
+	cached := 0
+	for _, engine := range engines {
+		cached += engine.Len()
+	}
+	require.Len(t, engines, len(fixture.ServiceIds))
+	require.Equal(t, len(keys), cached)
+
+	bytesPerSession := (int64(heapAfter) - int64(heapBefore)) / int64(len(keys))
+	t.Logf("%d cached sessions retain %d MiB, i.e. %d bytes per session", len(keys), (heapAfter-heapBefore)>>20, bytesPerSession)
+	require.LessOrEqual(t, bytesPerSession, int64(maxBytesPerCachedSession))
+
+	start := time.Now()
+	for _, key := range keys {
+		sessionInfo, err := sessionCache.GetSession(context.Background(), key.AppAddress, key.ServiceId, 2)
+		require.NoError(t, err)
+		require.Equal(t, sdk.SessionSourceCache, sessionInfo.Source)
+	}
+	lookup := time.Since(start) / time.Duration(len(keys))
+	t.Logf("cached session lookup: %s", lookup)
+	require.LessOrEqual(t, lookup, maxCachedSessionLookup)
+
+	runtime.KeepAlive(sessionCache)
+}
+
+func TestLargeGateway_RingConstruction(t *testing.T) {
+	fixture, err := largeGateway()
+	require.NoError(t, err)
+
+	apps := fixture.Apps[:1_000]
+	start := time.Now()
+	for _, app := range apps {
+		appRing, err := fixture.ApplicationRing(app.Address)
+		require.NoError(t, err)
+		_, err = appRing.GetRing(context.Background(), uint64(fixture.Config.NumBlocksPerSession))
+		require.NoError(t, err)
+	}
+	construction := time.Since(start) / time.Duration(len(apps))
+	t.Logf("ring construction: %s", construction)
+	require.LessOrEqual(t, construction, maxRingConstruction)
+}
+
+func BenchmarkSessionCache_GetSession(b *testing.B) {
+	fixture, err := largeGateway()
+	require.NoError(b, err)
+	keys := fixture.SessionKeys()
+
+	sessionCache := sdk.NewSessionCache(&sdk.SessionClient{PoktNodeSessionFetcher: fixture}, cache.Config{})
+	for _, key := range keys {
+		_, err := sessionCache.GetSession(context.Background(), key.AppAddress, key.ServiceId, 1)
+		require.NoError(b, err)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			if _, err := sessionCache.GetSession(context.Background(), key.AppAddress, key.ServiceId, 1); err != nil {
+				b.Error(err)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkApplicationRing_GetRing(b *testing.B) {
+	fixture, err := largeGateway()
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := range b.N {
+		appRing, err := fixture.ApplicationRing(fixture.Apps[i%len(fixture.Apps)].Address)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := appRing.GetRing(context.Background(), uint64(fixture.Config.NumBlocksPerSession)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// heapAlloc returns the heap allocated after a garbage collection.
+func heapAlloc() uint64 {
+	runtime.GC()
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return memStats.HeapAlloc
+}
@@ -0,0 +1,403 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/gogoproto/grpc"
+	grpcoptions "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultNodeCooldown is the duration for which a POKT full node is not
+	// selected after failing a request, unless all the other nodes are failing too.
+	defaultNodeCooldown = 30 * time.Second
+
+	// defaultNodeProbeTimeout is the timeout of the health checks of the full nodes.
+	defaultNodeProbeTimeout = 5 * time.Second
+
+	// nodeLatencyWeight is the weight of the latest request latency in the
+	// moving average of a full node's latency.
+	nodeLatencyWeight = 0.2
+)
+
+// NodeSelectionOption is a functional option used to configure how requests
+// are spread across multiple POKT full nodes.
+type NodeSelectionOption func(*nodeSelector)
+
+// WithNodeRoundRobin spreads the requests across the healthy full nodes in
+// turn, instead of sticking to the same node as long as it is healthy.
+func WithNodeRoundRobin() NodeSelectionOption {
+	return func(s *nodeSelector) {
+		s.roundRobin = true
+	}
+}
+
+// WithNodeCooldown sets the duration for which a full node is not selected
+// after failing a request, unless all the other nodes are failing too.
+// It defaults to 30 seconds.
+func WithNodeCooldown(cooldown time.Duration) NodeSelectionOption {
+	return func(s *nodeSelector) {
+		s.cooldown = cooldown
+	}
+}
+
+// WithNodeHealthChecks makes a failed full node be selected again only once a
+// health check succeeds, instead of as soon as its cooldown ends: the node is
+// checked in the background at the end of each cooldown, e.g. by fetching its
+// status or latest block, and a node still failing starts a new cooldown.
+// A failed node is still selected if all the other nodes are failing too.
+func WithNodeHealthChecks() NodeSelectionOption {
+	return func(s *nodeSelector) {
+		s.healthChecks = true
+	}
+}
+
+// NewMultiNodeStatusFetcher returns a PoktNodeStatusFetcher which connects to
+// all the POKT full nodes at the given RPC URLs, and selects the node used for
+// each request based on its health and latency.
+//
+// Requests stick to the same node as long as it is healthy, and fail over to
+// the healthy node with the lowest latency otherwise, unless configured
+// otherwise using the given options.
+// With health checks enabled, the failed nodes are checked using their status,
+// and are not selected again while catching up.
+func NewMultiNodeStatusFetcher(queryNodeRpcUrls []string, opts ...NodeSelectionOption) (PoktNodeStatusFetcher, error) {
+	if len(queryNodeRpcUrls) == 0 {
+		return nil, errors.New("NewMultiNodeStatusFetcher: at least one RPC URL is required")
+	}
+
+	statusFetchers := make([]PoktNodeStatusFetcher, 0, len(queryNodeRpcUrls))
+	for _, queryNodeRpcUrl := range queryNodeRpcUrls {
+		statusFetcher, err := NewPoktNodeStatusFetcher(queryNodeRpcUrl)
+		if err != nil {
+			return nil, fmt.Errorf("NewMultiNodeStatusFetcher: error connecting to %s: %w", queryNodeRpcUrl, err)
+		}
+		statusFetchers = append(statusFetchers, statusFetcher)
+	}
+
+	selector := newNodeSelector(len(statusFetchers), opts...)
+	selector.probe = func(ctx context.Context, node int) error {
+		return probeStatusFetcher(ctx, statusFetchers[node])
+	}
+
+	return &multiNodeStatusFetcher{
+		statusFetchers: statusFetchers,
+		selector:       selector,
+	}, nil
+}
+
+// multiNodeStatusFetcher is a PoktNodeStatusFetcher failing over between
+// multiple POKT full nodes.
+type multiNodeStatusFetcher struct {
+	statusFetchers []PoktNodeStatusFetcher
+	selector       *nodeSelector
+}
+
+// Status returns the status of one of the POKT full nodes.
+func (f *multiNodeStatusFetcher) Status(ctx context.Context) (nodeStatus *ctypes.ResultStatus, err error) {
+	err = f.selector.do(ctx, isNodeError, func(node int) error {
+		nodeStatus, err = f.statusFetchers[node].Status(ctx)
+		return err
+	})
+	return nodeStatus, err
+}
+
+// NewFailoverGRPCConn returns a gRPC connection which sends each request
+// through one of the given connections to POKT full nodes, selected based on
+// its health and latency.
+//
+// Requests stick to the same connection as long as it is healthy, and fail
+// over to the healthy connection with the lowest latency otherwise.
+// Only errors caused by the full node being unavailable or not responding in
+// time trigger a failover: e.g. a NotFound error is returned as-is.
+//
+// The returned connection can be used anywhere a gRPC connection is expected,
+// e.g. by NewPoktNodeSessionFetcher or NewPoktNodeAccountFetcher.
+func NewFailoverGRPCConn(conns ...grpc.ClientConn) (grpc.ClientConn, error) {
+	return NewFailoverGRPCConnWithOptions(conns)
+}
+
+// NewFailoverGRPCConnWithOptions returns a gRPC connection which sends each
+// request through one of the given connections to POKT full nodes, like
+// NewFailoverGRPCConn, configured using the given options.
+// With health checks enabled, the failed connections are checked by fetching
+// the latest block of their full node.
+func NewFailoverGRPCConnWithOptions(conns []grpc.ClientConn, opts ...NodeSelectionOption) (grpc.ClientConn, error) {
+	if len(conns) == 0 {
+		return nil, errors.New("NewFailoverGRPCConnWithOptions: at least one connection is required")
+	}
+
+	selector := newNodeSelector(len(conns), opts...)
+	selector.probe = func(ctx context.Context, node int) error {
+		return probeGRPCConn(ctx, conns[node])
+	}
+
+	return &failoverGRPCConn{
+		conns:    conns,
+		selector: selector,
+	}, nil
+}
+
+// failoverGRPCConn is a gRPC connection failing over between multiple connections.
+type failoverGRPCConn struct {
+	conns    []grpc.ClientConn
+	selector *nodeSelector
+}
+
+// Invoke performs a unary RPC through one of the connections.
+func (c *failoverGRPCConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpcoptions.CallOption,
+) error {
+	return c.selector.do(ctx, isGRPCNodeError, func(node int) error {
+		return c.conns[node].Invoke(ctx, method, args, reply, opts...)
+	})
+}
+
+// NewStream begins a streaming RPC through one of the connections.
+// Only the creation of the stream fails over to another connection.
+func (c *failoverGRPCConn) NewStream(
+	ctx context.Context,
+	desc *grpcoptions.StreamDesc,
+	method string,
+	opts ...grpcoptions.CallOption,
+) (stream grpcoptions.ClientStream, err error) {
+	err = c.selector.do(ctx, isGRPCNodeError, func(node int) error {
+		stream, err = c.conns[node].NewStream(ctx, desc, method, opts...)
+		return err
+	})
+	return stream, err
+}
+
+// probeStatusFetcher checks the health of the full node of the given status
+// fetcher, which must respond and not be catching up.
+func probeStatusFetcher(ctx context.Context, statusFetcher PoktNodeStatusFetcher) error {
+	nodeStatus, err := statusFetcher.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if nodeStatus.SyncInfo.CatchingUp {
+		return errors.New("full node is catching up")
+	}
+	return nil
+}
+
+// probeGRPCConn checks the health of the full node at the other end of the
+// given gRPC connection, which must return its latest block.
+func probeGRPCConn(ctx context.Context, conn grpc.ClientConn) error {
+	_, err := NewGRPCHeightFetcher(conn).LatestBlockHeight(ctx)
+	return err
+}
+
+// isNodeError returns true for any error other than a context cancellation,
+// assuming the error is caused by the node.
+func isNodeError(err error) bool {
+	return !errors.Is(err, context.Canceled)
+}
+
+// isGRPCNodeError returns true if the given gRPC error is caused by the full
+// node being unavailable or not responding in time.
+func isGRPCNodeError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// nodeSelector selects, for each request, one of a set of POKT full nodes
+// based on their health and latency.
+// It is safe for concurrent use.
+type nodeSelector struct {
+	cooldown time.Duration
+	// roundRobin spreads the requests across the healthy nodes in turn,
+	// instead of sticking to the current node.
+	roundRobin bool
+	// healthChecks keeps the failed nodes unhealthy until probe succeeds.
+	healthChecks bool
+	// probe checks the health of the given node. It is set by the constructors
+	// of the failover clients, and only used if healthChecks is set.
+	probe func(ctx context.Context, node int) error
+	// now returns the current time. It is overridden in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	current int
+	// next is the position, among the healthy nodes, of the next node selected
+	// in round-robin mode.
+	next  int
+	nodes []nodeHealth
+}
+
+// nodeHealth tracks the health and latency of a single full node.
+type nodeHealth struct {
+	// latency is the moving average of the node's request latency.
+	latency time.Duration
+	// unhealthyUntil is the end of the cooldown following the node's latest failure.
+	unhealthyUntil time.Time
+	// failed is set from the node's latest failure until its next success.
+	failed bool
+	// probing is set while the health of the node is being checked.
+	probing bool
+}
+
+// newNodeSelector returns a nodeSelector for the given number of nodes,
+// configured using the given options.
+func newNodeSelector(numNodes int, opts ...NodeSelectionOption) *nodeSelector {
+	s := &nodeSelector{
+		cooldown: defaultNodeCooldown,
+		now:      time.Now,
+		nodes:    make([]nodeHealth, numNodes),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// do calls fn with the selected node, failing over to the next selected node
+// as long as fn fails with an error accepted by isFailoverErr.
+// Each node is tried at most once.
+func (s *nodeSelector) do(ctx context.Context, isFailoverErr func(error) bool, fn func(node int) error) error {
+	var errs []error
+	for _, node := range s.order() {
+		startTime := s.now()
+		err := fn(node)
+		if err == nil {
+			s.reportSuccess(node, s.now().Sub(startTime))
+			return nil
+		}
+
+		if !isFailoverErr(err) || ctx.Err() != nil {
+			return err
+		}
+
+		s.reportFailure(node)
+		errs = append(errs, fmt.Errorf("node %d: %w", node, err))
+	}
+
+	return fmt.Errorf("all full nodes failed: %w", errors.Join(errs...))
+}
+
+// order returns the nodes in the order they should be tried: the current node
+// if healthy, then the other healthy nodes by increasing latency, then the
+// unhealthy nodes by increasing end of cooldown.
+// In round-robin mode, the healthy nodes are instead rotated on every call.
+// The health checks of the failed nodes whose cooldown ended are started.
+func (s *nodeSelector) order() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var healthy, unhealthy []int
+	for node := range s.nodes {
+		if s.isHealthy(node, now) {
+			healthy = append(healthy, node)
+			continue
+		}
+
+		unhealthy = append(unhealthy, node)
+		if health := &s.nodes[node]; s.checksHealth() && !health.probing && !now.Before(health.unhealthyUntil) {
+			health.probing = true
+			go s.checkHealth(node)
+		}
+	}
+
+	if s.roundRobin && len(healthy) > 0 {
+		start := s.next % len(healthy)
+		s.next = start + 1
+		healthy = slices.Concat(healthy[start:], healthy[:start])
+	} else {
+		sort.SliceStable(healthy, func(i, j int) bool {
+			a, b := healthy[i], healthy[j]
+			if a == s.current || b == s.current {
+				return a == s.current
+			}
+			return s.nodes[a].latency < s.nodes[b].latency
+		})
+	}
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return s.nodes[unhealthy[i]].unhealthyUntil.Before(s.nodes[unhealthy[j]].unhealthyUntil)
+	})
+
+	return append(healthy, unhealthy...)
+}
+
+// isHealthy returns true if the given node is not in a failure cooldown, nor
+// waiting for a successful health check.
+// It must be called with the lock held.
+func (s *nodeSelector) isHealthy(node int, now time.Time) bool {
+	health := s.nodes[node]
+	if now.Before(health.unhealthyUntil) {
+		return false
+	}
+	return !health.failed || !s.checksHealth()
+}
+
+// checksHealth returns true if the failed nodes are only selected again once
+// their health check succeeds.
+func (s *nodeSelector) checksHealth() bool {
+	return s.healthChecks && s.probe != nil
+}
+
+// checkHealth checks the health of the given failed node: the node is healthy
+// again if the check succeeds, and starts a new cooldown otherwise.
+func (s *nodeSelector) checkHealth(node int) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultNodeProbeTimeout)
+	defer cancel()
+	err := s.probe(ctx, node)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := &s.nodes[node]
+	health.probing = false
+	if err != nil {
+		health.unhealthyUntil = s.now().Add(s.cooldown)
+		return
+	}
+	health.failed = false
+}
+
+// reportSuccess records a successful request to the given node, which becomes
+// the current node.
+func (s *nodeSelector) reportSuccess(node int, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := &s.nodes[node]
+	if health.latency == 0 {
+		health.latency = latency
+	} else {
+		health.latency = time.Duration(nodeLatencyWeight*float64(latency) + (1-nodeLatencyWeight)*float64(health.latency))
+	}
+	health.unhealthyUntil = time.Time{}
+	health.failed = false
+	s.current = node
+}
+
+// reportFailure records a failed request to the given node, starting its cooldown.
+func (s *nodeSelector) reportFailure(node int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nodes[node].unhealthyUntil = s.now().Add(s.cooldown)
+	s.nodes[node].failed = true
+}
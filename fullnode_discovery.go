@@ -0,0 +1,383 @@
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/gogoproto/grpc"
+	grpcoptions "google.golang.org/grpc"
+)
+
+// defaultNodeDiscoveryInterval is the interval at which the full nodes are
+// discovered again if no refresh interval is specified.
+const defaultNodeDiscoveryInterval = time.Minute
+
+// NodeResolver discovers the addresses of the POKT full nodes to connect to,
+// e.g. RPC URLs or gRPC targets.
+type NodeResolver interface {
+	ResolveNodes(ctx context.Context) ([]string, error)
+}
+
+// NodeResolverFunc is a function implementing the NodeResolver interface.
+type NodeResolverFunc func(ctx context.Context) ([]string, error)
+
+// ResolveNodes calls f.
+func (f NodeResolverFunc) ResolveNodes(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// NewDNSSRVNodeResolver returns a NodeResolver which looks up the full nodes in
+// the DNS SRV records of the given service, protocol, and domain name, e.g.
+// "_grpc._tcp.fullnodes.example.com" for ("grpc", "tcp", "fullnodes.example.com").
+//
+// The nodes are returned in the order of the records' priority and weight, as
+// "<scheme>://<host>:<port>", or as "<host>:<port>" if the scheme is empty,
+// e.g. to be used as gRPC targets.
+func NewDNSSRVNodeResolver(scheme, service, proto, name string) NodeResolver {
+	return NodeResolverFunc(func(ctx context.Context) ([]string, error) {
+		_, records, err := net.DefaultResolver.LookupSRV(ctx, service, proto, name)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up the SRV records of %s: %w", name, err)
+		}
+
+		addrs := make([]string, 0, len(records))
+		for _, record := range records {
+			addr := net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port)))
+			if scheme != "" {
+				addr = scheme + "://" + addr
+			}
+			addrs = append(addrs, addr)
+		}
+		return addrs, nil
+	})
+}
+
+// NewStaticFileNodeResolver returns a NodeResolver which reads the full nodes
+// from the file at the given path, holding one address per line.
+// Blank lines and lines starting with '#' are ignored.
+//
+// The file is read again on every resolution, so the full nodes can be rotated
+// by updating the file, e.g. a mounted ConfigMap, without restarting the gateway.
+func NewStaticFileNodeResolver(path string) NodeResolver {
+	return NodeResolverFunc(func(context.Context) ([]string, error) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the full nodes file: %w", err)
+		}
+
+		var addrs []string
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			addrs = append(addrs, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("error reading the full nodes file: %w", err)
+		}
+		return addrs, nil
+	})
+}
+
+// DiscoveredStatusFetcher is a PoktNodeStatusFetcher failing over between the
+// POKT full nodes discovered by a NodeResolver, like NewMultiNodeStatusFetcher.
+//
+// The full nodes are discovered again by Refresh, or periodically by Run, so
+// fleets can rotate their full nodes without reconfiguring every gateway.
+// The health and latency of the nodes are kept across discoveries.
+// It is safe for concurrent use.
+type DiscoveredStatusFetcher struct {
+	*nodeDiscovery[PoktNodeStatusFetcher]
+}
+
+// NewDiscoveredStatusFetcher returns a DiscoveredStatusFetcher connecting to the
+// RPC URLs discovered by the given resolver.
+// The full nodes are discovered once before returning, and then every
+// refreshInterval by Run, or every minute if refreshInterval is zero.
+// The requests are spread across the nodes as configured by the given options.
+// An error is returned if no discovered node can be connected to.
+func NewDiscoveredStatusFetcher(
+	ctx context.Context,
+	resolver NodeResolver,
+	refreshInterval time.Duration,
+	opts ...NodeSelectionOption,
+) (*DiscoveredStatusFetcher, error) {
+	connect := func(_ context.Context, queryNodeRpcUrl string) (PoktNodeStatusFetcher, error) {
+		return NewPoktNodeStatusFetcher(queryNodeRpcUrl)
+	}
+	discovery := newNodeDiscovery(resolver, refreshInterval, connect, probeStatusFetcher, opts...)
+	// Only fail if no node could be connected to: the others are tried again
+	// on the next refresh.
+	if err := discovery.Refresh(ctx); err != nil && len(discovery.Nodes()) == 0 {
+		return nil, fmt.Errorf("NewDiscoveredStatusFetcher: %w", err)
+	}
+
+	return &DiscoveredStatusFetcher{nodeDiscovery: discovery}, nil
+}
+
+// Status returns the status of one of the discovered full nodes.
+func (f *DiscoveredStatusFetcher) Status(ctx context.Context) (nodeStatus *ctypes.ResultStatus, err error) {
+	err = f.do(ctx, isNodeError, func(statusFetcher PoktNodeStatusFetcher) error {
+		nodeStatus, err = statusFetcher.Status(ctx)
+		return err
+	})
+	return nodeStatus, err
+}
+
+// DiscoveredGRPCConn is a gRPC connection failing over between the POKT full
+// nodes discovered by a NodeResolver, like NewFailoverGRPCConn.
+//
+// The full nodes are discovered again by Refresh, or periodically by Run, so
+// fleets can rotate their full nodes without reconfiguring every gateway.
+// The health and latency of the nodes are kept across discoveries.
+// The connections to the nodes which are no longer discovered are closed, if
+// they implement io.Closer.
+// It is safe for concurrent use.
+type DiscoveredGRPCConn struct {
+	*nodeDiscovery[grpc.ClientConn]
+}
+
+// NewDiscoveredGRPCConn returns a DiscoveredGRPCConn connecting, using the given
+// dial function, to the gRPC targets discovered by the given resolver.
+// The full nodes are discovered once before returning, and then every
+// refreshInterval by Run, or every minute if refreshInterval is zero.
+// The requests are spread across the nodes as configured by the given options.
+// An error is returned if no discovered node can be connected to.
+func NewDiscoveredGRPCConn(
+	ctx context.Context,
+	resolver NodeResolver,
+	refreshInterval time.Duration,
+	dial func(ctx context.Context, target string) (grpc.ClientConn, error),
+	opts ...NodeSelectionOption,
+) (*DiscoveredGRPCConn, error) {
+	discovery := newNodeDiscovery(resolver, refreshInterval, dial, probeGRPCConn, opts...)
+	// Only fail if no node could be connected to: the others are tried again
+	// on the next refresh.
+	if err := discovery.Refresh(ctx); err != nil && len(discovery.Nodes()) == 0 {
+		return nil, fmt.Errorf("NewDiscoveredGRPCConn: %w", err)
+	}
+
+	return &DiscoveredGRPCConn{nodeDiscovery: discovery}, nil
+}
+
+// Invoke performs a unary RPC through one of the discovered full nodes.
+func (c *DiscoveredGRPCConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpcoptions.CallOption,
+) error {
+	return c.do(ctx, isGRPCNodeError, func(conn grpc.ClientConn) error {
+		return conn.Invoke(ctx, method, args, reply, opts...)
+	})
+}
+
+// NewStream begins a streaming RPC through one of the discovered full nodes.
+// Only the creation of the stream fails over to another node.
+func (c *DiscoveredGRPCConn) NewStream(
+	ctx context.Context,
+	desc *grpcoptions.StreamDesc,
+	method string,
+	opts ...grpcoptions.CallOption,
+) (stream grpcoptions.ClientStream, err error) {
+	err = c.do(ctx, isGRPCNodeError, func(conn grpc.ClientConn) error {
+		stream, err = conn.NewStream(ctx, desc, method, opts...)
+		return err
+	})
+	return stream, err
+}
+
+// nodeDiscovery holds the clients of the full nodes discovered by a NodeResolver,
+// along with the nodeSelector selecting between them.
+type nodeDiscovery[T any] struct {
+	resolver        NodeResolver
+	refreshInterval time.Duration
+	connect         func(ctx context.Context, addr string) (T, error)
+	// probe checks the health of a node, see WithNodeHealthChecks.
+	probe         func(ctx context.Context, client T) error
+	selectionOpts []NodeSelectionOption
+
+	// refreshMu serializes the refreshes, which are the only writers of the
+	// discovered nodes.
+	refreshMu sync.Mutex
+
+	// mu protects the discovered nodes: addrs[i] is the address of clients[i],
+	// which is selector's node i.
+	mu       sync.RWMutex
+	addrs    []string
+	clients  []T
+	selector *nodeSelector
+}
+
+// newNodeDiscovery returns a nodeDiscovery connecting to the discovered nodes
+// using the given connect function, and checking their health using the given
+// probe function.
+func newNodeDiscovery[T any](
+	resolver NodeResolver,
+	refreshInterval time.Duration,
+	connect func(ctx context.Context, addr string) (T, error),
+	probe func(ctx context.Context, client T) error,
+	selectionOpts ...NodeSelectionOption,
+) *nodeDiscovery[T] {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultNodeDiscoveryInterval
+	}
+
+	return &nodeDiscovery[T]{
+		resolver:        resolver,
+		refreshInterval: refreshInterval,
+		connect:         connect,
+		probe:           probe,
+		selectionOpts:   selectionOpts,
+		selector:        newNodeSelector(0, selectionOpts...),
+	}
+}
+
+// Nodes returns the addresses of the currently discovered full nodes.
+func (d *nodeDiscovery[T]) Nodes() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return slices.Clone(d.addrs)
+}
+
+// Refresh discovers the full nodes again, connecting to the new nodes and
+// disconnecting from the nodes which are no longer discovered.
+// The current nodes are kept if the resolution fails, returns no node, or no
+// new node can be connected to.
+// The nodes which can not be connected to are skipped: their connection errors
+// are returned once the other nodes are in use.
+func (d *nodeDiscovery[T]) Refresh(ctx context.Context) error {
+	d.refreshMu.Lock()
+	defer d.refreshMu.Unlock()
+
+	resolvedAddrs, err := d.resolver.ResolveNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("error discovering the full nodes: %w", err)
+	}
+
+	// Deduplicate the addresses, keeping the resolver's order.
+	var addrs []string
+	for _, addr := range resolvedAddrs {
+		if !slices.Contains(addrs, addr) {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) == 0 {
+		return errors.New("no full node discovered")
+	}
+
+	d.mu.RLock()
+	previousAddrs, previousClients := d.addrs, d.clients
+	d.mu.RUnlock()
+
+	// Only connect to the new nodes.
+	var (
+		clients     []T
+		connectedTo []string
+		connectErrs []error
+	)
+	for _, addr := range addrs {
+		if i := slices.Index(previousAddrs, addr); i >= 0 {
+			clients = append(clients, previousClients[i])
+			connectedTo = append(connectedTo, addr)
+			continue
+		}
+
+		client, err := d.connect(ctx, addr)
+		if err != nil {
+			connectErrs = append(connectErrs, fmt.Errorf("error connecting to %s: %w", addr, err))
+			continue
+		}
+		clients = append(clients, client)
+		connectedTo = append(connectedTo, addr)
+	}
+	if len(clients) == 0 {
+		return fmt.Errorf("no discovered full node could be connected to: %w", errors.Join(connectErrs...))
+	}
+
+	d.mu.Lock()
+	// Carry over the health of the nodes which are still discovered.
+	selector := newNodeSelector(len(clients), d.selectionOpts...)
+	selector.now = d.selector.now
+	selector.probe = func(ctx context.Context, node int) error {
+		return d.probe(ctx, clients[node])
+	}
+	d.selector.mu.Lock()
+	for node, addr := range connectedTo {
+		previousNode := slices.Index(d.addrs, addr)
+		if previousNode < 0 {
+			continue
+		}
+		selector.nodes[node] = d.selector.nodes[previousNode]
+		// The ongoing health checks update the previous selector.
+		selector.nodes[node].probing = false
+		if previousNode == d.selector.current {
+			selector.current = node
+		}
+	}
+	d.selector.mu.Unlock()
+
+	removedClients := make([]T, 0, len(d.clients))
+	for i, addr := range d.addrs {
+		if !slices.Contains(connectedTo, addr) {
+			removedClients = append(removedClients, d.clients[i])
+		}
+	}
+
+	d.addrs, d.clients, d.selector = connectedTo, clients, selector
+	d.mu.Unlock()
+
+	for _, client := range removedClients {
+		if closer, ok := any(client).(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
+
+	return errors.Join(connectErrs...)
+}
+
+// Run discovers the full nodes again every refresh interval, until the given
+// context is done.
+// Discovery errors are ignored: the current nodes are kept until the next
+// successful discovery.
+func (d *nodeDiscovery[T]) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			_ = d.Refresh(ctx)
+		}
+	}
+}
+
+// do calls fn with the client of the selected node, failing over to the next
+// selected node as long as fn fails with an error accepted by isFailoverErr.
+// See nodeSelector.do.
+func (d *nodeDiscovery[T]) do(ctx context.Context, isFailoverErr func(error) bool, fn func(client T) error) error {
+	d.mu.RLock()
+	clients, selector := d.clients, d.selector
+	d.mu.RUnlock()
+
+	return selector.do(ctx, isFailoverErr, func(node int) error {
+		return fn(clients[node])
+	})
+}
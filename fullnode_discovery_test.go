@@ -0,0 +1,79 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cosmos/gogoproto/grpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStaticFileNodeResolver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fullnodes")
+	require.NoError(t, os.WriteFile(path, []byte("# gRPC full nodes\nnode1:9090\n\n  node2:9090  \n"), 0o600))
+
+	addrs, err := NewStaticFileNodeResolver(path).ResolveNodes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"node1:9090", "node2:9090"}, addrs)
+
+	_, err = NewStaticFileNodeResolver(filepath.Join(t.TempDir(), "missing")).ResolveNodes(context.Background())
+	require.Error(t, err)
+}
+
+func TestDiscoveredGRPCConn(t *testing.T) {
+	resolvedAddrs := []string{"node0", "node1"}
+	var resolveErr error
+	resolver := NodeResolverFunc(func(context.Context) ([]string, error) {
+		return resolvedAddrs, resolveErr
+	})
+
+	conns := map[string]*fakeClientConn{}
+	dial := func(_ context.Context, target string) (grpc.ClientConn, error) {
+		if target == "unreachable" {
+			return nil, errors.New("connection refused")
+		}
+		conns[target] = &fakeClientConn{}
+		return conns[target], nil
+	}
+
+	ctx := context.Background()
+	conn, err := NewDiscoveredGRPCConn(ctx, resolver, time.Minute, dial)
+	require.NoError(t, err)
+	require.Equal(t, []string{"node0", "node1"}, conn.Nodes())
+
+	// node0 fails, so requests stick to node1.
+	conns["node0"].err = status.Error(codes.Unavailable, "unavailable")
+	require.NoError(t, conn.Invoke(ctx, "method", nil, nil))
+	require.Equal(t, 1, conns["node1"].calls)
+
+	// A new node is discovered, and node0 is no longer discovered: node1 is only
+	// connected to once, and stays the current node.
+	node1 := conns["node1"]
+	resolvedAddrs = []string{"node2", "node1", "unreachable"}
+	require.ErrorContains(t, conn.Refresh(ctx), "connection refused")
+	require.Equal(t, []string{"node2", "node1"}, conn.Nodes())
+	require.Same(t, node1, conns["node1"])
+
+	require.NoError(t, conn.Invoke(ctx, "method", nil, nil))
+	require.Equal(t, 2, conns["node1"].calls)
+	require.Equal(t, 0, conns["node2"].calls)
+
+	// The current nodes are kept if the discovery fails.
+	resolveErr = errors.New("lookup failed")
+	require.Error(t, conn.Refresh(ctx))
+	resolveErr, resolvedAddrs = nil, nil
+	require.Error(t, conn.Refresh(ctx))
+	require.Equal(t, []string{"node2", "node1"}, conn.Nodes())
+
+	// No node can be connected to.
+	_, err = NewDiscoveredGRPCConn(ctx, NodeResolverFunc(func(context.Context) ([]string, error) {
+		return []string{"unreachable"}, nil
+	}), 0, dial)
+	require.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFailoverGRPCConn(t *testing.T) {
+	conn0 := &fakeClientConn{}
+	conn1 := &fakeClientConn{}
+	conn, err := NewFailoverGRPCConn(conn0, conn1)
+	require.NoError(t, err)
+
+	now := time.Now()
+	conn.(*failoverGRPCConn).selector.now = func() time.Time { return now }
+
+	ctx := context.Background()
+
+	// Requests stick to the first connection while it is healthy.
+	require.NoError(t, conn.Invoke(ctx, "method", nil, nil))
+	require.NoError(t, conn.Invoke(ctx, "method", nil, nil))
+	require.Equal(t, 2, conn0.calls)
+	require.Equal(t, 0, conn1.calls)
+
+	// Application errors are returned as-is, without failing over.
+	conn0.err = status.Error(codes.NotFound, "not found")
+	require.Equal(t, codes.NotFound, status.Code(conn.Invoke(ctx, "method", nil, nil)))
+	require.Equal(t, 0, conn1.calls)
+
+	// Unavailable nodes trigger a failover, which sticks to the new connection.
+	conn0.err = status.Error(codes.Unavailable, "unavailable")
+	require.NoError(t, conn.Invoke(ctx, "method", nil, nil))
+	require.NoError(t, conn.Invoke(ctx, "method", nil, nil))
+	require.Equal(t, 4, conn0.calls)
+	require.Equal(t, 2, conn1.calls)
+
+	// The failed connection is tried again once all the others fail.
+	conn0.err = nil
+	conn1.err = status.Error(codes.Unavailable, "unavailable")
+	require.NoError(t, conn.Invoke(ctx, "method", nil, nil))
+	require.Equal(t, 5, conn0.calls)
+
+	// An error is returned once all the connections fail.
+	conn0.err = status.Error(codes.Unavailable, "unavailable")
+	require.Error(t, conn.Invoke(ctx, "method", nil, nil))
+}
+
+func TestNodeSelector_Order(t *testing.T) {
+	selector := newNodeSelector(3)
+	now := time.Now()
+	selector.now = func() time.Time { return now }
+
+	selector.reportSuccess(2, 30*time.Millisecond)
+	selector.reportSuccess(1, 10*time.Millisecond)
+	selector.reportSuccess(0, 20*time.Millisecond)
+
+	// The current node first, then the other healthy nodes by latency.
+	require.Equal(t, []int{0, 1, 2}, selector.order())
+
+	selector.reportFailure(0)
+	require.Equal(t, []int{1, 2, 0}, selector.order())
+
+	// The cooldown expires.
+	now = now.Add(defaultNodeCooldown)
+	require.Equal(t, []int{0, 1, 2}, selector.order())
+}
+
+func TestNodeSelector_RoundRobin(t *testing.T) {
+	selector := newNodeSelector(3, WithNodeRoundRobin())
+	now := time.Now()
+	selector.now = func() time.Time { return now }
+
+	// The healthy nodes are used in turn, regardless of their latency.
+	selector.reportSuccess(2, 10*time.Millisecond)
+	require.Equal(t, []int{0, 1, 2}, selector.order())
+	require.Equal(t, []int{1, 2, 0}, selector.order())
+	require.Equal(t, []int{2, 0, 1}, selector.order())
+	require.Equal(t, []int{0, 1, 2}, selector.order())
+
+	// The unhealthy nodes are skipped, and tried last.
+	selector.reportFailure(1)
+	require.Equal(t, []int{2, 0, 1}, selector.order())
+	require.Equal(t, []int{0, 2, 1}, selector.order())
+}
+
+func TestNodeSelector_HealthChecks(t *testing.T) {
+	selector := newNodeSelector(2, WithNodeHealthChecks(), WithNodeCooldown(time.Second))
+	now := time.Now()
+	selector.now = func() time.Time { return now }
+
+	probeErrs := make(chan error)
+	selector.probe = func(context.Context, int) error { return <-probeErrs }
+	// probed waits for the ongoing health check to complete.
+	probed := func(err error) {
+		probeErrs <- err
+		require.Eventually(t, func() bool {
+			selector.mu.Lock()
+			defer selector.mu.Unlock()
+			return !selector.nodes[0].probing
+		}, time.Second, time.Millisecond)
+	}
+
+	selector.reportSuccess(1, 20*time.Millisecond)
+	selector.reportSuccess(0, 10*time.Millisecond)
+	selector.reportFailure(0)
+	require.Equal(t, []int{1, 0}, selector.order())
+
+	// The node stays unhealthy after its cooldown, until a health check succeeds.
+	now = now.Add(time.Second)
+	require.Equal(t, []int{1, 0}, selector.order())
+	probed(errors.New("full node is catching up"))
+
+	// A failed health check starts a new cooldown.
+	now = now.Add(time.Second / 2)
+	require.Equal(t, []int{1, 0}, selector.order())
+	now = now.Add(time.Second / 2)
+	require.Equal(t, []int{1, 0}, selector.order())
+	probed(nil)
+
+	// The node is healthy again, and selected first as the current node.
+	require.Equal(t, []int{0, 1}, selector.order())
+}
+
+// fakeClientConn is a gRPC connection returning a configurable error.
+type fakeClientConn struct {
+	calls int
+	err   error
+}
+
+func (c *fakeClientConn) Invoke(context.Context, string, interface{}, interface{}, ...grpcoptions.CallOption) error {
+	c.calls++
+	return c.err
+}
+
+func (c *fakeClientConn) NewStream(
+	context.Context,
+	*grpcoptions.StreamDesc,
+	string,
+	...grpcoptions.CallOption,
+) (grpcoptions.ClientStream, error) {
+	c.calls++
+	return nil, c.err
+}
@@ -0,0 +1,522 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// GatewayClient runs the relay pipeline of a gateway: for each request, it gets
+// the current session of the application and service, selects one of the
+// session's endpoints, builds and signs the relay request, sends it, and
+// validates the supplier's response.
+//
+// The components are set by the caller, so each step of the pipeline can be
+// configured, e.g. the SessionCache's TTL or the RelaySender's transport.
+// A GatewayClient is safe for concurrent use if its components are.
+type GatewayClient struct {
+	BlockClient      BlockQuerier
+	SessionCache     *SessionCache
+	Signer           RelayRequestSigner
+	PublicKeyFetcher PublicKeyFetcher
+	SendRelay        RelaySender
+
+	// SessionFilter, if set, specifies how the endpoints of the sessions are
+	// filtered, e.g. using its EndpointFilters. Its Session is set per relay.
+	SessionFilter SessionFilter
+	// SelectEndpoint, if set, selects the endpoint to relay to among the
+	// filtered endpoints of the session. The SessionFilter's Selector selects
+	// it otherwise.
+	SelectEndpoint func(endpoints []Endpoint) Endpoint
+	// RelayOutcomeObserver, if set, is notified of the outcome of every relay
+	// attempt sent to an endpoint, e.g. an EndpointStats used by the
+	// SessionFilter's Selector. The attempts failing before the relay request
+	// is sent, e.g. to be signed, or canceled by the caller are not reported,
+	// since they do not reflect the endpoint's quality.
+	RelayOutcomeObserver RelayOutcomeObserver
+	// RequestTransformer, if set, adapts the requests to the services' backends
+	// before they are signed.
+	RequestTransformer *RequestTransformer
+	// RelaySigners, if set, returns the Signer of each relay's application
+	// instead of Signer, e.g. the applications' own keys in centralized mode.
+	RelaySigners RelaySignerGetter
+	// Metrics, if set, records the durations of the signing of the relay
+	// requests and of the validation of the relay responses.
+	Metrics MetricsRecorder
+	// RelayRetry, if set, specifies how the failed relays are retried, each
+	// retry being sent to a newly selected endpoint of the session.
+	// The relays are not retried otherwise.
+	RelayRetry *retry.Config
+	// RelayTTL, if set, is the time allowed for a relay, including its retries,
+	// measured from the start time carried by the relay's context, set using
+	// ContextWithRelayStart, or from the call to Relay otherwise.
+	// Once it elapses, the relay in flight is abandoned, and no retry is sent
+	// even if retries remain: the relay fails with a *RelayExpiredError.
+	RelayTTL time.Duration
+	// RelayInterceptors, if set, wrap the signing, sending and validation of
+	// every relay attempt, in order, i.e. the first one is the outermost.
+	RelayInterceptors []RelayInterceptor
+	// Intake, if set, queues the relays ahead of the signing and sending
+	// pipeline, admitting them according to the priority class carried by their
+	// context, set using ContextWithRelayPriority. The time spent queued counts
+	// towards the RelayTTL.
+	Intake *RelayIntake
+	// LoadShedder, if set, limits the number of concurrent relays of each
+	// service, rejecting the relays exceeding the limit of their service with
+	// ErrRelayLoadShed. The relays are admitted to it once admitted by the
+	// Intake, and the time spent waiting for a slot counts towards the RelayTTL.
+	LoadShedder *LoadShedder
+	// Logger, if set, logs the relays: the failed relay attempts at warn, and
+	// the details of each relay attempt at debug, for a sample of the relays.
+	// Its level and debug sample rate can be adjusted at runtime.
+	Logger *RelayLogger
+	// EventBus, if set, is notified of every relay attempt sent to an endpoint
+	// which failed, as a SupplierFailedEvent, and of the relays which could not
+	// be signed because the signer is no longer in the application's ring, as a
+	// DelegationChangedEvent, so the SessionCache and the
+	// DelegatingApplicationsCache subscribed to it re-check the delegations.
+	EventBus *EventBus
+	// MethodPolicy, if set, rejects the relays calling a JSON-RPC method not
+	// allowed for their service with ErrJSONRPCMethodNotAllowed, before they
+	// are queued, signed or sent.
+	MethodPolicy *JSONRPCMethodPolicy
+	// ResponseValidator, if set, validates the suppliers' relay responses, e.g.
+	// to trust the suppliers in centralized mode, or to pass through degraded the
+	// responses failing basic validation, which are then returned with a Warning
+	// header. A RelayResponseValidator using the PublicKeyFetcher is used otherwise.
+	ResponseValidator *RelayResponseValidator
+}
+
+// GatewayClientOption is a functional option used to configure a GatewayClient.
+type GatewayClientOption func(*GatewayClient)
+
+// WithGatewayLogger sets the RelayLogger logging the relays of the GatewayClient.
+func WithGatewayLogger(logger *RelayLogger) GatewayClientOption {
+	return func(gc *GatewayClient) {
+		gc.Logger = logger
+	}
+}
+
+// WithGatewayMetrics sets the MetricsRecorder recording the durations of the
+// signing and validation of the relays of the GatewayClient.
+func WithGatewayMetrics(recorder MetricsRecorder) GatewayClientOption {
+	return func(gc *GatewayClient) {
+		gc.Metrics = recorder
+	}
+}
+
+// WithGatewayMethodPolicy sets the JSONRPCMethodPolicy checked by the
+// GatewayClient before relaying a request.
+func WithGatewayMethodPolicy(policy *JSONRPCMethodPolicy) GatewayClientOption {
+	return func(gc *GatewayClient) {
+		gc.MethodPolicy = policy
+	}
+}
+
+// WithGatewayResponseValidator sets the RelayResponseValidator validating the
+// relay responses of the GatewayClient.
+func WithGatewayResponseValidator(validator *RelayResponseValidator) GatewayClientOption {
+	return func(gc *GatewayClient) {
+		gc.ResponseValidator = validator
+	}
+}
+
+// WithGatewayHTTPClient sets the GatewayClient's SendRelay to a RelaySender
+// sending the relays using the given HTTP client, configured using the given
+// options. See NewHTTPRelaySender.
+func WithGatewayHTTPClient(httpClient *http.Client, opts ...RelaySenderOption) GatewayClientOption {
+	return func(gc *GatewayClient) {
+		gc.SendRelay = NewHTTPRelaySender(httpClient, opts...)
+	}
+}
+
+// NewGatewayClient returns a GatewayClient using the given components,
+// configured using the given options.
+// The other optional fields of the GatewayClient, e.g. RelayRetry, can be set
+// on the returned GatewayClient before its first relay.
+func NewGatewayClient(
+	blockClient BlockQuerier,
+	sessionCache *SessionCache,
+	signer RelayRequestSigner,
+	publicKeyFetcher PublicKeyFetcher,
+	opts ...GatewayClientOption,
+) *GatewayClient {
+	gc := &GatewayClient{
+		BlockClient:      blockClient,
+		SessionCache:     sessionCache,
+		Signer:           signer,
+		PublicKeyFetcher: publicKeyFetcher,
+		SendRelay:        NewHTTPRelaySender(nil),
+	}
+	for _, opt := range opts {
+		opt(gc)
+	}
+
+	return gc
+}
+
+// RelaySignerGetter returns the signer of the relay requests of an application.
+// It is implemented by AppKeyStore, which holds the keys of the applications
+// owned by a gateway running in centralized mode.
+type RelaySignerGetter interface {
+	GetRelaySigner(ctx context.Context, appAddress string) (RelayRequestSigner, error)
+}
+
+// Relay relays the given serialized POKTHTTPRequest, e.g. built using
+// types.SerializeHTTPRequest, to a supplier of the current session of the
+// given application and service, and returns the supplier's validated response.
+func (gc *GatewayClient) Relay(
+	ctx context.Context,
+	appAddress string,
+	serviceId string,
+	requestBz []byte,
+) (*types.POKTHTTPResponse, error) {
+	if gc.BlockClient == nil || gc.SessionCache == nil || (gc.Signer == nil && gc.RelaySigners == nil) ||
+		gc.PublicKeyFetcher == nil || gc.SendRelay == nil {
+		return nil, sdkerrors.Wrap(
+			sdkerrors.ErrNotConfigured,
+			"Relay: BlockClient, SessionCache, Signer or RelaySigners, PublicKeyFetcher and SendRelay must all be set",
+		)
+	}
+
+	if gc.MethodPolicy != nil {
+		if err := gc.MethodPolicy.CheckPayload(serviceId, requestBz); err != nil {
+			return nil, fmt.Errorf("Relay: %w", err)
+		}
+	}
+
+	ttl := newRelayTTL(ctx, gc.RelayTTL)
+
+	if gc.Intake != nil {
+		intakeCtx, cancel := ttl.context(ctx)
+		release, err := gc.Intake.Acquire(intakeCtx, RelayPriorityFromContext(ctx))
+		cancel()
+		if err != nil {
+			if expiredErr := ttl.expiredError(0, err); expiredErr != nil {
+				err = expiredErr
+			}
+			return nil, fmt.Errorf("Relay: %w", err)
+		}
+		defer release()
+	}
+
+	if gc.LoadShedder != nil {
+		loadShedderCtx, cancel := ttl.context(ctx)
+		release, err := gc.LoadShedder.Acquire(loadShedderCtx, serviceId)
+		cancel()
+		if err != nil {
+			if expiredErr := ttl.expiredError(0, err); expiredErr != nil {
+				err = expiredErr
+			}
+			return nil, fmt.Errorf("Relay: %w", err)
+		}
+		defer release()
+	}
+
+	signer := gc.Signer
+	if gc.RelaySigners != nil {
+		var err error
+		if signer, err = gc.RelaySigners.GetRelaySigner(ctx, appAddress); err != nil {
+			return nil, fmt.Errorf("Relay: %w", err)
+		}
+	}
+
+	height, err := gc.BlockClient.LatestBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Relay: error getting the latest block height: %w", err)
+	}
+
+	session, err := gc.SessionCache.GetSession(ctx, appAddress, serviceId, height)
+	if err != nil {
+		return nil, fmt.Errorf("Relay: error getting the session: %w", err)
+	}
+	if session.Application == nil {
+		return nil, fmt.Errorf("Relay: session %s has no application", session.SessionId)
+	}
+	if IsApplicationUnbonding(*session.Application) {
+		return nil, fmt.Errorf("Relay: %w: application %s", ErrApplicationUnbonding, appAddress)
+	}
+
+	logger := gc.Logger.relayLogger().With("app_address", appAddress, "service_id", serviceId)
+	return gc.relayWithRetries(ctx, ttl, logger, signer, session, serviceId, requestBz)
+}
+
+// relayWithRetries relays the given request to an endpoint of the given
+// session, retrying with a newly selected endpoint according to RelayRetry,
+// until the relay succeeds or the given TTL elapses.
+func (gc *GatewayClient) relayWithRetries(
+	ctx context.Context,
+	ttl relayTTL,
+	logger *slog.Logger,
+	signer RelayRequestSigner,
+	session SessionInfo,
+	serviceId string,
+	requestBz []byte,
+) (*types.POKTHTTPResponse, error) {
+	retryConfig := retry.Config{}
+	if gc.RelayRetry != nil {
+		retryConfig = *gc.RelayRetry
+	}
+	shouldRetry := retryConfig.ShouldRetry
+	retryConfig.ShouldRetry = func(err error) bool {
+		return !errors.Is(err, ErrRelayExpired) && (shouldRetry == nil || shouldRetry(err))
+	}
+
+	ctx, cancel := ttl.context(ctx)
+	defer cancel()
+
+	var (
+		poktHTTPResponse *types.POKTHTTPResponse
+		attempts         int
+		lastErr          error
+	)
+	err := retry.Do(ctx, retryConfig, func(ctx context.Context) error {
+		// The TTL is checked before every attempt, including the first one,
+		// since the relay may have started before Relay was called.
+		if err := ttl.expiredError(attempts, lastErr); err != nil {
+			return err
+		}
+
+		attempts++
+		poktHTTPResponse, lastErr = gc.relayAttempt(ctx, logger.With("attempt", attempts), signer, session, serviceId, requestBz)
+		return lastErr
+	})
+	if err != nil {
+		// The TTL may also elapse during an attempt, or while waiting to retry.
+		if !errors.Is(err, ErrRelayExpired) {
+			if expiredErr := ttl.expiredError(attempts, lastErr); expiredErr != nil {
+				err = expiredErr
+			}
+		}
+		return nil, fmt.Errorf("Relay: %w", err)
+	}
+
+	return poktHTTPResponse, nil
+}
+
+// relayAttempt relays the given request to an endpoint of the given session,
+// and returns the supplier's validated response.
+func (gc *GatewayClient) relayAttempt(
+	ctx context.Context,
+	logger *slog.Logger,
+	signer RelayRequestSigner,
+	session SessionInfo,
+	serviceId string,
+	requestBz []byte,
+) (*types.POKTHTTPResponse, error) {
+	endpoint, err := gc.selectEndpoint(ctx, session)
+	if err != nil {
+		logger.Warn("relay attempt failed: no endpoint selected", "session_id", session.SessionId, "error", err)
+		return nil, err
+	}
+	logger = logger.With("session_id", session.SessionId, "supplier", endpoint.Supplier(), "endpoint_url", endpoint.Endpoint().Url)
+
+	var relayRequest *servicetypes.RelayRequest
+	if gc.RequestTransformer != nil {
+		relayRequest, err = gc.RequestTransformer.BuildRelayRequest(endpoint, requestBz)
+	} else {
+		relayRequest, err = BuildRelayRequest(endpoint, requestBz)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error building the relay request: %w", err)
+	}
+
+	// sent records whether the relay request reached SendRelay, so the
+	// failures on the gateway side, e.g. signing errors, are not attributed to
+	// the endpoint, and degraded whether the relay response was passed through
+	// degraded by the ResponseValidator.
+	var sent, degraded atomic.Bool
+	invoke := gc.invokeRelay(signer, *session.Application, serviceId, &sent, &degraded)
+	if len(gc.RelayInterceptors) > 0 {
+		invoke = ChainRelayInterceptors(gc.RelayInterceptors...)(invoke)
+	}
+	relayStart := time.Now()
+	relayResponse, err := invoke(ctx, endpoint, relayRequest)
+	latency := time.Since(relayStart)
+	isEndpointOutcome := isEndpointRelayOutcome(ctx, sent.Load(), err)
+	if gc.RelayOutcomeObserver != nil && isEndpointOutcome {
+		gc.RelayOutcomeObserver.ObserveRelayOutcome(endpoint, latency, err)
+	}
+	if err != nil && isEndpointOutcome {
+		gc.EventBus.Publish(SupplierFailedEvent{ServiceId: serviceId, Supplier: endpoint.Supplier(), Err: err})
+	}
+	if err != nil {
+		logger.Warn("relay attempt failed", "latency", latency, "error", err)
+		return nil, err
+	}
+	logger.Debug("relay attempt succeeded", "latency", latency, "response_bytes", len(relayResponse.GetPayload()))
+	if relayResponse == nil {
+		return nil, fmt.Errorf("no relay response from supplier %s", endpoint.Supplier())
+	}
+
+	poktHTTPResponse, err := types.DeserializeHTTPResponse(relayResponse.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing the relay response payload: %w", err)
+	}
+	if degraded.Load() {
+		logger.Debug("relay response passed through degraded")
+		addDegradedWarning(poktHTTPResponse)
+	}
+
+	return poktHTTPResponse, nil
+}
+
+// addDegradedWarning adds the Warning header of the degraded relay responses to
+// the given POKTHTTPResponse. See ValidatedRelayResponse.AddDegradedWarning.
+func addDegradedWarning(poktHTTPResponse *types.POKTHTTPResponse) {
+	if poktHTTPResponse.Header == nil {
+		poktHTTPResponse.Header = make(map[string]*types.Header)
+	}
+	warning, ok := poktHTTPResponse.Header["Warning"]
+	if !ok || warning == nil {
+		warning = &types.Header{Key: "Warning"}
+		poktHTTPResponse.Header["Warning"] = warning
+	}
+	warning.Values = append(warning.Values, degradedResponseWarning)
+}
+
+// isEndpointRelayOutcome returns true if the outcome of a relay attempt which
+// failed with the given error, if any, reflects the endpoint's quality, i.e. if
+// the relay request was sent, and the attempt was not canceled by the caller.
+func isEndpointRelayOutcome(ctx context.Context, sent bool, err error) bool {
+	if !sent {
+		return false
+	}
+	return !(errors.Is(err, context.Canceled) && ctx.Err() != nil)
+}
+
+// invokeRelay returns the RelayInvoker signing the relay requests of the given
+// application using the given Signer, sending them, and validating the
+// suppliers' responses. It is the innermost RelayInvoker of the interceptors.
+// The given sent flag is set once a relay request is passed to SendRelay, and
+// the given degraded flag once a relay response is passed through degraded.
+func (gc *GatewayClient) invokeRelay(
+	signer RelayRequestSigner,
+	app apptypes.Application,
+	serviceId string,
+	sent *atomic.Bool,
+	degraded *atomic.Bool,
+) RelayInvoker {
+	return func(
+		ctx context.Context,
+		endpoint Endpoint,
+		relayRequest *servicetypes.RelayRequest,
+	) (*servicetypes.RelayResponse, error) {
+		appRing := ApplicationRing{
+			Application:      app,
+			PublicKeyFetcher: gc.PublicKeyFetcher,
+		}
+		signStart := time.Now()
+		relayRequest, err := signer.Sign(ctx, relayRequest, appRing)
+		if gc.Metrics != nil {
+			gc.Metrics.ObserveRelaySign(serviceId, time.Since(signStart), err)
+		}
+		if err != nil {
+			if errors.Is(err, ErrSignerNotInRing) {
+				// The application's delegations have changed since its session
+				// was fetched: have them re-checked through the EventBus.
+				event := DelegationChangedEvent{AppAddress: app.Address}
+				var notInRingErr *SignerNotInRingError
+				if errors.As(err, &notInRingErr) {
+					event.GatewayAddress = notInRingErr.SignerAddress
+				}
+				gc.EventBus.Publish(event)
+			}
+			return nil, fmt.Errorf("error signing the relay request: %w", err)
+		}
+
+		sent.Store(true)
+		relayResponseBz, err := gc.SendRelay(ctx, endpoint, relayRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		validator := RelayResponseValidator{PublicKeyFetcher: gc.PublicKeyFetcher}
+		if gc.ResponseValidator != nil {
+			validator = *gc.ResponseValidator
+		}
+		validationStart := time.Now()
+		validatedResponse, err := validator.Validate(ctx, endpoint.Supplier(), relayResponseBz)
+		if gc.Metrics != nil {
+			gc.Metrics.ObserveRelayValidation(serviceId, time.Since(validationStart), err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error validating the relay response of supplier %s: %w", endpoint.Supplier(), err)
+		}
+
+		degraded.Store(validatedResponse.Degraded())
+		return validatedResponse.RelayResponse, nil
+	}
+}
+
+// PrefetchRingPubKeys fetches the public keys of the members of the rings of
+// the given applications, fetched using the given ApplicationQuerier, i.e. the
+// applications and all the gateways they delegate to, including the gateways
+// being undelegated from.
+// It is meant to be called on startup, with a caching PublicKeyFetcher such as
+// a CachedAccountClient, so the first relays of each application do not wait
+// for the public keys of its ring to be fetched.
+func (gc *GatewayClient) PrefetchRingPubKeys(
+	ctx context.Context,
+	appClient ApplicationQuerier,
+	appAddresses ...string,
+) error {
+	if gc.PublicKeyFetcher == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "PrefetchRingPubKeys: PublicKeyFetcher not set")
+	}
+	if appClient == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "PrefetchRingPubKeys: ApplicationQuerier not set")
+	}
+
+	var ringAddresses []string
+	for _, appAddress := range appAddresses {
+		app, err := appClient.GetApplication(ctx, appAddress)
+		if err != nil {
+			return fmt.Errorf("PrefetchRingPubKeys: error getting application %s: %w", appAddress, err)
+		}
+
+		ringAddresses = append(ringAddresses, app.Address)
+		ringAddresses = append(ringAddresses, app.DelegateeGatewayAddresses...)
+		for _, undelegatingGateways := range app.PendingUndelegations {
+			ringAddresses = append(ringAddresses, undelegatingGateways.GatewayAddresses...)
+		}
+	}
+
+	if _, err := getPubKeys(ctx, gc.PublicKeyFetcher, ringAddresses); err != nil {
+		return fmt.Errorf("PrefetchRingPubKeys: %w", err)
+	}
+	return nil
+}
+
+// selectEndpoint returns the endpoint of the given session to relay to.
+func (gc *GatewayClient) selectEndpoint(ctx context.Context, session SessionInfo) (Endpoint, error) {
+	sessionFilter := gc.SessionFilter
+	sessionFilter.Session = session.Session
+
+	if gc.SelectEndpoint == nil {
+		return sessionFilter.SelectEndpoint(ctx)
+	}
+
+	endpoints, err := sessionFilter.FilteredEndpoints(WithEndpointExclusions(EndpointExclusionsFromContext(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("error getting the session endpoints: %w", err)
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoints in session %s", session.SessionId)
+	}
+	if endpoint := gc.SelectEndpoint(endpoints); endpoint != nil {
+		return endpoint, nil
+	}
+	return nil, fmt.Errorf("no endpoint selected in session %s", session.SessionId)
+}
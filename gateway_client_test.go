@@ -0,0 +1,460 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestGatewayClient_PrefetchRingPubKeys(t *testing.T) {
+	pubKeys := make(map[string]cryptotypes.PubKey)
+	newAddress := func() string {
+		pubKey := secp256k1.GenPrivKey().PubKey()
+		address, err := PubKeyToAddress(PoktAddressPrefix, pubKey)
+		require.NoError(t, err)
+		pubKeys[address] = pubKey
+		return address
+	}
+	appAddress, gatewayAddress, undelegatedGatewayAddress := newAddress(), newAddress(), newAddress()
+
+	appClient := &ApplicationClient{
+		QueryClient: &fakeAppQueryClient{apps: map[string]apptypes.Application{
+			appAddress: {
+				Address:                   appAddress,
+				DelegateeGatewayAddresses: []string{gatewayAddress},
+				PendingUndelegations: map[uint64]apptypes.UndelegatingGatewayList{
+					10: {GatewayAddresses: []string{undelegatedGatewayAddress}},
+				},
+			},
+		}},
+	}
+
+	fetcher := &countingPubKeyFetcher{pubKeys: pubKeys}
+	cachedClient := NewCachedAccountClient(fetcher)
+	gc := &GatewayClient{PublicKeyFetcher: cachedClient}
+
+	ctx := context.Background()
+	require.NoError(t, gc.PrefetchRingPubKeys(ctx, appClient, appAddress))
+	require.Equal(t, int64(3), fetcher.calls.Load())
+
+	// The ring's public keys are then served from the cache.
+	_, err := cachedClient.GetPubKeys(ctx, []string{appAddress, gatewayAddress, undelegatedGatewayAddress})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), fetcher.calls.Load())
+
+	require.Error(t, gc.PrefetchRingPubKeys(ctx, appClient, "pokt1unknown"))
+	require.ErrorIs(t, (&GatewayClient{}).PrefetchRingPubKeys(ctx, appClient, appAddress), sdkerrors.ErrNotConfigured)
+}
+
+func TestGatewayClient_RelayTTL(t *testing.T) {
+	app := apptypes.Application{Address: "app1"}
+	session := SessionInfo{Session: &sessiontypes.Session{
+		SessionId:   "session1",
+		Header:      &sessiontypes.SessionHeader{ApplicationAddress: "app1", ServiceId: "svc1"},
+		Application: &app,
+	}}
+	// The session has no endpoints, so every attempt fails.
+	gc := &GatewayClient{
+		RelayRetry: &retry.Config{MaxAttempts: 100, Policy: retry.Constant{Interval: 10 * time.Millisecond}},
+		RelayTTL:   35 * time.Millisecond,
+	}
+
+	// The retries stop once the TTL elapses, even if retries remain.
+	ctx := context.Background()
+	_, err := gc.relayWithRetries(ctx, newRelayTTL(ctx, gc.RelayTTL), gc.Logger.relayLogger(), nil, session, "svc1", nil)
+	require.ErrorIs(t, err, ErrRelayExpired)
+	var expiredErr *RelayExpiredError
+	require.ErrorAs(t, err, &expiredErr)
+	require.GreaterOrEqual(t, expiredErr.Attempts, 1)
+	require.Less(t, expiredErr.Attempts, 100)
+	require.Error(t, expiredErr.Err)
+
+	// A relay whose TTL elapsed before Relay was called is not sent.
+	ctx = ContextWithRelayStart(context.Background(), time.Now().Add(-time.Minute))
+	_, err = gc.relayWithRetries(ctx, newRelayTTL(ctx, gc.RelayTTL), gc.Logger.relayLogger(), nil, session, "svc1", nil)
+	require.ErrorAs(t, err, &expiredErr)
+	require.Zero(t, expiredErr.Attempts)
+	require.NoError(t, expiredErr.Err)
+
+	// Without a TTL, the relay fails with the error of its last attempt.
+	gc.RelayRetry = &retry.Config{MaxAttempts: 3}
+	gc.RelayTTL = 0
+	ctx = context.Background()
+	_, err = gc.relayWithRetries(ctx, newRelayTTL(ctx, gc.RelayTTL), gc.Logger.relayLogger(), nil, session, "svc1", nil)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrRelayExpired)
+}
+
+func TestGatewayClient_LoadShedder(t *testing.T) {
+	blockClient := &countingBlockQuerier{}
+	gc := &GatewayClient{
+		BlockClient:      blockClient,
+		SessionCache:     &SessionCache{},
+		Signer:           &Signer{},
+		PublicKeyFetcher: &countingPubKeyFetcher{},
+		SendRelay: func(context.Context, Endpoint, *servicetypes.RelayRequest) ([]byte, error) {
+			t.Fatal("the relay should not be sent")
+			return nil, nil
+		},
+		LoadShedder: NewLoadShedder(map[string]ServiceLoadLimits{"svc1": {MaxConcurrentRelays: 1}}),
+	}
+
+	// The relays of a service at its limit are rejected before reaching the pipeline.
+	release, err := gc.LoadShedder.Acquire(context.Background(), "svc1")
+	require.NoError(t, err)
+	defer release()
+
+	_, err = gc.Relay(context.Background(), "app1", "svc1", nil)
+	require.ErrorIs(t, err, ErrRelayLoadShed)
+	require.Zero(t, blockClient.calls.Load())
+	require.Equal(t, 1, gc.LoadShedder.InFlight("svc1"))
+}
+
+func TestGatewayClient_MethodPolicy(t *testing.T) {
+	blockClient := &countingBlockQuerier{}
+	gc := &GatewayClient{
+		BlockClient:      blockClient,
+		SessionCache:     &SessionCache{},
+		Signer:           &Signer{},
+		PublicKeyFetcher: &countingPubKeyFetcher{},
+		SendRelay: func(context.Context, Endpoint, *servicetypes.RelayRequest) ([]byte, error) {
+			t.Fatal("the relay should not be sent")
+			return nil, nil
+		},
+		MethodPolicy: &JSONRPCMethodPolicy{ServiceRules: map[string]MethodRules{
+			"svc1": {DeniedMethods: []string{"eth_sendRawTransaction"}},
+		}},
+	}
+
+	newRequest := func(body string) []byte {
+		httpRequest, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		require.NoError(t, err)
+		_, requestBz, err := types.SerializeHTTPRequest(httpRequest)
+		require.NoError(t, err)
+		return requestBz
+	}
+
+	// The relays calling a denied method are rejected before reaching the pipeline.
+	_, err := gc.Relay(context.Background(), "app1", "svc1", newRequest(`{"jsonrpc":"2.0","method":"eth_sendRawTransaction","id":"1"}`))
+	require.ErrorIs(t, err, ErrJSONRPCMethodNotAllowed)
+	require.Zero(t, blockClient.calls.Load())
+
+	// The other relays go on to the pipeline.
+	_, err = gc.Relay(context.Background(), "app1", "svc1", newRequest(`{"jsonrpc":"2.0","method":"eth_blockNumber","id":1}`))
+	require.NotErrorIs(t, err, ErrJSONRPCMethodNotAllowed)
+	require.Equal(t, int64(1), blockClient.calls.Load())
+}
+
+func TestNewGatewayClient(t *testing.T) {
+	blockClient := &countingBlockQuerier{}
+	logger := NewRelayLogger(slog.NewTextHandler(io.Discard, nil))
+	recorder := &fakeMetricsRecorder{}
+	gc := NewGatewayClient(
+		blockClient,
+		&SessionCache{},
+		&Signer{},
+		&countingPubKeyFetcher{},
+		WithGatewayLogger(logger),
+		WithGatewayMetrics(recorder),
+		WithGatewayHTTPClient(&http.Client{}, WithRelayTimeout(time.Second)),
+	)
+
+	require.Same(t, blockClient, gc.BlockClient)
+	require.Same(t, logger, gc.Logger)
+	require.Same(t, recorder, gc.Metrics)
+	require.NotNil(t, gc.SendRelay)
+
+	// Relays are sent using the default HTTP client without options.
+	require.NotNil(t, NewGatewayClient(blockClient, &SessionCache{}, &Signer{}, &countingPubKeyFetcher{}).SendRelay)
+}
+
+// countingBlockQuerier is a BlockQuerier counting its calls, and failing all of them.
+type countingBlockQuerier struct {
+	calls atomic.Int64
+}
+
+func (q *countingBlockQuerier) LatestBlockHeight(context.Context) (int64, error) {
+	q.calls.Add(1)
+	return 0, errors.New("no block height")
+}
+
+func TestGatewayClient_RelayFailureNotifications(t *testing.T) {
+	appKey := secp256k1.GenPrivKey()
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, appKey.PubKey())
+	require.NoError(t, err)
+
+	app := apptypes.Application{Address: appAddress}
+	session := SessionInfo{Session: &sessiontypes.Session{
+		SessionId:   "session1",
+		Header:      &sessiontypes.SessionHeader{ApplicationAddress: appAddress, ServiceId: "svc1", SessionId: "session1"},
+		Application: &app,
+		Suppliers: []*sharedtypes.Supplier{{
+			OperatorAddress: "pokt1supplier",
+			Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://supplier.example"}},
+			}},
+		}},
+	}}
+
+	var observed []error
+	bus := NewEventBus()
+	var published []Event
+	bus.Subscribe(func(event Event) { published = append(published, event) }, EventSupplierFailed)
+	gc := &GatewayClient{
+		EventBus:             bus,
+		PublicKeyFetcher:     &countingPubKeyFetcher{pubKeys: map[string]cryptotypes.PubKey{appAddress: appKey.PubKey()}},
+		RelayOutcomeObserver: relayOutcomeObserverFunc(func(_ Endpoint, _ time.Duration, err error) { observed = append(observed, err) }),
+	}
+	signer := &Signer{PrivateKeyHex: hex.EncodeToString(appKey.Bytes())}
+	errRefused := errors.New("connection refused")
+
+	tests := []struct {
+		desc           string
+		signer         *Signer
+		sendErr        error
+		cancel         bool
+		expectObserved bool
+	}{
+		{
+			desc:    "signing failure",
+			signer:  &Signer{PrivateKeyHex: "not hex"},
+			sendErr: errRefused,
+		},
+		{
+			desc:   "relay canceled by the caller",
+			signer: signer,
+			cancel: true,
+		},
+		{
+			desc:           "endpoint failure",
+			signer:         signer,
+			sendErr:        errRefused,
+			expectObserved: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			observed, published = nil, nil
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			gc.SendRelay = func(ctx context.Context, _ Endpoint, _ *servicetypes.RelayRequest) ([]byte, error) {
+				if test.cancel {
+					cancel()
+					return nil, ctx.Err()
+				}
+				return nil, test.sendErr
+			}
+
+			_, err := gc.relayAttempt(ctx, gc.Logger.relayLogger(), test.signer, session, "svc1", nil)
+			require.Error(t, err)
+			if test.expectObserved {
+				require.Equal(t, []error{err}, observed)
+				require.Equal(t, []Event{SupplierFailedEvent{ServiceId: "svc1", Supplier: "pokt1supplier", Err: err}}, published)
+			} else {
+				require.Empty(t, observed)
+				require.Empty(t, published)
+			}
+		})
+	}
+}
+
+func TestGatewayClient_SignerNotInRing(t *testing.T) {
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, secp256k1.GenPrivKey().PubKey())
+	require.NoError(t, err)
+	gatewayKey := secp256k1.GenPrivKey()
+	gatewayAddress, err := PubKeyToAddress(PoktAddressPrefix, gatewayKey.PubKey())
+	require.NoError(t, err)
+
+	recorder := &fakeMetricsRecorder{}
+	sessionCache := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: &heightSessionFetcher{numBlocksPerSession: 10}},
+		WithCacheConfig(cache.Config{TTL: time.Minute}),
+		WithSessionMetricsRecorder(recorder),
+	)
+	_, err = sessionCache.GetSession(context.Background(), appAddress, "svc1", 1)
+	require.NoError(t, err)
+
+	bus := NewEventBus()
+	var published []Event
+	bus.Subscribe(func(event Event) { published = append(published, event) }, EventDelegationChanged)
+	bus.Subscribe(sessionCache.HandleEvent, EventDelegationChanged)
+
+	// The application revoked its delegation to the gateway since its session was fetched.
+	app := apptypes.Application{Address: appAddress}
+	session := SessionInfo{Session: &sessiontypes.Session{
+		SessionId:   "session1",
+		Header:      &sessiontypes.SessionHeader{ApplicationAddress: appAddress, ServiceId: "svc1", SessionId: "session1"},
+		Application: &app,
+		Suppliers: []*sharedtypes.Supplier{{
+			OperatorAddress: "pokt1supplier",
+			Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://supplier.example"}},
+			}},
+		}},
+	}}
+	gc := &GatewayClient{
+		EventBus:         bus,
+		PublicKeyFetcher: &countingPubKeyFetcher{},
+		SendRelay: func(context.Context, Endpoint, *servicetypes.RelayRequest) ([]byte, error) {
+			return nil, errors.New("unexpected relay")
+		},
+	}
+	signer := &Signer{PrivateKeyHex: hex.EncodeToString(gatewayKey.Bytes())}
+
+	_, err = gc.relayAttempt(context.Background(), gc.Logger.relayLogger(), signer, session, "svc1", nil)
+	require.ErrorIs(t, err, ErrSignerNotInRing)
+	require.Equal(t, []Event{DelegationChangedEvent{AppAddress: appAddress, GatewayAddress: gatewayAddress}}, published)
+
+	// The application's session is fetched again, with its current delegations.
+	_, err = sessionCache.GetSession(context.Background(), appAddress, "svc1", 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, recorder.sessionRefreshes)
+
+	// The gateway address of a signer backed by a RingSigner is its RingSigner's.
+	published = nil
+	ringSigner, err := NewHexKeyRingSigner(hex.EncodeToString(gatewayKey.Bytes()))
+	require.NoError(t, err)
+	_, err = gc.relayAttempt(context.Background(), gc.Logger.relayLogger(), &Signer{RingSigner: ringSigner}, session, "svc1", nil)
+	require.ErrorIs(t, err, ErrSignerNotInRing)
+	require.Equal(t, []Event{DelegationChangedEvent{AppAddress: appAddress, GatewayAddress: gatewayAddress}}, published)
+}
+
+func TestGatewayClient_ResponseValidator(t *testing.T) {
+	appKey, supplierKey := secp256k1.GenPrivKey(), secp256k1.GenPrivKey()
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, appKey.PubKey())
+	require.NoError(t, err)
+
+	app := apptypes.Application{Address: appAddress}
+	sessionHeader := &sessiontypes.SessionHeader{
+		ApplicationAddress:      appAddress,
+		ServiceId:               "svc1",
+		SessionId:               "session1",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   4,
+	}
+	session := SessionInfo{Session: &sessiontypes.Session{
+		SessionId:   "session1",
+		Header:      sessionHeader,
+		Application: &app,
+		Suppliers: []*sharedtypes.Supplier{{
+			OperatorAddress: "pokt1supplier",
+			Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://supplier.example"}},
+			}},
+		}},
+	}}
+
+	// newRelayResponse returns a serialized RelayResponse with the given
+	// session header, signed by the given key.
+	newRelayResponse := func(header *sessiontypes.SessionHeader, signingKey *secp256k1.PrivKey) []byte {
+		_, payload, err := types.SerializeHTTPResponse(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("ok")),
+		})
+		require.NoError(t, err)
+		relayResponse := &servicetypes.RelayResponse{
+			Meta:    servicetypes.RelayResponseMetadata{SessionHeader: header},
+			Payload: payload,
+		}
+		signableBz, err := relayResponse.GetSignableBytesHash()
+		require.NoError(t, err)
+		relayResponse.Meta.SupplierOperatorSignature, err = signingKey.Sign(signableBz[:])
+		require.NoError(t, err)
+		relayResponseBz, err := relayResponse.Marshal()
+		require.NoError(t, err)
+		return relayResponseBz
+	}
+
+	publicKeyFetcher := &countingPubKeyFetcher{pubKeys: map[string]cryptotypes.PubKey{
+		appAddress:      appKey.PubKey(),
+		"pokt1supplier": supplierKey.PubKey(),
+	}}
+	signer := &Signer{PrivateKeyHex: hex.EncodeToString(appKey.Bytes())}
+
+	tests := []struct {
+		desc            string
+		validator       *RelayResponseValidator
+		relayResponseBz []byte
+		expectedErr     error
+		expectDegraded  bool
+	}{
+		{
+			desc:            "response failing basic validation",
+			relayResponseBz: newRelayResponse(&sessiontypes.SessionHeader{ServiceId: "svc1"}, supplierKey),
+			expectedErr:     sdkerrors.ErrRelayResponseBasicValidation,
+		},
+		{
+			desc:            "response failing basic validation passed through degraded",
+			validator:       &RelayResponseValidator{PublicKeyFetcher: publicKeyFetcher, ValidateBasicPolicy: ValidateBasicPassDegraded},
+			relayResponseBz: newRelayResponse(&sessiontypes.SessionHeader{ServiceId: "svc1"}, supplierKey),
+			expectDegraded:  true,
+		},
+		{
+			desc:            "forged response",
+			relayResponseBz: newRelayResponse(sessionHeader, secp256k1.GenPrivKey()),
+			expectedErr:     sdkerrors.ErrInvalidSupplierSignature,
+		},
+		{
+			desc:            "forged response from trusted suppliers",
+			validator:       &RelayResponseValidator{TrustSuppliers: true},
+			relayResponseBz: newRelayResponse(sessionHeader, secp256k1.GenPrivKey()),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			gc := NewGatewayClient(nil, nil, signer, publicKeyFetcher, WithGatewayResponseValidator(test.validator))
+			gc.SendRelay = func(context.Context, Endpoint, *servicetypes.RelayRequest) ([]byte, error) {
+				return test.relayResponseBz, nil
+			}
+
+			poktHTTPResponse, err := gc.relayAttempt(context.Background(), gc.Logger.relayLogger(), signer, session, "svc1", nil)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, []byte("ok"), poktHTTPResponse.BodyBz)
+			header := http.Header{}
+			poktHTTPResponse.CopyToHTTPHeader(header)
+			if test.expectDegraded {
+				require.Equal(t, degradedResponseWarning, header.Get("Warning"))
+			} else {
+				require.Empty(t, header.Get("Warning"))
+			}
+		})
+	}
+}
+
+// relayOutcomeObserverFunc is a RelayOutcomeObserver calling the function itself.
+type relayOutcomeObserverFunc func(endpoint Endpoint, latency time.Duration, err error)
+
+func (f relayOutcomeObserverFunc) ObserveRelayOutcome(endpoint Endpoint, latency time.Duration, err error) {
+	f(endpoint, latency, err)
+}
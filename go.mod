@@ -3,14 +3,24 @@ module github.com/pokt-network/shannon-sdk
 go 1.23.0
 
 require (
+	cosmossdk.io/math v1.3.0
 	github.com/cometbft/cometbft v0.38.10
 	github.com/cosmos/cosmos-sdk v0.50.9
+	github.com/cosmos/go-bip39 v1.0.0
 	github.com/cosmos/gogoproto v1.5.0
+	github.com/gorilla/websocket v1.5.1
 	github.com/pokt-network/poktroll v0.0.8-0.20240911114212-ecf74ced63cc
 	github.com/pokt-network/ring-go v0.1.0
+	github.com/pokt-network/shannon-sdk/cache v0.0.0-00010101000000-000000000000
+	github.com/pokt-network/shannon-sdk/config v0.0.0-00010101000000-000000000000
+	github.com/pokt-network/shannon-sdk/retry v0.0.0-00010101000000-000000000000
+	github.com/pokt-network/shannon-sdk/sdkerrors v0.0.0-00010101000000-000000000000
+	github.com/pokt-network/shannon-sdk/types v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.19.0
 	github.com/stretchr/testify v1.9.0
 	google.golang.org/grpc v1.65.0
 	google.golang.org/protobuf v1.34.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -24,7 +34,6 @@ require (
 	cosmossdk.io/depinject v1.0.0 // indirect
 	cosmossdk.io/errors v1.0.1 // indirect
 	cosmossdk.io/log v1.3.1 // indirect
-	cosmossdk.io/math v1.3.0 // indirect
 	cosmossdk.io/store v1.1.0 // indirect
 	cosmossdk.io/x/circuit v0.1.0 // indirect
 	cosmossdk.io/x/evidence v0.1.0 // indirect
@@ -58,7 +67,6 @@ require (
 	github.com/cosmos/btcutil v1.0.5 // indirect
 	github.com/cosmos/cosmos-db v1.0.2 // indirect
 	github.com/cosmos/cosmos-proto v1.0.0-beta.5 // indirect
-	github.com/cosmos/go-bip39 v1.0.0 // indirect
 	github.com/cosmos/gogogateway v1.2.0 // indirect
 	github.com/cosmos/iavl v1.1.2 // indirect
 	github.com/cosmos/ibc-go/modules/capability v1.0.0 // indirect
@@ -101,7 +109,6 @@ require (
 	github.com/googleapis/gax-go/v2 v2.12.2 // indirect
 	github.com/gorilla/handlers v1.5.2 // indirect
 	github.com/gorilla/mux v1.8.1 // indirect
-	github.com/gorilla/websocket v1.5.1 // indirect
 	github.com/grpc-ecosystem/go-grpc-middleware v1.4.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway v1.16.0 // indirect
 	github.com/gsterjov/go-libsecret v0.0.0-20161001094733-a6f4afe4910c // indirect
@@ -145,7 +152,6 @@ require (
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	github.com/pokt-network/smt v0.13.0 // indirect
-	github.com/prometheus/client_golang v1.19.0 // indirect
 	github.com/prometheus/client_model v0.6.1 // indirect
 	github.com/prometheus/common v0.52.2 // indirect
 	github.com/prometheus/procfs v0.13.0 // indirect
@@ -192,9 +198,18 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240709173604-40e1e62336c5 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gotest.tools/v3 v3.5.1 // indirect
 	nhooyr.io/websocket v1.8.7 // indirect
 	pgregory.net/rapid v1.1.0 // indirect
 	sigs.k8s.io/yaml v1.4.0 // indirect
 )
+
+// The light packages are separate modules, so their importers do not depend on
+// cosmos-sdk and poktroll. They are developed along with the root module.
+replace (
+	github.com/pokt-network/shannon-sdk/cache => ./cache
+	github.com/pokt-network/shannon-sdk/config => ./config
+	github.com/pokt-network/shannon-sdk/retry => ./retry
+	github.com/pokt-network/shannon-sdk/sdkerrors => ./sdkerrors
+	github.com/pokt-network/shannon-sdk/types => ./types
+)
@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cosmos/gogoproto/grpc"
+	grpcoptions "google.golang.org/grpc"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+)
+
+// GRPCConnOption is a functional option used to configure the gRPC connection
+// to a full node built by NewGRPCConn.
+type GRPCConnOption func(*grpcConnConfig)
+
+// grpcConnConfig holds the settings applied by GRPCConnOptions.
+type grpcConnConfig struct {
+	logger      *slog.Logger
+	metrics     MetricsRecorder
+	retryConfig *retry.Config
+	backoff     *NodeBackoff
+}
+
+// WithGRPCLogger sets the logger logging the failed unary RPCs at warn.
+func WithGRPCLogger(logger *slog.Logger) GRPCConnOption {
+	return func(c *grpcConnConfig) {
+		c.logger = logger
+	}
+}
+
+// WithGRPCMetrics sets the MetricsRecorder recording the durations and errors
+// of the queries. See NewMetricsGRPCConn.
+func WithGRPCMetrics(recorder MetricsRecorder) GRPCConnOption {
+	return func(c *grpcConnConfig) {
+		c.metrics = recorder
+	}
+}
+
+// WithGRPCRetry sets how the failed unary RPCs are retried. See NewRetryGRPCConn.
+func WithGRPCRetry(retryConfig retry.Config) GRPCConnOption {
+	return func(c *grpcConnConfig) {
+		c.retryConfig = &retryConfig
+	}
+}
+
+// WithGRPCNodeBackoff sets the NodeBackoff applied to the requests, which may
+// be shared with the other fetchers of the full node. See NewNodeBackoffGRPCConn.
+func WithGRPCNodeBackoff(backoff *NodeBackoff) GRPCConnOption {
+	return func(c *grpcConnConfig) {
+		c.backoff = backoff
+	}
+}
+
+// NewGRPCConn returns a gRPC connection which sends the requests through the
+// given connection, configured using the given options.
+//
+// The options are applied in the following order: each retry attempt waits for
+// the NodeBackoff, then is recorded by the MetricsRecorder, so the metrics
+// report each query sent to the full node, and is logged if it fails.
+//
+// The returned connection can be used anywhere a gRPC connection is expected,
+// e.g. by NewPoktNodeSessionFetcher or NewPoktNodeAccountFetcher.
+func NewGRPCConn(conn grpc.ClientConn, opts ...GRPCConnOption) grpc.ClientConn {
+	config := grpcConnConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.logger != nil {
+		conn = &loggingGRPCConn{conn: conn, logger: config.logger}
+	}
+	if config.metrics != nil {
+		conn = NewMetricsGRPCConn(conn, config.metrics)
+	}
+	if config.backoff != nil {
+		conn = NewNodeBackoffGRPCConn(conn, config.backoff)
+	}
+	if config.retryConfig != nil {
+		conn = NewRetryGRPCConn(conn, *config.retryConfig)
+	}
+
+	return conn
+}
+
+// loggingGRPCConn is a gRPC connection logging its failed unary RPCs.
+type loggingGRPCConn struct {
+	conn   grpc.ClientConn
+	logger *slog.Logger
+}
+
+// Invoke performs a unary RPC, and logs its error, if any.
+func (c *loggingGRPCConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpcoptions.CallOption,
+) error {
+	err := c.conn.Invoke(ctx, method, args, reply, opts...)
+	if err != nil {
+		c.logger.WarnContext(ctx, "full node query failed", "method", method, "error", err)
+	}
+	return err
+}
+
+// NewStream begins a streaming RPC, and logs the error of its creation, if any.
+func (c *loggingGRPCConn) NewStream(
+	ctx context.Context,
+	desc *grpcoptions.StreamDesc,
+	method string,
+	opts ...grpcoptions.CallOption,
+) (grpcoptions.ClientStream, error) {
+	stream, err := c.conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		c.logger.WarnContext(ctx, "full node stream failed", "method", method, "error", err)
+	}
+	return stream, err
+}
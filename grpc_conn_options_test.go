@@ -0,0 +1,35 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+)
+
+func TestNewGRPCConn(t *testing.T) {
+	var logs bytes.Buffer
+	recorder := &fakeMetricsRecorder{}
+	conn := &fakeClientConn{err: status.Error(codes.Unavailable, "unavailable")}
+	grpcConn := NewGRPCConn(
+		conn,
+		WithGRPCLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithGRPCMetrics(recorder),
+		WithGRPCRetry(retry.Config{Policy: retry.Constant{}, MaxAttempts: 2}),
+	)
+
+	// Each retry attempt is recorded and logged.
+	require.Equal(t, codes.Unavailable, status.Code(grpcConn.Invoke(context.Background(), "method", nil, nil)))
+	require.Equal(t, 2, conn.calls)
+	require.Equal(t, 2, recorder.grpcQueries["method"])
+	require.Equal(t, 2, bytes.Count(logs.Bytes(), []byte("full node query failed")))
+
+	// The connection is returned as-is without options.
+	require.Same(t, conn, NewGRPCConn(conn))
+}
@@ -0,0 +1,127 @@
+package sdk
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// GRPCConnStateSource is a gRPC connection whose connectivity state can be
+// monitored. It is implemented by *grpc.ClientConn.
+type GRPCConnStateSource interface {
+	Target() string
+	GetState() connectivity.State
+	WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool
+}
+
+// GRPCConnHealth is the health of a monitored gRPC connection, as reported by
+// GRPCConnMonitor.HealthReport.
+type GRPCConnHealth struct {
+	Target string
+	// State is the current connectivity state of the connection.
+	State connectivity.State
+	// Since is the time at which the connection entered its current state.
+	Since time.Time
+	// Transitions is the number of state changes observed since the connection
+	// started being monitored.
+	Transitions int
+	// SDK is the build of the SDK which monitored the connection.
+	SDK VersionInfo
+}
+
+// Healthy returns true if the connection is ready to serve requests.
+// Idle and connecting connections are considered healthy, as they become ready
+// on the next request unless the full node is unreachable.
+func (h GRPCConnHealth) Healthy() bool {
+	return h.State != connectivity.TransientFailure && h.State != connectivity.Shutdown
+}
+
+// GRPCConnMonitor monitors the connectivity state of gRPC connections to full
+// nodes, so operators can tell full node outages apart from application-level
+// failures, e.g. NotFound errors.
+//
+// Every state change is published on the EventBus as a GRPCConnStateChangedEvent,
+// e.g. to export metrics. A HealthChangedEvent is also published when a connection
+// becomes ready, or fails.
+// It is safe for concurrent use.
+type GRPCConnMonitor struct {
+	eventBus *EventBus
+
+	mu    sync.Mutex
+	conns map[string]*GRPCConnHealth
+}
+
+// NewGRPCConnMonitor returns a GRPCConnMonitor publishing the state changes on
+// the given EventBus, which may be nil.
+func NewGRPCConnMonitor(eventBus *EventBus) *GRPCConnMonitor {
+	return &GRPCConnMonitor{
+		eventBus: eventBus,
+		conns:    make(map[string]*GRPCConnHealth),
+	}
+}
+
+// Watch starts monitoring the given connection, in a new goroutine, until the
+// given context is done or the connection is shut down.
+func (m *GRPCConnMonitor) Watch(ctx context.Context, conn GRPCConnStateSource) {
+	target := conn.Target()
+	state := conn.GetState()
+
+	m.mu.Lock()
+	m.conns[target] = &GRPCConnHealth{Target: target, State: state, Since: time.Now()}
+	m.mu.Unlock()
+
+	go func() {
+		for state != connectivity.Shutdown && conn.WaitForStateChange(ctx, state) {
+			newState := conn.GetState()
+			m.onStateChange(target, state, newState)
+			state = newState
+		}
+	}()
+}
+
+// HealthReport returns the health of all the monitored connections, in lexical
+// order of their targets, along with the build of the SDK.
+func (m *GRPCConnMonitor) HealthReport() []GRPCConnHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report := make([]GRPCConnHealth, 0, len(m.conns))
+	sdkVersion := Version()
+	for _, health := range m.conns {
+		connHealth := *health
+		connHealth.SDK = sdkVersion
+		report = append(report, connHealth)
+	}
+	slices.SortFunc(report, func(a, b GRPCConnHealth) int {
+		return strings.Compare(a.Target, b.Target)
+	})
+	return report
+}
+
+// onStateChange records the given state change of a connection, and publishes
+// the corresponding events.
+func (m *GRPCConnMonitor) onStateChange(target string, previousState, state connectivity.State) {
+	m.mu.Lock()
+	health := m.conns[target]
+	health.State = state
+	health.Since = time.Now()
+	health.Transitions++
+	m.mu.Unlock()
+
+	m.eventBus.Publish(GRPCConnStateChangedEvent{
+		Target:        target,
+		PreviousState: previousState,
+		State:         state,
+	})
+
+	switch state {
+	case connectivity.Ready:
+		m.eventBus.Publish(HealthChangedEvent{Component: target, Healthy: true})
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		m.eventBus.Publish(HealthChangedEvent{Component: target, Healthy: false})
+	}
+}
@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestGRPCConnMonitor(t *testing.T) {
+	bus := NewEventBus()
+	var (
+		mu     sync.Mutex
+		events []Event
+	)
+	bus.Subscribe(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+
+	conn := &fakeStateConn{state: connectivity.Idle, changes: make(chan connectivity.State)}
+	monitor := NewGRPCConnMonitor(bus)
+	monitor.Watch(context.Background(), conn)
+
+	report := monitor.HealthReport()
+	require.Len(t, report, 1)
+	require.Equal(t, "node.example:9090", report[0].Target)
+	require.Equal(t, connectivity.Idle, report[0].State)
+	require.True(t, report[0].Healthy())
+	require.Equal(t, Version(), report[0].SDK)
+
+	conn.changes <- connectivity.Ready
+	conn.changes <- connectivity.TransientFailure
+	conn.changes <- connectivity.Shutdown
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 6
+	}, time.Second, time.Millisecond)
+
+	report = monitor.HealthReport()
+	require.Equal(t, connectivity.Shutdown, report[0].State)
+	require.Equal(t, 3, report[0].Transitions)
+	require.False(t, report[0].Healthy())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []Event{
+		GRPCConnStateChangedEvent{Target: "node.example:9090", PreviousState: connectivity.Idle, State: connectivity.Ready},
+		HealthChangedEvent{Component: "node.example:9090", Healthy: true},
+		GRPCConnStateChangedEvent{Target: "node.example:9090", PreviousState: connectivity.Ready, State: connectivity.TransientFailure},
+		HealthChangedEvent{Component: "node.example:9090", Healthy: false},
+		GRPCConnStateChangedEvent{Target: "node.example:9090", PreviousState: connectivity.TransientFailure, State: connectivity.Shutdown},
+		HealthChangedEvent{Component: "node.example:9090", Healthy: false},
+	}, events)
+}
+
+// fakeStateConn is a GRPCConnStateSource whose state changes are sent on a channel.
+type fakeStateConn struct {
+	mu      sync.Mutex
+	state   connectivity.State
+	changes chan connectivity.State
+}
+
+func (c *fakeStateConn) Target() string { return "node.example:9090" }
+
+func (c *fakeStateConn) GetState() connectivity.State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+func (c *fakeStateConn) WaitForStateChange(ctx context.Context, _ connectivity.State) bool {
+	select {
+	case state := <-c.changes:
+		c.mu.Lock()
+		c.state = state
+		c.mu.Unlock()
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/cosmos/gogoproto/grpc"
+	grpcoptions "google.golang.org/grpc"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+)
+
+// NewRetryGRPCConn returns a gRPC connection which sends the requests through
+// the given connection, retrying the failed unary RPCs using the given config.
+// Only the transient gRPC errors are retried, as reported by
+// retry.IsRetryableGRPCError, if the config does not set ShouldRetry.
+//
+// The returned connection can be used anywhere a gRPC connection is expected,
+// e.g. by the query clients of NewPoktNodeSessionFetcher or NewPoktNodeAccountFetcher,
+// so all the queries to the full node use the same retry policy as the BlockClient.
+func NewRetryGRPCConn(conn grpc.ClientConn, config retry.Config) grpc.ClientConn {
+	if config.ShouldRetry == nil {
+		config.ShouldRetry = retry.IsRetryableGRPCError
+	}
+	return &retryGRPCConn{conn: conn, config: config}
+}
+
+// retryGRPCConn is a gRPC connection retrying the failed unary RPCs.
+type retryGRPCConn struct {
+	conn   grpc.ClientConn
+	config retry.Config
+}
+
+// Invoke performs a unary RPC, retrying it according to the retry config.
+func (c *retryGRPCConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpcoptions.CallOption,
+) error {
+	return retry.Do(ctx, c.config, func(ctx context.Context) error {
+		return c.conn.Invoke(ctx, method, args, reply, opts...)
+	})
+}
+
+// NewStream begins a streaming RPC, without retrying it: the messages already
+// exchanged over a stream cannot be replayed.
+func (c *retryGRPCConn) NewStream(
+	ctx context.Context,
+	desc *grpcoptions.StreamDesc,
+	method string,
+	opts ...grpcoptions.CallOption,
+) (grpcoptions.ClientStream, error) {
+	return c.conn.NewStream(ctx, desc, method, opts...)
+}
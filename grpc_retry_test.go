@@ -0,0 +1,30 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+)
+
+func TestRetryGRPCConn(t *testing.T) {
+	conn := &fakeClientConn{err: status.Error(codes.Unavailable, "unavailable")}
+	retryConn := NewRetryGRPCConn(conn, retry.Config{Policy: retry.Constant{}, MaxAttempts: 3})
+
+	ctx := context.Background()
+	require.Equal(t, codes.Unavailable, status.Code(retryConn.Invoke(ctx, "method", nil, nil)))
+	require.Equal(t, 3, conn.calls)
+
+	// The non transient errors are not retried.
+	conn.calls, conn.err = 0, status.Error(codes.NotFound, "not found")
+	require.Equal(t, codes.NotFound, status.Code(retryConn.Invoke(ctx, "method", nil, nil)))
+	require.Equal(t, 1, conn.calls)
+
+	conn.calls, conn.err = 0, nil
+	require.NoError(t, retryConn.Invoke(ctx, "method", nil, nil))
+	require.Equal(t, 1, conn.calls)
+}
@@ -0,0 +1,145 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	"github.com/cosmos/gogoproto/grpc"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// GRPCHeightFetcher fetches the latest block height known to the POKT full node
+// serving the gRPC queries, e.g. the session queries.
+type GRPCHeightFetcher interface {
+	LatestBlockHeight(ctx context.Context) (int64, error)
+}
+
+// NewGRPCHeightFetcher returns the default implementation of the GRPCHeightFetcher
+// interface, using the cometbft service of the full node at the other end of
+// the given gRPC connection.
+func NewGRPCHeightFetcher(grpcConn grpc.ClientConn) GRPCHeightFetcher {
+	return &cmtServiceHeightFetcher{client: cmtservice.NewServiceClient(grpcConn)}
+}
+
+// cmtServiceHeightFetcher is a GRPCHeightFetcher using the cometbft gRPC service.
+type cmtServiceHeightFetcher struct {
+	client cmtservice.ServiceClient
+}
+
+// LatestBlockHeight returns the height of the latest block of the full node.
+func (f *cmtServiceHeightFetcher) LatestBlockHeight(ctx context.Context) (int64, error) {
+	res, err := f.client.GetLatestBlock(ctx, &cmtservice.GetLatestBlockRequest{})
+	if err != nil {
+		return 0, err
+	}
+
+	if res.SdkBlock != nil {
+		return res.SdkBlock.Header.Height, nil
+	}
+	if res.Block != nil {
+		return res.Block.Header.Height, nil
+	}
+
+	return 0, errors.New("LatestBlockHeight: full node returned no block")
+}
+
+// HeightCheck is the result of comparing the latest block heights reported by
+// the RPC and gRPC full node connections.
+type HeightCheck struct {
+	RPCHeight  int64
+	GRPCHeight int64
+	// Divergence is the absolute difference between the two heights.
+	Divergence int64
+	// Sustained is true if the heights diverged by more than the allowed number
+	// of blocks for at least the configured number of consecutive checks.
+	Sustained bool
+}
+
+// HeightConsistencyChecker compares the latest block heights reported by the
+// RPC connection of a BlockClient and by a gRPC connection.
+//
+// When the two connections point to different full nodes, their heights can
+// diverge, e.g. if one of the nodes lags behind, which breaks the session logic
+// relying on the height from one connection to query the other one.
+type HeightConsistencyChecker struct {
+	BlockClient       BlockQuerier
+	GRPCHeightFetcher GRPCHeightFetcher
+
+	// MaxDivergence is the number of blocks by which the heights may diverge
+	// without being reported.
+	MaxDivergence int64
+	// SustainedChecks is the number of consecutive divergent checks after which
+	// the divergence is considered sustained. It defaults to 1.
+	SustainedChecks int
+	// OnSustainedDivergence, if set, is called every time a check finds a
+	// sustained divergence, e.g. to log a warning.
+	OnSustainedDivergence func(HeightCheck)
+	// PreferGRPCHeight makes LatestBlockHeight return the gRPC node's height,
+	// which is the one serving session queries.
+	PreferGRPCHeight bool
+
+	mu              sync.Mutex
+	divergentChecks int
+}
+
+// Check fetches the latest block height from both connections and compares them.
+func (c *HeightConsistencyChecker) Check(ctx context.Context) (HeightCheck, error) {
+	if c.BlockClient == nil || c.GRPCHeightFetcher == nil {
+		return HeightCheck{}, errors.New("Check: BlockClient and GRPCHeightFetcher must be set")
+	}
+
+	rpcHeight, err := c.BlockClient.LatestBlockHeight(ctx)
+	if err != nil {
+		return HeightCheck{}, fmt.Errorf("Check: error getting the RPC node height: %w", err)
+	}
+
+	grpcHeight, err := c.GRPCHeightFetcher.LatestBlockHeight(ctx)
+	if err != nil {
+		return HeightCheck{}, fmt.Errorf("Check: error getting the gRPC node height: %w", err)
+	}
+
+	check := HeightCheck{
+		RPCHeight:  rpcHeight,
+		GRPCHeight: grpcHeight,
+		Divergence: rpcHeight - grpcHeight,
+	}
+	if check.Divergence < 0 {
+		check.Divergence = -check.Divergence
+	}
+
+	sustainedChecks := max(c.SustainedChecks, 1)
+
+	c.mu.Lock()
+	if check.Divergence > c.MaxDivergence {
+		c.divergentChecks++
+	} else {
+		c.divergentChecks = 0
+	}
+	check.Sustained = c.divergentChecks >= sustainedChecks
+	c.mu.Unlock()
+
+	if check.Sustained && c.OnSustainedDivergence != nil {
+		c.OnSustainedDivergence(check)
+	}
+
+	return check, nil
+}
+
+// LatestBlockHeight returns the latest block height to use for session
+// decisions: the gRPC node's height if PreferGRPCHeight is set, or the
+// BlockClient's height otherwise.
+func (c *HeightConsistencyChecker) LatestBlockHeight(ctx context.Context) (int64, error) {
+	if c.PreferGRPCHeight && c.GRPCHeightFetcher != nil {
+		return c.GRPCHeightFetcher.LatestBlockHeight(ctx)
+	}
+
+	if c.BlockClient == nil {
+		return 0, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "LatestBlockHeight: BlockClient not set")
+	}
+
+	return c.BlockClient.LatestBlockHeight(ctx)
+}
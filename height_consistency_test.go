@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeightConsistencyChecker(t *testing.T) {
+	statusFetcher := &fakeStatusFetcher{height: 100}
+	grpcFetcher := &fakeGRPCHeightFetcher{height: 100}
+
+	var reported []HeightCheck
+	checker := &HeightConsistencyChecker{
+		BlockClient:       &BlockClient{PoktNodeStatusFetcher: statusFetcher},
+		GRPCHeightFetcher: grpcFetcher,
+		MaxDivergence:     2,
+		SustainedChecks:   2,
+		OnSustainedDivergence: func(check HeightCheck) {
+			reported = append(reported, check)
+		},
+	}
+
+	ctx := context.Background()
+	check, err := checker.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), check.Divergence)
+	require.False(t, check.Sustained)
+
+	// A single divergent check is not sustained.
+	grpcFetcher.height = 95
+	check, err = checker.Check(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), check.Divergence)
+	require.False(t, check.Sustained)
+	require.Empty(t, reported)
+
+	check, err = checker.Check(ctx)
+	require.NoError(t, err)
+	require.True(t, check.Sustained)
+	require.Len(t, reported, 1)
+
+	// The divergence count is reset once the heights converge.
+	grpcFetcher.height = 99
+	check, err = checker.Check(ctx)
+	require.NoError(t, err)
+	require.False(t, check.Sustained)
+
+	height, err := checker.LatestBlockHeight(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), height)
+
+	checker.PreferGRPCHeight = true
+	height, err = checker.LatestBlockHeight(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(99), height)
+}
+
+// fakeGRPCHeightFetcher is a GRPCHeightFetcher returning a fixed height.
+type fakeGRPCHeightFetcher struct {
+	height int64
+}
+
+func (f *fakeGRPCHeightFetcher) LatestBlockHeight(context.Context) (int64, error) {
+	return f.height, nil
+}
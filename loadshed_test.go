@@ -0,0 +1,51 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadShedder_Acquire(t *testing.T) {
+	ls := NewLoadShedder(map[string]ServiceLoadLimits{
+		"immediate": {MaxConcurrentRelays: 1},
+		"queued":    {MaxConcurrentRelays: 1, MaxQueueTime: 50 * time.Millisecond},
+	})
+	ctx := context.Background()
+
+	// Relays are rejected immediately once the limit is reached.
+	release, err := ls.Acquire(ctx, "immediate")
+	require.NoError(t, err)
+	require.Equal(t, 1, ls.InFlight("immediate"))
+
+	_, err = ls.Acquire(ctx, "immediate")
+	require.ErrorIs(t, err, ErrRelayLoadShed)
+
+	release()
+	release, err = ls.Acquire(ctx, "immediate")
+	require.NoError(t, err)
+	release()
+
+	// Queued relays acquire a slot if released within the queue time.
+	releaseFirst, err := ls.Acquire(ctx, "queued")
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		releaseFirst()
+	}()
+	release, err = ls.Acquire(ctx, "queued")
+	require.NoError(t, err)
+
+	// Queued relays are rejected once the queue time is exceeded.
+	_, err = ls.Acquire(ctx, "queued")
+	require.ErrorIs(t, err, ErrRelayLoadShed)
+	release()
+
+	// Services without limits are never shed.
+	for i := 0; i < 10; i++ {
+		_, err = ls.Acquire(ctx, "unlimited")
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,34 @@
+package localnet
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBundledComposeFile(t *testing.T) {
+	var compose struct {
+		Services map[string]struct {
+			Image   string   `yaml:"image"`
+			Command []string `yaml:"command"`
+			Ports   []string `yaml:"ports"`
+		} `yaml:"services"`
+	}
+	require.NoError(t, yaml.Unmarshal(bundledComposeFile, &compose))
+
+	// The full node is exposed at the default addresses.
+	rpcURL, err := url.Parse(DefaultRPCURL)
+	require.NoError(t, err)
+	fullNode, ok := compose.Services["poktrolld"]
+	require.True(t, ok)
+	require.NotEmpty(t, fullNode.Image)
+	require.NotEmpty(t, fullNode.Command)
+	require.ElementsMatch(t, []string{rpcURL.Port() + ":26657", "9090:9090"}, fullNode.Ports)
+	require.Equal(t, "localhost:9090", DefaultGRPCAddr)
+
+	// It is only launched for the configs using the default addresses.
+	require.True(t, usesDefaultAddresses(Config{RPCURL: DefaultRPCURL, GRPCAddr: DefaultGRPCAddr}))
+	require.False(t, usesDefaultAddresses(Config{RPCURL: DefaultRPCURL, GRPCAddr: "localhost:19090"}))
+}
@@ -0,0 +1,302 @@
+// Package localnet provides a POKT full node to the integration tests of SDK
+// consumers, standardizing the setup every downstream project otherwise scripts
+// by hand.
+//
+// The full node is either an existing LocalNet, whose addresses can be overridden
+// through environment variables, or a full node launched from a docker compose
+// file, which is torn down once the test completes. If no compose file is
+// configured and no LocalNet is running, a single-validator LocalNet is launched
+// from the compose file bundled with the package. In all cases, the returned
+// FullNode is only handed over once the full node is ready to serve queries.
+package localnet
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	grpcoptions "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// DefaultRPCURL is the RPC URL of the full node of a LocalNet.
+	DefaultRPCURL = "http://localhost:26657"
+	// DefaultGRPCAddr is the gRPC address of the full node of a LocalNet.
+	DefaultGRPCAddr = "localhost:9090"
+
+	// EnvRPCURL is the environment variable overriding the default RPC URL.
+	EnvRPCURL = "POKT_LOCALNET_RPC_URL"
+	// EnvGRPCAddr is the environment variable overriding the default gRPC address.
+	EnvGRPCAddr = "POKT_LOCALNET_GRPC_ADDR"
+	// EnvComposeFile is the environment variable setting the default docker
+	// compose file used to launch the full node.
+	EnvComposeFile = "POKT_LOCALNET_COMPOSE_FILE"
+
+	// defaultReadyTimeout is the maximum duration to wait for the full node to be
+	// ready if no timeout is specified.
+	defaultReadyTimeout = 2 * time.Minute
+	// readyPollInterval is the interval at which the readiness of the full node is checked.
+	readyPollInterval = 500 * time.Millisecond
+
+	// bundledProjectName is the docker compose project name of the LocalNet
+	// launched from the bundled compose file.
+	bundledProjectName = "shannon-sdk-localnet"
+)
+
+// bundledComposeFile is the docker compose file launching a single-validator
+// LocalNet, exposing its full node at DefaultRPCURL and DefaultGRPCAddr.
+//
+//go:embed docker-compose.yaml
+var bundledComposeFile []byte
+
+// Config specifies the full node to provide.
+// Its zero value attaches to the LocalNet at the addresses set by the
+// environment variables, or at the default addresses.
+type Config struct {
+	// RPCURL is the RPC URL of the full node.
+	RPCURL string
+	// GRPCAddr is the address of the gRPC server of the full node.
+	GRPCAddr string
+	// ComposeFile, if set, is the docker compose file launching the full node,
+	// which must expose it at RPCURL and GRPCAddr.
+	// Otherwise, the LocalNet running at RPCURL and GRPCAddr is used, or, if
+	// none is running at the default addresses, one is launched from the
+	// compose file bundled with the package.
+	ComposeFile string
+	// ReadyTimeout is the maximum duration to wait for the full node to be
+	// ready. It defaults to 2 minutes.
+	ReadyTimeout time.Duration
+}
+
+// FullNode is a POKT full node ready to serve queries.
+type FullNode struct {
+	// RPCURL is the RPC URL of the full node, e.g. to build a BlockClient using
+	// sdk.NewBlockClient.
+	RPCURL string
+	// GRPCAddr is the address of the gRPC server of the full node.
+	GRPCAddr string
+	// LatestBlockHeight is the latest block height of the full node when it
+	// became ready.
+	LatestBlockHeight int64
+}
+
+// DialGRPC returns a plaintext gRPC connection to the full node, e.g. to build
+// the SDK's query clients.
+func (n FullNode) DialGRPC() (*grpcoptions.ClientConn, error) {
+	return grpcoptions.NewClient(n.GRPCAddr, grpcoptions.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// Start launches the full node using the configured compose file, if any, or
+// the bundled one if no LocalNet is running at the default addresses, and waits
+// for it to be ready.
+// The returned function stops the launched full node; it does nothing when
+// attaching to an existing LocalNet.
+func Start(ctx context.Context, config Config) (FullNode, func(context.Context) error, error) {
+	config = withDefaults(config)
+	stop := func(context.Context) error { return nil }
+
+	var project *composeProject
+	switch {
+	case config.ComposeFile != "":
+		project = &composeProject{file: config.ComposeFile}
+	case usesDefaultAddresses(config) && dialTCP(ctx, config.GRPCAddr) != nil:
+		project = &composeProject{}
+	}
+
+	if project != nil {
+		if err := project.run(ctx, "up", "--detach"); err != nil {
+			return FullNode{}, stop, fmt.Errorf("Start: error launching the full node: %w", err)
+		}
+		stop = func(ctx context.Context) error {
+			return project.run(ctx, "down", "--volumes")
+		}
+	}
+
+	fullNode, err := WaitReady(ctx, config)
+	if err != nil {
+		return FullNode{}, stop, fmt.Errorf("Start: %w", err)
+	}
+
+	return fullNode, stop, nil
+}
+
+// Require returns the full node specified by the given config, stopping it once
+// the test completes.
+// The test is skipped if no compose file is configured, no LocalNet is
+// reachable, and the bundled LocalNet can not be launched, either because docker
+// is not installed or because the addresses are not the default ones, so
+// integration tests can run alongside unit tests.
+func Require(t testing.TB, config Config) FullNode {
+	t.Helper()
+
+	config = withDefaults(config)
+	if config.ComposeFile == "" {
+		// Fail fast if no LocalNet can be used, rather than waiting for the ready timeout.
+		if err := dialTCP(context.Background(), config.GRPCAddr); err != nil {
+			if !usesDefaultAddresses(config) {
+				t.Skipf("no LocalNet reachable at %s, and %s not set: %v", config.GRPCAddr, EnvComposeFile, err)
+			}
+			if _, lookErr := exec.LookPath("docker"); lookErr != nil {
+				t.Skipf("no LocalNet reachable at %s, and docker not available: %v", config.GRPCAddr, lookErr)
+			}
+		}
+	}
+
+	fullNode, stop, err := Start(context.Background(), config)
+	t.Cleanup(func() {
+		if err := stop(context.Background()); err != nil {
+			t.Errorf("error stopping the full node: %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("error starting the full node: %v", err)
+	}
+
+	return fullNode
+}
+
+// WaitReady waits until the full node specified by the given config serves its
+// gRPC server, and reports a non-zero block height without catching up.
+func WaitReady(ctx context.Context, config Config) (FullNode, error) {
+	config = withDefaults(config)
+	ctx, cancel := context.WithTimeout(ctx, config.ReadyTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		height, err := checkReady(ctx, config)
+		if err == nil {
+			return FullNode{RPCURL: config.RPCURL, GRPCAddr: config.GRPCAddr, LatestBlockHeight: height}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return FullNode{}, fmt.Errorf("full node not ready after %s: %w", config.ReadyTimeout, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// withDefaults returns the given config, with its unset fields set from the
+// environment variables, or to their defaults.
+func withDefaults(config Config) Config {
+	if config.RPCURL == "" {
+		config.RPCURL = envOrDefault(EnvRPCURL, DefaultRPCURL)
+	}
+	if config.GRPCAddr == "" {
+		config.GRPCAddr = envOrDefault(EnvGRPCAddr, DefaultGRPCAddr)
+	}
+	if config.ComposeFile == "" {
+		config.ComposeFile = os.Getenv(EnvComposeFile)
+	}
+	if config.ReadyTimeout <= 0 {
+		config.ReadyTimeout = defaultReadyTimeout
+	}
+	return config
+}
+
+// envOrDefault returns the value of the given environment variable, or the
+// given default value if it is not set.
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// checkReady returns the latest block height of the full node if it is ready.
+func checkReady(ctx context.Context, config Config) (int64, error) {
+	if err := dialTCP(ctx, config.GRPCAddr); err != nil {
+		return 0, fmt.Errorf("gRPC server not reachable: %w", err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(config.RPCURL, "/")+"/status", nil)
+	if err != nil {
+		return 0, err
+	}
+	httpResponse, err := http.DefaultClient.Do(httpRequest)
+	if err != nil {
+		return 0, fmt.Errorf("RPC server not reachable: %w", err)
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("RPC status returned HTTP %d", httpResponse.StatusCode)
+	}
+
+	var status struct {
+		Result struct {
+			SyncInfo struct {
+				LatestBlockHeight string `json:"latest_block_height"`
+				CatchingUp        bool   `json:"catching_up"`
+			} `json:"sync_info"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(httpResponse.Body).Decode(&status); err != nil {
+		return 0, fmt.Errorf("error decoding the RPC status: %w", err)
+	}
+
+	height, err := strconv.ParseInt(status.Result.SyncInfo.LatestBlockHeight, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid latest block height %q: %w", status.Result.SyncInfo.LatestBlockHeight, err)
+	}
+	if height == 0 || status.Result.SyncInfo.CatchingUp {
+		return 0, errors.New("full node has not produced a block yet, or is catching up")
+	}
+
+	return height, nil
+}
+
+// dialTCP returns an error if no TCP connection can be established to the given address.
+func dialTCP(ctx context.Context, addr string) error {
+	dialer := net.Dialer{Timeout: time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// usesDefaultAddresses returns true if the given config's addresses are the
+// ones the bundled LocalNet exposes its full node at.
+func usesDefaultAddresses(config Config) bool {
+	return config.RPCURL == DefaultRPCURL && config.GRPCAddr == DefaultGRPCAddr
+}
+
+// composeProject is a docker compose project, launched from the given compose
+// file, or from the bundled compose file if none is given.
+type composeProject struct {
+	file string
+}
+
+// run runs the docker compose command with the given arguments on the project.
+func (p composeProject) run(ctx context.Context, args ...string) error {
+	composeArgs := []string{"compose", "--file", p.file}
+	if p.file == "" {
+		// The bundled compose file is read from the standard input.
+		composeArgs = []string{"compose", "--file", "-", "--project-name", bundledProjectName}
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", append(composeArgs, args...)...)
+	if p.file == "" {
+		cmd.Stdin = bytes.NewReader(bundledComposeFile)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("docker compose %s: %w: %s", strings.Join(args, " "), err, output)
+	}
+	return nil
+}
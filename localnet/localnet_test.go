@@ -0,0 +1,61 @@
+package localnet_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/localnet"
+)
+
+func TestWaitReady(t *testing.T) {
+	// The full node is ready once it produced its first block.
+	var statusCalls atomic.Int64
+	rpcServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status" {
+			http.NotFound(w, r)
+			return
+		}
+		height := min(statusCalls.Add(1)-1, 1)
+		_, _ = fmt.Fprintf(w, `{"result":{"sync_info":{"latest_block_height":"%d","catching_up":false}}}`, height)
+	}))
+	defer rpcServer.Close()
+
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer grpcListener.Close()
+
+	fullNode, err := localnet.WaitReady(context.Background(), localnet.Config{
+		RPCURL:   rpcServer.URL,
+		GRPCAddr: grpcListener.Addr().String(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, rpcServer.URL, fullNode.RPCURL)
+	require.Equal(t, grpcListener.Addr().String(), fullNode.GRPCAddr)
+	require.Equal(t, int64(1), fullNode.LatestBlockHeight)
+
+	conn, err := fullNode.DialGRPC()
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
+func TestWaitReady_Timeout(t *testing.T) {
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcAddr := grpcListener.Addr().String()
+	require.NoError(t, grpcListener.Close())
+
+	_, err = localnet.WaitReady(context.Background(), localnet.Config{
+		RPCURL:       "http://" + grpcAddr,
+		GRPCAddr:     grpcAddr,
+		ReadyTimeout: 100 * time.Millisecond,
+	})
+	require.ErrorContains(t, err, "gRPC server not reachable")
+}
@@ -0,0 +1,85 @@
+package sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/cosmos/gogoproto/grpc"
+	grpcoptions "google.golang.org/grpc"
+)
+
+// The names of the caches reported to the MetricsRecorders.
+const (
+	// MetricsCacheSession is the name of the SessionCache's cache.
+	MetricsCacheSession = "session"
+	// MetricsCachePubKey is the name of the CachedAccountClient's cache.
+	MetricsCachePubKey = "pubkey"
+)
+
+// MetricsRecorder records the metrics of the SDK's operations, e.g. to export
+// them to Prometheus using the metrics package, so all the gateways built on
+// the SDK expose the same metrics.
+//
+// It can be set on the SessionCache, the CachedAccountClient and the
+// GatewayClient, and on the gRPC connection to the full node using
+// NewMetricsGRPCConn. Its methods are called synchronously, and must not block.
+type MetricsRecorder interface {
+	// ObserveCacheLookup records a lookup in the given cache, e.g.
+	// MetricsCacheSession, and whether the value was cached.
+	ObserveCacheLookup(cacheName string, hit bool)
+	// ObserveSessionRefresh records the duration and the error, if any, of a
+	// session fetched from the full node.
+	ObserveSessionRefresh(serviceId string, duration time.Duration, err error)
+	// ObserveGRPCQuery records the duration and the error, if any, of a gRPC
+	// query to the full node, by full method name.
+	ObserveGRPCQuery(method string, duration time.Duration, err error)
+	// ObserveRelaySign records the duration and the error, if any, of the
+	// signing of a relay request.
+	ObserveRelaySign(serviceId string, duration time.Duration, err error)
+	// ObserveRelayValidation records the duration and the error, if any, of the
+	// validation of a relay response.
+	ObserveRelayValidation(serviceId string, duration time.Duration, err error)
+}
+
+// NewMetricsGRPCConn returns a gRPC connection which sends the requests
+// through the given connection, recording their durations and errors using the
+// given MetricsRecorder.
+//
+// The returned connection can be used anywhere a gRPC connection is expected,
+// e.g. by NewPoktNodeSessionFetcher or NewPoktNodeAccountFetcher.
+func NewMetricsGRPCConn(conn grpc.ClientConn, recorder MetricsRecorder) grpc.ClientConn {
+	return &metricsGRPCConn{conn: conn, recorder: recorder}
+}
+
+// metricsGRPCConn is a gRPC connection recording the metrics of its queries.
+type metricsGRPCConn struct {
+	conn     grpc.ClientConn
+	recorder MetricsRecorder
+}
+
+// Invoke performs a unary RPC, and records its duration and error.
+func (c *metricsGRPCConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpcoptions.CallOption,
+) error {
+	start := time.Now()
+	err := c.conn.Invoke(ctx, method, args, reply, opts...)
+	c.recorder.ObserveGRPCQuery(method, time.Since(start), err)
+	return err
+}
+
+// NewStream begins a streaming RPC.
+// Only the creation of the stream is recorded.
+func (c *metricsGRPCConn) NewStream(
+	ctx context.Context,
+	desc *grpcoptions.StreamDesc,
+	method string,
+	opts ...grpcoptions.CallOption,
+) (grpcoptions.ClientStream, error) {
+	start := time.Now()
+	stream, err := c.conn.NewStream(ctx, desc, method, opts...)
+	c.recorder.ObserveGRPCQuery(method, time.Since(start), err)
+	return stream, err
+}
@@ -0,0 +1,211 @@
+// Package metrics exports the metrics of the SDK's operations to Prometheus.
+//
+// Its PrometheusRecorder implements the SDK's MetricsRecorder, so it can be set
+// on the SDK's components, and registers standardized collectors, so all the
+// gateways built on the SDK can be scraped and monitored the same way.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Namespace is the namespace of the names of the SDK's metrics.
+const Namespace = "shannon_sdk"
+
+// The values of the status label of the metrics.
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// UnknownServiceId is the value of the service_id label of the operations of
+// the services which are not known to the PrometheusRecorder.
+const UnknownServiceId = "unknown"
+
+// maxLearnedServiceIds is the maximum number of service ids learned by a
+// PrometheusRecorder from the successful session refreshes.
+const maxLearnedServiceIds = 1000
+
+// PrometheusRecorder records the metrics of the SDK's operations using
+// Prometheus collectors. It is safe for concurrent use.
+//
+// The hit ratio of a cache can be computed from the cache_lookups_total
+// counter, e.g. using:
+//
+//	sum(rate(shannon_sdk_cache_lookups_total{cache="session",result="hit"}[5m]))
+//	  / sum(rate(shannon_sdk_cache_lookups_total{cache="session"}[5m]))
+//
+// The service ids are usually provided by the gateways' clients, so the
+// service_id label is bounded to the known services, and set to
+// UnknownServiceId for the others. The known services are the ones set by
+// WithPrometheusServiceIds, or by default the ones whose sessions were
+// successfully fetched, which exist onchain.
+type PrometheusRecorder struct {
+	cacheLookups           *prometheus.CounterVec
+	sessionRefreshDuration *prometheus.HistogramVec
+	grpcQueryDuration      *prometheus.HistogramVec
+	grpcQueryErrors        *prometheus.CounterVec
+	relaySignDuration      *prometheus.HistogramVec
+	relayValidateDuration  *prometheus.HistogramVec
+
+	// serviceIdsMu guards serviceIds, the service ids used as service_id label values.
+	serviceIdsMu sync.RWMutex
+	serviceIds   map[string]struct{}
+	// learnServiceIds is true if the known service ids are learned from the
+	// successful session refreshes, i.e. if WithPrometheusServiceIds is not used.
+	learnServiceIds bool
+}
+
+// PrometheusRecorderOption is a functional option used to configure a
+// PrometheusRecorder.
+type PrometheusRecorderOption func(*PrometheusRecorder)
+
+// WithPrometheusServiceIds sets the ids of the services used as values of the
+// service_id label. The operations of any other service are labeled with
+// UnknownServiceId.
+// By default, the service ids are learned from the successful session refreshes,
+// up to 1000 of them.
+func WithPrometheusServiceIds(serviceIds ...string) PrometheusRecorderOption {
+	return func(r *PrometheusRecorder) {
+		r.learnServiceIds = false
+		for _, serviceId := range serviceIds {
+			r.serviceIds[serviceId] = struct{}{}
+		}
+	}
+}
+
+// NewPrometheusRecorder returns a PrometheusRecorder whose collectors are
+// registered with the given Registerer, e.g. prometheus.DefaultRegisterer,
+// configured using the given options.
+// It panics if the collectors are already registered, e.g. if it is called
+// twice with the same Registerer.
+func NewPrometheusRecorder(registerer prometheus.Registerer, opts ...PrometheusRecorderOption) *PrometheusRecorder {
+	factory := promauto.With(registerer)
+
+	r := &PrometheusRecorder{
+		serviceIds:      make(map[string]struct{}),
+		learnServiceIds: true,
+
+		cacheLookups: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Number of lookups in the SDK's caches, by cache and result (hit or miss).",
+		}, []string{"cache", "result"}),
+		sessionRefreshDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "session_refresh_duration_seconds",
+			Help:      "Duration of the sessions fetched from the full node, by service and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service_id", "status"}),
+		grpcQueryDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "grpc_query_duration_seconds",
+			Help:      "Duration of the gRPC queries to the full node, by method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "status"}),
+		grpcQueryErrors: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "grpc_query_errors_total",
+			Help:      "Number of failed gRPC queries to the full node, by method.",
+		}, []string{"method"}),
+		relaySignDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "relay_sign_duration_seconds",
+			Help:      "Duration of the signing of the relay requests, by service and status.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+		}, []string{"service_id", "status"}),
+		relayValidateDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "relay_validate_duration_seconds",
+			Help:      "Duration of the validation of the relay responses, by service and status.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+		}, []string{"service_id", "status"}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// ObserveCacheLookup records a lookup in the given cache.
+func (r *PrometheusRecorder) ObserveCacheLookup(cacheName string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	r.cacheLookups.WithLabelValues(cacheName, result).Inc()
+}
+
+// ObserveSessionRefresh records the duration of a session fetched from the full node.
+// A successful refresh makes the service known, unless WithPrometheusServiceIds is used.
+func (r *PrometheusRecorder) ObserveSessionRefresh(serviceId string, duration time.Duration, err error) {
+	if err == nil {
+		r.learnServiceId(serviceId)
+	}
+	r.sessionRefreshDuration.WithLabelValues(r.serviceIdLabel(serviceId), status(err)).Observe(duration.Seconds())
+}
+
+// ObserveGRPCQuery records the duration, and the error if any, of a gRPC query.
+func (r *PrometheusRecorder) ObserveGRPCQuery(method string, duration time.Duration, err error) {
+	r.grpcQueryDuration.WithLabelValues(method, status(err)).Observe(duration.Seconds())
+	if err != nil {
+		r.grpcQueryErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// ObserveRelaySign records the duration of the signing of a relay request.
+func (r *PrometheusRecorder) ObserveRelaySign(serviceId string, duration time.Duration, err error) {
+	r.relaySignDuration.WithLabelValues(r.serviceIdLabel(serviceId), status(err)).Observe(duration.Seconds())
+}
+
+// ObserveRelayValidation records the duration of the validation of a relay response.
+func (r *PrometheusRecorder) ObserveRelayValidation(serviceId string, duration time.Duration, err error) {
+	r.relayValidateDuration.WithLabelValues(r.serviceIdLabel(serviceId), status(err)).Observe(duration.Seconds())
+}
+
+// learnServiceId makes the given service known, if the service ids are learned
+// and their maximum number is not reached.
+func (r *PrometheusRecorder) learnServiceId(serviceId string) {
+	if !r.learnServiceIds {
+		return
+	}
+
+	r.serviceIdsMu.RLock()
+	_, known := r.serviceIds[serviceId]
+	r.serviceIdsMu.RUnlock()
+	if known {
+		return
+	}
+
+	r.serviceIdsMu.Lock()
+	defer r.serviceIdsMu.Unlock()
+	if len(r.serviceIds) < maxLearnedServiceIds {
+		r.serviceIds[serviceId] = struct{}{}
+	}
+}
+
+// serviceIdLabel returns the value of the service_id label of the given service:
+// its id if it is known, and UnknownServiceId otherwise.
+func (r *PrometheusRecorder) serviceIdLabel(serviceId string) string {
+	r.serviceIdsMu.RLock()
+	defer r.serviceIdsMu.RUnlock()
+
+	if _, ok := r.serviceIds[serviceId]; !ok {
+		return UnknownServiceId
+	}
+	return serviceId
+}
+
+// status returns the value of the status label of an operation which failed
+// with the given error, if any.
+func status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusOK
+}
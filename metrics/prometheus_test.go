@@ -0,0 +1,107 @@
+package metrics_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/pokt-network/shannon-sdk"
+	"github.com/pokt-network/shannon-sdk/metrics"
+)
+
+var _ sdk.MetricsRecorder = (*metrics.PrometheusRecorder)(nil)
+
+func TestPrometheusRecorder(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	recorder := metrics.NewPrometheusRecorder(registry)
+
+	recorder.ObserveCacheLookup(sdk.MetricsCacheSession, true)
+	recorder.ObserveCacheLookup(sdk.MetricsCacheSession, true)
+	recorder.ObserveCacheLookup(sdk.MetricsCacheSession, false)
+	recorder.ObserveGRPCQuery("/poktroll.session.Query/GetSession", time.Millisecond, nil)
+	recorder.ObserveGRPCQuery("/poktroll.session.Query/GetSession", time.Millisecond, errors.New("unavailable"))
+	recorder.ObserveSessionRefresh("svc1", time.Millisecond, nil)
+	recorder.ObserveRelaySign("svc1", time.Millisecond, nil)
+	recorder.ObserveRelayValidation("svc1", time.Millisecond, nil)
+
+	expected := `
+# HELP shannon_sdk_cache_lookups_total Number of lookups in the SDK's caches, by cache and result (hit or miss).
+# TYPE shannon_sdk_cache_lookups_total counter
+shannon_sdk_cache_lookups_total{cache="session",result="hit"} 2
+shannon_sdk_cache_lookups_total{cache="session",result="miss"} 1
+# HELP shannon_sdk_grpc_query_errors_total Number of failed gRPC queries to the full node, by method.
+# TYPE shannon_sdk_grpc_query_errors_total counter
+shannon_sdk_grpc_query_errors_total{method="/poktroll.session.Query/GetSession"} 1
+`
+	err := testutil.GatherAndCompare(
+		registry,
+		strings.NewReader(expected),
+		"shannon_sdk_cache_lookups_total",
+		"shannon_sdk_grpc_query_errors_total",
+	)
+	require.NoError(t, err)
+
+	count, err := testutil.GatherAndCount(
+		registry,
+		"shannon_sdk_grpc_query_duration_seconds",
+		"shannon_sdk_session_refresh_duration_seconds",
+		"shannon_sdk_relay_sign_duration_seconds",
+		"shannon_sdk_relay_validate_duration_seconds",
+	)
+	require.NoError(t, err)
+	// The gRPC queries are recorded once per status.
+	require.Equal(t, 5, count)
+}
+
+func TestPrometheusRecorder_ServiceIdLabel(t *testing.T) {
+	// The service ids are learned from the successful session refreshes.
+	registry := prometheus.NewPedanticRegistry()
+	recorder := metrics.NewPrometheusRecorder(registry)
+
+	recorder.ObserveSessionRefresh("svc1", time.Millisecond, nil)
+	recorder.ObserveSessionRefresh("attacker-chosen-1", time.Millisecond, errors.New("service not found"))
+	recorder.ObserveRelaySign("svc1", time.Millisecond, nil)
+	recorder.ObserveRelaySign("attacker-chosen-2", time.Millisecond, nil)
+
+	require.ElementsMatch(t, []string{"svc1", metrics.UnknownServiceId}, serviceIdLabels(t, registry, "shannon_sdk_session_refresh_duration_seconds"))
+	require.ElementsMatch(t, []string{"svc1", metrics.UnknownServiceId}, serviceIdLabels(t, registry, "shannon_sdk_relay_sign_duration_seconds"))
+
+	// Only the configured service ids are used once set.
+	registry = prometheus.NewPedanticRegistry()
+	recorder = metrics.NewPrometheusRecorder(registry, metrics.WithPrometheusServiceIds("svc1"))
+
+	recorder.ObserveSessionRefresh("svc1", time.Millisecond, nil)
+	recorder.ObserveSessionRefresh("svc2", time.Millisecond, nil)
+	recorder.ObserveRelayValidation("svc2", time.Millisecond, nil)
+
+	require.ElementsMatch(t, []string{"svc1", metrics.UnknownServiceId}, serviceIdLabels(t, registry, "shannon_sdk_session_refresh_duration_seconds"))
+	require.Equal(t, []string{metrics.UnknownServiceId}, serviceIdLabels(t, registry, "shannon_sdk_relay_validate_duration_seconds"))
+}
+
+// serviceIdLabels returns the service_id label values of the given metric.
+func serviceIdLabels(t *testing.T, registry *prometheus.Registry, name string) []string {
+	t.Helper()
+
+	metricFamilies, err := registry.Gather()
+	require.NoError(t, err)
+
+	var serviceIds []string
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != name {
+			continue
+		}
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "service_id" {
+					serviceIds = append(serviceIds, label.GetValue())
+				}
+			}
+		}
+	}
+	return serviceIds
+}
@@ -0,0 +1,99 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+func TestSessionCache_MetricsRecorder(t *testing.T) {
+	recorder := &fakeMetricsRecorder{}
+	sessionCache := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: &heightSessionFetcher{numBlocksPerSession: 10}},
+		WithCacheConfig(cache.Config{TTL: time.Minute}),
+		WithSessionMetricsRecorder(recorder),
+	)
+
+	ctx := context.Background()
+	for _, height := range []int64{1, 2, 11} {
+		_, err := sessionCache.GetSession(ctx, "app1", "svc1", height)
+		require.NoError(t, err)
+	}
+
+	// The session of height 2 is served from the cache, and the session of
+	// height 11 is fetched on the session rollover.
+	require.Equal(t, []bool{false, true, false}, recorder.cacheLookups[MetricsCacheSession])
+	require.Equal(t, 2, recorder.sessionRefreshes)
+}
+
+func TestMetricsGRPCConn(t *testing.T) {
+	recorder := &fakeMetricsRecorder{}
+	conn := &fakeClientConn{err: status.Error(codes.Unavailable, "unavailable")}
+	metricsConn := NewMetricsGRPCConn(conn, recorder)
+
+	ctx := context.Background()
+	require.Error(t, metricsConn.Invoke(ctx, "method", nil, nil))
+	conn.err = nil
+	require.NoError(t, metricsConn.Invoke(ctx, "method", nil, nil))
+
+	require.Equal(t, 2, recorder.grpcQueries["method"])
+	require.Equal(t, 1, recorder.grpcQueryErrors["method"])
+}
+
+// fakeMetricsRecorder is a MetricsRecorder keeping the recorded metrics in memory.
+type fakeMetricsRecorder struct {
+	mu               sync.Mutex
+	cacheLookups     map[string][]bool
+	sessionRefreshes int
+	grpcQueries      map[string]int
+	grpcQueryErrors  map[string]int
+	relaySigns       int
+	relayValidations int
+}
+
+func (r *fakeMetricsRecorder) ObserveCacheLookup(cacheName string, hit bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cacheLookups == nil {
+		r.cacheLookups = make(map[string][]bool)
+	}
+	r.cacheLookups[cacheName] = append(r.cacheLookups[cacheName], hit)
+}
+
+func (r *fakeMetricsRecorder) ObserveSessionRefresh(string, time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessionRefreshes++
+}
+
+func (r *fakeMetricsRecorder) ObserveGRPCQuery(method string, _ time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.grpcQueries == nil {
+		r.grpcQueries = make(map[string]int)
+		r.grpcQueryErrors = make(map[string]int)
+	}
+	r.grpcQueries[method]++
+	if err != nil {
+		r.grpcQueryErrors[method]++
+	}
+}
+
+func (r *fakeMetricsRecorder) ObserveRelaySign(string, time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.relaySigns++
+}
+
+func (r *fakeMetricsRecorder) ObserveRelayValidation(string, time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.relayValidations++
+}
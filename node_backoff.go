@@ -0,0 +1,296 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cosmos/gogoproto/grpc"
+	grpcoptions "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// ErrNodeRateLimited is returned when a request to a full node is not sent
+// because the full node is rate limiting the SDK's requests, and the request's
+// context is done, or would be, before the backoff ends.
+var ErrNodeRateLimited = sdkerrors.ErrNodeRateLimited
+
+// defaultNodeBackoffPolicy is the policy of the delays applied after successive
+// rate limit errors of the full node.
+var defaultNodeBackoffPolicy = retry.Exponential{
+	Initial: time.Second,
+	Max:     time.Minute,
+	Jitter:  true,
+}
+
+// NodeBackoffOption is a functional option used to configure a NodeBackoff.
+type NodeBackoffOption func(*NodeBackoff)
+
+// WithNodeBackoffPolicy sets the policy of the delays applied after successive
+// rate limit errors. It defaults to an exponential backoff with full jitter,
+// from 1 second up to 1 minute.
+func WithNodeBackoffPolicy(policy retry.Policy) NodeBackoffOption {
+	return func(b *NodeBackoff) {
+		b.policy = policy
+	}
+}
+
+// WithNodeBackoffEventBus publishes a NodeRateLimitedEvent on the given
+// EventBus whenever the backoff starts or grows, and a NodeRateLimitRecoveredEvent
+// once the full node accepts requests again.
+func WithNodeBackoffEventBus(eventBus *EventBus) NodeBackoffOption {
+	return func(b *NodeBackoff) {
+		b.eventBus = eventBus
+	}
+}
+
+// NodeBackoff applies a client-wide backoff to the requests sent to the full
+// node once it returns rate limit errors, e.g. ResourceExhausted gRPC errors
+// or HTTP 429 responses, so a rate limited full node provider does not cause a
+// retry storm from the SDK.
+//
+// While backing off, requests wait for the backoff delay to end. A single
+// request is then sent as a recovery probe, while the others keep waiting:
+// if the probe is rate limited too, the delay grows following the backoff
+// policy, and all the waiting requests are released otherwise.
+//
+// A single NodeBackoff should be shared by all the fetchers of a full node,
+// e.g. using NewNodeBackoffGRPCConn and NewNodeBackoffStatusFetcher.
+// It is safe for concurrent use.
+type NodeBackoff struct {
+	policy   retry.Policy
+	eventBus *EventBus
+	// now returns the current time. It is overridden in tests.
+	now func() time.Time
+
+	mu sync.Mutex
+	// attempt is the number of successive rate limit errors, zero if the
+	// requests are not delayed.
+	attempt int
+	// since is the time at which the backoff started.
+	since time.Time
+	// until is the end of the current backoff delay.
+	until time.Time
+	// probing is set while a recovery probe is in-flight.
+	probing bool
+	// changed is closed, and replaced, whenever the backoff state changes.
+	changed chan struct{}
+}
+
+// NewNodeBackoff returns a NodeBackoff configured using the given options.
+func NewNodeBackoff(opts ...NodeBackoffOption) *NodeBackoff {
+	b := &NodeBackoff{
+		policy:  defaultNodeBackoffPolicy,
+		now:     time.Now,
+		changed: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Acquire waits until a request can be sent to the full node.
+//
+// It returns a function that must be called with the request's error, or nil,
+// once it completes, or an error wrapping ErrNodeRateLimited if the context is
+// done before the backoff ends.
+func (b *NodeBackoff) Acquire(ctx context.Context) (report func(err error), err error) {
+	for {
+		b.mu.Lock()
+		if b.attempt == 0 {
+			b.mu.Unlock()
+			return func(err error) { b.report(0, false, err) }, nil
+		}
+
+		attempt, now, changed := b.attempt, b.now(), b.changed
+		if !now.Before(b.until) && !b.probing {
+			b.probing = true
+			b.mu.Unlock()
+			return func(err error) { b.report(attempt, true, err) }, nil
+		}
+
+		wait := b.until.Sub(now)
+		b.mu.Unlock()
+
+		// Requests wait for the probe's outcome once the delay ends.
+		if deadline, ok := ctx.Deadline(); ok && wait > 0 && deadline.Before(now.Add(wait)) {
+			return nil, fmt.Errorf("%w: backing off for %s", ErrNodeRateLimited, wait)
+		}
+		if err := waitForBackoff(ctx, wait, changed); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNodeRateLimited, err)
+		}
+	}
+}
+
+// waitForBackoff waits for the given delay to elapse, or for the given channel
+// to be closed, whichever comes first, or returns the context's error if it is
+// done before. A non-positive delay only waits for the channel.
+func waitForBackoff(ctx context.Context, delay time.Duration, changed <-chan struct{}) error {
+	var timerC <-chan time.Time
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		timerC = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+	case <-timerC:
+	}
+	return nil
+}
+
+// RateLimited returns true if the requests to the full node are being delayed.
+func (b *NodeBackoff) RateLimited() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.attempt > 0
+}
+
+// report records the outcome of a request acquired at the given backoff attempt.
+func (b *NodeBackoff) report(attempt int, probe bool, err error) {
+	var event Event
+
+	b.mu.Lock()
+	switch {
+	case attempt != b.attempt:
+		// The outcome of a request acquired before the backoff started, or grew,
+		// which must not grow the backoff again: concurrent requests are usually
+		// rate limited together.
+	case IsRateLimitError(err):
+		b.attempt++
+		if b.attempt == 1 {
+			b.since = b.now()
+		}
+		var delay time.Duration
+		if b.policy != nil {
+			delay = b.policy.Delay(b.attempt)
+		}
+		b.until = b.now().Add(delay)
+		b.probing = false
+		b.notify()
+		event = NodeRateLimitedEvent{Attempt: b.attempt, Delay: delay, Err: err}
+	case probe && !retry.IsNotContextError(err):
+		// The probe did not complete: another request probes the full node.
+		b.probing = false
+		b.notify()
+	case probe:
+		event = NodeRateLimitRecoveredEvent{Duration: b.now().Sub(b.since)}
+		b.attempt = 0
+		b.probing = false
+		b.notify()
+	}
+	b.mu.Unlock()
+
+	if event != nil {
+		b.eventBus.Publish(event)
+	}
+}
+
+// notify wakes up the requests waiting for the backoff state to change.
+// It must be called with the lock held.
+func (b *NodeBackoff) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// IsRateLimitError returns true if the given error is caused by the full node
+// rate limiting the requests, i.e. a ResourceExhausted gRPC error, or a non-gRPC
+// RPC error reporting an HTTP "429 Too Many Requests" status.
+func IsRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if grpcStatus, ok := status.FromError(err); ok {
+		return grpcStatus.Code() == codes.ResourceExhausted
+	}
+
+	// The cometbft RPC client does not expose the HTTP status of its errors.
+	return strings.Contains(strings.ToLower(err.Error()), "429 too many requests")
+}
+
+// NewNodeBackoffGRPCConn returns a gRPC connection which sends the requests
+// through the given connection, applying the given NodeBackoff.
+//
+// The returned connection can be used anywhere a gRPC connection is expected,
+// e.g. by NewPoktNodeSessionFetcher or NewPoktNodeAccountFetcher.
+func NewNodeBackoffGRPCConn(conn grpc.ClientConn, backoff *NodeBackoff) grpc.ClientConn {
+	return &nodeBackoffGRPCConn{conn: conn, backoff: backoff}
+}
+
+// nodeBackoffGRPCConn is a gRPC connection applying a NodeBackoff.
+type nodeBackoffGRPCConn struct {
+	conn    grpc.ClientConn
+	backoff *NodeBackoff
+}
+
+// Invoke performs a unary RPC once the backoff allows it.
+func (c *nodeBackoffGRPCConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...grpcoptions.CallOption,
+) error {
+	report, err := c.backoff.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.conn.Invoke(ctx, method, args, reply, opts...)
+	report(err)
+	return err
+}
+
+// NewStream begins a streaming RPC once the backoff allows it.
+// Only the creation of the stream is subject to the backoff.
+func (c *nodeBackoffGRPCConn) NewStream(
+	ctx context.Context,
+	desc *grpcoptions.StreamDesc,
+	method string,
+	opts ...grpcoptions.CallOption,
+) (grpcoptions.ClientStream, error) {
+	report, err := c.backoff.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.conn.NewStream(ctx, desc, method, opts...)
+	report(err)
+	return stream, err
+}
+
+// NewNodeBackoffStatusFetcher returns a PoktNodeStatusFetcher fetching the
+// status using the given fetcher, applying the given NodeBackoff.
+func NewNodeBackoffStatusFetcher(statusFetcher PoktNodeStatusFetcher, backoff *NodeBackoff) PoktNodeStatusFetcher {
+	return &nodeBackoffStatusFetcher{statusFetcher: statusFetcher, backoff: backoff}
+}
+
+// nodeBackoffStatusFetcher is a PoktNodeStatusFetcher applying a NodeBackoff.
+type nodeBackoffStatusFetcher struct {
+	statusFetcher PoktNodeStatusFetcher
+	backoff       *NodeBackoff
+}
+
+// Status returns the status of the full node once the backoff allows it.
+func (f *nodeBackoffStatusFetcher) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	report, err := f.backoff.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeStatus, err := f.statusFetcher.Status(ctx)
+	report(err)
+	return nodeStatus, err
+}
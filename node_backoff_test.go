@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+)
+
+func TestIsRateLimitError(t *testing.T) {
+	require.True(t, IsRateLimitError(status.Error(codes.ResourceExhausted, "quota exceeded")))
+	require.True(t, IsRateLimitError(errors.New("error in json rpc client: 429 Too Many Requests")))
+	require.False(t, IsRateLimitError(status.Error(codes.Unavailable, "unavailable")))
+	require.False(t, IsRateLimitError(context.DeadlineExceeded))
+	require.False(t, IsRateLimitError(nil))
+
+	// Only the non-gRPC errors are matched by message, against the full status.
+	require.False(t, IsRateLimitError(status.Error(codes.Unavailable, "429 Too Many Requests")))
+	require.False(t, IsRateLimitError(errors.New("error getting block 4290: not found")))
+	require.False(t, IsRateLimitError(errors.New("too many requests in batch")))
+}
+
+func TestNodeBackoff(t *testing.T) {
+	backoff := NewNodeBackoff(WithNodeBackoffPolicy(retry.Constant{Interval: time.Second}))
+	now := time.Now()
+	var nowMu sync.Mutex
+	backoff.now = func() time.Time {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		now = now.Add(d)
+	}
+
+	ctx := context.Background()
+	rateLimitErr := status.Error(codes.ResourceExhausted, "rate limited")
+
+	// Concurrent requests rate limited together only start a single backoff.
+	report1, err := backoff.Acquire(ctx)
+	require.NoError(t, err)
+	report2, err := backoff.Acquire(ctx)
+	require.NoError(t, err)
+	report1(rateLimitErr)
+	report2(rateLimitErr)
+	require.True(t, backoff.RateLimited())
+	require.Equal(t, 1, backoff.attempt)
+
+	// Requests are rejected if the backoff ends after their deadline.
+	shortCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	_, err = backoff.Acquire(shortCtx)
+	require.ErrorIs(t, err, ErrNodeRateLimited)
+
+	// Once the delay ends, a single probe is sent while the others wait.
+	advance(time.Second)
+	probeReport, err := backoff.Acquire(ctx)
+	require.NoError(t, err)
+
+	waiterDone := make(chan error)
+	go func() {
+		report, err := backoff.Acquire(ctx)
+		if err == nil {
+			report(nil)
+		}
+		waiterDone <- err
+	}()
+
+	// A rate limited probe grows the backoff.
+	probeReport(rateLimitErr)
+	require.Equal(t, 2, backoff.attempt)
+	select {
+	case <-waiterDone:
+		t.Fatal("request sent while backing off")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// A successful probe releases the waiting requests.
+	advance(time.Second)
+	probeReport, err = backoff.Acquire(ctx)
+	require.NoError(t, err)
+	probeReport(nil)
+	require.NoError(t, <-waiterDone)
+	require.False(t, backoff.RateLimited())
+}
+
+func TestNodeBackoff_CanceledProbe(t *testing.T) {
+	backoff := NewNodeBackoff(WithNodeBackoffPolicy(retry.Constant{}))
+	ctx := context.Background()
+
+	report, err := backoff.Acquire(ctx)
+	require.NoError(t, err)
+	report(status.Error(codes.ResourceExhausted, "rate limited"))
+
+	// A probe which does not complete is replaced by another request.
+	probeReport, err := backoff.Acquire(ctx)
+	require.NoError(t, err)
+	probeReport(context.Canceled)
+	require.True(t, backoff.RateLimited())
+
+	probeReport, err = backoff.Acquire(ctx)
+	require.NoError(t, err)
+	probeReport(nil)
+	require.False(t, backoff.RateLimited())
+}
+
+func TestNodeBackoffGRPCConn(t *testing.T) {
+	eventBus := NewEventBus()
+	var events []Event
+	eventBus.Subscribe(func(event Event) {
+		events = append(events, event)
+	}, EventNodeRateLimited, EventNodeRateLimitRecovered)
+
+	backoff := NewNodeBackoff(WithNodeBackoffPolicy(retry.Constant{}), WithNodeBackoffEventBus(eventBus))
+	conn := &fakeClientConn{err: status.Error(codes.ResourceExhausted, "rate limited")}
+	backoffConn := NewNodeBackoffGRPCConn(conn, backoff)
+
+	ctx := context.Background()
+	require.Equal(t, codes.ResourceExhausted, status.Code(backoffConn.Invoke(ctx, "method", nil, nil)))
+	require.True(t, backoff.RateLimited())
+
+	conn.err = nil
+	require.NoError(t, backoffConn.Invoke(ctx, "method", nil, nil))
+	require.False(t, backoff.RateLimited())
+
+	require.Len(t, events, 2)
+	require.IsType(t, NodeRateLimitedEvent{}, events[0])
+	require.IsType(t, NodeRateLimitRecoveredEvent{}, events[1])
+}
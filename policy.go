@@ -0,0 +1,97 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// ErrJSONRPCMethodNotAllowed is returned when a JSON-RPC request's method is
+// rejected by the JSONRPCMethodPolicy of the request's service.
+var ErrJSONRPCMethodNotAllowed = sdkerrors.ErrJSONRPCMethodNotAllowed
+
+// MethodRules specifies the JSON-RPC methods allowed or denied for a service.
+//
+// If AllowedMethods is not empty, only the listed methods are allowed.
+// Any method listed in DeniedMethods is rejected, even if it is also allowed.
+type MethodRules struct {
+	AllowedMethods []string
+	DeniedMethods  []string
+}
+
+// JSONRPCMethodPolicy specifies the JSON-RPC methods that may be relayed,
+// per service id.
+// For example, it can be used to block eth_sendRawTransaction for services
+// that are offered as read-only.
+//
+// Services without rules allow all methods.
+type JSONRPCMethodPolicy struct {
+	ServiceRules map[string]MethodRules
+}
+
+// Check returns an error if any method called by the given request is not
+// allowed for the given service id.
+//
+// The methods are read from the request body whatever its Content-Type, and
+// every call of a JSON-RPC batch is checked. The requests of a service with
+// rules whose methods can not be read, e.g. non JSON-RPC requests or empty
+// batches, are rejected, so the rules can not be bypassed.
+func (p JSONRPCMethodPolicy) Check(
+	serviceId string,
+	poktRequest *types.POKTHTTPRequest,
+) error {
+	rules, ok := p.ServiceRules[serviceId]
+	if !ok {
+		return nil
+	}
+
+	methods, ok := poktRequest.GetJSONRPCMethods()
+	if !ok {
+		return fmt.Errorf("%w: the JSON-RPC methods of the request to service %s can not be read", ErrJSONRPCMethodNotAllowed, serviceId)
+	}
+
+	for _, method := range methods {
+		if slices.Contains(rules.DeniedMethods, method) {
+			return fmt.Errorf("%w: method %q is denied for service %s", ErrJSONRPCMethodNotAllowed, method, serviceId)
+		}
+
+		if len(rules.AllowedMethods) > 0 && !slices.Contains(rules.AllowedMethods, method) {
+			return fmt.Errorf("%w: method %q is not allowed for service %s", ErrJSONRPCMethodNotAllowed, method, serviceId)
+		}
+	}
+
+	return nil
+}
+
+// CheckPayload deserializes the given serialized POKTHTTPRequest and checks it
+// against the policy of the given service id.
+// It is called by GatewayClient.Relay, before the relay request is signed,
+// when the GatewayClient's MethodPolicy is set.
+func (p JSONRPCMethodPolicy) CheckPayload(serviceId string, requestBz []byte) error {
+	poktRequest, err := types.DeserializeHTTPRequest(requestBz)
+	if err != nil {
+		return fmt.Errorf("error deserializing relay request payload: %w", err)
+	}
+
+	return p.Check(serviceId, poktRequest)
+}
+
+// CheckRelayPayload deserializes the given relay request payload and checks it
+// against the policy of the service of the given endpoint.
+// It is intended to be called before building, signing and sending the relay request.
+func (p JSONRPCMethodPolicy) CheckRelayPayload(
+	endpoint Endpoint,
+	requestBz []byte,
+) error {
+	if endpoint == nil {
+		return errors.New("CheckRelayPayload: endpoint not specified")
+	}
+
+	if err := p.CheckPayload(endpoint.Header().ServiceId, requestBz); err != nil {
+		return fmt.Errorf("CheckRelayPayload: %w", err)
+	}
+	return nil
+}
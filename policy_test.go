@@ -0,0 +1,133 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestJSONRPCMethodPolicy_Check(t *testing.T) {
+	policy := JSONRPCMethodPolicy{
+		ServiceRules: map[string]MethodRules{
+			"readonly": {DeniedMethods: []string{"eth_sendRawTransaction"}},
+			"allowlist": {
+				AllowedMethods: []string{"eth_blockNumber", "eth_sendRawTransaction"},
+				DeniedMethods:  []string{"eth_sendRawTransaction"},
+			},
+		},
+	}
+
+	tests := []struct {
+		desc        string
+		serviceId   string
+		contentType string
+		body        string
+		expectErr   bool
+	}{
+		{
+			desc:      "denied method",
+			serviceId: "readonly",
+			body:      `{"jsonrpc":"2.0","method":"eth_sendRawTransaction","params":[],"id":1}`,
+			expectErr: true,
+		},
+		{
+			desc:      "method not denied",
+			serviceId: "readonly",
+			body:      `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`,
+		},
+		{
+			desc:      "allowed method",
+			serviceId: "allowlist",
+			body:      `{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`,
+		},
+		{
+			desc:      "method not in allow list",
+			serviceId: "allowlist",
+			body:      `{"jsonrpc":"2.0","method":"eth_chainId","params":[],"id":1}`,
+			expectErr: true,
+		},
+		{
+			desc:      "deny list takes precedence over allow list",
+			serviceId: "allowlist",
+			body:      `{"jsonrpc":"2.0","method":"eth_sendRawTransaction","params":[],"id":1}`,
+			expectErr: true,
+		},
+		{
+			desc:      "service without rules",
+			serviceId: "other",
+			body:      `{"jsonrpc":"2.0","method":"eth_sendRawTransaction","params":[],"id":1}`,
+		},
+		{
+			desc:      "non JSON-RPC request of a service with rules",
+			serviceId: "readonly",
+			body:      `{"key":"value"}`,
+			expectErr: true,
+		},
+		{
+			desc:      "non JSON-RPC request of a service without rules",
+			serviceId: "other",
+			body:      `{"key":"value"}`,
+		},
+		{
+			desc:        "denied method with a charset suffix",
+			serviceId:   "readonly",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"jsonrpc":"2.0","method":"eth_sendRawTransaction","params":[],"id":1}`,
+			expectErr:   true,
+		},
+		{
+			desc:      "denied method with a string id",
+			serviceId: "readonly",
+			body:      `{"jsonrpc":"2.0","method":"eth_sendRawTransaction","params":[],"id":"1"}`,
+			expectErr: true,
+		},
+		{
+			desc:      "denied method with a zero id",
+			serviceId: "readonly",
+			body:      `{"jsonrpc":"2.0","method":"eth_sendRawTransaction","params":[],"id":0}`,
+			expectErr: true,
+		},
+		{
+			desc:      "denied method in a batch",
+			serviceId: "readonly",
+			body:      `[{"jsonrpc":"2.0","method":"eth_blockNumber","id":1},{"jsonrpc":"2.0","method":"eth_sendRawTransaction","id":2}]`,
+			expectErr: true,
+		},
+		{
+			desc:      "batch of allowed methods",
+			serviceId: "allowlist",
+			body:      `[{"jsonrpc":"2.0","method":"eth_blockNumber","id":1},{"jsonrpc":"2.0","method":"eth_blockNumber","id":2}]`,
+		},
+		{
+			desc:      "empty batch",
+			serviceId: "readonly",
+			body:      `[]`,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			contentType := test.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			poktRequest := &types.POKTHTTPRequest{
+				Header: map[string]*types.Header{
+					"Content-Type": {Key: "Content-Type", Values: []string{contentType}},
+				},
+				Method: "POST",
+				BodyBz: []byte(test.body),
+			}
+
+			err := policy.Check(test.serviceId, poktRequest)
+			if test.expectErr {
+				require.ErrorIs(t, err, ErrJSONRPCMethodNotAllowed)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+)
+
+// RelayFailure captures the data available to a gateway when a relay fails.
+// Any of its fields may be unset, depending on the step at which the relay failed.
+type RelayFailure struct {
+	Session         *sessiontypes.Session
+	Endpoint        Endpoint
+	RelayRequest    *servicetypes.RelayRequest
+	RelayResponseBz []byte
+	Err             error
+}
+
+// RelayPostMortem is a diagnostic bundle describing a failed relay, intended to
+// be serialized to JSON and attached to support tickets or GitHub issues.
+//
+// The signature of the relay request is redacted.
+type RelayPostMortem struct {
+	CreatedAt time.Time `json:"created_at"`
+	Error     string    `json:"error,omitempty"`
+	// SDK is the build of the SDK which sent the relay.
+	SDK VersionInfo `json:"sdk"`
+
+	Session          *sessiontypes.Session         `json:"session,omitempty"`
+	SupplierAddress  SupplierAddress               `json:"supplier_address,omitempty"`
+	SupplierEndpoint *sharedtypes.SupplierEndpoint `json:"supplier_endpoint,omitempty"`
+
+	// RelayRequestBz is the serialized relay request, with its signature removed.
+	RelayRequestBz []byte `json:"relay_request,omitempty"`
+	// RelayRequestSignatureLen is the length of the redacted signature, which
+	// allows telling a missing signature apart from a redacted one.
+	RelayRequestSignatureLen int    `json:"relay_request_signature_len"`
+	RelayResponseBz          []byte `json:"relay_response,omitempty"`
+	// ValidationErr is the error returned by ValidateBasic on the relay response, if any.
+	ValidationErr string `json:"validation_error,omitempty"`
+
+	NodeStatus *RelayPostMortemNodeStatus `json:"node_status,omitempty"`
+
+	// BuildErrs lists the errors encountered while building the post-mortem.
+	BuildErrs []string `json:"build_errors,omitempty"`
+}
+
+// RelayPostMortemNodeStatus is the status of the POKT full node at the time a
+// RelayPostMortem was built.
+type RelayPostMortemNodeStatus struct {
+	LatestBlockHeight int64     `json:"latest_block_height"`
+	LatestBlockTime   time.Time `json:"latest_block_time"`
+	CatchingUp        bool      `json:"catching_up"`
+	// Err is set if the status of the full node could not be fetched.
+	Err string `json:"error,omitempty"`
+}
+
+// NewRelayPostMortem builds a RelayPostMortem for the given relay failure.
+//
+// If the given BlockClient is not nil, it is used to capture the chain height
+// and the health of the POKT full node.
+// Errors encountered while building the post-mortem are recorded in it, so
+// that a post-mortem is always returned.
+func NewRelayPostMortem(
+	ctx context.Context,
+	failure RelayFailure,
+	blockClient *BlockClient,
+) RelayPostMortem {
+	postMortem := RelayPostMortem{
+		CreatedAt:       time.Now().UTC(),
+		SDK:             Version(),
+		Session:         failure.Session,
+		RelayResponseBz: failure.RelayResponseBz,
+	}
+
+	if failure.Err != nil {
+		postMortem.Error = failure.Err.Error()
+	}
+
+	if failure.Endpoint != nil {
+		supplierEndpoint := failure.Endpoint.Endpoint()
+		postMortem.SupplierAddress = failure.Endpoint.Supplier()
+		postMortem.SupplierEndpoint = &supplierEndpoint
+	}
+
+	if failure.RelayRequest != nil {
+		redactedRequest := *failure.RelayRequest
+		postMortem.RelayRequestSignatureLen = len(redactedRequest.Meta.Signature)
+		redactedRequest.Meta.Signature = nil
+
+		relayRequestBz, err := redactedRequest.Marshal()
+		if err != nil {
+			postMortem.BuildErrs = append(postMortem.BuildErrs, fmt.Sprintf("error marshaling relay request: %v", err))
+		} else {
+			postMortem.RelayRequestBz = relayRequestBz
+		}
+	}
+
+	if len(failure.RelayResponseBz) > 0 {
+		relayResponse := &servicetypes.RelayResponse{}
+		if err := relayResponse.Unmarshal(failure.RelayResponseBz); err != nil {
+			postMortem.ValidationErr = fmt.Sprintf("error unmarshaling relay response: %v", err)
+		} else if err := relayResponse.ValidateBasic(); err != nil {
+			postMortem.ValidationErr = err.Error()
+		}
+	}
+
+	if blockClient != nil {
+		postMortem.NodeStatus = getPostMortemNodeStatus(ctx, blockClient)
+	}
+
+	return postMortem
+}
+
+// JSON returns the JSON serialization of the post-mortem.
+func (p RelayPostMortem) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// getPostMortemNodeStatus returns the status of the full node used by the given BlockClient.
+func getPostMortemNodeStatus(ctx context.Context, blockClient *BlockClient) *RelayPostMortemNodeStatus {
+	if blockClient.PoktNodeStatusFetcher == nil {
+		return &RelayPostMortemNodeStatus{Err: "PoktNodeStatusFetcher not set"}
+	}
+
+	status, err := blockClient.PoktNodeStatusFetcher.Status(ctx)
+	if err != nil {
+		return &RelayPostMortemNodeStatus{Err: err.Error()}
+	}
+
+	return &RelayPostMortemNodeStatus{
+		LatestBlockHeight: status.SyncInfo.LatestBlockHeight,
+		LatestBlockTime:   status.SyncInfo.LatestBlockTime,
+		CatchingUp:        status.SyncInfo.CatchingUp,
+	}
+}
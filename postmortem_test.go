@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRelayPostMortem(t *testing.T) {
+	header := sessiontypes.SessionHeader{ServiceId: "svc1", SessionId: "session1"}
+	endpoint := NewEndpoint(
+		header,
+		sharedtypes.SupplierEndpoint{Url: "https://supplier.example"},
+		SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: "pokt1supplier"}},
+	)
+
+	relayRequest, err := BuildRelayRequest(endpoint, []byte("payload"))
+	require.NoError(t, err)
+	relayRequest.Meta.Signature = []byte("secret signature")
+
+	postMortem := NewRelayPostMortem(
+		context.Background(),
+		RelayFailure{
+			Session:         &sessiontypes.Session{Header: &header},
+			Endpoint:        endpoint,
+			RelayRequest:    relayRequest,
+			RelayResponseBz: []byte("not a relay response"),
+			Err:             errors.New("relay failed"),
+		},
+		&BlockClient{PoktNodeStatusFetcher: &fakeStatusFetcher{height: 42}},
+	)
+
+	// The signature of the original relay request is left untouched.
+	require.Equal(t, []byte("secret signature"), relayRequest.Meta.Signature)
+
+	require.Equal(t, "relay failed", postMortem.Error)
+	require.Equal(t, Version(), postMortem.SDK)
+	require.Equal(t, SupplierAddress("pokt1supplier"), postMortem.SupplierAddress)
+	require.Equal(t, len("secret signature"), postMortem.RelayRequestSignatureLen)
+	require.NotEmpty(t, postMortem.ValidationErr)
+	require.Equal(t, int64(42), postMortem.NodeStatus.LatestBlockHeight)
+	require.Empty(t, postMortem.BuildErrs)
+
+	redactedRequest := &servicetypes.RelayRequest{}
+	require.NoError(t, redactedRequest.Unmarshal(postMortem.RelayRequestBz))
+	require.Empty(t, redactedRequest.Meta.Signature)
+	require.Equal(t, []byte("payload"), redactedRequest.Payload)
+
+	postMortemJSON, err := postMortem.JSON()
+	require.NoError(t, err)
+	require.True(t, json.Valid(postMortemJSON))
+	require.NotContains(t, string(postMortemJSON), "secret signature")
+}
+
+// fakeStatusFetcher is a PoktNodeStatusFetcher returning a fixed latest block height.
+type fakeStatusFetcher struct {
+	height int64
+}
+
+func (f *fakeStatusFetcher) Status(context.Context) (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: f.height}}, nil
+}
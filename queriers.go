@@ -0,0 +1,75 @@
+package sdk
+
+import (
+	"context"
+
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+)
+
+// The interfaces below are the onchain queries the SDK components depend on,
+// one per module, so the components can be built by dependency injection
+// frameworks, e.g. wire or fx, and tested using mocks.
+// The SDK's clients implement them, e.g. the ApplicationClient implements the
+// ApplicationQuerier.
+
+// ApplicationQuerier queries the applications of the application module.
+type ApplicationQuerier interface {
+	GetApplication(ctx context.Context, appAddress string) (apptypes.Application, error)
+	GetAllApplications(ctx context.Context) ([]apptypes.Application, error)
+}
+
+// SessionQuerier queries the sessions of the session module.
+type SessionQuerier interface {
+	GetSession(ctx context.Context, appAddress, serviceId string, height int64) (*sessiontypes.Session, error)
+}
+
+// AccountQuerier queries the public keys of the accounts of the auth module.
+// It is the PublicKeyFetcher.
+type AccountQuerier = PublicKeyFetcher
+
+// SharedParamsQuerier queries the params of the shared module, e.g. the
+// sessions' grace period.
+type SharedParamsQuerier interface {
+	GetParams(ctx context.Context) (sharedtypes.Params, error)
+}
+
+// SupplierQuerier queries the suppliers of the supplier module.
+type SupplierQuerier interface {
+	GetSupplier(ctx context.Context, operatorAddress string) (SupplierInfo, error)
+	GetAllSuppliers(ctx context.Context) ([]SupplierInfo, error)
+}
+
+// BlockQuerier queries the latest block height of the chain.
+type BlockQuerier interface {
+	LatestBlockHeight(ctx context.Context) (int64, error)
+}
+
+var (
+	_ ApplicationQuerier  = (*ApplicationClient)(nil)
+	_ SessionQuerier      = (*SessionClient)(nil)
+	_ AccountQuerier      = (*AccountClient)(nil)
+	_ AccountQuerier      = (*CachedAccountClient)(nil)
+	_ SharedParamsQuerier = (*SharedClient)(nil)
+	_ SupplierQuerier     = (*SupplierClient)(nil)
+	_ BlockQuerier        = (*BlockClient)(nil)
+)
+
+// SharedClient is the interface to interact with the onchain shared module,
+// e.g. to get the sessions' grace period.
+//
+// The SharedClient uses the gRPC query client of the shared module.
+type SharedClient struct {
+	sharedtypes.QueryClient
+}
+
+// GetParams returns the current params of the shared module.
+func (sc *SharedClient) GetParams(ctx context.Context) (sharedtypes.Params, error) {
+	res, err := sc.QueryClient.Params(ctx, &sharedtypes.QueryParamsRequest{})
+	if err != nil {
+		return sharedtypes.Params{}, err
+	}
+
+	return res.Params, nil
+}
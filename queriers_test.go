@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+// The components depending on the querier interfaces can be built using mocks
+// rather than the SDK's gRPC clients.
+func TestQueriers_Mocks(t *testing.T) {
+	sessionQuerier := &mockSessionQuerier{numBlocksPerSession: 10}
+	sessionCache := NewSessionCacheWithOptions(sessionQuerier, WithCacheConfig(cache.Config{TTL: time.Minute}))
+	resolver := NewEffectiveSessionResolverFromQuerier(sessionCache, mockSharedParamsQuerier{
+		NumBlocksPerSession:        10,
+		GracePeriodEndOffsetBlocks: 2,
+	})
+
+	effectiveSession, err := resolver.ResolveEffectiveSession(context.Background(), "svc1", "app1", 11)
+	require.NoError(t, err)
+	require.True(t, effectiveSession.IsPreviousSession)
+	require.Equal(t, int64(0), effectiveSession.Header.SessionStartBlockHeight)
+	require.Equal(t, int64(11), effectiveSession.GracePeriodEndHeight)
+
+	_, err = sessionCache.GetSession(context.Background(), "app1", "svc1", 11)
+	require.NoError(t, err)
+	require.Equal(t, 2, sessionQuerier.calls)
+}
+
+func TestSharedClient_GetParams(t *testing.T) {
+	params := sharedtypes.Params{NumBlocksPerSession: 10, GracePeriodEndOffsetBlocks: 2}
+	sharedClient := &SharedClient{QueryClient: &fakeSharedQueryClient{params: params}}
+
+	fetchedParams, err := sharedClient.GetParams(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, params, fetchedParams)
+}
+
+// mockSessionQuerier is a SessionQuerier returning sessions of a fixed number
+// of blocks, and counting its calls.
+type mockSessionQuerier struct {
+	numBlocksPerSession int64
+	calls               int
+}
+
+func (q *mockSessionQuerier) GetSession(
+	_ context.Context,
+	appAddress string,
+	serviceId string,
+	height int64,
+) (*sessiontypes.Session, error) {
+	q.calls++
+	startHeight := height / q.numBlocksPerSession * q.numBlocksPerSession
+
+	return &sessiontypes.Session{
+		Header: &sessiontypes.SessionHeader{
+			ApplicationAddress:      appAddress,
+			ServiceId:               serviceId,
+			SessionStartBlockHeight: startHeight,
+			SessionEndBlockHeight:   startHeight + q.numBlocksPerSession - 1,
+		},
+	}, nil
+}
+
+// mockSharedParamsQuerier is a SharedParamsQuerier returning fixed params.
+type mockSharedParamsQuerier sharedtypes.Params
+
+func (q mockSharedParamsQuerier) GetParams(context.Context) (sharedtypes.Params, error) {
+	return sharedtypes.Params(q), nil
+}
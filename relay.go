@@ -1,15 +1,33 @@
 package sdk
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"sync"
+	"syscall"
+	"time"
 
 	cosmossdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/pokt-network/poktroll/app"
 	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
 )
 
+// ErrRelayResponseTooLarge is returned when a supplier's relay response exceeds
+// the maximum size set using WithMaxRelayResponseSize.
+var ErrRelayResponseTooLarge = sdkerrors.ErrRelayResponseTooLarge
+
+// errRelayNotSent is wrapped by the errors of the relays which failed before a
+// connection to the supplier was obtained, so the relay request was not sent.
+var errRelayNotSent = errors.New("relay not sent")
+
 var once sync.Once
 
 func init() {
@@ -67,28 +85,403 @@ func ValidateRelayResponse(
 	relayResponseBz []byte,
 	publicKeyFetcher PublicKeyFetcher,
 ) (*servicetypes.RelayResponse, error) {
+	validator := RelayResponseValidator{PublicKeyFetcher: publicKeyFetcher}
+	validatedResponse, err := validator.Validate(ctx, supplierAddress, relayResponseBz)
+	return validatedResponse.RelayResponse, err
+}
+
+// ValidatedRelayResponse is a RelayResponse validated by a RelayResponseValidator.
+type ValidatedRelayResponse struct {
+	*servicetypes.RelayResponse
+
+	// SignatureVerified is false if the supplier's signature was not verified,
+	// i.e. if the validator runs in trusted mode.
+	SignatureVerified bool
+
+	// ValidateBasicErr is the error returned by the basic validation of the
+	// relay response, if the response was passed through degraded.
+	// See ValidateBasicPassDegraded.
+	ValidateBasicErr error
+}
+
+// Degraded returns true if the relay response failed basic validation, but was
+// passed through as allowed by ValidateBasicPassDegraded.
+func (r ValidatedRelayResponse) Degraded() bool {
+	return r.ValidateBasicErr != nil
+}
+
+// degradedResponseWarning is the Warning header value added to degraded relay responses.
+const degradedResponseWarning = `199 - "relay response failed basic validation"`
+
+// AddDegradedWarning adds a Warning header to the given headers if the relay
+// response is degraded, so the clients can tell it did not pass full validation.
+// It does nothing otherwise.
+func (r ValidatedRelayResponse) AddDegradedWarning(header http.Header) {
+	if r.Degraded() {
+		header.Add("Warning", degradedResponseWarning)
+	}
+}
+
+// ValidateBasicPolicy specifies how a RelayResponseValidator handles the relay
+// responses failing basic validation, e.g. with a malformed session header,
+// but which may still be usable.
+type ValidateBasicPolicy int
+
+const (
+	// ValidateBasicFail rejects the relay responses failing basic validation.
+	// It is the default policy.
+	ValidateBasicFail ValidateBasicPolicy = iota
+	// ValidateBasicPassDegraded passes through the relay responses failing basic
+	// validation as long as the supplier's signature is verified. They are
+	// flagged as degraded, e.g. to add a warning header to the client's response
+	// using AddDegradedWarning.
+	// It requires the suppliers' signatures to be verified: a validator trusting
+	// the suppliers fails with ErrNotConfigured.
+	ValidateBasicPassDegraded
+)
+
+// RelayResponseValidator validates RelayResponses and verifies the supplier's signature.
+type RelayResponseValidator struct {
+	PublicKeyFetcher PublicKeyFetcher
+
+	// TrustSuppliers, if set, skips the verification of the suppliers' signatures,
+	// saving a public key fetch and a signature verification per relay.
+	//
+	// WARNING: responses are accepted from any party able to reach the gateway.
+	// Only enable it for benchmarking, or when the gateway's operator also runs
+	// all the suppliers it relays to. It is disabled by default.
+	TrustSuppliers bool
+
+	// VerificationParallelism is the maximum number of relay responses validated
+	// in parallel by ValidateBatch. It defaults to GOMAXPROCS.
+	VerificationParallelism int
+
+	// ValidateBasicPolicy specifies how the relay responses failing basic
+	// validation are handled. They are rejected by default.
+	ValidateBasicPolicy ValidateBasicPolicy
+
+	// Hasher, if set, hashes the signable bytes of the relay responses whose
+	// signature is verified. The DefaultSignableBytesHasher is used otherwise.
+	Hasher SignableBytesHasher
+}
+
+// Validate validates the serialized RelayResponse, and verifies the signature of
+// the given supplier unless the validator runs in trusted mode.
+//
+// A relay response failing basic validation fails with an error matching both
+// sdkerrors.ErrRelayResponseBasicValidation and sdkerrors.ErrInvalidRelayResponse,
+// unless the ValidateBasicPolicy passes it through degraded.
+func (v RelayResponseValidator) Validate(
+	ctx context.Context,
+	supplierAddress SupplierAddress,
+	relayResponseBz []byte,
+) (ValidatedRelayResponse, error) {
+	// A degraded relay response is only usable if its signature is verified,
+	// which is never the case in trusted mode.
+	if v.TrustSuppliers && v.ValidateBasicPolicy == ValidateBasicPassDegraded {
+		return ValidatedRelayResponse{}, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Validate: ValidateBasicPassDegraded can not be used with TrustSuppliers")
+	}
+
 	relayResponse := &servicetypes.RelayResponse{}
 	if err := relayResponse.Unmarshal(relayResponseBz); err != nil {
-		return nil, err
+		return ValidatedRelayResponse{}, fmt.Errorf("%w: %w", sdkerrors.ErrInvalidRelayResponse, err)
 	}
 
+	validatedResponse := ValidatedRelayResponse{RelayResponse: relayResponse}
 	if err := relayResponse.ValidateBasic(); err != nil {
-		// Even if the relay response is invalid, we still return it to the caller
-		// as it might contain the reason why it's failing basic validation.
-		return relayResponse, err
+		basicErr := fmt.Errorf("%w: %w: %w", sdkerrors.ErrRelayResponseBasicValidation, sdkerrors.ErrInvalidRelayResponse, err)
+		if v.ValidateBasicPolicy != ValidateBasicPassDegraded {
+			// Even if the relay response is invalid, we still return it to the caller
+			// as it might contain the reason why it's failing basic validation.
+			return validatedResponse, basicErr
+		}
+		validatedResponse.ValidateBasicErr = basicErr
+	}
+
+	if v.TrustSuppliers {
+		return validatedResponse, nil
+	}
+
+	if v.PublicKeyFetcher == nil {
+		return ValidatedRelayResponse{}, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Validate: PublicKeyFetcher not set")
 	}
 
-	supplierPubKey, err := publicKeyFetcher.GetPubKeyFromAddress(
+	supplierPubKey, err := v.PublicKeyFetcher.GetPubKeyFromAddress(
 		ctx,
 		string(supplierAddress),
 	)
+	if err != nil {
+		return ValidatedRelayResponse{}, err
+	}
+
+	signableBz, err := signableBytesHasherOrDefault(v.Hasher).RelayResponseHash(relayResponse)
+	if err != nil {
+		return ValidatedRelayResponse{}, fmt.Errorf("%w: error hashing the signable bytes: %w", sdkerrors.ErrInvalidSupplierSignature, err)
+	}
+	if !supplierPubKey.VerifySignature(signableBz[:], relayResponse.GetMeta().SupplierOperatorSignature) {
+		return ValidatedRelayResponse{}, fmt.Errorf("%w: supplier %s", sdkerrors.ErrInvalidSupplierSignature, supplierAddress)
+	}
+
+	validatedResponse.SignatureVerified = true
+	return validatedResponse, nil
+}
+
+// RelaySender sends the given signed relay request to the given endpoint and
+// returns the serialized relay response.
+type RelaySender func(
+	ctx context.Context,
+	endpoint Endpoint,
+	relayRequest *servicetypes.RelayRequest,
+) (relayResponseBz []byte, err error)
+
+// RelaySenderOption is a functional option used to configure the RelaySender
+// built by NewHTTPRelaySender.
+type RelaySenderOption func(*relaySenderConfig)
+
+// relaySenderConfig holds the settings applied by RelaySenderOptions.
+type relaySenderConfig struct {
+	latencyTracker  *RelayLatencyTracker
+	identification  RelayIdentification
+	timeout         time.Duration
+	retryConfig     *retry.Config
+	maxResponseSize int64
+}
+
+// WithRelayLatencyTracker sets the RelayLatencyTracker recording the latency of
+// the successful relays, and providing the endpoint's recent P95 latency to
+// the RelayTimeoutErrors.
+// The same tracker can be shared by several RelaySenders.
+func WithRelayLatencyTracker(tracker *RelayLatencyTracker) RelaySenderOption {
+	return func(c *relaySenderConfig) {
+		c.latencyTracker = tracker
+	}
+}
+
+// WithRelayIdentification sets how the gateway identifies itself to the
+// suppliers, e.g. to set its moniker, or to opt out of the identification.
+// By default, the relays carry the DefaultUserAgent and the SDK version.
+func WithRelayIdentification(identification RelayIdentification) RelaySenderOption {
+	return func(c *relaySenderConfig) {
+		c.identification = identification
+	}
+}
+
+// WithRelayTimeout sets the maximum duration of each attempt to send a relay,
+// including reading the response, in addition to the deadline of the relay's
+// context and to the timeout of the HTTP client.
+func WithRelayTimeout(timeout time.Duration) RelaySenderOption {
+	return func(c *relaySenderConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithRelayRetry sets how the relays which fail to be sent are retried to the
+// same endpoint, e.g. using a retry.Exponential policy.
+// If the config's ShouldRetry is not set, only the relays which were not sent
+// are retried, as reported by IsTransientRelayError. Retrying the relays whose
+// connection dropped after they were sent, which the supplier may have served,
+// is opt-in, using retry.Any(IsTransientRelayError, IsDroppedRelayError).
+// The relays are not retried by default.
+func WithRelayRetry(retryConfig retry.Config) RelaySenderOption {
+	return func(c *relaySenderConfig) {
+		c.retryConfig = &retryConfig
+	}
+}
+
+// WithMaxRelayResponseSize sets the maximum size, in bytes, of the relay
+// responses: larger responses fail with an error wrapping
+// ErrRelayResponseTooLarge, without being read entirely.
+// The size of the relay responses is not limited by default.
+func WithMaxRelayResponseSize(maxBytes int64) RelaySenderOption {
+	return func(c *relaySenderConfig) {
+		c.maxResponseSize = maxBytes
+	}
+}
+
+// IsTransientRelayError returns true if the given error, returned by a
+// RelaySender, is likely transient and the relay was not sent, i.e. the
+// connection to the supplier could not be established, so the relay can be
+// sent again without the supplier serving it twice.
+// Timeouts are not transient, since the time budget of the relay is spent.
+func IsTransientRelayError(err error) bool {
+	if err == nil || isTimeoutError(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, errRelayNotSent) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// IsDroppedRelayError returns true if the given error, returned by a
+// RelaySender, reports that the connection to the supplier was dropped, e.g.
+// reset by a restarting supplier, after the relay may have been sent.
+// The supplier may have served such a relay, so sending it again may consume
+// the application's stake twice.
+func IsDroppedRelayError(err error) bool {
+	if err == nil || isTimeoutError(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// NewHTTPRelaySender returns a RelaySender which sends relay requests through
+// HTTP POST requests using the given HTTP client, or http.DefaultClient if nil.
+//
+// The relays are sent to the URL returned by the endpoint, which may have been
+// overridden using WithEndpointURL, and include the endpoint's authentication
+// headers, if any, and the identification headers. See WithRelayIdentification.
+//
+// Relays which time out fail with a *RelayTimeoutError, describing how the time
+// budget of the relay was consumed.
+//
+// The HTTP client's transport pools the connections to each supplier endpoint:
+// an HTTP client built by NewHTTPClientFromConfig can be used to tune the
+// pooling, the timeouts and the TLS validation of the endpoints.
+func NewHTTPRelaySender(httpClient *http.Client, opts ...RelaySenderOption) RelaySender {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	config := relaySenderConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	send := func(
+		ctx context.Context,
+		endpoint Endpoint,
+		relayRequest *servicetypes.RelayRequest,
+	) ([]byte, error) {
+		if config.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, config.timeout)
+			defer cancel()
+		}
+
+		budget := relayBudget(ctx, httpClient)
+		tracer, ctx := newRelayPhaseTracer(ctx)
+
+		httpRequest, err := newRelayHTTPRequest(ctx, endpoint, relayRequest, config.identification)
+		if err != nil {
+			return nil, fmt.Errorf("SendRelay: %w", err)
+		}
+
+		httpResponse, err := httpClient.Do(httpRequest)
+		if err != nil {
+			if isTimeoutError(err) {
+				err = newRelayTimeoutError(endpoint, tracer, budget, config.latencyTracker, err)
+			} else if !tracer.connected() {
+				err = fmt.Errorf("%w: %w", errRelayNotSent, err)
+			}
+			return nil, fmt.Errorf("SendRelay: error sending relay to supplier %s: %w", endpoint.Supplier(), err)
+		}
+		defer httpResponse.Body.Close()
+
+		relayResponseBz, err := readRelayResponse(httpResponse, config.maxResponseSize)
+		if err != nil {
+			if isTimeoutError(err) {
+				err = newRelayTimeoutError(endpoint, tracer, budget, config.latencyTracker, err)
+			}
+			return nil, fmt.Errorf("SendRelay: error reading relay response of supplier %s: %w", endpoint.Supplier(), err)
+		}
+
+		if config.latencyTracker != nil {
+			config.latencyTracker.Observe(endpoint, tracer.elapsed())
+		}
+		return relayResponseBz, nil
+	}
+
+	if config.retryConfig == nil {
+		return send
+	}
+
+	retryConfig := *config.retryConfig
+	if retryConfig.ShouldRetry == nil {
+		retryConfig.ShouldRetry = IsTransientRelayError
+	}
+	return func(
+		ctx context.Context,
+		endpoint Endpoint,
+		relayRequest *servicetypes.RelayRequest,
+	) ([]byte, error) {
+		var relayResponseBz []byte
+		err := retry.Do(ctx, retryConfig, func(ctx context.Context) error {
+			var err error
+			relayResponseBz, err = send(ctx, endpoint, relayRequest)
+			return err
+		})
+		return relayResponseBz, err
+	}
+}
+
+// readRelayResponse reads the body of the given relay response, failing with an
+// error wrapping ErrRelayResponseTooLarge if it exceeds the given maximum size,
+// if positive.
+func readRelayResponse(httpResponse *http.Response, maxResponseSize int64) ([]byte, error) {
+	if maxResponseSize <= 0 {
+		return io.ReadAll(httpResponse.Body)
+	}
+
+	if httpResponse.ContentLength > maxResponseSize {
+		return nil, fmt.Errorf(
+			"%w: %d bytes exceeds the %d bytes limit",
+			ErrRelayResponseTooLarge,
+			httpResponse.ContentLength,
+			maxResponseSize,
+		)
+	}
+
+	relayResponseBz, err := io.ReadAll(io.LimitReader(httpResponse.Body, maxResponseSize+1))
 	if err != nil {
 		return nil, err
 	}
+	if int64(len(relayResponseBz)) > maxResponseSize {
+		return nil, fmt.Errorf("%w: exceeds the %d bytes limit", ErrRelayResponseTooLarge, maxResponseSize)
+	}
+	return relayResponseBz, nil
+}
+
+// newRelayHTTPRequest returns the HTTP POST request delivering the given relay
+// request to the URL of the given endpoint, including the endpoint's
+// authentication headers, if any, and the given identification headers.
+func newRelayHTTPRequest(
+	ctx context.Context,
+	endpoint Endpoint,
+	relayRequest *servicetypes.RelayRequest,
+	identification RelayIdentification,
+) (*http.Request, error) {
+	relayRequestBz, err := relayRequest.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling relay request: %w", err)
+	}
+
+	typed := GetTypedEndpoint(endpoint)
+	if !typed.Valid() {
+		return nil, typed.Err
+	}
+
+	// The request is built without a URL, to reuse the endpoint's URL parsed when
+	// the session was loaded.
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, "", bytes.NewReader(relayRequestBz))
+	if err != nil {
+		return nil, fmt.Errorf("error building HTTP request: %w", err)
+	}
+	endpointURL := *typed.URL
+	httpRequest.URL = &endpointURL
+	httpRequest.Host = endpointURL.Host
 
-	if signatureErr := relayResponse.VerifySupplierOperatorSignature(supplierPubKey); signatureErr != nil {
-		return nil, signatureErr
+	for key, values := range GetEndpointAuthHeaders(endpoint) {
+		for _, value := range values {
+			httpRequest.Header.Add(key, value)
+		}
 	}
+	identification.apply(httpRequest.Header)
 
-	return relayResponse, nil
+	return httpRequest, nil
 }
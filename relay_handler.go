@@ -0,0 +1,205 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+const (
+	// HeaderAppAddress is the header of the client requests read by the default
+	// RelayRouter, carrying the address of the application to relay for.
+	HeaderAppAddress = "App-Address"
+	// HeaderTargetServiceId is the header of the client requests read by the
+	// default RelayRouter, carrying the id of the service to relay to.
+	HeaderTargetServiceId = "Target-Service-Id"
+
+	// defaultMaxRelayRequestBodySize is the maximum size of the client requests'
+	// body accepted by the RelayHandler, if none is specified.
+	defaultMaxRelayRequestBodySize = 10 << 20
+)
+
+// RelayRouter returns the address of the application and the id of the service
+// a client request is relayed for, e.g. read from its headers or path.
+type RelayRouter func(r *http.Request) (appAddress, serviceId string, err error)
+
+// HeaderRelayRouter returns a RelayRouter reading the application address and
+// the service id from the given headers of the client requests.
+func HeaderRelayRouter(appAddressHeader, serviceIdHeader string) RelayRouter {
+	return func(r *http.Request) (string, string, error) {
+		appAddress, serviceId := r.Header.Get(appAddressHeader), r.Header.Get(serviceIdHeader)
+		if appAddress == "" || serviceId == "" {
+			return "", "", fmt.Errorf("missing %s or %s header", appAddressHeader, serviceIdHeader)
+		}
+		return appAddress, serviceId, nil
+	}
+}
+
+// RelayHandlerOption is a functional option used to configure the http.Handler
+// built by NewRelayHandler.
+type RelayHandlerOption func(*relayHandler)
+
+// WithRelayRouter sets the RelayRouter selecting the application and the service
+// of each client request.
+// It defaults to reading the HeaderAppAddress and HeaderTargetServiceId headers.
+func WithRelayRouter(router RelayRouter) RelayHandlerOption {
+	return func(h *relayHandler) {
+		h.router = router
+	}
+}
+
+// WithRelayForwardingPolicy sets how the forwarding headers of the client
+// requests, e.g. X-Forwarded-For, are relayed to the suppliers.
+// They are passed through by default.
+func WithRelayForwardingPolicy(policy types.ForwardingPolicy) RelayHandlerOption {
+	return func(h *relayHandler) {
+		h.forwardingPolicy = policy
+	}
+}
+
+// WithRelayErrorFormatter sets the ErrorFormatter formatting the errors returned
+// to the clients, matching the RPC type of their requests.
+// The default error messages are used otherwise.
+func WithRelayErrorFormatter(errorFormatter *types.ErrorFormatter) RelayHandlerOption {
+	return func(h *relayHandler) {
+		h.errorFormatter = errorFormatter
+	}
+}
+
+// WithMaxRequestBodySize sets the maximum size, in bytes, of the client
+// requests' body. Larger requests are rejected with a 413 status code.
+// It defaults to 10 MiB.
+func WithMaxRequestBodySize(maxBytes int64) RelayHandlerOption {
+	return func(h *relayHandler) {
+		h.maxRequestBodySize = maxBytes
+	}
+}
+
+// NewRelayHandler returns an http.Handler relaying the client requests through
+// the given GatewayClient, configured using the given options.
+//
+// The handler can be mounted on any net/http compatible router or middleware
+// stack, e.g. http.ServeMux, chi or echo, so a minimal gateway is a matter of
+// configuring a GatewayClient and serving the handler:
+//
+//	http.ListenAndServe(":3000", sdk.NewRelayHandler(gatewayClient))
+//
+// The suppliers' responses are written as-is. Failed relays are answered with
+// an error response matching the RPC type of the request, e.g. a JSON-RPC error.
+func NewRelayHandler(gatewayClient *GatewayClient, opts ...RelayHandlerOption) http.Handler {
+	h := &relayHandler{
+		gatewayClient:      gatewayClient,
+		router:             HeaderRelayRouter(HeaderAppAddress, HeaderTargetServiceId),
+		maxRequestBodySize: defaultMaxRelayRequestBodySize,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// relayHandler is the http.Handler built by NewRelayHandler.
+type relayHandler struct {
+	gatewayClient      *GatewayClient
+	router             RelayRouter
+	forwardingPolicy   types.ForwardingPolicy
+	errorFormatter     *types.ErrorFormatter
+	maxRequestBodySize int64
+}
+
+// ServeHTTP relays the given client request, and writes the supplier's response.
+// The TTL of the relay, if any, is measured from the reception of the request.
+func (h *relayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	relayCtx := ContextWithRelayStart(r.Context(), time.Now())
+
+	if h.maxRequestBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxRequestBodySize)
+	}
+
+	poktRequest, requestBz, err := types.SerializeHTTPRequestWithForwardingPolicy(r, h.forwardingPolicy)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if maxBytesErr := new(http.MaxBytesError); errors.As(err, &maxBytesErr) {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, fmt.Sprintf("error reading the request: %v", err), statusCode)
+		return
+	}
+
+	appAddress, serviceId, err := h.router(r)
+	if err != nil {
+		h.writeError(w, poktRequest, http.StatusBadRequest, err)
+		return
+	}
+
+	if h.gatewayClient == nil {
+		err := sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "ServeHTTP: GatewayClient not set")
+		h.writeError(w, poktRequest, http.StatusInternalServerError, err)
+		return
+	}
+
+	poktResponse, err := h.gatewayClient.Relay(relayCtx, appAddress, serviceId, requestBz)
+	if err != nil {
+		h.writeError(w, poktRequest, relayErrorStatusCode(r.Context(), err), err)
+		return
+	}
+
+	writePOKTHTTPResponse(w, poktResponse)
+}
+
+// writeError writes the error response, matching the RPC type of the given
+// request, with the given status code, or the status code required by the
+// protocol of the requests to gRPC services.
+// The message of the server errors is not exposed to the client.
+func (h *relayHandler) writeError(w http.ResponseWriter, poktRequest *types.POKTHTTPRequest, statusCode int, err error) {
+	var errorResponse *types.POKTHTTPResponse
+	if h.errorFormatter != nil {
+		errorResponse, _ = h.errorFormatter.FormatErrorWithStatus(poktRequest, err, statusCode)
+	} else {
+		errorResponse, _ = poktRequest.FormatErrorWithStatus(err, statusCode)
+	}
+	writePOKTHTTPResponse(w, errorResponse)
+}
+
+// relayErrorStatusCode returns the status code of the response to a failed relay.
+func relayErrorStatusCode(ctx context.Context, err error) int {
+	category, _ := sdkerrors.CategoryOf(err)
+	switch {
+	case errors.Is(err, ErrRelayLoadShed), errors.Is(err, ErrNodeRateLimited):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, sdkerrors.ErrRelayTimeout), errors.Is(err, ErrRelayExpired),
+		errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case ctx.Err() != nil:
+		// The client went away: the status code is only logged by the server.
+		return http.StatusRequestTimeout
+	case category == sdkerrors.CategoryRequest:
+		return http.StatusBadRequest
+	case category == sdkerrors.CategoryConfig:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// writePOKTHTTPResponse writes the given POKTHTTPResponse to the client.
+func writePOKTHTTPResponse(w http.ResponseWriter, poktResponse *types.POKTHTTPResponse) {
+	header := make(http.Header, len(poktResponse.GetHeader()))
+	for key, values := range poktResponse.GetHeader() {
+		header[key] = values.GetValues()
+	}
+	copyStreamHeaders(w.Header(), header)
+
+	statusCode := int(poktResponse.GetStatusCode())
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(poktResponse.GetBodyBz())
+}
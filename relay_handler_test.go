@@ -0,0 +1,135 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestRelayHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        http.Handler
+		header         http.Header
+		body           string
+		wantStatusCode int
+		wantBody       string
+		wantHeader     http.Header
+	}{
+		{
+			name:           "missing routing headers",
+			handler:        NewRelayHandler(&GatewayClient{}),
+			body:           `{"jsonrpc":"2.0","id":7,"method":"eth_blockNumber"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       `"id":7`,
+		},
+		{
+			name: "custom router",
+			handler: NewRelayHandler(&GatewayClient{}, WithRelayRouter(func(*http.Request) (string, string, error) {
+				return "", "", errors.New("unknown service")
+			})),
+			header:         http.Header{HeaderAppAddress: {"pokt1app"}, HeaderTargetServiceId: {"anvil"}},
+			body:           `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "unknown service",
+		},
+		{
+			name:           "unconfigured GatewayClient",
+			handler:        NewRelayHandler(&GatewayClient{}),
+			header:         http.Header{HeaderAppAddress: {"pokt1app"}, HeaderTargetServiceId: {"anvil"}},
+			body:           `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`,
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `"jsonrpc":"2.0"`,
+		},
+		{
+			name:           "request body too large",
+			handler:        NewRelayHandler(&GatewayClient{}, WithMaxRequestBodySize(8)),
+			header:         http.Header{HeaderAppAddress: {"pokt1app"}, HeaderTargetServiceId: {"anvil"}},
+			body:           `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`,
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "gRPC request",
+			handler: NewRelayHandler(&GatewayClient{}),
+			header: http.Header{
+				HeaderAppAddress:      {"pokt1app"},
+				HeaderTargetServiceId: {"cosmos"},
+				"Content-Type":        {"application/grpc"},
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeader:     http.Header{"Grpc-Status": {"13"}, "Grpc-Message": {"Internal error"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+			for key, values := range test.header {
+				req.Header[key] = values
+			}
+
+			recorder := httptest.NewRecorder()
+			test.handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, test.wantStatusCode, recorder.Code)
+			require.Contains(t, recorder.Body.String(), test.wantBody)
+			for key, values := range test.wantHeader {
+				require.Equal(t, values, recorder.Header().Values(key))
+			}
+		})
+	}
+}
+
+func TestRelayErrorStatusCode(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		err            error
+		wantStatusCode int
+	}{
+		{"load shed", context.Background(), fmt.Errorf("Relay: %w", ErrRelayLoadShed), http.StatusServiceUnavailable},
+		{"full node rate limited", context.Background(), fmt.Errorf("Relay: %w", ErrNodeRateLimited), http.StatusServiceUnavailable},
+		{"timeout", context.Background(), fmt.Errorf("Relay: %w", sdkerrors.ErrRelayTimeout), http.StatusGatewayTimeout},
+		{"relay TTL expired", context.Background(), fmt.Errorf("Relay: %w", &RelayExpiredError{}), http.StatusGatewayTimeout},
+		{"client gone", canceledCtx, fmt.Errorf("Relay: %w", context.Canceled), http.StatusRequestTimeout},
+		{"bad request", context.Background(), sdkerrors.ErrJSONRPCMethodNotAllowed, http.StatusBadRequest},
+		{"not configured", context.Background(), sdkerrors.ErrNotConfigured, http.StatusInternalServerError},
+		{"supplier error", context.Background(), errors.New("connection refused"), http.StatusBadGateway},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.wantStatusCode, relayErrorStatusCode(test.ctx, test.err))
+		})
+	}
+}
+
+func TestWritePOKTHTTPResponse(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	writePOKTHTTPResponse(recorder, &types.POKTHTTPResponse{
+		StatusCode: http.StatusCreated,
+		Header: map[string]*types.Header{
+			"Content-Type":   {Key: "Content-Type", Values: []string{"application/json"}},
+			"Content-Length": {Key: "Content-Length", Values: []string{"999"}},
+		},
+		BodyBz: []byte(`{"result":"0x1"}`),
+	})
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	require.Empty(t, recorder.Header().Get("Content-Length"))
+	require.Equal(t, `{"result":"0x1"}`, recorder.Body.String())
+}
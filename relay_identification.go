@@ -0,0 +1,70 @@
+package sdk
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// sdkUserAgentProduct is the product name of the SDK's default User-Agent.
+	sdkUserAgentProduct = "shannon-sdk"
+
+	// HeaderSDKVersion is the header carrying the version of the SDK which sent a relay.
+	HeaderSDKVersion = "X-Shannon-SDK-Version"
+	// HeaderGatewayMoniker is the header carrying the moniker of the gateway which
+	// sent a relay, if configured.
+	HeaderGatewayMoniker = "X-Gateway-Moniker"
+)
+
+// DefaultUserAgent returns the User-Agent sent with the relays if none is
+// configured, i.e. "shannon-sdk/<version> (commit <commit>; poktroll <version>)",
+// the commit being omitted if unknown.
+func DefaultUserAgent() string {
+	version := Version()
+	comments := []string{}
+	if version.GitCommit != "" {
+		comments = append(comments, "commit "+version.GitCommit)
+	}
+	comments = append(comments, "poktroll "+version.PoktrollVersion)
+	return sdkUserAgentProduct + "/" + version.Version + " (" + strings.Join(comments, "; ") + ")"
+}
+
+// RelayIdentification specifies how a gateway identifies itself to the suppliers
+// it sends relays to, e.g. so supplier operators can tell gateways apart, and
+// contact them when debugging their traffic.
+//
+// Its zero value sends the DefaultUserAgent and the HeaderSDKVersion header.
+type RelayIdentification struct {
+	// UserAgent, if set, overrides the DefaultUserAgent.
+	UserAgent string
+	// GatewayMoniker, if set, is sent in the HeaderGatewayMoniker header.
+	GatewayMoniker string
+	// Disabled, if set, opts out of the identification: no identification header
+	// is sent, and the HTTP client's default User-Agent is used.
+	Disabled bool
+}
+
+// apply sets the identification headers on the given headers, leaving the ones
+// already set, e.g. by the endpoint's authentication headers, unchanged.
+func (id RelayIdentification) apply(header http.Header) {
+	if id.Disabled {
+		return
+	}
+
+	userAgent := id.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
+	setHeaderIfUnset(header, "User-Agent", userAgent)
+	setHeaderIfUnset(header, HeaderSDKVersion, SDKVersion())
+	if id.GatewayMoniker != "" {
+		setHeaderIfUnset(header, HeaderGatewayMoniker, id.GatewayMoniker)
+	}
+}
+
+// setHeaderIfUnset sets the given header to the given value if it has no value yet.
+func setHeaderIfUnset(header http.Header, key, value string) {
+	if header.Get(key) == "" {
+		header.Set(key, value)
+	}
+}
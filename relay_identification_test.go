@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPRelaySender_Identification(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	endpoint := NewEndpoint(
+		sessiontypes.SessionHeader{ServiceId: "svc1"},
+		sharedtypes.SupplierEndpoint{Url: server.URL},
+		SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: "pokt1supplier"}},
+	)
+
+	tests := []struct {
+		name                   string
+		opts                   []RelaySenderOption
+		expectedUserAgent      string
+		expectedSDKVersion     string
+		expectedGatewayMoniker string
+	}{
+		{
+			name:               "default identification",
+			expectedUserAgent:  DefaultUserAgent(),
+			expectedSDKVersion: SDKVersion(),
+		},
+		{
+			name: "custom user agent and moniker",
+			opts: []RelaySenderOption{WithRelayIdentification(RelayIdentification{
+				UserAgent:      "my-gateway/1.0",
+				GatewayMoniker: "my-gateway",
+			})},
+			expectedUserAgent:      "my-gateway/1.0",
+			expectedSDKVersion:     SDKVersion(),
+			expectedGatewayMoniker: "my-gateway",
+		},
+		{
+			name: "opt-out",
+			opts: []RelaySenderOption{WithRelayIdentification(RelayIdentification{
+				GatewayMoniker: "my-gateway",
+				Disabled:       true,
+			})},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sender := NewHTTPRelaySender(server.Client(), test.opts...)
+			_, err := sender(context.Background(), endpoint, &servicetypes.RelayRequest{})
+			require.NoError(t, err)
+
+			if test.expectedUserAgent == "" {
+				// The HTTP client's default User-Agent is sent.
+				require.False(t, strings.HasPrefix(received.Get("User-Agent"), sdkUserAgentProduct))
+			} else {
+				require.Equal(t, test.expectedUserAgent, received.Get("User-Agent"))
+			}
+			require.Equal(t, test.expectedSDKVersion, received.Get(HeaderSDKVersion))
+			require.Equal(t, test.expectedGatewayMoniker, received.Get(HeaderGatewayMoniker))
+		})
+	}
+}
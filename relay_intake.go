@@ -0,0 +1,302 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultRelayStarvationAge is the duration after which a queued relay is
+// admitted ahead of the relays of higher priority classes, if none is specified.
+const defaultRelayStarvationAge = time.Second
+
+// RelayPriorityClass is a class of relays sharing the capacity of a RelayIntake,
+// e.g. the relays of the paid tier or of the free tier.
+type RelayPriorityClass struct {
+	// Name identifies the class, e.g. "paid".
+	Name string
+	// Share is the relative share of the intake's concurrency granted to the
+	// class when several classes compete for it, e.g. 3 for the paid tier and
+	// 1 for the free tier. It must be positive.
+	// A class can use all the concurrency if no other class has queued relays.
+	Share int
+	// MaxQueueLength, if positive, is the maximum number of queued relays of the
+	// class. The relays exceeding it are rejected.
+	MaxQueueLength int
+}
+
+// RelayIntakeConfig specifies the capacity of a RelayIntake, and how it is
+// shared between the priority classes.
+type RelayIntakeConfig struct {
+	// MaxConcurrentRelays is the maximum number of relays admitted at the same
+	// time, across all classes. It must be positive.
+	MaxConcurrentRelays int
+	// Classes are the priority classes, in decreasing priority: the higher
+	// priority classes are admitted first among the classes using the same
+	// proportion of their share.
+	Classes []RelayPriorityClass
+	// DefaultClass is the class of the relays without one. It defaults to the
+	// last, i.e. the lowest priority, class.
+	DefaultClass string
+	// MaxQueueTime, if positive, is the maximum duration a relay may be queued
+	// before being rejected. Relays are queued until their context is done otherwise.
+	MaxQueueTime time.Duration
+	// StarvationAge is the duration after which a queued relay is admitted
+	// ahead of the relays of the other classes, oldest first, so the lower
+	// priority classes always make progress. It defaults to 1 second.
+	// A negative value disables the starvation protection.
+	StarvationAge time.Duration
+}
+
+// relayPriorityKey is the context key of the priority class of a relay.
+type relayPriorityKey struct{}
+
+// ContextWithRelayPriority returns a copy of the given context carrying the
+// given priority class of a relay, e.g. set by the gateway from the end client's
+// tier, and read by the RelayIntake of the GatewayClient.
+func ContextWithRelayPriority(ctx context.Context, class string) context.Context {
+	return context.WithValue(ctx, relayPriorityKey{}, class)
+}
+
+// RelayPriorityFromContext returns the priority class of the relay carried by
+// the given context, or an empty string if it carries none.
+func RelayPriorityFromContext(ctx context.Context) string {
+	class, _ := ctx.Value(relayPriorityKey{}).(string)
+	return class
+}
+
+// RelayIntake queues the relays ahead of the signing and sending pipeline,
+// admitting them according to their priority class, so gateways can enforce
+// tiered QoS where the relay capacity is consumed.
+//
+// When relays are queued, a released slot is granted to the class using the
+// lowest proportion of its share, ties being broken by priority, so the
+// concurrency is split between the busy classes according to their shares.
+// Relays queued for longer than the StarvationAge are admitted first.
+// Within a class, relays are admitted in order.
+//
+// A RelayIntake is safe for concurrent use.
+type RelayIntake struct {
+	config       RelayIntakeConfig
+	defaultClass *intakeClass
+
+	mu       sync.Mutex
+	classes  map[string]*intakeClass
+	ordered  []*intakeClass
+	inFlight int
+}
+
+// intakeClass holds the state of a priority class of a RelayIntake.
+type intakeClass struct {
+	RelayPriorityClass
+	inFlight int
+	queue    []*intakeWaiter
+}
+
+// intakeWaiter is a relay queued by a RelayIntake.
+type intakeWaiter struct {
+	queuedAt time.Time
+	admitted chan struct{}
+}
+
+// NewRelayIntake returns a RelayIntake sharing the given capacity between the
+// given priority classes.
+func NewRelayIntake(config RelayIntakeConfig) (*RelayIntake, error) {
+	if config.MaxConcurrentRelays <= 0 {
+		return nil, errors.New("NewRelayIntake: MaxConcurrentRelays must be positive")
+	}
+	if len(config.Classes) == 0 {
+		return nil, errors.New("NewRelayIntake: at least one priority class is required")
+	}
+	if config.StarvationAge == 0 {
+		config.StarvationAge = defaultRelayStarvationAge
+	}
+
+	intake := &RelayIntake{
+		config:  config,
+		classes: make(map[string]*intakeClass, len(config.Classes)),
+	}
+	for _, class := range config.Classes {
+		if class.Share <= 0 {
+			return nil, fmt.Errorf("NewRelayIntake: share of class %q must be positive", class.Name)
+		}
+		if _, ok := intake.classes[class.Name]; ok {
+			return nil, fmt.Errorf("NewRelayIntake: duplicate class %q", class.Name)
+		}
+
+		intakeClass := &intakeClass{RelayPriorityClass: class}
+		intake.classes[class.Name] = intakeClass
+		intake.ordered = append(intake.ordered, intakeClass)
+	}
+
+	intake.defaultClass = intake.ordered[len(intake.ordered)-1]
+	if config.DefaultClass != "" {
+		var ok bool
+		if intake.defaultClass, ok = intake.classes[config.DefaultClass]; !ok {
+			return nil, fmt.Errorf("NewRelayIntake: unknown default class %q", config.DefaultClass)
+		}
+	}
+
+	return intake, nil
+}
+
+// Acquire admits a relay of the given priority class, or of the DefaultClass
+// if empty, waiting for a slot if needed.
+//
+// It returns a function that must be called to release the slot once the relay
+// completes, or an error wrapping ErrRelayLoadShed if the relay was rejected
+// because its class' queue is full or its MaxQueueTime elapsed.
+func (in *RelayIntake) Acquire(ctx context.Context, className string) (release func(), err error) {
+	class := in.defaultClass
+	if className != "" {
+		var ok bool
+		if class, ok = in.classes[className]; !ok {
+			return nil, fmt.Errorf("Acquire: unknown priority class %q", className)
+		}
+	}
+	release = in.releaseFunc(class)
+
+	in.mu.Lock()
+	// Fast path: a slot is available, and no relay is queued ahead.
+	if in.inFlight < in.config.MaxConcurrentRelays && !in.hasQueued() {
+		in.admit(class)
+		in.mu.Unlock()
+		return release, nil
+	}
+	if class.MaxQueueLength > 0 && len(class.queue) >= class.MaxQueueLength {
+		in.mu.Unlock()
+		return nil, fmt.Errorf("%w: queue of priority class %s is full", ErrRelayLoadShed, class.Name)
+	}
+	waiter := &intakeWaiter{queuedAt: time.Now(), admitted: make(chan struct{})}
+	class.queue = append(class.queue, waiter)
+	in.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if in.config.MaxQueueTime > 0 {
+		timer := time.NewTimer(in.config.MaxQueueTime)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-waiter.admitted:
+		return release, nil
+	case <-timeout:
+		err = fmt.Errorf("%w: queue time of %s of priority class %s exceeded", ErrRelayLoadShed, in.config.MaxQueueTime, class.Name)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	select {
+	case <-waiter.admitted:
+		// The relay was admitted concurrently: its slot is granted to the next one.
+		in.releaseLocked(class)
+	default:
+		class.queue = slices.DeleteFunc(class.queue, func(w *intakeWaiter) bool { return w == waiter })
+	}
+	return nil, err
+}
+
+// InFlight returns the number of admitted relays of the given priority class.
+func (in *RelayIntake) InFlight(className string) int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if class, ok := in.classes[className]; ok {
+		return class.inFlight
+	}
+	return 0
+}
+
+// Queued returns the number of queued relays of the given priority class.
+func (in *RelayIntake) Queued(className string) int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if class, ok := in.classes[className]; ok {
+		return len(class.queue)
+	}
+	return 0
+}
+
+// releaseFunc returns the function releasing the slot of a relay of the given
+// class. Only its first call releases the slot.
+func (in *RelayIntake) releaseFunc(class *intakeClass) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			in.mu.Lock()
+			defer in.mu.Unlock()
+			in.releaseLocked(class)
+		})
+	}
+}
+
+// releaseLocked releases the slot of a relay of the given class, and admits the
+// next queued relays. The intake's lock must be held.
+func (in *RelayIntake) releaseLocked(class *intakeClass) {
+	class.inFlight--
+	in.inFlight--
+
+	for in.inFlight < in.config.MaxConcurrentRelays {
+		next := in.nextClass(time.Now())
+		if next == nil {
+			return
+		}
+
+		waiter := next.queue[0]
+		next.queue = next.queue[1:]
+		in.admit(next)
+		close(waiter.admitted)
+	}
+}
+
+// admit records the admission of a relay of the given class.
+// The intake's lock must be held.
+func (in *RelayIntake) admit(class *intakeClass) {
+	class.inFlight++
+	in.inFlight++
+}
+
+// hasQueued returns true if any relay is queued. The intake's lock must be held.
+func (in *RelayIntake) hasQueued() bool {
+	for _, class := range in.ordered {
+		if len(class.queue) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// nextClass returns the class of the next relay to admit, or nil if no relay is
+// queued. The intake's lock must be held.
+func (in *RelayIntake) nextClass(now time.Time) *intakeClass {
+	var starved, next *intakeClass
+	for _, class := range in.ordered {
+		if len(class.queue) == 0 {
+			continue
+		}
+
+		queuedAt := class.queue[0].queuedAt
+		if in.config.StarvationAge > 0 && now.Sub(queuedAt) >= in.config.StarvationAge &&
+			(starved == nil || queuedAt.Before(starved.queue[0].queuedAt)) {
+			starved = class
+		}
+
+		// The class using the lowest proportion of its share is admitted first,
+		// the classes being ordered by priority.
+		if next == nil || class.inFlight*next.Share < next.inFlight*class.Share {
+			next = class
+		}
+	}
+
+	if starved != nil {
+		return starved
+	}
+	return next
+}
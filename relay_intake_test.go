@@ -0,0 +1,126 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayIntake_Shares(t *testing.T) {
+	intake, err := NewRelayIntake(RelayIntakeConfig{
+		MaxConcurrentRelays: 4,
+		Classes:             []RelayPriorityClass{{Name: "paid", Share: 3}, {Name: "free", Share: 1}},
+		StarvationAge:       -1,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// The relays are admitted immediately while slots are available.
+	var releases []func()
+	for range 4 {
+		release, err := intake.Acquire(ctx, "")
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+	require.Equal(t, 4, intake.InFlight("free"))
+
+	// Once the intake is busy, the released slots are split between the
+	// classes according to their shares.
+	admitted := make(chan string, 16)
+	for range 4 {
+		for _, class := range []string{"paid", "free"} {
+			go func() {
+				if _, err := intake.Acquire(ctx, class); err == nil {
+					admitted <- class
+				}
+			}()
+		}
+	}
+	require.Eventually(t, func() bool {
+		return intake.Queued("paid") == 4 && intake.Queued("free") == 4
+	}, time.Second, time.Millisecond)
+
+	for _, release := range releases {
+		release()
+		// Releasing twice has no effect.
+		release()
+	}
+	for range 4 {
+		<-admitted
+	}
+	require.Equal(t, 3, intake.InFlight("paid"))
+	require.Equal(t, 1, intake.InFlight("free"))
+}
+
+func TestRelayIntake_StarvationProtection(t *testing.T) {
+	intake, err := NewRelayIntake(RelayIntakeConfig{
+		MaxConcurrentRelays: 1,
+		Classes:             []RelayPriorityClass{{Name: "paid", Share: 100}, {Name: "free", Share: 1}},
+		StarvationAge:       20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	release, err := intake.Acquire(ctx, "paid")
+	require.NoError(t, err)
+
+	admitted := make(chan string, 2)
+	acquire := func(class string) {
+		go func() {
+			if _, err := intake.Acquire(ctx, class); err == nil {
+				admitted <- class
+			}
+		}()
+	}
+
+	// The free relay queued for longer than the starvation age is admitted
+	// ahead of the paid relay.
+	acquire("free")
+	require.Eventually(t, func() bool { return intake.Queued("free") == 1 }, time.Second, time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	acquire("paid")
+	require.Eventually(t, func() bool { return intake.Queued("paid") == 1 }, time.Second, time.Millisecond)
+
+	release()
+	require.Equal(t, "free", <-admitted)
+}
+
+func TestRelayIntake_Rejections(t *testing.T) {
+	intake, err := NewRelayIntake(RelayIntakeConfig{
+		MaxConcurrentRelays: 1,
+		Classes:             []RelayPriorityClass{{Name: "free", Share: 1, MaxQueueLength: 1}},
+		MaxQueueTime:        20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	release, err := intake.Acquire(ctx, "free")
+	require.NoError(t, err)
+	defer release()
+
+	// The queued relays are rejected once the queue time elapses.
+	_, err = intake.Acquire(ctx, "free")
+	require.ErrorIs(t, err, ErrRelayLoadShed)
+	require.Zero(t, intake.Queued("free"))
+
+	// The relays exceeding the queue length are rejected immediately.
+	go func() { _, _ = intake.Acquire(ctx, "free") }()
+	require.Eventually(t, func() bool { return intake.Queued("free") == 1 }, time.Second, time.Millisecond)
+	_, err = intake.Acquire(ctx, "free")
+	require.ErrorIs(t, err, ErrRelayLoadShed)
+
+	// The queued relays are abandoned once their context is done.
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Eventually(t, func() bool { return intake.Queued("free") == 0 }, time.Second, time.Millisecond)
+	_, err = intake.Acquire(canceledCtx, "free")
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = intake.Acquire(ctx, "unknown")
+	require.Error(t, err)
+
+	_, err = NewRelayIntake(RelayIntakeConfig{MaxConcurrentRelays: 1, Classes: []RelayPriorityClass{{Name: "free"}}})
+	require.Error(t, err)
+}
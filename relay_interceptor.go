@@ -0,0 +1,37 @@
+package sdk
+
+import (
+	"context"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+)
+
+// RelayInvoker signs the given relay request, sends it to the given endpoint,
+// and returns the supplier's validated relay response.
+//
+// The application, service and session of the relay can be read from the
+// session header of the relay request's metadata.
+type RelayInvoker func(
+	ctx context.Context,
+	endpoint Endpoint,
+	relayRequest *servicetypes.RelayRequest,
+) (*servicetypes.RelayResponse, error)
+
+// RelayInterceptor wraps the RelayInvoker of the GatewayClient's relays, to
+// observe or mutate them without forking the SDK, e.g. to log or trace the
+// relays, to inject headers into the requests' payload before they are signed,
+// or to retry the relays using a custom policy by calling next several times.
+//
+// An interceptor must call next, unless it fails or answers the relay itself.
+type RelayInterceptor func(next RelayInvoker) RelayInvoker
+
+// ChainRelayInterceptors returns a RelayInterceptor applying the given
+// interceptors in order, i.e. the first one is the outermost.
+func ChainRelayInterceptors(interceptors ...RelayInterceptor) RelayInterceptor {
+	return func(next RelayInvoker) RelayInvoker {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			next = interceptors[i](next)
+		}
+		return next
+	}
+}
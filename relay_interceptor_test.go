@@ -0,0 +1,44 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainRelayInterceptors(t *testing.T) {
+	var calls []string
+	newInterceptor := func(name string) RelayInterceptor {
+		return func(next RelayInvoker) RelayInvoker {
+			return func(
+				ctx context.Context,
+				endpoint Endpoint,
+				relayRequest *servicetypes.RelayRequest,
+			) (*servicetypes.RelayResponse, error) {
+				calls = append(calls, name+" before")
+				relayRequest.Payload = append(relayRequest.Payload, name...)
+				relayResponse, err := next(ctx, endpoint, relayRequest)
+				calls = append(calls, name+" after")
+				return relayResponse, err
+			}
+		}
+	}
+
+	invoke := ChainRelayInterceptors(newInterceptor("first"), newInterceptor("second"))(
+		func(
+			_ context.Context,
+			_ Endpoint,
+			relayRequest *servicetypes.RelayRequest,
+		) (*servicetypes.RelayResponse, error) {
+			calls = append(calls, "invoke")
+			return &servicetypes.RelayResponse{Payload: relayRequest.Payload}, nil
+		},
+	)
+
+	relayResponse, err := invoke(context.Background(), nil, &servicetypes.RelayRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "firstsecond", string(relayResponse.Payload))
+	require.Equal(t, []string{"first before", "second before", "invoke", "second after", "first after"}, calls)
+}
@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// RelayLogger is the logger of the relays sent by a GatewayClient, whose level
+// and debug sampling rate can be adjusted at runtime, e.g. from an admin
+// endpoint, to turn up the verbosity during an incident without redeploying.
+//
+// The debug records are only logged for a sample of the relays: once the level
+// is set to slog.LevelDebug, each relay is logged at debug with a probability of
+// the debug sample rate, e.g. 0.01 to log 1% of the relays.
+//
+// A RelayLogger is safe for concurrent use.
+type RelayLogger struct {
+	handler slog.Handler
+
+	level slog.LevelVar
+	// debugSampleRate holds the bits of the float64 debug sample rate.
+	debugSampleRate atomic.Uint64
+}
+
+// NewRelayLogger returns a RelayLogger writing the records to the given handler,
+// at slog.LevelInfo, and logging all the relays at debug once the level is set
+// to slog.LevelDebug.
+// The given handler should accept all the levels, e.g. be created with a level
+// of slog.LevelDebug, since the RelayLogger filters the records itself.
+func NewRelayLogger(handler slog.Handler) *RelayLogger {
+	l := &RelayLogger{handler: handler}
+	l.debugSampleRate.Store(math.Float64bits(1))
+	return l
+}
+
+// SetLevel sets the minimum level of the logged records.
+func (l *RelayLogger) SetLevel(level slog.Level) {
+	l.level.Set(level)
+}
+
+// Level returns the minimum level of the logged records.
+func (l *RelayLogger) Level() slog.Level {
+	return l.level.Level()
+}
+
+// SetDebugSampleRate sets the share of the relays logged at debug, between 0
+// and 1. Rates outside this range are clamped.
+func (l *RelayLogger) SetDebugSampleRate(rate float64) {
+	if math.IsNaN(rate) {
+		rate = 0
+	}
+	l.debugSampleRate.Store(math.Float64bits(min(max(rate, 0), 1)))
+}
+
+// DebugSampleRate returns the share of the relays logged at debug.
+func (l *RelayLogger) DebugSampleRate() float64 {
+	return math.Float64frombits(l.debugSampleRate.Load())
+}
+
+// relayLogger returns the logger of a single relay, which logs the debug
+// records only if the relay is sampled.
+// It discards all the records if the RelayLogger is nil.
+func (l *RelayLogger) relayLogger() *slog.Logger {
+	if l == nil {
+		return slog.New(discardLogHandler{})
+	}
+
+	level := l.level.Level()
+	sampled := level <= slog.LevelDebug && rand.Float64() < l.DebugSampleRate()
+	return slog.New(&relayLogHandler{handler: l.handler, level: &l.level, sampled: sampled})
+}
+
+// relayLogHandler is the slog.Handler of a single relay: it drops the records
+// below the level of its RelayLogger, and the debug ones if the relay is not sampled.
+type relayLogHandler struct {
+	handler slog.Handler
+	level   slog.Leveler
+	sampled bool
+}
+
+func (h *relayLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.level.Level() || (level <= slog.LevelDebug && !h.sampled) {
+		return false
+	}
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *relayLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *relayLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &relayLogHandler{handler: h.handler.WithAttrs(attrs), level: h.level, sampled: h.sampled}
+}
+
+func (h *relayLogHandler) WithGroup(name string) slog.Handler {
+	return &relayLogHandler{handler: h.handler.WithGroup(name), level: h.level, sampled: h.sampled}
+}
+
+// discardLogHandler is a slog.Handler discarding all the records.
+type discardLogHandler struct{}
+
+func (discardLogHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardLogHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardLogHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardLogHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelayLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewRelayLogger(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	require.Equal(t, slog.LevelInfo, logger.Level())
+	require.Equal(t, float64(1), logger.DebugSampleRate())
+
+	// logRelay logs a record of each level for a single relay, and returns the
+	// logged records.
+	logRelay := func() string {
+		buf.Reset()
+		relayLogger := logger.relayLogger().With("service_id", "svc1")
+		relayLogger.Debug("debug")
+		relayLogger.Info("info")
+		relayLogger.Warn("warn")
+		return buf.String()
+	}
+
+	output := logRelay()
+	require.NotContains(t, output, "msg=debug")
+	require.Contains(t, output, "msg=info service_id=svc1")
+	require.Contains(t, output, "msg=warn")
+
+	// The debug records are logged once the level is lowered, for the sampled relays only.
+	logger.SetLevel(slog.LevelDebug)
+	require.Contains(t, logRelay(), "msg=debug")
+
+	logger.SetDebugSampleRate(0)
+	output = logRelay()
+	require.NotContains(t, output, "msg=debug")
+	require.Contains(t, output, "msg=info")
+
+	logger.SetDebugSampleRate(0.5)
+	var sampled int
+	for range 1000 {
+		if strings.Contains(logRelay(), "msg=debug") {
+			sampled++
+		}
+	}
+	require.Greater(t, sampled, 350)
+	require.Less(t, sampled, 650)
+
+	// Out of range rates are clamped.
+	logger.SetDebugSampleRate(2)
+	require.Equal(t, float64(1), logger.DebugSampleRate())
+	logger.SetDebugSampleRate(-1)
+	require.Zero(t, logger.DebugSampleRate())
+
+	// Raising the level drops the lower level records.
+	logger.SetLevel(slog.LevelWarn)
+	output = logRelay()
+	require.NotContains(t, output, "msg=info")
+	require.Contains(t, output, "msg=warn")
+
+	// A nil RelayLogger discards all the records.
+	var nilLogger *RelayLogger
+	require.False(t, nilLogger.relayLogger().Handler().Enabled(context.Background(), slog.LevelError))
+}
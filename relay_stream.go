@@ -0,0 +1,269 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// ErrRelayStreamStalled is returned when a supplier stops sending its response
+// for longer than the configured stall timeout.
+var ErrRelayStreamStalled = sdkerrors.ErrRelayStreamStalled
+
+// RelayStreamer sends the given relay request to the given endpoint and writes
+// the supplier's validated response to the given ResponseWriter.
+// It returns the number of response body bytes written to the ResponseWriter.
+type RelayStreamer func(
+	ctx context.Context,
+	endpoint Endpoint,
+	relayRequest *servicetypes.RelayRequest,
+	w http.ResponseWriter,
+) (written int64, err error)
+
+// RelayStreamConfig specifies how a RelayStreamer receives and validates the
+// suppliers' responses.
+type RelayStreamConfig struct {
+	// PublicKeyFetcher fetches the public keys of the suppliers signing the
+	// relay responses.
+	PublicKeyFetcher PublicKeyFetcher
+	// Validator, if set, validates the relay responses, e.g. to trust the
+	// suppliers in centralized mode. A RelayResponseValidator using
+	// PublicKeyFetcher is used otherwise.
+	Validator *RelayResponseValidator
+
+	// StallTimeout is the maximum duration to wait for the supplier's response
+	// headers, and then for each chunk of its response body.
+	// The relay is aborted if it is exceeded. Zero disables the stall detection.
+	StallTimeout time.Duration
+
+	// MaxResponseSize, if positive, is the maximum size of the supplier's
+	// serialized relay response. A larger response fails with
+	// ErrRelayResponseTooLarge.
+	MaxResponseSize int64
+
+	// Identification specifies how the gateway identifies itself to the
+	// suppliers. See WithRelayIdentification.
+	Identification RelayIdentification
+
+	// Passthrough, if set, writes the supplier's response to the client as it
+	// is received, instead of once the whole relay response is received and
+	// validated, e.g. for the long-lived streams of centralized deployments.
+	// The supplier's signature covers the whole relay response, so it can not
+	// be verified before the response is written: Passthrough requires a
+	// Validator trusting the suppliers, and the relays fail with
+	// ErrNotConfigured otherwise.
+	Passthrough bool
+
+	// ErrorFormatter, if set, formats the error responses of the failed relays,
+	// matching the RPC type of the relayed request. The default error messages
+	// are used otherwise. See WithRelayErrorFormatter.
+	ErrorFormatter *types.ErrorFormatter
+}
+
+// NewHTTPRelayStreamer returns a RelayStreamer which sends relay requests the
+// same way as NewHTTPRelaySender, using the given HTTP client, or
+// http.DefaultClient if nil, and writes the supplier's response directly to the
+// client's ResponseWriter, with its status code and headers.
+//
+// Relay miners reply with a single RelayResponse, signed over its whole payload,
+// so the response can not be forwarded before it is fully received: it is
+// read, validated along with the supplier's signature, and unwrapped before
+// its POKTHTTPResponse is written to the client. Suppliers stalling for longer
+// than the StallTimeout while sending it are aborted early.
+//
+// With Passthrough, the POKTHTTPResponse is instead decoded from the relay
+// response as it is received: its status code and headers are written once
+// received, and then its body chunk by chunk, without validating the response.
+//
+// If the relay fails before the response is written, a 502 Bad Gateway (or 504
+// Gateway Timeout if the supplier stalled) error response matching the RPC type
+// of the relayed request is written, and the error is returned.
+func NewHTTPRelayStreamer(httpClient *http.Client, config RelayStreamConfig) RelayStreamer {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	validator := RelayResponseValidator{PublicKeyFetcher: config.PublicKeyFetcher}
+	if config.Validator != nil {
+		validator = *config.Validator
+	}
+
+	return func(
+		ctx context.Context,
+		endpoint Endpoint,
+		relayRequest *servicetypes.RelayRequest,
+		w http.ResponseWriter,
+	) (int64, error) {
+		if config.Passthrough && !validator.TrustSuppliers {
+			err := sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "StreamRelay: Passthrough requires a Validator trusting the suppliers")
+			writeStreamErrorResponse(w, config.ErrorFormatter, relayRequest, http.StatusInternalServerError, err)
+			return 0, err
+		}
+
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		// The stall timer cancels the relay if the supplier does not send
+		// anything within the stall timeout. It is reset on every received chunk.
+		resetStallTimer := func() {}
+		if config.StallTimeout > 0 {
+			stallTimer := time.AfterFunc(config.StallTimeout, func() { cancel(ErrRelayStreamStalled) })
+			defer stallTimer.Stop()
+			resetStallTimer = func() { stallTimer.Reset(config.StallTimeout) }
+		}
+
+		httpRequest, err := newRelayHTTPRequest(ctx, endpoint, relayRequest, config.Identification)
+		if err != nil {
+			err = fmt.Errorf("StreamRelay: %w", err)
+			writeStreamErrorResponse(w, config.ErrorFormatter, relayRequest, http.StatusInternalServerError, err)
+			return 0, err
+		}
+
+		httpResponse, err := httpClient.Do(httpRequest)
+		if err != nil {
+			err = fmt.Errorf("StreamRelay: error sending relay to supplier %s: %w", endpoint.Supplier(), streamError(ctx, err))
+			writeStreamErrorResponse(w, config.ErrorFormatter, relayRequest, streamErrorStatusCode(err), err)
+			return 0, err
+		}
+		defer httpResponse.Body.Close()
+		resetStallTimer()
+
+		httpResponse.Body = &stallResetReader{ReadCloser: httpResponse.Body, reset: resetStallTimer}
+		if config.Passthrough {
+			written, responseWritten, err := passthroughRelayResponse(httpResponse, config.MaxResponseSize, w)
+			if err != nil {
+				err = fmt.Errorf("StreamRelay: error passing through relay response of supplier %s: %w", endpoint.Supplier(), streamError(ctx, err))
+				if !responseWritten {
+					writeStreamErrorResponse(w, config.ErrorFormatter, relayRequest, streamErrorStatusCode(err), err)
+				}
+			}
+			return written, err
+		}
+
+		relayResponseBz, err := readRelayResponse(httpResponse, config.MaxResponseSize)
+		if err != nil {
+			err = fmt.Errorf("StreamRelay: error reading relay response of supplier %s: %w", endpoint.Supplier(), streamError(ctx, err))
+			writeStreamErrorResponse(w, config.ErrorFormatter, relayRequest, streamErrorStatusCode(err), err)
+			return 0, err
+		}
+
+		validatedResponse, err := validator.Validate(ctx, endpoint.Supplier(), relayResponseBz)
+		if err != nil {
+			err = fmt.Errorf("StreamRelay: error validating relay response of supplier %s: %w", endpoint.Supplier(), err)
+			writeStreamErrorResponse(w, config.ErrorFormatter, relayRequest, streamErrorStatusCode(err), err)
+			return 0, err
+		}
+
+		poktHTTPResponse, err := types.DeserializeHTTPResponse(validatedResponse.Payload)
+		if err != nil {
+			err = fmt.Errorf(
+				"StreamRelay: %w: error deserializing relay response payload of supplier %s: %w",
+				sdkerrors.ErrInvalidRelayResponse,
+				endpoint.Supplier(),
+				err,
+			)
+			writeStreamErrorResponse(w, config.ErrorFormatter, relayRequest, streamErrorStatusCode(err), err)
+			return 0, err
+		}
+
+		header := make(http.Header, len(poktHTTPResponse.GetHeader()))
+		for key, values := range poktHTTPResponse.GetHeader() {
+			header[key] = values.GetValues()
+		}
+		copyStreamHeaders(w.Header(), header)
+		validatedResponse.AddDegradedWarning(w.Header())
+
+		statusCode := int(poktHTTPResponse.GetStatusCode())
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		w.WriteHeader(statusCode)
+
+		written, err := w.Write(poktHTTPResponse.GetBodyBz())
+		if err != nil {
+			return int64(written), fmt.Errorf("StreamRelay: error writing response to the client: %w", err)
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		return int64(written), nil
+	}
+}
+
+// stallResetReader resets the stall timer of a relay on every chunk read from
+// the supplier's response body.
+type stallResetReader struct {
+	io.ReadCloser
+	reset func()
+}
+
+// Read reads from the response body, resetting the stall timer if any byte was read.
+func (r *stallResetReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.reset()
+	}
+	return n, err
+}
+
+// streamError returns ErrRelayStreamStalled, wrapping the given error, if the
+// relay was aborted by the stall timer, or the given error otherwise.
+func streamError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrRelayStreamStalled) {
+		return fmt.Errorf("%w: %w", ErrRelayStreamStalled, err)
+	}
+	return err
+}
+
+// streamErrorStatusCode returns the status code of the response to a relay
+// which failed while receiving the supplier's response.
+func streamErrorStatusCode(err error) int {
+	if errors.Is(err, ErrRelayStreamStalled) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+// writeStreamErrorResponse writes the error response of a relay which failed
+// before any byte of the supplier's response was written, matching the RPC
+// type of the relayed request, as the relay handler does.
+// The message of the server errors is not exposed to the client.
+func writeStreamErrorResponse(
+	w http.ResponseWriter,
+	errorFormatter *types.ErrorFormatter,
+	relayRequest *servicetypes.RelayRequest,
+	statusCode int,
+	err error,
+) {
+	poktRequest, deserializeErr := types.DeserializeHTTPRequest(relayRequest.GetPayload())
+	if deserializeErr != nil {
+		poktRequest = &types.POKTHTTPRequest{}
+	}
+
+	var errorResponse *types.POKTHTTPResponse
+	if errorFormatter != nil {
+		errorResponse, _ = errorFormatter.FormatErrorWithStatus(poktRequest, err, statusCode)
+	} else {
+		errorResponse, _ = poktRequest.FormatErrorWithStatus(err, statusCode)
+	}
+	writePOKTHTTPResponse(w, errorResponse)
+}
+
+// copyStreamHeaders copies the headers of the supplier's response which are
+// relevant to the client, i.e. all but the ones managed by the HTTP server.
+func copyStreamHeaders(dst, src http.Header) {
+	for key, values := range src {
+		switch http.CanonicalHeaderKey(key) {
+		case "Content-Length", "Connection", "Transfer-Encoding", "Keep-Alive":
+			continue
+		}
+		dst[key] = append(dst[key][:0:0], values...)
+	}
+}
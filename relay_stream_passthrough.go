@@ -0,0 +1,303 @@
+package sdk
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// The protobuf field numbers of the relay response fields decoded by
+// passthroughRelayResponse.
+const (
+	relayResponsePayloadField       protowire.Number = 2
+	poktHTTPResponseStatusCodeField protowire.Number = 1
+	poktHTTPResponseHeaderField     protowire.Number = 2
+	poktHTTPResponseBodyField       protowire.Number = 3
+)
+
+// passthroughRelayResponse decodes the POKTHTTPResponse wrapped in the given
+// supplier's serialized relay response as it is received, and writes it to the
+// given ResponseWriter: its status code and headers once received, and then its
+// body chunk by chunk. The relay response is neither validated nor its
+// signature verified.
+//
+// It returns the number of response body bytes written, and whether the status
+// code was written, i.e. whether the error response of a failed relay can still
+// be written.
+func passthroughRelayResponse(
+	httpResponse *http.Response,
+	maxResponseSize int64,
+	w http.ResponseWriter,
+) (written int64, responseWritten bool, err error) {
+	if maxResponseSize > 0 && httpResponse.ContentLength > maxResponseSize {
+		return 0, false, fmt.Errorf(
+			"%w: %d bytes exceeds the %d bytes limit",
+			ErrRelayResponseTooLarge,
+			httpResponse.ContentLength,
+			maxResponseSize,
+		)
+	}
+
+	// The errors reading the supplier's response, e.g. a stall, are returned
+	// as is, rather than as the decoding errors they cause.
+	body := &passthroughBodyReader{r: httpResponse.Body, maxSize: maxResponseSize}
+	defer func() {
+		if err != nil && body.err != nil {
+			err = body.err
+		}
+	}()
+
+	// The fields of the relay response are serialized in the order of their
+	// numbers, so its payload follows its metadata, which is skipped.
+	r := bufio.NewReader(body)
+	for {
+		num, typ, err := readProtoTag(r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return written, responseWritten, err
+		}
+
+		if num != relayResponsePayloadField || typ != protowire.BytesType {
+			if err := skipProtoField(r, typ); err != nil {
+				return written, responseWritten, err
+			}
+			continue
+		}
+
+		payloadSize, err := readProtoLength(r)
+		if err != nil {
+			return written, responseWritten, err
+		}
+		payload := &io.LimitedReader{R: r, N: payloadSize}
+		n, err := passthroughHTTPResponse(bufio.NewReader(payload), w)
+		written += n
+		responseWritten = true
+		if err == nil && payload.N > 0 {
+			// The relay response ended before its payload.
+			err = invalidProtoError(io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return written, responseWritten, err
+		}
+	}
+
+	if !responseWritten {
+		return 0, false, fmt.Errorf("%w: no payload", sdkerrors.ErrInvalidRelayResponse)
+	}
+	return written, true, nil
+}
+
+// passthroughHTTPResponse decodes the given serialized POKTHTTPResponse as it
+// is received, and writes it to the given ResponseWriter.
+// Its status code and headers are written once its body is reached, or once it
+// is fully read if it has no body.
+func passthroughHTTPResponse(r *bufio.Reader, w http.ResponseWriter) (int64, error) {
+	statusCode := http.StatusOK
+	header := make(http.Header)
+	writeHeader := func() {
+		copyStreamHeaders(w.Header(), header)
+		w.WriteHeader(statusCode)
+	}
+
+	var written int64
+	for {
+		num, typ, err := readProtoTag(r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			writeHeader()
+			return written, err
+		}
+
+		switch {
+		case num == poktHTTPResponseStatusCodeField && typ == protowire.VarintType:
+			code, err := binary.ReadUvarint(r)
+			if err != nil {
+				writeHeader()
+				return written, invalidProtoError(err)
+			}
+			if code != 0 {
+				statusCode = int(code)
+			}
+
+		case num == poktHTTPResponseHeaderField && typ == protowire.BytesType:
+			if err := readProtoHeaderEntry(r, header); err != nil {
+				writeHeader()
+				return written, err
+			}
+
+		case num == poktHTTPResponseBodyField && typ == protowire.BytesType:
+			bodySize, err := readProtoLength(r)
+			if err != nil {
+				writeHeader()
+				return written, err
+			}
+			writeHeader()
+			n, err := io.CopyN(flushWriter{ResponseWriter: w}, r, bodySize)
+			written += n
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = invalidProtoError(err)
+				}
+				return written, fmt.Errorf("error copying the response body: %w", err)
+			}
+			// The fields following the body, if any, can not be written anymore.
+			_, err = io.Copy(io.Discard, r)
+			return written, err
+
+		default:
+			if err := skipProtoField(r, typ); err != nil {
+				writeHeader()
+				return written, err
+			}
+		}
+	}
+
+	writeHeader()
+	return written, nil
+}
+
+// readProtoHeaderEntry reads an entry of the header map of a POKTHTTPResponse,
+// and adds its values to the given header.
+func readProtoHeaderEntry(r *bufio.Reader, header http.Header) error {
+	entrySize, err := readProtoLength(r)
+	if err != nil {
+		return err
+	}
+	entryBz := make([]byte, entrySize)
+	if _, err := io.ReadFull(r, entryBz); err != nil {
+		return invalidProtoError(err)
+	}
+
+	// The entry is decoded as the header map of a POKTHTTPResponse.
+	entryResponse := &types.POKTHTTPResponse{}
+	fieldBz := protowire.AppendBytes(protowire.AppendTag(nil, poktHTTPResponseHeaderField, protowire.BytesType), entryBz)
+	if err := proto.Unmarshal(fieldBz, entryResponse); err != nil {
+		return invalidProtoError(err)
+	}
+	for key, values := range entryResponse.GetHeader() {
+		header[key] = append(header[key], values.GetValues()...)
+	}
+	return nil
+}
+
+// readProtoTag reads the tag of the next protobuf field.
+// It returns io.EOF if there are no more fields.
+func readProtoTag(r *bufio.Reader) (protowire.Number, protowire.Type, error) {
+	tag, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return 0, 0, io.EOF
+	}
+	if err != nil {
+		return 0, 0, invalidProtoError(err)
+	}
+
+	num, typ := protowire.DecodeTag(tag)
+	if !num.IsValid() {
+		return 0, 0, invalidProtoError(fmt.Errorf("invalid field number %d", num))
+	}
+	return num, typ, nil
+}
+
+// readProtoLength reads the length of a length-delimited protobuf field.
+func readProtoLength(r *bufio.Reader) (int64, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, invalidProtoError(err)
+	}
+	return int64(length), nil
+}
+
+// skipProtoField skips the value of a protobuf field of the given wire type.
+func skipProtoField(r *bufio.Reader, typ protowire.Type) error {
+	var err error
+	switch typ {
+	case protowire.VarintType:
+		_, err = binary.ReadUvarint(r)
+	case protowire.Fixed32Type:
+		_, err = r.Discard(4)
+	case protowire.Fixed64Type:
+		_, err = r.Discard(8)
+	case protowire.BytesType:
+		var length int64
+		if length, err = readProtoLength(r); err == nil {
+			_, err = io.CopyN(io.Discard, r, length)
+		}
+	default:
+		err = fmt.Errorf("unsupported wire type %d", typ)
+	}
+	if err != nil {
+		return invalidProtoError(err)
+	}
+	return nil
+}
+
+// invalidProtoError returns the error of a relay response which could not be
+// decoded because of the given error.
+func invalidProtoError(err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("%w: %w", sdkerrors.ErrInvalidRelayResponse, err)
+}
+
+// passthroughBodyReader reads the supplier's response body, failing with
+// ErrRelayResponseTooLarge once more than maxSize bytes are read, if positive.
+// It records the first error reading the body, other than io.EOF.
+type passthroughBodyReader struct {
+	r       io.Reader
+	maxSize int64
+	read    int64
+	err     error
+}
+
+// Read reads from the response body, failing once maxSize is exceeded.
+func (r *passthroughBodyReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	// At most one byte past the limit is read, to tell whether it is exceeded.
+	if r.maxSize > 0 {
+		if remaining := r.maxSize - r.read + 1; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	if r.maxSize > 0 && r.read > r.maxSize {
+		n--
+		err = fmt.Errorf("%w: exceeds the %d bytes limit", ErrRelayResponseTooLarge, r.maxSize)
+	}
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// flushWriter flushes the ResponseWriter after every write, so the response
+// body is sent to the client as it is received.
+type flushWriter struct {
+	http.ResponseWriter
+}
+
+// Write writes the given bytes to the ResponseWriter, and flushes it.
+func (w flushWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
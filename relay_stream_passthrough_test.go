@@ -0,0 +1,179 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestHTTPRelayStreamer_Passthrough(t *testing.T) {
+	header := sessiontypes.SessionHeader{
+		ApplicationAddress:      "pokt1app",
+		ServiceId:               "svc1",
+		SessionId:               "session1",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   4,
+	}
+	payload, err := proto.Marshal(&types.POKTHTTPResponse{
+		StatusCode: http.StatusAccepted,
+		Header: map[string]*types.Header{
+			"Content-Type": {Key: "Content-Type", Values: []string{"text/event-stream"}},
+			"X-Custom":     {Key: "X-Custom", Values: []string{"a", "b"}},
+		},
+		BodyBz: []byte("data: 1\n\ndata: 2\n\n"),
+	})
+	require.NoError(t, err)
+	// The relay response is not signed, since the suppliers are trusted.
+	relayResponseBz, err := (&servicetypes.RelayResponse{
+		Meta:    servicetypes.RelayResponseMetadata{SessionHeader: &header},
+		Payload: payload,
+	}).Marshal()
+	require.NoError(t, err)
+	// firstChunkSize splits the relay response in the middle of the body.
+	firstChunkSize := len(relayResponseBz) - len("data: 2\n\n")
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/complete":
+			_, _ = w.Write(relayResponseBz[:firstChunkSize])
+			w.(http.Flusher).Flush()
+			<-release
+			_, _ = w.Write(relayResponseBz[firstChunkSize:])
+		case "/truncated-before-body":
+			_, _ = w.Write(relayResponseBz[:10])
+		case "/truncated-mid-body":
+			_, _ = w.Write(relayResponseBz[:firstChunkSize])
+		case "/stall-mid-body":
+			_, _ = w.Write(relayResponseBz[:firstChunkSize])
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	newEndpoint := func(path string) Endpoint {
+		return NewEndpoint(
+			header,
+			sharedtypes.SupplierEndpoint{Url: server.URL + path},
+			SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: "pokt1supplier"}},
+		)
+	}
+	config := RelayStreamConfig{
+		Validator:    &RelayResponseValidator{TrustSuppliers: true},
+		StallTimeout: 50 * time.Millisecond,
+		Passthrough:  true,
+	}
+
+	t.Run("body written as it is received", func(t *testing.T) {
+		streamer := NewHTTPRelayStreamer(nil, config)
+		w := &flushSignalingRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{}, 1)}
+		type result struct {
+			written int64
+			err     error
+		}
+		done := make(chan result, 1)
+		go func() {
+			written, err := streamer(context.Background(), newEndpoint("/complete"), &servicetypes.RelayRequest{}, w)
+			done <- result{written: written, err: err}
+		}()
+
+		// The first chunk of the body is written before the rest is sent.
+		<-w.flushed
+		require.Equal(t, http.StatusAccepted, w.Code)
+		require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		require.Equal(t, []string{"a", "b"}, w.Header().Values("X-Custom"))
+		require.Equal(t, "data: 1\n\n", w.Body.String())
+
+		close(release)
+		res := <-done
+		require.NoError(t, res.err)
+		require.Equal(t, int64(18), res.written)
+		require.Equal(t, "data: 1\n\ndata: 2\n\n", w.Body.String())
+	})
+
+	tests := []struct {
+		desc               string
+		path               string
+		maxResponseSize    int64
+		expectedStatusCode int
+		expectedWritten    int64
+		expectedErr        error
+	}{
+		{
+			desc:               "truncated before the body",
+			path:               "/truncated-before-body",
+			expectedStatusCode: http.StatusBadGateway,
+			expectedErr:        sdkerrors.ErrInvalidRelayResponse,
+		},
+		{
+			desc:               "truncated in the middle of the body",
+			path:               "/truncated-mid-body",
+			expectedStatusCode: http.StatusAccepted,
+			expectedWritten:    9,
+			expectedErr:        sdkerrors.ErrInvalidRelayResponse,
+		},
+		{
+			desc:               "stalled in the middle of the body",
+			path:               "/stall-mid-body",
+			expectedStatusCode: http.StatusAccepted,
+			expectedWritten:    9,
+			expectedErr:        ErrRelayStreamStalled,
+		},
+		{
+			desc:               "too large",
+			path:               "/truncated-mid-body",
+			maxResponseSize:    10,
+			expectedStatusCode: http.StatusBadGateway,
+			expectedErr:        ErrRelayResponseTooLarge,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			config := config
+			config.MaxResponseSize = test.maxResponseSize
+			streamer := NewHTTPRelayStreamer(nil, config)
+
+			recorder := httptest.NewRecorder()
+			written, err := streamer(context.Background(), newEndpoint(test.path), &servicetypes.RelayRequest{}, recorder)
+			require.ErrorIs(t, err, test.expectedErr)
+			require.Equal(t, test.expectedWritten, written)
+			require.Equal(t, test.expectedStatusCode, recorder.Code)
+		})
+	}
+
+	t.Run("suppliers not trusted", func(t *testing.T) {
+		streamer := NewHTTPRelayStreamer(nil, RelayStreamConfig{Passthrough: true})
+
+		recorder := httptest.NewRecorder()
+		_, err := streamer(context.Background(), newEndpoint("/complete"), &servicetypes.RelayRequest{}, recorder)
+		require.ErrorIs(t, err, sdkerrors.ErrNotConfigured)
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
+
+// flushSignalingRecorder is a ResponseRecorder signaling its first flush.
+type flushSignalingRecorder struct {
+	*httptest.ResponseRecorder
+	flushed chan struct{}
+}
+
+func (r *flushSignalingRecorder) Flush() {
+	r.ResponseRecorder.Flush()
+	select {
+	case r.flushed <- struct{}{}:
+	default:
+	}
+}
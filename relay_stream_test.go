@@ -0,0 +1,161 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestHTTPRelayStreamer(t *testing.T) {
+	supplierKey := secp256k1.GenPrivKey()
+	supplierAddress, err := PubKeyToAddress(PoktAddressPrefix, supplierKey.PubKey())
+	require.NoError(t, err)
+	header := sessiontypes.SessionHeader{
+		ApplicationAddress:      supplierAddress,
+		ServiceId:               "svc1",
+		SessionId:               "session1",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   4,
+	}
+
+	// newRelayResponse returns a serialized RelayResponse wrapping an SSE
+	// response, signed by the given key, as sent by the relay miners.
+	newRelayResponse := func(signingKey *secp256k1.PrivKey) []byte {
+		poktHTTPResponse := &types.POKTHTTPResponse{
+			StatusCode: http.StatusOK,
+			Header:     map[string]*types.Header{"Content-Type": {Key: "Content-Type", Values: []string{"text/event-stream"}}},
+			BodyBz:     []byte("data: 1\n\ndata: 2\n\n"),
+		}
+		payload, err := proto.Marshal(poktHTTPResponse)
+		require.NoError(t, err)
+
+		relayResponse := &servicetypes.RelayResponse{
+			Meta:    servicetypes.RelayResponseMetadata{SessionHeader: &header},
+			Payload: payload,
+		}
+		signableBz, err := relayResponse.GetSignableBytesHash()
+		require.NoError(t, err)
+		relayResponse.Meta.SupplierOperatorSignature, err = signingKey.Sign(signableBz[:])
+		require.NoError(t, err)
+		relayResponseBz, err := relayResponse.Marshal()
+		require.NoError(t, err)
+		return relayResponseBz
+	}
+	validResponseBz := newRelayResponse(supplierKey)
+	forgedResponseBz := newRelayResponse(secp256k1.GenPrivKey())
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/complete":
+			_, _ = w.Write(validResponseBz[:10])
+			w.(http.Flusher).Flush()
+			_, _ = w.Write(validResponseBz[10:])
+		case "/forged":
+			_, _ = w.Write(forgedResponseBz)
+		case "/stall-mid-response":
+			_, _ = w.Write(validResponseBz[:10])
+			w.(http.Flusher).Flush()
+			<-release
+		case "/stall-before-headers":
+			<-release
+		}
+	}))
+	defer server.Close()
+	// Unblock the stalled handlers before closing the server.
+	defer close(release)
+
+	streamer := NewHTTPRelayStreamer(nil, RelayStreamConfig{
+		PublicKeyFetcher: &countingPubKeyFetcher{
+			pubKeys: map[string]cryptotypes.PubKey{supplierAddress: supplierKey.PubKey()},
+		},
+		StallTimeout: 50 * time.Millisecond,
+	})
+
+	tests := []struct {
+		path               string
+		expectedStatusCode int
+		expectedBody       string
+		expectedWritten    int64
+		expectedErr        error
+	}{
+		{
+			path:               "/complete",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "data: 1\n\ndata: 2\n\n",
+			expectedWritten:    18,
+		},
+		{
+			path:               "/forged",
+			expectedStatusCode: http.StatusBadGateway,
+			expectedErr:        sdkerrors.ErrInvalidSupplierSignature,
+		},
+		{
+			path:               "/stall-mid-response",
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedErr:        ErrRelayStreamStalled,
+		},
+		{
+			path:               "/stall-before-headers",
+			expectedStatusCode: http.StatusGatewayTimeout,
+			expectedErr:        ErrRelayStreamStalled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			endpoint := NewEndpoint(
+				header,
+				sharedtypes.SupplierEndpoint{Url: server.URL + test.path},
+				SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: supplierAddress}},
+			)
+
+			recorder := httptest.NewRecorder()
+			written, err := streamer(context.Background(), endpoint, &servicetypes.RelayRequest{}, recorder)
+
+			require.Equal(t, test.expectedWritten, written)
+			require.Equal(t, test.expectedStatusCode, recorder.Code)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+			require.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+
+	// The error responses match the RPC type of the relayed request.
+	httpRequest, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","method":"eth_blockNumber","id":1}`))
+	require.NoError(t, err)
+	httpRequest.Header.Set("Content-Type", "application/json")
+	_, requestBz, err := types.SerializeHTTPRequest(httpRequest)
+	require.NoError(t, err)
+	endpoint := NewEndpoint(
+		header,
+		sharedtypes.SupplierEndpoint{Url: server.URL + "/forged"},
+		SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: supplierAddress}},
+	)
+
+	recorder := httptest.NewRecorder()
+	_, err = streamer(context.Background(), endpoint, &servicetypes.RelayRequest{Payload: requestBz}, recorder)
+	require.ErrorIs(t, err, sdkerrors.ErrInvalidSupplierSignature)
+	require.Equal(t, http.StatusBadGateway, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), `"jsonrpc":"2.0"`)
+	require.NotContains(t, recorder.Body.String(), supplierAddress)
+}
@@ -1,17 +1,31 @@
 package sdk
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
-	"net/url"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
 
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	apptypes "github.com/pokt-network/poktroll/x/application/types"
 	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
 
 	grpc "github.com/cosmos/gogoproto/grpc"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
 )
 
 func ExampleRelay() {
@@ -66,7 +80,12 @@ func ExampleRelay() {
 	}
 
 	// 4.f. Send the Signed Relay Request to the selected endpoint
-	responseBz, err := SendHttpRelay(ctx, endpoints[0].Endpoint().Url, *req)
+	sendRelay := NewHTTPRelaySender(
+		&http.Client{Timeout: 30 * time.Second},
+		WithRelayRetry(retry.Config{MaxAttempts: 3, Policy: retry.Exponential{Initial: 100 * time.Millisecond, Max: time.Second}}),
+		WithMaxRelayResponseSize(10<<20),
+	)
+	responseBz, err := sendRelay(ctx, endpoints[0], req)
 	if err != nil {
 		fmt.Printf("error sending relay: %v", err)
 		return
@@ -82,36 +101,238 @@ func ExampleRelay() {
 	fmt.Printf("Validated response: %v\n", validatedResponse)
 }
 
-// SendHttpRelay sends the relay request to the supplier at the given URL using an HTTP Post request.
-func SendHttpRelay(
-	ctx context.Context,
-	supplierUrlStr string,
-	relayRequest servicetypes.RelayRequest,
-) (relayResponseBz []byte, err error) {
-	supplierUrl, err := url.Parse(supplierUrlStr)
-	if err != nil {
-		return nil, err
+func TestRelayResponseValidator_TrustSuppliers(t *testing.T) {
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, secp256k1.GenPrivKey().PubKey())
+	require.NoError(t, err)
+
+	relayResponse := &servicetypes.RelayResponse{
+		Meta: servicetypes.RelayResponseMetadata{
+			SessionHeader: &sessiontypes.SessionHeader{
+				ApplicationAddress:      appAddress,
+				ServiceId:               "svc1",
+				SessionId:               "session1",
+				SessionStartBlockHeight: 1,
+				SessionEndBlockHeight:   4,
+			},
+			SupplierOperatorSignature: []byte("invalid signature"),
+		},
+		Payload: []byte("payload"),
 	}
+	relayResponseBz, err := relayResponse.Marshal()
+	require.NoError(t, err)
 
-	relayRequestBz, err := relayRequest.Marshal()
-	if err != nil {
-		return nil, err
+	fetcher := &countingPubKeyFetcher{
+		pubKeys: map[string]cryptotypes.PubKey{"pokt1supplier": secp256k1.GenPrivKey().PubKey()},
 	}
 
-	relayRequestReadCloser := io.NopCloser(bytes.NewReader(relayRequestBz))
-	defer relayRequestReadCloser.Close()
+	// The invalid signature is rejected by default.
+	validator := RelayResponseValidator{PublicKeyFetcher: fetcher}
+	_, err = validator.Validate(context.Background(), "pokt1supplier", relayResponseBz)
+	require.Error(t, err)
+	require.Equal(t, int64(1), fetcher.calls.Load())
 
-	relayHTTPRequest := &http.Request{
-		Method: http.MethodPost,
-		URL:    supplierUrl,
-		Body:   relayRequestReadCloser,
+	// The signature is not verified in trusted mode.
+	validator.TrustSuppliers = true
+	validatedResponse, err := validator.Validate(context.Background(), "pokt1supplier", relayResponseBz)
+	require.NoError(t, err)
+	require.False(t, validatedResponse.SignatureVerified)
+	require.Equal(t, []byte("payload"), validatedResponse.Payload)
+	require.Equal(t, int64(1), fetcher.calls.Load())
+}
+
+func TestVerifyRelayResponse_Light(t *testing.T) {
+	supplierKey := secp256k1.GenPrivKey()
+	relayResponse := &servicetypes.RelayResponse{
+		Meta: servicetypes.RelayResponseMetadata{
+			SessionHeader: &sessiontypes.SessionHeader{
+				ApplicationAddress:      "pokt1app",
+				ServiceId:               "svc1",
+				SessionId:               "session1",
+				SessionStartBlockHeight: 1,
+				SessionEndBlockHeight:   4,
+			},
+		},
+		Payload: []byte("payload"),
 	}
+	signableBz, err := relayResponse.GetSignableBytesHash()
+	require.NoError(t, err)
+	relayResponse.Meta.SupplierOperatorSignature, err = supplierKey.Sign(signableBz[:])
+	require.NoError(t, err)
+	relayResponseBz, err := relayResponse.Marshal()
+	require.NoError(t, err)
 
-	relayHTTPResponse, err := http.DefaultClient.Do(relayHTTPRequest)
-	if err != nil {
-		return nil, err
+	// The light relay types hash the signable bytes as poktroll does, and verify
+	// the signatures of the poktroll relay responses.
+	lightResponse, err := types.VerifyRelayResponse(relayResponseBz, supplierKey.PubKey().Bytes())
+	require.NoError(t, err)
+	lightSignableBz, err := lightResponse.SignableBytesHash()
+	require.NoError(t, err)
+	require.Equal(t, signableBz, lightSignableBz)
+	require.Equal(t, []byte("payload"), lightResponse.GetPayload())
+
+	_, err = types.VerifyRelayResponse(relayResponseBz, secp256k1.GenPrivKey().PubKey().Bytes())
+	require.ErrorIs(t, err, sdkerrors.ErrInvalidSupplierSignature)
+}
+
+func TestRelayResponseValidator_ValidateBasicPolicy(t *testing.T) {
+	supplierKey := secp256k1.GenPrivKey()
+	fetcher := &countingPubKeyFetcher{
+		pubKeys: map[string]cryptotypes.PubKey{
+			"pokt1supplier": supplierKey.PubKey(),
+			"pokt1other":    secp256k1.GenPrivKey().PubKey(),
+		},
+	}
+
+	// The session header is missing the session id, failing basic validation.
+	relayResponse := &servicetypes.RelayResponse{
+		Meta: servicetypes.RelayResponseMetadata{
+			SessionHeader: &sessiontypes.SessionHeader{ServiceId: "svc1"},
+		},
+		Payload: []byte("payload"),
 	}
-	defer relayHTTPResponse.Body.Close()
+	signableBz, err := relayResponse.GetSignableBytesHash()
+	require.NoError(t, err)
+	relayResponse.Meta.SupplierOperatorSignature, err = supplierKey.Sign(signableBz[:])
+	require.NoError(t, err)
+	relayResponseBz, err := relayResponse.Marshal()
+	require.NoError(t, err)
+
+	// The response is rejected by default, with an error telling it apart from
+	// signature failures.
+	validator := RelayResponseValidator{PublicKeyFetcher: fetcher}
+	_, err = validator.Validate(context.Background(), "pokt1supplier", relayResponseBz)
+	require.ErrorIs(t, err, sdkerrors.ErrRelayResponseBasicValidation)
+	require.ErrorIs(t, err, sdkerrors.ErrInvalidRelayResponse)
+	code, ok := sdkerrors.CodeOf(err)
+	require.True(t, ok)
+	require.Equal(t, sdkerrors.ErrRelayResponseBasicValidation.Code(), code)
+
+	// The response is passed through degraded if its signature is verified.
+	validator.ValidateBasicPolicy = ValidateBasicPassDegraded
+	validatedResponse, err := validator.Validate(context.Background(), "pokt1supplier", relayResponseBz)
+	require.NoError(t, err)
+	require.True(t, validatedResponse.SignatureVerified)
+	require.True(t, validatedResponse.Degraded())
+	require.ErrorIs(t, validatedResponse.ValidateBasicErr, sdkerrors.ErrRelayResponseBasicValidation)
+	require.Equal(t, []byte("payload"), validatedResponse.Payload)
+
+	header := http.Header{}
+	validatedResponse.AddDegradedWarning(header)
+	require.Contains(t, header.Get("Warning"), "failed basic validation")
+
+	// A degraded response still requires a valid signature.
+	_, err = validator.Validate(context.Background(), "pokt1other", relayResponseBz)
+	require.ErrorIs(t, err, sdkerrors.ErrInvalidSupplierSignature)
+
+	// Degraded responses can not be passed through without verifying their
+	// signature, even the ones passing basic validation.
+	validator.TrustSuppliers = true
+	_, err = validator.Validate(context.Background(), "pokt1supplier", relayResponseBz)
+	require.ErrorIs(t, err, sdkerrors.ErrNotConfigured)
+}
+
+func TestNewHTTPRelaySender_Options(t *testing.T) {
+	var requests, failures atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures.Load() {
+			// Drop the connection, as a restarting supplier would.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		if r.URL.Path == "/slow" {
+			// The request context is only canceled on disconnection once the body is read.
+			_, _ = io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+	}))
+	defer server.Close()
+
+	newEndpoint := func(url string) Endpoint {
+		return NewEndpoint(
+			sessiontypes.SessionHeader{ServiceId: "svc1"},
+			sharedtypes.SupplierEndpoint{Url: url},
+			SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: "pokt1supplier"}},
+		)
+	}
+	endpoint := newEndpoint(server.URL)
+	ctx := context.Background()
+
+	// The relays dropped after being sent are not retried by default, since the
+	// supplier may have served them.
+	sender := NewHTTPRelaySender(server.Client(), WithRelayRetry(retry.Config{MaxAttempts: 3}))
+	failures.Store(2)
+	_, err := sender(ctx, endpoint, &servicetypes.RelayRequest{})
+	require.True(t, IsDroppedRelayError(err))
+	require.False(t, IsTransientRelayError(err))
+	require.Equal(t, int32(1), requests.Load())
+
+	// Retrying them is opt-in.
+	requests.Store(0)
+	sender = NewHTTPRelaySender(server.Client(), WithRelayRetry(retry.Config{
+		MaxAttempts: 3,
+		ShouldRetry: retry.Any(IsTransientRelayError, IsDroppedRelayError),
+	}))
+	responseBz, err := sender(ctx, endpoint, &servicetypes.RelayRequest{})
+	require.NoError(t, err)
+	require.Len(t, responseBz, 100)
+	require.Equal(t, int32(3), requests.Load())
+
+	// The relays which could not be sent are retried.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedURL := "http://" + listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	var dials atomic.Int32
+	dialer := &net.Dialer{}
+	dialingClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dials.Add(1)
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}}
+	sender = NewHTTPRelaySender(dialingClient, WithRelayRetry(retry.Config{MaxAttempts: 3}))
+	_, err = sender(ctx, newEndpoint(closedURL), &servicetypes.RelayRequest{})
+	require.True(t, IsTransientRelayError(err))
+	require.Equal(t, int32(3), dials.Load())
+
+	// The responses exceeding the maximum size are rejected, and not retried.
+	requests.Store(0)
+	failures.Store(0)
+	sender = NewHTTPRelaySender(
+		server.Client(),
+		WithRelayRetry(retry.Config{MaxAttempts: 3}),
+		WithMaxRelayResponseSize(10),
+	)
+	_, err = sender(ctx, endpoint, &servicetypes.RelayRequest{})
+	require.ErrorIs(t, err, ErrRelayResponseTooLarge)
+	require.Equal(t, int32(1), requests.Load())
+
+	// Each attempt is bounded by the relay timeout.
+	sender = NewHTTPRelaySender(server.Client(), WithRelayTimeout(10*time.Millisecond))
+	_, err = sender(ctx, newEndpoint(server.URL+"/slow"), &servicetypes.RelayRequest{})
+	require.ErrorIs(t, err, sdkerrors.ErrRelayTimeout)
+}
+
+func TestIsTransientRelayError(t *testing.T) {
+	require.True(t, IsTransientRelayError(fmt.Errorf("SendRelay: %w: %w", errRelayNotSent, io.EOF)))
+	require.True(t, IsTransientRelayError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))
+	require.False(t, IsTransientRelayError(fmt.Errorf("SendRelay: %w", io.EOF)))
+	require.False(t, IsTransientRelayError(syscall.ECONNRESET))
+	require.False(t, IsTransientRelayError(context.DeadlineExceeded))
+	require.False(t, IsTransientRelayError(context.Canceled))
+	require.False(t, IsTransientRelayError(ErrRelayResponseTooLarge))
+	require.False(t, IsTransientRelayError(nil))
+}
 
-	return io.ReadAll(relayHTTPResponse.Body)
+func TestIsDroppedRelayError(t *testing.T) {
+	require.True(t, IsDroppedRelayError(fmt.Errorf("SendRelay: %w", io.EOF)))
+	require.True(t, IsDroppedRelayError(syscall.ECONNRESET))
+	require.False(t, IsDroppedRelayError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))
+	require.False(t, IsDroppedRelayError(context.DeadlineExceeded))
+	require.False(t, IsDroppedRelayError(nil))
 }
@@ -0,0 +1,309 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// defaultLatencyWindowSize is the number of latest relay latencies kept per
+// endpoint if no window size is specified.
+const defaultLatencyWindowSize = 100
+
+// RelayPhase is a phase of a relay sent over HTTP.
+type RelayPhase string
+
+const (
+	// RelayPhaseConnect covers the DNS lookup, the dial, and the TLS handshake.
+	// It is skipped when an idle connection is reused.
+	RelayPhaseConnect RelayPhase = "connect"
+	// RelayPhaseSend covers writing the relay request.
+	RelayPhaseSend RelayPhase = "send"
+	// RelayPhaseWait covers waiting for the first byte of the response, i.e.
+	// the time taken by the supplier and its backend to process the relay.
+	RelayPhaseWait RelayPhase = "wait"
+	// RelayPhaseRead covers reading the relay response.
+	RelayPhaseRead RelayPhase = "read"
+)
+
+// RelayPhaseDurations holds the time consumed by each phase of a relay.
+// The phases which were not reached are zero.
+type RelayPhaseDurations struct {
+	Connect time.Duration
+	Send    time.Duration
+	Wait    time.Duration
+	Read    time.Duration
+}
+
+// RelayTimeoutError is returned by the RelaySender built by NewHTTPRelaySender
+// when a relay times out.
+//
+// It tells how the time budget of the relay was consumed, and how it compares to
+// the endpoint's recent latency, so that a slow supplier can be told apart from a
+// timeout which is too aggressive for the endpoint.
+// It matches sdkerrors.ErrRelayTimeout using errors.Is.
+type RelayTimeoutError struct {
+	Supplier SupplierAddress
+	// Budget is the time allowed for the relay, from the context deadline or the
+	// HTTP client timeout, whichever is shorter. It is zero if unknown.
+	Budget time.Duration
+	// Elapsed is the time consumed by the relay before it timed out.
+	Elapsed time.Duration
+	// Phase is the phase in progress when the relay timed out.
+	Phase RelayPhase
+	// Phases holds the time consumed by each phase.
+	Phases RelayPhaseDurations
+	// EndpointP95 is the 95th percentile of the endpoint's recent successful
+	// relay latencies. It is zero if no RelayLatencyTracker is configured, or if
+	// the endpoint has no recent successful relay.
+	EndpointP95 time.Duration
+	// Err is the underlying timeout error.
+	Err error
+}
+
+// Error returns a description of the timeout, including the budget consumption hints.
+func (e *RelayTimeoutError) Error() string {
+	msg := fmt.Sprintf(
+		"relay to supplier %s timed out in the %s phase after %s (connect %s, send %s, wait %s, read %s)",
+		e.Supplier, e.Phase, e.Elapsed, e.Phases.Connect, e.Phases.Send, e.Phases.Wait, e.Phases.Read,
+	)
+	if e.Budget > 0 {
+		msg += fmt.Sprintf(", budget %s", e.Budget)
+	}
+	if e.EndpointP95 > 0 {
+		msg += fmt.Sprintf(", endpoint P95 %s", e.EndpointP95)
+	}
+	if e.BudgetBelowP95() {
+		msg += ": the timeout is likely too aggressive for this endpoint"
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+// Unwrap returns sdkerrors.ErrRelayTimeout and the underlying timeout error.
+func (e *RelayTimeoutError) Unwrap() []error {
+	return []error{sdkerrors.ErrRelayTimeout, e.Err}
+}
+
+// BudgetBelowP95 returns true if the budget of the relay is lower than the
+// endpoint's recent P95 latency, i.e. the timeout is likely too aggressive for
+// the endpoint rather than the supplier being unusually slow.
+func (e *RelayTimeoutError) BudgetBelowP95() bool {
+	return e.Budget > 0 && e.EndpointP95 > 0 && e.Budget < e.EndpointP95
+}
+
+// RelayLatencyTracker tracks the latencies of the latest successful relays of
+// each endpoint, to compute their recent P95 latency.
+// It is safe for concurrent use.
+type RelayLatencyTracker struct {
+	windowSize int
+
+	mu        sync.Mutex
+	latencies map[string]*latencyWindow
+}
+
+// latencyWindow is a ring buffer of the latest latencies of an endpoint.
+type latencyWindow struct {
+	latencies []time.Duration
+	next      int
+}
+
+// NewRelayLatencyTracker returns a RelayLatencyTracker keeping the given number
+// of latest latencies per endpoint, or 100 if windowSize is zero.
+func NewRelayLatencyTracker(windowSize int) *RelayLatencyTracker {
+	if windowSize <= 0 {
+		windowSize = defaultLatencyWindowSize
+	}
+
+	return &RelayLatencyTracker{
+		windowSize: windowSize,
+		latencies:  make(map[string]*latencyWindow),
+	}
+}
+
+// Observe records the latency of a successful relay to the given endpoint.
+func (t *RelayLatencyTracker) Observe(endpoint Endpoint, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := latencyKey(endpoint)
+	window, ok := t.latencies[key]
+	if !ok {
+		window = &latencyWindow{latencies: make([]time.Duration, 0, t.windowSize)}
+		t.latencies[key] = window
+	}
+
+	if len(window.latencies) < t.windowSize {
+		window.latencies = append(window.latencies, latency)
+		return
+	}
+	window.latencies[window.next] = latency
+	window.next = (window.next + 1) % t.windowSize
+}
+
+// P95 returns the 95th percentile of the recorded latencies of the given
+// endpoint, or false if no latency was recorded.
+func (t *RelayLatencyTracker) P95(endpoint Endpoint) (time.Duration, bool) {
+	t.mu.Lock()
+	window, ok := t.latencies[latencyKey(endpoint)]
+	var latencies []time.Duration
+	if ok {
+		latencies = slices.Clone(window.latencies)
+	}
+	t.mu.Unlock()
+
+	if len(latencies) == 0 {
+		return 0, false
+	}
+
+	slices.Sort(latencies)
+	// Nearest-rank percentile.
+	rank := (95*len(latencies) + 99) / 100
+	return latencies[rank-1], true
+}
+
+// latencyKey returns the key identifying the given endpoint in a RelayLatencyTracker.
+func latencyKey(endpoint Endpoint) string {
+	return string(endpoint.Supplier()) + " " + endpoint.Endpoint().Url
+}
+
+// relayPhaseTracer records the phases of a relay through an httptrace.ClientTrace.
+// It is safe for concurrent use, as the trace hooks may be called from
+// different goroutines.
+type relayPhaseTracer struct {
+	mu        sync.Mutex
+	startTime time.Time
+	// phaseStarts holds the start time of the reached phases.
+	phaseStarts map[RelayPhase]time.Time
+}
+
+// newRelayPhaseTracer returns a relayPhaseTracer for a relay starting now,
+// along with the context tracing the relay's HTTP request.
+func newRelayPhaseTracer(ctx context.Context) (*relayPhaseTracer, context.Context) {
+	startTime := time.Now()
+	tracer := &relayPhaseTracer{
+		startTime:   startTime,
+		phaseStarts: map[RelayPhase]time.Time{RelayPhaseConnect: startTime},
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn:              func(httptrace.GotConnInfo) { tracer.startPhase(RelayPhaseSend) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { tracer.startPhase(RelayPhaseWait) },
+		GotFirstResponseByte: func() { tracer.startPhase(RelayPhaseRead) },
+	}
+	return tracer, httptrace.WithClientTrace(ctx, trace)
+}
+
+// startPhase records the start of the given phase.
+func (t *relayPhaseTracer) startPhase(phase RelayPhase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.phaseStarts[phase]; !ok {
+		t.phaseStarts[phase] = time.Now()
+	}
+}
+
+// connected reports whether a connection to the supplier was obtained, i.e.
+// whether the relay request may have been sent.
+func (t *relayPhaseTracer) connected() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.phaseStarts[RelayPhaseSend]
+	return ok
+}
+
+// elapsed returns the time elapsed since the start of the relay.
+func (t *relayPhaseTracer) elapsed() time.Duration {
+	return time.Since(t.startTime)
+}
+
+// phases returns the time consumed by each phase until now, along with the
+// phase in progress.
+func (t *relayPhaseTracer) phases() (RelayPhaseDurations, RelayPhase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	order := []RelayPhase{RelayPhaseConnect, RelayPhaseSend, RelayPhaseWait, RelayPhaseRead}
+	durations := make(map[RelayPhase]time.Duration, len(order))
+	current := RelayPhaseConnect
+	for i, phase := range order {
+		start, ok := t.phaseStarts[phase]
+		if !ok {
+			continue
+		}
+		current = phase
+
+		end := now
+		for _, nextPhase := range order[i+1:] {
+			if nextStart, ok := t.phaseStarts[nextPhase]; ok {
+				end = nextStart
+				break
+			}
+		}
+		durations[phase] = end.Sub(start)
+	}
+
+	return RelayPhaseDurations{
+		Connect: durations[RelayPhaseConnect],
+		Send:    durations[RelayPhaseSend],
+		Wait:    durations[RelayPhaseWait],
+		Read:    durations[RelayPhaseRead],
+	}, current
+}
+
+// newRelayTimeoutError returns the RelayTimeoutError describing the given
+// timeout error of a relay, traced by the given tracer.
+func newRelayTimeoutError(
+	endpoint Endpoint,
+	tracer *relayPhaseTracer,
+	budget time.Duration,
+	latencyTracker *RelayLatencyTracker,
+	err error,
+) *RelayTimeoutError {
+	phases, phase := tracer.phases()
+	timeoutErr := &RelayTimeoutError{
+		Supplier: endpoint.Supplier(),
+		Budget:   budget,
+		Elapsed:  tracer.elapsed(),
+		Phase:    phase,
+		Phases:   phases,
+		Err:      err,
+	}
+	if latencyTracker != nil {
+		timeoutErr.EndpointP95, _ = latencyTracker.P95(endpoint)
+	}
+	return timeoutErr
+}
+
+// relayBudget returns the time allowed for a relay sent with the given context
+// and HTTP client, or zero if the relay has no deadline.
+func relayBudget(ctx context.Context, httpClient *http.Client) time.Duration {
+	budget := httpClient.Timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); budget == 0 || remaining < budget {
+			budget = max(remaining, 0)
+		}
+	}
+	return budget
+}
+
+// isTimeoutError returns true if the given error is caused by a deadline or a
+// timeout being exceeded.
+func isTimeoutError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,81 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestNewHTTPRelaySender_TimeoutHints(t *testing.T) {
+	var delay time.Duration
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+		}
+		_, _ = w.Write([]byte("response"))
+	}))
+	defer server.Close()
+
+	endpoint := NewEndpoint(
+		sessiontypes.SessionHeader{ServiceId: "svc1"},
+		sharedtypes.SupplierEndpoint{Url: server.URL},
+		SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: "pokt1supplier"}},
+	)
+	tracker := NewRelayLatencyTracker(10)
+	sender := NewHTTPRelaySender(server.Client(), WithRelayLatencyTracker(tracker))
+
+	// The latency of the successful relays is tracked.
+	delay = 50 * time.Millisecond
+	_, err := sender(context.Background(), endpoint, &servicetypes.RelayRequest{})
+	require.NoError(t, err)
+	p95, ok := tracker.P95(endpoint)
+	require.True(t, ok)
+	require.GreaterOrEqual(t, p95, delay)
+
+	// A relay timing out while waiting for the supplier reports how its budget
+	// was consumed, and the endpoint's P95 above the budget.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = sender(ctx, endpoint, &servicetypes.RelayRequest{})
+	require.ErrorIs(t, err, sdkerrors.ErrRelayTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var timeoutErr *RelayTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	require.Equal(t, SupplierAddress("pokt1supplier"), timeoutErr.Supplier)
+	require.Equal(t, RelayPhaseWait, timeoutErr.Phase)
+	require.LessOrEqual(t, timeoutErr.Budget, 10*time.Millisecond)
+	require.Positive(t, timeoutErr.Phases.Wait)
+	require.Zero(t, timeoutErr.Phases.Read)
+	require.Equal(t, p95, timeoutErr.EndpointP95)
+	require.True(t, timeoutErr.BudgetBelowP95())
+	require.ErrorContains(t, err, "timeout is likely too aggressive")
+}
+
+func TestRelayLatencyTracker_P95(t *testing.T) {
+	endpoint := NewEndpoint(sessiontypes.SessionHeader{}, sharedtypes.SupplierEndpoint{Url: "http://supplier"}, SupplierInfo{})
+	tracker := NewRelayLatencyTracker(20)
+
+	_, ok := tracker.P95(endpoint)
+	require.False(t, ok)
+
+	for i := 1; i <= 40; i++ {
+		tracker.Observe(endpoint, time.Duration(i)*time.Millisecond)
+	}
+
+	// Only the latest 20 latencies, i.e. 21ms to 40ms, are kept.
+	p95, ok := tracker.P95(endpoint)
+	require.True(t, ok)
+	require.Equal(t, 39*time.Millisecond, p95)
+}
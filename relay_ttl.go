@@ -0,0 +1,104 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// ErrRelayExpired is returned when a relay is not sent, or not retried, because
+// its TTL elapsed.
+var ErrRelayExpired = sdkerrors.ErrRelayExpired
+
+// relayStartKey is the context key of the start time of a relay.
+type relayStartKey struct{}
+
+// ContextWithRelayStart returns a copy of the given context carrying the given
+// start time of a relay, e.g. the time at which the end client's request was
+// received, from which the TTL of the relay is measured.
+func ContextWithRelayStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, relayStartKey{}, start)
+}
+
+// RelayStartFromContext returns the start time of the relay carried by the
+// given context, and a boolean indicating whether it carries one.
+func RelayStartFromContext(ctx context.Context) (time.Time, bool) {
+	start, ok := ctx.Value(relayStartKey{}).(time.Time)
+	return start, ok
+}
+
+// RelayExpiredError is returned by the GatewayClient when a relay's TTL elapsed
+// before it succeeded, so it is not sent, or not retried, even if retries
+// remain: the end client has likely given up on it, and a late response would
+// only consume the supplier's and the gateway's resources.
+// It matches sdkerrors.ErrRelayExpired using errors.Is.
+type RelayExpiredError struct {
+	// TTL is the time allowed for the relay, including its retries.
+	TTL time.Duration
+	// Elapsed is the time elapsed since the start of the relay.
+	Elapsed time.Duration
+	// Attempts is the number of attempts sent before the TTL elapsed.
+	Attempts int
+	// Err is the error of the last attempt, if any.
+	Err error
+}
+
+// Error returns a description of the expiry, including the last attempt's error.
+func (e *RelayExpiredError) Error() string {
+	msg := fmt.Sprintf("relay TTL of %s expired after %s and %d attempt(s)", e.TTL, e.Elapsed, e.Attempts)
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+// Unwrap returns sdkerrors.ErrRelayExpired and the last attempt's error, if any.
+func (e *RelayExpiredError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{sdkerrors.ErrRelayExpired}
+	}
+	return []error{sdkerrors.ErrRelayExpired, e.Err}
+}
+
+// relayTTL enforces the TTL of a relay, measured from its start time.
+// A zero TTL is never exceeded.
+type relayTTL struct {
+	ttl   time.Duration
+	start time.Time
+	now   func() time.Time
+}
+
+// newRelayTTL returns the relayTTL of the relay of the given context, starting
+// at the time carried by the context, if any, and now otherwise.
+func newRelayTTL(ctx context.Context, ttl time.Duration) relayTTL {
+	start, ok := RelayStartFromContext(ctx)
+	if !ok {
+		start = time.Now()
+	}
+	return relayTTL{ttl: ttl, start: start, now: time.Now}
+}
+
+// context returns a copy of the given context which is canceled once the TTL
+// elapses, so the attempt in flight is abandoned.
+func (t relayTTL) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t.ttl <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, t.start.Add(t.ttl))
+}
+
+// expiredError returns a *RelayExpiredError wrapping the given error of the
+// last attempt if the TTL elapsed, and nil otherwise.
+func (t relayTTL) expiredError(attempts int, lastErr error) error {
+	if t.ttl <= 0 {
+		return nil
+	}
+
+	elapsed := t.now().Sub(t.start)
+	if elapsed < t.ttl {
+		return nil
+	}
+	return &RelayExpiredError{TTL: t.ttl, Elapsed: elapsed, Attempts: attempts, Err: lastErr}
+}
@@ -0,0 +1,82 @@
+package sdk
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// SupplierRelayResponse is a serialized relay response along with the supplier
+// which sent it, to be validated by RelayResponseValidator.ValidateBatch.
+type SupplierRelayResponse struct {
+	Supplier        SupplierAddress
+	RelayResponseBz []byte
+}
+
+// BatchValidationResult is the result of the validation of a relay response by
+// RelayResponseValidator.ValidateBatch.
+type BatchValidationResult struct {
+	ValidatedRelayResponse
+
+	// Err is the error returned by the validation of the relay response, if any.
+	Err error
+}
+
+// ValidateBatch validates the given relay responses and verifies their suppliers'
+// signatures, across at most VerificationParallelism goroutines, so the CPU cost
+// of the verification of many responses, e.g. the responses of a relay fanned out
+// to several suppliers, is spread across cores.
+//
+// The supplier signatures are secp256k1 signatures, which do not support batch
+// verification: the batch is verified in parallel instead, fetching the public
+// key of each distinct supplier only once.
+//
+// The returned results are in the order of the given responses. The responses
+// which were not validated once the given context is done fail with the
+// context's error.
+func (v RelayResponseValidator) ValidateBatch(
+	ctx context.Context,
+	responses []SupplierRelayResponse,
+) []BatchValidationResult {
+	results := make([]BatchValidationResult, len(responses))
+
+	batchValidator := v
+	if !v.TrustSuppliers && v.PublicKeyFetcher != nil {
+		batchValidator.PublicKeyFetcher = NewCachedAccountClient(v.PublicKeyFetcher)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(v.verificationParallelism(), len(responses)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+
+				validatedResponse, err := batchValidator.Validate(ctx, responses[i].Supplier, responses[i].RelayResponseBz)
+				results[i] = BatchValidationResult{ValidatedRelayResponse: validatedResponse, Err: err}
+			}
+		}()
+	}
+
+	for i := range responses {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// verificationParallelism returns the maximum number of relay responses
+// validated in parallel by ValidateBatch.
+func (v RelayResponseValidator) verificationParallelism() int {
+	if v.VerificationParallelism > 0 {
+		return v.VerificationParallelism
+	}
+	return runtime.GOMAXPROCS(0)
+}
@@ -0,0 +1,95 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestRelayResponseValidator_ValidateBatch(t *testing.T) {
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, secp256k1.GenPrivKey().PubKey())
+	require.NoError(t, err)
+
+	fetcher := &countingPubKeyFetcher{pubKeys: make(map[string]cryptotypes.PubKey)}
+	var suppliers []SupplierAddress
+	supplierKeys := make(map[SupplierAddress]*secp256k1.PrivKey)
+	for range 3 {
+		supplierKey := secp256k1.GenPrivKey()
+		address, err := PubKeyToAddress(PoktAddressPrefix, supplierKey.PubKey())
+		require.NoError(t, err)
+
+		supplier := SupplierAddress(address)
+		suppliers = append(suppliers, supplier)
+		supplierKeys[supplier] = supplierKey
+		fetcher.pubKeys[address] = supplierKey.PubKey()
+	}
+
+	signedResponse := func(supplier SupplierAddress, payload string) []byte {
+		relayResponse := &servicetypes.RelayResponse{
+			Meta: servicetypes.RelayResponseMetadata{
+				SessionHeader: &sessiontypes.SessionHeader{
+					ApplicationAddress:      appAddress,
+					ServiceId:               "svc1",
+					SessionId:               "session1",
+					SessionStartBlockHeight: 1,
+					SessionEndBlockHeight:   4,
+				},
+			},
+			Payload: []byte(payload),
+		}
+		signableBz, err := relayResponse.GetSignableBytesHash()
+		require.NoError(t, err)
+		relayResponse.Meta.SupplierOperatorSignature, err = supplierKeys[supplier].Sign(signableBz[:])
+		require.NoError(t, err)
+
+		relayResponseBz, err := relayResponse.Marshal()
+		require.NoError(t, err)
+		return relayResponseBz
+	}
+
+	var responses []SupplierRelayResponse
+	for i := range 30 {
+		supplier := suppliers[i%3]
+		responses = append(responses, SupplierRelayResponse{
+			Supplier:        supplier,
+			RelayResponseBz: signedResponse(supplier, fmt.Sprintf("payload%d", i)),
+		})
+	}
+	// A response signed by another supplier, and an undecodable response.
+	responses[10].Supplier = suppliers[2]
+	responses[20].RelayResponseBz = []byte("not a relay response")
+
+	validator := RelayResponseValidator{PublicKeyFetcher: fetcher, VerificationParallelism: 4}
+	results := validator.ValidateBatch(context.Background(), responses)
+	require.Len(t, results, len(responses))
+
+	for i, result := range results {
+		switch i {
+		case 10:
+			require.ErrorIs(t, result.Err, sdkerrors.ErrInvalidSupplierSignature)
+		case 20:
+			require.ErrorIs(t, result.Err, sdkerrors.ErrInvalidRelayResponse)
+		default:
+			require.NoError(t, result.Err)
+			require.True(t, result.SignatureVerified)
+			require.Equal(t, []byte(fmt.Sprintf("payload%d", i)), result.Payload)
+		}
+	}
+	// The public key of each supplier is fetched once.
+	require.Equal(t, int64(3), fetcher.calls.Load())
+
+	// The responses are not validated once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, result := range validator.ValidateBatch(ctx, responses) {
+		require.ErrorIs(t, result.Err, context.Canceled)
+	}
+}
@@ -0,0 +1,146 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// RequestTransform rewrites a client request before it is relayed, e.g. to
+// adapt it to the protocol expected by a service's backends.
+// The request is modified in place.
+type RequestTransform func(poktRequest *types.POKTHTTPRequest) error
+
+// RequestTransformer applies per-service RequestTransforms to the serialized
+// client requests, before they are embedded into a RelayRequest and signed.
+// It is safe for concurrent use.
+type RequestTransformer struct {
+	mu         sync.RWMutex
+	transforms map[string][]RequestTransform
+}
+
+// NewRequestTransformer returns a RequestTransformer with no registered transforms.
+func NewRequestTransformer() *RequestTransformer {
+	return &RequestTransformer{
+		transforms: make(map[string][]RequestTransform),
+	}
+}
+
+// Register adds the given transforms to the ones applied to the requests of the
+// given service. Transforms are applied in the order they are registered.
+func (rt *RequestTransformer) Register(serviceId string, transforms ...RequestTransform) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	rt.transforms[serviceId] = append(rt.transforms[serviceId], transforms...)
+}
+
+// Transform applies the transforms registered for the given service to the
+// given serialized POKTHTTPRequest, and returns the re-serialized request.
+// The framing headers of the transformed request, e.g. Content-Length, are fixed
+// up using POKTHTTPRequest.FixFramingHeaders.
+// The request is returned unchanged if no transforms are registered for the service.
+func (rt *RequestTransformer) Transform(serviceId string, requestBz []byte) ([]byte, error) {
+	rt.mu.RLock()
+	transforms := rt.transforms[serviceId]
+	rt.mu.RUnlock()
+
+	if len(transforms) == 0 {
+		return requestBz, nil
+	}
+
+	poktRequest, err := types.DeserializeHTTPRequest(requestBz)
+	if err != nil {
+		return nil, fmt.Errorf("Transform: error deserializing request of service %s: %w", serviceId, err)
+	}
+
+	for _, transform := range transforms {
+		if err := transform(poktRequest); err != nil {
+			return nil, fmt.Errorf("Transform: error transforming request of service %s: %w", serviceId, err)
+		}
+	}
+	poktRequest.FixFramingHeaders()
+
+	// Use deterministic marshalling, consistently with types.SerializeHTTPRequest.
+	transformedBz, err := proto.MarshalOptions{Deterministic: true}.Marshal(poktRequest)
+	if err != nil {
+		return nil, fmt.Errorf("Transform: error serializing request of service %s: %w", serviceId, err)
+	}
+
+	return transformedBz, nil
+}
+
+// BuildRelayRequest transforms the given serialized request using the transforms
+// registered for the endpoint's service, and builds a RelayRequest from it.
+// It is a drop-in replacement for the package-level BuildRelayRequest.
+func (rt *RequestTransformer) BuildRelayRequest(
+	endpoint Endpoint,
+	requestBz []byte,
+) (*servicetypes.RelayRequest, error) {
+	if endpoint == nil {
+		return nil, errors.New("BuildRelayRequest: endpoint not specified")
+	}
+
+	transformedBz, err := rt.Transform(endpoint.Header().ServiceId, requestBz)
+	if err != nil {
+		return nil, fmt.Errorf("BuildRelayRequest: %w", err)
+	}
+
+	return BuildRelayRequest(endpoint, transformedBz)
+}
+
+// WithPathPrefix returns a RequestTransform which prepends the given prefix to
+// the request path, e.g. to inject an API version path such as "/v1".
+// The leading and trailing slashes of the prefix are ignored, so an empty prefix
+// leaves the request unchanged.
+func WithPathPrefix(prefix string) RequestTransform {
+	prefix = strings.Trim(prefix, "/")
+	return func(poktRequest *types.POKTHTTPRequest) error {
+		if prefix == "" {
+			return nil
+		}
+
+		requestURL, err := url.Parse(poktRequest.Url)
+		if err != nil {
+			return fmt.Errorf("error parsing request URL: %w", err)
+		}
+
+		requestURL.Path = "/" + prefix + "/" + strings.TrimPrefix(requestURL.Path, "/")
+		requestURL.RawPath = ""
+		poktRequest.Url = requestURL.String()
+		return nil
+	}
+}
+
+// WithRequestHeader returns a RequestTransform which sets the given header of
+// the request, replacing any existing values.
+func WithRequestHeader(key, value string) RequestTransform {
+	return func(poktRequest *types.POKTHTTPRequest) error {
+		if poktRequest.Header == nil {
+			poktRequest.Header = map[string]*types.Header{}
+		}
+		poktRequest.Header[key] = &types.Header{Key: key, Values: []string{value}}
+		return nil
+	}
+}
+
+// WithBodyRewrite returns a RequestTransform which replaces the request body
+// with the result of the given function, e.g. to wrap or unwrap JSON-RPC
+// envelopes, or to strip disallowed params.
+func WithBodyRewrite(rewrite func(body []byte) ([]byte, error)) RequestTransform {
+	return func(poktRequest *types.POKTHTTPRequest) error {
+		body, err := rewrite(poktRequest.BodyBz)
+		if err != nil {
+			return fmt.Errorf("error rewriting request body: %w", err)
+		}
+		poktRequest.BodyBz = body
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestRequestTransformer_Transform(t *testing.T) {
+	requestBz, err := proto.MarshalOptions{Deterministic: true}.Marshal(&types.POKTHTTPRequest{
+		Method: http.MethodPost,
+		Url:    "/blocks/latest?format=json",
+		BodyBz: []byte(`{"method":"status"}`),
+	})
+	require.NoError(t, err)
+
+	rt := NewRequestTransformer()
+	rt.Register("rest-service",
+		WithPathPrefix("/v1/"),
+		WithRequestHeader("X-Api-Version", "1"),
+		WithBodyRewrite(func(body []byte) ([]byte, error) {
+			return bytes.ToUpper(body), nil
+		}),
+	)
+	rt.Register("failing-service", WithBodyRewrite(func([]byte) ([]byte, error) {
+		return nil, errors.New("disallowed param")
+	}))
+
+	// Requests of services without transforms are returned unchanged.
+	untransformedBz, err := rt.Transform("other-service", requestBz)
+	require.NoError(t, err)
+	require.Equal(t, requestBz, untransformedBz)
+
+	transformedBz, err := rt.Transform("rest-service", requestBz)
+	require.NoError(t, err)
+
+	transformed, err := types.DeserializeHTTPRequest(transformedBz)
+	require.NoError(t, err)
+	require.Equal(t, "/v1/blocks/latest?format=json", transformed.Url)
+	require.Equal(t, []string{"1"}, transformed.Header["X-Api-Version"].Values)
+	require.Equal(t, []byte(`{"METHOD":"STATUS"}`), transformed.BodyBz)
+	require.Equal(t, http.MethodPost, transformed.Method)
+	// The framing headers match the transformed body.
+	require.Equal(t, []string{"19"}, transformed.Header["Content-Length"].Values)
+
+	_, err = rt.Transform("failing-service", requestBz)
+	require.ErrorContains(t, err, "disallowed param")
+}
+
+func TestWithPathPrefix(t *testing.T) {
+	tests := []struct {
+		prefix      string
+		url         string
+		expectedURL string
+	}{
+		{prefix: "/v1", url: "/blocks", expectedURL: "/v1/blocks"},
+		{prefix: "v1/", url: "blocks", expectedURL: "/v1/blocks"},
+		{prefix: "/api/v1/", url: "/blocks?height=1", expectedURL: "/api/v1/blocks?height=1"},
+		{prefix: "/v1", url: "https://backend.example/", expectedURL: "https://backend.example/v1/"},
+		{prefix: "", url: "/blocks", expectedURL: "/blocks"},
+		{prefix: "/", url: "/blocks", expectedURL: "/blocks"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.prefix+test.url, func(t *testing.T) {
+			poktRequest := &types.POKTHTTPRequest{Url: test.url}
+			require.NoError(t, WithPathPrefix(test.prefix)(poktRequest))
+			require.Equal(t, test.expectedURL, poktRequest.Url)
+		})
+	}
+}
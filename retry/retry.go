@@ -0,0 +1,176 @@
+// Package retry implements context-aware retry and backoff utilities.
+//
+// It is used by the SDK's clients when interacting with POKT full nodes and
+// suppliers, and is exported so that SDK consumers can reuse the same policies.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policy specifies the delay to wait for before each retry attempt.
+type Policy interface {
+	// Delay returns the delay before the given retry attempt.
+	// The attempt argument starts at 1 for the first retry.
+	Delay(attempt int) time.Duration
+}
+
+// Predicate reports whether the given error should be retried.
+type Predicate func(error) bool
+
+// Config specifies how an operation should be retried.
+type Config struct {
+	// Policy specifies the delay between attempts.
+	Policy Policy `yaml:"-"`
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// A value lower than 1 is treated as a single attempt, i.e. no retries.
+	MaxAttempts int `yaml:"max_attempts"`
+	// ShouldRetry reports whether an error is retryable.
+	// All errors are retried if ShouldRetry is not set.
+	ShouldRetry Predicate `yaml:"-"`
+}
+
+// Do calls fn until it succeeds, the error returned by fn is not retryable, the
+// maximum number of attempts is reached, or the context is done.
+// The error returned by the last attempt is returned if all attempts fail.
+func Do(ctx context.Context, config Config, fn func(context.Context) error) error {
+	maxAttempts := max(config.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		if attempt >= maxAttempts {
+			return err
+		}
+
+		if config.ShouldRetry != nil && !config.ShouldRetry(err) {
+			return err
+		}
+
+		var delay time.Duration
+		if config.Policy != nil {
+			delay = config.Policy.Delay(attempt)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry: context done after %d attempt(s): %w", attempt, errors.Join(ctx.Err(), err))
+		case <-timer.C:
+		}
+	}
+}
+
+// Constant is a Policy that waits for the same delay before every retry.
+type Constant struct {
+	Interval time.Duration
+}
+
+// Delay returns the constant interval, regardless of the attempt.
+func (c Constant) Delay(int) time.Duration {
+	return c.Interval
+}
+
+// Exponential is a Policy that doubles the delay on every retry, starting at
+// Initial and capped at Max.
+// If Jitter is set, a random delay of up to the computed one is used instead,
+// i.e. "full jitter", to avoid synchronized retries from multiple clients.
+type Exponential struct {
+	Initial time.Duration
+	Max     time.Duration
+	Jitter  bool
+}
+
+// Delay returns the exponentially increasing delay for the given attempt.
+func (e Exponential) Delay(attempt int) time.Duration {
+	delay := e.Initial
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+
+	if e.Max > 0 && delay > e.Max {
+		delay = e.Max
+	}
+
+	if e.Jitter && delay > 0 {
+		delay = rand.N(delay + 1)
+	}
+
+	return delay
+}
+
+// Fibonacci is a Policy whose delays follow the Fibonacci sequence, using
+// Initial as the unit and capped at Max.
+type Fibonacci struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Delay returns the Fibonacci delay for the given attempt.
+func (f Fibonacci) Delay(attempt int) time.Duration {
+	prev, curr := time.Duration(0), f.Initial
+	for i := 1; i < attempt; i++ {
+		// Saturate instead of overflowing when the delay is not capped.
+		if prev > math.MaxInt64-curr {
+			curr = math.MaxInt64
+			break
+		}
+		prev, curr = curr, prev+curr
+		if f.Max > 0 && curr >= f.Max {
+			return f.Max
+		}
+	}
+
+	if f.Max > 0 && curr > f.Max {
+		return f.Max
+	}
+
+	return curr
+}
+
+// IsRetryableGRPCError reports whether the given error is a gRPC error whose
+// status code indicates a transient failure of the full node.
+func IsRetryableGRPCError(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNotContextError reports whether the given error is not caused by a
+// canceled context or an exceeded context deadline.
+func IsNotContextError(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// Any returns a Predicate that reports whether any of the given predicates
+// considers the error retryable.
+func Any(predicates ...Predicate) Predicate {
+	return func(err error) bool {
+		for _, predicate := range predicates {
+			if predicate(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,130 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+)
+
+var errTransient = errors.New("transient error")
+
+func TestRetry_Do(t *testing.T) {
+	tests := []struct {
+		desc             string
+		config           retry.Config
+		failures         int
+		expectedAttempts int
+		expectErr        bool
+	}{
+		{
+			desc:             "succeeds on first attempt",
+			config:           retry.Config{MaxAttempts: 3},
+			failures:         0,
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "succeeds after retries",
+			config:           retry.Config{MaxAttempts: 3},
+			failures:         2,
+			expectedAttempts: 3,
+		},
+		{
+			desc:             "fails after max attempts",
+			config:           retry.Config{MaxAttempts: 3},
+			failures:         5,
+			expectedAttempts: 3,
+			expectErr:        true,
+		},
+		{
+			desc: "does not retry non-retryable errors",
+			config: retry.Config{
+				MaxAttempts: 3,
+				ShouldRetry: func(error) bool { return false },
+			},
+			failures:         5,
+			expectedAttempts: 1,
+			expectErr:        true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			attempts := 0
+			err := retry.Do(context.Background(), test.config, func(context.Context) error {
+				attempts++
+				if attempts <= test.failures {
+					return errTransient
+				}
+				return nil
+			})
+
+			require.Equal(t, test.expectedAttempts, attempts)
+			if test.expectErr {
+				require.ErrorIs(t, err, errTransient)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRetry_Do_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	config := retry.Config{
+		Policy:      retry.Constant{Interval: time.Hour},
+		MaxAttempts: 3,
+	}
+	err := retry.Do(ctx, config, func(context.Context) error { return errTransient })
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, errTransient)
+}
+
+func TestRetry_Policies(t *testing.T) {
+	exponential := retry.Exponential{Initial: time.Second, Max: 5 * time.Second}
+	require.Equal(t, time.Second, exponential.Delay(1))
+	require.Equal(t, 2*time.Second, exponential.Delay(2))
+	require.Equal(t, 4*time.Second, exponential.Delay(3))
+	require.Equal(t, 5*time.Second, exponential.Delay(4))
+	require.Equal(t, 5*time.Second, exponential.Delay(100))
+
+	jittered := retry.Exponential{Initial: time.Second, Max: 5 * time.Second, Jitter: true}
+	require.LessOrEqual(t, jittered.Delay(3), 4*time.Second)
+
+	fibonacci := retry.Fibonacci{Initial: time.Second, Max: 10 * time.Second}
+	require.Equal(t, time.Second, fibonacci.Delay(1))
+	require.Equal(t, time.Second, fibonacci.Delay(2))
+	require.Equal(t, 2*time.Second, fibonacci.Delay(3))
+	require.Equal(t, 3*time.Second, fibonacci.Delay(4))
+	require.Equal(t, 5*time.Second, fibonacci.Delay(5))
+	require.Equal(t, 10*time.Second, fibonacci.Delay(10))
+
+	// The uncapped delays saturate instead of overflowing.
+	uncapped := retry.Fibonacci{Initial: time.Second}
+	require.Equal(t, time.Duration(math.MaxInt64), uncapped.Delay(200))
+	require.Positive(t, retry.Exponential{Initial: time.Second}.Delay(200))
+
+	require.Equal(t, time.Second, retry.Constant{Interval: time.Second}.Delay(7))
+}
+
+func TestRetry_Predicates(t *testing.T) {
+	require.True(t, retry.IsRetryableGRPCError(status.Error(codes.Unavailable, "unavailable")))
+	require.False(t, retry.IsRetryableGRPCError(status.Error(codes.NotFound, "not found")))
+	require.False(t, retry.IsRetryableGRPCError(errTransient))
+
+	require.False(t, retry.IsNotContextError(context.Canceled))
+	require.True(t, retry.IsNotContextError(errTransient))
+
+	anyPredicate := retry.Any(retry.IsRetryableGRPCError, func(err error) bool { return errors.Is(err, errTransient) })
+	require.True(t, anyPredicate(errTransient))
+	require.False(t, anyPredicate(errors.New("other")))
+}
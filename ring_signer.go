@@ -0,0 +1,277 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/pokt-network/poktroll/pkg/crypto/rings"
+	"github.com/pokt-network/ring-go"
+
+	"github.com/pokt-network/shannon-sdk/crypto"
+)
+
+// maxRemoteSignerErrorBodySize is the maximum number of bytes of the body of a
+// remote signer's error response included in the returned error.
+const maxRemoteSignerErrorBodySize = 512
+
+// RingSigner produces the ring signatures of the relay requests, using a key
+// which does not need to be set in the gateway's configuration, e.g. held by a
+// keyring or by a remote signing service.
+//
+// Ring signatures can not be produced from a plain secp256k1 signature, so the
+// RingSigner is given the public keys of the application's ring members, and
+// returns the serialized ring signature.
+type RingSigner interface {
+	// Address returns the address of the signing key, which must be a member of
+	// the rings it signs with.
+	Address() string
+	// SignRing returns the serialized ring signature of the given signable bytes
+	// hash, using the ring of the given public keys.
+	SignRing(ctx context.Context, ringPubKeys []cryptotypes.PubKey, signableBz [32]byte) ([]byte, error)
+}
+
+// NewHexKeyRingSigner returns a RingSigner using the given hex-encoded
+// secp256k1 private key, held in memory.
+func NewHexKeyRingSigner(privateKeyHex string) (RingSigner, error) {
+	privKey, err := crypto.PrivateKeyFromHex(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("NewHexKeyRingSigner: %w", err)
+	}
+
+	return newPrivKeyRingSigner(privKey)
+}
+
+// NewKeyringRingSigner returns a RingSigner using the key with the given name
+// of the given Cosmos keyring, e.g. using the os, file or test backend, so the
+// private key does not have to be set in the gateway's configuration.
+//
+// The key is exported once from the keyring, and then held in memory.
+func NewKeyringRingSigner(kr keyring.Keyring, keyName string) (RingSigner, error) {
+	// The key is only exported to be unarmored right away: a random passphrase
+	// is used so the armored key can not be decrypted by anyone else.
+	passphraseBz := make([]byte, 32)
+	if _, err := rand.Read(passphraseBz); err != nil {
+		return nil, fmt.Errorf("NewKeyringRingSigner: error generating a passphrase: %w", err)
+	}
+	passphrase := hex.EncodeToString(passphraseBz)
+
+	armoredPrivKey, err := kr.ExportPrivKeyArmor(keyName, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("NewKeyringRingSigner: error exporting key %q: %w", keyName, err)
+	}
+
+	privKey, err := crypto.UnarmorPrivateKey(armoredPrivKey, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("NewKeyringRingSigner: error reading key %q: %w", keyName, err)
+	}
+
+	return newPrivKeyRingSigner(privKey)
+}
+
+// newPrivKeyRingSigner returns a RingSigner using the given private key.
+func newPrivKeyRingSigner(privKey *secp256k1.PrivKey) (*privKeyRingSigner, error) {
+	address, err := crypto.Address(PoktAddressPrefix, privKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return &privKeyRingSigner{address: address, privKey: privKey}, nil
+}
+
+// privKeyRingSigner is a RingSigner using a private key held in memory.
+type privKeyRingSigner struct {
+	address string
+	privKey *secp256k1.PrivKey
+}
+
+// Address returns the address of the private key.
+func (s *privKeyRingSigner) Address() string {
+	return s.address
+}
+
+// SignRing signs the given signable bytes hash using the ring of the given
+// public keys.
+func (s *privKeyRingSigner) SignRing(
+	_ context.Context,
+	ringPubKeys []cryptotypes.PubKey,
+	signableBz [32]byte,
+) ([]byte, error) {
+	sessionRing, err := rings.GetRingFromPubKeys(ringPubKeys)
+	if err != nil {
+		return nil, fmt.Errorf("SignRing: error building the ring: %w", err)
+	}
+
+	signerPrivKey, err := ring.Secp256k1().DecodeToScalar(s.privKey.Key)
+	if err != nil {
+		return nil, fmt.Errorf("SignRing: error decoding private key to a scalar: %w", err)
+	}
+
+	ringSig, err := sessionRing.Sign(signableBz, signerPrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("SignRing: %w", err)
+	}
+
+	return ringSig.Serialize()
+}
+
+// RemoteRingSignerOption configures a remote RingSigner.
+type RemoteRingSignerOption func(*remoteRingSigner)
+
+// WithRemoteSignerHTTPClient sets the HTTP client used to reach the remote
+// signer, e.g. configured with mTLS client certificates.
+// http.DefaultClient is used by default.
+func WithRemoteSignerHTTPClient(client *http.Client) RemoteRingSignerOption {
+	return func(s *remoteRingSigner) {
+		s.client = client
+	}
+}
+
+// WithRemoteSignerHeader sets a header sent with every signing request, e.g.
+// an authorization token.
+func WithRemoteSignerHeader(key, value string) RemoteRingSignerOption {
+	return func(s *remoteRingSigner) {
+		s.header.Set(key, value)
+	}
+}
+
+// RemoteRingSignRequest is the JSON body POSTed to a remote signer.
+type RemoteRingSignRequest struct {
+	// Address is the address of the key to sign with.
+	Address string `json:"address"`
+	// RingPublicKeys are the hex-encoded compressed secp256k1 public keys of
+	// the ring members, in order.
+	RingPublicKeys []string `json:"ring_public_keys"`
+	// SignableBytesHash is the hex-encoded hash to sign.
+	SignableBytesHash string `json:"signable_bytes_hash"`
+}
+
+// RemoteRingSignResponse is the JSON body returned by a remote signer.
+type RemoteRingSignResponse struct {
+	// Signature is the hex-encoded serialized ring signature.
+	Signature string `json:"signature"`
+}
+
+// NewRemoteRingSigner returns a RingSigner delegating the signing with the key
+// of the given address to the remote signer at the given URL, e.g. backed by a
+// hardware security module, so the private key never leaves the signer.
+//
+// A RemoteRingSignRequest is POSTed to the URL for every relay request, and a
+// RemoteRingSignResponse is expected in return. The returned signatures are
+// verified against the ring before being used.
+func NewRemoteRingSigner(url, address string, opts ...RemoteRingSignerOption) (RingSigner, error) {
+	if url == "" {
+		return nil, errors.New("NewRemoteRingSigner: URL not set")
+	}
+	if address == "" {
+		return nil, errors.New("NewRemoteRingSigner: address not set")
+	}
+
+	s := &remoteRingSigner{
+		url:     url,
+		address: address,
+		client:  http.DefaultClient,
+		header:  make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// remoteRingSigner is a RingSigner delegating the signing to a remote signer
+// over HTTP.
+type remoteRingSigner struct {
+	url     string
+	address string
+	client  *http.Client
+	header  http.Header
+}
+
+// Address returns the address of the remote signer's key.
+func (s *remoteRingSigner) Address() string {
+	return s.address
+}
+
+// SignRing requests the ring signature of the given signable bytes hash from
+// the remote signer.
+func (s *remoteRingSigner) SignRing(
+	ctx context.Context,
+	ringPubKeys []cryptotypes.PubKey,
+	signableBz [32]byte,
+) ([]byte, error) {
+	sessionRing, err := rings.GetRingFromPubKeys(ringPubKeys)
+	if err != nil {
+		return nil, fmt.Errorf("SignRing: error building the ring: %w", err)
+	}
+
+	signRequest := RemoteRingSignRequest{
+		Address:           s.address,
+		RingPublicKeys:    make([]string, 0, len(ringPubKeys)),
+		SignableBytesHash: hex.EncodeToString(signableBz[:]),
+	}
+	for _, pubKey := range ringPubKeys {
+		signRequest.RingPublicKeys = append(signRequest.RingPublicKeys, hex.EncodeToString(pubKey.Bytes()))
+	}
+
+	requestBz, err := json.Marshal(signRequest)
+	if err != nil {
+		return nil, fmt.Errorf("SignRing: error marshaling the signing request: %w", err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(requestBz))
+	if err != nil {
+		return nil, fmt.Errorf("SignRing: error building the signing request: %w", err)
+	}
+	for key, values := range s.header {
+		httpRequest.Header[key] = values
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := s.client.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("SignRing: error reaching the remote signer: %w", err)
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(httpResponse.Body, maxRemoteSignerErrorBodySize))
+		return nil, fmt.Errorf(
+			"SignRing: remote signer returned status %d: %s",
+			httpResponse.StatusCode,
+			bytes.TrimSpace(body),
+		)
+	}
+
+	var signResponse RemoteRingSignResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&signResponse); err != nil {
+		return nil, fmt.Errorf("SignRing: error decoding the remote signer response: %w", err)
+	}
+
+	signature, err := hex.DecodeString(signResponse.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("SignRing: error decoding the remote signature: %w", err)
+	}
+
+	// Check the signature before it is sent to the suppliers, which would
+	// otherwise reject the relays with opaque errors.
+	ringSig := new(ring.RingSig)
+	if err := ringSig.Deserialize(ring.Secp256k1(), signature); err != nil {
+		return nil, fmt.Errorf("SignRing: error deserializing the remote signature: %w", err)
+	}
+	if !ringSig.Ring().Equals(sessionRing) || !ringSig.Verify(signableBz) {
+		return nil, errors.New("SignRing: remote signature does not verify against the ring")
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/pokt-network/poktroll/pkg/crypto/rings"
+	"github.com/pokt-network/ring-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteRingSigner(t *testing.T) {
+	gatewayPrivKey := secp256k1.GenPrivKey()
+	localSigner, err := NewHexKeyRingSigner(hex.EncodeToString(gatewayPrivKey.Bytes()))
+	require.NoError(t, err)
+
+	ringPubKeys := []cryptotypes.PubKey{secp256k1.GenPrivKey().PubKey(), gatewayPrivKey.PubKey()}
+	signableBz := [32]byte{1, 2, 3}
+
+	// The remote signer signs using the in-memory signer, or returns a signature
+	// of another hash if tampering is enabled.
+	tamper := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		var signRequest RemoteRingSignRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&signRequest))
+		require.Equal(t, localSigner.Address(), signRequest.Address)
+		require.Equal(t, hex.EncodeToString(signableBz[:]), signRequest.SignableBytesHash)
+		require.Len(t, signRequest.RingPublicKeys, len(ringPubKeys))
+
+		hash := signableBz
+		if tamper {
+			hash = [32]byte{4, 5, 6}
+		}
+		signature, err := localSigner.SignRing(r.Context(), ringPubKeys, hash)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(w).Encode(RemoteRingSignResponse{Signature: hex.EncodeToString(signature)}))
+	}))
+	defer server.Close()
+
+	remoteSigner, err := NewRemoteRingSigner(
+		server.URL,
+		localSigner.Address(),
+		WithRemoteSignerHeader("Authorization", "Bearer token"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, localSigner.Address(), remoteSigner.Address())
+
+	signature, err := remoteSigner.SignRing(context.Background(), ringPubKeys, signableBz)
+	require.NoError(t, err)
+
+	sessionRing, err := rings.GetRingFromPubKeys(ringPubKeys)
+	require.NoError(t, err)
+	ringSig := new(ring.RingSig)
+	require.NoError(t, ringSig.Deserialize(ring.Secp256k1(), signature))
+	require.True(t, ringSig.Ring().Equals(sessionRing))
+	require.True(t, ringSig.Verify(signableBz))
+
+	// Signatures which do not verify are rejected.
+	tamper = true
+	_, err = remoteSigner.SignRing(context.Background(), ringPubKeys, signableBz)
+	require.ErrorContains(t, err, "does not verify")
+}
+
+func TestNewRemoteRingSigner_NotSet(t *testing.T) {
+	_, err := NewRemoteRingSigner("", "pokt1gateway")
+	require.Error(t, err)
+
+	_, err = NewRemoteRingSigner("http://localhost", "")
+	require.Error(t, err)
+}
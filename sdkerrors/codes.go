@@ -0,0 +1,77 @@
+package sdkerrors
+
+// The errors returned by the SDK.
+// New errors must be appended with a new code: existing codes must not change.
+var (
+	// ErrNotConfigured is returned when a dependency required by an SDK
+	// component, e.g. a full node fetcher, is not set.
+	ErrNotConfigured = New(1, CategoryConfig, "required dependency not configured")
+
+	// ErrSignerNotInRing is returned when the signer's address is not part of the
+	// application's ring, i.e. the application does not delegate to the signer.
+	ErrSignerNotInRing = New(2, CategoryProtocol, "signer is not in the application ring")
+	// ErrApplicationUnbonding is returned when an application which started
+	// unbonding is used to send relays.
+	ErrApplicationUnbonding = New(3, CategoryProtocol, "application is unbonding")
+
+	// ErrPathTraversal is returned when a relay request path attempts to escape
+	// the endpoint's base path.
+	ErrPathTraversal = New(4, CategoryRequest, "request path traversal is not allowed")
+	// ErrJSONRPCMethodNotAllowed is returned when a relay request calls a JSON-RPC
+	// method which is not allowed by the relay policy of the service.
+	ErrJSONRPCMethodNotAllowed = New(5, CategoryRequest, "JSON-RPC method not allowed")
+
+	// ErrInvalidRelayResponse is returned when a supplier's relay response can
+	// not be decoded or fails basic validation.
+	ErrInvalidRelayResponse = New(6, CategorySupplier, "invalid relay response")
+	// ErrInvalidSupplierSignature is returned when the signature of a supplier's
+	// relay response can not be verified.
+	ErrInvalidSupplierSignature = New(7, CategorySupplier, "invalid supplier signature")
+	// ErrRelayStreamStalled is returned when a supplier stops sending its
+	// response for longer than the configured stall timeout.
+	ErrRelayStreamStalled = New(8, CategorySupplier, "supplier response stream stalled")
+
+	// ErrRelayLoadShed is returned when a relay is rejected because the
+	// concurrency limit of its service is reached.
+	ErrRelayLoadShed = New(9, CategoryGateway, "relay rejected: service concurrency limit reached")
+
+	// ErrRelayTimeout is returned when a relay does not complete within its
+	// time budget.
+	ErrRelayTimeout = New(10, CategorySupplier, "relay timed out")
+
+	// ErrRelayResponseBasicValidation is returned when a supplier's relay response
+	// is decoded, but fails basic validation. It also matches ErrInvalidRelayResponse.
+	ErrRelayResponseBasicValidation = New(11, CategorySupplier, "relay response failed basic validation")
+
+	// ErrInvalidEndpoint is returned when a supplier endpoint can not be relayed
+	// to, e.g. because of a malformed URL or an unsupported URL scheme.
+	ErrInvalidEndpoint = New(12, CategorySupplier, "invalid supplier endpoint")
+
+	// ErrUnknownConfigField is returned when a configuration parsed in strict
+	// mode holds a field which does not match any setting, e.g. a typo'd one.
+	ErrUnknownConfigField = New(13, CategoryConfig, "unknown configuration field")
+
+	// ErrPubKeyAddressMismatch is returned when the public key fetched for an
+	// account does not derive to the account's address.
+	ErrPubKeyAddressMismatch = New(14, CategoryProtocol, "public key does not match address")
+
+	// ErrUnsupportedHashVersion is returned when none of the requested versions
+	// of the relays' signable bytes hashing scheme is supported.
+	ErrUnsupportedHashVersion = New(15, CategoryProtocol, "unsupported signable bytes hash version")
+
+	// ErrNodeRateLimited is returned when a request to a full node is not sent
+	// because the full node is rate limiting the SDK's requests.
+	ErrNodeRateLimited = New(16, CategoryGateway, "full node rate limit reached")
+
+	// ErrRelayExpired is returned when a relay is not sent, or not retried,
+	// because its TTL elapsed, e.g. since the end client gave up on it.
+	ErrRelayExpired = New(17, CategoryGateway, "relay TTL expired")
+
+	// ErrRelayResponseTooLarge is returned when a supplier's relay response
+	// exceeds the maximum response size of the relay transport.
+	ErrRelayResponseTooLarge = New(18, CategorySupplier, "relay response too large")
+
+	// ErrRelayChannelSessionEnded is returned when a relay channel, e.g. a
+	// WebSocket connection to a supplier, is closed because its session ended.
+	ErrRelayChannelSessionEnded = New(19, CategoryProtocol, "relay channel session ended")
+)
@@ -0,0 +1,114 @@
+// Package sdkerrors defines the errors returned by the SDK, each identified by
+// a stable numeric code and a category.
+//
+// Callers should match the SDK errors using errors.Is, or extract their code
+// using CodeOf, rather than relying on the error messages, which may change.
+// Errors wrapped using Wrap or Wrapf, or using fmt.Errorf with the %w verb,
+// keep matching the error they wrap.
+package sdkerrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code is the stable numeric code of an SDK error.
+// Codes are never reused, even if the error they identify is removed.
+type Code uint32
+
+// Category groups the SDK errors by the component responsible for them, e.g.
+// to decide whether a relay should be retried with another supplier.
+type Category string
+
+const (
+	// CategoryConfig is the category of the errors caused by an invalid or
+	// incomplete configuration of the SDK.
+	CategoryConfig Category = "config"
+	// CategoryRequest is the category of the errors caused by the client's request.
+	CategoryRequest Category = "request"
+	// CategoryProtocol is the category of the errors caused by the onchain state,
+	// e.g. an application which is unbonding or does not delegate to the gateway.
+	CategoryProtocol Category = "protocol"
+	// CategorySupplier is the category of the errors caused by a supplier.
+	CategorySupplier Category = "supplier"
+	// CategoryGateway is the category of the errors caused by the gateway's own
+	// limits, e.g. load shedding.
+	CategoryGateway Category = "gateway"
+)
+
+// Error is an SDK error, identified by its code.
+// Errors are compared by code, so an Error matches any error wrapping an Error
+// with the same code.
+type Error struct {
+	code     Code
+	category Category
+	message  string
+}
+
+// New returns an Error with the given code, category and message.
+// It is meant to define package-level sentinel errors.
+func New(code Code, category Category, message string) *Error {
+	return &Error{code: code, category: category, message: message}
+}
+
+// Error returns the message of the error.
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Code returns the code of the error.
+func (e *Error) Code() Code {
+	return e.code
+}
+
+// Category returns the category of the error.
+func (e *Error) Category() Category {
+	return e.category
+}
+
+// Is returns true if the target is an Error with the same code.
+func (e *Error) Is(target error) bool {
+	var targetErr *Error
+	if !errors.As(target, &targetErr) {
+		return false
+	}
+	return e.code == targetErr.code
+}
+
+// Wrap returns an error which prefixes the message of the given error with the
+// given message, and matches the given error using errors.Is and errors.As.
+// It returns nil if the given error is nil.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// Wrapf is the same as Wrap, with a message built from the given format and args.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return Wrap(err, fmt.Sprintf(format, args...))
+}
+
+// CodeOf returns the code of the first SDK error in the chain of the given error.
+// It returns false if the chain does not contain an SDK error.
+func CodeOf(err error) (Code, bool) {
+	var sdkErr *Error
+	if !errors.As(err, &sdkErr) {
+		return 0, false
+	}
+	return sdkErr.code, true
+}
+
+// CategoryOf returns the category of the first SDK error in the chain of the
+// given error. It returns false if the chain does not contain an SDK error.
+func CategoryOf(err error) (Category, bool) {
+	var sdkErr *Error
+	if !errors.As(err, &sdkErr) {
+		return "", false
+	}
+	return sdkErr.category, true
+}
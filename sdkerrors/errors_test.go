@@ -0,0 +1,77 @@
+package sdkerrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestError_Is(t *testing.T) {
+	wrapped := sdkerrors.Wrapf(sdkerrors.ErrSignerNotInRing, "Sign: signer %s", "pokt1signer")
+	require.ErrorIs(t, wrapped, sdkerrors.ErrSignerNotInRing)
+	require.NotErrorIs(t, wrapped, sdkerrors.ErrApplicationUnbonding)
+	require.Equal(t, "Sign: signer pokt1signer: signer is not in the application ring", wrapped.Error())
+
+	// Errors wrapped using fmt.Errorf, or joined, keep matching.
+	wrapped = fmt.Errorf("outer: %w", errors.Join(errors.New("other"), wrapped))
+	require.ErrorIs(t, wrapped, sdkerrors.ErrSignerNotInRing)
+
+	// Errors are matched by code, regardless of their message.
+	require.ErrorIs(t, wrapped, sdkerrors.New(sdkerrors.ErrSignerNotInRing.Code(), sdkerrors.CategoryProtocol, "renamed"))
+
+	require.Nil(t, sdkerrors.Wrap(nil, "no error"))
+}
+
+func TestError_As(t *testing.T) {
+	wrapped := fmt.Errorf("%w: %w", sdkerrors.ErrInvalidRelayResponse, errors.New("unexpected EOF"))
+
+	var sdkErr *sdkerrors.Error
+	require.ErrorAs(t, wrapped, &sdkErr)
+	require.Equal(t, sdkerrors.ErrInvalidRelayResponse.Code(), sdkErr.Code())
+
+	code, ok := sdkerrors.CodeOf(wrapped)
+	require.True(t, ok)
+	require.Equal(t, sdkerrors.ErrInvalidRelayResponse.Code(), code)
+
+	category, ok := sdkerrors.CategoryOf(wrapped)
+	require.True(t, ok)
+	require.Equal(t, sdkerrors.CategorySupplier, category)
+
+	_, ok = sdkerrors.CodeOf(errors.New("not an SDK error"))
+	require.False(t, ok)
+	_, ok = sdkerrors.CategoryOf(nil)
+	require.False(t, ok)
+}
+
+func TestError_StableCodes(t *testing.T) {
+	// The codes are part of the SDK's API: they must never change.
+	expectedCodes := map[*sdkerrors.Error]sdkerrors.Code{
+		sdkerrors.ErrNotConfigured:                1,
+		sdkerrors.ErrSignerNotInRing:              2,
+		sdkerrors.ErrApplicationUnbonding:         3,
+		sdkerrors.ErrPathTraversal:                4,
+		sdkerrors.ErrJSONRPCMethodNotAllowed:      5,
+		sdkerrors.ErrInvalidRelayResponse:         6,
+		sdkerrors.ErrInvalidSupplierSignature:     7,
+		sdkerrors.ErrRelayStreamStalled:           8,
+		sdkerrors.ErrRelayLoadShed:                9,
+		sdkerrors.ErrRelayTimeout:                 10,
+		sdkerrors.ErrRelayResponseBasicValidation: 11,
+		sdkerrors.ErrInvalidEndpoint:              12,
+		sdkerrors.ErrUnknownConfigField:           13,
+		sdkerrors.ErrPubKeyAddressMismatch:        14,
+		sdkerrors.ErrUnsupportedHashVersion:       15,
+		sdkerrors.ErrNodeRateLimited:              16,
+		sdkerrors.ErrRelayExpired:                 17,
+		sdkerrors.ErrRelayResponseTooLarge:        18,
+		sdkerrors.ErrRelayChannelSessionEnded:     19,
+	}
+
+	for sdkErr, expectedCode := range expectedCodes {
+		require.Equal(t, expectedCode, sdkErr.Code(), sdkErr.Error())
+	}
+}
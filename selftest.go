@@ -0,0 +1,201 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// defaultSelfTestRequestBody is the JSON-RPC request sent by the self-test to
+// the JSON-RPC services if no payload is specified.
+// eth_chainId is benign: it has no side effects and is cheap to serve.
+const defaultSelfTestRequestBody = `{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`
+
+// SelfTestService specifies a service to be checked by the self-test.
+type SelfTestService struct {
+	ServiceId string
+	// Application is the application used to sign the self-test relay of the service.
+	Application apptypes.Application
+	// RPCType is the RPC type of the service's endpoints to check, which selects
+	// the default Payload. Any endpoint is checked if it is not set, i.e.
+	// RPCType_UNKNOWN_RPC, using the default JSON-RPC payload.
+	RPCType sharedtypes.RPCType
+	// Payload is the serialized POKTHTTPRequest sent to the service.
+	// If Payload is empty, a JSON-RPC eth_chainId request is sent to the
+	// JSON-RPC services, and a GET request of the root path to the REST
+	// services. It must be set for the other RPC types, e.g. gRPC.
+	Payload []byte
+}
+
+// SelfTestResult is the report produced by the self-test for a single service.
+type SelfTestResult struct {
+	ServiceId       string
+	SupplierAddress SupplierAddress
+	Latency         time.Duration
+
+	// Err is set if any step of the self-test failed, e.g. fetching the session,
+	// signing the relay, sending it or validating the response.
+	// It wraps ErrApplicationUnbonding if the service's application is unbonding.
+	Err error
+
+	// SDK is the build of the SDK which ran the self-test.
+	SDK VersionInfo
+}
+
+// IsReady returns true if the self-test relay of the service succeeded.
+func (r SelfTestResult) IsReady() bool {
+	return r.Err == nil
+}
+
+// SelfTest sends a signed, benign relay to a single supplier of each configured
+// service and verifies the full validation path of the response.
+//
+// It is intended to be run once on startup, to report the readiness of each
+// service before the gateway starts accepting traffic.
+type SelfTest struct {
+	BlockClient      BlockQuerier
+	SessionClient    SessionQuerier
+	Signer           RelayRequestSigner
+	PublicKeyFetcher PublicKeyFetcher
+	SendRelay        RelaySender
+}
+
+// Run runs the self-test for all the given services, and returns one result per service.
+// The services are checked sequentially, in the order they are given.
+//
+// An error is only returned if the self-test could not be started, e.g. if the
+// latest block height could not be fetched.
+// Per-service failures are reported through the returned results.
+func (st *SelfTest) Run(ctx context.Context, services []SelfTestService) ([]SelfTestResult, error) {
+	if st.BlockClient == nil || st.SessionClient == nil || st.Signer == nil ||
+		st.PublicKeyFetcher == nil || st.SendRelay == nil {
+		return nil, errors.New("SelfTest: BlockClient, SessionClient, Signer, PublicKeyFetcher and SendRelay must all be set")
+	}
+
+	height, err := st.BlockClient.LatestBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("SelfTest: error getting the latest block height: %w", err)
+	}
+
+	results := make([]SelfTestResult, 0, len(services))
+	for _, service := range services {
+		results = append(results, st.runService(ctx, service, height))
+	}
+
+	return results, nil
+}
+
+// runService runs the self-test of a single service at the given height.
+func (st *SelfTest) runService(ctx context.Context, service SelfTestService, height int64) SelfTestResult {
+	result := SelfTestResult{ServiceId: service.ServiceId, SDK: Version()}
+
+	if IsApplicationUnbonding(service.Application) {
+		result.Err = fmt.Errorf("%w: application %s", ErrApplicationUnbonding, service.Application.Address)
+		return result
+	}
+
+	session, err := st.SessionClient.GetSession(ctx, service.Application.Address, service.ServiceId, height)
+	if err != nil {
+		result.Err = fmt.Errorf("error getting session: %w", err)
+		return result
+	}
+
+	sessionFilter := SessionFilter{Session: session}
+	if service.RPCType != sharedtypes.RPCType_UNKNOWN_RPC {
+		sessionFilter.EndpointFilters = []EndpointFilter{ByRPCType(service.RPCType)}
+	}
+	endpoints, err := sessionFilter.FilteredEndpoints()
+	if err != nil {
+		result.Err = fmt.Errorf("error getting session endpoints: %w", err)
+		return result
+	}
+	if len(endpoints) == 0 {
+		result.Err = fmt.Errorf("no endpoints in session %s", session.SessionId)
+		return result
+	}
+
+	endpoint := endpoints[0]
+	result.SupplierAddress = endpoint.Supplier()
+
+	payload := service.Payload
+	if len(payload) == 0 {
+		if payload, err = defaultSelfTestPayload(service.RPCType); err != nil {
+			result.Err = fmt.Errorf("error building the default payload: %w", err)
+			return result
+		}
+	}
+
+	relayRequest, err := BuildRelayRequest(endpoint, payload)
+	if err != nil {
+		result.Err = fmt.Errorf("error building relay request: %w", err)
+		return result
+	}
+
+	appRing := ApplicationRing{
+		Application:      service.Application,
+		PublicKeyFetcher: st.PublicKeyFetcher,
+	}
+	if relayRequest, err = st.Signer.Sign(ctx, relayRequest, appRing); err != nil {
+		result.Err = fmt.Errorf("error signing relay request: %w", err)
+		return result
+	}
+
+	startTime := time.Now()
+	relayResponseBz, err := st.SendRelay(ctx, endpoint, relayRequest)
+	result.Latency = time.Since(startTime)
+	if err != nil {
+		result.Err = fmt.Errorf("error sending relay to supplier %s: %w", endpoint.Supplier(), err)
+		return result
+	}
+
+	relayResponse, err := ValidateRelayResponse(ctx, endpoint.Supplier(), relayResponseBz, st.PublicKeyFetcher)
+	if err != nil {
+		result.Err = fmt.Errorf("error validating relay response of supplier %s: %w", endpoint.Supplier(), err)
+		return result
+	}
+
+	poktHTTPResponse, err := types.DeserializeHTTPResponse(relayResponse.Payload)
+	if err != nil {
+		result.Err = fmt.Errorf("error deserializing relay response payload: %w", err)
+		return result
+	}
+	if poktHTTPResponse.StatusCode < http.StatusOK || poktHTTPResponse.StatusCode >= http.StatusMultipleChoices {
+		result.Err = fmt.Errorf("unexpected relay response status code %d", poktHTTPResponse.StatusCode)
+		return result
+	}
+
+	return result
+}
+
+// defaultSelfTestPayload returns the serialized POKTHTTPRequest of the default
+// self-test request of the given RPC type.
+func defaultSelfTestPayload(rpcType sharedtypes.RPCType) ([]byte, error) {
+	var poktHTTPRequest *types.POKTHTTPRequest
+	switch rpcType {
+	case sharedtypes.RPCType_UNKNOWN_RPC, sharedtypes.RPCType_JSON_RPC:
+		poktHTTPRequest = &types.POKTHTTPRequest{
+			Method: http.MethodPost,
+			Header: map[string]*types.Header{
+				"Content-Type": {Key: "Content-Type", Values: []string{"application/json"}},
+			},
+			BodyBz: []byte(defaultSelfTestRequestBody),
+		}
+	case sharedtypes.RPCType_REST:
+		poktHTTPRequest = &types.POKTHTTPRequest{
+			Method: http.MethodGet,
+			Url:    "/",
+		}
+	default:
+		return nil, fmt.Errorf("no default payload for RPC type %s: the service's Payload must be set", rpcType)
+	}
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(poktHTTPRequest)
+}
@@ -0,0 +1,151 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestSelfTest_DefaultPayload(t *testing.T) {
+	payload, err := defaultSelfTestPayload(sharedtypes.RPCType_JSON_RPC)
+	require.NoError(t, err)
+
+	poktHTTPRequest, err := types.DeserializeHTTPRequest(payload)
+	require.NoError(t, err)
+
+	method, ok := poktHTTPRequest.GetJSONRPCMethod()
+	require.True(t, ok)
+	require.Equal(t, "eth_chainId", method)
+
+	payload, err = defaultSelfTestPayload(sharedtypes.RPCType_REST)
+	require.NoError(t, err)
+	poktHTTPRequest, err = types.DeserializeHTTPRequest(payload)
+	require.NoError(t, err)
+	require.Equal(t, http.MethodGet, poktHTTPRequest.Method)
+
+	_, err = defaultSelfTestPayload(sharedtypes.RPCType_GRPC)
+	require.Error(t, err)
+}
+
+func TestSelfTest_Run(t *testing.T) {
+	appKey := secp256k1.GenPrivKey()
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, appKey.PubKey())
+	require.NoError(t, err)
+	supplierKey := secp256k1.GenPrivKey()
+
+	header := &sessiontypes.SessionHeader{
+		ApplicationAddress:      appAddress,
+		ServiceId:               "svc1",
+		SessionId:               "session1",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   4,
+	}
+	app := apptypes.Application{Address: appAddress}
+	sessionQuerier := &fakeSelfTestSessionQuerier{session: &sessiontypes.Session{
+		Header:      header,
+		SessionId:   "session1",
+		Application: &app,
+		Suppliers: []*sharedtypes.Supplier{{
+			OperatorAddress: "pokt1supplier",
+			Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{
+					{Url: "https://grpc.example", RpcType: sharedtypes.RPCType_GRPC},
+					{Url: "https://rest.example", RpcType: sharedtypes.RPCType_REST},
+				},
+			}},
+		}},
+	}}
+
+	// The fake supplier endpoint records the probes it receives, and serves them
+	// with a signed, successful response.
+	var probedURL, probedMethod string
+	sendRelay := func(_ context.Context, endpoint Endpoint, relayRequest *servicetypes.RelayRequest) ([]byte, error) {
+		poktHTTPRequest, err := types.DeserializeHTTPRequest(relayRequest.Payload)
+		require.NoError(t, err)
+		probedURL, probedMethod = endpoint.Endpoint().Url, poktHTTPRequest.Method
+
+		payload, err := proto.Marshal(&types.POKTHTTPResponse{StatusCode: http.StatusOK})
+		require.NoError(t, err)
+		relayResponse := &servicetypes.RelayResponse{
+			Meta:    servicetypes.RelayResponseMetadata{SessionHeader: header},
+			Payload: payload,
+		}
+		signableBz, err := relayResponse.GetSignableBytesHash()
+		require.NoError(t, err)
+		relayResponse.Meta.SupplierOperatorSignature, err = supplierKey.Sign(signableBz[:])
+		require.NoError(t, err)
+		return relayResponse.Marshal()
+	}
+
+	selfTest := &SelfTest{
+		BlockClient:   &fakeSelfTestBlockQuerier{height: 2},
+		SessionClient: sessionQuerier,
+		Signer:        &Signer{PrivateKeyHex: hex.EncodeToString(appKey.Bytes())},
+		PublicKeyFetcher: &countingPubKeyFetcher{pubKeys: map[string]cryptotypes.PubKey{
+			appAddress:      appKey.PubKey(),
+			"pokt1supplier": supplierKey.PubKey(),
+		}},
+		SendRelay: sendRelay,
+	}
+
+	// The REST endpoint is probed with the default REST request.
+	results, err := selfTest.Run(context.Background(), []SelfTestService{
+		{ServiceId: "svc1", Application: app, RPCType: sharedtypes.RPCType_REST},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	require.True(t, results[0].IsReady())
+	require.Equal(t, SupplierAddress("pokt1supplier"), results[0].SupplierAddress)
+	require.Equal(t, "https://rest.example", probedURL)
+	require.Equal(t, http.MethodGet, probedMethod)
+	require.Equal(t, Version(), results[0].SDK)
+
+	// The gRPC endpoints have no default probe.
+	probedURL = ""
+	results, err = selfTest.Run(context.Background(), []SelfTestService{
+		{ServiceId: "svc1", Application: app, RPCType: sharedtypes.RPCType_GRPC},
+	})
+	require.NoError(t, err)
+	require.False(t, results[0].IsReady())
+	require.Empty(t, probedURL)
+
+	// A session without endpoints of the RPC type is reported.
+	results, err = selfTest.Run(context.Background(), []SelfTestService{
+		{ServiceId: "svc1", Application: app, RPCType: sharedtypes.RPCType_JSON_RPC},
+	})
+	require.NoError(t, err)
+	require.ErrorContains(t, results[0].Err, "no endpoints")
+	require.Equal(t, Version(), results[0].SDK)
+}
+
+// fakeSelfTestBlockQuerier is a BlockQuerier returning a fixed height.
+type fakeSelfTestBlockQuerier struct {
+	height int64
+}
+
+func (q *fakeSelfTestBlockQuerier) LatestBlockHeight(context.Context) (int64, error) {
+	return q.height, nil
+}
+
+// fakeSelfTestSessionQuerier is a SessionQuerier returning a fixed session.
+type fakeSelfTestSessionQuerier struct {
+	session *sessiontypes.Session
+}
+
+func (q *fakeSelfTestSessionQuerier) GetSession(context.Context, string, string, int64) (*sessiontypes.Session, error) {
+	return q.session, nil
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/gogoproto/grpc"
 	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
 	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
 	grpcoptions "google.golang.org/grpc"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
 )
 
 // SessionClient is the interface to interact with the on-chain session module.
@@ -27,7 +32,7 @@ func (s *SessionClient) GetSession(
 	height int64,
 ) (session *sessiontypes.Session, err error) {
 	if s.PoktNodeSessionFetcher == nil {
-		return nil, errors.New("PoktNodeSessionFetcher not set")
+		return nil, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "PoktNodeSessionFetcher not set")
 	}
 
 	req := &sessiontypes.QueryGetSessionRequest{
@@ -50,6 +55,243 @@ func (s *SessionClient) GetSession(
 	return res.Session, nil
 }
 
+// SessionKey identifies a session by its application address and service id.
+type SessionKey struct {
+	AppAddress string
+	ServiceId  string
+}
+
+// ActiveSessionsOption is a functional option used to configure the fetching
+// of active sessions, e.g. by GetActiveSessionsAtHeight.
+type ActiveSessionsOption func(*activeSessionsConfig)
+
+// activeSessionsConfig holds the settings applied by ActiveSessionsOptions.
+type activeSessionsConfig struct {
+	maxParallelFetches int
+}
+
+// WithMaxParallelSessionFetches bounds the number of sessions fetched in parallel,
+// e.g. to avoid overloading the full node when fetching the sessions of many
+// applications at once. A limit of zero or less disables the bound.
+func WithMaxParallelSessionFetches(maxParallelFetches int) ActiveSessionsOption {
+	return func(c *activeSessionsConfig) {
+		c.maxParallelFetches = maxParallelFetches
+	}
+}
+
+// GetActiveSessionsAtHeight returns the sessions for all the combinations of the
+// given service ids and application addresses, all pinned to the given height.
+//
+// The sessions are fetched in parallel, with an optional bound on the number of
+// parallel fetches set using WithMaxParallelSessionFetches. Pinning all the sessions
+// to the same height ensures the returned sessions share the same session number,
+// which allows gateways to coordinate session rollovers across multiple services.
+// An error is returned if any of the sessions could not be fetched, or if the
+// fetched sessions do not share the same session number.
+func (s *SessionClient) GetActiveSessionsAtHeight(
+	ctx context.Context,
+	serviceIds []string,
+	appAddresses []string,
+	height int64,
+	opts ...ActiveSessionsOption,
+) (map[SessionKey]*sessiontypes.Session, error) {
+	config := &activeSessionsConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	sessions, fetchErrs := s.fetchSessions(ctx, serviceIds, appAddresses, height, config.maxParallelFetches)
+	if len(fetchErrs) > 0 {
+		errs := make([]error, 0, len(fetchErrs))
+		for _, err := range fetchErrs {
+			errs = append(errs, err)
+		}
+		return nil, fmt.Errorf("GetActiveSessionsAtHeight: %w", errors.Join(errs...))
+	}
+
+	// Sanity check: all the sessions fetched at the same height are expected
+	// to share the same session number.
+	sessionNumber := int64(-1)
+	for key, session := range sessions {
+		if session == nil {
+			return nil, fmt.Errorf(
+				"GetActiveSessionsAtHeight: nil session returned for app %s and service %s",
+				key.AppAddress,
+				key.ServiceId,
+			)
+		}
+
+		if sessionNumber == -1 {
+			sessionNumber = session.SessionNumber
+			continue
+		}
+
+		if session.SessionNumber != sessionNumber {
+			return nil, fmt.Errorf(
+				"GetActiveSessionsAtHeight: inconsistent session number %d for app %s and service %s at height %d, expected %d",
+				session.SessionNumber,
+				key.AppAddress,
+				key.ServiceId,
+				height,
+				sessionNumber,
+			)
+		}
+	}
+
+	return sessions, nil
+}
+
+// ActiveSessions holds the results of GetActiveSessionsAtHeightPartial.
+type ActiveSessions struct {
+	// Sessions holds the successfully fetched sessions.
+	Sessions map[SessionKey]*sessiontypes.Session
+	// Errors holds the errors of the sessions which could not be fetched,
+	// e.g. due to an unstaked application.
+	Errors map[SessionKey]error
+}
+
+// AppErrors returns the errors of the failed sessions, grouped by application address.
+func (as ActiveSessions) AppErrors() map[string]error {
+	errsByApp := make(map[string][]error)
+	for key, err := range as.Errors {
+		errsByApp[key.AppAddress] = append(errsByApp[key.AppAddress], err)
+	}
+
+	appErrors := make(map[string]error, len(errsByApp))
+	for appAddress, errs := range errsByApp {
+		appErrors[appAddress] = errors.Join(errs...)
+	}
+	return appErrors
+}
+
+// GetActiveSessionsAtHeightPartial is a variant of GetActiveSessionsAtHeight
+// which does not fail if some of the sessions can not be fetched.
+//
+// It returns the successfully fetched sessions along with the errors of the
+// failed ones, allowing gateways to serve the healthy applications while
+// alerting on the broken ones.
+// Sessions with a session number differing from the one shared by most of the
+// fetched sessions are reported as errors.
+func (s *SessionClient) GetActiveSessionsAtHeightPartial(
+	ctx context.Context,
+	serviceIds []string,
+	appAddresses []string,
+	height int64,
+	opts ...ActiveSessionsOption,
+) ActiveSessions {
+	config := &activeSessionsConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	sessions, fetchErrs := s.fetchSessions(ctx, serviceIds, appAddresses, height, config.maxParallelFetches)
+
+	sessionNumberCounts := make(map[int64]int)
+	for key, session := range sessions {
+		if session == nil {
+			fetchErrs[key] = fmt.Errorf("nil session returned for app %s and service %s", key.AppAddress, key.ServiceId)
+			delete(sessions, key)
+			continue
+		}
+		sessionNumberCounts[session.SessionNumber]++
+	}
+
+	// The expected session number is the one shared by most sessions.
+	var expectedSessionNumber int64
+	for sessionNumber, count := range sessionNumberCounts {
+		if count > sessionNumberCounts[expectedSessionNumber] ||
+			(count == sessionNumberCounts[expectedSessionNumber] && sessionNumber > expectedSessionNumber) {
+			expectedSessionNumber = sessionNumber
+		}
+	}
+
+	for key, session := range sessions {
+		if session.SessionNumber != expectedSessionNumber {
+			fetchErrs[key] = fmt.Errorf(
+				"inconsistent session number %d for app %s and service %s at height %d, expected %d",
+				session.SessionNumber,
+				key.AppAddress,
+				key.ServiceId,
+				height,
+				expectedSessionNumber,
+			)
+			delete(sessions, key)
+		}
+	}
+
+	return ActiveSessions{Sessions: sessions, Errors: fetchErrs}
+}
+
+// fetchSessions fetches the sessions for all the combinations of the given
+// service ids and application addresses at the given height, in parallel.
+// At most maxParallelFetches sessions are fetched at the same time, unless it is zero or less.
+// The errors of the failed fetches are returned keyed by session.
+func (s *SessionClient) fetchSessions(
+	ctx context.Context,
+	serviceIds []string,
+	appAddresses []string,
+	height int64,
+	maxParallelFetches int,
+) (map[SessionKey]*sessiontypes.Session, map[SessionKey]error) {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		sessions  = make(map[SessionKey]*sessiontypes.Session)
+		fetchErrs = make(map[SessionKey]error)
+		semaphore chan struct{}
+	)
+	if maxParallelFetches > 0 {
+		semaphore = make(chan struct{}, maxParallelFetches)
+	}
+
+	for _, serviceId := range serviceIds {
+		for _, appAddress := range appAddresses {
+			wg.Add(1)
+			go func(key SessionKey) {
+				defer wg.Done()
+
+				session, err := s.fetchSession(ctx, key, height, semaphore)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					fetchErrs[key] = fmt.Errorf(
+						"error getting session for app %s and service %s: %w",
+						key.AppAddress,
+						key.ServiceId,
+						err,
+					)
+					return
+				}
+				sessions[key] = session
+			}(SessionKey{AppAddress: appAddress, ServiceId: serviceId})
+		}
+	}
+	wg.Wait()
+
+	return sessions, fetchErrs
+}
+
+// fetchSession fetches the session with the given key at the given height,
+// after acquiring a slot of the given semaphore, if not nil.
+func (s *SessionClient) fetchSession(
+	ctx context.Context,
+	key SessionKey,
+	height int64,
+	semaphore chan struct{},
+) (*sessiontypes.Session, error) {
+	if semaphore != nil {
+		select {
+		case semaphore <- struct{}{}:
+			defer func() { <-semaphore }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return s.GetSession(ctx, key.AppAddress, key.ServiceId, height)
+}
+
 // NewPoktNodeSessionFetcher returns the default implementation of the
 // PoktNodeSessionFetcher interface.
 // It connects to a POKT full node through the session module's query client
@@ -81,6 +323,14 @@ type SupplierAddress string
 // indicating whether the input endpoint should be filtered out.
 type EndpointFilter func(Endpoint) bool
 
+// FilterSuppliersWithStakeBelow returns an EndpointFilter that filters out the
+// endpoints of suppliers whose stake is lower than the given minimum stake.
+func FilterSuppliersWithStakeBelow(minStake cosmostypes.Coin) EndpointFilter {
+	return func(e Endpoint) bool {
+		return !e.SupplierInfo().HasStakeAtLeast(minStake)
+	}
+}
+
 // SessionFilter wraps a Session, allowing node selection by filtering out endpoints
 // based on the filters set on the struct.
 // This is needed so functions that enable sending relays can be provided with a
@@ -89,7 +339,15 @@ type SessionFilter struct {
 	*sessiontypes.Session
 
 	EndpointFilters []EndpointFilter
-	// TODO_IMPROVE: Add a slice of endpoint ordering functions
+	// SchemePolicy, if set, is applied to the endpoints before the filters,
+	// e.g. to upgrade or exclude the plaintext endpoints.
+	SchemePolicy *EndpointSchemePolicy
+	// EndpointDecorators, if set, are applied to the endpoints before the
+	// filters, e.g. to set the metadata used by the filters.
+	EndpointDecorators []EndpointDecorator
+	// Selector, if set, selects the endpoint returned by SelectEndpoint among
+	// the filtered endpoints. An endpoint is selected at random otherwise.
+	Selector EndpointSelector
 }
 
 // AllEndpoints returns all the endpoints corresponding to a session for the
@@ -119,7 +377,9 @@ func (f *SessionFilter) AllEndpoints() (map[SupplierAddress][]Endpoint, error) {
 					// TODO_TECHDEBT: Need deep copying here.
 					header:           *header,
 					supplierEndpoint: *e,
+					typed:            NewTypedSupplierEndpoint(*e),
 					supplier:         SupplierAddress(supplier.OperatorAddress),
+					supplierInfo:     SupplierInfo{Supplier: *supplier},
 				})
 			}
 			endpoints = append(endpoints, newEndpoints...)
@@ -132,18 +392,37 @@ func (f *SessionFilter) AllEndpoints() (map[SupplierAddress][]Endpoint, error) {
 
 // TODO_TECHDEBT: add a unit test to cover this method.
 // FilteredEndpoints returns the endpoints that pass all the filters set of
-// the FilteredSession.
-func (f *SessionFilter) FilteredEndpoints() ([]Endpoint, error) {
+// the FilteredSession, after applying the SchemePolicy and EndpointDecorators, if set.
+// The given options customize the endpoints for a single request, e.g. to
+// exclude the suppliers a request already failed against.
+func (f *SessionFilter) FilteredEndpoints(opts ...FilteredEndpointsOption) ([]Endpoint, error) {
 	allEndpoints, err := f.AllEndpoints()
 	if err != nil {
 		return nil, fmt.Errorf("FilteredEndpoints: error getting all endpoints: %w", err)
 	}
 
+	var options filteredEndpointsOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	filters := f.EndpointFilters
+	if !options.exclusions.IsEmpty() {
+		filters = append(slices.Clip(filters), options.exclusions.Filter())
+	}
+
 	var filteredEndpoints []Endpoint
 	for _, endpoints := range allEndpoints {
 		for _, endpoint := range endpoints {
+			if f.SchemePolicy != nil {
+				var permitted bool
+				if endpoint, permitted = f.SchemePolicy.Apply(endpoint); !permitted {
+					continue
+				}
+			}
+			endpoint = DecorateEndpoint(endpoint, f.EndpointDecorators...)
+
 			includePoint := true
-			for _, filter := range f.EndpointFilters {
+			for _, filter := range filters {
 				if filter(endpoint) {
 					includePoint = false
 					break
@@ -158,13 +437,38 @@ func (f *SessionFilter) FilteredEndpoints() ([]Endpoint, error) {
 	return filteredEndpoints, nil
 }
 
+// SelectEndpoint returns the endpoint selected by the Selector among the
+// filtered endpoints, excluding the endpoints excluded by the given context.
+// See FilteredEndpoints and ContextWithEndpointExclusions.
+func (f *SessionFilter) SelectEndpoint(ctx context.Context) (Endpoint, error) {
+	endpoints, err := f.FilteredEndpoints(WithEndpointExclusions(EndpointExclusionsFromContext(ctx)))
+	if err != nil {
+		return nil, fmt.Errorf("SelectEndpoint: %w", err)
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("SelectEndpoint: no endpoints in session %s", f.Session.SessionId)
+	}
+
+	selector := f.Selector
+	if selector == nil {
+		selector = NewRandomSelector()
+	}
+	endpoint := selector.Select(ctx, endpoints)
+	if endpoint == nil {
+		return nil, fmt.Errorf("SelectEndpoint: no endpoint selected in session %s", f.Session.SessionId)
+	}
+	return endpoint, nil
+}
+
 // Endpoint is a struct that represents an endpoint with its corresponding
 // supplier and session that contains the endpoint.
 // It implements the Endpoint interface.
 type endpoint struct {
 	header           sessiontypes.SessionHeader
 	supplierEndpoint sharedtypes.SupplierEndpoint
+	typed            TypedSupplierEndpoint
 	supplier         SupplierAddress
+	supplierInfo     SupplierInfo
 }
 
 // Endpoint returns the supplier endpoint for the endpoint.
@@ -172,6 +476,12 @@ func (e endpoint) Endpoint() sharedtypes.SupplierEndpoint {
 	return e.supplierEndpoint
 }
 
+// Typed returns the supplier endpoint for the endpoint, parsed and validated
+// when the endpoint was built.
+func (e endpoint) Typed() TypedSupplierEndpoint {
+	return e.typed
+}
+
 // Supplier returns the supplier address for the endpoint.
 func (e endpoint) Supplier() SupplierAddress {
 	return e.supplier
@@ -182,6 +492,11 @@ func (e endpoint) Header() sessiontypes.SessionHeader {
 	return e.header
 }
 
+// SupplierInfo returns the metadata of the supplier owning the endpoint.
+func (e endpoint) SupplierInfo() SupplierInfo {
+	return e.supplierInfo
+}
+
 // TODO_CONSIDERATION: Prefix the Endpoint methods with `Get` to make it clear
 // that they are getters.
 // Endpoint is an interface that represents an endpoint with its corresponding
@@ -190,4 +505,5 @@ type Endpoint interface {
 	Header() sessiontypes.SessionHeader
 	Supplier() SupplierAddress
 	Endpoint() sharedtypes.SupplierEndpoint
+	SupplierInfo() SupplierInfo
 }
@@ -0,0 +1,544 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// SessionCache wraps a SessionClient, caching the sessions it fetches to avoid
+// querying the POKT full node for every relay.
+//
+// A cached session is used for any height within its start and end block heights.
+// If the cache is configured with a stale grace period, the latest cached session
+// is served when fetching a new session fails due to the full node not
+// responding in time.
+//
+// If sharding by service is enabled, each service id gets its own cache instance,
+// so a high-churn service can not evict or crowd out the sessions of other services.
+type SessionCache struct {
+	sessionClient SessionQuerier
+	config        sessionCacheConfig
+	cache         *cache.Cache[SessionKey, SessionInfo]
+
+	// shardsMu protects shards, which holds the per-service caches if sharding
+	// by service is enabled.
+	shardsMu sync.Mutex
+	shards   map[string]*cache.Cache[SessionKey, SessionInfo]
+
+	// generation is incremented every time a session is fetched from the full node.
+	generation atomic.Uint64
+
+	// refreshLagMu protects refreshLagStats, which holds the refresh lag stats per service id.
+	refreshLagMu    sync.Mutex
+	refreshLagStats map[string]RefreshLagStats
+
+	// fetchSem bounds the number of concurrent session fetches from the full
+	// node, if a limit is configured.
+	fetchSem chan struct{}
+}
+
+// RefreshLagStats summarizes the refresh lags of the sessions of a service.
+//
+// The refresh lag of a session is the number of blocks between the end height
+// of the session it replaced and the height at which it was fetched.
+// A high refresh lag indicates slow session rollovers, e.g. due to full node lag.
+type RefreshLagStats struct {
+	// Refreshes is the number of sessions which replaced a previously cached session.
+	Refreshes int64
+	// LastLagBlocks is the refresh lag of the latest refreshed session.
+	LastLagBlocks int64
+	// MaxLagBlocks is the highest observed refresh lag.
+	MaxLagBlocks int64
+	// TotalLagBlocks is the sum of all the observed refresh lags.
+	TotalLagBlocks int64
+}
+
+// MeanLagBlocks returns the mean observed refresh lag, or zero if no session was refreshed.
+func (s RefreshLagStats) MeanLagBlocks() float64 {
+	if s.Refreshes == 0 {
+		return 0
+	}
+	return float64(s.TotalLagBlocks) / float64(s.Refreshes)
+}
+
+// SessionSource indicates where a session returned by the SessionCache came from.
+type SessionSource int
+
+const (
+	// SessionSourceCache indicates the session was served from the cache.
+	SessionSourceCache SessionSource = iota
+	// SessionSourceFullNode indicates the session was fetched from the full node.
+	SessionSourceFullNode
+	// SessionSourceStale indicates a stale cached session was served, because
+	// fetching the session from the full node failed.
+	SessionSourceStale
+)
+
+// String returns the name of the session source.
+func (s SessionSource) String() string {
+	switch s {
+	case SessionSourceCache:
+		return "cache"
+	case SessionSourceFullNode:
+		return "fullnode"
+	case SessionSourceStale:
+		return "stale"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// SessionInfo is a session returned by the SessionCache, along with metadata
+// allowing consumers to reason about the freshness of the session.
+type SessionInfo struct {
+	*sessiontypes.Session
+
+	// FetchedAtHeight is the height requested when the session was fetched from the full node.
+	FetchedAtHeight int64
+	// FetchedAt is the time at which the session was fetched from the full node.
+	FetchedAt time.Time
+	// Source indicates where the session was served from.
+	Source SessionSource
+	// Generation identifies the fetch of the session from the full node.
+	// It increases with every session fetched by the SessionCache, e.g. it can
+	// be logged to follow session rollovers.
+	Generation uint64
+	// RefreshLagBlocks is the number of blocks between the end height of the
+	// session this session replaced in the cache and FetchedAtHeight.
+	// It is zero if the session did not replace a cached session.
+	RefreshLagBlocks int64
+	// Metadata holds the metadata of the session, computed when it was fetched
+	// from the full node.
+	Metadata SessionMetadata
+	// FetchErr is the error which caused a stale session to be served.
+	// It is only set if Source is SessionSourceStale.
+	FetchErr error
+}
+
+// ValidUntilHeight returns the end block height of the session, so the cached
+// sessions are removed by InvalidateAtHeight once they end.
+func (si SessionInfo) ValidUntilHeight() int64 {
+	return si.GetHeader().GetSessionEndBlockHeight()
+}
+
+// SessionCacheOption is a functional option used to configure a SessionCache.
+type SessionCacheOption func(*sessionCacheConfig)
+
+// sessionCacheConfig holds the settings applied by SessionCacheOptions.
+type sessionCacheConfig struct {
+	cacheConfig          cache.Config
+	shardByService       bool
+	serviceCacheConfigs  map[string]cache.Config
+	newEngine            func() cache.Engine[SessionKey, SessionInfo]
+	refreshLagObserver   func(serviceId string, lagBlocks int64)
+	maxConcurrentFetches int
+	eventBus             *EventBus
+	metricsRecorder      MetricsRecorder
+}
+
+// WithCacheConfig sets the configuration of the cache used by the SessionCache.
+func WithCacheConfig(cacheConfig cache.Config) SessionCacheOption {
+	return func(c *sessionCacheConfig) {
+		c.cacheConfig = cacheConfig
+	}
+}
+
+// WithCacheEngine sets the function used to create the engines storing the
+// cached sessions, overriding the default in-memory map.
+// The function is called once per cache instance, i.e. once per service id if
+// sharding by service is enabled.
+func WithCacheEngine(newEngine func() cache.Engine[SessionKey, SessionInfo]) SessionCacheOption {
+	return func(c *sessionCacheConfig) {
+		c.newEngine = newEngine
+	}
+}
+
+// WithRefreshLagObserver sets a function called with the refresh lag, in blocks,
+// of every session replacing a previously cached session, e.g. to export it as a metric.
+// The function is called synchronously, and must not block.
+func WithRefreshLagObserver(observer func(serviceId string, lagBlocks int64)) SessionCacheOption {
+	return func(c *sessionCacheConfig) {
+		c.refreshLagObserver = observer
+	}
+}
+
+// WithMaxConcurrentFetches bounds the number of sessions fetched concurrently
+// from the full node, across all application addresses and service ids.
+//
+// Concurrent fetches of the same session are always coalesced into a single
+// query. This option additionally protects the full node from query storms
+// when many distinct sessions need to be fetched at once, e.g. at a session
+// boundary. Fetches beyond the limit wait for a slot, or until their context is done.
+// A limit of zero or less disables the bound.
+func WithMaxConcurrentFetches(maxConcurrentFetches int) SessionCacheOption {
+	return func(c *sessionCacheConfig) {
+		c.maxConcurrentFetches = maxConcurrentFetches
+	}
+}
+
+// WithEventBus sets the EventBus on which the SessionCache publishes a
+// SessionRefreshedEvent for every session fetched from the full node.
+func WithEventBus(eventBus *EventBus) SessionCacheOption {
+	return func(c *sessionCacheConfig) {
+		c.eventBus = eventBus
+	}
+}
+
+// WithSessionMetricsRecorder sets the MetricsRecorder of the SessionCache's
+// lookups, and of the durations of the sessions fetched from the full node.
+func WithSessionMetricsRecorder(recorder MetricsRecorder) SessionCacheOption {
+	return func(c *sessionCacheConfig) {
+		c.metricsRecorder = recorder
+	}
+}
+
+// WithServiceSharding enables sharding the SessionCache by service id, i.e.
+// using a separate cache instance for the sessions of each service id.
+func WithServiceSharding() SessionCacheOption {
+	return func(c *sessionCacheConfig) {
+		c.shardByService = true
+	}
+}
+
+// WithServiceCacheConfig sets the configuration of the cache used for the given
+// service id, overriding the one set by WithCacheConfig.
+// It implies WithServiceSharding.
+func WithServiceCacheConfig(serviceId string, cacheConfig cache.Config) SessionCacheOption {
+	return func(c *sessionCacheConfig) {
+		c.shardByService = true
+		if c.serviceCacheConfigs == nil {
+			c.serviceCacheConfigs = make(map[string]cache.Config)
+		}
+		c.serviceCacheConfigs[serviceId] = cacheConfig
+	}
+}
+
+// NewSessionCacheWithOptions returns a SessionCache which fetches sessions using
+// the given SessionQuerier, e.g. a SessionClient, configured using the given options.
+func NewSessionCacheWithOptions(sessionClient SessionQuerier, opts ...SessionCacheOption) *SessionCache {
+	config := &sessionCacheConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	sc := &SessionCache{
+		sessionClient:   sessionClient,
+		config:          *config,
+		refreshLagStats: make(map[string]RefreshLagStats),
+	}
+	if config.shardByService {
+		sc.shards = make(map[string]*cache.Cache[SessionKey, SessionInfo])
+	} else {
+		sc.cache = sc.newCache(config.cacheConfig)
+	}
+	if config.maxConcurrentFetches > 0 {
+		sc.fetchSem = make(chan struct{}, config.maxConcurrentFetches)
+	}
+
+	return sc
+}
+
+// NewSessionCache returns a SessionCache which fetches sessions using the
+// given SessionQuerier, e.g. a SessionClient.
+// It is a shorthand for NewSessionCacheWithOptions with the WithCacheConfig option.
+func NewSessionCache(sessionClient SessionQuerier, config cache.Config) *SessionCache {
+	return NewSessionCacheWithOptions(sessionClient, WithCacheConfig(config))
+}
+
+// GetSession returns the session with the given application address, service id
+// and height, fetching it from the full node if it is not cached.
+// The Source field of the returned SessionInfo indicates whether the session was
+// served from the cache, fetched from the full node, or served stale.
+func (sc *SessionCache) GetSession(
+	ctx context.Context,
+	appAddress string,
+	serviceId string,
+	height int64,
+) (SessionInfo, error) {
+	if sc.sessionClient == nil {
+		return SessionInfo{}, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "GetSession: SessionClient not set")
+	}
+
+	sessionCache := sc.getCache(serviceId)
+	key := SessionKey{AppAddress: appAddress, ServiceId: serviceId}
+	cachedInfo, isCached := sessionCache.Get(key)
+	if isCached && sessionCoversHeight(cachedInfo.Session, height) {
+		sc.observeCacheLookup(true)
+		cachedInfo.Source = SessionSourceCache
+		return cachedInfo, nil
+	}
+	sc.observeCacheLookup(false)
+
+	fetchSession := func(ctx context.Context) (SessionInfo, error) {
+		release, err := sc.acquireFetchSlot(ctx)
+		if err != nil {
+			return SessionInfo{}, err
+		}
+		defer release()
+
+		fetchStart := time.Now()
+		session, err := sc.sessionClient.GetSession(ctx, appAddress, serviceId, height)
+		if sc.config.metricsRecorder != nil {
+			sc.config.metricsRecorder.ObserveSessionRefresh(serviceId, time.Since(fetchStart), err)
+		}
+		if err != nil {
+			return SessionInfo{}, err
+		}
+
+		fetchedInfo := SessionInfo{
+			Session:         session,
+			FetchedAtHeight: height,
+			FetchedAt:       time.Now(),
+			Source:          SessionSourceFullNode,
+			Generation:      sc.generation.Add(1),
+			Metadata:        NewSessionMetadata(session),
+		}
+		if isCached && cachedInfo.Session != nil && cachedInfo.Header != nil &&
+			height > cachedInfo.Header.SessionEndBlockHeight {
+			fetchedInfo.RefreshLagBlocks = height - cachedInfo.Header.SessionEndBlockHeight
+			sc.recordRefreshLag(serviceId, fetchedInfo.RefreshLagBlocks)
+		}
+
+		sc.config.eventBus.Publish(SessionRefreshedEvent{
+			AppAddress: appAddress,
+			ServiceId:  serviceId,
+			Session:    fetchedInfo,
+		})
+
+		return fetchedInfo, nil
+	}
+
+	sessionInfo, result, err := sessionCache.Fetch(ctx, key, fetchSession)
+	if err == nil && !result.StaleServed && !sessionCoversHeight(sessionInfo.Session, height) {
+		// The fetch was coalesced with a concurrent fetch of another height, e.g.
+		// across a session rollover: fetch the session of the requested height,
+		// without caching it.
+		sessionInfo, err = fetchSession(ctx)
+	}
+	if err != nil {
+		return SessionInfo{}, fmt.Errorf("GetSession: error fetching session for app %s and service %s: %w", appAddress, serviceId, err)
+	}
+
+	if result.StaleServed {
+		sessionInfo.Source = SessionSourceStale
+		sessionInfo.FetchErr = result.FetchErr
+	}
+
+	return sessionInfo, nil
+}
+
+// SetReplicatedSession caches the given session, fetched from the full node by
+// another SessionCache, e.g. of a gateway in another region, unless a session
+// of the same application and service ending at the same or a later height is
+// already cached. It returns true if the session was cached.
+// No SessionRefreshedEvent is published for the replicated sessions, so they
+// are not replicated back.
+func (sc *SessionCache) SetReplicatedSession(sessionInfo SessionInfo) bool {
+	if sessionInfo.Session == nil || sessionInfo.Header == nil {
+		return false
+	}
+
+	header := sessionInfo.Header
+	key := SessionKey{AppAddress: header.ApplicationAddress, ServiceId: header.ServiceId}
+	sessionCache := sc.getCache(header.ServiceId)
+	cachedInfo, isCached := sessionCache.Get(key)
+	if isCached && cachedInfo.Session != nil && cachedInfo.Header != nil &&
+		cachedInfo.Header.SessionEndBlockHeight >= header.SessionEndBlockHeight {
+		return false
+	}
+
+	sessionInfo.Source = SessionSourceFullNode
+	sessionInfo.Generation = sc.generation.Add(1)
+	sessionInfo.Metadata = NewSessionMetadata(sessionInfo.Session)
+	sessionInfo.FetchErr = nil
+	sessionCache.Set(key, sessionInfo)
+	return true
+}
+
+// InvalidateAtHeight removes, at once from each cache instance, the sessions
+// ending at or before the given height, e.g. on every new block or after a
+// chain reorg on LocalNet, and publishes a CacheEvictedEvent for each of them.
+func (sc *SessionCache) InvalidateAtHeight(height int64) {
+	sc.invalidateAtHeight(height)
+}
+
+// RecoverFromReorg recovers the SessionCache from the given chain reorg: all the
+// cached sessions, which were derived from the abandoned chain, are removed, and
+// fetched again at the height of the reorg.
+// It can be set as a ReorgRecoveryFunc of a ReorgDetector.
+func (sc *SessionCache) RecoverFromReorg(ctx context.Context, reorg ChainReorgEvent) error {
+	// All the cached sessions are height scoped: invalidating them at the highest
+	// height removes them all.
+	invalidatedKeys := sc.invalidateAtHeight(math.MaxInt64)
+
+	var errs []error
+	for _, key := range invalidatedKeys {
+		if _, err := sc.GetSession(ctx, key.AppAddress, key.ServiceId, reorg.Height); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("RecoverFromReorg: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// InvalidateApplication removes, from each cache instance, the sessions of the
+// given application, e.g. after it undelegated from a gateway or unstaked
+// mid-session, and publishes a CacheEvictedEvent for each of them.
+// The sessions are fetched again, with the application's current state, on
+// their next use.
+func (sc *SessionCache) InvalidateApplication(appAddress string) {
+	sc.invalidate(func(key SessionKey, _ int64) bool {
+		return key.AppAddress == appAddress
+	})
+}
+
+// HandleEvent invalidates the sessions of the application of the
+// DelegationChangedEvents and ApplicationStakeChangedEvents.
+// It can be subscribed to an EventBus, which notifies the application changes,
+// e.g. as published by an ApplicationEventWatcher.
+func (sc *SessionCache) HandleEvent(event Event) {
+	switch event := event.(type) {
+	case DelegationChangedEvent:
+		sc.InvalidateApplication(event.AppAddress)
+	case ApplicationStakeChangedEvent:
+		sc.InvalidateApplication(event.AppAddress)
+	}
+}
+
+// invalidateAtHeight removes the sessions ending at or before the given height,
+// publishes a CacheEvictedEvent for each of them, and returns their keys.
+func (sc *SessionCache) invalidateAtHeight(height int64) []SessionKey {
+	return sc.invalidate(func(_ SessionKey, validUntilHeight int64) bool {
+		return validUntilHeight <= height
+	})
+}
+
+// invalidate removes the sessions matching the given function, publishes a
+// CacheEvictedEvent for each of them, and returns their keys.
+func (sc *SessionCache) invalidate(match func(key SessionKey, validUntilHeight int64) bool) []SessionKey {
+	var invalidatedKeys []SessionKey
+	for _, sessionCache := range sc.caches() {
+		invalidatedKeys = append(invalidatedKeys, sessionCache.InvalidateFunc(match)...)
+	}
+
+	for _, key := range invalidatedKeys {
+		sc.config.eventBus.Publish(CacheEvictedEvent{Key: key})
+	}
+	return invalidatedKeys
+}
+
+// RefreshLagStats returns the refresh lag stats of the sessions of the given service id.
+func (sc *SessionCache) RefreshLagStats(serviceId string) RefreshLagStats {
+	sc.refreshLagMu.Lock()
+	defer sc.refreshLagMu.Unlock()
+
+	return sc.refreshLagStats[serviceId]
+}
+
+// recordRefreshLag updates the refresh lag stats of the given service id with
+// the given refresh lag, and notifies the refresh lag observer, if any.
+func (sc *SessionCache) recordRefreshLag(serviceId string, lagBlocks int64) {
+	sc.refreshLagMu.Lock()
+	stats := sc.refreshLagStats[serviceId]
+	stats.Refreshes++
+	stats.LastLagBlocks = lagBlocks
+	stats.TotalLagBlocks += lagBlocks
+	stats.MaxLagBlocks = max(stats.MaxLagBlocks, lagBlocks)
+	sc.refreshLagStats[serviceId] = stats
+	sc.refreshLagMu.Unlock()
+
+	if sc.config.refreshLagObserver != nil {
+		sc.config.refreshLagObserver(serviceId, lagBlocks)
+	}
+}
+
+// observeCacheLookup notifies the metrics recorder, if any, of a lookup in the cache.
+func (sc *SessionCache) observeCacheLookup(hit bool) {
+	if sc.config.metricsRecorder != nil {
+		sc.config.metricsRecorder.ObserveCacheLookup(MetricsCacheSession, hit)
+	}
+}
+
+// acquireFetchSlot blocks until a full node fetch slot is available, or the
+// given context is done. The returned function must be called to release the slot.
+func (sc *SessionCache) acquireFetchSlot(ctx context.Context) (func(), error) {
+	if sc.fetchSem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case sc.fetchSem <- struct{}{}:
+		return func() { <-sc.fetchSem }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("error waiting for a full node fetch slot: %w", ctx.Err())
+	}
+}
+
+// getCache returns the cache holding the sessions of the given service id,
+// creating it if sharding by service is enabled and it does not exist yet.
+func (sc *SessionCache) getCache(serviceId string) *cache.Cache[SessionKey, SessionInfo] {
+	if !sc.config.shardByService {
+		return sc.cache
+	}
+
+	sc.shardsMu.Lock()
+	defer sc.shardsMu.Unlock()
+
+	shard, ok := sc.shards[serviceId]
+	if !ok {
+		cacheConfig, ok := sc.config.serviceCacheConfigs[serviceId]
+		if !ok {
+			cacheConfig = sc.config.cacheConfig
+		}
+		shard = sc.newCache(cacheConfig)
+		sc.shards[serviceId] = shard
+	}
+
+	return shard
+}
+
+// caches returns all the cache instances holding sessions.
+func (sc *SessionCache) caches() []*cache.Cache[SessionKey, SessionInfo] {
+	if !sc.config.shardByService {
+		return []*cache.Cache[SessionKey, SessionInfo]{sc.cache}
+	}
+
+	sc.shardsMu.Lock()
+	defer sc.shardsMu.Unlock()
+
+	return slices.Collect(maps.Values(sc.shards))
+}
+
+// newCache returns a cache with the given configuration, using the configured
+// cache engine, if any.
+func (sc *SessionCache) newCache(cacheConfig cache.Config) *cache.Cache[SessionKey, SessionInfo] {
+	if sc.config.newEngine == nil {
+		return cache.New[SessionKey, SessionInfo](cacheConfig)
+	}
+
+	return cache.NewWithEngine[SessionKey, SessionInfo](cacheConfig, sc.config.newEngine())
+}
+
+// sessionCoversHeight returns true if the given height is within the start and
+// end block heights of the given session.
+func sessionCoversHeight(session *sessiontypes.Session, height int64) bool {
+	if session == nil || session.Header == nil {
+		return false
+	}
+
+	return height >= session.Header.SessionStartBlockHeight && height <= session.Header.SessionEndBlockHeight
+}
@@ -0,0 +1,276 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+func TestSessionCache_ServiceSharding(t *testing.T) {
+	sessionClient := &SessionClient{
+		PoktNodeSessionFetcher: &fakeSessionFetcher{
+			sessionNumbers: map[string]int64{"svc1": 1, "svc2": 1},
+		},
+	}
+
+	sc := NewSessionCacheWithOptions(
+		sessionClient,
+		WithCacheConfig(cache.Config{TTL: time.Minute}),
+		WithServiceCacheConfig("svc2", cache.Config{TTL: time.Second}),
+	)
+
+	ctx := context.Background()
+	for _, serviceId := range []string{"svc1", "svc2"} {
+		for _, appAddress := range []string{"app1", "app2"} {
+			sessionInfo, err := sc.GetSession(ctx, appAddress, serviceId, 0)
+			require.NoError(t, err)
+			require.Equal(t, SessionSourceFullNode, sessionInfo.Source)
+		}
+	}
+
+	svc1Cache := sc.getCache("svc1")
+	svc2Cache := sc.getCache("svc2")
+	require.NotSame(t, svc1Cache, svc2Cache)
+	require.Equal(t, 2, svc1Cache.Len())
+	require.Equal(t, 2, svc2Cache.Len())
+
+	// Clearing the sessions of one service must not affect the other service.
+	svc2Cache.Delete(SessionKey{AppAddress: "app1", ServiceId: "svc2"})
+	require.Equal(t, 2, svc1Cache.Len())
+	require.Equal(t, 1, svc2Cache.Len())
+}
+
+func TestSessionCache_NoSharding(t *testing.T) {
+	sc := NewSessionCache(&SessionClient{PoktNodeSessionFetcher: &fakeSessionFetcher{}}, cache.Config{})
+
+	require.Same(t, sc.getCache("svc1"), sc.getCache("svc2"))
+}
+
+func TestSessionCache_SessionInfo(t *testing.T) {
+	sc := NewSessionCache(
+		&SessionClient{PoktNodeSessionFetcher: &fakeSessionFetcher{}},
+		cache.Config{TTL: time.Minute},
+	)
+
+	ctx := context.Background()
+	fetched, err := sc.GetSession(ctx, "app1", "svc1", 0)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceFullNode, fetched.Source)
+	require.Equal(t, uint64(1), fetched.Generation)
+	require.False(t, fetched.FetchedAt.IsZero())
+
+	cached, err := sc.GetSession(ctx, "app1", "svc1", 0)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceCache, cached.Source)
+	require.Equal(t, fetched.Generation, cached.Generation)
+	require.Equal(t, fetched.FetchedAt, cached.FetchedAt)
+
+	other, err := sc.GetSession(ctx, "app2", "svc1", 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), other.Generation)
+}
+
+func TestSessionCache_RefreshLag(t *testing.T) {
+	var observedLags []int64
+	sc := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: &heightSessionFetcher{numBlocksPerSession: 4}},
+		WithRefreshLagObserver(func(serviceId string, lagBlocks int64) {
+			require.Equal(t, "svc1", serviceId)
+			observedLags = append(observedLags, lagBlocks)
+		}),
+	)
+
+	ctx := context.Background()
+	for _, height := range []int64{2, 3, 5, 10} {
+		_, err := sc.GetSession(ctx, "app1", "svc1", height)
+		require.NoError(t, err)
+	}
+
+	// The session [0, 3] is replaced at height 5, and the session [4, 7] at height 10.
+	require.Equal(t, []int64{2, 3}, observedLags)
+
+	stats := sc.RefreshLagStats("svc1")
+	require.Equal(t, int64(2), stats.Refreshes)
+	require.Equal(t, int64(3), stats.LastLagBlocks)
+	require.Equal(t, int64(3), stats.MaxLagBlocks)
+	require.Equal(t, 2.5, stats.MeanLagBlocks())
+
+	require.Equal(t, RefreshLagStats{}, sc.RefreshLagStats("svc2"))
+}
+
+func TestSessionCache_InvalidateAtHeight(t *testing.T) {
+	bus := NewEventBus()
+	var evicted []Event
+	bus.Subscribe(func(event Event) { evicted = append(evicted, event) }, EventCacheEvicted)
+
+	sc := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: &heightSessionFetcher{numBlocksPerSession: 4}},
+		WithServiceSharding(),
+		WithEventBus(bus),
+	)
+
+	ctx := context.Background()
+	_, err := sc.GetSession(ctx, "app1", "svc1", 2)
+	require.NoError(t, err)
+	_, err = sc.GetSession(ctx, "app1", "svc2", 6)
+	require.NoError(t, err)
+
+	// Only the session [0, 3] ends at or before height 3.
+	sc.InvalidateAtHeight(3)
+	require.Equal(t, []Event{CacheEvictedEvent{Key: SessionKey{AppAddress: "app1", ServiceId: "svc1"}}}, evicted)
+
+	sessionInfo, err := sc.GetSession(ctx, "app1", "svc1", 2)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceFullNode, sessionInfo.Source)
+
+	sessionInfo, err = sc.GetSession(ctx, "app1", "svc2", 6)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceCache, sessionInfo.Source)
+}
+
+func TestSessionCache_InvalidateApplication(t *testing.T) {
+	bus := NewEventBus()
+	var evicted []Event
+	bus.Subscribe(func(event Event) { evicted = append(evicted, event) }, EventCacheEvicted)
+
+	sc := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: &heightSessionFetcher{numBlocksPerSession: 4}},
+		WithEventBus(bus),
+	)
+	bus.Subscribe(sc.HandleEvent, EventDelegationChanged, EventApplicationStakeChanged)
+
+	ctx := context.Background()
+	_, err := sc.GetSession(ctx, "app1", "svc1", 2)
+	require.NoError(t, err)
+	_, err = sc.GetSession(ctx, "app2", "svc1", 2)
+	require.NoError(t, err)
+
+	// The sessions of the changed application are fetched again mid-session.
+	bus.Publish(DelegationChangedEvent{AppAddress: "app1", GatewayAddress: "gw1"})
+	require.Equal(t, []Event{CacheEvictedEvent{Key: SessionKey{AppAddress: "app1", ServiceId: "svc1"}}}, evicted)
+
+	sessionInfo, err := sc.GetSession(ctx, "app1", "svc1", 2)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceFullNode, sessionInfo.Source)
+
+	sessionInfo, err = sc.GetSession(ctx, "app2", "svc1", 2)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceCache, sessionInfo.Source)
+
+	evicted = nil
+	bus.Publish(ApplicationStakeChangedEvent{AppAddress: "app2"})
+	require.Equal(t, []Event{CacheEvictedEvent{Key: SessionKey{AppAddress: "app2", ServiceId: "svc1"}}}, evicted)
+}
+
+func TestSessionCache_MaxConcurrentFetches(t *testing.T) {
+	const maxConcurrentFetches = 3
+	fetcher := &concurrencySessionFetcher{release: make(chan struct{})}
+	sc := NewSessionCacheWithOptions(
+		&SessionClient{PoktNodeSessionFetcher: fetcher},
+		WithCacheConfig(cache.Config{TTL: time.Minute}),
+		WithMaxConcurrentFetches(maxConcurrentFetches),
+	)
+
+	// Fetch the sessions of many distinct apps at once.
+	const numApps = 20
+	var wg sync.WaitGroup
+	errs := make([]error, numApps)
+	for i := 0; i < numApps; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = sc.GetSession(context.Background(), fmt.Sprintf("app%d", i), "svc1", 0)
+		}(i)
+	}
+
+	require.Eventually(t, func() bool { return fetcher.inFlight.Load() == maxConcurrentFetches }, time.Second, time.Millisecond)
+	close(fetcher.release)
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(maxConcurrentFetches), fetcher.maxInFlight.Load())
+
+	// Fetches waiting for a slot give up once their context is done.
+	fetcher.release = make(chan struct{})
+	defer close(fetcher.release)
+	for i := 0; i < maxConcurrentFetches; i++ {
+		go sc.GetSession(context.Background(), fmt.Sprintf("blocking-app%d", i), "svc1", 0) //nolint:errcheck
+	}
+	require.Eventually(t, func() bool { return fetcher.inFlight.Load() == maxConcurrentFetches }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := sc.GetSession(ctx, "waiting-app", "svc1", 0)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// concurrencySessionFetcher is a PoktNodeSessionFetcher which blocks until
+// released, tracking the number of concurrent GetSession calls.
+type concurrencySessionFetcher struct {
+	release     chan struct{}
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (f *concurrencySessionFetcher) GetSession(
+	_ context.Context,
+	req *sessiontypes.QueryGetSessionRequest,
+	_ ...grpcoptions.CallOption,
+) (*sessiontypes.QueryGetSessionResponse, error) {
+	inFlight := f.inFlight.Add(1)
+	defer f.inFlight.Add(-1)
+	for {
+		maxInFlight := f.maxInFlight.Load()
+		if inFlight <= maxInFlight || f.maxInFlight.CompareAndSwap(maxInFlight, inFlight) {
+			break
+		}
+	}
+
+	<-f.release
+
+	return &sessiontypes.QueryGetSessionResponse{
+		Session: &sessiontypes.Session{
+			Header: &sessiontypes.SessionHeader{
+				ApplicationAddress:    req.ApplicationAddress,
+				ServiceId:             req.ServiceId,
+				SessionEndBlockHeight: 10,
+			},
+		},
+	}, nil
+}
+
+// heightSessionFetcher is a PoktNodeSessionFetcher which returns sessions of a
+// fixed number of blocks, covering the requested height.
+type heightSessionFetcher struct {
+	numBlocksPerSession int64
+}
+
+func (f *heightSessionFetcher) GetSession(
+	_ context.Context,
+	req *sessiontypes.QueryGetSessionRequest,
+	_ ...grpcoptions.CallOption,
+) (*sessiontypes.QueryGetSessionResponse, error) {
+	startHeight := req.BlockHeight / f.numBlocksPerSession * f.numBlocksPerSession
+
+	return &sessiontypes.QueryGetSessionResponse{
+		Session: &sessiontypes.Session{
+			Header: &sessiontypes.SessionHeader{
+				ApplicationAddress:      req.ApplicationAddress,
+				ServiceId:               req.ServiceId,
+				SessionStartBlockHeight: startHeight,
+				SessionEndBlockHeight:   startHeight + f.numBlocksPerSession - 1,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,164 @@
+package sdk
+
+import (
+	"strings"
+
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+)
+
+// An EndpointFilter returns true for the endpoints that should be filtered out.
+// The And and Or combinators below operate on the endpoints the filters keep,
+// so they compose like the criteria they express: And keeps the endpoints kept
+// by all the given filters, i.e. their intersection, and Or keeps the endpoints
+// kept by any of them, i.e. their union.
+//
+// The prebuilt By* filters filter out every endpoint that does NOT match the
+// given criteria, i.e. ByRPCType(JSON_RPC) keeps only the JSON-RPC endpoints,
+// and And(ByRPCType(JSON_RPC), ByURLScheme("https")) keeps only the JSON-RPC
+// endpoints served over HTTPS.
+// Use Not to invert a prebuilt filter, e.g. Not(ByURLScheme("http")) filters out
+// all the plain HTTP endpoints.
+
+// And returns an EndpointFilter which keeps an endpoint only if all the given
+// filters keep it, i.e. which filters it out if any of the given filters does.
+// An And with no filters never filters out an endpoint.
+func And(filters ...EndpointFilter) EndpointFilter {
+	return func(e Endpoint) bool {
+		for _, filter := range filters {
+			if filter(e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Or returns an EndpointFilter which keeps an endpoint if any of the given
+// filters keeps it, i.e. which filters it out only if all the given filters do.
+// An Or with no filters never filters out an endpoint.
+func Or(filters ...EndpointFilter) EndpointFilter {
+	return func(e Endpoint) bool {
+		if len(filters) == 0 {
+			return false
+		}
+
+		for _, filter := range filters {
+			if !filter(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Not returns an EndpointFilter which filters out exactly the endpoints that
+// the given filter keeps.
+func Not(filter EndpointFilter) EndpointFilter {
+	return func(e Endpoint) bool {
+		return !filter(e)
+	}
+}
+
+// ByRPCType returns an EndpointFilter which filters out the endpoints whose
+// RPC type is not one of the given RPC types.
+func ByRPCType(rpcTypes ...sharedtypes.RPCType) EndpointFilter {
+	allowed := make(map[sharedtypes.RPCType]struct{}, len(rpcTypes))
+	for _, rpcType := range rpcTypes {
+		allowed[rpcType] = struct{}{}
+	}
+
+	return func(e Endpoint) bool {
+		_, ok := allowed[e.Endpoint().RpcType]
+		return !ok
+	}
+}
+
+// BySupplierAllowlist returns an EndpointFilter which filters out the endpoints
+// of all the suppliers not included in the given allowlist.
+func BySupplierAllowlist(suppliers ...SupplierAddress) EndpointFilter {
+	allowed := make(map[SupplierAddress]struct{}, len(suppliers))
+	for _, supplier := range suppliers {
+		allowed[supplier] = struct{}{}
+	}
+
+	return func(e Endpoint) bool {
+		_, ok := allowed[e.Supplier()]
+		return !ok
+	}
+}
+
+// ByValidEndpoint returns an EndpointFilter which filters out the endpoints
+// failing validation, e.g. with a malformed URL or an unsupported URL scheme.
+// See NewTypedSupplierEndpoint.
+func ByValidEndpoint() EndpointFilter {
+	return func(e Endpoint) bool {
+		return !GetTypedEndpoint(e).Valid()
+	}
+}
+
+// ByURLScheme returns an EndpointFilter which filters out the endpoints whose
+// URL scheme, e.g. "https" or "wss", is not one of the given schemes.
+// Schemes are compared case-insensitively, and endpoints with a URL that can
+// not be parsed are always filtered out.
+func ByURLScheme(schemes ...string) EndpointFilter {
+	allowed := make(map[string]struct{}, len(schemes))
+	for _, scheme := range schemes {
+		allowed[strings.ToLower(scheme)] = struct{}{}
+	}
+
+	return func(e Endpoint) bool {
+		typed := GetTypedEndpoint(e)
+		if typed.URL == nil {
+			return true
+		}
+
+		_, ok := allowed[typed.Scheme]
+		return !ok
+	}
+}
+
+// ByWebSocketSupport returns an EndpointFilter which filters out the endpoints
+// not known to accept WebSocket upgrade requests, i.e. without the
+// EndpointWebSocketSupported value of the EndpointMetadataWebSocket metadata,
+// as set by the DecorateEndpoint method of a WebSocketProber.
+func ByWebSocketSupport() EndpointFilter {
+	return func(e Endpoint) bool {
+		capability, _ := GetEndpointMetadata(e, EndpointMetadataWebSocket)
+		return capability != EndpointWebSocketSupported
+	}
+}
+
+// TODO_IMPROVE: Use a dedicated onchain endpoint config option for the region,
+// once one is supported by the protocol.
+//
+// ByRegionHint returns an EndpointFilter which filters out the endpoints whose
+// region does not contain any of the given region hints, e.g. "us-east" or "eu".
+// The region is read from the EndpointMetadataRegion metadata of the endpoint
+// if set, and defaults to the hostname of the endpoint's URL otherwise.
+// Hints are matched case-insensitively, and endpoints without a region metadata
+// and with a URL that can not be parsed are always filtered out.
+func ByRegionHint(hints ...string) EndpointFilter {
+	lowerHints := make([]string, 0, len(hints))
+	for _, hint := range hints {
+		lowerHints = append(lowerHints, strings.ToLower(hint))
+	}
+
+	return func(e Endpoint) bool {
+		region, ok := GetEndpointMetadata(e, EndpointMetadataRegion)
+		if !ok {
+			typed := GetTypedEndpoint(e)
+			if typed.URL == nil {
+				return true
+			}
+			region = typed.URL.Hostname()
+		}
+
+		region = strings.ToLower(region)
+		for _, hint := range lowerHints {
+			if strings.Contains(region, hint) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,85 @@
+package sdk
+
+import (
+	"testing"
+
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointFilter_Combinators(t *testing.T) {
+	filterAll := func(Endpoint) bool { return true }
+	filterNone := func(Endpoint) bool { return false }
+
+	tests := []struct {
+		desc           string
+		filter         EndpointFilter
+		expectFiltered bool
+	}{
+		{desc: "And: all filters keep the endpoint", filter: And(filterNone, filterNone), expectFiltered: false},
+		{desc: "And: one filter filters the endpoint out", filter: And(filterNone, filterAll), expectFiltered: true},
+		{desc: "And: no filters", filter: And(), expectFiltered: false},
+		{desc: "Or: one filter keeps the endpoint", filter: Or(filterAll, filterNone), expectFiltered: false},
+		{desc: "Or: all filters filter the endpoint out", filter: Or(filterAll, filterAll), expectFiltered: true},
+		{desc: "Or: no filters", filter: Or(), expectFiltered: false},
+		{desc: "Not: inverts the filter", filter: Not(filterAll), expectFiltered: false},
+		{desc: "nested combinators", filter: Not(Or(filterAll, And(filterNone, filterNone))), expectFiltered: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			require.Equal(t, test.expectFiltered, test.filter(endpoint{}))
+		})
+	}
+}
+
+func TestEndpointFilter_Prebuilt(t *testing.T) {
+	testEndpoint := endpoint{
+		supplierEndpoint: sharedtypes.SupplierEndpoint{
+			Url:     "https://US-East-1.supplier.example:443/relay",
+			RpcType: sharedtypes.RPCType_JSON_RPC,
+		},
+		supplier: SupplierAddress("pokt1supplier"),
+	}
+
+	tests := []struct {
+		desc           string
+		filter         EndpointFilter
+		expectFiltered bool
+	}{
+		{desc: "matching RPC type", filter: ByRPCType(sharedtypes.RPCType_REST, sharedtypes.RPCType_JSON_RPC), expectFiltered: false},
+		{desc: "non-matching RPC type", filter: ByRPCType(sharedtypes.RPCType_REST), expectFiltered: true},
+		{desc: "allowlisted supplier", filter: BySupplierAllowlist("pokt1supplier"), expectFiltered: false},
+		{desc: "supplier not allowlisted", filter: BySupplierAllowlist("pokt1other"), expectFiltered: true},
+		{desc: "matching URL scheme", filter: ByURLScheme("HTTPS"), expectFiltered: false},
+		{desc: "non-matching URL scheme", filter: ByURLScheme("http", "wss"), expectFiltered: true},
+		{desc: "matching region hint", filter: ByRegionHint("eu-west", "us-east"), expectFiltered: false},
+		{desc: "non-matching region hint", filter: ByRegionHint("eu-west"), expectFiltered: true},
+		{
+			desc:           "intersection of matching filters",
+			filter:         And(ByRPCType(sharedtypes.RPCType_JSON_RPC), BySupplierAllowlist("pokt1supplier")),
+			expectFiltered: false,
+		},
+		{
+			desc:           "intersection with a non-matching filter",
+			filter:         And(ByRPCType(sharedtypes.RPCType_JSON_RPC), BySupplierAllowlist("pokt1other")),
+			expectFiltered: true,
+		},
+		{
+			desc:           "union with a matching filter",
+			filter:         Or(ByURLScheme("http"), BySupplierAllowlist("pokt1supplier")),
+			expectFiltered: false,
+		},
+		{
+			desc:           "union of non-matching filters",
+			filter:         Or(ByURLScheme("http"), Not(BySupplierAllowlist("pokt1supplier"))),
+			expectFiltered: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			require.Equal(t, test.expectFiltered, test.filter(testEndpoint))
+		})
+	}
+}
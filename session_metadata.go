@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+)
+
+// SessionMetadata holds metadata computed from a session, allowing operators to
+// detect degenerate sessions, e.g. sessions with a single supplier, before
+// user traffic suffers.
+type SessionMetadata struct {
+	// SupplierCount is the number of suppliers of the session.
+	SupplierCount int
+	// EndpointCount is the total number of endpoints of the session's suppliers,
+	// for the service of the session.
+	EndpointCount int
+	// SupplierSetHash is the hex-encoded SHA-256 hash of the sorted operator
+	// addresses of the session's suppliers.
+	// It can be compared across sessions to detect changes of the supplier set.
+	SupplierSetHash string
+}
+
+// NewSessionMetadata returns the metadata of the given session.
+func NewSessionMetadata(session *sessiontypes.Session) SessionMetadata {
+	if session == nil {
+		return SessionMetadata{}
+	}
+
+	var serviceId string
+	if session.Header != nil {
+		serviceId = session.Header.ServiceId
+	}
+
+	metadata := SessionMetadata{}
+	supplierAddresses := make([]string, 0, len(session.Suppliers))
+	for _, supplier := range session.Suppliers {
+		if supplier == nil {
+			continue
+		}
+
+		metadata.SupplierCount++
+		supplierAddresses = append(supplierAddresses, supplier.OperatorAddress)
+		for _, service := range supplier.Services {
+			if service != nil && service.ServiceId == serviceId {
+				metadata.EndpointCount += len(service.Endpoints)
+			}
+		}
+	}
+
+	slices.Sort(supplierAddresses)
+	hasher := sha256.New()
+	for _, supplierAddress := range supplierAddresses {
+		hasher.Write([]byte(supplierAddress))
+		// Separate the addresses to avoid ambiguous concatenations.
+		hasher.Write([]byte{'\n'})
+	}
+	metadata.SupplierSetHash = hex.EncodeToString(hasher.Sum(nil))
+
+	return metadata
+}
@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"testing"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSessionMetadata(t *testing.T) {
+	supplier1 := &sharedtypes.Supplier{
+		OperatorAddress: "pokt1supplier1",
+		Services: []*sharedtypes.SupplierServiceConfig{
+			{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://s1.example"}, {Url: "wss://s1.example"}},
+			},
+			{
+				ServiceId: "svc2",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://s1.example/svc2"}},
+			},
+		},
+	}
+	supplier2 := &sharedtypes.Supplier{
+		OperatorAddress: "pokt1supplier2",
+		Services: []*sharedtypes.SupplierServiceConfig{
+			{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://s2.example"}},
+			},
+		},
+	}
+
+	header := &sessiontypes.SessionHeader{ServiceId: "svc1"}
+	metadata := NewSessionMetadata(&sessiontypes.Session{
+		Header:    header,
+		Suppliers: []*sharedtypes.Supplier{supplier1, supplier2},
+	})
+	require.Equal(t, 2, metadata.SupplierCount)
+	require.Equal(t, 3, metadata.EndpointCount)
+
+	// The supplier set hash does not depend on the order of the suppliers.
+	reorderedMetadata := NewSessionMetadata(&sessiontypes.Session{
+		Header:    header,
+		Suppliers: []*sharedtypes.Supplier{supplier2, supplier1},
+	})
+	require.Equal(t, metadata.SupplierSetHash, reorderedMetadata.SupplierSetHash)
+
+	// The supplier set hash changes with the supplier set.
+	singleSupplierMetadata := NewSessionMetadata(&sessiontypes.Session{
+		Header:    header,
+		Suppliers: []*sharedtypes.Supplier{supplier1},
+	})
+	require.Equal(t, 1, singleSupplierMetadata.SupplierCount)
+	require.NotEqual(t, metadata.SupplierSetHash, singleSupplierMetadata.SupplierSetHash)
+
+	require.Equal(t, SessionMetadata{}, NewSessionMetadata(nil))
+}
@@ -0,0 +1,231 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+)
+
+// defaultSessionPublishTimeout is the timeout of the publication of a session
+// refresh to the other regions.
+const defaultSessionPublishTimeout = 5 * time.Second
+
+// SessionPubSub is a publish/subscribe system shared by the gateways of a
+// fleet, e.g. backed by NATS subjects or Redis channels, used to replicate the
+// sessions fetched by each gateway to the others.
+type SessionPubSub interface {
+	// Publish publishes the given message to all the subscribers.
+	Publish(ctx context.Context, msg []byte) error
+	// Subscribe calls the given handler with every published message, until
+	// the given context is done.
+	Subscribe(ctx context.Context, handler func(msg []byte)) error
+}
+
+// SessionRefreshMessage is a session fetched from the full node by a gateway,
+// as replicated to the other gateways of the fleet.
+type SessionRefreshMessage struct {
+	// Origin identifies the gateway, or region, which fetched the session, so
+	// it can ignore its own messages.
+	Origin          string    `json:"origin"`
+	AppAddress      string    `json:"app_address"`
+	ServiceId       string    `json:"service_id"`
+	FetchedAtHeight int64     `json:"fetched_at_height"`
+	FetchedAt       time.Time `json:"fetched_at"`
+	// Session is the protobuf-serialized session.
+	Session []byte `json:"session"`
+}
+
+// SessionReplicatorOption is a functional option used to configure a SessionReplicator.
+type SessionReplicatorOption func(*SessionReplicator)
+
+// WithReplicationErrorObserver sets a function called with the errors of the
+// replication, e.g. failed publications or invalid received messages, e.g. to
+// log them. Replication errors are ignored otherwise.
+func WithReplicationErrorObserver(observer func(err error)) SessionReplicatorOption {
+	return func(r *SessionReplicator) {
+		r.errorObserver = observer
+	}
+}
+
+// SessionReplicator replicates the sessions fetched by a SessionCache to the
+// SessionCaches of the other gateways of a fleet, e.g. deployed in multiple
+// regions, through a SessionPubSub, so the fleet shares the session fetches
+// instead of each gateway querying the full node independently.
+//
+// Its HandleEvent method publishes the SessionRefreshedEvents, and must be
+// subscribed to the EventBus of the SessionCache, preferably through
+// NewAsyncEventHandler so the publications do not delay the relays. Its Run
+// method applies the sessions published by the other gateways to the
+// SessionCache.
+type SessionReplicator struct {
+	sessionCache  *SessionCache
+	pubSub        SessionPubSub
+	origin        string
+	errorObserver func(err error)
+}
+
+// NewSessionReplicator returns a SessionReplicator replicating the sessions of
+// the given SessionCache through the given SessionPubSub.
+// The origin identifies the gateway, and must be unique across the fleet.
+func NewSessionReplicator(
+	sessionCache *SessionCache,
+	pubSub SessionPubSub,
+	origin string,
+	opts ...SessionReplicatorOption,
+) *SessionReplicator {
+	r := &SessionReplicator{
+		sessionCache: sessionCache,
+		pubSub:       pubSub,
+		origin:       origin,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// HandleEvent publishes the sessions of the SessionRefreshedEvents.
+func (r *SessionReplicator) HandleEvent(event Event) {
+	refreshedEvent, ok := event.(SessionRefreshedEvent)
+	if !ok || refreshedEvent.Session.Session == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultSessionPublishTimeout)
+	defer cancel()
+
+	if err := r.Publish(ctx, refreshedEvent.Session); err != nil {
+		r.observeError(err)
+	}
+}
+
+// Publish publishes the given session to the other gateways.
+func (r *SessionReplicator) Publish(ctx context.Context, sessionInfo SessionInfo) error {
+	sessionBz, err := sessionInfo.Session.Marshal()
+	if err != nil {
+		return fmt.Errorf("Publish: error serializing session %s: %w", sessionInfo.SessionId, err)
+	}
+
+	msg, err := json.Marshal(SessionRefreshMessage{
+		Origin:          r.origin,
+		AppAddress:      sessionInfo.GetHeader().GetApplicationAddress(),
+		ServiceId:       sessionInfo.GetHeader().GetServiceId(),
+		FetchedAtHeight: sessionInfo.FetchedAtHeight,
+		FetchedAt:       sessionInfo.FetchedAt,
+		Session:         sessionBz,
+	})
+	if err != nil {
+		return fmt.Errorf("Publish: error encoding session %s: %w", sessionInfo.SessionId, err)
+	}
+
+	if err := r.pubSub.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("Publish: error publishing session %s: %w", sessionInfo.SessionId, err)
+	}
+	return nil
+}
+
+// Run applies the sessions published by the other gateways to the
+// SessionCache, until the given context is done.
+func (r *SessionReplicator) Run(ctx context.Context) error {
+	err := r.pubSub.Subscribe(ctx, func(msg []byte) {
+		if _, err := r.Apply(msg); err != nil {
+			r.observeError(err)
+		}
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("Run: %w", err)
+	}
+	return nil
+}
+
+// Apply caches the session of the given SessionRefreshMessage, unless it was
+// published by this gateway, or a session of the same application and service
+// ending at the same or a later height is already cached.
+// It returns true if the session was cached.
+func (r *SessionReplicator) Apply(msg []byte) (bool, error) {
+	var refreshMsg SessionRefreshMessage
+	if err := json.Unmarshal(msg, &refreshMsg); err != nil {
+		return false, fmt.Errorf("Apply: error decoding the message: %w", err)
+	}
+	if refreshMsg.Origin == r.origin {
+		return false, nil
+	}
+
+	session := new(sessiontypes.Session)
+	if err := session.Unmarshal(refreshMsg.Session); err != nil {
+		return false, fmt.Errorf("Apply: error deserializing the session from %s: %w", refreshMsg.Origin, err)
+	}
+	if session.Header == nil ||
+		session.Header.ApplicationAddress != refreshMsg.AppAddress ||
+		session.Header.ServiceId != refreshMsg.ServiceId {
+		return false, fmt.Errorf(
+			"Apply: session from %s does not match application %s and service %s",
+			refreshMsg.Origin,
+			refreshMsg.AppAddress,
+			refreshMsg.ServiceId,
+		)
+	}
+
+	return r.sessionCache.SetReplicatedSession(SessionInfo{
+		Session:         session,
+		FetchedAtHeight: refreshMsg.FetchedAtHeight,
+		FetchedAt:       refreshMsg.FetchedAt,
+	}), nil
+}
+
+// observeError notifies the error observer, if any, of the given error.
+func (r *SessionReplicator) observeError(err error) {
+	if r.errorObserver != nil {
+		r.errorObserver(err)
+	}
+}
+
+// NewInMemorySessionPubSub returns a SessionPubSub delivering the messages to
+// the subscribers of the same process, e.g. to replicate the sessions between
+// multiple SessionCaches of a process, or in tests.
+func NewInMemorySessionPubSub() SessionPubSub {
+	return &inMemorySessionPubSub{
+		subscribers: make(map[uint64]func(msg []byte)),
+	}
+}
+
+// inMemorySessionPubSub is a SessionPubSub within a single process.
+type inMemorySessionPubSub struct {
+	mu          sync.RWMutex
+	nextId      uint64
+	subscribers map[uint64]func(msg []byte)
+}
+
+// Publish calls the handlers of the current subscribers with the given message.
+func (ps *inMemorySessionPubSub) Publish(_ context.Context, msg []byte) error {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	for _, handler := range ps.subscribers {
+		handler(msg)
+	}
+	return nil
+}
+
+// Subscribe registers the given handler until the given context is done.
+func (ps *inMemorySessionPubSub) Subscribe(ctx context.Context, handler func(msg []byte)) error {
+	ps.mu.Lock()
+	id := ps.nextId
+	ps.nextId++
+	ps.subscribers[id] = handler
+	ps.mu.Unlock()
+
+	<-ctx.Done()
+
+	ps.mu.Lock()
+	delete(ps.subscribers, id)
+	ps.mu.Unlock()
+
+	return ctx.Err()
+}
@@ -0,0 +1,131 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+func TestSessionReplicator(t *testing.T) {
+	pubSub := NewInMemorySessionPubSub()
+
+	// Two gateways, in different regions, sharing their session fetches.
+	newGateway := func(origin string) (*SessionCache, *countingSessionFetcher) {
+		fetcher := &countingSessionFetcher{heightSessionFetcher: heightSessionFetcher{numBlocksPerSession: 10}}
+		bus := NewEventBus()
+		sc := NewSessionCacheWithOptions(
+			&SessionClient{PoktNodeSessionFetcher: fetcher},
+			WithCacheConfig(cache.Config{TTL: time.Minute}),
+			WithEventBus(bus),
+		)
+
+		replicator := NewSessionReplicator(sc, pubSub, origin, WithReplicationErrorObserver(func(err error) {
+			require.NoError(t, err)
+		}))
+		bus.Subscribe(replicator.HandleEvent, EventSessionRefreshed)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go func() { require.NoError(t, replicator.Run(ctx)) }()
+
+		return sc, fetcher
+	}
+	cacheA, fetcherA := newGateway("region-a")
+	cacheB, fetcherB := newGateway("region-b")
+
+	// Wait for both replicators to subscribe.
+	require.Eventually(t, func() bool {
+		ps := pubSub.(*inMemorySessionPubSub)
+		ps.mu.RLock()
+		defer ps.mu.RUnlock()
+		return len(ps.subscribers) == 2
+	}, time.Second, time.Millisecond)
+
+	ctx := context.Background()
+	sessionA, err := cacheA.GetSession(ctx, "app1", "svc1", 12)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceFullNode, sessionA.Source)
+
+	// The session fetched by region A is served from region B's cache.
+	sessionB, err := cacheB.GetSession(ctx, "app1", "svc1", 15)
+	require.NoError(t, err)
+	require.Equal(t, SessionSourceCache, sessionB.Source)
+	require.Equal(t, sessionA.Header.SessionEndBlockHeight, sessionB.Header.SessionEndBlockHeight)
+	require.Equal(t, sessionA.FetchedAtHeight, sessionB.FetchedAtHeight)
+	require.Equal(t, int64(1), fetcherA.calls.Load())
+	require.Equal(t, int64(0), fetcherB.calls.Load())
+}
+
+func TestSessionReplicator_Apply(t *testing.T) {
+	sc := NewSessionCache(&SessionClient{PoktNodeSessionFetcher: &fakeSessionFetcher{}}, cache.Config{})
+	replicator := NewSessionReplicator(sc, NewInMemorySessionPubSub(), "region-a")
+
+	newMsg := func(origin string, endHeight int64, appAddress string) []byte {
+		session := &sessiontypes.Session{
+			Header: &sessiontypes.SessionHeader{
+				ApplicationAddress:    "app1",
+				ServiceId:             "svc1",
+				SessionEndBlockHeight: endHeight,
+			},
+		}
+		sessionBz, err := session.Marshal()
+		require.NoError(t, err)
+
+		msg, err := json.Marshal(SessionRefreshMessage{
+			Origin:     origin,
+			AppAddress: appAddress,
+			ServiceId:  "svc1",
+			Session:    sessionBz,
+		})
+		require.NoError(t, err)
+		return msg
+	}
+
+	// The gateway's own messages are ignored.
+	applied, err := replicator.Apply(newMsg("region-a", 10, "app1"))
+	require.NoError(t, err)
+	require.False(t, applied)
+
+	applied, err = replicator.Apply(newMsg("region-b", 10, "app1"))
+	require.NoError(t, err)
+	require.True(t, applied)
+
+	// Older or identical sessions do not replace the cached one.
+	applied, err = replicator.Apply(newMsg("region-b", 10, "app1"))
+	require.NoError(t, err)
+	require.False(t, applied)
+
+	applied, err = replicator.Apply(newMsg("region-b", 20, "app1"))
+	require.NoError(t, err)
+	require.True(t, applied)
+
+	// Sessions not matching their message are rejected.
+	_, err = replicator.Apply(newMsg("region-b", 30, "app2"))
+	require.Error(t, err)
+
+	_, err = replicator.Apply([]byte("not json"))
+	require.Error(t, err)
+}
+
+// countingSessionFetcher is a heightSessionFetcher which counts the number of fetches.
+type countingSessionFetcher struct {
+	heightSessionFetcher
+	calls atomic.Int64
+}
+
+func (f *countingSessionFetcher) GetSession(
+	ctx context.Context,
+	req *sessiontypes.QueryGetSessionRequest,
+	opts ...grpcoptions.CallOption,
+) (*sessiontypes.QueryGetSessionResponse, error) {
+	f.calls.Add(1)
+	return f.heightSessionFetcher.GetSession(ctx, req, opts...)
+}
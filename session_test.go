@@ -2,10 +2,16 @@ package sdk
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"testing"
+	"time"
 
 	"github.com/cosmos/gogoproto/grpc"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
 	"github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
 )
 
 func ExampleSessionClient() {
@@ -65,3 +71,154 @@ func ExampleSessionClient() {
 		)
 	}
 }
+
+func TestSessionClient_GetActiveSessionsAtHeight(t *testing.T) {
+	tests := []struct {
+		desc           string
+		sessionNumbers map[string]int64
+		fetchErr       error
+		expectErr      bool
+	}{
+		{
+			desc:           "sessions with consistent session numbers",
+			sessionNumbers: map[string]int64{"svc1": 5, "svc2": 5},
+		},
+		{
+			desc:           "sessions with inconsistent session numbers",
+			sessionNumbers: map[string]int64{"svc1": 5, "svc2": 6},
+			expectErr:      true,
+		},
+		{
+			desc:           "error fetching sessions",
+			sessionNumbers: map[string]int64{"svc1": 5, "svc2": 5},
+			fetchErr:       errors.New("fetch error"),
+			expectErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			sc := SessionClient{
+				PoktNodeSessionFetcher: &fakeSessionFetcher{
+					sessionNumbers: test.sessionNumbers,
+					err:            test.fetchErr,
+				},
+			}
+
+			sessions, err := sc.GetActiveSessionsAtHeight(
+				context.Background(),
+				[]string{"svc1", "svc2"},
+				[]string{"app1", "app2"},
+				42,
+			)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, sessions, 4)
+			for key, session := range sessions {
+				require.Equal(t, key.AppAddress, session.Header.ApplicationAddress)
+				require.Equal(t, key.ServiceId, session.Header.ServiceId)
+			}
+		})
+	}
+}
+
+func TestSessionClient_GetActiveSessionsAtHeight_MaxParallelFetches(t *testing.T) {
+	const maxParallelFetches = 3
+	fetcher := &concurrencySessionFetcher{release: make(chan struct{})}
+	sc := SessionClient{PoktNodeSessionFetcher: fetcher}
+
+	appAddresses := make([]string, 10)
+	for i := range appAddresses {
+		appAddresses[i] = fmt.Sprintf("app%d", i)
+	}
+
+	var (
+		sessions map[SessionKey]*sessiontypes.Session
+		err      error
+		done     = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		sessions, err = sc.GetActiveSessionsAtHeight(
+			context.Background(),
+			[]string{"svc1", "svc2"},
+			appAddresses,
+			42,
+			WithMaxParallelSessionFetches(maxParallelFetches),
+		)
+	}()
+
+	require.Eventually(t, func() bool { return fetcher.inFlight.Load() == maxParallelFetches }, time.Second, time.Millisecond)
+	close(fetcher.release)
+	<-done
+
+	require.NoError(t, err)
+	require.Len(t, sessions, 20)
+	require.Equal(t, int32(maxParallelFetches), fetcher.maxInFlight.Load())
+}
+
+func TestSessionClient_GetActiveSessionsAtHeightPartial(t *testing.T) {
+	errUnstaked := errors.New("application is not staked")
+	sc := SessionClient{
+		PoktNodeSessionFetcher: &fakeSessionFetcher{
+			sessionNumbers: map[string]int64{"svc1": 5, "svc2": 5, "svc3": 6},
+			appErrs:        map[string]error{"app2": errUnstaked},
+		},
+	}
+
+	activeSessions := sc.GetActiveSessionsAtHeightPartial(
+		context.Background(),
+		[]string{"svc1", "svc2", "svc3"},
+		[]string{"app1", "app2"},
+		42,
+	)
+
+	// The sessions of the healthy app are returned, except for the one with
+	// an inconsistent session number.
+	require.Len(t, activeSessions.Sessions, 2)
+	require.Contains(t, activeSessions.Sessions, SessionKey{AppAddress: "app1", ServiceId: "svc1"})
+	require.Contains(t, activeSessions.Sessions, SessionKey{AppAddress: "app1", ServiceId: "svc2"})
+
+	require.Len(t, activeSessions.Errors, 4)
+	require.ErrorContains(t, activeSessions.Errors[SessionKey{AppAddress: "app1", ServiceId: "svc3"}], "inconsistent session number")
+
+	appErrors := activeSessions.AppErrors()
+	require.Len(t, appErrors, 2)
+	require.ErrorIs(t, appErrors["app2"], errUnstaked)
+}
+
+// fakeSessionFetcher is a PoktNodeSessionFetcher which returns sessions with
+// the session number configured for the requested service id.
+type fakeSessionFetcher struct {
+	sessionNumbers map[string]int64
+	err            error
+	// appErrs holds the errors returned for specific application addresses.
+	appErrs map[string]error
+}
+
+func (f *fakeSessionFetcher) GetSession(
+	_ context.Context,
+	req *sessiontypes.QueryGetSessionRequest,
+	_ ...grpcoptions.CallOption,
+) (*sessiontypes.QueryGetSessionResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if err := f.appErrs[req.ApplicationAddress]; err != nil {
+		return nil, err
+	}
+
+	return &sessiontypes.QueryGetSessionResponse{
+		Session: &sessiontypes.Session{
+			Header: &sessiontypes.SessionHeader{
+				ApplicationAddress: req.ApplicationAddress,
+				ServiceId:          req.ServiceId,
+			},
+			SessionNumber: f.sessionNumbers[req.ServiceId],
+		},
+	}, nil
+}
@@ -0,0 +1,244 @@
+package sdk
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+)
+
+// RelayUsage is the usage of a single relay, as recorded by a SessionUsageTracker.
+type RelayUsage struct {
+	// RequestBytes is the size of the relay request sent to the supplier.
+	RequestBytes int64
+	// ResponseBytes is the size of the relay response received from the supplier.
+	ResponseBytes int64
+	// ComputeUnits is the estimated number of compute units of the relay,
+	// e.g. the ComputeUnitsPerRelay of its service.
+	ComputeUnits uint64
+	// Failed is true if the relay failed. Failed relays are counted separately,
+	// and their compute units are not accounted.
+	Failed bool
+}
+
+// CloseSessionReport is the usage of a session by an application, reported by a
+// SessionUsageTracker once the session is closed, e.g. for customer billing
+// reconciliation by centralized gateways.
+//
+// It is published on the EventBus as an Event of type EventSessionClosed.
+type CloseSessionReport struct {
+	AppAddress         string
+	ServiceId          string
+	SessionId          string
+	SessionStartHeight int64
+	SessionEndHeight   int64
+
+	// Relays is the number of successful relays.
+	Relays uint64
+	// FailedRelays is the number of failed relays.
+	FailedRelays uint64
+	// RequestBytes is the total size of the relay requests, including the failed ones.
+	RequestBytes uint64
+	// ResponseBytes is the total size of the relay responses, including the failed ones.
+	ResponseBytes uint64
+	// ComputeUnits is the estimated number of compute units of the successful relays.
+	ComputeUnits uint64
+
+	// FirstRelayAt and LastRelayAt are the times of the first and last relays
+	// recorded for the session.
+	FirstRelayAt time.Time
+	LastRelayAt  time.Time
+	// ClosedAt is the time at which the session was closed.
+	ClosedAt time.Time
+}
+
+// EventType returns EventSessionClosed.
+func (CloseSessionReport) EventType() EventType { return EventSessionClosed }
+
+// SessionUsageTracker accounts the relays sent for each application and session,
+// and reports the usage of each session once it rolls over, through the EventBus
+// and the configured report sinks, e.g. the Send method of an AsyncSink writing
+// to an audit log.
+//
+// Sessions are closed once their grace period is over: when a session refreshed
+// past it is observed, if HandleEvent is subscribed to the SessionRefreshedEvents,
+// or when CloseEndedSessions is called, e.g. from a BlockScheduler.
+// It is safe for concurrent use.
+type SessionUsageTracker struct {
+	// GracePeriodBlocks is the number of blocks after their end height during
+	// which relays are still accepted for the sessions, e.g. the
+	// GracePeriodEndOffsetBlocks of the shared module params.
+	// HandleEvent keeps the sessions open until it is over, so the relays sent
+	// during the grace period are accounted to their session's report.
+	// It must be set before the tracker is used.
+	GracePeriodBlocks int64
+
+	eventBus    *EventBus
+	reportSinks []func(CloseSessionReport)
+
+	// mu protects sessions, which holds the usage of the open sessions by session id.
+	mu       sync.Mutex
+	sessions map[string]*CloseSessionReport
+}
+
+// NewSessionUsageTracker returns a SessionUsageTracker publishing the reports of
+// the closed sessions on the given EventBus, which may be nil, and passing them
+// to the given report sinks.
+func NewSessionUsageTracker(eventBus *EventBus, reportSinks ...func(CloseSessionReport)) *SessionUsageTracker {
+	return &SessionUsageTracker{
+		eventBus:    eventBus,
+		reportSinks: reportSinks,
+		sessions:    make(map[string]*CloseSessionReport),
+	}
+}
+
+// RecordRelay accounts the given relay usage to the session of the given header.
+func (t *SessionUsageTracker) RecordRelay(header sessiontypes.SessionHeader, usage RelayUsage) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report, ok := t.sessions[header.SessionId]
+	if !ok {
+		report = &CloseSessionReport{
+			AppAddress:         header.ApplicationAddress,
+			ServiceId:          header.ServiceId,
+			SessionId:          header.SessionId,
+			SessionStartHeight: header.SessionStartBlockHeight,
+			SessionEndHeight:   header.SessionEndBlockHeight,
+			FirstRelayAt:       now,
+		}
+		t.sessions[header.SessionId] = report
+	}
+
+	if usage.Failed {
+		report.FailedRelays++
+	} else {
+		report.Relays++
+		report.ComputeUnits += usage.ComputeUnits
+	}
+	report.RequestBytes += uint64(max(usage.RequestBytes, 0))
+	report.ResponseBytes += uint64(max(usage.ResponseBytes, 0))
+	report.LastRelayAt = now
+}
+
+// RelayInterceptor returns a RelayInterceptor recording the usage of every relay
+// attempt of a GatewayClient, e.g. set in its RelayInterceptors: the size of the
+// signed relay request, the size of the relay response, and the compute units
+// of the relay's service, returned by the given function, e.g. backed by the
+// ComputeUnitsPerRelay of the onchain services. The relays are not accounted
+// any compute units if the function is nil.
+//
+// The failed relay attempts are recorded as failed, including the ones failing
+// before they are sent, e.g. to be signed.
+func (t *SessionUsageTracker) RelayInterceptor(computeUnitsPerRelay func(serviceId string) uint64) RelayInterceptor {
+	return func(next RelayInvoker) RelayInvoker {
+		return func(
+			ctx context.Context,
+			endpoint Endpoint,
+			relayRequest *servicetypes.RelayRequest,
+		) (*servicetypes.RelayResponse, error) {
+			relayResponse, err := next(ctx, endpoint, relayRequest)
+
+			header := relayRequest.GetMeta().SessionHeader
+			if header == nil {
+				return relayResponse, err
+			}
+			// The relay request is signed in place, so its size includes the signature.
+			usage := RelayUsage{
+				RequestBytes: int64(relayRequest.Size()),
+				Failed:       err != nil,
+			}
+			if relayResponse != nil {
+				usage.ResponseBytes = int64(relayResponse.Size())
+			}
+			if computeUnitsPerRelay != nil {
+				usage.ComputeUnits = computeUnitsPerRelay(header.ServiceId)
+			}
+			t.RecordRelay(*header, usage)
+
+			return relayResponse, err
+		}
+	}
+}
+
+// Usage returns the usage accounted so far to the open session with the given
+// id, or false if no relay was recorded for it.
+func (t *SessionUsageTracker) Usage(sessionId string) (CloseSessionReport, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report, ok := t.sessions[sessionId]
+	if !ok {
+		return CloseSessionReport{}, false
+	}
+	return *report, true
+}
+
+// HandleEvent closes the sessions whose grace period was over at the height the
+// session of a SessionRefreshedEvent was fetched at, i.e. which ended more than
+// GracePeriodBlocks blocks before it.
+// Sessions replaced by the refreshed one are kept open during their grace period.
+// It can be subscribed to the EventBus of a SessionCache.
+func (t *SessionUsageTracker) HandleEvent(event Event) {
+	refreshed, ok := event.(SessionRefreshedEvent)
+	if !ok || refreshed.Session.FetchedAtHeight <= 0 {
+		return
+	}
+
+	t.CloseEndedSessions(refreshed.Session.FetchedAtHeight - t.GracePeriodBlocks)
+}
+
+// CloseEndedSessions closes the open sessions which ended before the given
+// height, and returns their reports.
+// Callers accepting relays during the grace period of the sessions should
+// subtract it from the given height.
+func (t *SessionUsageTracker) CloseEndedSessions(height int64) []CloseSessionReport {
+	return t.close(func(report *CloseSessionReport) bool {
+		return report.SessionEndHeight < height
+	})
+}
+
+// CloseAll closes all the open sessions and returns their reports, e.g. to
+// flush the usage when the gateway shuts down.
+func (t *SessionUsageTracker) CloseAll() []CloseSessionReport {
+	return t.close(func(*CloseSessionReport) bool { return true })
+}
+
+// close removes the open sessions matching the given function, and reports
+// their usage, in the order of their application, service, and session id.
+func (t *SessionUsageTracker) close(match func(*CloseSessionReport) bool) []CloseSessionReport {
+	now := time.Now()
+
+	t.mu.Lock()
+	var reports []CloseSessionReport
+	for sessionId, report := range t.sessions {
+		if match(report) {
+			report.ClosedAt = now
+			reports = append(reports, *report)
+			delete(t.sessions, sessionId)
+		}
+	}
+	t.mu.Unlock()
+
+	slices.SortFunc(reports, func(a, b CloseSessionReport) int {
+		return cmp.Or(
+			cmp.Compare(a.AppAddress, b.AppAddress),
+			cmp.Compare(a.ServiceId, b.ServiceId),
+			cmp.Compare(a.SessionId, b.SessionId),
+		)
+	})
+
+	for _, report := range reports {
+		t.eventBus.Publish(report)
+		for _, sink := range t.reportSinks {
+			sink(report)
+		}
+	}
+	return reports
+}
@@ -0,0 +1,155 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionUsageTracker(t *testing.T) {
+	bus := NewEventBus()
+	var published []CloseSessionReport
+	bus.Subscribe(func(event Event) {
+		published = append(published, event.(CloseSessionReport))
+	}, EventSessionClosed)
+
+	var sunk []CloseSessionReport
+	tracker := NewSessionUsageTracker(bus, func(report CloseSessionReport) {
+		sunk = append(sunk, report)
+	})
+	bus.Subscribe(tracker.HandleEvent, EventSessionRefreshed)
+
+	session1 := sessiontypes.SessionHeader{
+		ApplicationAddress:      "pokt1app1",
+		ServiceId:               "svc1",
+		SessionId:               "session1",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   10,
+	}
+	session2 := sessiontypes.SessionHeader{
+		ApplicationAddress:      "pokt1app2",
+		ServiceId:               "svc1",
+		SessionId:               "session2",
+		SessionStartBlockHeight: 11,
+		SessionEndBlockHeight:   20,
+	}
+
+	tracker.RecordRelay(session1, RelayUsage{RequestBytes: 100, ResponseBytes: 1000, ComputeUnits: 5})
+	tracker.RecordRelay(session1, RelayUsage{RequestBytes: 50, ResponseBytes: 10, ComputeUnits: 5, Failed: true})
+	tracker.RecordRelay(session2, RelayUsage{RequestBytes: 10, ResponseBytes: 20, ComputeUnits: 3})
+
+	usage, ok := tracker.Usage("session1")
+	require.True(t, ok)
+	require.Equal(t, uint64(1), usage.Relays)
+	require.Equal(t, uint64(1), usage.FailedRelays)
+	require.Equal(t, uint64(150), usage.RequestBytes)
+	require.Equal(t, uint64(1010), usage.ResponseBytes)
+	require.Equal(t, uint64(5), usage.ComputeUnits)
+
+	// Refreshing a new session of the same application and service does not
+	// close the previous one during its grace period, so the relays sent during
+	// the grace period are accounted to it.
+	tracker.GracePeriodBlocks = 2
+	bus.Publish(SessionRefreshedEvent{
+		AppAddress: "pokt1app1",
+		ServiceId:  "svc1",
+		Session:    SessionInfo{Session: &sessiontypes.Session{SessionId: "session3"}, FetchedAtHeight: 11},
+	})
+	require.Empty(t, published)
+
+	tracker.RecordRelay(session1, RelayUsage{RequestBytes: 10, ResponseBytes: 10, ComputeUnits: 5})
+	bus.Publish(SessionRefreshedEvent{
+		AppAddress: "pokt1app1",
+		ServiceId:  "svc1",
+		Session:    SessionInfo{Session: &sessiontypes.Session{SessionId: "session3"}, FetchedAtHeight: 12},
+	})
+	require.Empty(t, published)
+
+	// The session is closed once a session is refreshed past its grace period.
+	bus.Publish(SessionRefreshedEvent{
+		AppAddress: "pokt1app1",
+		ServiceId:  "svc1",
+		Session:    SessionInfo{Session: &sessiontypes.Session{SessionId: "session3"}, FetchedAtHeight: 13},
+	})
+	require.Len(t, published, 1)
+	require.Equal(t, sunk, published)
+
+	report := published[0]
+	require.Equal(t, "pokt1app1", report.AppAddress)
+	require.Equal(t, "session1", report.SessionId)
+	require.Equal(t, int64(10), report.SessionEndHeight)
+	require.Equal(t, uint64(2), report.Relays)
+	require.Equal(t, uint64(10), report.ComputeUnits)
+	require.False(t, report.ClosedAt.Before(report.LastRelayAt))
+
+	_, ok = tracker.Usage("session1")
+	require.False(t, ok)
+
+	// The sessions are closed once their end height is passed.
+	require.Empty(t, tracker.CloseEndedSessions(20))
+	reports := tracker.CloseEndedSessions(21)
+	require.Len(t, reports, 1)
+	require.Equal(t, "session2", reports[0].SessionId)
+	require.Equal(t, uint64(3), reports[0].ComputeUnits)
+	require.Len(t, published, 2)
+
+	require.Empty(t, tracker.CloseAll())
+}
+
+func TestSessionUsageTracker_RelayInterceptor(t *testing.T) {
+	tracker := NewSessionUsageTracker(nil)
+	computeUnitsPerRelay := func(serviceId string) uint64 {
+		return map[string]uint64{"svc1": 7}[serviceId]
+	}
+
+	errRelay := errors.New("relay failed")
+	invoke := tracker.RelayInterceptor(computeUnitsPerRelay)(func(
+		_ context.Context,
+		_ Endpoint,
+		relayRequest *servicetypes.RelayRequest,
+	) (*servicetypes.RelayResponse, error) {
+		if string(relayRequest.Payload) == "fail" {
+			return nil, errRelay
+		}
+		// The relay request is signed in place by the invoker.
+		relayRequest.Meta.Signature = []byte("signature")
+		return &servicetypes.RelayResponse{Payload: []byte("response payload")}, nil
+	})
+
+	newRelayRequest := func(payload string) *servicetypes.RelayRequest {
+		return &servicetypes.RelayRequest{
+			Meta: servicetypes.RelayRequestMetadata{
+				SessionHeader: &sessiontypes.SessionHeader{
+					ApplicationAddress:      "pokt1app1",
+					ServiceId:               "svc1",
+					SessionId:               "session1",
+					SessionStartBlockHeight: 1,
+					SessionEndBlockHeight:   10,
+				},
+			},
+			Payload: []byte(payload),
+		}
+	}
+
+	relayRequest := newRelayRequest("request payload")
+	relayResponse, err := invoke(context.Background(), nil, relayRequest)
+	require.NoError(t, err)
+	_, err = invoke(context.Background(), nil, newRelayRequest("fail"))
+	require.ErrorIs(t, err, errRelay)
+	// The relays without a session header are not recorded.
+	_, err = invoke(context.Background(), nil, &servicetypes.RelayRequest{})
+	require.NoError(t, err)
+
+	usage, ok := tracker.Usage("session1")
+	require.True(t, ok)
+	require.Equal(t, "pokt1app1", usage.AppAddress)
+	require.Equal(t, uint64(1), usage.Relays)
+	require.Equal(t, uint64(1), usage.FailedRelays)
+	require.Equal(t, uint64(relayRequest.Size()+newRelayRequest("fail").Size()), usage.RequestBytes)
+	require.Equal(t, uint64(relayResponse.Size()), usage.ResponseBytes)
+	require.Equal(t, uint64(7), usage.ComputeUnits)
+}
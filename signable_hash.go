@@ -0,0 +1,106 @@
+package sdk
+
+import (
+	"fmt"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// ErrUnsupportedHashVersion is returned when none of the requested signable
+// bytes hash versions is supported by the SDK.
+var ErrUnsupportedHashVersion = sdkerrors.ErrUnsupportedHashVersion
+
+// SignableBytesHashVersion identifies the scheme used to hash the signable
+// bytes of relay requests and responses, i.e. the bytes signed by the gateways
+// and the suppliers.
+type SignableBytesHashVersion string
+
+// SignableBytesHashSHA256 is the scheme of the current protocol: the SHA-256
+// hash of the marshaled message, with its signature removed.
+const SignableBytesHashSHA256 SignableBytesHashVersion = "sha256"
+
+// SignableBytesHasher hashes the signable bytes of relay requests and responses.
+//
+// The Signer, the RelayResponseValidator and the RelayVerifier use the
+// DefaultSignableBytesHasher unless configured otherwise, so a change of the
+// protocol's hashing scheme can be rolled out by negotiating the version used
+// with the suppliers, e.g. using NegotiateSignableBytesHasher, instead of
+// requiring a new SDK release.
+type SignableBytesHasher interface {
+	// Version returns the hashing scheme implemented by the hasher.
+	Version() SignableBytesHashVersion
+	// RelayRequestHash returns the hash signed by the application's ring.
+	RelayRequestHash(relayRequest *servicetypes.RelayRequest) ([32]byte, error)
+	// RelayResponseHash returns the hash signed by the supplier's operator.
+	RelayResponseHash(relayResponse *servicetypes.RelayResponse) ([32]byte, error)
+}
+
+// DefaultSignableBytesHasher is the SignableBytesHasher implementing the
+// scheme of the current protocol, SignableBytesHashSHA256.
+var DefaultSignableBytesHasher SignableBytesHasher = sha256SignableBytesHasher{}
+
+// signableBytesHashers are the hashers supported by the SDK, by version.
+var signableBytesHashers = map[SignableBytesHashVersion]SignableBytesHasher{
+	SignableBytesHashSHA256: DefaultSignableBytesHasher,
+}
+
+// SignableBytesHasherForVersion returns the SignableBytesHasher implementing
+// the given version, or an error wrapping ErrUnsupportedHashVersion if the SDK
+// does not support it.
+func SignableBytesHasherForVersion(version SignableBytesHashVersion) (SignableBytesHasher, error) {
+	hasher, ok := signableBytesHashers[version]
+	if !ok {
+		return nil, fmt.Errorf("SignableBytesHasherForVersion: %w: %q", ErrUnsupportedHashVersion, version)
+	}
+	return hasher, nil
+}
+
+// NegotiateSignableBytesHasher returns the SignableBytesHasher implementing the
+// first of the given versions, e.g. as advertised by a supplier by order of
+// preference, which is supported by the SDK.
+// The DefaultSignableBytesHasher is returned if no version is given, and an
+// error wrapping ErrUnsupportedHashVersion if none is supported.
+func NegotiateSignableBytesHasher(versions ...SignableBytesHashVersion) (SignableBytesHasher, error) {
+	if len(versions) == 0 {
+		return DefaultSignableBytesHasher, nil
+	}
+
+	for _, version := range versions {
+		if hasher, ok := signableBytesHashers[version]; ok {
+			return hasher, nil
+		}
+	}
+	return nil, fmt.Errorf("NegotiateSignableBytesHasher: %w: %q", ErrUnsupportedHashVersion, versions)
+}
+
+// signableBytesHasherOrDefault returns the given hasher, or the
+// DefaultSignableBytesHasher if it is nil.
+func signableBytesHasherOrDefault(hasher SignableBytesHasher) SignableBytesHasher {
+	if hasher == nil {
+		return DefaultSignableBytesHasher
+	}
+	return hasher
+}
+
+// sha256SignableBytesHasher implements SignableBytesHashSHA256, relying on the
+// protocol's own implementation.
+type sha256SignableBytesHasher struct{}
+
+// Version returns SignableBytesHashSHA256.
+func (sha256SignableBytesHasher) Version() SignableBytesHashVersion {
+	return SignableBytesHashSHA256
+}
+
+// RelayRequestHash returns the SHA-256 hash of the marshaled relay request,
+// without its signature.
+func (sha256SignableBytesHasher) RelayRequestHash(relayRequest *servicetypes.RelayRequest) ([32]byte, error) {
+	return relayRequest.GetSignableBytesHash()
+}
+
+// RelayResponseHash returns the SHA-256 hash of the marshaled relay response,
+// without its signature.
+func (sha256SignableBytesHasher) RelayResponseHash(relayResponse *servicetypes.RelayResponse) ([32]byte, error) {
+	return relayResponse.GetSignableBytesHash()
+}
@@ -0,0 +1,83 @@
+package sdk
+
+import (
+	"encoding/hex"
+	"testing"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultSignableBytesHasher_KnownVectors(t *testing.T) {
+	sessionHeader := &sessiontypes.SessionHeader{
+		ApplicationAddress:      "pokt1app",
+		ServiceId:               "anvil",
+		SessionId:               "session1",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   10,
+	}
+	relayRequest := &servicetypes.RelayRequest{
+		Meta: servicetypes.RelayRequestMetadata{
+			SessionHeader:           sessionHeader,
+			Signature:               []byte("signature"),
+			SupplierOperatorAddress: "pokt1supplier",
+		},
+		Payload: []byte("payload"),
+	}
+	relayResponse := &servicetypes.RelayResponse{
+		Meta: servicetypes.RelayResponseMetadata{
+			SessionHeader:             sessionHeader,
+			SupplierOperatorSignature: []byte("signature"),
+		},
+		Payload: []byte("response"),
+	}
+
+	// The hashes of the current protocol, which must not change: signatures
+	// would no longer verify across SDK versions otherwise.
+	hasher := DefaultSignableBytesHasher
+	require.Equal(t, SignableBytesHashSHA256, hasher.Version())
+
+	requestHash, err := hasher.RelayRequestHash(relayRequest)
+	require.NoError(t, err)
+	require.Equal(t, "8c03213c42959bec2aee39393e500d48980fd95bc76e48bb6d3b69a70c4454ac", hex.EncodeToString(requestHash[:]))
+
+	responseHash, err := hasher.RelayResponseHash(relayResponse)
+	require.NoError(t, err)
+	require.Equal(t, "5577c8c2d68833fbc0cb274dec9774087ff17aa28111f19ae1efe3461f151099", hex.EncodeToString(responseHash[:]))
+
+	// The signatures are excluded from the signable bytes, and left unchanged.
+	relayRequest.Meta.Signature = []byte("another signature")
+	otherRequestHash, err := hasher.RelayRequestHash(relayRequest)
+	require.NoError(t, err)
+	require.Equal(t, requestHash, otherRequestHash)
+	require.Equal(t, []byte("another signature"), relayRequest.Meta.Signature)
+
+	// The hashes match the protocol's implementation.
+	protocolRequestHash, err := relayRequest.GetSignableBytesHash()
+	require.NoError(t, err)
+	require.Equal(t, protocolRequestHash, requestHash)
+	protocolResponseHash, err := relayResponse.GetSignableBytesHash()
+	require.NoError(t, err)
+	require.Equal(t, protocolResponseHash, responseHash)
+}
+
+func TestNegotiateSignableBytesHasher(t *testing.T) {
+	hasher, err := NegotiateSignableBytesHasher()
+	require.NoError(t, err)
+	require.Equal(t, DefaultSignableBytesHasher, hasher)
+
+	hasher, err = NegotiateSignableBytesHasher("sha3-v2", SignableBytesHashSHA256)
+	require.NoError(t, err)
+	require.Equal(t, SignableBytesHashSHA256, hasher.Version())
+
+	_, err = NegotiateSignableBytesHasher("sha3-v2")
+	require.ErrorIs(t, err, ErrUnsupportedHashVersion)
+
+	hasher, err = SignableBytesHasherForVersion(SignableBytesHashSHA256)
+	require.NoError(t, err)
+	require.Equal(t, DefaultSignableBytesHasher, hasher)
+
+	_, err = SignableBytesHasherForVersion("")
+	require.ErrorIs(t, err, ErrUnsupportedHashVersion)
+}
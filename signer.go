@@ -2,17 +2,124 @@ package sdk
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
+	"slices"
 
 	servicetypes "github.com/pokt-network/poktroll/x/service/types"
-	"github.com/pokt-network/ring-go"
+
+	"github.com/pokt-network/shannon-sdk/crypto"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// ErrSignerNotInRing is returned when signing a relay request with a key which
+// is not part of the application's ring at the session end height, e.g. if the
+// application revoked its delegation to the gateway.
+// Suppliers reject relay requests signed by keys outside the ring, so the
+// application's delegations should be re-checked, e.g. by fetching the
+// application again using the ApplicationClient.
+var ErrSignerNotInRing = sdkerrors.ErrSignerNotInRing
+
+// SignerNotInRingError is returned by Signer.Sign when its key is not part of
+// the application's ring at the session end height.
+// It matches ErrSignerNotInRing using errors.Is.
+type SignerNotInRingError struct {
+	// SignerAddress is the address of the signing key.
+	SignerAddress string
+	// AppAddress is the address of the application.
+	AppAddress string
+	// SessionEndHeight is the session end height of the ring.
+	SessionEndHeight uint64
+}
+
+// Error returns a description of the signer and the ring it is not part of.
+func (e *SignerNotInRingError) Error() string {
+	return fmt.Sprintf(
+		"%v: signer %s, application %s, session end height %d",
+		ErrSignerNotInRing,
+		e.SignerAddress,
+		e.AppAddress,
+		e.SessionEndHeight,
+	)
+}
+
+// Unwrap returns ErrSignerNotInRing.
+func (e *SignerNotInRingError) Unwrap() error {
+	return ErrSignerNotInRing
+}
+
+// RelayRequestSigner signs relay requests using the ring of their application.
+// It is implemented by Signer, RotatingSigner and MultiSigner, so any of them
+// can sign the relays of a GatewayClient, a WebSocketRelayChannel or a SelfTest.
+type RelayRequestSigner interface {
+	Sign(
+		ctx context.Context,
+		relayRequest *servicetypes.RelayRequest,
+		appRing ApplicationRing,
+	) (*servicetypes.RelayRequest, error)
+}
+
+var (
+	_ RelayRequestSigner = (*Signer)(nil)
+	_ RelayRequestSigner = (*RotatingSigner)(nil)
+	_ RelayRequestSigner = MultiSigner{}
 )
 
 // Signer is a struct that holds the application or gateways private keys used
 // to sign Relay Requests.
 type Signer struct {
 	PrivateKeyHex string
+
+	// RingSigner, if set, signs the relay requests instead of PrivateKeyHex,
+	// e.g. using a keyring or a remote signer holding the key.
+	RingSigner RingSigner
+
+	// Hasher, if set, hashes the signable bytes of the relay requests.
+	// The DefaultSignableBytesHasher is used otherwise.
+	Hasher SignableBytesHasher
+}
+
+// NewSigner returns a Signer using the given private key, after checking that
+// the key belongs to the account with the given address, e.g. the configured
+// gateway or application address.
+// This catches mismatched key/address pairs on startup, instead of relays
+// silently failing signature verification on the supplier side.
+func NewSigner(privateKeyHex string, expectedAddress string) (*Signer, error) {
+	address, err := AddressFromPrivateKeyHex(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("NewSigner: %w", err)
+	}
+
+	if address != expectedAddress {
+		return nil, fmt.Errorf(
+			"NewSigner: private key belongs to address %s, expected %s",
+			address,
+			expectedAddress,
+		)
+	}
+
+	return &Signer{PrivateKeyHex: privateKeyHex}, nil
+}
+
+// Address returns the address of the signer's key: the address of its
+// RingSigner if set, or of the account owning its PrivateKeyHex otherwise.
+func (s *Signer) Address() (string, error) {
+	ringSigner, err := s.ringSigner()
+	if err != nil {
+		return "", fmt.Errorf("Address: %w", err)
+	}
+
+	return ringSigner.Address(), nil
+}
+
+// AddressFromPrivateKeyHex returns the POKT address of the account owning the
+// given hex-encoded secp256k1 private key.
+func AddressFromPrivateKeyHex(privateKeyHex string) (string, error) {
+	privKey, err := crypto.PrivateKeyFromHex(privateKeyHex)
+	if err != nil {
+		return "", err
+	}
+
+	return crypto.Address(PoktAddressPrefix, privKey.PubKey())
 }
 
 // Note: Sign returns a pointer instead of directly setting the signature on the input relay request.
@@ -28,45 +135,42 @@ func (s *Signer) Sign(
 	// TODO_IMPROVE: this input argument should be changed to an interface.
 	appRing ApplicationRing,
 ) (*servicetypes.RelayRequest, error) {
-	sessionRing, err := appRing.GetRing(ctx, uint64(relayRequest.Meta.SessionHeader.SessionEndBlockHeight))
-	if err != nil {
-		return nil, fmt.Errorf(
-			"Sign: error getting a ring for application address %s: %w",
-			appRing.Application.Address,
-			err,
-		)
-	}
-
-	signableBz, err := relayRequest.GetSignableBytesHash()
-	if err != nil {
-		return nil, fmt.Errorf("Sign: error getting signable bytes hash from the relay request: %w", err)
-	}
+	sessionEndHeight := uint64(relayRequest.Meta.SessionHeader.SessionEndBlockHeight)
 
-	// TODO_DISCUSS: should the Signer struct store the private key as scalar instead?
-	// This would reduce the number of steps required for processing each Relay Request.
-	signerPrivKeyBz, err := hex.DecodeString(s.PrivateKeyHex)
+	ringSigner, err := s.ringSigner()
 	if err != nil {
-		return nil, fmt.Errorf("Sign: error decoding private key to a string: %w", err)
+		return nil, fmt.Errorf("Sign: %w", err)
 	}
 
-	signerPrivKey, err := ring.Secp256k1().DecodeToScalar(signerPrivKeyBz)
-	if err != nil {
-		return nil, fmt.Errorf("Sign: error decoding private key to a scalar: %w", err)
+	// Check the signer is part of the ring before signing, as suppliers reject
+	// signatures from keys outside the ring with opaque errors.
+	signerAddress := ringSigner.Address()
+	if !slices.Contains(appRing.GetRingAddresses(sessionEndHeight), signerAddress) {
+		return nil, fmt.Errorf("Sign: %w", &SignerNotInRingError{
+			SignerAddress:    signerAddress,
+			AppAddress:       appRing.Application.Address,
+			SessionEndHeight: sessionEndHeight,
+		})
 	}
 
-	ringSig, err := sessionRing.Sign(signableBz, signerPrivKey)
+	ringPubKeys, err := appRing.GetRingPubKeys(ctx, sessionEndHeight)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"Sign: error signing using the ring of application with address %s: %w",
+			"Sign: error getting a ring for application address %s: %w",
 			appRing.Application.Address,
 			err,
 		)
 	}
 
-	signature, err := ringSig.Serialize()
+	signableBz, err := signableBytesHasherOrDefault(s.Hasher).RelayRequestHash(relayRequest)
+	if err != nil {
+		return nil, fmt.Errorf("Sign: error getting signable bytes hash from the relay request: %w", err)
+	}
+
+	signature, err := ringSigner.SignRing(ctx, ringPubKeys, signableBz)
 	if err != nil {
 		return nil, fmt.Errorf(
-			"Sign: error serializing the signature of application with address %s: %w",
+			"Sign: error signing using the ring of application with address %s: %w",
 			appRing.Application.Address,
 			err,
 		)
@@ -75,3 +179,19 @@ func (s *Signer) Sign(
 	relayRequest.Meta.Signature = signature
 	return relayRequest, nil
 }
+
+// ringSigner returns the signer's RingSigner, or one using its PrivateKeyHex
+// if not set.
+func (s *Signer) ringSigner() (RingSigner, error) {
+	if s.RingSigner != nil {
+		return s.RingSigner, nil
+	}
+
+	// TODO_DISCUSS: should the Signer struct store the private key as scalar instead?
+	// This would reduce the number of steps required for processing each Relay Request.
+	ringSigner, err := NewHexKeyRingSigner(s.PrivateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("error getting the signer address: %w", err)
+	}
+	return ringSigner, nil
+}
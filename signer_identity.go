@@ -0,0 +1,59 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+)
+
+// signingIdentityKey is the context key of the signing identity.
+type signingIdentityKey struct{}
+
+// ContextWithSigningIdentity returns a copy of the given context carrying the
+// given signing identity, e.g. "gateway" or an application address, which is
+// used by MultiSigner to select the key signing the relays of the request.
+func ContextWithSigningIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, signingIdentityKey{}, identity)
+}
+
+// SigningIdentityFromContext returns the signing identity carried by the given
+// context, and a boolean indicating whether it carries one.
+func SigningIdentityFromContext(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(signingIdentityKey{}).(string)
+	return identity, ok
+}
+
+// MultiSigner holds multiple signing identities, e.g. the gateway key and the
+// keys of specific applications, and signs each relay request using the
+// identity carried by the request's context.
+// It allows multi-identity gateways to select the signing key per request, e.g.
+// by setting it as the Signer of a GatewayClient.
+type MultiSigner struct {
+	// Signers holds the signers keyed by identity, e.g. a Signer or a
+	// RotatingSigner per identity.
+	Signers map[string]RelayRequestSigner
+	// DefaultIdentity is the identity used if the context does not carry one.
+	DefaultIdentity string
+}
+
+// Sign signs the given relay request using the signer of the identity carried
+// by the given context, or of the default identity if the context carries none.
+// An error is returned if there is no signer for the identity.
+func (ms MultiSigner) Sign(
+	ctx context.Context,
+	relayRequest *servicetypes.RelayRequest,
+	appRing ApplicationRing,
+) (*servicetypes.RelayRequest, error) {
+	identity, ok := SigningIdentityFromContext(ctx)
+	if !ok {
+		identity = ms.DefaultIdentity
+	}
+
+	signer, ok := ms.Signers[identity]
+	if !ok || signer == nil {
+		return nil, fmt.Errorf("Sign: no signer for identity %q", identity)
+	}
+
+	return signer.Sign(ctx, relayRequest, appRing)
+}
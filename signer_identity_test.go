@@ -0,0 +1,67 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/crypto"
+)
+
+func TestMultiSigner_Sign(t *testing.T) {
+	gatewayKeyHex, gatewayAddress := newTestKey(t)
+	appKeyHex, appAddress := newTestKey(t)
+
+	appSigner, err := NewRotatingSigner(appKeyHex, appAddress)
+	require.NoError(t, err)
+
+	// The MultiSigner is a RelayRequestSigner, whose identities are backed by any RelayRequestSigner.
+	var multiSigner RelayRequestSigner = MultiSigner{
+		Signers: map[string]RelayRequestSigner{
+			"gateway":  &Signer{PrivateKeyHex: gatewayKeyHex},
+			appAddress: appSigner,
+		},
+		DefaultIdentity: "gateway",
+	}
+
+	appPrivKey, err := crypto.PrivateKeyFromHex(appKeyHex)
+	require.NoError(t, err)
+
+	// The application does not delegate to the gateway, so only its own key
+	// is part of its ring.
+	appRing := ApplicationRing{
+		Application: apptypes.Application{Address: appAddress},
+		PublicKeyFetcher: &countingPubKeyFetcher{
+			pubKeys: map[string]cryptotypes.PubKey{appAddress: appPrivKey.PubKey()},
+		},
+	}
+	newRelayRequest := func() *servicetypes.RelayRequest {
+		return &servicetypes.RelayRequest{
+			Meta: servicetypes.RelayRequestMetadata{
+				SessionHeader: &sessiontypes.SessionHeader{ApplicationAddress: appAddress, SessionEndBlockHeight: 10},
+			},
+		}
+	}
+
+	// The default identity is used if the context carries none.
+	_, err = multiSigner.Sign(context.Background(), newRelayRequest(), appRing)
+	require.ErrorIs(t, err, ErrSignerNotInRing)
+	require.ErrorContains(t, err, gatewayAddress)
+
+	ctx := ContextWithSigningIdentity(context.Background(), appAddress)
+	identity, ok := SigningIdentityFromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, appAddress, identity)
+
+	signedRequest, err := multiSigner.Sign(ctx, newRelayRequest(), appRing)
+	require.NoError(t, err)
+	require.NotEmpty(t, signedRequest.Meta.Signature)
+
+	_, err = multiSigner.Sign(ContextWithSigningIdentity(context.Background(), "unknown"), newRelayRequest(), appRing)
+	require.ErrorContains(t, err, `no signer for identity "unknown"`)
+}
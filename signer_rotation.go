@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// RotationCheck is called by RotatingSigner.Rotate with the address of the new
+// key, before the key is swapped in. Returning an error aborts the rotation.
+type RotationCheck func(ctx context.Context, newAddress string) error
+
+// RotatingSigner is a Signer whose private key can be swapped at runtime,
+// enabling scheduled or emergency key rotations without a restart.
+// It is safe for concurrent use.
+type RotatingSigner struct {
+	// mu guards the current signer and its address, which are swapped together.
+	mu      sync.RWMutex
+	signer  *Signer
+	address string
+}
+
+// NewRotatingSigner returns a RotatingSigner using the given private key, after
+// checking that the key belongs to the account with the given address.
+func NewRotatingSigner(privateKeyHex string, expectedAddress string) (*RotatingSigner, error) {
+	signer, err := NewSigner(privateKeyHex, expectedAddress)
+	if err != nil {
+		return nil, fmt.Errorf("NewRotatingSigner: %w", err)
+	}
+
+	return &RotatingSigner{signer: signer, address: expectedAddress}, nil
+}
+
+// Address returns the address of the current signing key.
+func (rs *RotatingSigner) Address() string {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	return rs.address
+}
+
+// Sign signs the given relay request using the current private key and the
+// application's ring. See Signer.Sign.
+// The lock is only held to get the current key, not while signing, so a slow
+// sign, e.g. by a remote signer, does not block the rotations.
+func (rs *RotatingSigner) Sign(
+	ctx context.Context,
+	relayRequest *servicetypes.RelayRequest,
+	appRing ApplicationRing,
+) (*servicetypes.RelayRequest, error) {
+	rs.mu.RLock()
+	signer := rs.signer
+	rs.mu.RUnlock()
+
+	return signer.Sign(ctx, relayRequest, appRing)
+}
+
+// Rotate swaps the signing key for the given private key, after checking that
+// it belongs to the account with the given address, and that it passes all the
+// given checks, e.g. RequireDelegations.
+//
+// The key is swapped atomically: the signs in flight complete using the previous
+// key, and the next ones use the new key. The previous key is kept if any of the
+// checks fails.
+func (rs *RotatingSigner) Rotate(
+	ctx context.Context,
+	privateKeyHex string,
+	expectedAddress string,
+	checks ...RotationCheck,
+) error {
+	signer, err := NewSigner(privateKeyHex, expectedAddress)
+	if err != nil {
+		return fmt.Errorf("Rotate: %w", err)
+	}
+
+	for _, check := range checks {
+		if err := check(ctx, expectedAddress); err != nil {
+			return fmt.Errorf("Rotate: check of address %s failed: %w", expectedAddress, err)
+		}
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.signer = signer
+	rs.address = expectedAddress
+	return nil
+}
+
+// RequireDelegations returns a RotationCheck which fails unless all the given
+// applications currently delegate to the new address, so that relays of the
+// applications can still be signed once the key is rotated.
+func RequireDelegations(appClient ApplicationQuerier, appAddresses ...string) RotationCheck {
+	return func(ctx context.Context, newAddress string) error {
+		if appClient == nil {
+			return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "RequireDelegations: ApplicationClient not set")
+		}
+
+		var errs []error
+		for _, appAddress := range appAddresses {
+			app, err := appClient.GetApplication(ctx, appAddress)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error getting application %s: %w", appAddress, err))
+				continue
+			}
+
+			if !slices.Contains(app.DelegateeGatewayAddresses, newAddress) {
+				errs = append(errs, fmt.Errorf("application %s does not delegate to %s", appAddress, newAddress))
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,117 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/crypto"
+)
+
+func TestRotatingSigner_Rotate(t *testing.T) {
+	oldKeyHex, oldAddress := newTestKey(t)
+	newKeyHex, newAddress := newTestKey(t)
+
+	rs, err := NewRotatingSigner(oldKeyHex, oldAddress)
+	require.NoError(t, err)
+	require.Equal(t, oldAddress, rs.Address())
+
+	appClient := &ApplicationClient{
+		QueryClient: &fakeAppQueryClient{apps: map[string]apptypes.Application{
+			"pokt1app1": {Address: "pokt1app1", DelegateeGatewayAddresses: []string{oldAddress, newAddress}},
+			"pokt1app2": {Address: "pokt1app2", DelegateeGatewayAddresses: []string{oldAddress}},
+		}},
+	}
+	ctx := context.Background()
+
+	// The rotation is aborted if the key does not match the address.
+	err = rs.Rotate(ctx, newKeyHex, oldAddress)
+	require.Error(t, err)
+	require.Equal(t, oldAddress, rs.Address())
+
+	// The rotation is aborted if an application does not delegate to the new address.
+	err = rs.Rotate(ctx, newKeyHex, newAddress, RequireDelegations(appClient, "pokt1app1", "pokt1app2"))
+	require.ErrorContains(t, err, "pokt1app2")
+	require.Equal(t, oldAddress, rs.Address())
+
+	err = rs.Rotate(ctx, newKeyHex, newAddress, RequireDelegations(appClient, "pokt1app1"))
+	require.NoError(t, err)
+	require.Equal(t, newAddress, rs.Address())
+}
+
+func TestRotatingSigner_RotateDuringSign(t *testing.T) {
+	oldKeyHex, oldAddress := newTestKey(t)
+	newKeyHex, newAddress := newTestKey(t)
+
+	appKey := secp256k1.GenPrivKey()
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, appKey.PubKey())
+	require.NoError(t, err)
+	oldKey, err := crypto.PrivateKeyFromHex(oldKeyHex)
+	require.NoError(t, err)
+	appRing := ApplicationRing{
+		Application: apptypes.Application{Address: appAddress, DelegateeGatewayAddresses: []string{oldAddress}},
+		PublicKeyFetcher: &countingPubKeyFetcher{pubKeys: map[string]cryptotypes.PubKey{
+			appAddress: appKey.PubKey(),
+			oldAddress: oldKey.PubKey(),
+		}},
+	}
+	relayRequest := &servicetypes.RelayRequest{
+		Meta: servicetypes.RelayRequestMetadata{
+			SessionHeader: &sessiontypes.SessionHeader{ApplicationAddress: appAddress, SessionEndBlockHeight: 10},
+		},
+	}
+
+	rs, err := NewRotatingSigner(oldKeyHex, oldAddress)
+	require.NoError(t, err)
+	ringSigner := &blockingRingSigner{address: oldAddress, started: make(chan struct{}), release: make(chan struct{})}
+	rs.signer = &Signer{RingSigner: ringSigner}
+
+	signErr := make(chan error, 1)
+	go func() {
+		_, err := rs.Sign(context.Background(), relayRequest, appRing)
+		signErr <- err
+	}()
+	<-ringSigner.started
+
+	// The key is rotated while a sign is in flight, without waiting for it.
+	require.NoError(t, rs.Rotate(context.Background(), newKeyHex, newAddress))
+	require.Equal(t, newAddress, rs.Address())
+
+	// The sign in flight completes using the previous key.
+	close(ringSigner.release)
+	require.NoError(t, <-signErr)
+	require.Equal(t, []byte("signature"), relayRequest.Meta.Signature)
+}
+
+// blockingRingSigner is a RingSigner whose signs block until released.
+type blockingRingSigner struct {
+	address string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingRingSigner) Address() string { return s.address }
+
+func (s *blockingRingSigner) SignRing(context.Context, []cryptotypes.PubKey, [32]byte) ([]byte, error) {
+	close(s.started)
+	<-s.release
+	return []byte("signature"), nil
+}
+
+// newTestKey returns a new hex-encoded private key along with its address.
+func newTestKey(t *testing.T) (privateKeyHex string, address string) {
+	t.Helper()
+
+	privateKeyHex = hex.EncodeToString(secp256k1.GenPrivKey().Bytes())
+	address, err := AddressFromPrivateKeyHex(privateKeyHex)
+	require.NoError(t, err)
+
+	return privateKeyHex, address
+}
@@ -0,0 +1,121 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSigner(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	privKeyHex := hex.EncodeToString(privKey.Bytes())
+
+	address, err := PubKeyToAddress(PoktAddressPrefix, privKey.PubKey())
+	require.NoError(t, err)
+
+	otherAddress, err := PubKeyToAddress(PoktAddressPrefix, secp256k1.GenPrivKey().PubKey())
+	require.NoError(t, err)
+
+	tests := []struct {
+		desc            string
+		privateKeyHex   string
+		expectedAddress string
+		expectErr       bool
+	}{
+		{
+			desc:            "private key matches address",
+			privateKeyHex:   privKeyHex,
+			expectedAddress: address,
+		},
+		{
+			desc:            "private key does not match address",
+			privateKeyHex:   privKeyHex,
+			expectedAddress: otherAddress,
+			expectErr:       true,
+		},
+		{
+			desc:            "invalid private key hex",
+			privateKeyHex:   "not hex",
+			expectedAddress: address,
+			expectErr:       true,
+		},
+		{
+			desc:            "invalid private key length",
+			privateKeyHex:   "abcd",
+			expectedAddress: address,
+			expectErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			signer, err := NewSigner(test.privateKeyHex, test.expectedAddress)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.privateKeyHex, signer.PrivateKeyHex)
+		})
+	}
+}
+
+func TestSigner_Address(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	privKeyHex := hex.EncodeToString(privKey.Bytes())
+	address, err := PubKeyToAddress(PoktAddressPrefix, privKey.PubKey())
+	require.NoError(t, err)
+
+	signerAddress, err := (&Signer{PrivateKeyHex: privKeyHex}).Address()
+	require.NoError(t, err)
+	require.Equal(t, address, signerAddress)
+
+	// The address of the RingSigner is used if set, even without a private key.
+	ringSigner, err := NewHexKeyRingSigner(privKeyHex)
+	require.NoError(t, err)
+	signerAddress, err = (&Signer{RingSigner: ringSigner}).Address()
+	require.NoError(t, err)
+	require.Equal(t, address, signerAddress)
+
+	_, err = (&Signer{PrivateKeyHex: "invalid"}).Address()
+	require.Error(t, err)
+}
+
+func TestSigner_Sign_SignerNotInRing(t *testing.T) {
+	gatewayPrivKey := secp256k1.GenPrivKey()
+	signer := Signer{PrivateKeyHex: hex.EncodeToString(gatewayPrivKey.Bytes())}
+
+	appAddress, err := PubKeyToAddress(PoktAddressPrefix, secp256k1.GenPrivKey().PubKey())
+	require.NoError(t, err)
+
+	relayRequest := &servicetypes.RelayRequest{
+		Meta: servicetypes.RelayRequestMetadata{
+			SessionHeader: &sessiontypes.SessionHeader{
+				ApplicationAddress:    appAddress,
+				SessionEndBlockHeight: 10,
+			},
+		},
+	}
+
+	// The application does not delegate to the gateway.
+	appRing := ApplicationRing{
+		Application:      apptypes.Application{Address: appAddress},
+		PublicKeyFetcher: &countingPubKeyFetcher{},
+	}
+
+	_, signErr := signer.Sign(context.Background(), relayRequest, appRing)
+	require.ErrorIs(t, signErr, ErrSignerNotInRing)
+
+	gatewayAddress, err := signer.Address()
+	require.NoError(t, err)
+	var notInRingErr *SignerNotInRingError
+	require.ErrorAs(t, signErr, &notInRingErr)
+	require.Equal(t, SignerNotInRingError{SignerAddress: gatewayAddress, AppAddress: appAddress, SessionEndHeight: 10}, *notInRingErr)
+}
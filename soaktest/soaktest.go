@@ -0,0 +1,271 @@
+// Package soaktest implements a soak test of the session caching and signing
+// path across session rollovers.
+//
+// It continuously sends synthetic relays against a stub full node whose height
+// advances at a configurable block time, and reports the relays signed against
+// a session which does not cover the height they were sent at, i.e. stale
+// session sends, along with the signing failures. These regressions typically
+// only surface at session boundaries, so the soak test spans multiple rollovers.
+//
+// It is importable, so gateways can soak test their own SessionCache options
+// and signing setup.
+package soaktest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	grpcoptions "google.golang.org/grpc"
+
+	sdk "github.com/pokt-network/shannon-sdk"
+)
+
+// SignFunc signs a synthetic relay against the given session, e.g. by building
+// a RelayRequest and signing it using a Signer.
+type SignFunc func(ctx context.Context, session *sessiontypes.Session) error
+
+// Config specifies a soak test.
+type Config struct {
+	// BlockTime is the duration between two blocks of the stub full node.
+	BlockTime time.Duration
+	// NumBlocksPerSession is the number of blocks of the stub full node's sessions.
+	NumBlocksPerSession int64
+	// Rollovers is the number of session rollovers after which the soak test stops.
+	Rollovers int
+	// Concurrency is the number of goroutines sending relays. It defaults to 1.
+	Concurrency int
+
+	AppAddress string
+	ServiceId  string
+
+	// SessionCacheOptions configure the SessionCache under test.
+	SessionCacheOptions []sdk.SessionCacheOption
+	// Sign, if set, is called to sign every relay.
+	Sign SignFunc
+}
+
+// Report summarizes the results of a soak test.
+type Report struct {
+	// Relays is the number of relays sent.
+	Relays uint64
+	// Rollovers is the number of session rollovers observed by the stub full node.
+	Rollovers int
+	// SessionFetchFailures is the number of relays which could not get a session.
+	SessionFetchFailures uint64
+	// SigningFailures is the number of relays which failed to be signed.
+	SigningFailures uint64
+	// StaleSessionSends is the number of relays which used a session not
+	// covering the height they were sent at.
+	StaleSessionSends uint64
+	// FirstErr is the first observed failure, if any.
+	FirstErr error
+}
+
+// Err returns an error if any relay failed to get a session, failed to be
+// signed, or was sent using a stale session.
+func (r Report) Err() error {
+	if r.SessionFetchFailures == 0 && r.SigningFailures == 0 && r.StaleSessionSends == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"soak test failed after %d relays: %d session fetch failures, %d signing failures, %d stale session sends, first error: %w",
+		r.Relays, r.SessionFetchFailures, r.SigningFailures, r.StaleSessionSends, r.FirstErr,
+	)
+}
+
+// Run runs the soak test specified by the given config, until the configured
+// number of session rollovers is reached or the given context is done.
+func Run(ctx context.Context, config Config) (Report, error) {
+	if config.BlockTime <= 0 || config.NumBlocksPerSession <= 0 || config.Rollovers <= 0 {
+		return Report{}, errors.New("Run: block time, number of blocks per session and rollovers must be greater than zero")
+	}
+
+	node := NewStubFullNode(config.NumBlocksPerSession)
+	sessionCache := sdk.NewSessionCacheWithOptions(
+		&sdk.SessionClient{PoktNodeSessionFetcher: node},
+		config.SessionCacheOptions...,
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Advance the stub full node's height until the last rollover.
+	targetHeight := 1 + int64(config.Rollovers)*config.NumBlocksPerSession
+	go func() {
+		defer cancel()
+		ticker := time.NewTicker(config.BlockTime)
+		defer ticker.Stop()
+
+		for node.Height() < targetHeight {
+			select {
+			case <-ticker.C:
+				node.AdvanceHeight()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		wg           sync.WaitGroup
+		relayResults results
+	)
+	for range max(config.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				relayResults.record(sendRelay(ctx, config, node, sessionCache))
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := relayResults.snapshot()
+	report.Rollovers = int((node.Height() - 1) / config.NumBlocksPerSession)
+	return report, nil
+}
+
+// relayOutcome is the outcome of a synthetic relay.
+type relayOutcome int
+
+const (
+	relaySent relayOutcome = iota
+	relayAborted
+	relaySessionFetchFailed
+	relaySigningFailed
+	relayStaleSession
+)
+
+// sendRelay gets the session of the current height and signs a synthetic relay against it.
+func sendRelay(
+	ctx context.Context,
+	config Config,
+	node *StubFullNode,
+	sessionCache *sdk.SessionCache,
+) (relayOutcome, error) {
+	height := node.Height()
+
+	sessionInfo, err := sessionCache.GetSession(ctx, config.AppAddress, config.ServiceId, height)
+	if err != nil {
+		if ctx.Err() != nil {
+			return relayAborted, nil
+		}
+		return relaySessionFetchFailed, err
+	}
+
+	header := sessionInfo.GetHeader()
+	if header == nil || height < header.SessionStartBlockHeight || height > header.SessionEndBlockHeight {
+		return relayStaleSession, fmt.Errorf("stale session %s used at height %d", sessionInfo.GetSessionId(), height)
+	}
+
+	if config.Sign != nil {
+		if err := config.Sign(ctx, sessionInfo.Session); err != nil {
+			if ctx.Err() != nil {
+				return relayAborted, nil
+			}
+			return relaySigningFailed, err
+		}
+	}
+
+	return relaySent, nil
+}
+
+// results accumulates the outcomes of the relays sent concurrently.
+type results struct {
+	mu     sync.Mutex
+	report Report
+}
+
+// record records the outcome of a relay.
+func (r *results) record(outcome relayOutcome, err error) {
+	if outcome == relayAborted {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.report.Relays++
+	switch outcome {
+	case relaySessionFetchFailed:
+		r.report.SessionFetchFailures++
+	case relaySigningFailed:
+		r.report.SigningFailures++
+	case relayStaleSession:
+		r.report.StaleSessionSends++
+	}
+	if err != nil && r.report.FirstErr == nil {
+		r.report.FirstErr = err
+	}
+}
+
+// snapshot returns the accumulated report.
+func (r *results) snapshot() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.report
+}
+
+// StubFullNode is a PoktNodeSessionFetcher simulating a full node whose height
+// advances on demand, and which returns sessions of a fixed number of blocks.
+// It is safe for concurrent use.
+type StubFullNode struct {
+	numBlocksPerSession int64
+	height              atomic.Int64
+}
+
+// NewStubFullNode returns a StubFullNode at height 1, returning sessions of the
+// given number of blocks.
+func NewStubFullNode(numBlocksPerSession int64) *StubFullNode {
+	node := &StubFullNode{numBlocksPerSession: numBlocksPerSession}
+	node.height.Store(1)
+	return node
+}
+
+// Height returns the current height of the stub full node.
+func (n *StubFullNode) Height() int64 {
+	return n.height.Load()
+}
+
+// AdvanceHeight increments the height of the stub full node.
+func (n *StubFullNode) AdvanceHeight() {
+	n.height.Add(1)
+}
+
+// GetSession returns the session covering the requested height.
+// It fails for heights beyond the current height, as a full node would.
+func (n *StubFullNode) GetSession(
+	_ context.Context,
+	req *sessiontypes.QueryGetSessionRequest,
+	_ ...grpcoptions.CallOption,
+) (*sessiontypes.QueryGetSessionResponse, error) {
+	if req.BlockHeight > n.Height() {
+		return nil, fmt.Errorf("GetSession: height %d is beyond the current height %d", req.BlockHeight, n.Height())
+	}
+
+	sessionNumber := (req.BlockHeight - 1) / n.numBlocksPerSession
+	startHeight := 1 + sessionNumber*n.numBlocksPerSession
+	sessionId := fmt.Sprintf("%s-%s-%d", req.ApplicationAddress, req.ServiceId, sessionNumber)
+
+	return &sessiontypes.QueryGetSessionResponse{
+		Session: &sessiontypes.Session{
+			Header: &sessiontypes.SessionHeader{
+				ApplicationAddress:      req.ApplicationAddress,
+				ServiceId:               req.ServiceId,
+				SessionId:               sessionId,
+				SessionStartBlockHeight: startHeight,
+				SessionEndBlockHeight:   startHeight + n.numBlocksPerSession - 1,
+			},
+			SessionId:     sessionId,
+			SessionNumber: sessionNumber,
+		},
+	}, nil
+}
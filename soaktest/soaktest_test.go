@@ -0,0 +1,53 @@
+package soaktest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/pokt-network/shannon-sdk"
+	"github.com/pokt-network/shannon-sdk/cache"
+	"github.com/pokt-network/shannon-sdk/soaktest"
+)
+
+func TestRun(t *testing.T) {
+	report, err := soaktest.Run(context.Background(), soaktest.Config{
+		BlockTime:           2 * time.Millisecond,
+		NumBlocksPerSession: 4,
+		Rollovers:           3,
+		Concurrency:         4,
+		AppAddress:          "app1",
+		ServiceId:           "svc1",
+		SessionCacheOptions: []sdk.SessionCacheOption{sdk.WithCacheConfig(cache.Config{TTL: time.Minute})},
+		Sign: func(_ context.Context, session *sessiontypes.Session) error {
+			if session.GetHeader().GetSessionId() == "" {
+				return errors.New("session id not set")
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, report.Err())
+	require.Equal(t, 3, report.Rollovers)
+	require.NotZero(t, report.Relays)
+}
+
+func TestRun_SigningFailures(t *testing.T) {
+	report, err := soaktest.Run(context.Background(), soaktest.Config{
+		BlockTime:           time.Millisecond,
+		NumBlocksPerSession: 2,
+		Rollovers:           1,
+		AppAddress:          "app1",
+		ServiceId:           "svc1",
+		Sign: func(context.Context, *sessiontypes.Session) error {
+			return errors.New("signing failed")
+		},
+	})
+	require.NoError(t, err)
+	require.ErrorContains(t, report.Err(), "signing failed")
+	require.Equal(t, report.Relays, report.SigningFailures)
+}
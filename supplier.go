@@ -0,0 +1,114 @@
+package sdk
+
+import (
+	"context"
+
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	query "github.com/cosmos/cosmos-sdk/types/query"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	suppliertypes "github.com/pokt-network/poktroll/x/supplier/types"
+)
+
+// SupplierClient is the interface to interact with the on-chain supplier-module.
+//
+// For example, it can be used to get the details of a specific supplier, including
+// its owner and operator addresses, stake and revenue share configuration.
+//
+// The SupplierClient uses the gRPC query client of the supplier module.
+type SupplierClient struct {
+	suppliertypes.QueryClient
+}
+
+// GetSupplier returns the details of the supplier with the given operator address.
+func (sc *SupplierClient) GetSupplier(
+	ctx context.Context,
+	operatorAddress string,
+) (SupplierInfo, error) {
+	req := &suppliertypes.QueryGetSupplierRequest{OperatorAddress: operatorAddress}
+	res, err := sc.QueryClient.Supplier(ctx, req)
+	if err != nil {
+		return SupplierInfo{}, err
+	}
+
+	return SupplierInfo{Supplier: res.Supplier}, nil
+}
+
+// TODO_FUTURE: support pagination if/when the number of onchain suppliers grows enough
+// to cause a performance issue with returning all suppliers at-once.
+//
+// GetAllSuppliers returns all suppliers in the network.
+func (sc *SupplierClient) GetAllSuppliers(
+	ctx context.Context,
+) ([]SupplierInfo, error) {
+	req := &suppliertypes.QueryAllSuppliersRequest{
+		Pagination: &query.PageRequest{
+			Limit: query.PaginationMaxLimit,
+		},
+	}
+
+	res, err := sc.QueryClient.AllSuppliers(ctx, req)
+	if err != nil {
+		return []SupplierInfo{}, err
+	}
+
+	suppliers := make([]SupplierInfo, 0, len(res.Supplier))
+	for _, supplier := range res.Supplier {
+		suppliers = append(suppliers, SupplierInfo{Supplier: supplier})
+	}
+
+	return suppliers, nil
+}
+
+// SupplierInfo wraps an onchain supplier, exposing the metadata that is relevant
+// to routing decisions: the owner vs. operator addresses, the staked amount, and
+// the revenue share configuration of each service.
+type SupplierInfo struct {
+	sharedtypes.Supplier
+}
+
+// Operator returns the address of the supplier's operator, i.e. the address
+// that signs relay responses.
+func (s SupplierInfo) Operator() SupplierAddress {
+	return SupplierAddress(s.Supplier.OperatorAddress)
+}
+
+// Owner returns the address of the supplier's owner, i.e. the address that
+// owns the supplier's stake.
+func (s SupplierInfo) Owner() string {
+	return s.Supplier.OwnerAddress
+}
+
+// StakeAmount returns the supplier's staked amount.
+// A zero coin is returned if the supplier's stake is not set.
+func (s SupplierInfo) StakeAmount() cosmostypes.Coin {
+	if s.Supplier.Stake == nil {
+		return cosmostypes.Coin{}
+	}
+
+	return *s.Supplier.Stake
+}
+
+// RevShare returns the revenue share configuration of the supplier for the given service id.
+// It returns nil if the supplier is not staked for the service.
+func (s SupplierInfo) RevShare(serviceId string) []*sharedtypes.ServiceRevenueShare {
+	for _, service := range s.Supplier.Services {
+		if service.ServiceId == serviceId {
+			return service.RevShare
+		}
+	}
+
+	return nil
+}
+
+// HasStakeAtLeast returns true if the supplier's stake is greater than or equal
+// to the given amount.
+// It returns false if the denomination of the supplier's stake does not match
+// that of the given amount.
+func (s SupplierInfo) HasStakeAtLeast(minStake cosmostypes.Coin) bool {
+	stake := s.StakeAmount()
+	if stake.Denom != minStake.Denom || stake.Amount.IsNil() {
+		return false
+	}
+
+	return stake.IsGTE(minStake)
+}
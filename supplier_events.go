@@ -0,0 +1,182 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+const (
+	// supplierTxQuery is the CometBFT event query matching the transactions
+	// including a message of the supplier module.
+	supplierTxQuery = "tm.event='Tx' AND message.module='supplier'"
+
+	// The type URLs of the supplier module messages changing the stake of a supplier.
+	msgStakeSupplierTypeURL   = "/poktroll.supplier.MsgStakeSupplier"
+	msgUnstakeSupplierTypeURL = "/poktroll.supplier.MsgUnstakeSupplier"
+)
+
+// SupplierEventWatcher watches the onchain supplier stake changes, i.e. the
+// stakes and unstakes of the suppliers, and publishes them on an EventBus as
+// SupplierStakeChangedEvents.
+//
+// Subscribing a SupplierStakeWatcher to the EventBus makes it refresh the
+// changed suppliers, so the endpoints of the suppliers starting to unbond are
+// deprioritized mid-session.
+type SupplierEventWatcher struct {
+	subscriber      TxEventSubscriber
+	eventBus        *EventBus
+	reconnectPolicy retry.Policy
+	onError         func(error)
+}
+
+// SupplierEventWatcherOption is a functional option used to configure a
+// SupplierEventWatcher.
+type SupplierEventWatcherOption func(*SupplierEventWatcher)
+
+// WithSupplierEventsReconnectPolicy sets the policy specifying the delays
+// between the consecutive reconnections of the subscription.
+// It defaults to an exponential backoff, from 1 to 30 seconds, with jitter.
+func WithSupplierEventsReconnectPolicy(policy retry.Policy) SupplierEventWatcherOption {
+	return func(w *SupplierEventWatcher) {
+		w.reconnectPolicy = policy
+	}
+}
+
+// WithSupplierEventsErrorObserver sets a function called with the error every
+// time the subscription fails or is lost, e.g. to log a warning.
+// Supplier changes committed while the subscription is down are missed.
+func WithSupplierEventsErrorObserver(observer func(error)) SupplierEventWatcherOption {
+	return func(w *SupplierEventWatcher) {
+		w.onError = observer
+	}
+}
+
+// NewSupplierEventWatcher returns a SupplierEventWatcher receiving the
+// transactions through the given TxEventSubscriber, and publishing the supplier
+// stake changes on the given EventBus, configured using the given options.
+func NewSupplierEventWatcher(
+	subscriber TxEventSubscriber,
+	eventBus *EventBus,
+	opts ...SupplierEventWatcherOption,
+) *SupplierEventWatcher {
+	w := &SupplierEventWatcher{
+		subscriber:      subscriber,
+		eventBus:        eventBus,
+		reconnectPolicy: defaultSubscriptionReconnectPolicy,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Run subscribes to the transactions of the supplier module and publishes the
+// supplier stake changes, reconnecting whenever the subscription fails or is
+// lost, until the given context is done.
+func (w *SupplierEventWatcher) Run(ctx context.Context) error {
+	if w.subscriber == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Run: TxEventSubscriber not set")
+	}
+	if w.eventBus == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Run: EventBus not set")
+	}
+
+	return runSubscription(ctx, w.reconnectPolicy, w.onError, w.subscribe)
+}
+
+// subscribe publishes the supplier stake changes of the committed transactions,
+// until the given context is done or the subscription is lost.
+// It returns whether any transaction was received, along with the reason the
+// subscription ended.
+func (w *SupplierEventWatcher) subscribe(ctx context.Context) (received bool, err error) {
+	subscriptionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	txs, err := w.subscriber.SubscribeTxEvents(subscriptionCtx, supplierTxQuery)
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return received, ctx.Err()
+		case txEvents, ok := <-txs:
+			if !ok {
+				return received, errors.New("supplier events subscription closed")
+			}
+			received = true
+
+			for _, event := range supplierEventsFromTx(txEvents) {
+				w.eventBus.Publish(event)
+			}
+		}
+	}
+}
+
+// supplierEventsFromTx returns the supplier stake changes of the transaction
+// with the given events.
+//
+// The supplier module messages may be signed by the supplier's owner instead of
+// its operator, so the operator address is read from the "operator_address"
+// attribute, or the "supplier" attribute of the typed events, of the events
+// emitted while handling the message, if any, and defaults to the "sender" of
+// the message otherwise. See applicationEventsFromTx.
+func supplierEventsFromTx(txEvents []abci.Event) []Event {
+	var (
+		events []Event
+		// current is the index in events of the change of the message whose
+		// events are being read, or -1 if it is not a supplier change, and
+		// resolved is true once its operator address was read from them.
+		current  = -1
+		resolved bool
+	)
+	for _, txEvent := range txEvents {
+		if action, ok := eventAttribute(txEvent, "action"); ok && txEvent.Type == "message" {
+			current, resolved = -1, false
+			sender, _ := eventAttribute(txEvent, "sender")
+			if (action == msgStakeSupplierTypeURL || action == msgUnstakeSupplierTypeURL) && sender != "" {
+				current = len(events)
+				events = append(events, SupplierStakeChangedEvent{Supplier: SupplierAddress(sender)})
+			}
+			continue
+		}
+
+		if current < 0 || resolved {
+			continue
+		}
+		if operatorAddress := supplierOperatorAddressFromEvent(txEvent); operatorAddress != "" {
+			events[current] = SupplierStakeChangedEvent{Supplier: SupplierAddress(operatorAddress)}
+			resolved = true
+		}
+	}
+
+	return events
+}
+
+// supplierOperatorAddressFromEvent returns the supplier operator address carried
+// by the given event, or an empty string if it carries none.
+func supplierOperatorAddressFromEvent(event abci.Event) string {
+	if operatorAddress, ok := eventAttribute(event, "operator_address"); ok {
+		return operatorAddress
+	}
+
+	supplierJSON, ok := eventAttribute(event, "supplier")
+	if !ok {
+		return ""
+	}
+	var supplier struct {
+		OperatorAddress string `json:"operator_address"`
+	}
+	if err := json.Unmarshal([]byte(supplierJSON), &supplier); err != nil {
+		return ""
+	}
+	return supplier.OperatorAddress
+}
@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/retry"
+)
+
+func TestSupplierEventsFromTx(t *testing.T) {
+	txEvents := []abci.Event{
+		// A stake signed by the supplier's owner.
+		messageEvent(msgStakeSupplierTypeURL, "pokt1owner1"),
+		{Type: "transfer", Attributes: []abci.EventAttribute{{Key: "recipient", Value: "pokt1module"}}},
+		{Type: "poktroll.supplier.EventSupplierStaked", Attributes: []abci.EventAttribute{
+			{Key: "supplier", Value: `{"owner_address":"pokt1owner1","operator_address":"pokt1supplier1"}`},
+		}},
+		messageEvent("/cosmos.bank.v1beta1.MsgSend", "pokt1supplier2"),
+		// An unstake signed by the supplier's operator, without typed events.
+		messageEvent(msgUnstakeSupplierTypeURL, "pokt1supplier3"),
+		messageEvent(msgUnstakeSupplierTypeURL, "pokt1owner4"),
+		{Type: "poktroll.supplier.EventSupplierUnbondingBegin", Attributes: []abci.EventAttribute{
+			{Key: "operator_address", Value: `"pokt1supplier4"`},
+		}},
+	}
+
+	require.Equal(t, []Event{
+		SupplierStakeChangedEvent{Supplier: "pokt1supplier1"},
+		SupplierStakeChangedEvent{Supplier: "pokt1supplier3"},
+		SupplierStakeChangedEvent{Supplier: "pokt1supplier4"},
+	}, supplierEventsFromTx(txEvents))
+
+	require.Empty(t, supplierEventsFromTx([]abci.Event{messageEvent(msgStakeApplicationTypeURL, "pokt1app1")}))
+}
+
+func TestSupplierEventWatcher_SupplierStakeWatcher(t *testing.T) {
+	queryClient := &fakeSupplierQueryClient{suppliers: map[string]sharedtypes.Supplier{
+		"pokt1supplier1": {OperatorAddress: "pokt1supplier1"},
+	}}
+	stakeWatcher := NewSupplierStakeWatcher(&SupplierClient{QueryClient: queryClient})
+	stakeWatcher.TrackSession(&sessiontypes.Session{
+		Suppliers: []*sharedtypes.Supplier{{OperatorAddress: "pokt1supplier1"}},
+	})
+
+	bus := NewEventBus()
+	refreshed := make(chan error, 1)
+	bus.Subscribe(func(event Event) {
+		stakeWatcher.HandleEvent(event)
+		refreshed <- stakeWatcher.Refresh(context.Background())
+	}, EventSupplierStakeChanged)
+
+	subscriber := &fakeTxEventSubscriber{subscriptions: make(chan chan []abci.Event), queries: make(chan string, 1)}
+	eventWatcher := NewSupplierEventWatcher(subscriber, bus, WithSupplierEventsReconnectPolicy(retry.Constant{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- eventWatcher.Run(ctx) }()
+
+	txs := <-subscriber.subscriptions
+	require.Equal(t, supplierTxQuery, <-subscriber.queries)
+
+	// The supplier starts unbonding onchain, which is picked up from its unstake transaction.
+	queryClient.suppliers["pokt1supplier1"] = sharedtypes.Supplier{OperatorAddress: "pokt1supplier1", UnstakeSessionEndHeight: 20}
+	require.False(t, stakeWatcher.IsUnbonding("pokt1supplier1"))
+	txs <- []abci.Event{messageEvent(msgUnstakeSupplierTypeURL, "pokt1supplier1")}
+	require.NoError(t, <-refreshed)
+	require.True(t, stakeWatcher.IsUnbonding("pokt1supplier1"))
+
+	cancel()
+	require.ErrorIs(t, <-runErr, context.Canceled)
+}
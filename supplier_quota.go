@@ -0,0 +1,174 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+)
+
+// supplierQuotaTolerance absorbs the floating point errors when comparing the
+// relays of a supplier to its quota.
+const supplierQuotaTolerance = 1e-9
+
+// SupplierQuotaOption is a functional option used to configure a
+// SupplierQuotaSelector.
+type SupplierQuotaOption func(*SupplierQuotaSelector)
+
+// WithSupplierMaxShare overrides the maximum share of the relays of an
+// application's session selected from the given supplier.
+func WithSupplierMaxShare(supplier SupplierAddress, maxShare float64) SupplierQuotaOption {
+	return func(s *SupplierQuotaSelector) {
+		s.supplierMaxShares[supplier] = maxShare
+	}
+}
+
+// SupplierQuotaSelector is an EndpointSelector limiting the share of the relays
+// of an application's session sent to any single supplier, e.g. so no supplier
+// gets more than 40% of them, distributing the risk and the rewards across the
+// suppliers of the session.
+//
+// It wraps another EndpointSelector, which selects among the endpoints of the
+// suppliers still within their quota. If every supplier has reached its quota,
+// e.g. for the first relays of a session or if the quotas add up to less than
+// 100%, it selects among the endpoints of the suppliers with the fewest relays.
+//
+// The relays are counted per application and service, and the counters are
+// reset when the session of the selected endpoints rolls over. The relays of a
+// previous session, e.g. sent during its grace period, are neither limited nor
+// counted.
+// It is safe for concurrent use.
+type SupplierQuotaSelector struct {
+	selector          EndpointSelector
+	maxShare          float64
+	supplierMaxShares map[SupplierAddress]float64
+
+	mu       sync.Mutex
+	sessions map[string]*supplierQuotaSession
+}
+
+// supplierQuotaSession holds the relays counted in the current session of an
+// application for a service.
+type supplierQuotaSession struct {
+	sessionID   string
+	startHeight int64
+	relays      int
+	suppliers   map[SupplierAddress]int
+}
+
+// NewSupplierQuotaSelector returns a SupplierQuotaSelector selecting endpoints
+// using the given selector, or at random if nil, and limiting the share of the
+// relays of a session sent to each supplier to the given maximum share, between
+// 0 and 1, unless overridden for the supplier.
+func NewSupplierQuotaSelector(selector EndpointSelector, maxShare float64, opts ...SupplierQuotaOption) *SupplierQuotaSelector {
+	if selector == nil {
+		selector = NewRandomSelector()
+	}
+
+	s := &SupplierQuotaSelector{
+		selector:          selector,
+		maxShare:          maxShare,
+		supplierMaxShares: make(map[SupplierAddress]float64),
+		sessions:          make(map[string]*supplierQuotaSession),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Select selects one of the given endpoints, which must belong to the same
+// session, and counts the relay against the quota of its supplier.
+func (s *SupplierQuotaSelector) Select(ctx context.Context, endpoints []Endpoint) Endpoint {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session := s.session(endpoints[0])
+	if session == nil {
+		return s.selector.Select(ctx, endpoints)
+	}
+
+	var (
+		withinQuota, leastRelayed []Endpoint
+		fewestRelays              int
+	)
+	for _, e := range endpoints {
+		relays := session.suppliers[e.Supplier()]
+		if float64(relays+1) <= s.supplierMaxShare(e.Supplier())*float64(session.relays+1)+supplierQuotaTolerance {
+			withinQuota = append(withinQuota, e)
+		}
+
+		switch {
+		case len(leastRelayed) == 0 || relays < fewestRelays:
+			leastRelayed = append(leastRelayed[:0], e)
+			fewestRelays = relays
+		case relays == fewestRelays:
+			leastRelayed = append(leastRelayed, e)
+		}
+	}
+	if len(withinQuota) == 0 {
+		withinQuota = leastRelayed
+	}
+
+	selected := s.selector.Select(ctx, withinQuota)
+	if selected != nil {
+		session.suppliers[selected.Supplier()]++
+		session.relays++
+	}
+	return selected
+}
+
+// SupplierShare returns the share of the relays of the current session of the
+// given application for the given service sent to the given supplier, or false
+// if no relay of the session was selected.
+func (s *SupplierQuotaSelector) SupplierShare(appAddress, serviceID string, supplier SupplierAddress) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[supplierQuotaKey(appAddress, serviceID)]
+	if !ok || session.relays == 0 {
+		return 0, false
+	}
+	return float64(session.suppliers[supplier]) / float64(session.relays), true
+}
+
+// session returns the counters of the session of the given endpoint, reset if
+// the session rolled over, or nil if the endpoint belongs to a previous session.
+// The selector's lock must be held.
+func (s *SupplierQuotaSelector) session(endpoint Endpoint) *supplierQuotaSession {
+	header := endpoint.Header()
+	key := supplierQuotaKey(header.ApplicationAddress, header.ServiceId)
+
+	session, ok := s.sessions[key]
+	switch {
+	case ok && session.sessionID == header.SessionId:
+		return session
+	case ok && header.SessionStartBlockHeight <= session.startHeight:
+		return nil
+	}
+
+	session = &supplierQuotaSession{
+		sessionID:   header.SessionId,
+		startHeight: header.SessionStartBlockHeight,
+		suppliers:   make(map[SupplierAddress]int),
+	}
+	s.sessions[key] = session
+	return session
+}
+
+// supplierMaxShare returns the maximum share of the relays of a session sent to
+// the given supplier.
+func (s *SupplierQuotaSelector) supplierMaxShare(supplier SupplierAddress) float64 {
+	if maxShare, ok := s.supplierMaxShares[supplier]; ok {
+		return maxShare
+	}
+	return s.maxShare
+}
+
+// supplierQuotaKey returns the key of the sessions of the given application for
+// the given service.
+func supplierQuotaKey(appAddress, serviceID string) string {
+	return appAddress + " " + serviceID
+}
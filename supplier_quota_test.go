@@ -0,0 +1,73 @@
+package sdk
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplierQuotaSelector(t *testing.T) {
+	newEndpoints := func(sessionID string, startHeight int64, suppliers ...SupplierAddress) []Endpoint {
+		header := sessiontypes.SessionHeader{
+			ApplicationAddress:      "pokt1app",
+			ServiceId:               "svc1",
+			SessionId:               sessionID,
+			SessionStartBlockHeight: startHeight,
+		}
+		var endpoints []Endpoint
+		for _, supplier := range suppliers {
+			endpoints = append(endpoints, endpoint{
+				header:           header,
+				supplierEndpoint: sharedtypes.SupplierEndpoint{Url: "https://" + string(supplier) + ".example"},
+				supplier:         supplier,
+			})
+		}
+		return endpoints
+	}
+	// The wrapped selector always favors the first supplier in alphabetical order.
+	greedy := EndpointSelectorFunc(func(_ context.Context, endpoints []Endpoint) Endpoint {
+		if len(endpoints) == 0 {
+			return nil
+		}
+		return slices.MinFunc(endpoints, func(a, b Endpoint) int {
+			return strings.Compare(string(a.Supplier()), string(b.Supplier()))
+		})
+	})
+	ctx := context.Background()
+	suppliers := []SupplierAddress{"a", "b", "c", "d", "e"}
+
+	selector := NewSupplierQuotaSelector(greedy, 0.4, WithSupplierMaxShare("b", 0.1))
+	endpoints := newEndpoints("session1", 10, suppliers...)
+	for range 100 {
+		require.NotNil(t, selector.Select(ctx, endpoints))
+	}
+
+	share, ok := selector.SupplierShare("pokt1app", "svc1", "a")
+	require.True(t, ok)
+	require.Equal(t, 0.4, share)
+	share, _ = selector.SupplierShare("pokt1app", "svc1", "b")
+	require.Positive(t, share)
+	require.LessOrEqual(t, share, 0.1)
+
+	// The relays of a previous session are neither limited nor counted.
+	require.Equal(t, SupplierAddress("a"), selector.Select(ctx, newEndpoints("session0", 5, suppliers...)).Supplier())
+	share, _ = selector.SupplierShare("pokt1app", "svc1", "a")
+	require.Equal(t, 0.4, share)
+
+	// The counters are reset when the session rolls over.
+	endpoints = newEndpoints("session2", 20, suppliers...)
+	require.Equal(t, SupplierAddress("a"), selector.Select(ctx, endpoints).Supplier())
+	share, _ = selector.SupplierShare("pokt1app", "svc1", "a")
+	require.Equal(t, 1.0, share)
+
+	// The quota is not enforced if there is no other supplier.
+	for range 3 {
+		require.Equal(t, SupplierAddress("c"), selector.Select(ctx, newEndpoints("session2", 20, "c")).Supplier())
+	}
+	require.Nil(t, selector.Select(ctx, nil))
+}
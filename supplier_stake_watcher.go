@@ -0,0 +1,222 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+const (
+	// EndpointMetadataDeprioritized is the metadata key set on the endpoints
+	// which should only be used if no other endpoint is available. Its value is
+	// the reason of the deprioritization, e.g. DeprioritizedSupplierUnbonding.
+	EndpointMetadataDeprioritized = "deprioritized"
+
+	// DeprioritizedSupplierUnbonding is the deprioritization reason of the
+	// endpoints of unbonding or unstaked suppliers, whose claims may not settle.
+	DeprioritizedSupplierUnbonding = "supplier_unbonding"
+)
+
+// SupplierStakeWatcher tracks the stake status of the suppliers of the active
+// sessions, and deprioritizes the endpoints of the suppliers which are unbonding
+// or unstaked, since the relays they serve may not be settled.
+//
+// The suppliers are first tracked using the data of their session, and then
+// refreshed through a SupplierClient when notified of a stake change, e.g. by
+// the SupplierStakeChangedEvents published by a SupplierEventWatcher.
+// The suppliers done unbonding are removed by Prune.
+// It is safe for concurrent use.
+type SupplierStakeWatcher struct {
+	supplierClient SupplierQuerier
+
+	// mu protects unbonding, which holds whether each tracked supplier is
+	// unbonding or unstaked, unstakeHeights, which holds the unstake session
+	// end height of the unbonding suppliers, or zero for the unstaked ones, and
+	// dirty, which holds the suppliers to refresh.
+	mu             sync.Mutex
+	unbonding      map[SupplierAddress]bool
+	unstakeHeights map[SupplierAddress]int64
+	dirty          map[SupplierAddress]struct{}
+}
+
+// NewSupplierStakeWatcher returns a SupplierStakeWatcher refreshing the
+// suppliers using the given SupplierClient.
+func NewSupplierStakeWatcher(supplierClient SupplierQuerier) *SupplierStakeWatcher {
+	return &SupplierStakeWatcher{
+		supplierClient: supplierClient,
+		unbonding:      make(map[SupplierAddress]bool),
+		unstakeHeights: make(map[SupplierAddress]int64),
+		dirty:          make(map[SupplierAddress]struct{}),
+	}
+}
+
+// TrackSession starts tracking the suppliers of the given session which are not
+// tracked yet, using the supplier data of the session.
+func (w *SupplierStakeWatcher) TrackSession(session *sessiontypes.Session) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, supplier := range session.GetSuppliers() {
+		supplierAddress := SupplierAddress(supplier.OperatorAddress)
+		if _, ok := w.unbonding[supplierAddress]; !ok {
+			w.setUnbonding(supplierAddress, supplier)
+		}
+	}
+}
+
+// Invalidate marks the given supplier to be refreshed on the next call to
+// Refresh, e.g. after it staked, unstaked, or started unbonding.
+func (w *SupplierStakeWatcher) Invalidate(supplierAddress SupplierAddress) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.dirty[supplierAddress] = struct{}{}
+}
+
+// HandleEvent invalidates the supplier of the SupplierStakeChangedEvents.
+// It can be subscribed to an EventBus, which notifies the stake changes.
+func (w *SupplierStakeWatcher) HandleEvent(event Event) {
+	if stakeChanged, ok := event.(SupplierStakeChangedEvent); ok {
+		w.Invalidate(stakeChanged.Supplier)
+	}
+}
+
+// Refresh fetches the suppliers marked to be refreshed.
+// The suppliers which fail to be fetched stay marked, and are fetched again on
+// the next call.
+func (w *SupplierStakeWatcher) Refresh(ctx context.Context) error {
+	if w.supplierClient == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Refresh: SupplierClient not set")
+	}
+
+	w.mu.Lock()
+	dirty := make([]SupplierAddress, 0, len(w.dirty))
+	for supplierAddress := range w.dirty {
+		dirty = append(dirty, supplierAddress)
+	}
+	w.mu.Unlock()
+
+	var errs []error
+	for _, supplierAddress := range dirty {
+		supplierInfo, err := w.supplierClient.GetSupplier(ctx, string(supplierAddress))
+		supplier := &supplierInfo.Supplier
+		switch {
+		case status.Code(err) == codes.NotFound:
+			// The supplier unstaked, i.e. is done unbonding.
+			supplier = nil
+		case err != nil:
+			errs = append(errs, fmt.Errorf("error refreshing supplier %s: %w", supplierAddress, err))
+			continue
+		}
+
+		w.mu.Lock()
+		w.setUnbonding(supplierAddress, supplier)
+		delete(w.dirty, supplierAddress)
+		w.mu.Unlock()
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Refresh: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// setUnbonding records the stake status of the given supplier, which is unstaked if nil.
+// It must be called with the lock held.
+func (w *SupplierStakeWatcher) setUnbonding(supplierAddress SupplierAddress, supplier *sharedtypes.Supplier) {
+	switch {
+	case supplier == nil:
+		w.unbonding[supplierAddress] = true
+		w.unstakeHeights[supplierAddress] = 0
+	case isSupplierUnbonding(supplier):
+		w.unbonding[supplierAddress] = true
+		w.unstakeHeights[supplierAddress] = int64(supplier.UnstakeSessionEndHeight)
+	default:
+		w.unbonding[supplierAddress] = false
+		delete(w.unstakeHeights, supplierAddress)
+	}
+}
+
+// Prune stops tracking the unbonding suppliers whose unstake session ended
+// before the given height, and the unstaked ones, so the suppliers which left
+// the network are not tracked forever. The suppliers are tracked again if they
+// are part of a session passed to TrackSession.
+// Callers should subtract the unbonding period of the suppliers from the given
+// height, so their endpoints stay deprioritized until they are unstaked, e.g.
+// by calling it with each new block height from a BlockScheduler.
+func (w *SupplierStakeWatcher) Prune(height int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for supplierAddress, unstakeHeight := range w.unstakeHeights {
+		if unstakeHeight < height {
+			delete(w.unbonding, supplierAddress)
+			delete(w.unstakeHeights, supplierAddress)
+			delete(w.dirty, supplierAddress)
+		}
+	}
+}
+
+// IsUnbonding returns true if the given supplier is tracked, and is unbonding
+// or unstaked.
+func (w *SupplierStakeWatcher) IsUnbonding(supplierAddress SupplierAddress) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.unbonding[supplierAddress]
+}
+
+// DecorateEndpoint is an EndpointDecorator setting the EndpointMetadataDeprioritized
+// metadata on the endpoints of unbonding or unstaked suppliers.
+// It can be set in the EndpointDecorators of a SessionFilter.
+func (w *SupplierStakeWatcher) DecorateEndpoint(e Endpoint) Endpoint {
+	if !w.IsUnbonding(e.Supplier()) {
+		return e
+	}
+	return WithEndpointMetadata(EndpointMetadataDeprioritized, DeprioritizedSupplierUnbonding)(e)
+}
+
+// isSupplierUnbonding returns true if the given supplier has requested to
+// unstake, i.e. its unstake session end height is set.
+func isSupplierUnbonding(supplier *sharedtypes.Supplier) bool {
+	return supplier != nil && supplier.UnstakeSessionEndHeight != 0
+}
+
+// IsEndpointDeprioritized returns true if the given endpoint carries the
+// EndpointMetadataDeprioritized metadata.
+func IsEndpointDeprioritized(e Endpoint) bool {
+	_, ok := GetEndpointMetadata(e, EndpointMetadataDeprioritized)
+	return ok
+}
+
+// FilterDeprioritizedEndpoints returns an EndpointFilter that filters out the
+// deprioritized endpoints, e.g. to never relay to unbonding suppliers.
+func FilterDeprioritizedEndpoints() EndpointFilter {
+	return IsEndpointDeprioritized
+}
+
+// SortDeprioritizedLast sorts the given endpoints so the deprioritized endpoints
+// come last, keeping the order of the endpoints otherwise, so they are only
+// selected if no other endpoint is available.
+func SortDeprioritizedLast(endpoints []Endpoint) {
+	slices.SortStableFunc(endpoints, func(a, b Endpoint) int {
+		aDeprioritized, bDeprioritized := IsEndpointDeprioritized(a), IsEndpointDeprioritized(b)
+		switch {
+		case aDeprioritized == bDeprioritized:
+			return 0
+		case bDeprioritized:
+			return -1
+		default:
+			return 1
+		}
+	})
+}
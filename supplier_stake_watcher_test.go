@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	suppliertypes "github.com/pokt-network/poktroll/x/supplier/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSupplierStakeWatcher(t *testing.T) {
+	queryClient := &fakeSupplierQueryClient{suppliers: map[string]sharedtypes.Supplier{
+		"pokt1supplier1": {OperatorAddress: "pokt1supplier1"},
+		"pokt1supplier2": {OperatorAddress: "pokt1supplier2"},
+	}}
+	watcher := NewSupplierStakeWatcher(&SupplierClient{QueryClient: queryClient})
+
+	bus := NewEventBus()
+	bus.Subscribe(watcher.HandleEvent, EventSupplierStakeChanged)
+
+	session := &sessiontypes.Session{
+		Header: &sessiontypes.SessionHeader{ServiceId: "svc1"},
+		Suppliers: []*sharedtypes.Supplier{
+			{OperatorAddress: "pokt1supplier1", Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://supplier1.example"}},
+			}}},
+			{OperatorAddress: "pokt1supplier2", Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://supplier2.example"}},
+			}}},
+			{OperatorAddress: "pokt1supplier3", UnstakeSessionEndHeight: 100, Services: []*sharedtypes.SupplierServiceConfig{{
+				ServiceId: "svc1",
+				Endpoints: []*sharedtypes.SupplierEndpoint{{Url: "https://supplier3.example"}},
+			}}},
+		},
+	}
+	watcher.TrackSession(session)
+
+	// supplier1 starts unbonding, and supplier2 unstakes.
+	queryClient.suppliers["pokt1supplier1"] = sharedtypes.Supplier{OperatorAddress: "pokt1supplier1", UnstakeSessionEndHeight: 200}
+	delete(queryClient.suppliers, "pokt1supplier2")
+	bus.Publish(SupplierStakeChangedEvent{Supplier: "pokt1supplier1"})
+	require.NoError(t, watcher.Refresh(context.Background()))
+	require.True(t, watcher.IsUnbonding("pokt1supplier1"))
+	require.False(t, watcher.IsUnbonding("pokt1supplier2"))
+	require.True(t, watcher.IsUnbonding("pokt1supplier3"))
+
+	bus.Publish(SupplierStakeChangedEvent{Supplier: "pokt1supplier2"})
+	require.NoError(t, watcher.Refresh(context.Background()))
+	require.True(t, watcher.IsUnbonding("pokt1supplier2"))
+
+	// The endpoints of the unbonding suppliers are deprioritized.
+	queryClient.suppliers["pokt1supplier2"] = sharedtypes.Supplier{OperatorAddress: "pokt1supplier2"}
+	bus.Publish(SupplierStakeChangedEvent{Supplier: "pokt1supplier2"})
+	require.NoError(t, watcher.Refresh(context.Background()))
+
+	filter := &SessionFilter{Session: session, EndpointDecorators: []EndpointDecorator{watcher.DecorateEndpoint}}
+	endpoints, err := filter.FilteredEndpoints()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 3)
+
+	SortDeprioritizedLast(endpoints)
+	require.Equal(t, SupplierAddress("pokt1supplier2"), endpoints[0].Supplier())
+	require.True(t, IsEndpointDeprioritized(endpoints[1]))
+	require.True(t, IsEndpointDeprioritized(endpoints[2]))
+
+	reason, ok := GetEndpointMetadata(endpoints[2], EndpointMetadataDeprioritized)
+	require.True(t, ok)
+	require.Equal(t, DeprioritizedSupplierUnbonding, reason)
+
+	filter.EndpointFilters = []EndpointFilter{FilterDeprioritizedEndpoints()}
+	endpoints, err = filter.FilteredEndpoints()
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+
+	// The suppliers are pruned once done unbonding: supplier3 unstakes at the
+	// end of the session ending at height 100, and supplier1 at height 200.
+	watcher.Prune(100)
+	require.True(t, watcher.IsUnbonding("pokt1supplier1"))
+	require.True(t, watcher.IsUnbonding("pokt1supplier3"))
+
+	watcher.Prune(101)
+	require.True(t, watcher.IsUnbonding("pokt1supplier1"))
+	require.False(t, watcher.IsUnbonding("pokt1supplier3"))
+
+	watcher.Prune(201)
+	require.False(t, watcher.IsUnbonding("pokt1supplier1"))
+
+	// The staked suppliers are kept, and the pruned ones tracked again if part of a session.
+	watcher.mu.Lock()
+	require.Len(t, watcher.unbonding, 1)
+	watcher.mu.Unlock()
+	watcher.TrackSession(session)
+	require.True(t, watcher.IsUnbonding("pokt1supplier3"))
+}
+
+// fakeSupplierQueryClient is a supplier module QueryClient serving the given suppliers.
+// Calling any method other than Supplier panics.
+type fakeSupplierQueryClient struct {
+	suppliertypes.QueryClient
+	suppliers map[string]sharedtypes.Supplier
+}
+
+func (c *fakeSupplierQueryClient) Supplier(
+	_ context.Context,
+	req *suppliertypes.QueryGetSupplierRequest,
+	_ ...grpcoptions.CallOption,
+) (*suppliertypes.QueryGetSupplierResponse, error) {
+	supplier, ok := c.suppliers[req.OperatorAddress]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "supplier not found")
+	}
+	return &suppliertypes.QueryGetSupplierResponse{Supplier: supplier}, nil
+}
@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"testing"
+
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplierInfo_HasStakeAtLeast(t *testing.T) {
+	tests := []struct {
+		desc           string
+		supplierStake  *cosmostypes.Coin
+		minStake       cosmostypes.Coin
+		expectedResult bool
+	}{
+		{
+			desc:           "stake above minimum",
+			supplierStake:  coinPtr(cosmostypes.NewInt64Coin("upokt", 200)),
+			minStake:       cosmostypes.NewInt64Coin("upokt", 100),
+			expectedResult: true,
+		},
+		{
+			desc:           "stake equal to minimum",
+			supplierStake:  coinPtr(cosmostypes.NewInt64Coin("upokt", 100)),
+			minStake:       cosmostypes.NewInt64Coin("upokt", 100),
+			expectedResult: true,
+		},
+		{
+			desc:           "stake below minimum",
+			supplierStake:  coinPtr(cosmostypes.NewInt64Coin("upokt", 50)),
+			minStake:       cosmostypes.NewInt64Coin("upokt", 100),
+			expectedResult: false,
+		},
+		{
+			desc:           "stake denom mismatch",
+			supplierStake:  coinPtr(cosmostypes.NewInt64Coin("upokt", 200)),
+			minStake:       cosmostypes.NewInt64Coin("stake", 100),
+			expectedResult: false,
+		},
+		{
+			desc:           "stake not set",
+			supplierStake:  nil,
+			minStake:       cosmostypes.NewInt64Coin("upokt", 100),
+			expectedResult: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			supplierInfo := SupplierInfo{
+				Supplier: sharedtypes.Supplier{Stake: test.supplierStake},
+			}
+			require.Equal(t, test.expectedResult, supplierInfo.HasStakeAtLeast(test.minStake))
+		})
+	}
+}
+
+func TestSupplierInfo_RevShare(t *testing.T) {
+	revShare := []*sharedtypes.ServiceRevenueShare{
+		{Address: "pokt1owner", RevSharePercentage: 100},
+	}
+	supplierInfo := SupplierInfo{
+		Supplier: sharedtypes.Supplier{
+			Services: []*sharedtypes.SupplierServiceConfig{
+				{ServiceId: "svc1", RevShare: revShare},
+			},
+		},
+	}
+
+	require.Equal(t, revShare, supplierInfo.RevShare("svc1"))
+	require.Nil(t, supplierInfo.RevShare("svc2"))
+}
+
+func coinPtr(coin cosmostypes.Coin) *cosmostypes.Coin {
+	return &coin
+}
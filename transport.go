@@ -0,0 +1,242 @@
+package sdk
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"path"
+	"slices"
+	"time"
+
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+)
+
+// TransportProtocol is the HTTP protocol used to send relays to suppliers.
+type TransportProtocol string
+
+const (
+	// TransportHTTP1 sends relays using HTTP/1.1 only.
+	TransportHTTP1 TransportProtocol = "http1"
+	// TransportH2 sends relays using HTTP/2 if supported by the supplier,
+	// falling back to HTTP/1.1 otherwise.
+	TransportH2 TransportProtocol = "h2"
+	// TransportWS is the WebSocket protocol, which is rejected by Validate:
+	// a RelaySender sends each relay as a single HTTP request, so the relays of
+	// WebSocket services must go through a WebSocketRelayChannel instead, as
+	// established by DialWebSocketRelay.
+	TransportWS TransportProtocol = "ws"
+)
+
+// TransportConfig specifies the transport used to send relays, which may be
+// overridden per service, e.g. to use HTTP/2 for high-throughput services.
+type TransportConfig struct {
+	// Default is the transport used for the services without an override.
+	Default HTTPTransportConfig `yaml:"default"`
+	// Services holds the transport overrides, keyed by service id.
+	Services map[string]HTTPTransportConfig `yaml:"services"`
+}
+
+// HTTPTransportConfig specifies the protocol and tuning of an HTTP relay transport.
+// Zero values use the defaults of the net/http package.
+type HTTPTransportConfig struct {
+	// Protocol is the HTTP protocol used to send relays. It defaults to TransportHTTP1.
+	Protocol TransportProtocol `yaml:"protocol"`
+	// Timeout is the maximum duration of a relay, including reading the response.
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept per supplier.
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host"`
+	// IdleConnTimeout is the maximum duration an idle connection is kept open.
+	IdleConnTimeout time.Duration `yaml:"idle_conn_timeout"`
+	// TLSHandshakeTimeout is the maximum duration of a TLS handshake.
+	TLSHandshakeTimeout time.Duration `yaml:"tls_handshake_timeout"`
+	// Dialers holds custom dialers keyed by endpoint address pattern, e.g. to
+	// reach co-located suppliers through a Unix domain socket using UnixSocketDialer.
+	// Patterns are matched against the "host:port" address of the endpoints,
+	// using path.Match syntax, e.g. "*.internal:8545".
+	// Endpoints not matching any pattern are dialed over TCP.
+	Dialers map[string]DialContextFunc `yaml:"-"`
+	// TLS specifies how the TLS certificates of the endpoints are validated.
+	// It defaults to verifying them using the system's root CAs.
+	TLS TLSPolicy `yaml:"tls"`
+	// TLSSessionCacheSize is the number of TLS sessions cached, across all the
+	// endpoints, to resume them when reconnecting instead of performing a full
+	// handshake. Zero disables the TLS session resumption.
+	TLSSessionCacheSize int `yaml:"tls_session_cache_size"`
+}
+
+// DialContextFunc dials a connection to the given address, e.g. a supplier endpoint.
+// It has the signature of net.Dialer.DialContext.
+type DialContextFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// UnixSocketDialer returns a DialContextFunc which connects to the Unix domain
+// socket at the given path, regardless of the dialed address.
+func UnixSocketDialer(socketPath string) DialContextFunc {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+}
+
+// Validate returns an error if the config can not be used to build a transport.
+func (c HTTPTransportConfig) Validate() error {
+	switch c.Protocol {
+	case "", TransportHTTP1, TransportH2:
+	case TransportWS:
+		return fmt.Errorf("transport protocol %q can not be used to send relays: use DialWebSocketRelay for WebSocket services", c.Protocol)
+	default:
+		return fmt.Errorf("unsupported transport protocol %q: must be one of %q, %q", c.Protocol, TransportHTTP1, TransportH2)
+	}
+
+	if c.Timeout < 0 || c.IdleConnTimeout < 0 || c.TLSHandshakeTimeout < 0 {
+		return errors.New("transport timeouts must not be negative")
+	}
+
+	if c.MaxIdleConnsPerHost < 0 {
+		return errors.New("transport max idle connections per host must not be negative")
+	}
+
+	if c.TLSSessionCacheSize < 0 {
+		return errors.New("transport TLS session cache size must not be negative")
+	}
+
+	for pattern, dialContext := range c.Dialers {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid dialer pattern %q: %w", pattern, err)
+		}
+		if dialContext == nil {
+			return fmt.Errorf("nil dialer for pattern %q", pattern)
+		}
+	}
+
+	if err := c.TLS.Validate(); err != nil {
+		return fmt.Errorf("invalid TLS policy: %w", err)
+	}
+
+	return nil
+}
+
+// Validate returns an error if the default transport or any of the service
+// transport overrides is invalid.
+func (c TransportConfig) Validate() error {
+	if err := c.Default.Validate(); err != nil {
+		return fmt.Errorf("invalid default transport: %w", err)
+	}
+
+	for serviceId, serviceConfig := range c.Services {
+		if err := serviceConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid transport of service %s: %w", serviceId, err)
+		}
+	}
+
+	return nil
+}
+
+// NewRelaySenderFromConfig returns a RelaySender which sends the relays of each
+// service using the transport configured for it, as built by NewHTTPRelaySender
+// with the given options.
+// An error is returned if the config is invalid.
+func NewRelaySenderFromConfig(config TransportConfig, opts ...RelaySenderOption) (RelaySender, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("NewRelaySenderFromConfig: %w", err)
+	}
+
+	defaultClient, err := newHTTPClient(config.Default)
+	if err != nil {
+		return nil, fmt.Errorf("NewRelaySenderFromConfig: %w", err)
+	}
+	defaultSender := NewHTTPRelaySender(defaultClient, opts...)
+
+	serviceSenders := make(map[string]RelaySender, len(config.Services))
+	for serviceId, serviceConfig := range config.Services {
+		serviceClient, err := newHTTPClient(serviceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("NewRelaySenderFromConfig: service %s: %w", serviceId, err)
+		}
+		serviceSenders[serviceId] = NewHTTPRelaySender(serviceClient, opts...)
+	}
+
+	return func(
+		ctx context.Context,
+		endpoint Endpoint,
+		relayRequest *servicetypes.RelayRequest,
+	) ([]byte, error) {
+		if sender, ok := serviceSenders[endpoint.Header().ServiceId]; ok {
+			return sender(ctx, endpoint, relayRequest)
+		}
+		return defaultSender(ctx, endpoint, relayRequest)
+	}, nil
+}
+
+// NewHTTPClientFromConfig returns an HTTP client using a transport tuned by the
+// given config, e.g. to share its connections between a RelaySender built by
+// NewHTTPRelaySender and a ConnectionWarmer.
+// An error is returned if the config is invalid.
+func NewHTTPClientFromConfig(config HTTPTransportConfig) (*http.Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("NewHTTPClientFromConfig: %w", err)
+	}
+
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("NewHTTPClientFromConfig: %w", err)
+	}
+	return httpClient, nil
+}
+
+// newHTTPClient returns an HTTP client using a transport tuned by the given config.
+func newHTTPClient(config HTTPTransportConfig) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("error building TLS config: %w", err)
+	}
+	if config.TLSSessionCacheSize > 0 {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(config.TLSSessionCacheSize)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	if config.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = config.TLSHandshakeTimeout
+	}
+	if len(config.Dialers) > 0 {
+		transport.DialContext = newPatternDialer(config.Dialers, transport.DialContext)
+	}
+
+	switch config.Protocol {
+	case TransportH2:
+		transport.ForceAttemptHTTP2 = true
+	default:
+		// A non-nil, empty TLSNextProto map disables HTTP/2.
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   config.Timeout,
+	}, nil
+}
+
+// newPatternDialer returns a DialContextFunc which dials each address using the
+// dialer of the first matching pattern, in lexical order of the patterns, or
+// using the given default dialer if no pattern matches.
+func newPatternDialer(dialers map[string]DialContextFunc, defaultDialer DialContextFunc) DialContextFunc {
+	patterns := slices.Sorted(maps.Keys(dialers))
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, address); matched {
+				return dialers[pattern](ctx, network, address)
+			}
+		}
+		return defaultDialer(ctx, network, address)
+	}
+}
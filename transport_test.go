@@ -0,0 +1,230 @@
+package sdk
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportConfig_Validate(t *testing.T) {
+	tests := []struct {
+		desc           string
+		config         TransportConfig
+		expectErr      bool
+		expectErrMatch string
+	}{
+		{
+			desc: "zero config is valid",
+		},
+		{
+			desc: "valid service override",
+			config: TransportConfig{
+				Default:  HTTPTransportConfig{Protocol: TransportHTTP1, Timeout: time.Second},
+				Services: map[string]HTTPTransportConfig{"svc1": {Protocol: TransportH2, MaxIdleConnsPerHost: 100}},
+			},
+		},
+		{
+			desc: "invalid dialer pattern",
+			config: TransportConfig{
+				Default: HTTPTransportConfig{Dialers: map[string]DialContextFunc{"[": UnixSocketDialer("/tmp/s.sock")}},
+			},
+			expectErr: true,
+		},
+		{
+			desc: "custom CA verification without CA bundle",
+			config: TransportConfig{
+				Default: HTTPTransportConfig{TLS: TLSPolicy{Verification: TLSVerifyCustomCA}},
+			},
+			expectErr: true,
+		},
+		{
+			desc: "invalid SPKI pin",
+			config: TransportConfig{
+				Default: HTTPTransportConfig{TLS: TLSPolicy{SPKIPins: map[string][]string{"*.example": {"not-a-pin"}}}},
+			},
+			expectErr: true,
+		},
+		{
+			desc:      "negative TLS session cache size",
+			config:    TransportConfig{Default: HTTPTransportConfig{TLSSessionCacheSize: -1}},
+			expectErr: true,
+		},
+		{
+			desc:      "unsupported default protocol",
+			config:    TransportConfig{Default: HTTPTransportConfig{Protocol: "http3"}},
+			expectErr: true,
+		},
+		{
+			desc: "WebSocket service protocol",
+			config: TransportConfig{
+				Services: map[string]HTTPTransportConfig{"svc1": {Protocol: TransportWS}},
+			},
+			expectErr:      true,
+			expectErrMatch: "use DialWebSocketRelay",
+		},
+		{
+			desc: "negative service timeout",
+			config: TransportConfig{
+				Services: map[string]HTTPTransportConfig{"svc1": {Timeout: -time.Second}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.expectErr {
+				require.Error(t, err)
+				require.ErrorContains(t, err, test.expectErrMatch)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	// A WebSocket transport is rejected when building a RelaySender.
+	_, err := NewRelaySenderFromConfig(TransportConfig{Default: HTTPTransportConfig{Protocol: TransportWS}})
+	require.ErrorContains(t, err, "use DialWebSocketRelay")
+}
+
+func TestNewHTTPClient_Protocol(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		protocol      TransportProtocol
+		expectedProto string
+	}{
+		{protocol: TransportHTTP1, expectedProto: "HTTP/1.1"},
+		{protocol: TransportH2, expectedProto: "HTTP/2.0"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.protocol), func(t *testing.T) {
+			client, err := newHTTPClient(HTTPTransportConfig{Protocol: test.protocol})
+			require.NoError(t, err)
+			// Trust the test server's certificate.
+			client.Transport.(*http.Transport).TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+
+			resp, err := client.Get(server.URL)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, test.expectedProto, resp.Proto)
+		})
+	}
+}
+
+func TestNewHTTPClient_UnixSocketDialer(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "supplier.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("unix:" + r.Host))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	client, err := newHTTPClient(HTTPTransportConfig{
+		Dialers: map[string]DialContextFunc{"*.internal:*": UnixSocketDialer(socketPath)},
+	})
+	require.NoError(t, err)
+
+	// Endpoints matching the pattern are served through the Unix domain socket.
+	resp, err := client.Get("http://supplier.internal:8545")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "unix:supplier.internal:8545", string(body))
+
+	// Endpoints not matching any pattern are dialed over TCP.
+	_, err = client.Get("http://127.0.0.1:1")
+	require.Error(t, err)
+}
+
+func TestNewHTTPClient_TLSPolicy(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	serverCert := server.Certificate()
+	serverCertPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverCert.Raw})
+	otherPin := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	tests := []struct {
+		desc      string
+		policy    TLSPolicy
+		expectErr bool
+	}{
+		{
+			desc:      "system roots do not trust the test server",
+			policy:    TLSPolicy{},
+			expectErr: true,
+		},
+		{
+			desc:   "custom CA bundle trusts the test server",
+			policy: TLSPolicy{Verification: TLSVerifyCustomCA, CABundlePEM: serverCertPEM},
+		},
+		{
+			desc:   "insecure skips the verification",
+			policy: TLSPolicy{Verification: TLSVerifyInsecure},
+		},
+		{
+			desc: "matching SPKI pin",
+			policy: TLSPolicy{
+				Verification: TLSVerifyInsecure,
+				SPKIPins:     map[string][]string{"*.com": {otherPin, SPKIPin(serverCert)}},
+			},
+		},
+		{
+			desc: "mismatching SPKI pin",
+			policy: TLSPolicy{
+				Verification: TLSVerifyCustomCA,
+				CABundlePEM:  serverCertPEM,
+				SPKIPins:     map[string][]string{"example.com": {otherPin}},
+			},
+			expectErr: true,
+		},
+		{
+			desc: "SPKI pins of other hosts are ignored",
+			policy: TLSPolicy{
+				Verification: TLSVerifyCustomCA,
+				CABundlePEM:  serverCertPEM,
+				SPKIPins:     map[string][]string{"*.supplier.example": {otherPin}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			client, err := newHTTPClient(HTTPTransportConfig{TLS: test.policy})
+			require.NoError(t, err)
+			// The test server's certificate is valid for "example.com".
+			client.Transport.(*http.Transport).TLSClientConfig.ServerName = "example.com"
+
+			resp, err := client.Get(server.URL)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			resp.Body.Close()
+		})
+	}
+}
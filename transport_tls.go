@@ -0,0 +1,134 @@
+package sdk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+)
+
+// TLSVerification specifies how the certificate chains of the suppliers'
+// endpoints are verified.
+type TLSVerification string
+
+const (
+	// TLSVerifySystemRoots verifies the certificate chains using the system's
+	// root CAs. It is the default.
+	TLSVerifySystemRoots TLSVerification = "system"
+	// TLSVerifyCustomCA verifies the certificate chains using the CAs of the
+	// configured CA bundle, in place of the system's root CAs, e.g. to relay to
+	// suppliers using a private CA.
+	TLSVerifyCustomCA TLSVerification = "custom_ca"
+	// TLSVerifyInsecure skips the verification of the certificate chains.
+	// It must only be used for LocalNet, or along with SPKI pins.
+	TLSVerifyInsecure TLSVerification = "insecure"
+)
+
+// TLSPolicy specifies how the TLS certificates of the suppliers' endpoints are
+// validated by the relay transport.
+type TLSPolicy struct {
+	// Verification specifies how the certificate chains are verified.
+	// It defaults to TLSVerifySystemRoots.
+	Verification TLSVerification `yaml:"verification"`
+	// CABundlePEM holds the PEM-encoded CA certificates used by TLSVerifyCustomCA.
+	CABundlePEM []byte `yaml:"ca_bundle_pem"`
+	// SPKIPins holds the pinned public keys of the endpoints, keyed by endpoint
+	// host pattern, using path.Match syntax, e.g. "*.supplier1.example".
+	// A pin is the base64-encoded SHA-256 hash of a certificate's
+	// DER-encoded SubjectPublicKeyInfo, as used by HPKP.
+	// Connections to a host matching a pattern fail unless a certificate of the
+	// presented chain matches one of the pattern's pins.
+	// Pins are checked in addition to, not in place of, the chain verification.
+	SPKIPins map[string][]string `yaml:"spki_pins"`
+}
+
+// Validate returns an error if the policy can not be used to build a TLS config.
+func (p TLSPolicy) Validate() error {
+	_, err := newTLSConfig(p)
+	return err
+}
+
+// newTLSConfig returns the TLS client config enforcing the given policy.
+func newTLSConfig(policy TLSPolicy) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	switch policy.Verification {
+	case "", TLSVerifySystemRoots:
+	case TLSVerifyCustomCA:
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(policy.CABundlePEM) {
+			return nil, errors.New("no valid CA certificate in the TLS CA bundle")
+		}
+		tlsConfig.RootCAs = rootCAs
+	case TLSVerifyInsecure:
+		tlsConfig.InsecureSkipVerify = true
+	default:
+		return nil, fmt.Errorf(
+			"unsupported TLS verification %q: must be one of %q, %q, %q",
+			policy.Verification, TLSVerifySystemRoots, TLSVerifyCustomCA, TLSVerifyInsecure,
+		)
+	}
+
+	if len(policy.SPKIPins) == 0 {
+		return tlsConfig, nil
+	}
+
+	pins := make(map[string][][]byte, len(policy.SPKIPins))
+	for pattern, patternPins := range policy.SPKIPins {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid SPKI pin pattern %q: %w", pattern, err)
+		}
+		if len(patternPins) == 0 {
+			return nil, fmt.Errorf("no SPKI pin for pattern %q", pattern)
+		}
+		for _, pin := range patternPins {
+			pinHash, err := base64.StdEncoding.DecodeString(pin)
+			if err != nil || len(pinHash) != sha256.Size {
+				return nil, fmt.Errorf("invalid SPKI pin %q for pattern %q: must be a base64-encoded SHA-256 hash", pin, pattern)
+			}
+			pins[pattern] = append(pins[pattern], pinHash)
+		}
+	}
+	tlsConfig.VerifyConnection = newSPKIPinVerifier(pins)
+
+	return tlsConfig, nil
+}
+
+// newSPKIPinVerifier returns a function verifying that the certificate chain
+// presented by a host matches one of the pins of the first matching pattern,
+// in lexical order of the patterns. Hosts not matching any pattern are accepted.
+func newSPKIPinVerifier(pins map[string][][]byte) func(tls.ConnectionState) error {
+	patterns := slices.Sorted(maps.Keys(pins))
+
+	return func(state tls.ConnectionState) error {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, state.ServerName); !matched {
+				continue
+			}
+
+			for _, cert := range state.PeerCertificates {
+				spkiHash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+				for _, pin := range pins[pattern] {
+					if bytes.Equal(spkiHash[:], pin) {
+						return nil
+					}
+				}
+			}
+			return fmt.Errorf("no certificate of %s matches its SPKI pins", state.ServerName)
+		}
+
+		return nil
+	}
+}
+
+// SPKIPin returns the SPKI pin of the given certificate, for use in TLSPolicy.SPKIPins.
+func SPKIPin(cert *x509.Certificate) string {
+	spkiHash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(spkiHash[:])
+}
@@ -0,0 +1,159 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// minJSONRPCServerErrorCode and maxJSONRPCServerErrorCode bound the range of
+	// JSON-RPC error codes reserved for implementation-defined server errors.
+	// See: https://www.jsonrpc.org/specification#error_object
+	minJSONRPCServerErrorCode = -32099
+	maxJSONRPCServerErrorCode = -32000
+)
+
+// defaultErrorFormatter is the ErrorFormatter used by POKTHTTPRequest.FormatError.
+var defaultErrorFormatter = &ErrorFormatter{config: ErrorFormatConfig{
+	InternalErrorMessage: defaultErrorMessage,
+	JSONRPCErrorCode:     defaultJSONRPCErrorCode,
+}}
+
+// ErrorFormatConfig customizes the error responses built by an ErrorFormatter,
+// e.g. to match a gateway's branding.
+type ErrorFormatConfig struct {
+	// InternalErrorMessage is the message returned in place of internal errors.
+	// It defaults to "Internal error".
+	InternalErrorMessage string `yaml:"internal_error_message"`
+
+	// JSONRPCErrorCode is the code of the returned JSON-RPC errors. It must be
+	// within the [-32099, -32000] range reserved for server errors, and defaults to -32000.
+	JSONRPCErrorCode int `yaml:"jsonrpc_error_code"`
+
+	// SupportURL, if set, is included in the error responses, e.g. to point
+	// users to the gateway's support page.
+	// It is returned in the "data" field of JSON-RPC errors, and in the
+	// "support_url" field of JSON REST errors.
+	SupportURL string `yaml:"support_url"`
+}
+
+// Validate returns an error if the config can not be used to format errors.
+func (c ErrorFormatConfig) Validate() error {
+	if c.JSONRPCErrorCode < minJSONRPCServerErrorCode || c.JSONRPCErrorCode > maxJSONRPCServerErrorCode {
+		return fmt.Errorf(
+			"invalid JSON-RPC error code %d: must be within [%d, %d]",
+			c.JSONRPCErrorCode,
+			minJSONRPCServerErrorCode,
+			maxJSONRPCServerErrorCode,
+		)
+	}
+
+	if strings.TrimSpace(c.InternalErrorMessage) == "" {
+		return errors.New("internal error message must not be empty")
+	}
+
+	if c.SupportURL != "" {
+		supportURL, err := url.ParseRequestURI(c.SupportURL)
+		if err != nil {
+			return fmt.Errorf("invalid support URL %q: %w", c.SupportURL, err)
+		}
+		if supportURL.Scheme != "http" && supportURL.Scheme != "https" {
+			return fmt.Errorf("invalid support URL %q: scheme must be http or https", c.SupportURL)
+		}
+	}
+
+	return nil
+}
+
+// ErrorFormatter formats errors into POKTHTTPResponses matching the RPC type
+// of the request, using a validated ErrorFormatConfig.
+type ErrorFormatter struct {
+	config ErrorFormatConfig
+}
+
+// NewErrorFormatter returns an ErrorFormatter using the given config, with
+// its unset fields set to their defaults.
+// An error is returned if the config is invalid, so that misconfigurations
+// are caught when the config is loaded.
+func NewErrorFormatter(config ErrorFormatConfig) (*ErrorFormatter, error) {
+	if config.InternalErrorMessage == "" {
+		config.InternalErrorMessage = defaultErrorMessage
+	}
+	if config.JSONRPCErrorCode == 0 {
+		config.JSONRPCErrorCode = defaultJSONRPCErrorCode
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("NewErrorFormatter: %w", err)
+	}
+
+	return &ErrorFormatter{config: config}, nil
+}
+
+// FormatError formats the given error into a POKTHTTPResponse matching the
+// RPC type of the given request, and its corresponding byte representation.
+//
+// The errors of the requests to gRPC services are formatted as required by
+// their protocol, with the Internal gRPC status code if isInternal is true,
+// and InvalidArgument otherwise. See FormatErrorWithStatus.
+func (f *ErrorFormatter) FormatError(
+	request *POKTHTTPRequest,
+	err error,
+	isInternal bool,
+) (*POKTHTTPResponse, []byte) {
+	switch request.GetRPCType() {
+	case RPCTypeGRPC:
+		statusCode := http.StatusBadRequest
+		if isInternal {
+			statusCode = http.StatusInternalServerError
+		}
+		return request.formatGRPCError(err, statusCode, f.config)
+	case RPCTypeJSONRPC:
+		return request.formatJSONRPCError(err, isInternal, f.config)
+	case RPCTypeREST:
+		return request.formatRESTError(err, isInternal, f.config)
+	default:
+		return unsupportedRPCTypeErrorReply, unsupportedRPCTypeErrorReplyBz
+	}
+}
+
+// FormatErrorWithStatus formats the given error, reported by the gateway with
+// the given HTTP status code, e.g. 503 if the relay was shed, into a
+// POKTHTTPResponse matching the RPC type of the given request, and its
+// corresponding byte representation.
+// The message of the server errors, i.e. with a 5xx status code, is not exposed.
+//
+// The responses to the requests to gRPC services carry the matching gRPC
+// status code, e.g. Unavailable for a 503, with the HTTP status code required
+// by their protocol: the gRPC errors are returned in the grpc-status and
+// grpc-message headers of a response with a 200 status code.
+// The other responses have the given status code.
+func (f *ErrorFormatter) FormatErrorWithStatus(
+	request *POKTHTTPRequest,
+	err error,
+	statusCode int,
+) (*POKTHTTPResponse, []byte) {
+	if request.GetRPCType() == RPCTypeGRPC {
+		return request.formatGRPCError(err, statusCode, f.config)
+	}
+
+	errorResponse, _ := f.FormatError(request, err, statusCode >= http.StatusInternalServerError)
+	// The shared error replies are not modified.
+	errorResponse = &POKTHTTPResponse{
+		StatusCode: uint32(statusCode),
+		Header:     errorResponse.GetHeader(),
+		BodyBz:     errorResponse.GetBodyBz(),
+	}
+
+	errorResponseBz, err := proto.Marshal(errorResponse)
+	if err != nil {
+		return defaultRESTErrorReply, defaultRESTErrorReplyBz
+	}
+
+	return errorResponse, errorResponseBz
+}
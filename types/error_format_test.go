@@ -0,0 +1,137 @@
+package types_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestErrorFormatter_Validation(t *testing.T) {
+	tests := []struct {
+		desc      string
+		config    types.ErrorFormatConfig
+		expectErr bool
+	}{
+		{desc: "default config", config: types.ErrorFormatConfig{}},
+		{
+			desc: "custom config",
+			config: types.ErrorFormatConfig{
+				InternalErrorMessage: "Gateway error",
+				JSONRPCErrorCode:     -32050,
+				SupportURL:           "https://support.example.com",
+			},
+		},
+		{desc: "JSON-RPC code out of range", config: types.ErrorFormatConfig{JSONRPCErrorCode: -32700}, expectErr: true},
+		{desc: "message with quotes", config: types.ErrorFormatConfig{InternalErrorMessage: `say "hi" \o/`}},
+		{desc: "blank message", config: types.ErrorFormatConfig{InternalErrorMessage: " "}, expectErr: true},
+		{desc: "relative support URL", config: types.ErrorFormatConfig{SupportURL: "support"}, expectErr: true},
+		{desc: "non-HTTP support URL", config: types.ErrorFormatConfig{SupportURL: "ftp://support.example.com"}, expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := types.NewErrorFormatter(test.config)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestErrorFormatter_FormatError(t *testing.T) {
+	formatter, err := types.NewErrorFormatter(types.ErrorFormatConfig{
+		InternalErrorMessage: "Gateway error",
+		JSONRPCErrorCode:     -32050,
+		SupportURL:           "https://support.example.com",
+	})
+	require.NoError(t, err)
+
+	jsonHeader := map[string]*types.Header{
+		contentTypeHeaderKey: {Key: contentTypeHeaderKey, Values: []string{contentTypeHeaderValueJSON}},
+	}
+
+	jsonRPCRequest := &types.POKTHTTPRequest{Header: jsonHeader, Method: method, Url: requestUrl, BodyBz: jsonRPCContentBz}
+	response, _ := formatter.FormatError(jsonRPCRequest, errDefault, true)
+	require.Equal(t, uint32(http.StatusOK), response.StatusCode)
+	require.Equal(
+		t,
+		`{"error":{"code":-32050,"data":{"support_url":"https://support.example.com"},"message":"Gateway error"},"id":1,"jsonrpc":"2.0"}`,
+		string(response.BodyBz),
+	)
+
+	restRequest := &types.POKTHTTPRequest{Header: jsonHeader, Method: method, Url: requestUrl, BodyBz: restContentBz}
+	response, _ = formatter.FormatError(restRequest, errDefault, true)
+	require.Equal(t, uint32(http.StatusInternalServerError), response.StatusCode)
+	require.Equal(t, `{"error":"Gateway error","support_url":"https://support.example.com"}`, string(response.BodyBz))
+
+	// Messages with quotes or backslashes are JSON-encoded.
+	quotingFormatter, err := types.NewErrorFormatter(types.ErrorFormatConfig{InternalErrorMessage: `say "hi" \o/`})
+	require.NoError(t, err)
+
+	response, _ = quotingFormatter.FormatError(restRequest, errDefault, true)
+	require.JSONEq(t, `{"error":"say \"hi\" \\o/"}`, string(response.BodyBz))
+	response, _ = quotingFormatter.FormatError(jsonRPCRequest, errDefault, true)
+	require.JSONEq(t, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"data":null,"message":"say \"hi\" \\o/"}}`, string(response.BodyBz))
+	response, _ = quotingFormatter.FormatError(restRequest, errors.New(`bad "param"`), false)
+	require.JSONEq(t, `{"error":"bad \"param\""}`, string(response.BodyBz))
+}
+
+func TestErrorFormatter_FormatGRPCError(t *testing.T) {
+	formatter, err := types.NewErrorFormatter(types.ErrorFormatConfig{})
+	require.NoError(t, err)
+
+	newRequest := func(contentType string, extraHeaders ...string) *types.POKTHTTPRequest {
+		header := map[string]*types.Header{
+			contentTypeHeaderKey: {Key: contentTypeHeaderKey, Values: []string{contentType}},
+		}
+		for _, key := range extraHeaders {
+			header[key] = &types.Header{Key: key, Values: []string{"1"}}
+		}
+		return &types.POKTHTTPRequest{Header: header, Method: method, Url: requestUrl + "/pkg.Service/Method"}
+	}
+	headerValue := func(response *types.POKTHTTPResponse, key string) string {
+		return response.Header[key].GetValues()[0]
+	}
+
+	// gRPC errors are returned in the headers of a Trailers-Only response.
+	response, _ := formatter.FormatErrorWithStatus(newRequest("application/grpc"), errors.New("no 100% match"), http.StatusBadRequest)
+	require.Equal(t, uint32(http.StatusOK), response.StatusCode)
+	require.Equal(t, "application/grpc", headerValue(response, contentTypeHeaderKey))
+	require.Equal(t, "3", headerValue(response, "Grpc-Status"))
+	require.Equal(t, "no 100%25 match", headerValue(response, "Grpc-Message"))
+	require.Empty(t, response.BodyBz)
+
+	// The message of the server errors is not exposed.
+	response, _ = formatter.FormatErrorWithStatus(newRequest("application/grpc-web+proto"), errDefault, http.StatusServiceUnavailable)
+	require.Equal(t, uint32(http.StatusOK), response.StatusCode)
+	require.Equal(t, "14", headerValue(response, "Grpc-Status"))
+	require.Equal(t, "Internal error", headerValue(response, "Grpc-Message"))
+
+	response, _ = formatter.FormatError(newRequest("application/grpc"), errDefault, true)
+	require.Equal(t, "13", headerValue(response, "Grpc-Status"))
+
+	// Connect unary errors are JSON errors, with the HTTP status code matching the gRPC one.
+	response, _ = formatter.FormatErrorWithStatus(newRequest("application/proto", "Connect-Protocol-Version"), errDefault, http.StatusGatewayTimeout)
+	require.Equal(t, uint32(http.StatusGatewayTimeout), response.StatusCode)
+	require.Equal(t, contentTypeHeaderValueJSON, headerValue(response, contentTypeHeaderKey))
+	require.JSONEq(t, `{"code":"deadline_exceeded","message":"Internal error"}`, string(response.BodyBz))
+
+	// Connect streaming errors are returned in the end-of-stream message.
+	response, _ = formatter.FormatErrorWithStatus(newRequest("application/connect+proto"), errDefault, http.StatusBadRequest)
+	require.Equal(t, uint32(http.StatusOK), response.StatusCode)
+	require.Equal(t, "application/connect+proto", headerValue(response, contentTypeHeaderKey))
+	endStream := `{"error":{"code":"invalid_argument","message":"error"}}`
+	require.Equal(t, append([]byte{0x02, 0, 0, 0, byte(len(endStream))}, endStream...), response.BodyBz)
+
+	// The other responses have the given status code.
+	restRequest := &types.POKTHTTPRequest{Method: method, Url: requestUrl, BodyBz: restContentBz}
+	response, _ = formatter.FormatErrorWithStatus(restRequest, errDefault, http.StatusBadGateway)
+	require.Equal(t, uint32(http.StatusBadGateway), response.StatusCode)
+	require.Equal(t, "Internal error", string(response.BodyBz))
+}
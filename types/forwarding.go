@@ -0,0 +1,140 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ForwardingMode specifies how the forwarding headers of the client requests,
+// e.g. X-Forwarded-For, are relayed to the suppliers.
+type ForwardingMode string
+
+const (
+	// ForwardingPassThrough relays the forwarding headers as received from the
+	// client. It is the default mode.
+	ForwardingPassThrough ForwardingMode = "pass_through"
+	// ForwardingStrip removes the forwarding headers, so the client IPs are not
+	// disclosed to the suppliers.
+	ForwardingStrip ForwardingMode = "strip"
+	// ForwardingAppend appends the client IP, i.e. the gateway hop, to the
+	// X-Forwarded-For header, and to the Forwarded header if present, as a
+	// reverse proxy does, e.g. for backends rate limiting per client IP.
+	ForwardingAppend ForwardingMode = "append"
+)
+
+// forwardingHeaders are the headers disclosing the clients and the proxies a
+// request went through.
+var forwardingHeaders = []string{
+	"Forwarded",
+	"X-Forwarded-For",
+	"X-Forwarded-Host",
+	"X-Forwarded-Proto",
+	"X-Real-Ip",
+}
+
+// ForwardingPolicy specifies how the forwarding headers of the client requests
+// are relayed to the suppliers.
+// The zero value passes the headers through.
+type ForwardingPolicy struct {
+	// Mode is the forwarding mode. It defaults to ForwardingPassThrough.
+	Mode ForwardingMode `yaml:"mode"`
+}
+
+// Validate returns an error if the policy's mode is not supported.
+func (p ForwardingPolicy) Validate() error {
+	switch p.Mode {
+	case "", ForwardingPassThrough, ForwardingStrip, ForwardingAppend:
+		return nil
+	default:
+		return fmt.Errorf(
+			"unsupported forwarding mode %q: must be one of %q, %q, %q",
+			p.Mode,
+			ForwardingPassThrough,
+			ForwardingStrip,
+			ForwardingAppend,
+		)
+	}
+}
+
+// ApplyForwardingPolicy rewrites the forwarding headers of the request according
+// to the given policy. The client address, e.g. the RemoteAddr of the client's
+// http.Request, is only used by ForwardingAppend, and may include a port.
+func (poktRequest *POKTHTTPRequest) ApplyForwardingPolicy(policy ForwardingPolicy, clientAddr string) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	if poktRequest.Header == nil {
+		poktRequest.Header = map[string]*Header{}
+	}
+
+	switch policy.Mode {
+	case ForwardingStrip:
+		for _, key := range forwardingHeaders {
+			poktRequest.deleteHeader(key)
+		}
+
+	case ForwardingAppend:
+		clientIP, err := parseClientIP(clientAddr)
+		if err != nil {
+			return err
+		}
+
+		forwardedFor := append(poktRequest.headerValues("X-Forwarded-For"), clientIP.String())
+		poktRequest.deleteHeader("X-Forwarded-For")
+		poktRequest.setHeader("X-Forwarded-For", strings.Join(forwardedFor, ", "))
+
+		if forwarded := poktRequest.headerValues("Forwarded"); len(forwarded) > 0 {
+			poktRequest.deleteHeader("Forwarded")
+			poktRequest.setHeader("Forwarded", strings.Join(append(forwarded, forwardedElement(clientIP)), ", "))
+		}
+	}
+
+	return nil
+}
+
+// SerializeHTTPRequestWithForwardingPolicy serializes the given http.Request, as
+// SerializeHTTPRequest does, after applying the given forwarding policy to its
+// headers, using its RemoteAddr as the client address.
+func SerializeHTTPRequestWithForwardingPolicy(
+	request *http.Request,
+	policy ForwardingPolicy,
+) (poktHTTPRequest *POKTHTTPRequest, poktHTTPRequestBz []byte, err error) {
+	poktHTTPRequest, _, err = SerializeHTTPRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := poktHTTPRequest.ApplyForwardingPolicy(policy, request.RemoteAddr); err != nil {
+		return nil, nil, err
+	}
+
+	// Use deterministic marshalling, consistently with BuildHTTPRequest.
+	poktHTTPRequestBz, err = proto.MarshalOptions{Deterministic: true}.Marshal(poktHTTPRequest)
+	return poktHTTPRequest, poktHTTPRequestBz, err
+}
+
+// parseClientIP returns the IP of the given client address, with or without a port.
+func parseClientIP(clientAddr string) (net.IP, error) {
+	host := clientAddr
+	if splitHost, _, err := net.SplitHostPort(clientAddr); err == nil {
+		host = splitHost
+	}
+
+	clientIP := net.ParseIP(strings.Trim(host, "[]"))
+	if clientIP == nil {
+		return nil, fmt.Errorf("invalid client address %q", clientAddr)
+	}
+	return clientIP, nil
+}
+
+// forwardedElement returns the element of the Forwarded header identifying the
+// given client IP, quoting IPv6 addresses as required by RFC 7239.
+func forwardedElement(clientIP net.IP) string {
+	if clientIP.To4() == nil {
+		return fmt.Sprintf(`for="[%s]"`, clientIP)
+	}
+	return "for=" + clientIP.String()
+}
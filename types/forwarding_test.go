@@ -0,0 +1,129 @@
+package types_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestPOKTHTTPRequest_ApplyForwardingPolicy(t *testing.T) {
+	clientHeader := http.Header{
+		"Content-Type":      {"application/json"},
+		"Forwarded":         {"for=192.0.2.60;proto=https"},
+		"X-Forwarded-For":   {"192.0.2.60"},
+		"X-Forwarded-Proto": {"https"},
+		"X-Real-Ip":         {"192.0.2.60"},
+	}
+
+	tests := []struct {
+		desc           string
+		policy         types.ForwardingPolicy
+		header         http.Header
+		clientAddr     string
+		expectedHeader http.Header
+		expectErr      bool
+	}{
+		{
+			desc:           "zero value passes the headers through",
+			header:         clientHeader,
+			expectedHeader: clientHeader,
+		},
+		{
+			desc:           "pass through",
+			policy:         types.ForwardingPolicy{Mode: types.ForwardingPassThrough},
+			header:         clientHeader,
+			clientAddr:     "198.51.100.17:4711",
+			expectedHeader: clientHeader,
+		},
+		{
+			desc:           "strip",
+			policy:         types.ForwardingPolicy{Mode: types.ForwardingStrip},
+			header:         clientHeader,
+			expectedHeader: http.Header{"Content-Type": {"application/json"}},
+		},
+		{
+			desc:       "append to the existing headers",
+			policy:     types.ForwardingPolicy{Mode: types.ForwardingAppend},
+			header:     clientHeader,
+			clientAddr: "198.51.100.17:4711",
+			expectedHeader: http.Header{
+				"Content-Type":      {"application/json"},
+				"Forwarded":         {"for=192.0.2.60;proto=https, for=198.51.100.17"},
+				"X-Forwarded-For":   {"192.0.2.60, 198.51.100.17"},
+				"X-Forwarded-Proto": {"https"},
+				"X-Real-Ip":         {"192.0.2.60"},
+			},
+		},
+		{
+			desc:       "append an IPv6 client",
+			policy:     types.ForwardingPolicy{Mode: types.ForwardingAppend},
+			header:     http.Header{"Forwarded": {"for=192.0.2.60"}},
+			clientAddr: "[2001:db8::1]:4711",
+			expectedHeader: http.Header{
+				"Forwarded":       {`for=192.0.2.60, for="[2001:db8::1]"`},
+				"X-Forwarded-For": {"2001:db8::1"},
+			},
+		},
+		{
+			desc:           "append without port nor existing headers",
+			policy:         types.ForwardingPolicy{Mode: types.ForwardingAppend},
+			clientAddr:     "198.51.100.17",
+			expectedHeader: http.Header{"X-Forwarded-For": {"198.51.100.17"}},
+		},
+		{
+			desc:       "append an invalid client address",
+			policy:     types.ForwardingPolicy{Mode: types.ForwardingAppend},
+			clientAddr: "client.example:4711",
+			expectErr:  true,
+		},
+		{
+			desc:      "unsupported mode",
+			policy:    types.ForwardingPolicy{Mode: "rewrite"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			poktRequest, _, err := types.BuildHTTPRequest(http.MethodPost, "https://supplier.example", test.header, nil)
+			require.NoError(t, err)
+
+			err = poktRequest.ApplyForwardingPolicy(test.policy, test.clientAddr)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			actualHeader := http.Header{}
+			for key, header := range poktRequest.Header {
+				actualHeader[key] = header.Values
+			}
+			require.Equal(t, test.expectedHeader, actualHeader)
+		})
+	}
+}
+
+func TestSerializeHTTPRequestWithForwardingPolicy(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "https://gateway.example/v1", bytes.NewReader([]byte(`{}`)))
+	request.RemoteAddr = "198.51.100.17:4711"
+	request.Header.Set("X-Forwarded-For", "192.0.2.60")
+
+	poktRequest, poktRequestBz, err := types.SerializeHTTPRequestWithForwardingPolicy(
+		request,
+		types.ForwardingPolicy{Mode: types.ForwardingAppend},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"192.0.2.60, 198.51.100.17"}, poktRequest.Header["X-Forwarded-For"].Values)
+
+	// The serialized request carries the rewritten headers.
+	deserialized, err := types.DeserializeHTTPRequest(poktRequestBz)
+	require.NoError(t, err)
+	require.Equal(t, poktRequest.Header["X-Forwarded-For"].Values, deserialized.Header["X-Forwarded-For"].Values)
+	require.Equal(t, []byte(`{}`), deserialized.BodyBz)
+}
@@ -0,0 +1,228 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// contentTypeHeaderValueGRPC is the content type of gRPC requests.
+	contentTypeHeaderValueGRPC = "application/grpc"
+	// contentTypeHeaderValueConnectStreamingPrefix is the prefix of the content
+	// types of Connect streaming requests, e.g. "application/connect+proto".
+	contentTypeHeaderValueConnectStreamingPrefix = "application/connect+"
+	// connectProtocolVersionHeaderKey is the header identifying Connect unary
+	// requests. See: https://connectrpc.com/docs/protocol
+	connectProtocolVersionHeaderKey = "Connect-Protocol-Version"
+	// grpcStatusHeaderKey and grpcMessageHeaderKey are the headers carrying the
+	// status of a gRPC response.
+	grpcStatusHeaderKey  = "Grpc-Status"
+	grpcMessageHeaderKey = "Grpc-Message"
+	// connectEndStreamFlag is the flag of the envelope carrying the end of a
+	// Connect stream, including its error.
+	connectEndStreamFlag = 0x02
+)
+
+// grpcProtocol is the protocol of a request to a gRPC service.
+type grpcProtocol int
+
+const (
+	grpcProtocolNone grpcProtocol = iota
+	// grpcProtocolGRPC is the gRPC protocol, including gRPC-web.
+	grpcProtocolGRPC
+	// grpcProtocolConnectUnary is the Connect protocol for unary RPCs.
+	grpcProtocolConnectUnary
+	// grpcProtocolConnectStreaming is the Connect protocol for streaming RPCs.
+	grpcProtocolConnectStreaming
+)
+
+// connectCode is the Connect representation of a gRPC status code: its name,
+// and the HTTP status code of the Connect unary responses carrying it.
+type connectCode struct {
+	name       string
+	httpStatus int
+}
+
+// connectCodes are the Connect representations of the gRPC status codes
+// returned by formatGRPCError.
+// See: https://connectrpc.com/docs/protocol#error-codes
+var connectCodes = map[codes.Code]connectCode{
+	codes.Canceled:          {"canceled", 499},
+	codes.Unknown:           {"unknown", http.StatusInternalServerError},
+	codes.InvalidArgument:   {"invalid_argument", http.StatusBadRequest},
+	codes.DeadlineExceeded:  {"deadline_exceeded", http.StatusGatewayTimeout},
+	codes.NotFound:          {"not_found", http.StatusNotFound},
+	codes.PermissionDenied:  {"permission_denied", http.StatusForbidden},
+	codes.ResourceExhausted: {"resource_exhausted", http.StatusTooManyRequests},
+	codes.Unimplemented:     {"unimplemented", http.StatusNotImplemented},
+	codes.Internal:          {"internal", http.StatusInternalServerError},
+	codes.Unavailable:       {"unavailable", http.StatusServiceUnavailable},
+	codes.Unauthenticated:   {"unauthenticated", http.StatusUnauthorized},
+}
+
+// isGRPC checks if the given POKTHTTPRequest is a request to a gRPC service,
+// using the gRPC, gRPC-web or Connect protocol.
+func (poktRequest *POKTHTTPRequest) isGRPC() bool {
+	return poktRequest.grpcProtocol() != grpcProtocolNone
+}
+
+// grpcProtocol returns the protocol of the given POKTHTTPRequest if it is a
+// request to a gRPC service, detected from its content type and, for the
+// Connect unary requests, which can use any content type, from their
+// Connect-Protocol-Version header or "connect" query parameter.
+func (poktRequest *POKTHTTPRequest) grpcProtocol() grpcProtocol {
+	var mediaType string
+	if contentTypes := poktRequest.headerValues(contentTypeHeaderKey); len(contentTypes) > 0 {
+		mediaType, _, _ = mime.ParseMediaType(contentTypes[0])
+	}
+
+	switch {
+	case mediaType == contentTypeHeaderValueGRPC,
+		strings.HasPrefix(mediaType, contentTypeHeaderValueGRPC+"+"),
+		strings.HasPrefix(mediaType, contentTypeHeaderValueGRPC+"-web"):
+		return grpcProtocolGRPC
+	case strings.HasPrefix(mediaType, contentTypeHeaderValueConnectStreamingPrefix):
+		return grpcProtocolConnectStreaming
+	case len(poktRequest.headerValues(connectProtocolVersionHeaderKey)) > 0:
+		return grpcProtocolConnectUnary
+	}
+
+	// Connect unary requests may also be sent using GET, without a body.
+	if requestURL, err := url.Parse(poktRequest.Url); err == nil && requestURL.Query().Get("connect") == "v1" {
+		return grpcProtocolConnectUnary
+	}
+	return grpcProtocolNone
+}
+
+// formatGRPCError formats the given error, reported by the gateway with the
+// given HTTP status code, into an error response of the request's protocol,
+// carrying the matching gRPC status code, using the given config:
+//   - gRPC and gRPC-web: a "Trailers-Only" response, with a 200 status code and
+//     the grpc-status and grpc-message headers.
+//   - Connect unary: a JSON error, with the HTTP status code matching the gRPC one.
+//   - Connect streaming: an end-of-stream message carrying the JSON error, with
+//     a 200 status code.
+func (poktRequest *POKTHTTPRequest) formatGRPCError(
+	err error,
+	statusCode int,
+	config ErrorFormatConfig,
+) (*POKTHTTPResponse, []byte) {
+	errorMsg := err.Error()
+	// The message of the server errors is not exposed to the client.
+	if statusCode >= http.StatusInternalServerError {
+		errorMsg = config.InternalErrorMessage
+	}
+	if config.SupportURL != "" {
+		errorMsg = fmt.Sprintf("%s (support: %s)", errorMsg, config.SupportURL)
+	}
+
+	code := grpcCodeFromHTTPStatus(statusCode)
+	connectError := map[string]string{
+		"code":    connectCodes[code].name,
+		"message": errorMsg,
+	}
+
+	contentType := contentTypeHeaderValueGRPC
+	if contentTypes := poktRequest.headerValues(contentTypeHeaderKey); len(contentTypes) > 0 {
+		contentType = contentTypes[0]
+	}
+
+	poktResponse := &POKTHTTPResponse{
+		StatusCode: http.StatusOK,
+		Header:     map[string]*Header{},
+	}
+	switch poktRequest.grpcProtocol() {
+	case grpcProtocolConnectUnary:
+		bodyBz, err := json.Marshal(connectError)
+		if err != nil {
+			return defaultRESTErrorReply, defaultRESTErrorReplyBz
+		}
+		poktResponse.StatusCode = uint32(connectCodes[code].httpStatus)
+		poktResponse.BodyBz = bodyBz
+		contentType = contentTypeHeaderValueJSON
+	case grpcProtocolConnectStreaming:
+		endStreamBz, err := json.Marshal(map[string]any{"error": connectError})
+		if err != nil {
+			return defaultRESTErrorReply, defaultRESTErrorReplyBz
+		}
+		// The end-of-stream message is enveloped: a flags byte, followed by the
+		// big-endian length of the message.
+		poktResponse.BodyBz = binary.BigEndian.AppendUint32([]byte{connectEndStreamFlag}, uint32(len(endStreamBz)))
+		poktResponse.BodyBz = append(poktResponse.BodyBz, endStreamBz...)
+	default:
+		poktResponse.Header[grpcStatusHeaderKey] = &Header{
+			Key:    grpcStatusHeaderKey,
+			Values: []string{strconv.Itoa(int(code))},
+		}
+		poktResponse.Header[grpcMessageHeaderKey] = &Header{
+			Key:    grpcMessageHeaderKey,
+			Values: []string{encodeGRPCMessage(errorMsg)},
+		}
+	}
+	poktResponse.Header[contentTypeHeaderKey] = &Header{
+		Key:    contentTypeHeaderKey,
+		Values: []string{contentType},
+	}
+
+	poktResponseBz, err := proto.Marshal(poktResponse)
+	if err != nil {
+		return defaultRESTErrorReply, defaultRESTErrorReplyBz
+	}
+
+	return poktResponse, poktResponseBz
+}
+
+// grpcCodeFromHTTPStatus returns the gRPC status code matching the given HTTP
+// status code of an error response.
+func grpcCodeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusRequestTimeout:
+		return codes.Canceled
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// encodeGRPCMessage percent-encodes the given message, as required for the
+// grpc-message header.
+// See: https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md#responses
+func encodeGRPCMessage(message string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if c >= ' ' && c <= '~' && c != '%' {
+			encoded.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&encoded, "%%%02X", c)
+	}
+	return encoded.String()
+}
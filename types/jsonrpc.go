@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -63,16 +64,83 @@ func (poktRequest *POKTHTTPRequest) isJSONRPC() bool {
 	return true
 }
 
-// formatJSONRPCError formats the given error into a JSON-RPC error response.
+// GetJSONRPCMethod returns the method of the given POKTHTTPRequest, and a boolean
+// indicating whether the request is a JSON-RPC request.
+func (poktRequest *POKTHTTPRequest) GetJSONRPCMethod() (string, bool) {
+	if !poktRequest.isJSONRPC() {
+		return "", false
+	}
+
+	payload, err := readJSONRPCPayload(poktRequest.BodyBz)
+	if err != nil {
+		return "", false
+	}
+
+	return payload.Method, true
+}
+
+// GetJSONRPCMethods returns the methods called by the body of the given
+// POKTHTTPRequest: the method of a single JSON-RPC call, or the method of each
+// call of a JSON-RPC batch, in order.
+//
+// Unlike GetJSONRPCMethod, it reads the body regardless of the request's
+// Content-Type and of the calls' ids, e.g. string ids, as the JSON-RPC servers
+// do, so it can be used to enforce a policy on the called methods.
+// The returned boolean is false if any method can not be read, e.g. if the body
+// is not JSON, if a call has no method, or if the batch is empty.
+func (poktRequest *POKTHTTPRequest) GetJSONRPCMethods() ([]string, bool) {
+	bodyBz := bytes.TrimSpace(poktRequest.BodyBz)
+	if len(bodyBz) == 0 {
+		return nil, false
+	}
+
+	var calls []jsonRPCMethodCall
+	if bodyBz[0] == '[' {
+		if err := json.Unmarshal(bodyBz, &calls); err != nil || len(calls) == 0 {
+			return nil, false
+		}
+	} else {
+		var call jsonRPCMethodCall
+		if err := json.Unmarshal(bodyBz, &call); err != nil {
+			return nil, false
+		}
+		calls = []jsonRPCMethodCall{call}
+	}
+
+	methods := make([]string, 0, len(calls))
+	for _, call := range calls {
+		if call.Method == nil || *call.Method == "" {
+			return nil, false
+		}
+		methods = append(methods, *call.Method)
+	}
+
+	return methods, true
+}
+
+// jsonRPCMethodCall is the method of a JSON-RPC call, which is nil if the call
+// has no method. A call whose method is not a string fails to be unmarshaled.
+type jsonRPCMethodCall struct {
+	Method *string `json:"method"`
+}
+
+// formatJSONRPCError formats the given error into a JSON-RPC error response,
+// using the given config.
 func (poktRequestBz *POKTHTTPRequest) formatJSONRPCError(
 	err error,
 	isInternal bool,
+	config ErrorFormatConfig,
 ) (*POKTHTTPResponse, []byte) {
 	errorMsg := err.Error()
 	// If the error is internal, we don't to expose the error message to the client
 	// but instead return a generic error message.
 	if isInternal {
-		errorMsg = defaultErrorMessage
+		errorMsg = config.InternalErrorMessage
+	}
+
+	var errorData interface{}
+	if config.SupportURL != "" {
+		errorData = map[string]interface{}{"support_url": config.SupportURL}
 	}
 
 	requestId := uint64(0)
@@ -85,9 +153,9 @@ func (poktRequestBz *POKTHTTPRequest) formatJSONRPCError(
 		"jsonrpc": "2.0",
 		"id":      requestId,
 		"error": map[string]interface{}{
-			"code":    defaultJSONRPCErrorCode,
+			"code":    config.JSONRPCErrorCode,
 			"message": errorMsg,
-			"data":    nil,
+			"data":    errorData,
 		},
 	}
 
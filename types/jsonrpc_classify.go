@@ -0,0 +1,217 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// JSON-RPC error codes defined by the specification.
+// See: https://www.jsonrpc.org/specification#error_object
+const (
+	jsonRPCParseErrorCode     = -32700
+	jsonRPCInvalidRequestCode = -32600
+	jsonRPCMethodNotFoundCode = -32601
+	jsonRPCInvalidParamsCode  = -32602
+
+	// jsonRPCLimitExceededCode is the error code used by Ethereum clients, e.g.
+	// for rate limited requests. See: EIP-1474.
+	jsonRPCLimitExceededCode = -32005
+)
+
+// nodeNotSyncedMessages are the substrings of the error messages returned by
+// blockchain nodes which are not synced, e.g. missing the requested block.
+var nodeNotSyncedMessages = []string{
+	"not synced",
+	"syncing",
+	"header not found",
+	"block not found",
+	"missing trie node",
+}
+
+// rateLimitMessages are the substrings of the error messages returned by
+// rate limited backends.
+var rateLimitMessages = []string{
+	"rate limit",
+	"too many requests",
+	"limit exceeded",
+}
+
+// ResponseClass classifies a relayed JSON-RPC response.
+type ResponseClass int
+
+const (
+	// ResponseClassSuccess is a successful JSON-RPC response.
+	ResponseClassSuccess ResponseClass = iota
+	// ResponseClassUserError is a JSON-RPC error caused by the request itself,
+	// e.g. invalid params or an unknown method.
+	ResponseClassUserError
+	// ResponseClassRateLimited is an error caused by the backend rate limiting requests.
+	ResponseClassRateLimited
+	// ResponseClassNodeNotSynced is an error caused by the backend node not being synced.
+	ResponseClassNodeNotSynced
+	// ResponseClassBackendError is any other JSON-RPC error returned by the backend.
+	ResponseClassBackendError
+	// ResponseClassHTTPError is an HTTP error status, or a body which is not a JSON-RPC response.
+	ResponseClassHTTPError
+)
+
+// String returns the name of the response class.
+func (c ResponseClass) String() string {
+	switch c {
+	case ResponseClassSuccess:
+		return "success"
+	case ResponseClassUserError:
+		return "user_error"
+	case ResponseClassRateLimited:
+		return "rate_limited"
+	case ResponseClassNodeNotSynced:
+		return "node_not_synced"
+	case ResponseClassBackendError:
+		return "backend_error"
+	case ResponseClassHTTPError:
+		return "http_error"
+	default:
+		return "unknown"
+	}
+}
+
+// IsSupplierFault returns true if the response class indicates a failure of
+// the supplier, as opposed to a success or an error caused by the user's request.
+// It can be used to decide whether a supplier should be penalized, e.g. by a
+// reputation system.
+func (c ResponseClass) IsSupplierFault() bool {
+	return c != ResponseClassSuccess && c != ResponseClassUserError
+}
+
+// ResponseClassification is the classification of a relayed JSON-RPC response.
+type ResponseClassification struct {
+	Class ResponseClass
+	// StatusCode is the HTTP status code of the response.
+	StatusCode uint32
+	// JSONRPCErrorCode and JSONRPCErrorMessage are the code and message of the
+	// JSON-RPC error of the response, if any.
+	JSONRPCErrorCode    int
+	JSONRPCErrorMessage string
+}
+
+// jsonRPCResponseError represents the error object of a JSON-RPC response.
+type jsonRPCResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// jsonRPCResponsePayload represents the JSON-RPC response fields that are
+// relevant for classifying responses.
+type jsonRPCResponsePayload struct {
+	JSONRPC string                `json:"jsonrpc"`
+	Error   *jsonRPCResponseError `json:"error"`
+}
+
+// ClassifyJSONRPCResponse classifies the given relayed JSON-RPC response,
+// distinguishing the backend's JSON-RPC errors from HTTP errors, and the
+// errors caused by the user's request from the supplier's own failures.
+//
+// For batch responses, the classification of the first failed response of the
+// batch is returned.
+func ClassifyJSONRPCResponse(poktResponse *POKTHTTPResponse) ResponseClassification {
+	classification := ResponseClassification{StatusCode: poktResponse.GetStatusCode()}
+
+	if classification.StatusCode == http.StatusTooManyRequests {
+		classification.Class = ResponseClassRateLimited
+		return classification
+	}
+
+	payloads, ok := readJSONRPCResponsePayloads(poktResponse.GetBodyBz())
+	if !ok {
+		classification.Class = ResponseClassHTTPError
+		return classification
+	}
+
+	// An empty batch response answers an empty batch request, which is invalid.
+	if len(payloads) == 0 {
+		classification.Class = ResponseClassUserError
+		return classification
+	}
+
+	for _, payload := range payloads {
+		if payload.Error == nil {
+			continue
+		}
+
+		classification.JSONRPCErrorCode = payload.Error.Code
+		classification.JSONRPCErrorMessage = payload.Error.Message
+		classification.Class = classifyJSONRPCError(payload.Error)
+		return classification
+	}
+
+	// Non-2xx responses without a JSON-RPC error are HTTP errors.
+	if classification.StatusCode < http.StatusOK || classification.StatusCode >= http.StatusMultipleChoices {
+		classification.Class = ResponseClassHTTPError
+		return classification
+	}
+
+	classification.Class = ResponseClassSuccess
+	return classification
+}
+
+// classifyJSONRPCError returns the class of the given JSON-RPC error.
+func classifyJSONRPCError(jsonRPCError *jsonRPCResponseError) ResponseClass {
+	// The codes of the errors caused by the request take precedence over the
+	// message, e.g. invalid params mentioning a block which was "not found".
+	switch jsonRPCError.Code {
+	case jsonRPCParseErrorCode, jsonRPCInvalidRequestCode, jsonRPCMethodNotFoundCode, jsonRPCInvalidParamsCode:
+		return ResponseClassUserError
+	}
+
+	message := strings.ToLower(jsonRPCError.Message)
+	switch {
+	case jsonRPCError.Code == jsonRPCLimitExceededCode || containsAny(message, rateLimitMessages):
+		return ResponseClassRateLimited
+	case containsAny(message, nodeNotSyncedMessages):
+		return ResponseClassNodeNotSynced
+	default:
+		return ResponseClassBackendError
+	}
+}
+
+// readJSONRPCResponsePayloads decodes the given single or batch JSON-RPC
+// response body, and returns false if it is not a JSON-RPC response.
+func readJSONRPCResponsePayloads(bodyBz []byte) ([]jsonRPCResponsePayload, bool) {
+	bodyBz = bytes.TrimSpace(bodyBz)
+	if len(bodyBz) == 0 {
+		return nil, false
+	}
+
+	var payloads []jsonRPCResponsePayload
+	if bodyBz[0] == '[' {
+		if err := json.Unmarshal(bodyBz, &payloads); err != nil {
+			return nil, false
+		}
+	} else {
+		var payload jsonRPCResponsePayload
+		if err := json.Unmarshal(bodyBz, &payload); err != nil {
+			return nil, false
+		}
+		payloads = append(payloads, payload)
+	}
+
+	for _, payload := range payloads {
+		if payload.JSONRPC == "" {
+			return nil, false
+		}
+	}
+
+	return payloads, true
+}
+
+// containsAny returns true if the given string contains any of the given substrings.
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
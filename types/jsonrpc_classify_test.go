@@ -0,0 +1,114 @@
+package types_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestClassifyJSONRPCResponse(t *testing.T) {
+	tests := []struct {
+		desc                 string
+		statusCode           uint32
+		body                 string
+		expectedClass        types.ResponseClass
+		expectSupplierFault  bool
+		expectedJSONRPCError int
+	}{
+		{
+			desc:          "successful response",
+			statusCode:    http.StatusOK,
+			body:          `{"jsonrpc":"2.0","id":1,"result":"0x10"}`,
+			expectedClass: types.ResponseClassSuccess,
+		},
+		{
+			desc:                 "invalid params is a user error",
+			statusCode:           http.StatusOK,
+			body:                 `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid argument 0"}}`,
+			expectedClass:        types.ResponseClassUserError,
+			expectedJSONRPCError: -32602,
+		},
+		{
+			desc:                 "rate limit error code",
+			statusCode:           http.StatusOK,
+			body:                 `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"request limit reached"}}`,
+			expectedClass:        types.ResponseClassRateLimited,
+			expectSupplierFault:  true,
+			expectedJSONRPCError: -32005,
+		},
+		{
+			desc:                "rate limit HTTP status",
+			statusCode:          http.StatusTooManyRequests,
+			body:                "Too Many Requests",
+			expectedClass:       types.ResponseClassRateLimited,
+			expectSupplierFault: true,
+		},
+		{
+			desc:                 "node not synced",
+			statusCode:           http.StatusOK,
+			body:                 `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"header not found"}}`,
+			expectedClass:        types.ResponseClassNodeNotSynced,
+			expectSupplierFault:  true,
+			expectedJSONRPCError: -32000,
+		},
+		{
+			desc:                 "internal backend error",
+			statusCode:           http.StatusInternalServerError,
+			body:                 `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"internal error"}}`,
+			expectedClass:        types.ResponseClassBackendError,
+			expectSupplierFault:  true,
+			expectedJSONRPCError: -32603,
+		},
+		{
+			desc:                 "batch response classified by its first error",
+			statusCode:           http.StatusOK,
+			body:                 `[{"jsonrpc":"2.0","id":1,"result":"0x1"},{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method not found"}}]`,
+			expectedClass:        types.ResponseClassUserError,
+			expectedJSONRPCError: -32601,
+		},
+		{
+			desc:                 "user error code with a node not synced message",
+			statusCode:           http.StatusOK,
+			body:                 `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid block number: block not found"}}`,
+			expectedClass:        types.ResponseClassUserError,
+			expectedJSONRPCError: -32602,
+		},
+		{
+			desc:                 "user error code with a rate limit message",
+			statusCode:           http.StatusOK,
+			body:                 `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"batch limit exceeded"}}`,
+			expectedClass:        types.ResponseClassUserError,
+			expectedJSONRPCError: -32600,
+		},
+		{
+			desc:          "empty batch response",
+			statusCode:    http.StatusOK,
+			body:          `[]`,
+			expectedClass: types.ResponseClassUserError,
+		},
+		{
+			desc:                "non JSON-RPC error body",
+			statusCode:          http.StatusBadGateway,
+			body:                "<html>Bad Gateway</html>",
+			expectedClass:       types.ResponseClassHTTPError,
+			expectSupplierFault: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			classification := types.ClassifyJSONRPCResponse(&types.POKTHTTPResponse{
+				StatusCode: test.statusCode,
+				BodyBz:     []byte(test.body),
+			})
+
+			require.Equal(t, test.expectedClass, classification.Class, classification.Class.String())
+			require.Equal(t, test.expectSupplierFault, classification.Class.IsSupplierFault())
+			require.Equal(t, test.expectedJSONRPCError, classification.JSONRPCErrorCode)
+			require.Equal(t, test.statusCode, classification.StatusCode)
+		})
+	}
+}
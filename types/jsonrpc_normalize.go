@@ -0,0 +1,65 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CanonicalizeJSONRPCPayload returns the canonical form of the given JSON-RPC
+// payload: object keys are sorted, insignificant whitespace is removed, and
+// the "id" field is stripped.
+// Batch payloads are supported, each request of the batch being canonicalized.
+//
+// Two requests which only differ by their formatting or their id have the same
+// canonical form, which makes it suitable as a cache or deduplication key, or
+// for comparing payloads.
+func CanonicalizeJSONRPCPayload(payloadBz []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payloadBz))
+	// Preserve numbers as-is, e.g. to avoid float rounding of large integers.
+	decoder.UseNumber()
+
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("CanonicalizeJSONRPCPayload: error decoding payload: %w", err)
+	}
+	if decoder.More() {
+		return nil, errors.New("CanonicalizeJSONRPCPayload: unexpected data after the payload")
+	}
+
+	switch p := payload.(type) {
+	case map[string]interface{}:
+		delete(p, "id")
+	case []interface{}:
+		for _, request := range p {
+			if requestObj, ok := request.(map[string]interface{}); ok {
+				delete(requestObj, "id")
+			}
+		}
+	default:
+		return nil, fmt.Errorf("CanonicalizeJSONRPCPayload: payload must be a JSON object or array, got %T", payload)
+	}
+
+	// encoding/json marshals map keys in sorted order, without whitespace.
+	canonicalBz, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("CanonicalizeJSONRPCPayload: error encoding payload: %w", err)
+	}
+
+	return canonicalBz, nil
+}
+
+// HashJSONRPCPayload returns the hex-encoded SHA-256 hash of the canonical form
+// of the given JSON-RPC payload, as returned by CanonicalizeJSONRPCPayload.
+func HashJSONRPCPayload(payloadBz []byte) (string, error) {
+	canonicalBz, err := CanonicalizeJSONRPCPayload(payloadBz)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(canonicalBz)
+	return hex.EncodeToString(hash[:]), nil
+}
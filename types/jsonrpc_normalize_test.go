@@ -0,0 +1,63 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestCanonicalizeJSONRPCPayload(t *testing.T) {
+	tests := []struct {
+		desc              string
+		payload           string
+		expectedCanonical string
+		expectErr         bool
+	}{
+		{
+			desc:              "keys sorted, whitespace trimmed and id stripped",
+			payload:           "{ \"method\": \"eth_getBalance\",\n \"id\": 42, \"jsonrpc\": \"2.0\", \"params\": [\"0xabc\", \"latest\"] }",
+			expectedCanonical: `{"jsonrpc":"2.0","method":"eth_getBalance","params":["0xabc","latest"]}`,
+		},
+		{
+			desc:              "large numbers preserved",
+			payload:           `{"jsonrpc":"2.0","id":1,"method":"m","params":[123456789012345678901234567890]}`,
+			expectedCanonical: `{"jsonrpc":"2.0","method":"m","params":[123456789012345678901234567890]}`,
+		},
+		{
+			desc:              "batch payload",
+			payload:           `[{"id":1,"method":"a","jsonrpc":"2.0"},{"id":2,"method":"b","jsonrpc":"2.0"}]`,
+			expectedCanonical: `[{"jsonrpc":"2.0","method":"a"},{"jsonrpc":"2.0","method":"b"}]`,
+		},
+		{desc: "invalid JSON", payload: `{"method":`, expectErr: true},
+		{desc: "scalar payload", payload: `"method"`, expectErr: true},
+		{desc: "trailing data", payload: `{"method":"a"} {"method":"b"}`, expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			canonicalBz, err := types.CanonicalizeJSONRPCPayload([]byte(test.payload))
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedCanonical, string(canonicalBz))
+		})
+	}
+}
+
+func TestHashJSONRPCPayload(t *testing.T) {
+	hash1, err := types.HashJSONRPCPayload([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`))
+	require.NoError(t, err)
+
+	hash2, err := types.HashJSONRPCPayload([]byte(`{ "method": "eth_blockNumber", "jsonrpc": "2.0", "id": 7 }`))
+	require.NoError(t, err)
+	require.Equal(t, hash1, hash2)
+
+	hash3, err := types.HashJSONRPCPayload([]byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`))
+	require.NoError(t, err)
+	require.NotEqual(t, hash1, hash3)
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// supplierSignatureSize is the size of the suppliers' secp256k1 signatures,
+// made of their 32 bytes R and S values.
+const supplierSignatureSize = 64
+
+// VerifyRelayResponse deserializes the given relay response, and verifies the
+// signature of the supplier's operator whose secp256k1 public key is given, in
+// its 33 bytes compressed form.
+//
+// It is the light counterpart of the SDK's RelayResponseValidator, for the
+// integrations which do not depend on poktroll: the public key must be fetched
+// by the caller, and the relay response is not validated beyond its signature.
+// The relay response is returned even if its signature is invalid, as it might
+// contain the reason of the supplier's failure.
+func VerifyRelayResponse(relayResponseBz []byte, supplierPubKeyBz []byte) (*RelayResponse, error) {
+	relayResponse := &RelayResponse{}
+	if err := proto.Unmarshal(relayResponseBz, relayResponse); err != nil {
+		return nil, fmt.Errorf("VerifyRelayResponse: %w: %w", sdkerrors.ErrInvalidRelayResponse, err)
+	}
+
+	supplierPubKey, err := secp256k1.ParsePubKey(supplierPubKeyBz)
+	if err != nil {
+		return relayResponse, fmt.Errorf("VerifyRelayResponse: %w: invalid public key: %w", sdkerrors.ErrInvalidSupplierSignature, err)
+	}
+
+	signatureBz := relayResponse.GetMeta().GetSupplierOperatorSignature()
+	if len(signatureBz) != supplierSignatureSize {
+		return relayResponse, fmt.Errorf(
+			"VerifyRelayResponse: %w: signature of %d bytes, expected %d",
+			sdkerrors.ErrInvalidSupplierSignature,
+			len(signatureBz),
+			supplierSignatureSize,
+		)
+	}
+	// The signatures whose S value is over half the curve order are rejected,
+	// as the suppliers only produce their lower-S form.
+	var r, s secp256k1.ModNScalar
+	r.SetByteSlice(signatureBz[:32])
+	s.SetByteSlice(signatureBz[32:])
+	if s.IsOverHalfOrder() {
+		return relayResponse, fmt.Errorf("VerifyRelayResponse: %w: signature is not in lower-S form", sdkerrors.ErrInvalidSupplierSignature)
+	}
+
+	signableBz, err := relayResponse.SignableBytesHash()
+	if err != nil {
+		return relayResponse, fmt.Errorf("VerifyRelayResponse: %w: error hashing the signable bytes: %w", sdkerrors.ErrInvalidSupplierSignature, err)
+	}
+	// The suppliers sign the SHA-256 hash of the signable bytes hash.
+	digest := sha256.Sum256(signableBz[:])
+	if !ecdsa.NewSignature(&r, &s).Verify(digest[:], supplierPubKey) {
+		return relayResponse, fmt.Errorf("VerifyRelayResponse: %w", sdkerrors.ErrInvalidSupplierSignature)
+	}
+
+	return relayResponse, nil
+}
+
+// SignableBytesHash returns the hash of the relay response's signable bytes,
+// i.e. of its serialization without the supplier's signature, as computed by
+// poktroll's RelayResponse.GetSignableBytesHash.
+func (res *RelayResponse) SignableBytesHash() ([sha256.Size]byte, error) {
+	// The metadata is always serialized, even if empty, as poktroll does.
+	signableResponse := &RelayResponse{
+		Meta:    &RelayResponseMetadata{SessionHeader: res.GetMeta().GetSessionHeader()},
+		Payload: res.GetPayload(),
+	}
+	signableResponseBz, err := proto.MarshalOptions{Deterministic: true}.Marshal(signableResponse)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(signableResponseBz), nil
+}
@@ -0,0 +1,111 @@
+package types_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestVerifyRelayResponse(t *testing.T) {
+	supplierKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	otherKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	// signedResponse returns the serialized relay response with the given
+	// payload, signed by the given key as the suppliers do.
+	signedResponse := func(key *secp256k1.PrivateKey, payload string) []byte {
+		relayResponse := &types.RelayResponse{
+			Meta: &types.RelayResponseMetadata{
+				SessionHeader: &types.SessionHeader{
+					ApplicationAddress:      "pokt1app",
+					ServiceId:               "svc1",
+					SessionId:               "session1",
+					SessionStartBlockHeight: 1,
+					SessionEndBlockHeight:   4,
+				},
+			},
+			Payload: []byte(payload),
+		}
+		signableBz, err := relayResponse.SignableBytesHash()
+		require.NoError(t, err)
+		digest := sha256.Sum256(signableBz[:])
+		// The compact signature is prefixed by its recovery code.
+		relayResponse.Meta.SupplierOperatorSignature = ecdsa.SignCompact(key, digest[:], false)[1:]
+
+		relayResponseBz, err := proto.Marshal(relayResponse)
+		require.NoError(t, err)
+		return relayResponseBz
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		relayResponse, err := types.VerifyRelayResponse(signedResponse(supplierKey, "payload"), supplierKey.PubKey().SerializeCompressed())
+		require.NoError(t, err)
+		require.Equal(t, []byte("payload"), relayResponse.GetPayload())
+		require.Equal(t, "svc1", relayResponse.GetMeta().GetSessionHeader().GetServiceId())
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		relayResponse := &types.RelayResponse{}
+		require.NoError(t, proto.Unmarshal(signedResponse(supplierKey, "payload"), relayResponse))
+		relayResponse.Payload = []byte("tampered")
+		relayResponseBz, err := proto.Marshal(relayResponse)
+		require.NoError(t, err)
+
+		_, err = types.VerifyRelayResponse(relayResponseBz, supplierKey.PubKey().SerializeCompressed())
+		require.ErrorIs(t, err, sdkerrors.ErrInvalidSupplierSignature)
+	})
+
+	tests := []struct {
+		desc            string
+		relayResponseBz []byte
+		pubKeyBz        []byte
+		expectedErr     error
+	}{
+		{
+			desc:            "signed by another supplier",
+			relayResponseBz: signedResponse(otherKey, "payload"),
+			pubKeyBz:        supplierKey.PubKey().SerializeCompressed(),
+			expectedErr:     sdkerrors.ErrInvalidSupplierSignature,
+		},
+		{
+			desc:            "unsigned",
+			relayResponseBz: mustMarshal(t, &types.RelayResponse{Payload: []byte("payload")}),
+			pubKeyBz:        supplierKey.PubKey().SerializeCompressed(),
+			expectedErr:     sdkerrors.ErrInvalidSupplierSignature,
+		},
+		{
+			desc:            "invalid public key",
+			relayResponseBz: signedResponse(supplierKey, "payload"),
+			pubKeyBz:        []byte("not a public key"),
+			expectedErr:     sdkerrors.ErrInvalidSupplierSignature,
+		},
+		{
+			desc:            "undecodable relay response",
+			relayResponseBz: []byte("not a relay response"),
+			pubKeyBz:        supplierKey.PubKey().SerializeCompressed(),
+			expectedErr:     sdkerrors.ErrInvalidRelayResponse,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := types.VerifyRelayResponse(test.relayResponseBz, test.pubKeyBz)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, message proto.Message) []byte {
+	t.Helper()
+	messageBz, err := proto.Marshal(message)
+	require.NoError(t, err)
+	return messageBz
+}
@@ -18,29 +18,7 @@ func SerializeHTTPRequest(
 		return nil, nil, err
 	}
 
-	headers := map[string]*Header{}
-	for key := range request.Header {
-		headerValues := request.Header.Values(key)
-		headers[key] = &Header{
-			Key:    key,
-			Values: headerValues,
-		}
-	}
-
-	poktHTTPRequest = &POKTHTTPRequest{
-		Method: request.Method,
-		Header: headers,
-		Url:    request.URL.String(),
-		BodyBz: requestBodyBz,
-	}
-
-	// Use deterministic marshalling to ensure that the serialized request is
-	// byte-for-byte equal when comparing the serialized request.
-	opts := proto.MarshalOptions{Deterministic: true}
-
-	poktHTTPRequestBz, err = opts.Marshal(poktHTTPRequest)
-
-	return poktHTTPRequest, poktHTTPRequestBz, err
+	return BuildHTTPRequest(request.Method, request.URL.String(), request.Header, requestBodyBz)
 }
 
 // DeserializeHTTPRequest takes a byte slice and deserializes it into a
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// contentTypeHeaderValueGRPCWeb is the content type of gRPC-web requests
+	// carrying binary protobuf frames.
+	contentTypeHeaderValueGRPCWeb = "application/grpc-web+proto"
+)
+
+// BuildHTTPRequest builds a POKTHTTPRequest from the given method, URL, header
+// and body, and serializes it into a byte slice that can be embedded into
+// another struct, such as RelayRequest.Payload.
+//
+// It allows gateways with non-HTTP ingress, e.g. message queues or custom TCP
+// protocols, to build the same serialized requests as SerializeHTTPRequest.
+func BuildHTTPRequest(
+	method string,
+	url string,
+	header http.Header,
+	body []byte,
+) (poktHTTPRequest *POKTHTTPRequest, poktHTTPRequestBz []byte, err error) {
+	headers := map[string]*Header{}
+	for key := range header {
+		headerValues := header.Values(key)
+		headers[key] = &Header{
+			Key:    key,
+			Values: headerValues,
+		}
+	}
+
+	poktHTTPRequest = &POKTHTTPRequest{
+		Method: method,
+		Header: headers,
+		Url:    url,
+		BodyBz: body,
+	}
+
+	// Use deterministic marshalling to ensure that the serialized request is
+	// byte-for-byte equal when comparing the serialized request.
+	opts := proto.MarshalOptions{Deterministic: true}
+
+	poktHTTPRequestBz, err = opts.Marshal(poktHTTPRequest)
+
+	return poktHTTPRequest, poktHTTPRequestBz, err
+}
+
+// BuildJSONRPCRequest builds and serializes a POKTHTTPRequest carrying the given
+// raw JSON-RPC payload, e.g. a JSON-RPC message received over a WebSocket
+// connection or a message queue.
+// The request is detected as a JSON-RPC request by GetRPCType if the payload
+// is a valid JSON-RPC request.
+func BuildJSONRPCRequest(payload []byte) (*POKTHTTPRequest, []byte, error) {
+	if !json.Valid(payload) {
+		return nil, nil, errors.New("BuildJSONRPCRequest: payload is not valid JSON")
+	}
+
+	header := http.Header{}
+	header.Set(contentTypeHeaderKey, contentTypeHeaderValueJSON)
+
+	return BuildHTTPRequest(http.MethodPost, "", header, payload)
+}
+
+// BuildGRPCWebRequest builds and serializes a POKTHTTPRequest carrying the given
+// gRPC-web frames, sent to the given fully-qualified gRPC method, e.g.
+// "/cosmos.bank.v1beta1.Query/Balance".
+func BuildGRPCWebRequest(fullMethod string, frames []byte) (*POKTHTTPRequest, []byte, error) {
+	if !strings.HasPrefix(fullMethod, "/") || strings.Count(fullMethod, "/") != 2 {
+		return nil, nil, errors.New(`BuildGRPCWebRequest: method must be of the form "/package.Service/Method"`)
+	}
+
+	header := http.Header{}
+	header.Set(contentTypeHeaderKey, contentTypeHeaderValueGRPCWeb)
+	header.Set("X-Grpc-Web", "1")
+
+	return BuildHTTPRequest(http.MethodPost, fullMethod, header, frames)
+}
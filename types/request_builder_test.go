@@ -0,0 +1,59 @@
+package types_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestBuildJSONRPCRequest(t *testing.T) {
+	payload := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+
+	poktReq, poktReqBz, err := types.BuildJSONRPCRequest(payload)
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, poktReq.Method)
+	require.Equal(t, payload, poktReq.BodyBz)
+	require.Equal(t, types.RPCTypeJSONRPC, poktReq.GetRPCType())
+
+	deserializedReq, err := types.DeserializeHTTPRequest(poktReqBz)
+	require.NoError(t, err)
+	require.Equal(t, []string{contentTypeHeaderValueJSON}, deserializedReq.Header[contentTypeHeaderKey].Values)
+
+	_, _, err = types.BuildJSONRPCRequest([]byte(`{"jsonrpc":`))
+	require.Error(t, err)
+}
+
+func TestBuildGRPCWebRequest(t *testing.T) {
+	frames := []byte{0x00, 0x00, 0x00, 0x00, 0x02, 0x0a, 0x00}
+
+	poktReq, _, err := types.BuildGRPCWebRequest("/cosmos.bank.v1beta1.Query/Balance", frames)
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, poktReq.Method)
+	require.Equal(t, "/cosmos.bank.v1beta1.Query/Balance", poktReq.Url)
+	require.Equal(t, []string{"application/grpc-web+proto"}, poktReq.Header[contentTypeHeaderKey].Values)
+	require.Equal(t, frames, poktReq.BodyBz)
+
+	_, _, err = types.BuildGRPCWebRequest("Balance", frames)
+	require.Error(t, err)
+}
+
+func TestBuildHTTPRequest_MatchesSerializeHTTPRequest(t *testing.T) {
+	header := http.Header{}
+	header.Set(contentTypeHeaderKey, contentTypeHeaderValueJSON)
+	header.Add(arbitraryHeaderKey, arbitraryHeaderFirstValue)
+
+	req, err := http.NewRequest(requestMethod, contentUrl, nil)
+	require.NoError(t, err)
+	req.Header = header.Clone()
+	req.Body = http.NoBody
+
+	_, serializedBz, err := types.SerializeHTTPRequest(req)
+	require.NoError(t, err)
+
+	_, builtBz, err := types.BuildHTTPRequest(requestMethod, contentUrl, header, nil)
+	require.NoError(t, err)
+	require.Equal(t, serializedBz, builtBz)
+}
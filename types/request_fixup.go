@@ -0,0 +1,121 @@
+package types
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// hopByHopHeaders are the headers which only apply to a single HTTP connection,
+// and must not be forwarded to the backends.
+// See: https://www.rfc-editor.org/rfc/rfc9110#section-7.6.1
+var hopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// supportedContentEncodings are the response content encodings which can be
+// decoded by the SDK's consumers, i.e. by the net/http package.
+var supportedContentEncodings = []string{"gzip", "identity"}
+
+// gzipMagic is the header of gzip-compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// FixFramingHeaders makes the headers of the request consistent with its body,
+// after the body was modified, e.g. by a RequestTransform, so backends do not
+// reject the relayed request:
+//   - Content-Length is set to the length of the body, or removed if the body is empty.
+//   - Content-Encoding is removed if it claims a gzip-compressed body which is not.
+//   - Hop-by-hop headers, including the ones listed by the Connection header, are removed,
+//     except "TE: trailers" for gRPC requests, which gRPC servers require.
+//   - Accept-Encoding is restricted to the encodings the net/http package can decode,
+//     except for gRPC requests, whose responses are not decoded by the net/http package.
+func (poktRequest *POKTHTTPRequest) FixFramingHeaders() {
+	if poktRequest.Header == nil {
+		poktRequest.Header = map[string]*Header{}
+	}
+
+	isGRPC := poktRequest.isGRPC()
+	keepTETrailers := isGRPC && slices.ContainsFunc(poktRequest.headerValues("TE"), func(value string) bool {
+		return strings.EqualFold(strings.TrimSpace(value), "trailers")
+	})
+
+	connectionHeaders := poktRequest.headerValues("Connection")
+	for _, key := range hopByHopHeaders {
+		poktRequest.deleteHeader(key)
+	}
+	for _, value := range connectionHeaders {
+		for _, key := range strings.Split(value, ",") {
+			poktRequest.deleteHeader(strings.TrimSpace(key))
+		}
+	}
+	if keepTETrailers {
+		poktRequest.setHeader("TE", "trailers")
+	}
+
+	poktRequest.deleteHeader("Content-Length")
+	if len(poktRequest.BodyBz) > 0 {
+		poktRequest.setHeader("Content-Length", strconv.Itoa(len(poktRequest.BodyBz)))
+	}
+
+	contentEncoding := strings.ToLower(strings.Join(poktRequest.headerValues("Content-Encoding"), ","))
+	if strings.Contains(contentEncoding, "gzip") && !bytes.HasPrefix(poktRequest.BodyBz, gzipMagic) {
+		poktRequest.deleteHeader("Content-Encoding")
+	}
+
+	if acceptEncodings := poktRequest.headerValues("Accept-Encoding"); len(acceptEncodings) > 0 && !isGRPC {
+		poktRequest.deleteHeader("Accept-Encoding")
+
+		var supported []string
+		for _, value := range acceptEncodings {
+			for _, encoding := range strings.Split(value, ",") {
+				encoding = strings.TrimSpace(encoding)
+				name, _, _ := strings.Cut(encoding, ";")
+				for _, supportedEncoding := range supportedContentEncodings {
+					if strings.EqualFold(strings.TrimSpace(name), supportedEncoding) {
+						supported = append(supported, encoding)
+					}
+				}
+			}
+		}
+		if len(supported) > 0 {
+			poktRequest.setHeader("Accept-Encoding", strings.Join(supported, ", "))
+		}
+	}
+}
+
+// headerValues returns the values of the given header, matching its key
+// case-insensitively.
+func (poktRequest *POKTHTTPRequest) headerValues(key string) []string {
+	var values []string
+	for headerKey, header := range poktRequest.Header {
+		if strings.EqualFold(headerKey, key) {
+			values = append(values, header.GetValues()...)
+		}
+	}
+	return values
+}
+
+// deleteHeader removes the given header, matching its key case-insensitively.
+func (poktRequest *POKTHTTPRequest) deleteHeader(key string) {
+	for headerKey := range poktRequest.Header {
+		if strings.EqualFold(headerKey, key) {
+			delete(poktRequest.Header, headerKey)
+		}
+	}
+}
+
+// setHeader sets the given header, using its canonical key.
+func (poktRequest *POKTHTTPRequest) setHeader(key, value string) {
+	key = http.CanonicalHeaderKey(key)
+	poktRequest.Header[key] = &Header{Key: key, Values: []string{value}}
+}
@@ -0,0 +1,102 @@
+package types_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestPOKTHTTPRequest_FixFramingHeaders(t *testing.T) {
+	tests := []struct {
+		desc           string
+		header         http.Header
+		body           []byte
+		expectedHeader http.Header
+	}{
+		{
+			desc:           "Content-Length is recomputed",
+			header:         http.Header{"Content-Length": {"100"}, "Content-Type": {"application/json"}},
+			body:           []byte(`{"id":1}`),
+			expectedHeader: http.Header{"Content-Length": {"8"}, "Content-Type": {"application/json"}},
+		},
+		{
+			desc:           "Content-Length is removed for empty bodies",
+			header:         http.Header{"content-length": {"100"}},
+			expectedHeader: http.Header{},
+		},
+		{
+			desc: "hop-by-hop headers are removed",
+			header: http.Header{
+				"Connection":        {"keep-alive, X-Session-Token"},
+				"Keep-Alive":        {"timeout=5"},
+				"Transfer-Encoding": {"chunked"},
+				"X-Session-Token":   {"secret"},
+				"X-Api-Key":         {"key"},
+			},
+			expectedHeader: http.Header{"X-Api-Key": {"key"}},
+		},
+		{
+			desc:           "gzip Content-Encoding of a plain body is removed",
+			header:         http.Header{"Content-Encoding": {"gzip"}},
+			body:           []byte(`{}`),
+			expectedHeader: http.Header{"Content-Length": {"2"}},
+		},
+		{
+			desc:           "gzip Content-Encoding of a gzip body is kept",
+			header:         http.Header{"Content-Encoding": {"gzip"}},
+			body:           []byte{0x1f, 0x8b, 0x08},
+			expectedHeader: http.Header{"Content-Encoding": {"gzip"}, "Content-Length": {"3"}},
+		},
+		{
+			desc:           "unsupported Accept-Encoding values are removed",
+			header:         http.Header{"Accept-Encoding": {"br, gzip;q=0.8", "zstd"}},
+			expectedHeader: http.Header{"Accept-Encoding": {"gzip;q=0.8"}},
+		},
+		{
+			desc:           "Accept-Encoding without supported values is removed",
+			header:         http.Header{"Accept-Encoding": {"br"}},
+			expectedHeader: http.Header{},
+		},
+		{
+			desc: "TE: trailers and Accept-Encoding of gRPC requests are kept",
+			header: http.Header{
+				"Content-Type":    {"application/grpc"},
+				"Te":              {"trailers"},
+				"Accept-Encoding": {"identity, deflate"},
+				"Connection":      {"keep-alive"},
+			},
+			body: []byte{0, 0, 0, 0, 0},
+			expectedHeader: http.Header{
+				"Content-Type":    {"application/grpc"},
+				"Te":              {"trailers"},
+				"Accept-Encoding": {"identity, deflate"},
+				"Content-Length":  {"5"},
+			},
+		},
+		{
+			desc:           "TE of non-gRPC requests is removed",
+			header:         http.Header{"Te": {"trailers"}},
+			expectedHeader: http.Header{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			poktRequest := &types.POKTHTTPRequest{BodyBz: test.body, Header: map[string]*types.Header{}}
+			for key, values := range test.header {
+				poktRequest.Header[key] = &types.Header{Key: key, Values: values}
+			}
+
+			poktRequest.FixFramingHeaders()
+
+			header := http.Header{}
+			for key, h := range poktRequest.Header {
+				header[key] = h.Values
+			}
+			require.Equal(t, test.expectedHeader, header)
+		})
+	}
+}
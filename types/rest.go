@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"slices"
@@ -35,15 +36,16 @@ func (poktRequest *POKTHTTPRequest) isREST() bool {
 }
 
 // formatRESTError formats the given error into a POKTHTTPResponse and its
-// corresponding byte representation.
+// corresponding byte representation, using the given config.
 func (poktRequest *POKTHTTPRequest) formatRESTError(
 	err error,
 	isInternal bool,
+	config ErrorFormatConfig,
 ) (*POKTHTTPResponse, []byte) {
 	errorMsg := err.Error()
 	statusCode := http.StatusBadRequest
 	if isInternal {
-		errorMsg = defaultErrorMessage
+		errorMsg = config.InternalErrorMessage
 		statusCode = http.StatusInternalServerError
 	}
 
@@ -54,7 +56,18 @@ func (poktRequest *POKTHTTPRequest) formatRESTError(
 
 	responseBodyBz := []byte(errorMsg)
 	if slices.Contains(contentTypeHeaderValues, contentTypeHeaderValueJSON) {
-		responseBodyBz = []byte(fmt.Sprintf(`{"error": "%s"}`, errorMsg))
+		errorBody := map[string]string{"error": errorMsg}
+		if config.SupportURL != "" {
+			errorBody["support_url"] = config.SupportURL
+		}
+		// The body is JSON-encoded so any error message, e.g. with quotes, is valid.
+		errorBodyBz, err := json.Marshal(errorBody)
+		if err != nil {
+			return defaultRESTErrorReply, defaultRESTErrorReplyBz
+		}
+		responseBodyBz = errorBodyBz
+	} else if config.SupportURL != "" {
+		responseBodyBz = []byte(fmt.Sprintf("%s (support: %s)", errorMsg, config.SupportURL))
 	}
 
 	header := &Header{
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"testing"
 
-	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
 	"github.com/stretchr/testify/require"
 
 	"github.com/pokt-network/shannon-sdk/types"
@@ -25,7 +24,7 @@ func TestRPCType_DetectRPC(t *testing.T) {
 	tests := []struct {
 		desc            string
 		inputRequest    *types.POKTHTTPRequest
-		expectedRPCType sharedtypes.RPCType
+		expectedRPCType types.RPCType
 	}{
 		{
 			desc: "Detect JSON-RPC",
@@ -40,7 +39,7 @@ func TestRPCType_DetectRPC(t *testing.T) {
 				Url:    requestUrl,
 				BodyBz: jsonRPCContentBz,
 			},
-			expectedRPCType: sharedtypes.RPCType_JSON_RPC,
+			expectedRPCType: types.RPCTypeJSONRPC,
 		},
 		{
 			desc: "Detect REST",
@@ -55,7 +54,62 @@ func TestRPCType_DetectRPC(t *testing.T) {
 				Url:    requestUrl,
 				BodyBz: restContentBz,
 			},
-			expectedRPCType: sharedtypes.RPCType_REST,
+			expectedRPCType: types.RPCTypeREST,
+		},
+		{
+			desc: "Detect gRPC",
+			inputRequest: &types.POKTHTTPRequest{
+				Header: map[string]*types.Header{
+					contentTypeHeaderKey: {
+						Key:    contentTypeHeaderKey,
+						Values: []string{"application/grpc+proto"},
+					},
+				},
+				Method: method,
+				Url:    requestUrl + "/cosmos.bank.v1beta1.Query/Balance",
+			},
+			expectedRPCType: types.RPCTypeGRPC,
+		},
+		{
+			desc: "Detect Connect streaming",
+			inputRequest: &types.POKTHTTPRequest{
+				Header: map[string]*types.Header{
+					contentTypeHeaderKey: {
+						Key:    contentTypeHeaderKey,
+						Values: []string{"application/connect+proto"},
+					},
+				},
+				Method: method,
+				Url:    requestUrl + "/cosmos.bank.v1beta1.Query/Balance",
+			},
+			expectedRPCType: types.RPCTypeGRPC,
+		},
+		{
+			desc: "Detect Connect unary",
+			inputRequest: &types.POKTHTTPRequest{
+				Header: map[string]*types.Header{
+					contentTypeHeaderKey: {
+						Key:    contentTypeHeaderKey,
+						Values: []string{contentTypeHeaderValueJSON},
+					},
+					"Connect-Protocol-Version": {
+						Key:    "Connect-Protocol-Version",
+						Values: []string{"1"},
+					},
+				},
+				Method: method,
+				Url:    requestUrl + "/cosmos.bank.v1beta1.Query/Balance",
+				BodyBz: restContentBz,
+			},
+			expectedRPCType: types.RPCTypeGRPC,
+		},
+		{
+			desc: "Detect Connect unary GET",
+			inputRequest: &types.POKTHTTPRequest{
+				Method: "GET",
+				Url:    requestUrl + "/cosmos.bank.v1beta1.Query/Balance?connect=v1&encoding=json",
+			},
+			expectedRPCType: types.RPCTypeGRPC,
 		},
 		{
 			desc: "Unknown RPC",
@@ -70,7 +124,7 @@ func TestRPCType_DetectRPC(t *testing.T) {
 				Url:    "",
 				BodyBz: restContentBz,
 			},
-			expectedRPCType: sharedtypes.RPCType_UNKNOWN_RPC,
+			expectedRPCType: types.RPCTypeUnknown,
 		},
 	}
 
@@ -220,3 +274,59 @@ func TestRPCType_FormatError(t *testing.T) {
 		})
 	}
 }
+
+func TestRPCType_GetJSONRPCMethod(t *testing.T) {
+	jsonRPCRequest := &types.POKTHTTPRequest{
+		Header: map[string]*types.Header{
+			contentTypeHeaderKey: {
+				Key:    contentTypeHeaderKey,
+				Values: []string{contentTypeHeaderValueJSON},
+			},
+		},
+		Method: method,
+		Url:    requestUrl,
+		BodyBz: jsonRPCContentBz,
+	}
+	jsonRPCMethod, isJSONRPC := jsonRPCRequest.GetJSONRPCMethod()
+	require.True(t, isJSONRPC)
+	require.Equal(t, "m", jsonRPCMethod)
+
+	restRequest := &types.POKTHTTPRequest{
+		Method: method,
+		Url:    requestUrl,
+		BodyBz: restContentBz,
+	}
+	_, isJSONRPC = restRequest.GetJSONRPCMethod()
+	require.False(t, isJSONRPC)
+}
+
+func TestRPCType_GetJSONRPCMethods(t *testing.T) {
+	tests := []struct {
+		desc            string
+		body            string
+		expectedMethods []string
+	}{
+		{desc: "single call", body: `{"jsonrpc":"2.0","method":"eth_call","id":1}`, expectedMethods: []string{"eth_call"}},
+		{desc: "string id", body: `{"jsonrpc":"2.0","method":"eth_call","id":"a"}`, expectedMethods: []string{"eth_call"}},
+		{desc: "zero id", body: ` {"jsonrpc":"2.0","method":"eth_call","id":0}`, expectedMethods: []string{"eth_call"}},
+		{
+			desc:            "batch",
+			body:            `[{"jsonrpc":"2.0","method":"eth_call","id":1},{"jsonrpc":"2.0","method":"eth_sendRawTransaction","id":2}]`,
+			expectedMethods: []string{"eth_call", "eth_sendRawTransaction"},
+		},
+		{desc: "empty batch", body: `[]`},
+		{desc: "batch call without method", body: `[{"jsonrpc":"2.0","method":"eth_call","id":1},{"id":2}]`},
+		{desc: "non-string method", body: `{"jsonrpc":"2.0","method":1,"id":1}`},
+		{desc: "not JSON", body: `eth_call`},
+		{desc: "empty body"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			request := &types.POKTHTTPRequest{Method: method, Url: requestUrl, BodyBz: []byte(test.body)}
+			methods, ok := request.GetJSONRPCMethods()
+			require.Equal(t, test.expectedMethods != nil, ok)
+			require.Equal(t, test.expectedMethods, methods)
+		})
+	}
+}
@@ -3,7 +3,6 @@ package types
 import (
 	"net/http"
 
-	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -28,33 +27,41 @@ func init() {
 }
 
 // GetRPCType returns the RPC type of a POKTHTTPRequest.
-func (poktRequest *POKTHTTPRequest) GetRPCType() sharedtypes.RPCType {
+// The requests to gRPC services, using the gRPC, gRPC-web or Connect protocol,
+// are detected as RPCTypeGRPC.
+func (poktRequest *POKTHTTPRequest) GetRPCType() RPCType {
+	if poktRequest.isGRPC() {
+		return RPCTypeGRPC
+	}
 	if poktRequest.isJSONRPC() {
-		return sharedtypes.RPCType_JSON_RPC
+		return RPCTypeJSONRPC
 	}
 	if poktRequest.isREST() {
-		return sharedtypes.RPCType_REST
+		return RPCTypeREST
 	}
 
-	return sharedtypes.RPCType_UNKNOWN_RPC
+	return RPCTypeUnknown
 }
 
 // FormatError formats the given error into a POKTHTTPResponse and its
-// corresponding byte representation.
+// corresponding byte representation, using the default error messages.
+// Use an ErrorFormatter to customize the error responses.
 func (request *POKTHTTPRequest) FormatError(
 	err error,
 	isInternal bool,
 ) (*POKTHTTPResponse, []byte) {
-	rpcType := request.GetRPCType()
+	return defaultErrorFormatter.FormatError(request, err, isInternal)
+}
 
-	switch rpcType {
-	case sharedtypes.RPCType_JSON_RPC:
-		return request.formatJSONRPCError(err, isInternal)
-	case sharedtypes.RPCType_REST:
-		return request.formatRESTError(err, isInternal)
-	default:
-		return unsupportedRPCTypeErrorReply, unsupportedRPCTypeErrorReplyBz
-	}
+// FormatErrorWithStatus formats the given error, reported with the given HTTP
+// status code, into a POKTHTTPResponse and its corresponding byte
+// representation, using the default error messages.
+// See ErrorFormatter.FormatErrorWithStatus.
+func (request *POKTHTTPRequest) FormatErrorWithStatus(
+	err error,
+	statusCode int,
+) (*POKTHTTPResponse, []byte) {
+	return defaultErrorFormatter.FormatErrorWithStatus(request, err, statusCode)
 }
 
 // initDefaultUnsupportedRPCTypeErrorReply initializes the unsupported RPC type error reply.
@@ -0,0 +1,33 @@
+package types
+
+// RPCType is the RPC type of a request.
+//
+// It mirrors the values of the shared module's RPCType, without depending on
+// poktroll, i.e. a shared module's RPCType converts to the same RPCType.
+type RPCType int32
+
+// The RPC types detected by POKTHTTPRequest.GetRPCType.
+// The values match the ones of the shared module's RPCType.
+const (
+	RPCTypeUnknown   RPCType = 0
+	RPCTypeGRPC      RPCType = 1
+	RPCTypeWebSocket RPCType = 2
+	RPCTypeJSONRPC   RPCType = 3
+	RPCTypeREST      RPCType = 4
+)
+
+// String returns the name of the RPC type, as defined by the shared module.
+func (t RPCType) String() string {
+	switch t {
+	case RPCTypeGRPC:
+		return "GRPC"
+	case RPCTypeWebSocket:
+		return "WEBSOCKET"
+	case RPCTypeJSONRPC:
+		return "JSON_RPC"
+	case RPCTypeREST:
+		return "REST"
+	default:
+		return "UNKNOWN_RPC"
+	}
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"bytes"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// wireFixtures holds the expected hex-encoded serialization of the wire format
+// fixtures, keyed by file name.
+// The fixtures must never change: a change indicates that the SDK's encoding of
+// POKTHTTPRequest/POKTHTTPResponse is no longer compatible with previous versions.
+//
+//go:embed wire_fixtures/*.hex
+var wireFixtures embed.FS
+
+// wireFixtureMessages returns the messages of the wire format fixtures, keyed by
+// the name of the file holding their expected serialization.
+func wireFixtureMessages() map[string]proto.Message {
+	return map[string]proto.Message{
+		"request_jsonrpc.hex": &POKTHTTPRequest{
+			Method: http.MethodPost,
+			Header: map[string]*Header{
+				"Content-Type": {Key: "Content-Type", Values: []string{"application/json"}},
+			},
+			BodyBz: []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`),
+		},
+		"request_rest.hex": &POKTHTTPRequest{
+			Method: http.MethodGet,
+			Header: map[string]*Header{
+				"X-Multi": {Key: "X-Multi", Values: []string{"a", "b"}},
+				"Accept":  {Key: "Accept", Values: []string{"application/json"}},
+			},
+			Url: "/v1/blocks/latest?format=json",
+		},
+		"response_ok.hex": &POKTHTTPResponse{
+			StatusCode: http.StatusOK,
+			Header: map[string]*Header{
+				"Content-Type": {Key: "Content-Type", Values: []string{"application/json"}},
+			},
+			BodyBz: []byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`),
+		},
+		"response_error.hex": &POKTHTTPResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			BodyBz:     []byte("unavailable"),
+		},
+	}
+}
+
+// VerifyWireCompatibility verifies that the deterministic serialization of
+// POKTHTTPRequest and POKTHTTPResponse, as used by SerializeHTTPRequest and
+// SerializeHTTPResponse, matches the fixtures checked in with the SDK, and that
+// the fixtures deserialize into the expected messages.
+//
+// Gateways and RelayMiners can call it, e.g. in their own tests or on startup,
+// to detect a silent drift of the payload encoding, e.g. caused by a protobuf
+// library upgrade.
+func VerifyWireCompatibility() error {
+	var errs []error
+	for name, message := range wireFixtureMessages() {
+		if err := verifyWireFixture(name, message); err != nil {
+			errs = append(errs, fmt.Errorf("fixture %s: %w", name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("VerifyWireCompatibility: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// verifyWireFixture verifies the serialization of the given message against the
+// fixture with the given name, in both directions.
+func verifyWireFixture(name string, message proto.Message) error {
+	fixtureHex, err := wireFixtures.ReadFile("wire_fixtures/" + name)
+	if err != nil {
+		return err
+	}
+
+	expectedBz, err := hex.DecodeString(strings.TrimSpace(string(fixtureHex)))
+	if err != nil {
+		return fmt.Errorf("error decoding fixture: %w", err)
+	}
+
+	actualBz, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("error serializing message: %w", err)
+	}
+	if !bytes.Equal(expectedBz, actualBz) {
+		return fmt.Errorf("serialization mismatch: expected %x, got %x", expectedBz, actualBz)
+	}
+
+	decoded := message.ProtoReflect().New().Interface()
+	if err := proto.Unmarshal(expectedBz, decoded); err != nil {
+		return fmt.Errorf("error deserializing fixture: %w", err)
+	}
+	if !proto.Equal(message, decoded) {
+		return errors.New("deserialized fixture does not match the expected message")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWireCompatibility(t *testing.T) {
+	require.NoError(t, VerifyWireCompatibility())
+}
+
+func TestWireFixtures_Complete(t *testing.T) {
+	fixtureFiles, err := wireFixtures.ReadDir("wire_fixtures")
+	require.NoError(t, err)
+
+	// Every checked-in fixture must be verified.
+	messages := wireFixtureMessages()
+	require.Len(t, fixtureFiles, len(messages))
+	for _, fixtureFile := range fixtureFiles {
+		require.Contains(t, messages, fixtureFile.Name())
+	}
+}
+
+func TestSerializationHelpers_WireFixtures(t *testing.T) {
+	// The public serialization helpers must produce the fixtures, not only proto.Marshal.
+	header := http.Header{}
+	header.Add("Accept", "application/json")
+	header.Add("X-Multi", "a")
+	header.Add("X-Multi", "b")
+	_, requestBz, err := BuildHTTPRequest(http.MethodGet, "/v1/blocks/latest?format=json", header, nil)
+	require.NoError(t, err)
+	require.Equal(t, readWireFixture(t, "request_rest.hex"), requestBz)
+
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`)),
+	}
+	_, responseBz, err := SerializeHTTPResponse(response)
+	require.NoError(t, err)
+	require.Equal(t, readWireFixture(t, "response_ok.hex"), responseBz)
+}
+
+// readWireFixture returns the decoded serialization held by the given fixture file.
+func readWireFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	fixtureHex, err := wireFixtures.ReadFile("wire_fixtures/" + name)
+	require.NoError(t, err)
+
+	fixtureBz, err := hex.DecodeString(strings.TrimSpace(string(fixtureHex)))
+	require.NoError(t, err)
+
+	return fixtureBz
+}
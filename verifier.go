@@ -0,0 +1,223 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	grpc "github.com/cosmos/gogoproto/grpc"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	"github.com/pokt-network/ring-go"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// RelayVerifier verifies archived relay request and response pairs, without
+// taking part in the relay.
+//
+// It is intended for third-party auditors and dispute tooling: it verifies both
+// the ring signature of the relay request, using the application's ring at the
+// session end height of the relay, and the supplier's signature of the relay response.
+//
+// The application is queried at the session end height of the relay, so the
+// relays of applications which have since unstaked or changed their delegations
+// are verified against the ring they were signed with. This requires the full
+// node to retain the state at that height, e.g. an archival node for old relays.
+type RelayVerifier struct {
+	ApplicationClient
+	PublicKeyFetcher
+
+	// Hasher, if set, hashes the signable bytes of the relay requests and
+	// responses. The DefaultSignableBytesHasher is used otherwise.
+	Hasher SignableBytesHasher
+}
+
+// RelayVerifierOption is a functional option used to configure a RelayVerifier.
+type RelayVerifierOption func(*RelayVerifier)
+
+// WithPublicKeyFetcher sets the PublicKeyFetcher used by the RelayVerifier,
+// e.g. to use a caching implementation, overriding the default AccountClient.
+func WithPublicKeyFetcher(publicKeyFetcher PublicKeyFetcher) RelayVerifierOption {
+	return func(v *RelayVerifier) {
+		v.PublicKeyFetcher = publicKeyFetcher
+	}
+}
+
+// WithSignableBytesHasher sets the SignableBytesHasher used by the RelayVerifier,
+// e.g. to verify relays signed using another version of the hashing scheme.
+func WithSignableBytesHasher(hasher SignableBytesHasher) RelayVerifierOption {
+	return func(v *RelayVerifier) {
+		v.Hasher = hasher
+	}
+}
+
+// NewRelayVerifier returns a RelayVerifier which fetches the onchain data
+// required to verify relays from the POKT full node at the other end of the
+// given gRPC connection, configured using the given options.
+func NewRelayVerifier(grpcConn grpc.ClientConn, opts ...RelayVerifierOption) *RelayVerifier {
+	v := &RelayVerifier{
+		ApplicationClient: ApplicationClient{QueryClient: apptypes.NewQueryClient(grpcConn)},
+		PublicKeyFetcher:  &AccountClient{PoktNodeAccountFetcher: NewPoktNodeAccountFetcher(grpcConn)},
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// RelayVerdict is the report produced by the RelayVerifier for a relay request
+// and response pair.
+type RelayVerdict struct {
+	AppAddress       string
+	ServiceId        string
+	SessionId        string
+	SessionEndHeight int64
+	SupplierAddress  SupplierAddress
+
+	// RequestErr is set if the relay request failed verification,
+	// e.g. due to an invalid ring signature.
+	RequestErr error
+	// ResponseErr is set if the relay response failed verification,
+	// e.g. due to an invalid supplier signature.
+	ResponseErr error
+}
+
+// IsValid returns true if both the relay request and the relay response passed verification.
+func (v RelayVerdict) IsValid() bool {
+	return v.RequestErr == nil && v.ResponseErr == nil
+}
+
+// Verify verifies the given serialized relay request and relay response pair.
+//
+// An error is only returned if the relay request could not be deserialized,
+// i.e. if no verdict could be reached.
+// Verification failures are reported through the returned RelayVerdict.
+func (v *RelayVerifier) Verify(
+	ctx context.Context,
+	relayRequestBz []byte,
+	relayResponseBz []byte,
+) (RelayVerdict, error) {
+	relayRequest := &servicetypes.RelayRequest{}
+	if err := relayRequest.Unmarshal(relayRequestBz); err != nil {
+		return RelayVerdict{}, fmt.Errorf("Verify: error unmarshaling relay request: %w", err)
+	}
+
+	sessionHeader := relayRequest.Meta.SessionHeader
+	if sessionHeader == nil {
+		return RelayVerdict{}, errors.New("Verify: relay request is missing the session header")
+	}
+
+	verdict := RelayVerdict{
+		AppAddress:       sessionHeader.ApplicationAddress,
+		ServiceId:        sessionHeader.ServiceId,
+		SessionId:        sessionHeader.SessionId,
+		SessionEndHeight: sessionHeader.SessionEndBlockHeight,
+		SupplierAddress:  SupplierAddress(relayRequest.Meta.SupplierOperatorAddress),
+	}
+
+	verdict.RequestErr = v.verifyRelayRequest(ctx, relayRequest)
+	verdict.ResponseErr = v.verifyRelayResponse(ctx, relayRequest, relayResponseBz)
+
+	return verdict, nil
+}
+
+// verifyRelayRequest verifies the relay request's ring signature against the
+// ring of the application at the session end height of the relay request,
+// using the application's state at that height.
+func (v *RelayVerifier) verifyRelayRequest(
+	ctx context.Context,
+	relayRequest *servicetypes.RelayRequest,
+) error {
+	if err := relayRequest.ValidateBasic(); err != nil {
+		return fmt.Errorf("relay request failed basic validation: %w", err)
+	}
+
+	sessionHeader := relayRequest.Meta.SessionHeader
+	app, err := v.ApplicationClient.GetApplication(
+		contextAtHeight(ctx, sessionHeader.SessionEndBlockHeight),
+		sessionHeader.ApplicationAddress,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"error getting application %s at height %d: %w",
+			sessionHeader.ApplicationAddress,
+			sessionHeader.SessionEndBlockHeight,
+			err,
+		)
+	}
+
+	appRing := ApplicationRing{
+		Application:      app,
+		PublicKeyFetcher: v.PublicKeyFetcher,
+	}
+	expectedRing, err := appRing.GetRing(ctx, uint64(sessionHeader.SessionEndBlockHeight))
+	if err != nil {
+		return fmt.Errorf("error getting the ring of application %s: %w", app.Address, err)
+	}
+
+	ringSig := new(ring.RingSig)
+	if err = ringSig.Deserialize(ring.Secp256k1(), relayRequest.Meta.Signature); err != nil {
+		return fmt.Errorf("error deserializing the relay request ring signature: %w", err)
+	}
+
+	// The ring of the signature must match the ring of the application at the
+	// session end height, otherwise the relay was signed by an unauthorized actor.
+	if ringSig.Ring().Size() != expectedRing.Size() || !ringSig.Ring().Equals(expectedRing) {
+		return fmt.Errorf("relay request ring does not match the ring of application %s", app.Address)
+	}
+
+	signableBz, err := signableBytesHasherOrDefault(v.Hasher).RelayRequestHash(relayRequest)
+	if err != nil {
+		return fmt.Errorf("error getting signable bytes hash from the relay request: %w", err)
+	}
+
+	if !ringSig.Verify(signableBz) {
+		return errors.New("invalid relay request ring signature")
+	}
+
+	return nil
+}
+
+// verifyRelayResponse verifies the relay response's supplier signature and
+// checks that it corresponds to the given relay request.
+func (v *RelayVerifier) verifyRelayResponse(
+	ctx context.Context,
+	relayRequest *servicetypes.RelayRequest,
+	relayResponseBz []byte,
+) error {
+	if v.PublicKeyFetcher == nil {
+		return sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "Public Key Fetcher not set")
+	}
+
+	validator := RelayResponseValidator{PublicKeyFetcher: v.PublicKeyFetcher, Hasher: v.Hasher}
+	relayResponse, err := validator.Validate(
+		ctx,
+		SupplierAddress(relayRequest.Meta.SupplierOperatorAddress),
+		relayResponseBz,
+	)
+	if err != nil {
+		return err
+	}
+
+	responseSessionId := relayResponse.Meta.SessionHeader.GetSessionId()
+	if responseSessionId != relayRequest.Meta.SessionHeader.SessionId {
+		return fmt.Errorf(
+			"relay response session id %q does not match relay request session id %q",
+			responseSessionId,
+			relayRequest.Meta.SessionHeader.SessionId,
+		)
+	}
+
+	return nil
+}
+
+// contextAtHeight returns a copy of the given context making the full node
+// serve the gRPC queries using its state at the given height.
+func contextAtHeight(ctx context.Context, height int64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatInt(height, 10))
+}
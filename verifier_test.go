@@ -0,0 +1,200 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"strconv"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	"github.com/stretchr/testify/require"
+	grpcoptions "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestRelayVerifier_Verify(t *testing.T) {
+	appKey := secp256k1.GenPrivKey()
+	gatewayKey := secp256k1.GenPrivKey()
+	otherGatewayKey := secp256k1.GenPrivKey()
+	supplierKey := secp256k1.GenPrivKey()
+	otherSupplierKey := secp256k1.GenPrivKey()
+
+	pubKeys := map[string]cryptotypes.PubKey{}
+	addressOf := func(key *secp256k1.PrivKey) string {
+		address, err := PubKeyToAddress(PoktAddressPrefix, key.PubKey())
+		require.NoError(t, err)
+		pubKeys[address] = key.PubKey()
+		return address
+	}
+	appAddress := addressOf(appKey)
+	gatewayAddress := addressOf(gatewayKey)
+	otherGatewayAddress := addressOf(otherGatewayKey)
+	supplierAddress := addressOf(supplierKey)
+	addressOf(otherSupplierKey)
+
+	header := &sessiontypes.SessionHeader{
+		ApplicationAddress:      appAddress,
+		ServiceId:               "svc1",
+		SessionId:               "session1",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   4,
+	}
+
+	// The application delegated to the gateway at the session end height of the
+	// relays, but has since redelegated to another gateway.
+	appAtSessionEnd := apptypes.Application{
+		Address:                   appAddress,
+		DelegateeGatewayAddresses: []string{gatewayAddress},
+	}
+	appQueryClient := &fakeHeightAppQueryClient{
+		appsAtHeight: map[int64]apptypes.Application{
+			header.SessionEndBlockHeight: appAtSessionEnd,
+			0: {
+				Address:                   appAddress,
+				DelegateeGatewayAddresses: []string{otherGatewayAddress},
+			},
+		},
+	}
+	pubKeyFetcher := &countingPubKeyFetcher{pubKeys: pubKeys}
+
+	// newRelayRequest returns a serialized relay request, ring signed by the
+	// given gateway key using the ring of the given application.
+	newRelayRequest := func(signingKey *secp256k1.PrivKey, app apptypes.Application, tamper bool) []byte {
+		relayRequest := &servicetypes.RelayRequest{
+			Meta: servicetypes.RelayRequestMetadata{
+				SessionHeader:           header,
+				SupplierOperatorAddress: supplierAddress,
+			},
+			Payload: []byte("request"),
+		}
+		signer := Signer{PrivateKeyHex: hex.EncodeToString(signingKey.Bytes())}
+		relayRequest, err := signer.Sign(
+			context.Background(),
+			relayRequest,
+			ApplicationRing{Application: app, PublicKeyFetcher: pubKeyFetcher},
+		)
+		require.NoError(t, err)
+		if tamper {
+			relayRequest.Payload = []byte("tampered")
+		}
+
+		relayRequestBz, err := relayRequest.Marshal()
+		require.NoError(t, err)
+		return relayRequestBz
+	}
+
+	// newRelayResponse returns a serialized relay response, signed by the given supplier key.
+	newRelayResponse := func(signingKey *secp256k1.PrivKey) []byte {
+		relayResponse := &servicetypes.RelayResponse{
+			Meta:    servicetypes.RelayResponseMetadata{SessionHeader: header},
+			Payload: []byte("response"),
+		}
+		signableBz, err := relayResponse.GetSignableBytesHash()
+		require.NoError(t, err)
+		relayResponse.Meta.SupplierOperatorSignature, err = signingKey.Sign(signableBz[:])
+		require.NoError(t, err)
+
+		relayResponseBz, err := relayResponse.Marshal()
+		require.NoError(t, err)
+		return relayResponseBz
+	}
+
+	tests := []struct {
+		desc                string
+		relayRequestBz      []byte
+		relayResponseBz     []byte
+		expectRequestErr    bool
+		expectedResponseErr error
+	}{
+		{
+			desc:            "valid relay request and response",
+			relayRequestBz:  newRelayRequest(gatewayKey, appAtSessionEnd, false),
+			relayResponseBz: newRelayResponse(supplierKey),
+		},
+		{
+			desc:             "tampered relay request",
+			relayRequestBz:   newRelayRequest(gatewayKey, appAtSessionEnd, true),
+			relayResponseBz:  newRelayResponse(supplierKey),
+			expectRequestErr: true,
+		},
+		{
+			desc:                "relay response signed by another supplier",
+			relayRequestBz:      newRelayRequest(gatewayKey, appAtSessionEnd, false),
+			relayResponseBz:     newRelayResponse(otherSupplierKey),
+			expectedResponseErr: sdkerrors.ErrInvalidSupplierSignature,
+		},
+		{
+			desc: "relay request signed using the current delegations",
+			relayRequestBz: newRelayRequest(otherGatewayKey, apptypes.Application{
+				Address:                   appAddress,
+				DelegateeGatewayAddresses: []string{otherGatewayAddress},
+			}, false),
+			relayResponseBz:  newRelayResponse(supplierKey),
+			expectRequestErr: true,
+		},
+	}
+
+	verifier := &RelayVerifier{
+		ApplicationClient: ApplicationClient{QueryClient: appQueryClient},
+		PublicKeyFetcher:  pubKeyFetcher,
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			verdict, err := verifier.Verify(context.Background(), test.relayRequestBz, test.relayResponseBz)
+			require.NoError(t, err)
+			require.Equal(t, appAddress, verdict.AppAddress)
+			require.Equal(t, SupplierAddress(supplierAddress), verdict.SupplierAddress)
+
+			if test.expectRequestErr {
+				require.Error(t, verdict.RequestErr)
+			} else {
+				require.NoError(t, verdict.RequestErr)
+			}
+			if test.expectedResponseErr != nil {
+				require.ErrorIs(t, verdict.ResponseErr, test.expectedResponseErr)
+			} else {
+				require.NoError(t, verdict.ResponseErr)
+			}
+			require.Equal(t, !test.expectRequestErr && test.expectedResponseErr == nil, verdict.IsValid())
+		})
+	}
+}
+
+// fakeHeightAppQueryClient is an application module QueryClient serving the
+// given applications at the height requested through the gRPC metadata, or at
+// height 0 if no height is requested.
+// Calling any method other than Application panics.
+type fakeHeightAppQueryClient struct {
+	apptypes.QueryClient
+	appsAtHeight map[int64]apptypes.Application
+}
+
+func (c *fakeHeightAppQueryClient) Application(
+	ctx context.Context,
+	req *apptypes.QueryGetApplicationRequest,
+	_ ...grpcoptions.CallOption,
+) (*apptypes.QueryGetApplicationResponse, error) {
+	var height int64
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if heights := md.Get(grpctypes.GRPCBlockHeightHeader); len(heights) > 0 {
+		var err error
+		if height, err = strconv.ParseInt(heights[0], 10, 64); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	app, ok := c.appsAtHeight[height]
+	if !ok || app.Address != req.Address {
+		return nil, status.Error(codes.NotFound, "application not found")
+	}
+	return &apptypes.QueryGetApplicationResponse{Application: app}, nil
+}
@@ -0,0 +1,167 @@
+package sdk
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const (
+	// sdkModulePath is the path of the SDK's Go module, used to find its version
+	// in the build info of the binary.
+	sdkModulePath = "github.com/pokt-network/shannon-sdk"
+	// poktrollModulePath is the path of the poktroll Go module, whose protocol
+	// types the SDK is compiled against.
+	poktrollModulePath = "github.com/pokt-network/poktroll"
+
+	// develVersion is the version reported when it is not recorded in the build
+	// info, e.g. in tests.
+	develVersion = "devel"
+)
+
+// gitCommit is the git commit of the SDK, which can be set at build time, e.g.
+// using -ldflags "-X github.com/pokt-network/shannon-sdk.gitCommit=<commit>".
+// If not set, it is read from the build info.
+var gitCommit string
+
+// VersionInfo describes the build of the SDK compiled in the binary, so the
+// behaviors observed by operators can be correlated with an exact SDK build,
+// e.g. in multi-service deployments running different builds.
+type VersionInfo struct {
+	// Version is the semantic version of the SDK module, or "devel" if unknown.
+	Version string `json:"version"`
+	// GitCommit is the git commit of the SDK, if known: it is read from the
+	// pseudo-version of the SDK module, or from the VCS information of the
+	// build if the SDK is the main module.
+	GitCommit string `json:"git_commit,omitempty"`
+	// PoktrollVersion is the version of the poktroll module whose protocol
+	// types the SDK is compiled against, or "devel" if unknown.
+	PoktrollVersion string `json:"poktroll_version"`
+	// GoVersion is the version of the Go toolchain which built the binary.
+	GoVersion string `json:"go_version"`
+}
+
+// String returns a one-line description of the build, e.g.
+// "shannon-sdk v0.1.0 (commit ecf74ced63cc, poktroll v0.0.8, go1.23.0)".
+func (v VersionInfo) String() string {
+	details := []string{}
+	if v.GitCommit != "" {
+		details = append(details, "commit "+v.GitCommit)
+	}
+	details = append(details, "poktroll "+v.PoktrollVersion, v.GoVersion)
+	return fmt.Sprintf("%s %s (%s)", sdkUserAgentProduct, v.Version, strings.Join(details, ", "))
+}
+
+// LogValue implements slog.LogValuer, so the build can be logged as a group,
+// e.g. slog.Info("starting gateway", "sdk", sdk.Version()).
+func (v VersionInfo) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("version", v.Version)}
+	if v.GitCommit != "" {
+		attrs = append(attrs, slog.String("git_commit", v.GitCommit))
+	}
+	attrs = append(attrs,
+		slog.String("poktroll_version", v.PoktrollVersion),
+		slog.String("go_version", v.GoVersion),
+	)
+	return slog.GroupValue(attrs...)
+}
+
+// versionInfo caches the build of the SDK, read once from the build info.
+var versionInfo = sync.OnceValue(func() VersionInfo {
+	// The build info is nil if the binary was not built with module support.
+	buildInfo, _ := debug.ReadBuildInfo()
+	info := readVersionInfo(buildInfo)
+	if gitCommit != "" {
+		info.GitCommit = gitCommit
+	}
+	return info
+})
+
+// Version returns the build of the SDK compiled in the binary, as recorded by
+// the Go toolchain.
+func Version() VersionInfo {
+	return versionInfo()
+}
+
+// SDKVersion returns the version of the SDK compiled in the binary, as recorded
+// by the Go toolchain, or "devel" if unknown, e.g. in tests.
+func SDKVersion() string {
+	return Version().Version
+}
+
+// readVersionInfo returns the build of the SDK recorded in the given build
+// info, which may be nil.
+func readVersionInfo(buildInfo *debug.BuildInfo) VersionInfo {
+	info := VersionInfo{
+		Version:         develVersion,
+		PoktrollVersion: develVersion,
+		GoVersion:       runtime.Version(),
+	}
+	if buildInfo == nil {
+		return info
+	}
+	if buildInfo.GoVersion != "" {
+		info.GoVersion = buildInfo.GoVersion
+	}
+
+	if buildInfo.Main.Path == sdkModulePath {
+		if buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+			info.Version = buildInfo.Main.Version
+		}
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				info.GitCommit = setting.Value
+			}
+		}
+	}
+
+	for _, dep := range buildInfo.Deps {
+		version := moduleVersion(dep)
+		if version == "" {
+			continue
+		}
+		switch dep.Path {
+		case sdkModulePath:
+			info.Version = version
+		case poktrollModulePath:
+			info.PoktrollVersion = version
+		}
+	}
+
+	if info.GitCommit == "" {
+		info.GitCommit = pseudoVersionCommit(info.Version)
+	}
+	return info
+}
+
+// moduleVersion returns the version of the given module, taking its
+// replacement into account.
+func moduleVersion(module *debug.Module) string {
+	if module.Replace != nil && module.Replace.Version != "" {
+		return module.Replace.Version
+	}
+	return module.Version
+}
+
+// pseudoVersionCommit returns the commit of the given pseudo-version, e.g.
+// "ecf74ced63cc" for "v0.0.8-0.20240911114212-ecf74ced63cc", or an empty string
+// if the version is not a pseudo-version.
+func pseudoVersionCommit(version string) string {
+	version, _, _ = strings.Cut(version, "+")
+	parts := strings.Split(version, "-")
+	if len(parts) < 3 {
+		return ""
+	}
+
+	commit, timestamp := parts[len(parts)-1], parts[len(parts)-2]
+	if len(commit) != 12 || strings.Trim(commit, "0123456789abcdef") != "" {
+		return ""
+	}
+	if len(timestamp) < 14 || strings.Trim(timestamp[len(timestamp)-14:], "0123456789") != "" {
+		return ""
+	}
+	return commit
+}
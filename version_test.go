@@ -0,0 +1,107 @@
+package sdk
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadVersionInfo(t *testing.T) {
+	poktrollDep := &debug.Module{Path: poktrollModulePath, Version: "v0.0.8-0.20240911114212-ecf74ced63cc"}
+
+	tests := []struct {
+		name      string
+		buildInfo *debug.BuildInfo
+		expected  VersionInfo
+	}{
+		{
+			name:      "no build info",
+			buildInfo: nil,
+			expected:  VersionInfo{Version: "devel", PoktrollVersion: "devel", GoVersion: "go1.23.0"},
+		},
+		{
+			name: "tagged dependency",
+			buildInfo: &debug.BuildInfo{
+				GoVersion: "go1.23.0",
+				Main:      debug.Module{Path: "example.com/gateway", Version: "(devel)"},
+				Deps:      []*debug.Module{{Path: sdkModulePath, Version: "v1.2.3"}, poktrollDep},
+			},
+			expected: VersionInfo{Version: "v1.2.3", PoktrollVersion: poktrollDep.Version, GoVersion: "go1.23.0"},
+		},
+		{
+			name: "pseudo-version dependency",
+			buildInfo: &debug.BuildInfo{
+				GoVersion: "go1.23.0",
+				Main:      debug.Module{Path: "example.com/gateway"},
+				Deps: []*debug.Module{
+					{Path: sdkModulePath, Version: "v0.0.0-20241001120000-0123456789ab"},
+					poktrollDep,
+				},
+			},
+			expected: VersionInfo{
+				Version:         "v0.0.0-20241001120000-0123456789ab",
+				GitCommit:       "0123456789ab",
+				PoktrollVersion: poktrollDep.Version,
+				GoVersion:       "go1.23.0",
+			},
+		},
+		{
+			name: "replaced dependency",
+			buildInfo: &debug.BuildInfo{
+				GoVersion: "go1.23.0",
+				Main:      debug.Module{Path: "example.com/gateway"},
+				Deps: []*debug.Module{
+					{Path: sdkModulePath, Version: "v1.2.3", Replace: &debug.Module{Path: "example.com/fork", Version: "v1.2.4-rc.1"}},
+				},
+			},
+			expected: VersionInfo{Version: "v1.2.4-rc.1", PoktrollVersion: "devel", GoVersion: "go1.23.0"},
+		},
+		{
+			name: "main module",
+			buildInfo: &debug.BuildInfo{
+				GoVersion: "go1.23.0",
+				Main:      debug.Module{Path: sdkModulePath, Version: "(devel)"},
+				Deps:      []*debug.Module{poktrollDep},
+				Settings:  []debug.BuildSetting{{Key: "vcs.revision", Value: "ecf74ced63ccecf74ced63ccecf74ced63ccecf7"}},
+			},
+			expected: VersionInfo{
+				Version:         "devel",
+				GitCommit:       "ecf74ced63ccecf74ced63ccecf74ced63ccecf7",
+				PoktrollVersion: poktrollDep.Version,
+				GoVersion:       "go1.23.0",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info := readVersionInfo(test.buildInfo)
+			if test.buildInfo == nil {
+				// The Go version of the running binary is reported.
+				test.expected.GoVersion = info.GoVersion
+			}
+			require.Equal(t, test.expected, info)
+		})
+	}
+}
+
+func TestVersionInfo_Formatting(t *testing.T) {
+	info := VersionInfo{
+		Version:         "v0.0.0-20241001120000-0123456789ab",
+		GitCommit:       "0123456789ab",
+		PoktrollVersion: "v0.0.8",
+		GoVersion:       "go1.23.0",
+	}
+	require.Equal(t, "shannon-sdk v0.0.0-20241001120000-0123456789ab (commit 0123456789ab, poktroll v0.0.8, go1.23.0)", info.String())
+
+	var logged strings.Builder
+	slog.New(slog.NewTextHandler(&logged, nil)).Info("starting", "sdk", info)
+	require.Contains(t, logged.String(), "sdk.git_commit=0123456789ab sdk.poktroll_version=v0.0.8")
+
+	// The relays identify the SDK build to the suppliers.
+	require.True(t, strings.HasPrefix(DefaultUserAgent(), "shannon-sdk/"+SDKVersion()+" ("))
+	require.Contains(t, DefaultUserAgent(), "poktroll "+Version().PoktrollVersion)
+}
@@ -0,0 +1,158 @@
+package sdk
+
+import (
+	"time"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+// The view types are JSON-marshalable representations of the SDK's session and
+// relay types, e.g. to be returned by the admin or debug APIs of a gateway.
+// Unlike the JSON encoding of the proto-generated types, their field names are
+// stable, snake_cased, and do not depend on the version of the protobuf runtime.
+
+// SessionView is the JSON representation of a session.
+type SessionView struct {
+	SessionId          string         `json:"session_id"`
+	SessionNumber      int64          `json:"session_number"`
+	ApplicationAddress string         `json:"application_address"`
+	ServiceId          string         `json:"service_id"`
+	StartBlockHeight   int64          `json:"start_block_height"`
+	EndBlockHeight     int64          `json:"end_block_height"`
+	Endpoints          []EndpointView `json:"endpoints"`
+
+	// The following fields are only set for the sessions returned by the
+	// SessionCache, see NewSessionInfoView.
+	Source          string     `json:"source,omitempty"`
+	FetchedAtHeight int64      `json:"fetched_at_height,omitempty"`
+	FetchedAt       *time.Time `json:"fetched_at,omitempty"`
+	Generation      uint64     `json:"generation,omitempty"`
+	FetchErr        string     `json:"fetch_error,omitempty"`
+}
+
+// NewSessionView returns the SessionView of the given session, listing the
+// endpoints of its suppliers in the order of the session's suppliers.
+func NewSessionView(session *sessiontypes.Session) SessionView {
+	header := session.GetHeader()
+	view := SessionView{
+		SessionId:          session.GetSessionId(),
+		SessionNumber:      session.GetSessionNumber(),
+		ApplicationAddress: header.GetApplicationAddress(),
+		ServiceId:          header.GetServiceId(),
+		StartBlockHeight:   header.GetSessionStartBlockHeight(),
+		EndBlockHeight:     header.GetSessionEndBlockHeight(),
+		Endpoints:          []EndpointView{},
+	}
+	if header == nil {
+		return view
+	}
+
+	sessionFilter := SessionFilter{Session: session}
+	supplierEndpoints, err := sessionFilter.AllEndpoints()
+	if err != nil {
+		return view
+	}
+	for _, supplier := range session.GetSuppliers() {
+		supplierAddress := SupplierAddress(supplier.GetOperatorAddress())
+		for _, endpoint := range supplierEndpoints[supplierAddress] {
+			view.Endpoints = append(view.Endpoints, NewEndpointView(endpoint))
+		}
+		// Suppliers listed more than once have their endpoints listed once.
+		delete(supplierEndpoints, supplierAddress)
+	}
+
+	return view
+}
+
+// NewSessionInfoView returns the SessionView of the given session returned by
+// the SessionCache, including its freshness metadata.
+func NewSessionInfoView(sessionInfo SessionInfo) SessionView {
+	view := NewSessionView(sessionInfo.Session)
+	view.Source = sessionInfo.Source.String()
+	view.FetchedAtHeight = sessionInfo.FetchedAtHeight
+	if !sessionInfo.FetchedAt.IsZero() {
+		view.FetchedAt = &sessionInfo.FetchedAt
+	}
+	view.Generation = sessionInfo.Generation
+	if sessionInfo.FetchErr != nil {
+		view.FetchErr = sessionInfo.FetchErr.Error()
+	}
+
+	return view
+}
+
+// EndpointView is the JSON representation of an Endpoint.
+type EndpointView struct {
+	SupplierAddress string `json:"supplier_address"`
+	SupplierOwner   string `json:"supplier_owner,omitempty"`
+	SupplierStake   string `json:"supplier_stake,omitempty"`
+	URL             string `json:"url"`
+	RPCType         string `json:"rpc_type"`
+}
+
+// NewEndpointView returns the EndpointView of the given endpoint.
+func NewEndpointView(endpoint Endpoint) EndpointView {
+	supplierEndpoint := endpoint.Endpoint()
+	supplierInfo := endpoint.SupplierInfo()
+
+	view := EndpointView{
+		SupplierAddress: string(endpoint.Supplier()),
+		SupplierOwner:   supplierInfo.Owner(),
+		URL:             supplierEndpoint.Url,
+		RPCType:         supplierEndpoint.RpcType.String(),
+	}
+	if stake := supplierInfo.Stake; stake != nil {
+		view.SupplierStake = stake.String()
+	}
+
+	return view
+}
+
+// RelayResultView is the JSON representation of the outcome of a relay.
+type RelayResultView struct {
+	SessionId       string `json:"session_id,omitempty"`
+	SupplierAddress string `json:"supplier_address,omitempty"`
+	EndpointURL     string `json:"endpoint_url,omitempty"`
+	// StatusCode is the status code of the supplier's response, if it could be
+	// deserialized.
+	StatusCode        int    `json:"status_code,omitempty"`
+	ResponseSizeBytes int    `json:"response_size_bytes,omitempty"`
+	SignatureVerified bool   `json:"signature_verified"`
+	Degraded          bool   `json:"degraded,omitempty"`
+	LatencyMs         int64  `json:"latency_ms"`
+	Error             string `json:"error,omitempty"`
+}
+
+// NewRelayResultView returns the RelayResultView of a relay to the given
+// endpoint, which took the given latency, and returned either the given
+// validated response or the given error. The endpoint and the response may
+// be nil, e.g. if the relay failed before an endpoint was selected.
+func NewRelayResultView(
+	endpoint Endpoint,
+	relayResponse *ValidatedRelayResponse,
+	latency time.Duration,
+	err error,
+) RelayResultView {
+	view := RelayResultView{LatencyMs: latency.Milliseconds()}
+	if endpoint != nil {
+		header := endpoint.Header()
+		view.SessionId = header.SessionId
+		view.SupplierAddress = string(endpoint.Supplier())
+		view.EndpointURL = endpoint.Endpoint().Url
+	}
+	if relayResponse != nil && relayResponse.RelayResponse != nil {
+		view.ResponseSizeBytes = len(relayResponse.Payload)
+		view.SignatureVerified = relayResponse.SignatureVerified
+		view.Degraded = relayResponse.ValidateBasicErr != nil
+		if poktHTTPResponse, err := types.DeserializeHTTPResponse(relayResponse.Payload); err == nil {
+			view.StatusCode = int(poktHTTPResponse.StatusCode)
+		}
+	}
+	if err != nil {
+		view.Error = err.Error()
+	}
+
+	return view
+}
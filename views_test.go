@@ -0,0 +1,131 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	cosmostypes "github.com/cosmos/cosmos-sdk/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pokt-network/shannon-sdk/types"
+)
+
+func TestSessionView_JSON(t *testing.T) {
+	stake := cosmostypes.NewInt64Coin("upokt", 1000)
+	session := &sessiontypes.Session{
+		SessionId:     "session1",
+		SessionNumber: 3,
+		Header: &sessiontypes.SessionHeader{
+			ApplicationAddress:      "pokt1app",
+			ServiceId:               "anvil",
+			SessionId:               "session1",
+			SessionStartBlockHeight: 11,
+			SessionEndBlockHeight:   20,
+		},
+		Suppliers: []*sharedtypes.Supplier{
+			viewsTestSupplier("pokt1supplier2", &stake, "https://supplier2.example"),
+			viewsTestSupplier("pokt1supplier1", nil, "https://supplier1a.example", "https://supplier1b.example"),
+		},
+	}
+
+	fetchedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	view := NewSessionInfoView(SessionInfo{
+		Session:         session,
+		FetchedAtHeight: 12,
+		FetchedAt:       fetchedAt,
+		Source:          SessionSourceFullNode,
+		Generation:      4,
+	})
+
+	viewBz, err := json.Marshal(view)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"session_id": "session1",
+		"session_number": 3,
+		"application_address": "pokt1app",
+		"service_id": "anvil",
+		"start_block_height": 11,
+		"end_block_height": 20,
+		"endpoints": [
+			{"supplier_address": "pokt1supplier2", "supplier_owner": "pokt1owner", "supplier_stake": "1000upokt", "url": "https://supplier2.example", "rpc_type": "JSON_RPC"},
+			{"supplier_address": "pokt1supplier1", "supplier_owner": "pokt1owner", "url": "https://supplier1a.example", "rpc_type": "JSON_RPC"},
+			{"supplier_address": "pokt1supplier1", "supplier_owner": "pokt1owner", "url": "https://supplier1b.example", "rpc_type": "JSON_RPC"}
+		],
+		"source": "fullnode",
+		"fetched_at_height": 12,
+		"fetched_at": "2024-01-02T03:04:05Z",
+		"generation": 4
+	}`, string(viewBz))
+
+	// A session without header has no endpoints.
+	viewBz, err = json.Marshal(NewSessionView(&sessiontypes.Session{SessionId: "session2"}))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"session_id": "session2",
+		"session_number": 0,
+		"application_address": "",
+		"service_id": "",
+		"start_block_height": 0,
+		"end_block_height": 0,
+		"endpoints": []
+	}`, string(viewBz))
+}
+
+func TestRelayResultView_JSON(t *testing.T) {
+	e := endpoint{
+		header:           sessiontypes.SessionHeader{SessionId: "session1"},
+		supplierEndpoint: sharedtypes.SupplierEndpoint{Url: "https://supplier.example"},
+		supplier:         "pokt1supplier",
+	}
+	payloadBz, err := proto.Marshal(&types.POKTHTTPResponse{StatusCode: 200, BodyBz: []byte(`{}`)})
+	require.NoError(t, err)
+	relayResponse := &ValidatedRelayResponse{
+		RelayResponse:     &servicetypes.RelayResponse{Payload: payloadBz},
+		SignatureVerified: true,
+	}
+
+	viewBz, err := json.Marshal(NewRelayResultView(e, relayResponse, 1500*time.Millisecond, nil))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"session_id": "session1",
+		"supplier_address": "pokt1supplier",
+		"endpoint_url": "https://supplier.example",
+		"status_code": 200,
+		"response_size_bytes": `+strconv.Itoa(len(payloadBz))+`,
+		"signature_verified": true,
+		"latency_ms": 1500
+	}`, string(viewBz))
+
+	viewBz, err = json.Marshal(NewRelayResultView(nil, nil, 0, errors.New("no endpoints in session")))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"signature_verified": false,
+		"latency_ms": 0,
+		"error": "no endpoints in session"
+	}`, string(viewBz))
+}
+
+// viewsTestSupplier returns a supplier of the anvil service with the given endpoints.
+func viewsTestSupplier(operatorAddress string, stake *cosmostypes.Coin, urls ...string) *sharedtypes.Supplier {
+	serviceConfig := &sharedtypes.SupplierServiceConfig{ServiceId: "anvil"}
+	for _, url := range urls {
+		serviceConfig.Endpoints = append(serviceConfig.Endpoints, &sharedtypes.SupplierEndpoint{
+			Url:     url,
+			RpcType: sharedtypes.RPCType_JSON_RPC,
+		})
+	}
+
+	return &sharedtypes.Supplier{
+		OperatorAddress: operatorAddress,
+		OwnerAddress:    "pokt1owner",
+		Stake:           stake,
+		Services:        []*sharedtypes.SupplierServiceConfig{serviceConfig},
+	}
+}
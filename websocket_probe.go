@@ -0,0 +1,283 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pokt-network/shannon-sdk/cache"
+)
+
+const (
+	// EndpointMetadataWebSocket is the metadata key set on the endpoints probed
+	// by a WebSocketProber. Its value is EndpointWebSocketSupported or
+	// EndpointWebSocketUnsupported.
+	EndpointMetadataWebSocket = "websocket"
+	// EndpointWebSocketSupported is the WebSocket capability of the endpoints
+	// which accepted the WebSocket upgrade of their probe.
+	EndpointWebSocketSupported = "supported"
+	// EndpointWebSocketUnsupported is the WebSocket capability of the endpoints
+	// which rejected the WebSocket upgrade of their probe.
+	EndpointWebSocketUnsupported = "unsupported"
+
+	// defaultWebSocketProbeTimeout is the maximum duration of the probe of an
+	// endpoint if no timeout is specified.
+	defaultWebSocketProbeTimeout = 5 * time.Second
+	// defaultMaxConcurrentWebSocketProbes is the maximum number of endpoints
+	// probed concurrently if no limit is specified.
+	defaultMaxConcurrentWebSocketProbes = 16
+
+	// maxWebSocketProbeDrainSize is the maximum size of the body of a rejected
+	// probe read to reuse its connection.
+	maxWebSocketProbeDrainSize = 4 << 10
+
+	// webSocketAcceptGUID is the GUID appended to the key of a WebSocket
+	// handshake to compute the accept value of the server, as per RFC 6455.
+	webSocketAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// WebSocketProberOption is a functional option used to configure a WebSocketProber.
+type WebSocketProberOption func(*webSocketProberConfig)
+
+// webSocketProberConfig holds the settings applied by WebSocketProberOptions.
+type webSocketProberConfig struct {
+	timeout       time.Duration
+	maxConcurrent int
+}
+
+// WithWebSocketProbeTimeout sets the maximum duration of the probe of an endpoint.
+// It defaults to 5 seconds.
+func WithWebSocketProbeTimeout(timeout time.Duration) WebSocketProberOption {
+	return func(c *webSocketProberConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithMaxConcurrentWebSocketProbes bounds the number of endpoints probed
+// concurrently by WebSocketEndpoints. It defaults to 16.
+func WithMaxConcurrentWebSocketProbes(maxConcurrent int) WebSocketProberOption {
+	return func(c *webSocketProberConfig) {
+		c.maxConcurrent = maxConcurrent
+	}
+}
+
+// WebSocketProber probes whether the supplier endpoints accept WebSocket
+// upgrade requests before WebSocket clients are bound to them, since many
+// endpoints only serve JSON-RPC over HTTP.
+//
+// An endpoint is probed by sending it a WebSocket handshake, which is closed as
+// soon as it is accepted. The capability of each endpoint is cached for the
+// session it belongs to: it is probed again in the next session.
+// It is safe for concurrent use.
+type WebSocketProber struct {
+	httpClient *http.Client
+	config     webSocketProberConfig
+	sem        chan struct{}
+
+	// capabilities holds the WebSocket capability of the probed endpoints,
+	// until the end of their session.
+	capabilities *cache.Cache[webSocketProbeKey, webSocketCapability]
+}
+
+// webSocketProbeKey identifies the capability of an endpoint in a session.
+type webSocketProbeKey struct {
+	SessionId string
+	URL       string
+}
+
+// webSocketCapability is the cached WebSocket capability of an endpoint.
+type webSocketCapability struct {
+	supported        bool
+	sessionEndHeight int64
+}
+
+// ValidUntilHeight returns the end height of the session of the endpoint, so
+// the capability is removed by InvalidateAtHeight once the session ends.
+func (c webSocketCapability) ValidUntilHeight() int64 {
+	return c.sessionEndHeight
+}
+
+// NewWebSocketProber returns a WebSocketProber probing the endpoints using the
+// given HTTP client, or http.DefaultClient if nil, configured using the given
+// options.
+func NewWebSocketProber(httpClient *http.Client, opts ...WebSocketProberOption) *WebSocketProber {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	config := webSocketProberConfig{
+		timeout:       defaultWebSocketProbeTimeout,
+		maxConcurrent: defaultMaxConcurrentWebSocketProbes,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &WebSocketProber{
+		httpClient:   httpClient,
+		config:       config,
+		sem:          make(chan struct{}, max(config.maxConcurrent, 1)),
+		capabilities: cache.New[webSocketProbeKey, webSocketCapability](cache.Config{}),
+	}
+}
+
+// SupportsWebSocket returns true if the given endpoint accepts WebSocket
+// upgrade requests, probing it unless its capability is cached for its session.
+// Concurrent probes of the same endpoint are coalesced.
+//
+// An error is returned if the endpoint could not be probed, e.g. if it is not
+// reachable. The failed probes are not cached.
+func (p *WebSocketProber) SupportsWebSocket(ctx context.Context, e Endpoint) (bool, error) {
+	typed := GetTypedEndpoint(e)
+	if !typed.Valid() {
+		return false, fmt.Errorf("SupportsWebSocket: %w", typed.Err)
+	}
+
+	header := e.Header()
+	key := webSocketProbeKey{SessionId: header.SessionId, URL: typed.URL.String()}
+	capability, _, err := p.capabilities.GetOrFetch(ctx, key, func(ctx context.Context) (webSocketCapability, error) {
+		supported, err := p.probe(ctx, typed, GetEndpointAuthHeaders(e))
+		if err != nil {
+			return webSocketCapability{}, err
+		}
+		return webSocketCapability{supported: supported, sessionEndHeight: header.SessionEndBlockHeight}, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("SupportsWebSocket: error probing endpoint %s of supplier %s: %w", key.URL, e.Supplier(), err)
+	}
+
+	return capability.supported, nil
+}
+
+// WebSocketEndpoints returns the given endpoints which accept WebSocket upgrade
+// requests, probing them concurrently, e.g. to select the endpoint a WebSocket
+// client is bound to. The endpoints which could not be probed are excluded.
+func (p *WebSocketProber) WebSocketEndpoints(ctx context.Context, endpoints []Endpoint) []Endpoint {
+	supported := make([]bool, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		select {
+		case p.sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-p.sem }()
+
+			supported[i], _ = p.SupportsWebSocket(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	var wsEndpoints []Endpoint
+	for i, e := range endpoints {
+		if supported[i] {
+			wsEndpoints = append(wsEndpoints, e)
+		}
+	}
+	return wsEndpoints
+}
+
+// DecorateEndpoint is an EndpointDecorator setting the EndpointMetadataWebSocket
+// metadata on the endpoints whose capability is cached, without probing them.
+// It can be set in the EndpointDecorators of a SessionFilter, along with the
+// ByWebSocketSupport filter.
+func (p *WebSocketProber) DecorateEndpoint(e Endpoint) Endpoint {
+	typed := GetTypedEndpoint(e)
+	if !typed.Valid() {
+		return e
+	}
+
+	capability, ok := p.capabilities.Get(webSocketProbeKey{SessionId: e.Header().SessionId, URL: typed.URL.String()})
+	switch {
+	case !ok:
+		return e
+	case capability.supported:
+		return WithEndpointMetadata(EndpointMetadataWebSocket, EndpointWebSocketSupported)(e)
+	default:
+		return WithEndpointMetadata(EndpointMetadataWebSocket, EndpointWebSocketUnsupported)(e)
+	}
+}
+
+// InvalidateAtHeight removes the capabilities of the endpoints of the sessions
+// ending at or before the given height, e.g. on every new block.
+func (p *WebSocketProber) InvalidateAtHeight(height int64) {
+	p.capabilities.InvalidateAtHeight(height)
+}
+
+// probe sends a WebSocket handshake to the given endpoint, and returns true if
+// the endpoint accepted it. The accepted connection is closed immediately.
+func (p *WebSocketProber) probe(
+	ctx context.Context,
+	typed TypedSupplierEndpoint,
+	authHeaders http.Header,
+) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.config.timeout)
+	defer cancel()
+
+	probeURL := *typed.URL
+	switch probeURL.Scheme {
+	case "ws":
+		probeURL.Scheme = "http"
+	case "wss":
+		probeURL.Scheme = "https"
+	}
+
+	keyBz := make([]byte, 16)
+	if _, err := rand.Read(keyBz); err != nil {
+		return false, fmt.Errorf("error generating the handshake key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBz)
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, "", nil)
+	if err != nil {
+		return false, fmt.Errorf("error building probe request: %w", err)
+	}
+	httpRequest.URL = &probeURL
+	httpRequest.Host = probeURL.Host
+	for key, values := range authHeaders {
+		for _, value := range values {
+			httpRequest.Header.Add(key, value)
+		}
+	}
+	// The upgrade headers make the HTTP client use HTTP/1.1, even if the
+	// endpoint supports HTTP/2.
+	httpRequest.Header.Set("Connection", "Upgrade")
+	httpRequest.Header.Set("Upgrade", "websocket")
+	httpRequest.Header.Set("Sec-WebSocket-Version", "13")
+	httpRequest.Header.Set("Sec-WebSocket-Key", key)
+
+	httpResponse, err := p.httpClient.Do(httpRequest)
+	if err != nil {
+		return false, err
+	}
+	// The body of an accepted upgrade is the connection itself: closing it
+	// closes the connection.
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusSwitchingProtocols {
+		// The body is drained, up to a small size, so the connection can be reused.
+		_, _ = io.Copy(io.Discard, io.LimitReader(httpResponse.Body, maxWebSocketProbeDrainSize))
+		return false, nil
+	}
+
+	return httpResponse.Header.Get("Sec-WebSocket-Accept") == webSocketAccept(key), nil
+}
+
+// webSocketAccept returns the accept value of a WebSocket handshake with the
+// given key, as per RFC 6455.
+func webSocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + webSocketAcceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
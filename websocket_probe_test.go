@@ -0,0 +1,83 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketProber(t *testing.T) {
+	var probes atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		if r.URL.Path != "/ws" || r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, "JSON-RPC only", http.StatusBadRequest)
+			return
+		}
+
+		conn, bufrw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _ = bufrw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + webSocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		_ = bufrw.Flush()
+	}))
+	defer server.Close()
+
+	unreachableServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachableURL := unreachableServer.URL
+	unreachableServer.Close()
+
+	newEndpoint := func(supplier, url string) Endpoint {
+		return NewEndpoint(
+			sessiontypes.SessionHeader{SessionId: "session1", ServiceId: "svc1", SessionEndBlockHeight: 10},
+			sharedtypes.SupplierEndpoint{Url: url, RpcType: sharedtypes.RPCType_WEBSOCKET},
+			SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: supplier}},
+		)
+	}
+	wsEndpoint := newEndpoint("pokt1supplier1", "ws"+server.URL[len("http"):]+"/ws")
+	jsonRPCEndpoint := newEndpoint("pokt1supplier2", server.URL+"/jsonrpc")
+	unreachableEndpoint := newEndpoint("pokt1supplier3", unreachableURL)
+
+	prober := NewWebSocketProber(server.Client())
+	ctx := context.Background()
+
+	// Only the endpoints accepting the upgrade are kept.
+	endpoints := prober.WebSocketEndpoints(ctx, []Endpoint{wsEndpoint, jsonRPCEndpoint, unreachableEndpoint})
+	require.Equal(t, []Endpoint{wsEndpoint}, endpoints)
+	require.Equal(t, int64(2), probes.Load())
+
+	// The capabilities are cached for the session.
+	supported, err := prober.SupportsWebSocket(ctx, wsEndpoint)
+	require.NoError(t, err)
+	require.True(t, supported)
+	supported, err = prober.SupportsWebSocket(ctx, jsonRPCEndpoint)
+	require.NoError(t, err)
+	require.False(t, supported)
+	require.Equal(t, int64(2), probes.Load())
+
+	// The failed probes are not cached.
+	_, err = prober.SupportsWebSocket(ctx, unreachableEndpoint)
+	require.Error(t, err)
+
+	// The cached capabilities are exposed as endpoint metadata, to exclude the
+	// non-capable endpoints from the WebSocket routing.
+	filter := ByWebSocketSupport()
+	require.False(t, filter(prober.DecorateEndpoint(wsEndpoint)))
+	require.True(t, filter(prober.DecorateEndpoint(jsonRPCEndpoint)))
+	require.True(t, filter(prober.DecorateEndpoint(unreachableEndpoint)))
+
+	// The capabilities are probed again once the session ends.
+	prober.InvalidateAtHeight(10)
+	_, err = prober.SupportsWebSocket(ctx, wsEndpoint)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), probes.Load())
+}
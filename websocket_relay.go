@@ -0,0 +1,286 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+// ErrRelayChannelSessionEnded is returned by a WebSocketRelayChannel closed
+// because the session it was established for ended.
+var ErrRelayChannelSessionEnded = sdkerrors.ErrRelayChannelSessionEnded
+
+// webSocketCloseTimeout is the maximum duration to wait for the close frame of
+// a WebSocketRelayChannel to be sent.
+const webSocketCloseTimeout = time.Second
+
+// WebSocketRelayConfig specifies how WebSocketRelayChannels are established,
+// and how their frames are signed and validated.
+type WebSocketRelayConfig struct {
+	// Signer signs the relay requests wrapping the outbound frames.
+	Signer RelayRequestSigner
+	// PublicKeyFetcher fetches the public keys of the applications' rings, and
+	// of the suppliers signing the inbound frames.
+	PublicKeyFetcher PublicKeyFetcher
+
+	// Dialer, if set, establishes the WebSocket connections to the endpoints.
+	// websocket.DefaultDialer is used otherwise.
+	Dialer *websocket.Dialer
+	// Validator, if set, validates the relay responses of the inbound frames,
+	// e.g. to trust the suppliers in centralized mode. A RelayResponseValidator
+	// using PublicKeyFetcher is used otherwise.
+	Validator *RelayResponseValidator
+	// MaxFrameSize, if positive, is the maximum size of the inbound frames.
+	// A larger frame fails with ErrRelayResponseTooLarge, closing the channel.
+	MaxFrameSize int64
+	// Identification specifies how the gateway identifies itself to the
+	// suppliers. See WithRelayIdentification.
+	Identification RelayIdentification
+}
+
+// WebSocketRelayChannel is a signed relay channel to a supplier's WebSocket
+// endpoint, e.g. to relay eth_subscribe subscriptions.
+//
+// Each outbound frame is wrapped in a RelayRequest signed on behalf of the
+// application, and each inbound frame is a RelayResponse, validated along with
+// the supplier's signature before being returned.
+//
+// A channel is only valid for the session of its endpoint: HandleNewBlock closes
+// it once the session ends, after which the client must establish a new channel
+// to an endpoint of the new session.
+// Send and Receive can be called concurrently with each other and with Close.
+type WebSocketRelayChannel struct {
+	conn      *websocket.Conn
+	endpoint  Endpoint
+	signer    RelayRequestSigner
+	appRing   ApplicationRing
+	validator RelayResponseValidator
+
+	maxFrameSize int64
+
+	writeMu sync.Mutex
+	readMu  sync.Mutex
+
+	closeOnce sync.Once
+	done      chan struct{}
+	err       error
+}
+
+// DialWebSocketRelay establishes a WebSocketRelayChannel to the given endpoint,
+// relaying on behalf of the given application.
+//
+// The endpoint's "http" and "https" URL schemes are dialed as "ws" and "wss".
+// The WebSocket handshake includes the endpoint's authentication headers, if
+// any, and the identification headers.
+func DialWebSocketRelay(
+	ctx context.Context,
+	config WebSocketRelayConfig,
+	app apptypes.Application,
+	endpoint Endpoint,
+) (*WebSocketRelayChannel, error) {
+	if config.Signer == nil || config.PublicKeyFetcher == nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrNotConfigured, "DialWebSocketRelay: Signer and PublicKeyFetcher must both be set")
+	}
+
+	typed := GetTypedEndpoint(endpoint)
+	if !typed.Valid() {
+		return nil, fmt.Errorf("DialWebSocketRelay: %w", typed.Err)
+	}
+	dialURL := *typed.URL
+	switch dialURL.Scheme {
+	case "http":
+		dialURL.Scheme = "ws"
+	case "https":
+		dialURL.Scheme = "wss"
+	}
+
+	header := http.Header{}
+	for key, values := range GetEndpointAuthHeaders(endpoint) {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	config.Identification.apply(header)
+
+	dialer := config.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	conn, httpResponse, err := dialer.DialContext(ctx, dialURL.String(), header)
+	if err != nil {
+		if httpResponse != nil {
+			err = fmt.Errorf("%w: status code %d", err, httpResponse.StatusCode)
+		}
+		return nil, fmt.Errorf(
+			"DialWebSocketRelay: error connecting to endpoint %s of supplier %s: %w",
+			dialURL.String(),
+			endpoint.Supplier(),
+			err,
+		)
+	}
+	if config.MaxFrameSize > 0 {
+		conn.SetReadLimit(config.MaxFrameSize)
+	}
+
+	validator := RelayResponseValidator{PublicKeyFetcher: config.PublicKeyFetcher}
+	if config.Validator != nil {
+		validator = *config.Validator
+	}
+
+	return &WebSocketRelayChannel{
+		conn:         conn,
+		endpoint:     endpoint,
+		signer:       config.Signer,
+		appRing:      ApplicationRing{Application: app, PublicKeyFetcher: config.PublicKeyFetcher},
+		validator:    validator,
+		maxFrameSize: config.MaxFrameSize,
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// Endpoint returns the endpoint the channel is established to.
+func (c *WebSocketRelayChannel) Endpoint() Endpoint {
+	return c.endpoint
+}
+
+// Send wraps the given frame in a signed RelayRequest, and sends it to the supplier.
+func (c *WebSocketRelayChannel) Send(ctx context.Context, frame []byte) error {
+	if err := c.Err(); err != nil {
+		return fmt.Errorf("Send: %w", err)
+	}
+
+	relayRequest, err := BuildRelayRequest(c.endpoint, frame)
+	if err != nil {
+		return fmt.Errorf("Send: %w", err)
+	}
+	relayRequest, err = c.signer.Sign(ctx, relayRequest, c.appRing)
+	if err != nil {
+		return fmt.Errorf("Send: error signing the relay request: %w", err)
+	}
+	relayRequestBz, err := relayRequest.Marshal()
+	if err != nil {
+		return fmt.Errorf("Send: error marshaling relay request: %w", err)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetWriteDeadline(deadline)
+		defer func() { _ = c.conn.SetWriteDeadline(time.Time{}) }()
+	}
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, relayRequestBz); err != nil {
+		return fmt.Errorf("Send: error sending frame to supplier %s: %w", c.endpoint.Supplier(), c.closedErr(err))
+	}
+	return nil
+}
+
+// Receive waits for the next inbound frame, and returns its validated RelayResponse.
+//
+// Since a WebSocket connection can not be read from after a read was abandoned,
+// the channel is closed if the given context is done before a frame is received.
+// The channel is also closed if a frame fails to be read, e.g. if it exceeds
+// the MaxFrameSize, but not if it fails validation.
+func (c *WebSocketRelayChannel) Receive(ctx context.Context) (*servicetypes.RelayResponse, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if err := c.Err(); err != nil {
+		return nil, fmt.Errorf("Receive: %w", err)
+	}
+
+	stop := context.AfterFunc(ctx, func() { c.closeWithError(context.Cause(ctx)) })
+	_, relayResponseBz, err := c.conn.ReadMessage()
+	stop()
+	if err != nil {
+		if errors.Is(err, websocket.ErrReadLimit) {
+			err = fmt.Errorf("%w: exceeds the %d bytes limit", ErrRelayResponseTooLarge, c.maxFrameSize)
+		}
+		err = c.closedErr(err)
+		c.closeWithError(err)
+		return nil, fmt.Errorf("Receive: error receiving frame from supplier %s: %w", c.endpoint.Supplier(), err)
+	}
+
+	validatedResponse, err := c.validator.Validate(ctx, c.endpoint.Supplier(), relayResponseBz)
+	if err != nil {
+		return nil, fmt.Errorf("Receive: error validating the relay response of supplier %s: %w", c.endpoint.Supplier(), err)
+	}
+	return validatedResponse.RelayResponse, nil
+}
+
+// HandleNewBlock closes the channel with ErrRelayChannelSessionEnded once the
+// given height is past the end height of the channel's session.
+// It can be registered using BlockSubscription.OnNewBlock, and does not block.
+func (c *WebSocketRelayChannel) HandleNewBlock(height int64) {
+	sessionEndHeight := c.endpoint.Header().SessionEndBlockHeight
+	if height <= sessionEndHeight {
+		return
+	}
+
+	go c.closeWithError(fmt.Errorf(
+		"%w: session %s ended at height %d",
+		ErrRelayChannelSessionEnded,
+		c.endpoint.Header().SessionId,
+		sessionEndHeight,
+	))
+}
+
+// Close closes the channel, and its connection to the supplier.
+func (c *WebSocketRelayChannel) Close() error {
+	c.closeWithError(net.ErrClosed)
+	return nil
+}
+
+// Done returns a channel which is closed once the channel is closed.
+func (c *WebSocketRelayChannel) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason the channel was closed, or nil if it is open.
+func (c *WebSocketRelayChannel) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// closeWithError closes the channel for the given reason, sending a close frame
+// to the supplier. It does nothing if the channel is already closed.
+func (c *WebSocketRelayChannel) closeWithError(err error) {
+	c.closeOnce.Do(func() {
+		c.err = err
+		close(c.done)
+
+		closeCode := websocket.CloseNormalClosure
+		if !errors.Is(err, ErrRelayChannelSessionEnded) && !errors.Is(err, net.ErrClosed) {
+			closeCode = websocket.CloseGoingAway
+		}
+		_ = c.conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(closeCode, ""),
+			time.Now().Add(webSocketCloseTimeout),
+		)
+		_ = c.conn.Close()
+	})
+}
+
+// closedErr returns the reason the channel was closed, if it was, rather than
+// the given error of the connection, which is then likely caused by the closing.
+func (c *WebSocketRelayChannel) closedErr(err error) error {
+	if closedErr := c.Err(); closedErr != nil {
+		return closedErr
+	}
+	return err
+}
@@ -0,0 +1,133 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/gorilla/websocket"
+	apptypes "github.com/pokt-network/poktroll/x/application/types"
+	servicetypes "github.com/pokt-network/poktroll/x/service/types"
+	sessiontypes "github.com/pokt-network/poktroll/x/session/types"
+	sharedtypes "github.com/pokt-network/poktroll/x/shared/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pokt-network/shannon-sdk/sdkerrors"
+)
+
+func TestWebSocketRelayChannel(t *testing.T) {
+	pubKeys := make(map[string]cryptotypes.PubKey)
+	newKey := func() (*secp256k1.PrivKey, string) {
+		privKey := secp256k1.GenPrivKey()
+		address, err := PubKeyToAddress(PoktAddressPrefix, privKey.PubKey())
+		require.NoError(t, err)
+		pubKeys[address] = privKey.PubKey()
+		return privKey, address
+	}
+	_, appAddress := newKey()
+	gatewayKey, gatewayAddress := newKey()
+	supplierKey, supplierAddress := newKey()
+
+	// The supplier echoes the payload of every relay request as a signed relay response.
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, relayRequestBz, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			relayRequest := &servicetypes.RelayRequest{}
+			if err := relayRequest.Unmarshal(relayRequestBz); err != nil || len(relayRequest.Meta.Signature) == 0 {
+				return
+			}
+
+			relayResponse := &servicetypes.RelayResponse{
+				Meta:    servicetypes.RelayResponseMetadata{SessionHeader: relayRequest.Meta.SessionHeader},
+				Payload: relayRequest.Payload,
+			}
+			signableBz, err := relayResponse.GetSignableBytesHash()
+			if err != nil {
+				return
+			}
+			if relayResponse.Meta.SupplierOperatorSignature, err = supplierKey.Sign(signableBz[:]); err != nil {
+				return
+			}
+			relayResponseBz, err := relayResponse.Marshal()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(websocket.BinaryMessage, relayResponseBz); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	header := sessiontypes.SessionHeader{
+		ApplicationAddress:      appAddress,
+		ServiceId:               "svc1",
+		SessionId:               "session1",
+		SessionStartBlockHeight: 1,
+		SessionEndBlockHeight:   4,
+	}
+	endpoint := NewEndpoint(
+		header,
+		sharedtypes.SupplierEndpoint{Url: server.URL, RpcType: sharedtypes.RPCType_WEBSOCKET},
+		SupplierInfo{Supplier: sharedtypes.Supplier{OperatorAddress: supplierAddress}},
+	)
+	app := apptypes.Application{Address: appAddress, DelegateeGatewayAddresses: []string{gatewayAddress}}
+	config := WebSocketRelayConfig{
+		Signer:           &Signer{PrivateKeyHex: hex.EncodeToString(gatewayKey.Bytes())},
+		PublicKeyFetcher: &countingPubKeyFetcher{pubKeys: pubKeys},
+	}
+	ctx := context.Background()
+
+	channel, err := DialWebSocketRelay(ctx, config, app, endpoint)
+	require.NoError(t, err)
+	defer channel.Close()
+
+	// The frames are relayed as signed relay requests and validated relay responses.
+	for _, frame := range []string{`{"method":"eth_subscribe"}`, `{"method":"eth_unsubscribe"}`} {
+		require.NoError(t, channel.Send(ctx, []byte(frame)))
+		relayResponse, err := channel.Receive(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []byte(frame), relayResponse.Payload)
+	}
+
+	// The channel is closed once its session ends.
+	channel.HandleNewBlock(4)
+	require.NoError(t, channel.Err())
+	channel.HandleNewBlock(5)
+	select {
+	case <-channel.Done():
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed at the end of its session")
+	}
+	require.ErrorIs(t, channel.Err(), ErrRelayChannelSessionEnded)
+	require.ErrorIs(t, channel.Send(ctx, []byte("frame")), ErrRelayChannelSessionEnded)
+	_, err = channel.Receive(ctx)
+	require.ErrorIs(t, err, ErrRelayChannelSessionEnded)
+
+	// An abandoned read closes the channel.
+	channel, err = DialWebSocketRelay(ctx, config, app, endpoint)
+	require.NoError(t, err)
+	receiveCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = channel.Receive(receiveCtx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, channel.Err(), context.DeadlineExceeded)
+
+	_, err = DialWebSocketRelay(ctx, WebSocketRelayConfig{}, app, endpoint)
+	require.ErrorIs(t, err, sdkerrors.ErrNotConfigured)
+}